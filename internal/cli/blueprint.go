@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,6 +26,8 @@ func init() {
 	blueprintDefinitionCmd.AddCommand(getBlueprintDefinitionCmd)
 	blueprintDefinitionCmd.AddCommand(listBlueprintDefinitionsCmd)
 	blueprintDefinitionCmd.AddCommand(removeBlueprintDefinitionCmd)
+	blueprintDefinitionCmd.AddCommand(updateBlueprintDefinitionCmd)
+	blueprintDefinitionCmd.AddCommand(migrateBlueprintDefinitionCmd)
 
 	// Blueprint commands
 	blueprintCmd.AddCommand(addBlueprintCmd)
@@ -31,9 +35,15 @@ func init() {
 	blueprintCmd.AddCommand(listBlueprintsCmd)
 	blueprintCmd.AddCommand(updateBlueprintCmd)
 	blueprintCmd.AddCommand(setBlueprintCmd)
+	blueprintCmd.AddCommand(patchBlueprintCmd)
+	blueprintCmd.AddCommand(applyBlueprintCmd)
+	blueprintCmd.AddCommand(diffBlueprintCmd)
 	blueprintCmd.AddCommand(removeBlueprintCmd)
+	blueprintCmd.AddCommand(finalizeBlueprintCmd)
 	blueprintCmd.AddCommand(reconcileBlueprintCmd)
 	blueprintCmd.AddCommand(historyBlueprintCmd)
+	blueprintCmd.AddCommand(rollbackBlueprintCmd)
+	blueprintCmd.AddCommand(waitBlueprintCmd)
 	blueprintCmd.AddCommand(logBlueprintCmd)
 	blueprintCmd.AddCommand(doctorBlueprintCmd)
 
@@ -52,6 +62,15 @@ func init() {
 	removeBlueprintDefinitionCmd.Flags().StringVarP(&BlueprintDefinitionName, "name", "", "", "BlueprintDefinition name")
 	removeBlueprintDefinitionCmd.MarkFlagRequired("name")
 
+	updateBlueprintDefinitionCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key (colony owner)")
+	updateBlueprintDefinitionCmd.Flags().StringVarP(&SpecFile, "spec", "", "", "JSON specification file")
+	updateBlueprintDefinitionCmd.MarkFlagRequired("spec")
+
+	migrateBlueprintDefinitionCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key (colony owner)")
+	migrateBlueprintDefinitionCmd.Flags().StringVarP(&BlueprintDefinitionName, "name", "", "", "BlueprintDefinition name")
+	migrateBlueprintDefinitionCmd.Flags().BoolVarP(&DryRun, "dryrun", "", false, "Only report what would be migrated")
+	migrateBlueprintDefinitionCmd.MarkFlagRequired("name")
+
 	// Blueprint flags
 	addBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	addBlueprintCmd.Flags().StringVarP(&SpecFile, "spec", "", "", "JSON specification file")
@@ -59,6 +78,7 @@ func init() {
 
 	getBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	getBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	getBlueprintCmd.Flags().StringVarP(&BlueprintVersion, "version", "", "", "Convert the blueprint to this version of its definition")
 	getBlueprintCmd.MarkFlagRequired("name")
 
 	listBlueprintsCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
@@ -66,6 +86,7 @@ func init() {
 
 	updateBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	updateBlueprintCmd.Flags().StringVarP(&SpecFile, "spec", "", "", "JSON specification file")
+	updateBlueprintCmd.Flags().Int64VarP(&ResourceVersion, "resourceversion", "", 0, "Only update if the blueprint has this resource version")
 	updateBlueprintCmd.MarkFlagRequired("spec")
 
 	setBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
@@ -76,10 +97,38 @@ func init() {
 	setBlueprintCmd.MarkFlagRequired("key")
 	setBlueprintCmd.MarkFlagRequired("value")
 
+	patchBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	patchBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	patchBlueprintCmd.Flags().StringVarP(&Patch, "patch", "", "", "JSON patch document, or a file containing it")
+	patchBlueprintCmd.Flags().StringVarP(&PatchType, "type", "", core.MergePatchType, "Patch type (merge or strategic)")
+	patchBlueprintCmd.Flags().Int64VarP(&ResourceVersion, "resourceversion", "", 0, "Only patch if the blueprint has this resource version")
+	patchBlueprintCmd.MarkFlagRequired("name")
+	patchBlueprintCmd.MarkFlagRequired("patch")
+
 	removeBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	removeBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	removeBlueprintCmd.Flags().Int64VarP(&ResourceVersion, "resourceversion", "", 0, "Only remove if the blueprint has this resource version")
 	removeBlueprintCmd.MarkFlagRequired("name")
 
+	applyBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	applyBlueprintCmd.Flags().StringVarP(&BlueprintPath, "filename", "f", "", "JSON blueprint file, or directory of JSON blueprint files")
+	applyBlueprintCmd.Flags().BoolVarP(&Prune, "prune", "", false, "Remove blueprints managed by --managedby that are not in the files")
+	applyBlueprintCmd.Flags().StringVarP(&ManagedBy, "managedby", "", "colonies-cli", "Value of the "+core.ManagedByLabel+" label set on applied blueprints")
+	applyBlueprintCmd.Flags().BoolVarP(&DryRun, "dryrun", "", false, "Only show the plan")
+	applyBlueprintCmd.MarkFlagRequired("filename")
+
+	diffBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	diffBlueprintCmd.Flags().StringVarP(&BlueprintPath, "filename", "f", "", "JSON blueprint file, or directory of JSON blueprint files")
+	diffBlueprintCmd.Flags().BoolVarP(&Prune, "prune", "", false, "Include blueprints managed by --managedby that are not in the files")
+	diffBlueprintCmd.Flags().StringVarP(&ManagedBy, "managedby", "", "colonies-cli", "Value of the "+core.ManagedByLabel+" label set on applied blueprints")
+	diffBlueprintCmd.MarkFlagRequired("filename")
+
+	finalizeBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	finalizeBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	finalizeBlueprintCmd.Flags().StringVarP(&Finalizer, "finalizer", "", "", "Finalizer to remove")
+	finalizeBlueprintCmd.MarkFlagRequired("name")
+	finalizeBlueprintCmd.MarkFlagRequired("finalizer")
+
 	reconcileBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	reconcileBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
 	reconcileBlueprintCmd.Flags().BoolVarP(&Force, "force", "f", false, "Force recreation of all containers (restarts with fresh image)")
@@ -91,6 +140,18 @@ func init() {
 	historyBlueprintCmd.Flags().IntVarP(&Generation, "generation", "g", -1, "Show details for specific generation")
 	historyBlueprintCmd.MarkFlagRequired("name")
 
+	rollbackBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	rollbackBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	rollbackBlueprintCmd.Flags().IntVarP(&Generation, "generation", "g", -1, "Generation to roll back to")
+	rollbackBlueprintCmd.MarkFlagRequired("name")
+	rollbackBlueprintCmd.MarkFlagRequired("generation")
+
+	waitBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
+	waitBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name")
+	waitBlueprintCmd.Flags().StringVarP(&WaitFor, "for", "", "condition="+core.ConditionReady, "Condition to wait for, condition=<type>[=<status>]")
+	waitBlueprintCmd.Flags().IntVarP(&Timeout, "timeout", "", 300, "Max time to wait in seconds, 0 waits forever")
+	waitBlueprintCmd.MarkFlagRequired("name")
+
 	logBlueprintCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	logBlueprintCmd.Flags().StringVarP(&BlueprintName, "name", "", "", "Blueprint name (optional, shows all reconciler logs if omitted)")
 	logBlueprintCmd.Flags().IntVarP(&Count, "count", "c", 100, "Number of log messages to fetch")
@@ -232,6 +293,77 @@ var removeBlueprintDefinitionCmd = &cobra.Command{
 	},
 }
 
+var updateBlueprintDefinitionCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a BlueprintDefinition",
+	Long:  "Update a BlueprintDefinition, e.g. to add a version or change the storage version (requires colony owner privileges)",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		jsonBytes, err := os.ReadFile(SpecFile)
+		CheckError(err)
+
+		var sd core.BlueprintDefinition
+		err = json.Unmarshal(jsonBytes, &sd)
+		CheckError(err)
+
+		// Set colony name if not specified
+		if sd.Metadata.ColonyName == "" {
+			sd.Metadata.ColonyName = ColonyName
+		}
+
+		updatedSD, err := client.UpdateBlueprintDefinition(&sd, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"BlueprintDefinitionID": updatedSD.ID,
+			"Name":                  updatedSD.Metadata.Name,
+			"Kind":                  updatedSD.Spec.Names.Kind,
+			"StorageVersion":        updatedSD.StorageVersion(),
+			"Generation":            updatedSD.Metadata.Generation,
+		}).Info("BlueprintDefinition updated")
+	},
+}
+
+var migrateBlueprintDefinitionCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate blueprints to the storage version",
+	Long:  "Convert all blueprints of a BlueprintDefinition to its storage version and report blueprints failing the storage schema (requires colony owner privileges)",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		report, err := client.MigrateBlueprintDefinition(ColonyName, BlueprintDefinitionName, DryRun, ColonyPrvKey)
+		CheckError(err)
+
+		counts := make(map[string]int)
+		for _, result := range report.Results {
+			counts[result.Status]++
+		}
+
+		if JSON {
+			jsonString, err := report.ToJSON()
+			CheckError(err)
+			fmt.Println(jsonString)
+		} else if len(report.Results) > 0 {
+			printBlueprintMigrationReportTable(report)
+		}
+
+		log.WithFields(log.Fields{
+			"Kind":           report.Kind,
+			"StorageVersion": report.StorageVersion,
+			"DryRun":         report.DryRun,
+			"Migrated":       counts[core.MigrationMigrated],
+			"Pending":        counts[core.MigrationPending],
+			"Unchanged":      counts[core.MigrationUnchanged],
+			"Failed":         counts[core.MigrationFailed],
+		}).Info("Blueprints migrated")
+
+		if counts[core.MigrationFailed] > 0 {
+			CheckError(fmt.Errorf("%d blueprints fail the schema of version '%s'", counts[core.MigrationFailed], report.StorageVersion))
+		}
+	},
+}
+
 var addBlueprintCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a Blueprint",
@@ -271,7 +403,7 @@ var getBlueprintCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		blueprint, err := client.GetBlueprint(ColonyName, BlueprintName, PrvKey)
+		blueprint, err := client.GetBlueprintByVersion(ColonyName, BlueprintName, BlueprintVersion, PrvKey)
 		CheckError(err)
 
 		if blueprint == nil {
@@ -347,14 +479,20 @@ var updateBlueprintCmd = &cobra.Command{
 			blueprint.Metadata.ColonyName = ColonyName
 		}
 
-		updatedBlueprint, err := client.UpdateBlueprint(&blueprint, PrvKey)
+		var updatedBlueprint *core.Blueprint
+		if cmd.Flags().Changed("resourceversion") {
+			updatedBlueprint, err = client.UpdateBlueprintWithResourceVersion(&blueprint, ResourceVersion, PrvKey)
+		} else {
+			updatedBlueprint, err = client.UpdateBlueprint(&blueprint, PrvKey)
+		}
 		CheckError(err)
 
 		log.WithFields(log.Fields{
-			"BlueprintID": updatedBlueprint.ID,
-			"Name":       updatedBlueprint.Metadata.Name,
-			"Kind":       updatedBlueprint.Kind,
-			"Generation": updatedBlueprint.Metadata.Generation,
+			"BlueprintID":     updatedBlueprint.ID,
+			"Name":            updatedBlueprint.Metadata.Name,
+			"Kind":            updatedBlueprint.Kind,
+			"Generation":      updatedBlueprint.Metadata.Generation,
+			"ResourceVersion": updatedBlueprint.Metadata.ResourceVersion,
 		}).Info("Blueprint updated")
 
 	},
@@ -423,8 +561,8 @@ var setBlueprintCmd = &cobra.Command{
 		// Update the blueprint spec
 		blueprint.Spec = specMap
 
-		// Update the blueprint in the colony
-		updatedBlueprint, err := client.UpdateBlueprint(blueprint, PrvKey)
+		// Update the blueprint in the colony, fails if someone else modified it after we read it
+		updatedBlueprint, err := client.UpdateBlueprintWithResourceVersion(blueprint, blueprint.Metadata.ResourceVersion, PrvKey)
 		CheckError(err)
 
 		log.WithFields(log.Fields{
@@ -449,6 +587,42 @@ var setBlueprintCmd = &cobra.Command{
 	},
 }
 
+var patchBlueprintCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "Patch a Blueprint",
+	Long:  "Apply a JSON merge patch or a strategic merge patch to a blueprint, e.g. --patch '{\"spec\":{\"replicas\":3}}'",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		patchJSON := []byte(Patch)
+		if !strings.HasPrefix(strings.TrimSpace(Patch), "{") {
+			var err error
+			patchJSON, err = os.ReadFile(Patch)
+			CheckError(err)
+		}
+
+		var patch map[string]interface{}
+		err := json.Unmarshal(patchJSON, &patch)
+		CheckError(err)
+
+		var patchedBlueprint *core.Blueprint
+		if cmd.Flags().Changed("resourceversion") {
+			patchedBlueprint, err = client.PatchBlueprintWithResourceVersion(ColonyName, BlueprintName, PatchType, patch, ResourceVersion, PrvKey)
+		} else {
+			patchedBlueprint, err = client.PatchBlueprint(ColonyName, BlueprintName, PatchType, patch, PrvKey)
+		}
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"BlueprintID":     patchedBlueprint.ID,
+			"Name":            patchedBlueprint.Metadata.Name,
+			"Kind":            patchedBlueprint.Kind,
+			"Generation":      patchedBlueprint.Metadata.Generation,
+			"ResourceVersion": patchedBlueprint.Metadata.ResourceVersion,
+		}).Info("Blueprint patched")
+	},
+}
+
 var removeBlueprintCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Remove a Blueprint",
@@ -456,7 +630,12 @@ var removeBlueprintCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		err := client.RemoveBlueprint(ColonyName, BlueprintName, PrvKey)
+		var err error
+		if cmd.Flags().Changed("resourceversion") {
+			err = client.RemoveBlueprintWithResourceVersion(ColonyName, BlueprintName, ResourceVersion, PrvKey)
+		} else {
+			err = client.RemoveBlueprint(ColonyName, BlueprintName, PrvKey)
+		}
 		CheckError(err)
 
 		log.WithFields(log.Fields{
@@ -466,6 +645,125 @@ var removeBlueprintCmd = &cobra.Command{
 	},
 }
 
+var applyBlueprintCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a directory of Blueprints",
+	Long:  "Create, update and optionally prune blueprints so that the colony matches the blueprint files. All changes are applied atomically.",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		blueprints, err := loadBlueprintFiles(BlueprintPath)
+		CheckError(err)
+
+		plan, err := client.ApplyBlueprints(ColonyName, blueprints, Prune, ManagedBy, DryRun, PrvKey)
+		CheckError(err)
+
+		printBlueprintApplyPlanTable(plan)
+
+		if !plan.HasChanges() {
+			log.Info("No changes, blueprints are up to date")
+		} else if plan.Applied {
+			log.WithFields(log.Fields{
+				"Created": plan.Count(core.ApplyCreate),
+				"Updated": plan.Count(core.ApplyUpdate),
+				"Deleted": plan.Count(core.ApplyDelete),
+			}).Info("Blueprints applied")
+		}
+	},
+}
+
+var diffBlueprintCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show drift between Blueprint files and the colony",
+	Long:  "Show the changes that applying the blueprint files would make, without changing anything",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		blueprints, err := loadBlueprintFiles(BlueprintPath)
+		CheckError(err)
+
+		plan, err := client.ApplyBlueprints(ColonyName, blueprints, Prune, ManagedBy, true, PrvKey)
+		CheckError(err)
+
+		if !plan.HasChanges() {
+			log.Info("No drift, blueprints are up to date")
+			return
+		}
+
+		printBlueprintApplyDiff(plan)
+	},
+}
+
+// loadBlueprintFiles reads blueprints from a JSON file, or from all JSON files in a directory. A file
+// contains a single blueprint or an array of blueprints.
+func loadBlueprintFiles(path string) ([]*core.Blueprint, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var blueprints []*core.Blueprint
+	for _, file := range files {
+		jsonBytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonString := strings.TrimSpace(string(jsonBytes))
+		if strings.HasPrefix(jsonString, "[") {
+			fileBlueprints, err := core.ConvertJSONToBlueprintArray(jsonString)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			blueprints = append(blueprints, fileBlueprints...)
+		} else {
+			blueprint, err := core.ConvertJSONToBlueprint(jsonString)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			blueprints = append(blueprints, blueprint)
+		}
+	}
+
+	if len(blueprints) == 0 {
+		return nil, errors.New("No blueprints found in " + path)
+	}
+
+	for _, blueprint := range blueprints {
+		if blueprint.Metadata.ColonyName == "" {
+			blueprint.Metadata.ColonyName = ColonyName
+		}
+	}
+
+	return blueprints, nil
+}
+
+var finalizeBlueprintCmd = &cobra.Command{
+	Use:   "finalize",
+	Short: "Remove a finalizer from a Blueprint",
+	Long:  "Remove a finalizer from a blueprint, e.g. when its reconciler is gone. A blueprint being deleted is removed when its last finalizer is removed.",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		err := client.RemoveBlueprintFinalizer(ColonyName, BlueprintName, Finalizer, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"Name":      BlueprintName,
+			"Finalizer": Finalizer,
+		}).Info("Blueprint finalizer removed")
+	},
+}
+
 var reconcileBlueprintCmd = &cobra.Command{
 	Use:   "reconcile",
 	Short: "Trigger immediate reconciliation of a blueprint",
@@ -542,6 +840,67 @@ var historyBlueprintCmd = &cobra.Command{
 	},
 }
 
+var rollbackBlueprintCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back a blueprint to a previous generation",
+	Long:  "Re-apply the spec of a previous generation from the blueprint history. The spec is applied as a new generation and reconciled.",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		blueprint, err := client.RollbackBlueprint(ColonyName, BlueprintName, int64(Generation), PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"BlueprintName":  blueprint.Metadata.Name,
+			"FromGeneration": Generation,
+			"Generation":     blueprint.Metadata.Generation,
+		}).Info("Blueprint rolled back")
+	},
+}
+
+var waitBlueprintCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for a blueprint condition",
+	Long:  "Wait until a blueprint condition, e.g. condition=Ready, is met for the current generation of the blueprint",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		conditionType, status, err := parseWaitCondition(WaitFor)
+		CheckError(err)
+
+		blueprint, err := client.WaitForBlueprintCondition(ColonyName, BlueprintName, conditionType, status, time.Duration(Timeout)*time.Second, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"BlueprintName": blueprint.Metadata.Name,
+			"Condition":     conditionType,
+			"Status":        status,
+			"Generation":    blueprint.Metadata.Generation,
+		}).Info("Blueprint condition met")
+	},
+}
+
+// parseWaitCondition parses condition=<type>[=<status>], the status defaults to True
+func parseWaitCondition(waitFor string) (string, string, error) {
+	parts := strings.Split(waitFor, "=")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "condition" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid --for '%s', must be condition=<type>[=<status>]", waitFor)
+	}
+
+	status := core.ConditionTrue
+	if len(parts) == 3 {
+		status = parts[2]
+	}
+
+	switch status {
+	case core.ConditionTrue, core.ConditionFalse, core.ConditionUnknown:
+	default:
+		return "", "", fmt.Errorf("invalid condition status '%s', must be %s, %s or %s", status, core.ConditionTrue, core.ConditionFalse, core.ConditionUnknown)
+	}
+
+	return parts[1], status, nil
+}
+
 var logBlueprintCmd = &cobra.Command{
 	Use:   "log",
 	Short: "Show logs from blueprint reconcilers",
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -110,6 +111,67 @@ func printBlueprintDefinitionTable(sd *core.BlueprintDefinition) {
 
 	t.Render()
 
+	// Versions section
+	if len(sd.Spec.Versions) > 0 {
+		t, theme = createTable(0)
+		t.SetTitle("Versions")
+
+		var versionCols = []table.Column{
+			{ID: "name", Name: "Name", SortIndex: 1},
+			{ID: "served", Name: "Served", SortIndex: 2},
+			{ID: "storage", Name: "Storage", SortIndex: 3},
+			{ID: "deprecated", Name: "Deprecated", SortIndex: 4},
+		}
+		t.SetCols(versionCols)
+
+		for _, version := range sd.Spec.Versions {
+			row = []interface{}{
+				termenv.String(version.Name).Foreground(theme.ColorMagenta),
+				termenv.String(fmt.Sprintf("%t", version.Served)).Foreground(theme.ColorGray),
+				termenv.String(fmt.Sprintf("%t", version.Storage)).Foreground(theme.ColorGray),
+				termenv.String(fmt.Sprintf("%t", version.Deprecated)).Foreground(theme.ColorGray),
+			}
+			t.AddRow(row)
+		}
+
+		t.Render()
+
+		t, theme = createTable(0)
+		t.SetTitle("Conversion")
+
+		row = []interface{}{
+			termenv.String("Strategy").Foreground(theme.ColorViolet),
+			termenv.String(sd.ConversionStrategy()).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+
+		if sd.Spec.Conversion != nil && sd.Spec.Conversion.Executor != nil {
+			row = []interface{}{
+				termenv.String("Executor Type").Foreground(theme.ColorViolet),
+				termenv.String(sd.Spec.Conversion.Executor.ExecutorType).Foreground(theme.ColorGray),
+			}
+			t.AddRow(row)
+
+			row = []interface{}{
+				termenv.String("Function Name").Foreground(theme.ColorViolet),
+				termenv.String(sd.Spec.Conversion.Executor.FunctionName).Foreground(theme.ColorGray),
+			}
+			t.AddRow(row)
+		}
+
+		if sd.Spec.Conversion != nil {
+			for _, mapping := range sd.Spec.Conversion.Mappings {
+				row = []interface{}{
+					termenv.String(mapping.From + " -> " + mapping.To).Foreground(theme.ColorViolet),
+					termenv.String(fmt.Sprintf("%d field mappings", len(mapping.Fields))).Foreground(theme.ColorGray),
+				}
+				t.AddRow(row)
+			}
+		}
+
+		t.Render()
+	}
+
 	// Schema section
 	if sd.Spec.Schema != nil {
 		t, theme = createTable(0)
@@ -165,6 +227,155 @@ func printBlueprintDefinitionTable(sd *core.BlueprintDefinition) {
 	}
 }
 
+// printBlueprintMigrationReportTable displays the result of migrating blueprints to the storage version
+func printBlueprintMigrationReportTable(report *core.BlueprintMigrationReport) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "name", Name: "Name", SortIndex: 1},
+		{ID: "from", Name: "From", SortIndex: 2},
+		{ID: "to", Name: "To", SortIndex: 3},
+		{ID: "status", Name: "Status", SortIndex: 4},
+		{ID: "details", Name: "Details", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, result := range report.Results {
+		statusColor := theme.ColorGreen
+		switch result.Status {
+		case core.MigrationFailed:
+			statusColor = theme.ColorRed
+		case core.MigrationPending:
+			statusColor = theme.ColorYellow
+		case core.MigrationUnchanged:
+			statusColor = theme.ColorGray
+		}
+
+		details := result.Error
+		if result.ProcessID != "" {
+			details = "process " + result.ProcessID
+		}
+		if details == "" {
+			details = "-"
+		}
+
+		row := []interface{}{
+			termenv.String(result.Name).Foreground(theme.ColorCyan),
+			termenv.String(result.FromVersion).Foreground(theme.ColorViolet),
+			termenv.String(result.ToVersion).Foreground(theme.ColorViolet),
+			termenv.String(result.Status).Foreground(statusColor),
+			termenv.String(details).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+// printBlueprintApplyPlanTable displays the actions of an apply
+func printBlueprintApplyPlanTable(plan *core.BlueprintApplyPlan) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "action", Name: "Action", SortIndex: 1},
+		{ID: "kind", Name: "Kind", SortIndex: 2},
+		{ID: "name", Name: "Name", SortIndex: 3},
+		{ID: "changes", Name: "Changes", SortIndex: 4},
+	}
+	t.SetCols(cols)
+
+	for _, step := range plan.Steps {
+		actionColor := theme.ColorGray
+		switch step.Action {
+		case core.ApplyCreate:
+			actionColor = theme.ColorGreen
+		case core.ApplyUpdate:
+			actionColor = theme.ColorYellow
+		case core.ApplyDelete:
+			actionColor = theme.ColorRed
+		}
+
+		changes := "-"
+		if step.Diff != nil && step.Diff.HasChanges {
+			paths := []string{}
+			for _, change := range append(append([]core.FieldChange{}, step.Diff.SpecChanges...), step.Diff.MetadataChanges...) {
+				paths = append(paths, change.Path)
+			}
+			sort.Strings(paths)
+			changes = strings.Join(paths, ", ")
+		}
+
+		row := []interface{}{
+			termenv.String(step.Action).Foreground(actionColor),
+			termenv.String(step.Kind).Foreground(theme.ColorViolet),
+			termenv.String(step.Name).Foreground(theme.ColorCyan),
+			termenv.String(changes).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+// printBlueprintApplyDiff displays every field that differs between blueprint files and the colony
+func printBlueprintApplyDiff(plan *core.BlueprintApplyPlan) {
+	for _, step := range plan.Steps {
+		if step.Action == core.ApplyUnchanged {
+			continue
+		}
+
+		t, theme := createTable(0)
+		t.SetTitle(step.Action + " " + step.Kind + "/" + step.Name)
+
+		if step.Diff == nil {
+			var cols = []table.Column{{ID: "action", Name: "Action", SortIndex: 1}}
+			t.SetCols(cols)
+			color := theme.ColorGreen
+			if step.Action == core.ApplyDelete {
+				color = theme.ColorRed
+			}
+			t.AddRow([]interface{}{termenv.String("blueprint will be " + step.Action + "d").Foreground(color)})
+			t.Render()
+			continue
+		}
+
+		var cols = []table.Column{
+			{ID: "path", Name: "Path", SortIndex: 1},
+			{ID: "type", Name: "Change", SortIndex: 2},
+			{ID: "old", Name: "Server", SortIndex: 3},
+			{ID: "new", Name: "Files", SortIndex: 4},
+		}
+		t.SetCols(cols)
+
+		changes := append(append([]core.FieldChange{}, step.Diff.SpecChanges...), step.Diff.MetadataChanges...)
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+		for _, change := range changes {
+			row := []interface{}{
+				termenv.String(change.Path).Foreground(theme.ColorCyan),
+				termenv.String(string(change.Type)).Foreground(theme.ColorYellow),
+				termenv.String(formatDiffValue(change.OldValue)).Foreground(theme.ColorRed),
+				termenv.String(formatDiffValue(change.NewValue)).Foreground(theme.ColorGreen),
+			}
+			t.AddRow(row)
+		}
+
+		t.Render()
+	}
+}
+
+func formatDiffValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(jsonBytes)
+}
+
 // printBlueprintsTable displays a list of Blueprints in a table
 func printBlueprintsTable(blueprints []*core.Blueprint) {
 	printBlueprintsTableWithClient(nil, blueprints)
@@ -180,8 +391,9 @@ func printBlueprintsTableWithClient(c *client.ColoniesClient, blueprints []*core
 		{ID: "location", Name: "Location", SortIndex: 3},
 		{ID: "executortype", Name: "Executor Type", SortIndex: 4},
 		{ID: "replicas", Name: "Replicas", SortIndex: 5},
-		{ID: "oldgen", Name: "OldGen", SortIndex: 6},
-		{ID: "generation", Name: "Gen", SortIndex: 7},
+		{ID: "condition", Name: "Condition", SortIndex: 6},
+		{ID: "oldgen", Name: "OldGen", SortIndex: 7},
+		{ID: "generation", Name: "Gen", SortIndex: 8},
 	}
 	t.SetCols(cols)
 
@@ -345,6 +557,7 @@ func printBlueprintsTableWithClient(c *client.ColoniesClient, blueprints []*core
 			termenv.String(locationStr).Foreground(theme.ColorGreen),
 			termenv.String(executorTypeStr).Foreground(theme.ColorBlue),
 			termenv.String(replicasStr).Foreground(theme.ColorMagenta),
+			termenv.String(conditionStr(blueprint)).Foreground(getConditionColor(blueprint, theme)),
 			termenv.String(oldGenStr).Foreground(getOldGenColor(oldGenStr, theme)),
 			termenv.String(fmt.Sprintf("%d", blueprint.Metadata.Generation)).Foreground(theme.ColorYellow),
 		}
@@ -362,6 +575,89 @@ func getOldGenColor(oldGen string, theme table.Theme) termenv.Color {
 	return theme.ColorRed
 }
 
+// conditionStr returns the condition summary of a blueprint, or - if it has no conditions
+func conditionStr(blueprint *core.Blueprint) string {
+	summary := blueprint.ConditionSummary()
+	if summary == "" {
+		return "-"
+	}
+	return summary
+}
+
+// getConditionColor returns appropriate color based on the condition summary of a blueprint
+func getConditionColor(blueprint *core.Blueprint, theme table.Theme) termenv.Color {
+	switch blueprint.ConditionSummary() {
+	case core.ConditionReady:
+		return theme.ColorGreen
+	case core.ConditionProgressing:
+		return theme.ColorYellow
+	case core.ConditionDegraded, core.ConditionNotReady:
+		return theme.ColorRed
+	}
+	return theme.ColorGray
+}
+
+// printBlueprintConditionsTable displays the conditions of a Blueprint
+func printBlueprintConditionsTable(blueprint *core.Blueprint) {
+	conditions := blueprint.GetConditions()
+	if len(conditions) == 0 {
+		return
+	}
+
+	t, theme := createTable(0)
+	t.SetTitle("Conditions")
+
+	var cols = []table.Column{
+		{ID: "type", Name: "Type", SortIndex: 1},
+		{ID: "status", Name: "Status", SortIndex: 2},
+		{ID: "reason", Name: "Reason", SortIndex: 3},
+		{ID: "message", Name: "Message", SortIndex: 4},
+		{ID: "transition", Name: "Last Transition", SortIndex: 5},
+		{ID: "observed", Name: "Observed Gen", SortIndex: 6},
+	}
+	t.SetCols(cols)
+
+	for _, condition := range conditions {
+		statusColor := theme.ColorGray
+		switch condition.Status {
+		case core.ConditionTrue:
+			statusColor = theme.ColorGreen
+			if condition.Type == core.ConditionDegraded {
+				statusColor = theme.ColorRed
+			}
+		case core.ConditionFalse:
+			statusColor = theme.ColorYellow
+		}
+
+		message := condition.Message
+		if len(message) > 60 {
+			message = message[:57] + "..."
+		}
+
+		transition := "-"
+		if !condition.LastTransitionTime.IsZero() {
+			transition = condition.LastTransitionTime.Format(TimeLayout)
+		}
+
+		observed := "-"
+		if condition.ObservedGeneration > 0 {
+			observed = fmt.Sprintf("%d", condition.ObservedGeneration)
+		}
+
+		row := []interface{}{
+			termenv.String(condition.Type).Foreground(theme.ColorCyan),
+			termenv.String(condition.Status).Foreground(statusColor),
+			termenv.String(condition.Reason).Foreground(theme.ColorViolet),
+			termenv.String(message).Foreground(theme.ColorGray),
+			termenv.String(transition).Foreground(theme.ColorGray),
+			termenv.String(observed).Foreground(theme.ColorYellow),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
 // printBlueprintTable displays a single Blueprint with details
 func printBlueprintTable(client *client.ColoniesClient, blueprint *core.Blueprint) {
 	t, theme := createTable(0)
@@ -391,6 +687,34 @@ func printBlueprintTable(client *client.ColoniesClient, blueprint *core.Blueprin
 	}
 	t.AddRow(row)
 
+	if len(blueprint.Metadata.OwnerReferences) > 0 {
+		owners := make([]string, len(blueprint.Metadata.OwnerReferences))
+		for i, ref := range blueprint.Metadata.OwnerReferences {
+			owners[i] = ref.Kind + "/" + ref.Name
+		}
+		row = []interface{}{
+			termenv.String("Owners").Foreground(theme.ColorCyan),
+			termenv.String(strings.Join(owners, ", ")).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	if len(blueprint.Metadata.Finalizers) > 0 {
+		row = []interface{}{
+			termenv.String("Finalizers").Foreground(theme.ColorCyan),
+			termenv.String(strings.Join(blueprint.Metadata.Finalizers, ", ")).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	if blueprint.IsBeingDeleted() {
+		row = []interface{}{
+			termenv.String("Deletion Requested").Foreground(theme.ColorCyan),
+			termenv.String(blueprint.Metadata.DeletionTimestamp.Format(TimeLayout)).Foreground(theme.ColorRed),
+		}
+		t.AddRow(row)
+	}
+
 	// Reconciliation status
 	if blueprint.Metadata.LastReconciliationProcess != "" {
 		process, err := client.GetProcess(blueprint.Metadata.LastReconciliationProcess, PrvKey)
@@ -768,6 +1092,8 @@ func printBlueprintTable(client *client.ColoniesClient, blueprint *core.Blueprin
 		}
 	}
 
+	printBlueprintConditionsTable(blueprint)
+
 	// Status section
 	if len(blueprint.Status) > 0 {
 		// Check if this is a deployment status with instances
@@ -869,18 +1195,22 @@ func printBlueprintTable(client *client.ColoniesClient, blueprint *core.Blueprin
 			t.SetTitle("Status")
 
 			// Filter out instance-related fields that should only show in the instances table
+			// Conditions are shown in their own table
 			excludeKeys := map[string]bool{
-				"instances":        true,
-				"runningInstances": true,
-				"stoppedInstances": true,
-				"totalInstances":   true,
+				"instances":              true,
+				"runningInstances":       true,
+				"stoppedInstances":       true,
+				"totalInstances":         true,
+				core.StatusConditionsKey: true,
 			}
 
+			rows := 0
 			for key, value := range blueprint.Status {
 				// Skip instance-related fields
 				if excludeKeys[key] {
 					continue
 				}
+				rows++
 
 				valueStr := fmt.Sprintf("%v", value)
 				if len(valueStr) > 60 {
@@ -895,7 +1225,7 @@ func printBlueprintTable(client *client.ColoniesClient, blueprint *core.Blueprin
 			}
 
 			// Only render if there are actually rows to display
-			if len(blueprint.Status) > len(excludeKeys) {
+			if rows > 0 {
 				t.Render()
 			}
 		}
@@ -27,6 +27,7 @@ func init() {
 	addColonyCmd.MarkFlagRequired("colonyid")
 	addColonyCmd.Flags().StringVarP(&TargetColonyName, "name", "", "", "Unique name of the Colony")
 	addColonyCmd.MarkFlagRequired("name")
+	addColonyCmd.Flags().BoolVarP(&RequireFuncReg, "requirefuncreg", "", false, "Validate submitted args against registered function args")
 
 	chColonyIDCmd.Flags().StringVarP(&TargetColonyID, "colonyid", "", "", "Colony Id")
 	chColonyIDCmd.MarkFlagRequired("colonyid")
@@ -63,7 +64,7 @@ var addColonyCmd = &cobra.Command{
 			CheckError(errors.New("Target Colony Id must be specifed"))
 		}
 
-		colony := &core.Colony{Name: TargetColonyName, ID: TargetColonyID}
+		colony := &core.Colony{Name: TargetColonyName, ID: TargetColonyID, RequireFuncReg: RequireFuncReg}
 
 		addedColony, err := client.AddColony(colony, ServerPrvKey)
 		CheckError(err)
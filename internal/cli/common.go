@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/colonyos/colonies/pkg/build"
 	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/client/backends"
 	"github.com/colonyos/colonies/pkg/constants"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/validate"
@@ -184,6 +186,29 @@ func parseEnv() {
 		}
 	}
 
+	// COLONIES_SERVER_GRPC_PORT is used by servers to enable the gRPC backend and by clients
+	// to connect to it when COLONIES_CLIENT_BACKENDS includes grpc
+	GRPCPortEnvStr := os.Getenv("COLONIES_SERVER_GRPC_PORT")
+	if GRPCPortEnvStr != "" && GRPCPort <= 0 {
+		GRPCPort, err = strconv.Atoi(GRPCPortEnvStr)
+		if err != nil {
+			log.Error("Failed to parse COLONIES_SERVER_GRPC_PORT")
+		}
+		CheckError(err)
+	}
+
+	ClientBackendsEnv := os.Getenv("COLONIES_CLIENT_BACKENDS")
+	if ClientBackendsEnv != "" {
+		ClientBackends = ClientBackendsEnv
+	}
+
+	// COLONIES_SERVER_ENDPOINTS lists additional HTTP endpoints (host:port) of other cluster nodes,
+	// used for failover when COLONIES_SERVER_HOST is unavailable
+	ServerEndpointsEnv := os.Getenv("COLONIES_SERVER_ENDPOINTS")
+	if ServerEndpointsEnv != "" {
+		ServerEndpoints = ServerEndpointsEnv
+	}
+
 	// COLONIES_TLS controls whether to use HTTPS (true) or HTTP (false)
 	TLSEnv := os.Getenv("COLONIES_TLS")
 	if TLSEnv == "true" {
@@ -516,9 +541,31 @@ func setup() *client.ColoniesClient {
 		envError()
 	}
 
-	// HTTP/Gin backend
-	log.WithFields(log.Fields{"ServerHost": ServerHost, "ServerPort": ServerPort, "Insecure": Insecure}).Debug("Starting a Colonies HTTP client")
-	return client.CreateColoniesClient(ServerHost, ServerPort, Insecure, SkipTLSVerify)
+	// Backends are tried in the order given by COLONIES_CLIENT_BACKENDS, default is HTTP/Gin
+	configs := []*backends.ClientConfig{}
+	for _, backendType := range backends.ParseClientBackendsFromEnv(ClientBackends) {
+		port := ServerPort
+		if backendType == backends.GRPCClientBackendType {
+			if GRPCPort <= 0 {
+				CheckError(errors.New("COLONIES_SERVER_GRPC_PORT must be set to use the grpc backend"))
+			}
+			port = GRPCPort
+		}
+		configs = append(configs, &backends.ClientConfig{
+			BackendType:   backendType,
+			Host:          ServerHost,
+			Port:          port,
+			Insecure:      Insecure,
+			SkipTLSVerify: SkipTLSVerify,
+		})
+	}
+
+	endpointConfigs, err := backends.ParseEndpointsFromEnv(ServerEndpoints, backends.CreateDefaultClientConfig(ServerHost, ServerPort, Insecure, SkipTLSVerify))
+	CheckError(err)
+	configs = append(configs, endpointConfigs...)
+
+	log.WithFields(log.Fields{"ServerHost": ServerHost, "ServerPort": ServerPort, "GRPCPort": GRPCPort, "Backends": ClientBackends, "Endpoints": ServerEndpoints, "Insecure": Insecure}).Debug("Starting a Colonies client")
+	return client.CreateColoniesClientWithMultipleBackends(configs)
 }
 
 func insertNewLines(s string, interval int) string {
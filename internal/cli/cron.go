@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	log "github.com/sirupsen/logrus"
@@ -18,6 +19,8 @@ func init() {
 	cronCmd.AddCommand(getCronCmd)
 	cronCmd.AddCommand(getCronsCmd)
 	cronCmd.AddCommand(runCronCmd)
+	cronCmd.AddCommand(suspendCronCmd)
+	cronCmd.AddCommand(resumeCronCmd)
 	rootCmd.AddCommand(cronCmd)
 
 	cronCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", "localhost", "Server host")
@@ -31,6 +34,14 @@ func init() {
 	addCronCmd.Flags().IntVarP(&CronInterval, "interval", "", -1, "Interval in seconds")
 	addCronCmd.Flags().BoolVarP(&CronRandom, "random", "", false, "Schedule a random cron, interval must be specified")
 	addCronCmd.Flags().BoolVarP(&WaitForPrevProcessGraph, "waitprevious", "", false, "Wait for previous processgrah to finish bore schedule a new workflow")
+	addCronCmd.Flags().StringVarP(&CronTimeZone, "timezone", "", "", "IANA time zone of the cron expression, e.g. Europe/Stockholm, server local time if not set")
+	addCronCmd.Flags().StringVarP(&CronStart, "start", "", "", "Do not run before this time (RFC3339)")
+	addCronCmd.Flags().StringVarP(&CronEnd, "end", "", "", "Do not run after this time (RFC3339)")
+	addCronCmd.Flags().IntVarP(&CronJitter, "jitter", "", 0, "Delay every run by a random number of seconds up to jitter")
+	addCronCmd.Flags().StringVarP(&CronMissedRuns, "missedruns", "", core.CronMissedRunsOnce, "Missed runs policy: once, skip or catchup")
+	addCronCmd.Flags().StringVarP(&CronConcurrency, "concurrency", "", "", "Concurrency policy: Allow, Forbid or Replace, Forbid if --waitprevious is set, otherwise Allow")
+	addCronCmd.Flags().IntVarP(&CronMaxConcurrent, "maxconcurrent", "", 0, "Maximum number of concurrent runs with the Allow policy, 0 is unlimited")
+	addCronCmd.Flags().IntVarP(&CronHistoryLimit, "historylimit", "", core.DefaultCronRunHistoryLimit, "Number of runs to keep in the run history")
 
 	delCronCmd.Flags().StringVarP(&CronID, "cronid", "", "", "Cron Id")
 	delCronCmd.MarkFlagRequired("cronid")
@@ -43,6 +54,12 @@ func init() {
 
 	runCronCmd.Flags().StringVarP(&CronID, "cronid", "", "", "Cron Id")
 	runCronCmd.MarkFlagRequired("cronid")
+
+	suspendCronCmd.Flags().StringVarP(&CronID, "cronid", "", "", "Cron Id")
+	suspendCronCmd.MarkFlagRequired("cronid")
+
+	resumeCronCmd.Flags().StringVarP(&CronID, "cronid", "", "", "Cron Id")
+	resumeCronCmd.MarkFlagRequired("cronid")
 }
 
 var cronCmd = &cobra.Command{
@@ -86,6 +103,21 @@ var addCronCmd = &cobra.Command{
 
 		cron := core.CreateCron(ColonyName, CronName, CronExpr, CronInterval, CronRandom, workflowSpecJSON)
 
+		cron.TimeZone = CronTimeZone
+		cron.Jitter = CronJitter
+		cron.MissedRunPolicy = CronMissedRuns
+		cron.ConcurrencyPolicy = CronConcurrency
+		cron.MaxConcurrentRuns = CronMaxConcurrent
+		cron.RunHistoryLimit = CronHistoryLimit
+		if CronStart != "" {
+			cron.StartTime, err = time.Parse(time.RFC3339, CronStart)
+			CheckError(err)
+		}
+		if CronEnd != "" {
+			cron.EndTime, err = time.Parse(time.RFC3339, CronEnd)
+			CheckError(err)
+		}
+
 		if WaitForPrevProcessGraph {
 			log.Info("Waiting for previous processgraph to finish")
 			cron.WaitForPrevProcessGraph = true
@@ -148,6 +180,10 @@ var getCronCmd = &cobra.Command{
 			for _, funcSpec := range workflowSpec.FunctionSpecs {
 				printFunctionSpecTable(&funcSpec)
 			}
+
+			if len(cron.Runs) > 0 {
+				printCronRunsTable(cron.Runs)
+			}
 		}
 	},
 }
@@ -187,3 +223,39 @@ var runCronCmd = &cobra.Command{
 		log.WithFields(log.Fields{"CronID": CronID}).Info("Running cron")
 	},
 }
+
+var suspendCronCmd = &cobra.Command{
+	Use:   "suspend",
+	Short: "Suspend a cron",
+	Long:  "Suspend a cron, a suspended cron is not triggered until it is resumed",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if CronID == "" {
+			CheckError(errors.New("Cron Id not specified"))
+		}
+
+		_, err := client.SuspendCron(CronID, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"CronID": CronID}).Info("Cron suspended")
+	},
+}
+
+var resumeCronCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a suspended cron",
+	Long:  "Resume a suspended cron, runs that were due while the cron was suspended are skipped",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		if CronID == "" {
+			CheckError(errors.New("Cron Id not specified"))
+		}
+
+		cron, err := client.ResumeCron(CronID, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"CronID": CronID, "NextRun": cron.NextRun.Format(TimeLayout)}).Info("Cron resumed")
+	},
+}
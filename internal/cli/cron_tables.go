@@ -91,6 +91,68 @@ func printCronTable(cron *core.Cron) {
 	}
 	t.AddRow(row)
 
+	timeZone := cron.TimeZone
+	if timeZone == "" {
+		timeZone = "Local"
+	}
+	row = []interface{}{
+		termenv.String("TimeZone").Foreground(theme.ColorCyan),
+		termenv.String(timeZone).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
+	if !cron.StartTime.IsZero() {
+		row = []interface{}{
+			termenv.String("StartTime").Foreground(theme.ColorCyan),
+			termenv.String(cron.StartTime.Format(TimeLayout)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	if !cron.EndTime.IsZero() {
+		row = []interface{}{
+			termenv.String("EndTime").Foreground(theme.ColorCyan),
+			termenv.String(cron.EndTime.Format(TimeLayout)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	row = []interface{}{
+		termenv.String("Jitter").Foreground(theme.ColorCyan),
+		termenv.String(strconv.Itoa(cron.Jitter)).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
+	missedRunPolicy := cron.MissedRunPolicy
+	if missedRunPolicy == "" {
+		missedRunPolicy = core.CronMissedRunsOnce
+	}
+	row = []interface{}{
+		termenv.String("MissedRunPolicy").Foreground(theme.ColorCyan),
+		termenv.String(missedRunPolicy).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
+	row = []interface{}{
+		termenv.String("ConcurrencyPolicy").Foreground(theme.ColorCyan),
+		termenv.String(cron.GetConcurrencyPolicy()).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
+	if cron.MaxConcurrentRuns > 0 {
+		row = []interface{}{
+			termenv.String("MaxConcurrentRuns").Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(cron.MaxConcurrentRuns)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	row = []interface{}{
+		termenv.String("Suspended").Foreground(theme.ColorCyan),
+		termenv.String(strconv.FormatBool(cron.Suspended)).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
 	t.Render()
 }
 
@@ -101,6 +163,7 @@ func printCronsTable(crons []*core.Cron) {
 		{ID: "cronid", Name: "CronId", SortIndex: 1},
 		{ID: "name", Name: "Name", SortIndex: 2},
 		{ID: "initiator", Name: "Initiator", SortIndex: 3},
+		{ID: "suspended", Name: "Suspended", SortIndex: 4},
 	}
 	t.SetCols(cols)
 
@@ -109,6 +172,31 @@ func printCronsTable(crons []*core.Cron) {
 			termenv.String(cron.ID).Foreground(theme.ColorGray),
 			termenv.String(cron.Name).Foreground(theme.ColorCyan),
 			termenv.String(cron.InitiatorName).Foreground(theme.ColorViolet),
+			termenv.String(strconv.FormatBool(cron.Suspended)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printCronRunsTable(runs []*core.CronRun) {
+	t, theme := createTable(0)
+
+	t.SetTitle("Runs")
+
+	var cols = []table.Column{
+		{ID: "processgraphid", Name: "ProcessGraphId", SortIndex: 1},
+		{ID: "starttime", Name: "Start Time", SortIndex: 2},
+		{ID: "state", Name: "State", SortIndex: 3},
+	}
+	t.SetCols(cols)
+
+	for _, run := range runs {
+		row := []interface{}{
+			termenv.String(run.ProcessGraphID).Foreground(theme.ColorGray),
+			termenv.String(run.StartTime.Format(TimeLayout)).Foreground(theme.ColorViolet),
+			termenv.String(State2String(run.State)).Foreground(theme.ColorCyan),
 		}
 		t.AddRow(row)
 	}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/fs"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +24,9 @@ func init() {
 	snapshotCmd.AddCommand(infoSnapshotCmd)
 	snapshotCmd.AddCommand(removeSnapshotCmd)
 	snapshotCmd.AddCommand(removeAllSnapshotsCmd)
+	snapshotCmd.AddCommand(diffSnapshotsCmd)
+	snapshotCmd.AddCommand(restoreSnapshotCmd)
+	snapshotCmd.AddCommand(cloneSnapshotCmd)
 
 	labelsCmd.AddCommand(listLabelsCmd)
 	labelsCmd.AddCommand(removeLabelCmd)
@@ -35,16 +39,27 @@ func init() {
 	fsCmd.AddCommand(getFileCmd)
 	fsCmd.AddCommand(removeFileCmd)
 	fsCmd.AddCommand(snapshotCmd)
+	fsCmd.AddCommand(keygenCmd)
+	fsCmd.AddCommand(rotateKeysCmd)
+	fsCmd.AddCommand(gcCmd)
+	fsCmd.AddCommand(retentionCmd)
+	fsCmd.AddCommand(lineageCmd)
+
+	retentionCmd.AddCommand(setRetentionCmd)
+	retentionCmd.AddCommand(listRetentionCmd)
+	retentionCmd.AddCommand(removeRetentionCmd)
 	rootCmd.AddCommand(fsCmd)
 
 	syncCmd.Flags().StringVarP(&SyncDir, "dir", "d", "", "Local directory to sync")
-	syncCmd.Flags().StringVarP(&StorageDriver, "driver", "", "s3", "Storage driver")
 	syncCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
 	syncCmd.Flags().BoolVarP(&Dry, "dry", "", false, "Dry run")
 	syncCmd.Flags().BoolVarP(&Yes, "yes", "", false, "Anser yes to all questions")
 	syncCmd.Flags().BoolVarP(&KeepLocal, "keeplocal", "", true, "Keep local files in case of conflicts")
 	syncCmd.Flags().BoolVarP(&SyncPlans, "syncplans", "", false, "Print sync plans details")
 	syncCmd.Flags().BoolVarP(&Quite, "quite", "", false, "No outputs")
+	syncCmd.Flags().StringVarP(&KeyRingFile, "keyring", "", "", "Key ring file used to encrypt and decrypt files, or set COLONIES_FS_KEYRING")
+	syncCmd.Flags().BoolVarP(&Chunked, "chunked", "", false, "Upload files as deduplicated chunks, or set COLONIES_FS_CHUNKING=true")
+	syncCmd.Flags().StringVarP(&ProcessID, "processid", "p", "", "Id of a process assigned to the executor, uploaded files are recorded as outputs of the process")
 
 	cleanCmd.Flags().StringVarP(&SyncDir, "dir", "d", "", "Local directory to clean")
 	cleanCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
@@ -63,6 +78,7 @@ func init() {
 	getFileCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
 	getFileCmd.Flags().StringVarP(&Filename, "name", "n", "", "Filename")
 	getFileCmd.Flags().StringVarP(&DownloadDir, "dir", "d", "", "Local directory to download file to")
+	getFileCmd.Flags().StringVarP(&KeyRingFile, "keyring", "", "", "Key ring file used to decrypt files, or set COLONIES_FS_KEYRING")
 
 	removeFileCmd.Flags().StringVarP(&FileID, "fileid", "i", "", "File Id")
 	removeFileCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
@@ -77,6 +93,7 @@ func init() {
 	downloadSnapshotCmd.Flags().StringVarP(&SnapshotID, "snapshotid", "i", "", "Snapshot Id")
 	downloadSnapshotCmd.Flags().StringVarP(&SnapshotName, "snapshotname", "n", "", "Snapshot name")
 	downloadSnapshotCmd.Flags().StringVarP(&DownloadDir, "dir", "d", "", "Local directory to download files to")
+	downloadSnapshotCmd.Flags().StringVarP(&KeyRingFile, "keyring", "", "", "Key ring file used to decrypt files, or set COLONIES_FS_KEYRING")
 
 	infoSnapshotCmd.Flags().StringVarP(&SnapshotID, "snapshotid", "i", "", "Snapshot Id")
 	infoSnapshotCmd.Flags().StringVarP(&SnapshotName, "snapshotname", "n", "", "Snapshot name")
@@ -84,9 +101,45 @@ func init() {
 	removeSnapshotCmd.Flags().StringVarP(&SnapshotID, "snapshotid", "i", "", "Snapshot Id")
 	removeSnapshotCmd.Flags().StringVarP(&SnapshotName, "snapshotname", "n", "", "Snapshot name")
 
+	diffSnapshotsCmd.Flags().StringVarP(&FromSnapshotName, "from", "", "", "Name of the snapshot to compare from")
+	diffSnapshotsCmd.MarkFlagRequired("from")
+	diffSnapshotsCmd.Flags().StringVarP(&ToSnapshotName, "to", "", "", "Name of the snapshot to compare to")
+	diffSnapshotsCmd.MarkFlagRequired("to")
+
+	restoreSnapshotCmd.Flags().StringVarP(&SnapshotName, "snapshotname", "n", "", "Snapshot name")
+	restoreSnapshotCmd.MarkFlagRequired("snapshotname")
+	restoreSnapshotCmd.Flags().BoolVarP(&Prune, "prune", "", false, "Remove files added after the snapshot was created")
+	restoreSnapshotCmd.Flags().BoolVarP(&Yes, "yes", "", false, "Anser yes to all questions")
+
+	cloneSnapshotCmd.Flags().StringVarP(&SnapshotName, "snapshotname", "n", "", "Snapshot name")
+	cloneSnapshotCmd.MarkFlagRequired("snapshotname")
+	cloneSnapshotCmd.Flags().StringVarP(&Label, "label", "l", "", "Label to clone the snapshot into, must be empty")
+	cloneSnapshotCmd.MarkFlagRequired("label")
+
 	removeLabelCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
 	removeLabelCmd.MarkFlagRequired("label")
 	removeLabelCmd.Flags().BoolVarP(&Yes, "yes", "", false, "Anser yes to all questions")
+
+	rotateKeysCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
+	rotateKeysCmd.MarkFlagRequired("label")
+	rotateKeysCmd.Flags().StringVarP(&KeyRingFile, "keyring", "", "", "Key ring file with the new and retired keys, or set COLONIES_FS_KEYRING")
+
+	gcCmd.Flags().BoolVarP(&Dry, "dry", "", false, "Dry run, only report what would be removed")
+	gcCmd.Flags().BoolVarP(&Yes, "yes", "", false, "Anser yes to all questions")
+	gcCmd.Flags().DurationVarP(&GCGracePeriod, "grace", "", 24*time.Hour, "Keep unreferenced objects issued within the grace period")
+
+	setRetentionCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
+	setRetentionCmd.MarkFlagRequired("label")
+	setRetentionCmd.Flags().IntVarP(&KeepLast, "keeplast", "", 0, "Number of revisions to keep of each file, 0 to disable")
+	setRetentionCmd.Flags().IntVarP(&KeepDays, "keepdays", "", 0, "Number of days to keep revisions, 0 to disable")
+
+	removeRetentionCmd.Flags().StringVarP(&Label, "label", "l", "", "Label")
+	removeRetentionCmd.MarkFlagRequired("label")
+
+	lineageCmd.Flags().StringVarP(&ProcessID, "processid", "p", "", "Process Id")
+	lineageCmd.Flags().StringVarP(&WorkflowID, "workflowid", "w", "", "Workflow (process graph) Id")
+	lineageCmd.Flags().StringVarP(&FileID, "fileid", "i", "", "File Id")
+	lineageCmd.Flags().StringVarP(&LineageDirection, "direction", "", "", "Only show inputs or outputs, "+core.LineageInput+" or "+core.LineageOutput)
 }
 
 var fsCmd = &cobra.Command{
@@ -101,6 +154,48 @@ var snapshotCmd = &cobra.Command{
 	Long:  "Manage file snapshots",
 }
 
+// createFSClient creates an FSClient using a storage backend registered in the colony if one has been
+// specified, otherwise the storage driver is configured using environmental variables
+func createFSClient(coloniesClient *client.ColoniesClient) (*fs.FSClient, error) {
+	if StorageBackendName == "" {
+		StorageBackendName = os.Getenv("COLONIES_FS_BACKEND")
+	}
+
+	if StorageBackendName != "" {
+		return fs.CreateFSClientWithBackend(coloniesClient, ColonyName, PrvKey, StorageBackendName)
+	}
+
+	if StorageDriver == "" {
+		StorageDriver = os.Getenv("COLONIES_FS_DRIVER")
+	}
+
+	if StorageDriver == "" || StorageDriver == fs.ProtocolS3 {
+		return fs.CreateFSClient(coloniesClient, ColonyName, PrvKey)
+	}
+
+	driver, err := fs.CreateStorageDriver(StorageDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.CreateFSClientWithDriver(coloniesClient, ColonyName, PrvKey, driver)
+}
+
+func setKeyRing(fsClient *fs.FSClient) {
+	if KeyRingFile == "" {
+		KeyRingFile = os.Getenv("COLONIES_FS_KEYRING")
+	}
+
+	if KeyRingFile == "" {
+		return
+	}
+
+	keyRing, err := fs.LoadKeyRing(KeyRingFile)
+	CheckError(err)
+
+	fsClient.SetKeyRing(keyRing)
+}
+
 func printSyncPlans(syncPlans []*fs.SyncPlan) {
 	filesToDownload := 0
 	filesToUpload := 0
@@ -187,13 +282,20 @@ var syncCmd = &cobra.Command{
 		CheckError(err)
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
+		setKeyRing(fsClient)
+
+		if Chunked || os.Getenv("COLONIES_FS_CHUNKING") == "true" {
+			fsClient.Chunking = true
+		}
 
 		if Quite {
 			fsClient.Quiet = true
 		}
 
+		fsClient.ProcessID = ProcessID
+
 		if !Quite {
 			log.Info("Calculating sync plans")
 		}
@@ -298,7 +400,7 @@ var cleanCmd = &cobra.Command{
 		CheckError(err)
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
 
 		if Quite {
@@ -380,7 +482,7 @@ var removeLabelCmd = &cobra.Command{
 		client := setup()
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
 
 		if Yes {
@@ -517,8 +619,9 @@ var getFileCmd = &cobra.Command{
 		}
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
+		setKeyRing(fsClient)
 
 		err = fsClient.Download(ColonyName, coloniesFiles[0].ID, DownloadDir)
 		CheckError(err)
@@ -535,7 +638,7 @@ var removeFileCmd = &cobra.Command{
 		client := setup()
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
 
 		if FileID != "" {
@@ -583,8 +686,9 @@ var downloadSnapshotCmd = &cobra.Command{
 		client := setup()
 
 		log.Debug("Starting a file storage client")
-		fsClient, err := fs.CreateFSClient(client, ColonyName, PrvKey)
+		fsClient, err := createFSClient(client)
 		CheckError(err)
+		setKeyRing(fsClient)
 
 		if DownloadDir == "" {
 			CheckError(errors.New("Download dir must be specified"))
@@ -697,3 +801,232 @@ var removeAllSnapshotsCmd = &cobra.Command{
 		log.Info("All snapshots removed")
 	},
 }
+
+var diffSnapshotsCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two snapshots",
+	Long:  "Compare two snapshots, files are compared by path relative to the label of each snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		diff, err := client.DiffSnapshots(ColonyName, FromSnapshotName, ToSnapshotName, PrvKey)
+		CheckError(err)
+
+		if diff.IsEmpty() {
+			log.WithFields(log.Fields{"From": FromSnapshotName, "To": ToSnapshotName}).Info("Snapshots are identical")
+			return
+		}
+
+		printSnapshotDiffTable(diff)
+	},
+}
+
+var restoreSnapshotCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a label to the state of a snapshot",
+	Long:  "Restore a label to the state of a snapshot, restored files are added as new revisions referencing the objects of the snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		snapshot, err := client.GetSnapshotByName(ColonyName, SnapshotName, PrvKey)
+		CheckError(err)
+
+		if !Yes {
+			fmt.Print("Are you sure you want to restore label <" + snapshot.Label + "> to snapshot <" + SnapshotName + ">? (yes,no): ")
+			reader := bufio.NewReader(os.Stdin)
+			reply, _ := reader.ReadString('\n')
+			if reply != "yes\n" && reply != "y\n" {
+				log.Info("Aborting ...")
+				os.Exit(0)
+			}
+		}
+
+		diff, err := client.RestoreSnapshot(ColonyName, SnapshotName, Prune, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"SnapshotName": SnapshotName, "Label": snapshot.Label, "Restored": len(diff.Added) + len(diff.Changed), "Pruned": len(diff.Removed)}).Info("Snapshot restored")
+
+		if !diff.IsEmpty() {
+			printSnapshotDiffTable(diff)
+		}
+	},
+}
+
+var cloneSnapshotCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone a snapshot into a new label",
+	Long:  "Clone a snapshot into a new label, cloned files reference the objects of the snapshot and no data is copied",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		Label = "/" + strings.Trim(Label, "/")
+
+		diff, err := client.CloneSnapshot(ColonyName, SnapshotName, Label, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"SnapshotName": SnapshotName, "Label": Label, "Files": len(diff.Added)}).Info("Snapshot cloned")
+	},
+}
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate a file encryption key",
+	Long:  "Generate a file encryption key",
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := fs.GenerateKey()
+		CheckError(err)
+
+		fmt.Println(key)
+	},
+}
+
+var rotateKeysCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-wrap file encryption keys with the current key in the key ring",
+	Long:  "Re-wrap file encryption keys with the current key in the key ring, files are not downloaded or uploaded again",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		Label = "/" + strings.Trim(Label, "/")
+
+		log.Debug("Starting a file storage client")
+		fsClient, err := createFSClient(client)
+		CheckError(err)
+		setKeyRing(fsClient)
+
+		counter, err := fsClient.RotateKeys(Label)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Label": Label, "Files": counter}).Info("Rotated file encryption keys")
+	},
+}
+
+var lineageCmd = &cobra.Command{
+	Use:   "lineage",
+	Short: "Show file revisions consumed and produced by processes",
+	Long:  "Show file revisions consumed and produced by a process or workflow, or the processes that consumed or produced a file",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		var lineage []*core.FileLineage
+		var err error
+		if ProcessID != "" {
+			lineage, err = client.GetProcessFileLineage(ColonyName, ProcessID, LineageDirection, PrvKey)
+		} else if WorkflowID != "" {
+			lineage, err = client.GetProcessGraphFileLineage(ColonyName, WorkflowID, LineageDirection, PrvKey)
+		} else if FileID != "" {
+			lineage, err = client.GetFileLineage(ColonyName, FileID, LineageDirection, PrvKey)
+		} else {
+			err = errors.New("Process Id, workflow Id nor file Id was provided")
+		}
+		CheckError(err)
+
+		if len(lineage) == 0 {
+			log.Info("No file lineage found")
+			return
+		}
+
+		printFileLineageTable(lineage)
+	},
+}
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove stale file revisions and unreferenced objects",
+	Long:  "Remove file revisions not retained by retention policies, and objects and chunks of the colony that are not referenced by any file or snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		log.Debug("Starting a file storage client")
+		fsClient, err := createFSClient(client)
+		CheckError(err)
+
+		report, err := fsClient.GC(GCGracePeriod, true)
+		CheckError(err)
+
+		printGCReport(report)
+
+		objects := len(report.UnreferencedObjects) + len(report.UnreferencedDriverObjects)
+		if len(report.StaleRevisions) == 0 && objects == 0 {
+			log.Info("Nothing to collect")
+			return
+		}
+
+		if Dry {
+			log.WithFields(log.Fields{"StaleRevisions": len(report.StaleRevisions), "UnreferencedObjects": objects, "Bytes": report.ReclaimedBytes}).Info("Dry run, nothing removed")
+			return
+		}
+
+		if !Yes {
+			fmt.Print("Are you sure you want to remove " + strconv.Itoa(len(report.StaleRevisions)) + " file revisions and " + strconv.Itoa(objects) + " objects? (yes,no): ")
+			reader := bufio.NewReader(os.Stdin)
+			reply, _ := reader.ReadString('\n')
+			if reply != "yes\n" && reply != "y\n" {
+				fmt.Println("Aborting ...")
+				return
+			}
+		}
+
+		report, err = fsClient.GC(GCGracePeriod, false)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"StaleRevisions": len(report.StaleRevisions), "UnreferencedObjects": len(report.UnreferencedObjects) + len(report.UnreferencedDriverObjects), "Bytes": report.ReclaimedBytes}).Info("Garbage collection completed")
+	},
+}
+
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage file revision retention policies",
+	Long:  "Manage file revision retention policies, stale revisions are removed by colonies fs gc",
+}
+
+var setRetentionCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the retention policy of a label",
+	Long:  "Set the retention policy of a label and its sub labels, the latest revision and revisions in snapshots are always kept",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		Label = "/" + strings.Trim(Label, "/")
+
+		policy, err := client.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(ColonyName, Label, KeepLast, KeepDays), PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Label": policy.Label, "KeepLast": policy.KeepLast, "KeepDays": policy.KeepDays}).Info("Retention policy set")
+	},
+}
+
+var listRetentionCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List retention policies",
+	Long:  "List retention policies",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		policies, err := client.GetFileRetentionPolicies(ColonyName, PrvKey)
+		CheckError(err)
+
+		if len(policies) == 0 {
+			log.Info("No retention policies found")
+			return
+		}
+
+		printFileRetentionPoliciesTable(policies)
+	},
+}
+
+var removeRetentionCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the retention policy of a label",
+	Long:  "Remove the retention policy of a label",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+
+		Label = "/" + strings.Trim(Label, "/")
+
+		err := client.RemoveFileRetentionPolicy(ColonyName, Label, PrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{"Label": Label}).Info("Retention policy removed")
+	},
+}
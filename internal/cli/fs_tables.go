@@ -234,6 +234,12 @@ func printFileInfoTable(file *core.File) {
 	}
 	t.AddRow(row)
 
+	row = []interface{}{
+		termenv.String("Chunks").Foreground(theme.ColorCyan),
+		termenv.String(strconv.Itoa(len(file.Chunks))).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
 	row = []interface{}{
 		termenv.String("S3 Accesskey").Foreground(theme.ColorCyan),
 		termenv.String("******************************").Foreground(theme.ColorGray),
@@ -353,3 +359,176 @@ func printSnapshotsTable(snapshots []*core.Snapshot) {
 
 	t.Render()
 }
+
+func printSnapshotDiffTable(diff *core.SnapshotDiff) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "path", Name: "Path", SortIndex: 1},
+		{ID: "change", Name: "Change", SortIndex: 2},
+		{ID: "from", Name: "From", SortIndex: 3},
+		{ID: "to", Name: "To", SortIndex: 4},
+	}
+	t.SetCols(cols)
+
+	formatSize := func(size int64) string {
+		return strconv.FormatInt(size/1024, 10) + " KiB"
+	}
+
+	for _, entry := range diff.Added {
+		t.AddRow([]interface{}{
+			termenv.String(entry.Path).Foreground(theme.ColorCyan),
+			termenv.String("added").Foreground(theme.ColorGreen),
+			termenv.String("").Foreground(theme.ColorGray),
+			termenv.String(formatSize(entry.ToSize)).Foreground(theme.ColorMagenta),
+		})
+	}
+
+	for _, entry := range diff.Changed {
+		t.AddRow([]interface{}{
+			termenv.String(entry.Path).Foreground(theme.ColorCyan),
+			termenv.String("changed").Foreground(theme.ColorYellow),
+			termenv.String(formatSize(entry.FromSize)).Foreground(theme.ColorMagenta),
+			termenv.String(formatSize(entry.ToSize)).Foreground(theme.ColorMagenta),
+		})
+	}
+
+	for _, entry := range diff.Removed {
+		t.AddRow([]interface{}{
+			termenv.String(entry.Path).Foreground(theme.ColorCyan),
+			termenv.String("removed").Foreground(theme.ColorRed),
+			termenv.String(formatSize(entry.FromSize)).Foreground(theme.ColorMagenta),
+			termenv.String("").Foreground(theme.ColorGray),
+		})
+	}
+
+	t.Render()
+}
+
+func printFileLineageTable(lineage []*core.FileLineage) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "processid", Name: "ProcessId", SortIndex: 1},
+		{ID: "direction", Name: "Direction", SortIndex: 2},
+		{ID: "label", Name: "Label", SortIndex: 3},
+		{ID: "name", Name: "Name", SortIndex: 4},
+		{ID: "fileid", Name: "FileId", SortIndex: 5},
+		{ID: "snapshotid", Name: "SnapshotId", SortIndex: 6},
+		{ID: "added", Name: "Added", SortIndex: 7},
+	}
+	t.SetCols(cols)
+
+	for _, l := range lineage {
+		row := []interface{}{
+			termenv.String(l.ProcessID).Foreground(theme.ColorGray),
+			termenv.String(l.Direction).Foreground(theme.ColorMagenta),
+			termenv.String(l.Label).Foreground(theme.ColorViolet),
+			termenv.String(l.Name).Foreground(theme.ColorCyan),
+			termenv.String(l.FileID).Foreground(theme.ColorGray),
+			termenv.String(l.SnapshotID).Foreground(theme.ColorGray),
+			termenv.String(l.Added.Format(TimeLayout)).Foreground(theme.ColorBlue),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printFileRetentionPoliciesTable(policies []*core.FileRetentionPolicy) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "label", Name: "Label", SortIndex: 1},
+		{ID: "keeplast", Name: "KeepLast", SortIndex: 2},
+		{ID: "keepdays", Name: "KeepDays", SortIndex: 3},
+		{ID: "added", Name: "Added", SortIndex: 4},
+	}
+	t.SetCols(cols)
+
+	for _, policy := range policies {
+		row := []interface{}{
+			termenv.String(policy.Label).Foreground(theme.ColorViolet),
+			termenv.String(strconv.Itoa(policy.KeepLast)).Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(policy.KeepDays)).Foreground(theme.ColorCyan),
+			termenv.String(policy.Added.Format(TimeLayout)).Foreground(theme.ColorBlue),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printGCReport(report *core.FileGCReport) {
+	if len(report.StaleRevisions) > 0 {
+		t, theme := createTable(0)
+
+		var cols = []table.Column{
+			{ID: "label", Name: "Label", SortIndex: 1},
+			{ID: "name", Name: "Name", SortIndex: 2},
+			{ID: "fileid", Name: "FileId", SortIndex: 3},
+			{ID: "size", Name: "Size", SortIndex: 4},
+			{ID: "added", Name: "Added", SortIndex: 5},
+		}
+		t.SetCols(cols)
+
+		for _, revision := range report.StaleRevisions {
+			row := []interface{}{
+				termenv.String(revision.Label).Foreground(theme.ColorViolet),
+				termenv.String(revision.Name).Foreground(theme.ColorCyan),
+				termenv.String(revision.ID).Foreground(theme.ColorGray),
+				termenv.String(strconv.FormatInt(revision.Size/1024, 10) + " KiB").Foreground(theme.ColorCyan),
+				termenv.String(revision.Added.Format(TimeLayout)).Foreground(theme.ColorBlue),
+			}
+			t.AddRow(row)
+		}
+
+		t.Render()
+	}
+
+	if len(report.UnreferencedObjects) > 0 {
+		t, theme := createTable(0)
+
+		var cols = []table.Column{
+			{ID: "backend", Name: "Backend", SortIndex: 1},
+			{ID: "object", Name: "Object", SortIndex: 2},
+			{ID: "issued", Name: "Issued", SortIndex: 3},
+		}
+		t.SetCols(cols)
+
+		for _, object := range report.UnreferencedObjects {
+			row := []interface{}{
+				termenv.String(object.Backend).Foreground(theme.ColorMagenta),
+				termenv.String(object.Object).Foreground(theme.ColorGray),
+				termenv.String(object.Issued.Format(TimeLayout)).Foreground(theme.ColorBlue),
+			}
+			t.AddRow(row)
+		}
+
+		t.Render()
+	}
+
+	if len(report.UnreferencedDriverObjects) > 0 {
+		t, theme := createTable(0)
+
+		var cols = []table.Column{
+			{ID: "protocol", Name: "Protocol", SortIndex: 1},
+			{ID: "object", Name: "Object", SortIndex: 2},
+			{ID: "size", Name: "Size", SortIndex: 3},
+			{ID: "modified", Name: "Modified", SortIndex: 4},
+		}
+		t.SetCols(cols)
+
+		for _, object := range report.UnreferencedDriverObjects {
+			row := []interface{}{
+				termenv.String(object.Protocol).Foreground(theme.ColorMagenta),
+				termenv.String(object.Object).Foreground(theme.ColorGray),
+				termenv.String(strconv.FormatInt(object.Size/1024, 10) + " KiB").Foreground(theme.ColorCyan),
+				termenv.String(object.Modified.Format(TimeLayout)).Foreground(theme.ColorBlue),
+			}
+			t.AddRow(row)
+		}
+
+		t.Render()
+	}
+}
@@ -28,14 +28,19 @@ func init() {
 	addGeneratorCmd.Flags().StringVarP(&GeneratorName, "name", "", "", "Generator name")
 	addGeneratorCmd.MarkFlagRequired("name")
 	addGeneratorCmd.Flags().IntVarP(&GeneratorTrigger, "trigger", "", -1, "Trigger")
-	addGeneratorCmd.MarkFlagRequired("trigger")
 	addGeneratorCmd.Flags().IntVarP(&GeneratorTimeout, "timeout", "", -1, "Timeout")
+	addGeneratorCmd.Flags().IntVarP(&GeneratorTriggerSize, "triggersize", "", 0, "Submit a batch when the packed args reach this many bytes")
+	addGeneratorCmd.Flags().StringVarP(&GeneratorWindow, "window", "", "", "Time window, tumbling or sliding")
+	addGeneratorCmd.Flags().IntVarP(&GeneratorWindowSize, "windowsize", "", 0, "Window size in seconds")
+	addGeneratorCmd.Flags().IntVarP(&GeneratorWindowSlide, "windowslide", "", 0, "Seconds between the start of two sliding windows")
+	addGeneratorCmd.Flags().StringVarP(&GeneratorBatchFormat, "batchformat", "", "", "How batches are passed to the workflow, args (default) or structured")
 
 	packGeneratorCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	packGeneratorCmd.Flags().StringVarP(&GeneratorID, "generatorid", "", "", "Generator Id")
 	packGeneratorCmd.MarkFlagRequired("generatorid")
 	packGeneratorCmd.Flags().StringVarP(&Arg, "arg", "", "", "Arg to pack to generator")
 	packGeneratorCmd.MarkFlagRequired("arg")
+	packGeneratorCmd.Flags().StringVarP(&Key, "key", "", "", "Args packed with the same key are batched together")
 
 	delGeneratorCmd.Flags().StringVarP(&PrvKey, "prvkey", "", "", "Private key")
 	delGeneratorCmd.Flags().StringVarP(&GeneratorID, "generatorid", "", "", "Generator Id")
@@ -81,15 +86,22 @@ var addGeneratorCmd = &cobra.Command{
 			CheckError(errors.New("Generator name not specified"))
 		}
 
-		if GeneratorTrigger == -1 {
+		if GeneratorTrigger == -1 && GeneratorTriggerSize <= 0 && GeneratorTimeout <= 0 && GeneratorWindow == "" {
 			CheckError(errors.New("Generator trigger not specified"))
 		}
 
 		generator := core.CreateGenerator(ColonyName, GeneratorName, workflowSpecJSON, GeneratorTrigger, GeneratorTimeout)
+		generator.TriggerSize = GeneratorTriggerSize
+		generator.WindowType = GeneratorWindow
+		generator.WindowSize = GeneratorWindowSize
+		generator.WindowSlide = GeneratorWindowSlide
+		generator.BatchFormat = GeneratorBatchFormat
+		CheckError(generator.ValidateTriggers())
+
 		addedGenerator, err := client.AddGenerator(generator, PrvKey)
 		CheckError(err)
 
-		log.WithFields(log.Fields{"GeneratorID": addedGenerator.ID, "GeneratorName": GeneratorName, "Trigger": GeneratorTrigger, "Timeout": GeneratorTimeout}).Info("Generator added")
+		log.WithFields(log.Fields{"GeneratorID": addedGenerator.ID, "GeneratorName": GeneratorName, "Trigger": GeneratorTrigger, "TriggerSize": GeneratorTriggerSize, "Timeout": GeneratorTimeout, "Window": GeneratorWindow}).Info("Generator added")
 	},
 }
 
@@ -104,10 +116,10 @@ var packGeneratorCmd = &cobra.Command{
 			CheckError(errors.New("Generator Id not specified"))
 		}
 
-		err := client.PackGenerator(GeneratorID, Arg, PrvKey)
+		err := client.PackGeneratorWithKey(GeneratorID, Key, Arg, PrvKey)
 		CheckError(err)
 
-		log.WithFields(log.Fields{"GeneratorID": GeneratorID, "Arg": Arg}).Info("Packing arg to generator")
+		log.WithFields(log.Fields{"GeneratorID": GeneratorID, "Key": Key, "Arg": Arg}).Info("Packing arg to generator")
 	},
 }
 
@@ -47,6 +47,50 @@ func printGeneratorTable(generator *core.Generator) {
 	}
 	t.AddRow(row)
 
+	if generator.TriggerSize > 0 {
+		row = []interface{}{
+			termenv.String("TriggerSize").Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(generator.TriggerSize)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	if generator.IsWindowed() {
+		row = []interface{}{
+			termenv.String("Window").Foreground(theme.ColorCyan),
+			termenv.String(generator.WindowType).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+
+		row = []interface{}{
+			termenv.String("WindowSize").Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(generator.WindowSize)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+
+		row = []interface{}{
+			termenv.String("WindowSlide").Foreground(theme.ColorCyan),
+			termenv.String(strconv.Itoa(int(generator.GetWindowSlide().Seconds()))).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+
+		row = []interface{}{
+			termenv.String("LastWindowEnd").Foreground(theme.ColorCyan),
+			termenv.String(generator.LastWindowEnd.Format(TimeLayout)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	batchFormat := generator.BatchFormat
+	if batchFormat == "" {
+		batchFormat = core.GeneratorBatchArgs
+	}
+	row = []interface{}{
+		termenv.String("BatchFormat").Foreground(theme.ColorCyan),
+		termenv.String(batchFormat).Foreground(theme.ColorGray),
+	}
+	t.AddRow(row)
+
 	row = []interface{}{
 		termenv.String("Lastrun").Foreground(theme.ColorCyan),
 		termenv.String(generator.LastRun.Format(TimeLayout)).Foreground(theme.ColorGray),
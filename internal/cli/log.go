@@ -1,14 +1,18 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/muesli/termenv"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -25,14 +29,22 @@ func init() {
 	addLogCmd.MarkFlagRequired("processid")
 	addLogCmd.Flags().StringVarP(&LogMsg, "msg", "m", "", "Message")
 	addLogCmd.MarkFlagRequired("msg")
+	addLogCmd.Flags().StringVarP(&LogLevel, "level", "", "", "Log level (debug, info, warn, error)")
+	addLogCmd.Flags().StringVarP(&LogStream, "stream", "", "", "Output stream (stdout, stderr)")
+	addLogCmd.Flags().StringSliceVarP(&LogFields, "field", "", make([]string, 0), "Log field as key=value, may be repeated")
 
 	getLogsCmd.Flags().StringVarP(&ProcessID, "processid", "p", "", "Process Id")
 	getLogsCmd.Flags().StringVarP(&TargetExecutorName, "executorname", "e", "", "Executor name")
+	getLogsCmd.Flags().StringVarP(&WorkflowID, "workflowid", "w", "", "Workflow (process graph) Id, requires --follow")
 	getLogsCmd.Flags().Int64VarP(&Since, "since", "", 0, "Fetch log generated since (unix nano) time")
 	getLogsCmd.Flags().IntVarP(&Count, "count", "", 100, "Number of messages to fetch")
 	getLogsCmd.Flags().BoolVarP(&Follow, "follow", "", false, "Follow process")
 	getLogsCmd.Flags().BoolVarP(&Latest, "latest", "l", true, "Show latest logs (most recent)")
 	getLogsCmd.Flags().BoolVarP(&First, "first", "f", false, "Show logs from the start")
+	getLogsCmd.Flags().Int64VarP(&Until, "until", "", 0, "Fetch log generated until (unix nano) time")
+	getLogsCmd.Flags().StringVarP(&LogLevel, "level", "", "", "Only show logs at or above level (debug, info, warn, error)")
+	getLogsCmd.Flags().StringVarP(&LogStream, "stream", "", "", "Only show logs written to stream (stdout, stderr)")
+	getLogsCmd.Flags().StringSliceVarP(&LogFields, "field", "", make([]string, 0), "Only show logs with field key=value, may be repeated")
 
 	searchLogsCmd.Flags().StringVarP(&Text, "text", "t", "", "Text to search")
 	searchLogsCmd.Flags().IntVarP(&Days, "days", "d", 1, "Number of days back in time to search")
@@ -54,7 +66,10 @@ var addLogCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := setup()
 
-		err := client.AddLog(ProcessID, LogMsg, PrvKey)
+		fields, err := core.ParseLogFields(LogFields)
+		CheckError(err)
+
+		err = client.AddStructuredLog(ProcessID, LogLevel, LogStream, fields, LogMsg, PrvKey)
 		CheckError(err)
 
 		log.WithFields(log.Fields{"ProcessID": ProcessID, "LogMsg": LogMsg}).Info("Adding log")
@@ -101,6 +116,74 @@ func formatLogMessage(message string, theme table.Theme) string {
 	return fmt.Sprintf("[%s] %-7s %s\n", timeColored, levelColored, msg)
 }
 
+// formatLogEntry formats a log entry for printing, structured entries are printed with their level and fields
+func formatLogEntry(logEntry *core.Log, theme table.Theme) string {
+	if JSON {
+		jsonStr, err := logEntry.ToJSON()
+		CheckError(err)
+		return jsonStr + "\n"
+	}
+
+	if logEntry.Level == "" && len(logEntry.Fields) == 0 {
+		return formatLogMessage(logEntry.Message, theme)
+	}
+
+	keys := make([]string, 0, len(logEntry.Fields))
+	for k := range logEntry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	message := strings.TrimSuffix(logEntry.Message, "\n")
+	for _, k := range keys {
+		message += " " + termenv.String(k+"="+logEntry.Fields[k]).Foreground(theme.ColorGray).String()
+	}
+
+	timestamp := time.Unix(0, logEntry.Timestamp).Format(TimeLayout)
+	level := logEntry.Level
+	if level == "" {
+		level = core.LogLevelInfo
+	}
+
+	return formatLogMessage(fmt.Sprintf("time=\"%s\" level=%s msg=\"%s\"", timestamp, level, message), theme)
+}
+
+// followLogTimeout is the number of seconds a log subscription lasts before it is renewed
+const followLogTimeout = 60
+
+// followLogs prints logs as they are added using a realtime subscription. The subscription is
+// renewed from the last printed entry when it times out or the connection is lost.
+func followLogs(client *client.ColoniesClient, fields map[string]string, theme table.Theme) {
+	lastTimestamp := Since
+	for {
+		msg := rpc.CreateSubscribeLogsMsg(ColonyName, ProcessID, TargetExecutorName, WorkflowID, lastTimestamp, followLogTimeout)
+		msg.Level = LogLevel
+		msg.Stream = LogStream
+		msg.Fields = fields
+		subscription, err := client.SubscribeLogs(msg, PrvKey)
+		CheckError(err)
+
+		for logs := range subscription.LogsChan {
+			for _, logEntry := range logs {
+				fmt.Print(formatLogEntry(logEntry, theme))
+				lastTimestamp = logEntry.Timestamp
+			}
+		}
+
+		select {
+		case err := <-subscription.ErrChan:
+			var coloniesErr *core.ColoniesError
+			if errors.As(err, &coloniesErr) {
+				CheckError(err)
+			}
+			log.WithFields(log.Fields{"Error": err}).Debug("Log subscription lost, resubscribing")
+			subscription.Close()
+			time.Sleep(1 * time.Second)
+		default:
+		}
+	}
+}
+
 var getLogsCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get logs added to a process",
@@ -114,30 +197,36 @@ var getLogsCmd = &cobra.Command{
 		// If --first is specified, disable --latest
 		useLatest := Latest && !First
 
-		if Follow {
-			var logs []*core.Log
-			var lastTimestamp int64
-			lastTimestamp = 0
-			for {
-				if TargetExecutorName == "" {
-					logs, err = client.GetLogsByProcessSince(ColonyName, ProcessID, Count, lastTimestamp, PrvKey)
-				} else {
-					logs, err = client.GetLogsByExecutorSince(ColonyName, TargetExecutorName, Count, lastTimestamp, PrvKey)
-				}
-				CheckError(err)
-				if len(logs) == 0 {
-					time.Sleep(1 * time.Second)
-					continue
-				} else {
-					for _, logEntry := range logs {
-						fmt.Print(formatLogMessage(logEntry.Message, theme))
-					}
-					lastTimestamp = logs[len(logs)-1].Timestamp
-				}
+		fields, err := core.ParseLogFields(LogFields)
+		CheckError(err)
+
+		if WorkflowID != "" && !Follow {
+			CheckError(errors.New("--workflowid requires --follow"))
+		}
+
+		var filter *core.LogFilter
+		if LogLevel != "" || LogStream != "" || len(fields) > 0 || Until > 0 {
+			filter = &core.LogFilter{
+				ColonyName:   ColonyName,
+				ProcessID:    ProcessID,
+				ExecutorName: TargetExecutorName,
+				Level:        LogLevel,
+				Stream:       LogStream,
+				Fields:       fields,
+				Since:        Since,
+				Until:        Until,
+				Latest:       useLatest && Since == 0,
+				Count:        Count,
 			}
+		}
+
+		if Follow {
+			followLogs(client, fields, theme)
 		} else {
 			var logs []*core.Log
-			if TargetExecutorName == "" {
+			if filter != nil {
+				logs, err = client.GetLogs(filter, PrvKey)
+			} else if TargetExecutorName == "" {
 				if useLatest && Since == 0 {
 					// Get latest logs (most recent count logs)
 					logs, err = client.GetLogsByProcessLatest(ColonyName, ProcessID, Count, PrvKey)
@@ -156,7 +245,7 @@ var getLogsCmd = &cobra.Command{
 			}
 			CheckError(err)
 			for _, logEntry := range logs {
-				fmt.Print(formatLogMessage(logEntry.Message, theme))
+				fmt.Print(formatLogEntry(logEntry, theme))
 			}
 		}
 	},
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	logSinkCmd.AddCommand(addLogSinkCmd)
+	logSinkCmd.AddCommand(listLogSinksCmd)
+	logSinkCmd.AddCommand(removeLogSinkCmd)
+	logCmd.AddCommand(logSinkCmd)
+
+	addLogSinkCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	addLogSinkCmd.Flags().StringVarP(&LogSinkName, "name", "", "", "Log sink name")
+	addLogSinkCmd.MarkFlagRequired("name")
+	addLogSinkCmd.Flags().StringVarP(&LogSinkType, "type", "", "", "Log sink type (file, syslog, http, otlp)")
+	addLogSinkCmd.MarkFlagRequired("type")
+	addLogSinkCmd.Flags().StringVarP(&LogSinkTarget, "target", "", "", "File path relative to the colony directory in the server log sink directory, syslog address (udp://host:514) or endpoint URL")
+	addLogSinkCmd.MarkFlagRequired("target")
+	addLogSinkCmd.Flags().StringSliceVarP(&LogSinkHeaders, "header", "", make([]string, 0), "HTTP header as key=value sent to http and otlp sinks, may be repeated")
+	addLogSinkCmd.Flags().StringVarP(&LogLevel, "level", "", "", "Only forward logs at or above level (debug, info, warn, error)")
+	addLogSinkCmd.Flags().IntVarP(&LogSinkBatchSize, "batchsize", "", 0, "Number of log entries sent in one batch, 0 means server default")
+	addLogSinkCmd.Flags().IntVarP(&LogSinkFlushInterval, "flushinterval", "", 0, "Milliseconds before a partial batch is sent, 0 means server default")
+	addLogSinkCmd.Flags().Int64VarP(&LogSinkMaxSize, "maxsize", "", 0, "Bytes before a file sink is rotated, 0 means server default")
+	addLogSinkCmd.Flags().IntVarP(&LogSinkMaxFiles, "maxfiles", "", 0, "Number of rotated files kept by a file sink, 0 means server default")
+
+	listLogSinksCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+
+	removeLogSinkCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	removeLogSinkCmd.Flags().StringVarP(&LogSinkName, "name", "", "", "Log sink name")
+	removeLogSinkCmd.MarkFlagRequired("name")
+}
+
+var logSinkCmd = &cobra.Command{
+	Use:   "sink",
+	Short: "Manage log sinks",
+	Long:  "Manage log sinks, log entries added to a colony are forwarded in batches to all registered sinks",
+}
+
+var addLogSinkCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new log sink",
+	Long:  "Add a new log sink",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		headers, err := core.ParseLogFields(LogSinkHeaders)
+		CheckError(err)
+
+		sink := core.CreateLogSink(ColonyName, LogSinkName, LogSinkType, LogSinkTarget)
+		if headers != nil {
+			sink.Headers = headers
+		}
+		sink.Level = LogLevel
+		sink.BatchSize = LogSinkBatchSize
+		sink.FlushInterval = LogSinkFlushInterval
+		sink.MaxSize = LogSinkMaxSize
+		sink.MaxFiles = LogSinkMaxFiles
+
+		addedSink, err := client.AddLogSink(sink, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"ColonyName": ColonyName,
+			"Name":       addedSink.Name,
+			"LogSinkID":  addedSink.ID,
+			"Type":       addedSink.Type,
+			"Target":     addedSink.Target}).
+			Info("Log sink added")
+	},
+}
+
+var listLogSinksCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List log sinks in a colony",
+	Long:  "List log sinks in a colony",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		sinks, err := client.GetLogSinks(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		if len(sinks) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No log sinks found")
+			os.Exit(0)
+		}
+
+		printLogSinksTable(sinks)
+	},
+}
+
+var removeLogSinkCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a log sink from a colony",
+	Long:  "Remove a log sink from a colony, logs already forwarded are not affected",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		fmt.Print("WARNING!!! Are you sure you want to remove log sink <" + LogSinkName + "> in colony <" + ColonyName + ">? (YES,no): ")
+
+		reader := bufio.NewReader(os.Stdin)
+		reply, _ := reader.ReadString('\n')
+		if reply == "YES\n" {
+			err := client.RemoveLogSink(ColonyName, LogSinkName, ColonyPrvKey)
+			CheckError(err)
+
+			log.WithFields(log.Fields{
+				"ColonyName":  ColonyName,
+				"LogSinkName": LogSinkName}).
+				Info("Log sink removed")
+		} else {
+			fmt.Println("Aborting ...")
+		}
+	},
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func printLogSinksTable(sinks []*core.LogSink) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "Name", Name: "Name", SortIndex: 1},
+		{ID: "Type", Name: "Type", SortIndex: 2},
+		{ID: "Target", Name: "Target", SortIndex: 3},
+		{ID: "Level", Name: "Level", SortIndex: 4},
+	}
+	t.SetCols(cols)
+
+	for _, sink := range sinks {
+		level := sink.Level
+		if level == "" {
+			level = "all"
+		}
+		row := []interface{}{
+			termenv.String(sink.Name).Foreground(theme.ColorCyan),
+			termenv.String(sink.Type).Foreground(theme.ColorMagenta),
+			termenv.String(sink.Target).Foreground(theme.ColorViolet),
+			termenv.String(level).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -36,6 +37,9 @@ var TLSCert string
 var TLSKey string
 var ServerHost string
 var ServerPort int
+var GRPCPort int
+var ClientBackends string
+var ServerEndpoints string
 var MonitorPort int
 var MonitorInterval int
 var ServerID string
@@ -51,6 +55,7 @@ var FunctionID string
 var TargetColonyID string
 var TargetColonyName string
 var NewColonyName string
+var RequireFuncReg bool
 var TargetExecutorID string
 var TargetExecutorType string
 var TargetExecutorName string
@@ -74,10 +79,24 @@ var GeneratorID string
 var GeneratorName string
 var GeneratorTrigger int
 var GeneratorTimeout int
+var GeneratorTriggerSize int
+var GeneratorWindow string
+var GeneratorWindowSize int
+var GeneratorWindowSlide int
+var GeneratorBatchFormat string
 var GeneratorCheckerPeriod int
 var FuncName string
 var BlueprintDefinitionName string
 var BlueprintName string
+var BlueprintVersion string
+var DryRun bool
+var ResourceVersion int64
+var PatchType string
+var Patch string
+var Finalizer string
+var BlueprintPath string
+var ManagedBy string
+var WaitFor string
 var Kind string
 var Arg string
 var Args []string
@@ -101,6 +120,14 @@ var CronExpr string
 var CronInterval int
 var CronCheckerPeriod int
 var CronRandom bool
+var CronTimeZone string
+var CronStart string
+var CronEnd string
+var CronJitter int
+var CronMissedRuns string
+var CronConcurrency string
+var CronMaxConcurrent int
+var CronHistoryLimit int
 var WaitForPrevProcessGraph bool
 var Long float64
 var Lat float64
@@ -114,7 +141,11 @@ var Failed bool
 var Cancelled bool
 var TimescaleDB bool
 var LogMsg string
+var LogLevel string
+var LogStream string
+var LogFields []string
 var Since int64
+var Until int64
 var Follow bool
 var Latest bool
 var First bool
@@ -123,12 +154,21 @@ var StorageDriver string
 var Label string
 var Dry bool
 var KeepLocal bool
+var Chunked bool
+var GCGracePeriod time.Duration
+var KeepLast int
+var KeepDays int
 var Yes bool
 var Filename string
 var FileID string
 var DownloadDir string
+var KeyRingFile string
 var SnapshotID string
 var SnapshotName string
+var FromSnapshotName string
+var ToSnapshotName string
+var Prune bool
+var LineageDirection string
 var KwArgs []string
 var Snapshots []string
 var Retention bool
@@ -149,6 +189,27 @@ var Text string
 var Days int
 var LocationName string
 var LocationDesc string
+var WebhookName string
+var WebhookURL string
+var WebhookSecret string
+var WebhookKinds []string
+var WebhookStates []string
+var WebhookExecutorTypes []string
+var WebhookLabels []string
+var StorageBackendName string
+var StorageBackendServer string
+var StorageBackendBucket string
+var StorageBackendRegion string
+var StorageBackendTLS bool
+var StorageBackendSkipVerify bool
+var LogSinkName string
+var LogSinkType string
+var LogSinkTarget string
+var LogSinkHeaders []string
+var LogSinkBatchSize int
+var LogSinkFlushInterval int
+var LogSinkMaxSize int64
+var LogSinkMaxFiles int
 var ASCII bool
 var Print bool
 var SecondsBack int
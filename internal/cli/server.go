@@ -36,12 +36,13 @@ func init() {
 	serverCmd.PersistentFlags().StringVarP(&TLSCert, "tlscert", "", "", "TLS certificate (can also use COLONIES_SERVER_HTTP_TLS_CERT)")
 	serverCmd.PersistentFlags().StringVarP(&TLSKey, "tlskey", "", "", "TLS key (can also use COLONIES_SERVER_HTTP_TLS_KEY)")
 	serverCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port (can also use COLONIES_SERVER_HTTP_PORT)")
+	serverCmd.PersistentFlags().IntVarP(&GRPCPort, "grpcport", "", -1, "Server gRPC port, gRPC is disabled if not set (can also use COLONIES_SERVER_GRPC_PORT)")
 	serverCmd.PersistentFlags().StringVarP(&EtcdName, "etcdname", "", "etcd", "Etcd name")
 	serverCmd.PersistentFlags().StringVarP(&EtcdHost, "etcdhost", "", "0.0.0.0", "Etcd host name")
 	serverCmd.PersistentFlags().IntVarP(&EtcdClientPort, "etcdclientport", "", 2379, "Etcd port")
 	serverCmd.PersistentFlags().IntVarP(&EtcdPeerPort, "etcdpeerport", "", 2380, "Etcd peer port")
 	serverCmd.PersistentFlags().IntVarP(&RelayPort, "relayport", "", 2381, "Colonies server relay port")
-	serverCmd.PersistentFlags().StringSliceVarP(&EtcdCluster, "initial-cluster", "", make([]string, 0), "Cluster config, e.g. --etcdcluster server1=localhost:peerport:relayport:apiport,server2=localhost:peerport:relayport:apiport")
+	serverCmd.PersistentFlags().StringSliceVarP(&EtcdCluster, "initial-cluster", "", make([]string, 0), "Cluster config, e.g. --etcdcluster server1=localhost:peerport:relayport:apiport,server2=localhost:peerport:relayport:apiport, append :grpcport to announce the gRPC port of a node")
 	serverCmd.PersistentFlags().StringVarP(&EtcdDataDir, "etcddatadir", "", "", "Etcd data dir")
 	serverCmd.PersistentFlags().BoolVarP(&InitDB, "initdb", "", false, "Initialize DB")
 	serverCmd.PersistentFlags().BoolVarP(&Insecure, "insecure", "", false, "Disable TLS")
@@ -83,6 +84,20 @@ func startServer(
 		staleExecutorDuration,
 	)
 
+	if GRPCPort > 0 {
+		grpcConfig := &server.ServerConfig{
+			BackendType:       server.GRPCBackendType,
+			Port:              GRPCPort,
+			TLS:               UseTLS,
+			TLSPrivateKeyPath: TLSKey,
+			TLSCertPath:       TLSCert,
+			Enabled:           true,
+		}
+		grpcServer, err := server.NewGRPCManagedServer(grpcConfig, &server.SharedResources{BaseServer: srv})
+		CheckError(err)
+		CheckError(grpcServer.Start())
+	}
+
 	for {
 		err := srv.ServeForever()
 		if err != nil {
@@ -253,6 +268,9 @@ var serverStartCmd = &cobra.Command{
 		}).Info("Database connection established")
 
 		node := cluster.Node{Name: EtcdName, Host: EtcdHost, APIPort: ServerPort, EtcdClientPort: EtcdClientPort, EtcdPeerPort: EtcdPeerPort, RelayPort: RelayPort}
+		if GRPCPort > 0 {
+			node.GRPCPort = GRPCPort
+		}
 		clusterConfig := cluster.Config{}
 
 		if len(EtcdCluster) > 0 {
@@ -265,7 +283,7 @@ var serverStartCmd = &cobra.Command{
 				}
 				name := split1[0]
 				split2 := strings.Split(split1[1], ":")
-				if len(split2) != 4 {
+				if len(split2) != 4 && len(split2) != 5 {
 					CheckError(errors.New(errMsg))
 				}
 				host := split2[0]
@@ -279,6 +297,11 @@ var serverStartCmd = &cobra.Command{
 				apiPort, err := strconv.Atoi(portStr3)
 				CheckError(err)
 				node := cluster.Node{Name: name, Host: host, EtcdClientPort: EtcdClientPort, EtcdPeerPort: etcPeerPort, RelayPort: relayPort, APIPort: apiPort}
+				if len(split2) == 5 {
+					grpcPort, err := strconv.Atoi(split2[4])
+					CheckError(err)
+					node.GRPCPort = grpcPort
+				}
 				clusterConfig.AddNode(node)
 			}
 		} else {
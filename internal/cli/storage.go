@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	storageCmd.AddCommand(addStorageBackendCmd)
+	storageCmd.AddCommand(listStorageBackendsCmd)
+	storageCmd.AddCommand(removeStorageBackendCmd)
+	rootCmd.AddCommand(storageCmd)
+
+	storageCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", DefaultServerHost, "Server host")
+	storageCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+
+	addStorageBackendCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	addStorageBackendCmd.Flags().StringVarP(&StorageBackendName, "name", "", "", "Storage backend name")
+	addStorageBackendCmd.MarkFlagRequired("name")
+	addStorageBackendCmd.Flags().StringVarP(&StorageBackendServer, "server", "", "", "S3 endpoint, e.g. s3.amazonaws.com or minio:9000")
+	addStorageBackendCmd.MarkFlagRequired("server")
+	addStorageBackendCmd.Flags().StringVarP(&StorageBackendBucket, "bucket", "", "", "S3 bucket")
+	addStorageBackendCmd.MarkFlagRequired("bucket")
+	addStorageBackendCmd.Flags().StringVarP(&StorageBackendRegion, "region", "", "", "S3 region")
+	addStorageBackendCmd.Flags().BoolVarP(&StorageBackendTLS, "tls", "", true, "Use TLS")
+	addStorageBackendCmd.Flags().BoolVarP(&StorageBackendSkipVerify, "skipverify", "", false, "Skip TLS certificate verification")
+
+	listStorageBackendsCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+
+	removeStorageBackendCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	removeStorageBackendCmd.Flags().StringVarP(&StorageBackendName, "name", "", "", "Storage backend name")
+	removeStorageBackendCmd.MarkFlagRequired("name")
+}
+
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Manage storage backends",
+	Long:  "Manage storage backends used by ColonyFS, credentials are kept by the server and executors get presigned URLs",
+}
+
+var addStorageBackendCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new storage backend",
+	Long:  "Add a new storage backend, credentials are read from AWS_S3_ACCESSKEY and AWS_S3_SECRETKEY",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		backend := core.CreateStorageBackend(ColonyName, StorageBackendName, StorageBackendServer, StorageBackendBucket, os.Getenv("AWS_S3_ACCESSKEY"), os.Getenv("AWS_S3_SECRETKEY"))
+		backend.Region = StorageBackendRegion
+		backend.TLS = StorageBackendTLS
+		backend.SkipVerify = StorageBackendSkipVerify
+
+		addedBackend, err := client.AddStorageBackend(backend, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"ColonyName": ColonyName,
+			"Name":       addedBackend.Name,
+			"BackendID":  addedBackend.ID,
+			"Server":     addedBackend.Server,
+			"Bucket":     addedBackend.Bucket}).
+			Info("Storage backend added")
+	},
+}
+
+var listStorageBackendsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List storage backends in a colony",
+	Long:  "List storage backends in a colony",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		backends, err := client.GetStorageBackends(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		if len(backends) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No storage backends found")
+			os.Exit(0)
+		}
+
+		printStorageBackendsTable(backends)
+	},
+}
+
+var removeStorageBackendCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a storage backend from a colony",
+	Long:  "Remove a storage backend from a colony, objects in the bucket are not removed",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		fmt.Print("WARNING!!! Are you sure you want to remove storage backend <" + StorageBackendName + "> in colony <" + ColonyName + ">? Files stored in the backend can no longer be downloaded. (YES,no): ")
+
+		reader := bufio.NewReader(os.Stdin)
+		reply, _ := reader.ReadString('\n')
+		if reply == "YES\n" {
+			err := client.RemoveStorageBackend(ColonyName, StorageBackendName, ColonyPrvKey)
+			CheckError(err)
+
+			log.WithFields(log.Fields{
+				"ColonyName":  ColonyName,
+				"BackendName": StorageBackendName}).
+				Info("Storage backend removed")
+		} else {
+			fmt.Println("Aborting ...")
+		}
+	},
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func printStorageBackendsTable(backends []*core.StorageBackend) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "Name", Name: "Name", SortIndex: 1},
+		{ID: "Protocol", Name: "Protocol", SortIndex: 2},
+		{ID: "Server", Name: "Server", SortIndex: 3},
+		{ID: "Bucket", Name: "Bucket", SortIndex: 4},
+		{ID: "TLS", Name: "TLS", SortIndex: 5},
+	}
+	t.SetCols(cols)
+
+	for _, backend := range backends {
+		row := []interface{}{
+			termenv.String(backend.Name).Foreground(theme.ColorCyan),
+			termenv.String(backend.Protocol).Foreground(theme.ColorMagenta),
+			termenv.String(backend.Server).Foreground(theme.ColorViolet),
+			termenv.String(backend.Bucket).Foreground(theme.ColorViolet),
+			termenv.String(strconv.FormatBool(backend.TLS)).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	webhookCmd.AddCommand(addWebhookCmd)
+	webhookCmd.AddCommand(listWebhooksCmd)
+	webhookCmd.AddCommand(getWebhookCmd)
+	webhookCmd.AddCommand(removeWebhookCmd)
+	webhookCmd.AddCommand(listWebhookDeliveriesCmd)
+	rootCmd.AddCommand(webhookCmd)
+
+	webhookCmd.PersistentFlags().StringVarP(&ServerHost, "host", "", DefaultServerHost, "Server host")
+	webhookCmd.PersistentFlags().IntVarP(&ServerPort, "port", "", -1, "Server HTTP port")
+
+	addWebhookCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	addWebhookCmd.Flags().StringVarP(&WebhookName, "name", "", "", "Webhook name")
+	addWebhookCmd.MarkFlagRequired("name")
+	addWebhookCmd.Flags().StringVarP(&WebhookURL, "url", "", "", "Endpoint URL events are POSTed to")
+	addWebhookCmd.MarkFlagRequired("url")
+	addWebhookCmd.Flags().StringVarP(&WebhookSecret, "secret", "", "", "Secret used to sign events, signature is sent in the X-Colonies-Signature header")
+	addWebhookCmd.Flags().StringSliceVarP(&WebhookKinds, "kinds", "", []string{}, "Only notify on these kinds, process and/or processgraph")
+	addWebhookCmd.Flags().StringSliceVarP(&WebhookStates, "states", "", []string{}, "Only notify on these states, successful, failed and/or cancelled")
+	addWebhookCmd.Flags().StringSliceVarP(&WebhookExecutorTypes, "executortypes", "", []string{}, "Only notify on processes with these executor types")
+	addWebhookCmd.Flags().StringSliceVarP(&WebhookLabels, "labels", "", []string{}, "Only notify on processes with these labels")
+	addWebhookCmd.Flags().StringVarP(&WorkflowID, "processgraphid", "", "", "Only notify on this processgraph and its processes")
+	addWebhookCmd.Flags().IntVarP(&MaxRetries, "maxretries", "", 0, "Number of retries if delivery fails, 0 means server default")
+
+	listWebhooksCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+
+	getWebhookCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	getWebhookCmd.Flags().StringVarP(&WebhookName, "name", "", "", "Webhook name")
+	getWebhookCmd.MarkFlagRequired("name")
+
+	removeWebhookCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	removeWebhookCmd.Flags().StringVarP(&WebhookName, "name", "", "", "Webhook name")
+	removeWebhookCmd.MarkFlagRequired("name")
+
+	listWebhookDeliveriesCmd.Flags().StringVarP(&ColonyPrvKey, "colonyprvkey", "", "", "Colony private key")
+	listWebhookDeliveriesCmd.Flags().StringVarP(&WebhookName, "name", "", "", "Webhook name")
+	listWebhookDeliveriesCmd.MarkFlagRequired("name")
+	listWebhookDeliveriesCmd.Flags().IntVarP(&Count, "count", "", 20, "Number of deliveries to list")
+}
+
+func parseWebhookStates(states []string) ([]int, error) {
+	parsedStates := make([]int, 0, len(states))
+	for _, state := range states {
+		switch strings.ToLower(strings.TrimSpace(state)) {
+		case "successful", "success":
+			parsedStates = append(parsedStates, core.SUCCESS)
+		case "failed":
+			parsedStates = append(parsedStates, core.FAILED)
+		case "cancelled", "canceled":
+			parsedStates = append(parsedStates, core.CANCELLED)
+		default:
+			return nil, errors.New("Invalid state <" + state + ">, must be successful, failed or cancelled")
+		}
+	}
+
+	return parsedStates, nil
+}
+
+func requireColonyPrvKey() {
+	if ColonyPrvKey == "" {
+		CheckError(errors.New("You must specify a Colony private key by exporting COLONIES_COLONY_PRVKEY"))
+	}
+}
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage webhooks",
+	Long:  "Manage webhooks notified when processes and workflows are closed",
+}
+
+var addWebhookCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new webhook",
+	Long:  "Add a new webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		for _, kind := range WebhookKinds {
+			if kind != core.WebhookKindProcess && kind != core.WebhookKindProcessGraph {
+				CheckError(errors.New("Invalid kind <" + kind + ">, must be " + core.WebhookKindProcess + " or " + core.WebhookKindProcessGraph))
+			}
+		}
+
+		states, err := parseWebhookStates(WebhookStates)
+		CheckError(err)
+
+		webhook := core.CreateWebhook(ColonyName, WebhookName, WebhookURL, WebhookSecret)
+		webhook.Kinds = WebhookKinds
+		webhook.States = states
+		webhook.ExecutorTypes = WebhookExecutorTypes
+		webhook.Labels = WebhookLabels
+		webhook.ProcessGraphID = WorkflowID
+		webhook.MaxRetries = MaxRetries
+
+		addedWebhook, err := client.AddWebhook(webhook, ColonyPrvKey)
+		CheckError(err)
+
+		log.WithFields(log.Fields{
+			"ColonyName": ColonyName,
+			"Name":       addedWebhook.Name,
+			"WebhookID":  addedWebhook.ID,
+			"URL":        addedWebhook.URL}).
+			Info("Webhook added")
+	},
+}
+
+var listWebhooksCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List webhooks in a colony",
+	Long:  "List webhooks in a colony",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		webhooks, err := client.GetWebhooks(ColonyName, ColonyPrvKey)
+		CheckError(err)
+
+		if len(webhooks) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName}).Info("No webhooks found")
+			os.Exit(0)
+		}
+
+		printWebhooksTable(webhooks)
+	},
+}
+
+var getWebhookCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get info about a webhook",
+	Long:  "Get info about a webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		webhook, err := client.GetWebhook(ColonyName, WebhookName, ColonyPrvKey)
+		CheckError(err)
+
+		if webhook == nil {
+			CheckError(errors.New("Webhook not found"))
+		}
+
+		printWebhookTable(webhook)
+	},
+}
+
+var removeWebhookCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a webhook from a colony",
+	Long:  "Remove a webhook from a colony",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		fmt.Print("WARNING!!! Are you sure you want to remove webhook <" + WebhookName + "> in colony <" + ColonyName + ">? (YES,no): ")
+
+		reader := bufio.NewReader(os.Stdin)
+		reply, _ := reader.ReadString('\n')
+		if reply == "YES\n" {
+			err := client.RemoveWebhook(ColonyName, WebhookName, ColonyPrvKey)
+			CheckError(err)
+
+			log.WithFields(log.Fields{
+				"ColonyName":  ColonyName,
+				"WebhookName": WebhookName}).
+				Info("Webhook removed")
+		} else {
+			fmt.Println("Aborting ...")
+		}
+	},
+}
+
+var listWebhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List recent deliveries of a webhook",
+	Long:  "List recent deliveries of a webhook",
+	Run: func(cmd *cobra.Command, args []string) {
+		client := setup()
+		requireColonyPrvKey()
+
+		deliveries, err := client.GetWebhookDeliveries(ColonyName, WebhookName, Count, ColonyPrvKey)
+		CheckError(err)
+
+		if len(deliveries) == 0 {
+			log.WithFields(log.Fields{"ColonyName": ColonyName, "WebhookName": WebhookName}).Info("No deliveries found")
+			os.Exit(0)
+		}
+
+		printWebhookDeliveriesTable(deliveries)
+	},
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/colonyos/colonies/internal/table"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/muesli/termenv"
+)
+
+func webhookStatesToString(states []int) string {
+	if len(states) == 0 {
+		return "*"
+	}
+
+	strs := make([]string, 0, len(states))
+	for _, state := range states {
+		strs = append(strs, State2String(state))
+	}
+
+	return strings.Join(strs, ",")
+}
+
+func webhookFilterToString(values []string) string {
+	if len(values) == 0 {
+		return "*"
+	}
+
+	return strings.Join(values, ",")
+}
+
+func printWebhookTable(webhook *core.Webhook) {
+	t, theme := createTable(1)
+
+	addRow := func(key string, value string) {
+		row := []interface{}{
+			termenv.String(key).Foreground(theme.ColorCyan),
+			termenv.String(value).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	maxRetries := "default"
+	if webhook.MaxRetries > 0 {
+		maxRetries = strconv.Itoa(webhook.MaxRetries)
+	}
+
+	processGraphID := webhook.ProcessGraphID
+	if processGraphID == "" {
+		processGraphID = "*"
+	}
+
+	addRow("Name", webhook.Name)
+	addRow("WebhookID", webhook.ID)
+	addRow("Colony", webhook.ColonyName)
+	addRow("URL", webhook.URL)
+	addRow("Kinds", webhookFilterToString(webhook.Kinds))
+	addRow("States", webhookStatesToString(webhook.States))
+	addRow("ExecutorTypes", webhookFilterToString(webhook.ExecutorTypes))
+	addRow("Labels", webhookFilterToString(webhook.Labels))
+	addRow("ProcessGraphID", processGraphID)
+	addRow("MaxRetries", maxRetries)
+	addRow("Added", webhook.Added.Format(TimeLayout))
+
+	t.Render()
+}
+
+func printWebhooksTable(webhooks []*core.Webhook) {
+	t, theme := createTable(1)
+
+	var cols = []table.Column{
+		{ID: "Name", Name: "Name", SortIndex: 1},
+		{ID: "URL", Name: "URL", SortIndex: 2},
+		{ID: "Kinds", Name: "Kinds", SortIndex: 3},
+		{ID: "States", Name: "States", SortIndex: 4},
+	}
+	t.SetCols(cols)
+
+	for _, webhook := range webhooks {
+		row := []interface{}{
+			termenv.String(webhook.Name).Foreground(theme.ColorCyan),
+			termenv.String(webhook.URL).Foreground(theme.ColorViolet),
+			termenv.String(webhookFilterToString(webhook.Kinds)).Foreground(theme.ColorMagenta),
+			termenv.String(webhookStatesToString(webhook.States)).Foreground(theme.ColorMagenta),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
+
+func printWebhookDeliveriesTable(deliveries []*core.WebhookDelivery) {
+	t, theme := createTable(0)
+
+	var cols = []table.Column{
+		{ID: "Time", Name: "Time", SortIndex: 1},
+		{ID: "Event", Name: "Event", SortIndex: 2},
+		{ID: "Status", Name: "Status", SortIndex: 3},
+		{ID: "Code", Name: "Code", SortIndex: 4},
+		{ID: "Attempts", Name: "Attempts", SortIndex: 5},
+		{ID: "Error", Name: "Error", SortIndex: 6},
+	}
+	t.SetCols(cols)
+
+	for _, delivery := range deliveries {
+		status := termenv.String("Delivered").Foreground(theme.ColorGreen)
+		if !delivery.Success {
+			status = termenv.String("Failed").Foreground(theme.ColorRed)
+		}
+
+		row := []interface{}{
+			termenv.String(delivery.LastAttempt.Format(TimeLayout)).Foreground(theme.ColorGray),
+			termenv.String(delivery.EventType).Foreground(theme.ColorCyan),
+			status,
+			termenv.String(strconv.Itoa(delivery.StatusCode)).Foreground(theme.ColorMagenta),
+			termenv.String(strconv.Itoa(delivery.Attempts)).Foreground(theme.ColorMagenta),
+			termenv.String(delivery.Error).Foreground(theme.ColorGray),
+		}
+		t.AddRow(row)
+	}
+
+	t.Render()
+}
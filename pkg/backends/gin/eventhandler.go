@@ -128,7 +128,8 @@ func (handler *DefaultEventHandler) relayListener() {
 		select {
 		case msg := <-handler.relayChan:
 			process, err := core.ConvertJSONToProcess(string(msg.Data))
-			if err != nil {
+			if err != nil || process == nil || process.ID == "" {
+				// Other messages, e.g. log entries, are relayed through the same server
 				log.WithFields(log.Fields{"Error": err}).Debug("relayListener received non-process message, ignoring")
 			} else {
 				handler.signalNoRelay(process)
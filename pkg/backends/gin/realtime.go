@@ -11,6 +11,7 @@ import (
 	"github.com/colonyos/colonies/pkg/channel"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/gorilla/websocket"
@@ -25,6 +26,10 @@ type RealtimeServer interface {
 	WSController() WSController
 	ChannelRouter() *channel.Router
 	ProcessDB() database.ProcessDatabase
+	ExecutorDB() database.ExecutorDatabase
+	ProcessGraphDB() database.ProcessGraphDatabase
+	LogDB() database.LogDatabase
+	LogBroker() *logstream.Broker
 	Validator() security.Validator
 }
 
@@ -114,6 +119,11 @@ func (h *RealtimeHandler) HandleWSRequest(c backends.Context) {
 			h.handleSubscribeProcess(c, rpcMsg, recoveredID, wsConn, wsMsgType)
 		case rpc.SubscribeChannelPayloadType:
 			h.handleSubscribeChannel(c, rpcMsg, recoveredID, wsConn, wsMsgType)
+		case rpc.SubscribeLogsPayloadType:
+			// The log subscription reads the connection to detect when the client disconnects, and the
+			// client closes the connection when the subscription ends, so no more messages are read
+			h.handleSubscribeLogs(c, rpcMsg, recoveredID, wsConn, wsMsgType)
+			return
 		}
 	}
 }
@@ -308,6 +318,75 @@ func (h *RealtimeHandler) handleSubscribeChannel(c backends.Context, rpcMsg *rpc
 	}
 }
 
+func (h *RealtimeHandler) handleSubscribeLogs(c backends.Context, rpcMsg *rpc.RPCMsg, recoveredID string, wsConn *websocket.Conn, wsMsgType int) {
+	msg, err := rpc.CreateSubscribeLogsMsgFromJSON(rpcMsg.DecodePayload())
+	if err != nil {
+		err := h.sendWSErrorMsg(err, http.StatusBadRequest, wsConn, wsMsgType)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, failed to send error message")
+		}
+		return
+	}
+
+	if msg.MsgType != rpcMsg.PayloadType {
+		err := h.sendWSErrorMsg(errors.New("Failed to subscribe to logs, msg.MsgType does not match rpcMsg.PayloadType"), http.StatusBadRequest, wsConn, wsMsgType)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, failed to send error message")
+		}
+		return
+	}
+
+	filter, errorCode, err := logstream.CreateFilter(msg, recoveredID, h.server.Validator(), h.server.ProcessDB(), h.server.ExecutorDB(), h.server.ProcessGraphDB())
+	if err != nil {
+		err := h.sendWSErrorMsg(err, errorCode, wsConn, wsMsgType)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to subscribe to logs, failed to send error message")
+		}
+		return
+	}
+
+	log.WithFields(log.Fields{"ProcessID": msg.ProcessID, "ExecutorName": msg.ExecutorName, "ProcessGraphID": msg.ProcessGraphID, "Timeout": msg.Timeout}).Debug("WebSocket log subscription started")
+
+	// The client does not send anything after subscribing, a failed read means that it has disconnected.
+	// The read is interrupted when the connection is closed after the subscription has ended.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = h.server.LogBroker().Follow(h.server.LogDB(), filter, time.Duration(msg.Timeout)*time.Second, done, func(logs []*core.Log) error {
+		return h.sendLogs(logs, wsConn, wsMsgType)
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Debug("WebSocket log subscription ended")
+	}
+}
+
+// sendLogs sends log entries to a WebSocket connection
+func (h *RealtimeHandler) sendLogs(logs []*core.Log, wsConn *websocket.Conn, wsMsgType int) error {
+	jsonString, err := core.ConvertLogArrayToJSON(logs)
+	if err != nil {
+		return err
+	}
+
+	replyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeLogsPayloadType, jsonString)
+	if err != nil {
+		return err
+	}
+
+	jsonString, err = replyMsg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	return wsConn.WriteMessage(wsMsgType, []byte(jsonString))
+}
+
 // ensureChannelExists creates a channel on demand if it's defined in the process spec
 // but doesn't exist locally. This handles cluster scenarios where a client connects
 // to a different server than where the process was originally submitted.
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the gRPC content-subtype used by the Colonies service, i.e. requests
+// are sent with content-type application/grpc+colonies
+const CodecName = "colonies"
+
+// MaxMessageSize is the largest envelope accepted by the client and the server
+const MaxMessageSize = 64 * 1024 * 1024
+
+// Envelope carries a serialized RPCMsg or RPCReplyMsg. The codec writes the JSON as is
+// on the wire, so no protobuf definitions are needed and the payload is identical to
+// the body of a POST /api request to the gin backend.
+type Envelope struct {
+	Data []byte
+}
+
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	envelope, ok := v.(*Envelope)
+	if !ok {
+		return nil, fmt.Errorf("colonies codec cannot marshal %T", v)
+	}
+
+	return envelope.Data, nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	envelope, ok := v.(*Envelope)
+	if !ok {
+		return fmt.Errorf("colonies codec cannot unmarshal into %T", v)
+	}
+
+	// The buffer is owned by gRPC and may be reused once Unmarshal returns
+	envelope.Data = append([]byte(nil), data...)
+
+	return nil
+}
+
+func (codec) Name() string {
+	return CodecName
+}
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
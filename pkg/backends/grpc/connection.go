@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/colonyos/colonies/pkg/backends"
+)
+
+// TextMessage is used as message type for subscriptions, it is ignored by the stream
+// but keeps RealtimeSubscriptions compatible with the websocket implementation
+const TextMessage = 1
+
+var ErrConnectionClosed = errors.New("grpc stream is closed")
+
+// StreamConnection implements backends.RealtimeConnection on top of a Subscribe stream.
+// Messages written to the connection are sent as envelopes, closing the connection
+// ends the stream.
+type StreamConnection struct {
+	stream SubscribeStream
+	mutex  sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewStreamConnection creates a new StreamConnection
+func NewStreamConnection(stream SubscribeStream) *StreamConnection {
+	return &StreamConnection{stream: stream, done: make(chan struct{})}
+}
+
+// WriteMessage implements backends.RealtimeConnection
+func (conn *StreamConnection) WriteMessage(msgType int, data []byte) error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if conn.closed {
+		return ErrConnectionClosed
+	}
+
+	return conn.stream.Send(&Envelope{Data: data})
+}
+
+// Close implements backends.RealtimeConnection
+func (conn *StreamConnection) Close() error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if !conn.closed {
+		conn.closed = true
+		close(conn.done)
+	}
+
+	return nil
+}
+
+// IsOpen implements backends.RealtimeConnection
+func (conn *StreamConnection) IsOpen() bool {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	return !conn.closed
+}
+
+// Done returns a channel that is closed when the connection is closed
+func (conn *StreamConnection) Done() <-chan struct{} {
+	return conn.done
+}
+
+// CloseOnCancel blocks until the connection is closed, the connection is closed if the
+// client cancels the stream
+func (conn *StreamConnection) CloseOnCancel() {
+	select {
+	case <-conn.done:
+	case <-conn.stream.Context().Done():
+		conn.Close()
+	}
+}
+
+var _ backends.RealtimeConnection = (*StreamConnection)(nil)
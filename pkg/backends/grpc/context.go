@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Context implements backends.Context for a single unary gRPC call. The reply written
+// by a handler is captured so that it can be returned as the response envelope, this
+// allows the handler registry to be shared with the gin backend.
+type Context struct {
+	request    *http.Request
+	body       []byte
+	statusCode int
+	response   []byte
+	headers    http.Header
+	keys       map[string]interface{}
+	aborted    bool
+}
+
+// NewContext creates a new Context for a request envelope
+func NewContext(ctx context.Context, body []byte) *Context {
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/api", bytes.NewReader(body))
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			for _, value := range values {
+				request.Header.Add(key, value)
+			}
+		}
+	}
+
+	return &Context{
+		request:    request,
+		body:       body,
+		statusCode: http.StatusOK,
+		headers:    make(http.Header),
+		keys:       make(map[string]interface{}),
+	}
+}
+
+// String writes a string response with the given status code
+func (c *Context) String(code int, format string, values ...interface{}) {
+	c.statusCode = code
+	if len(values) > 0 {
+		c.response = []byte(fmt.Sprintf(format, values...))
+	} else {
+		c.response = []byte(format)
+	}
+}
+
+// JSON serializes the given struct as JSON into the response
+func (c *Context) JSON(code int, obj interface{}) {
+	c.statusCode = code
+	c.response, _ = json.Marshal(obj)
+}
+
+// XML serializes the given struct as XML into the response
+func (c *Context) XML(code int, obj interface{}) {
+	c.statusCode = code
+	c.response, _ = xml.Marshal(obj)
+}
+
+// Data writes raw data into the response
+func (c *Context) Data(code int, contentType string, data []byte) {
+	c.statusCode = code
+	c.headers.Set("Content-Type", contentType)
+	c.response = data
+}
+
+// Status sets the response code
+func (c *Context) Status(code int) {
+	c.statusCode = code
+}
+
+// Request returns a synthetic http.Request carrying the context and metadata of the call
+func (c *Context) Request() *http.Request {
+	return c.request
+}
+
+// ReadBody returns the request envelope
+func (c *Context) ReadBody() ([]byte, error) {
+	return c.body, nil
+}
+
+// GetHeader returns a value from the incoming gRPC metadata
+func (c *Context) GetHeader(key string) string {
+	return c.request.Header.Get(key)
+}
+
+// Header sets a response header, headers are not sent to gRPC clients
+func (c *Context) Header(key, value string) {
+	c.headers.Set(key, value)
+}
+
+// Param always returns an empty string, gRPC calls have no URL parameters
+func (c *Context) Param(key string) string {
+	return ""
+}
+
+// Query always returns an empty string, gRPC calls have no query strings
+func (c *Context) Query(key string) string {
+	return ""
+}
+
+// DefaultQuery always returns the default value
+func (c *Context) DefaultQuery(key, defaultValue string) string {
+	return defaultValue
+}
+
+// PostForm always returns an empty string, gRPC calls have no form data
+func (c *Context) PostForm(key string) string {
+	return ""
+}
+
+// DefaultPostForm always returns the default value
+func (c *Context) DefaultPostForm(key, defaultValue string) string {
+	return defaultValue
+}
+
+// Bind decodes the request envelope as JSON
+func (c *Context) Bind(obj interface{}) error {
+	return c.BindJSON(obj)
+}
+
+// ShouldBind decodes the request envelope as JSON
+func (c *Context) ShouldBind(obj interface{}) error {
+	return c.BindJSON(obj)
+}
+
+// BindJSON decodes the request envelope as JSON
+func (c *Context) BindJSON(obj interface{}) error {
+	return json.Unmarshal(c.body, obj)
+}
+
+// ShouldBindJSON decodes the request envelope as JSON
+func (c *Context) ShouldBindJSON(obj interface{}) error {
+	return c.BindJSON(obj)
+}
+
+// Set stores a key/value pair for the lifetime of the call
+func (c *Context) Set(key string, value interface{}) {
+	c.keys[key] = value
+}
+
+// Get returns the value for the given key
+func (c *Context) Get(key string) (value interface{}, exists bool) {
+	value, exists = c.keys[key]
+	return
+}
+
+// GetString returns the value for the given key as a string
+func (c *Context) GetString(key string) (s string) {
+	if value, ok := c.keys[key]; ok && value != nil {
+		s, _ = value.(string)
+	}
+	return
+}
+
+// GetBool returns the value for the given key as a boolean
+func (c *Context) GetBool(key string) (b bool) {
+	if value, ok := c.keys[key]; ok && value != nil {
+		b, _ = value.(bool)
+	}
+	return
+}
+
+// GetInt returns the value for the given key as an integer
+func (c *Context) GetInt(key string) (i int) {
+	if value, ok := c.keys[key]; ok && value != nil {
+		i, _ = value.(int)
+	}
+	return
+}
+
+// GetInt64 returns the value for the given key as an int64
+func (c *Context) GetInt64(key string) (i64 int64) {
+	if value, ok := c.keys[key]; ok && value != nil {
+		i64, _ = value.(int64)
+	}
+	return
+}
+
+// GetFloat64 returns the value for the given key as a float64
+func (c *Context) GetFloat64(key string) (f64 float64) {
+	if value, ok := c.keys[key]; ok && value != nil {
+		f64, _ = value.(float64)
+	}
+	return
+}
+
+// Abort marks the call as aborted
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// AbortWithStatus marks the call as aborted and sets the response code
+func (c *Context) AbortWithStatus(code int) {
+	c.statusCode = code
+	c.Abort()
+}
+
+// AbortWithStatusJSON marks the call as aborted and writes a JSON response
+func (c *Context) AbortWithStatusJSON(code int, jsonObj interface{}) {
+	c.JSON(code, jsonObj)
+	c.Abort()
+}
+
+// IsAborted returns true if the call was aborted
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// Next is a no-op, gRPC calls are not routed through middleware
+func (c *Context) Next() {
+}
+
+// StatusCode returns the response code written by the handler
+func (c *Context) StatusCode() int {
+	return c.statusCode
+}
+
+// Response returns the response written by the handler
+func (c *Context) Response() []byte {
+	return c.response
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+)
+
+type streamMock struct {
+	ctx  context.Context
+	sent [][]byte
+}
+
+func (s *streamMock) Send(envelope *Envelope) error {
+	s.sent = append(s.sent, envelope.Data)
+	return nil
+}
+
+func (s *streamMock) Context() context.Context {
+	return s.ctx
+}
+
+func TestCodec(t *testing.T) {
+	c := encoding.GetCodec(CodecName)
+	assert.NotNil(t, c)
+
+	data, err := c.Marshal(&Envelope{Data: []byte(`{"payloadtype":"versionmsg"}`)})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"payloadtype":"versionmsg"}`, string(data))
+
+	envelope := &Envelope{}
+	assert.Nil(t, c.Unmarshal(data, envelope))
+	assert.Equal(t, data, envelope.Data)
+
+	// The decoded envelope must not share memory with the gRPC buffer
+	data[0] = 'x'
+	assert.Equal(t, byte('{'), envelope.Data[0])
+
+	_, err = c.Marshal("invalid")
+	assert.NotNil(t, err)
+	assert.NotNil(t, c.Unmarshal(data, "invalid"))
+}
+
+func TestContext(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-test", "value"))
+	c := NewContext(ctx, []byte(`{"name":"test"}`))
+
+	body, err := c.ReadBody()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"test"}`, string(body))
+	assert.Equal(t, "value", c.GetHeader("X-Test"))
+	assert.Equal(t, ctx, c.Request().Context())
+
+	var obj struct {
+		Name string `json:"name"`
+	}
+	assert.Nil(t, c.BindJSON(&obj))
+	assert.Equal(t, "test", obj.Name)
+
+	c.Set("key", "value")
+	assert.Equal(t, "value", c.GetString("key"))
+	assert.Equal(t, 0, c.GetInt("key"))
+	assert.Equal(t, "default", c.DefaultQuery("key", "default"))
+
+	assert.Equal(t, http.StatusOK, c.StatusCode())
+	c.String(http.StatusForbidden, `{"error":true}`)
+	assert.Equal(t, http.StatusForbidden, c.StatusCode())
+	assert.Equal(t, `{"error":true}`, string(c.Response()))
+
+	c.String(http.StatusOK, "%d%%", 100)
+	assert.Equal(t, "100%", string(c.Response()))
+
+	assert.False(t, c.IsAborted())
+	c.AbortWithStatus(http.StatusBadRequest)
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusBadRequest, c.StatusCode())
+}
+
+func TestStreamConnection(t *testing.T) {
+	stream := &streamMock{ctx: context.Background()}
+	conn := NewStreamConnection(stream)
+
+	assert.True(t, conn.IsOpen())
+	assert.Nil(t, conn.WriteMessage(TextMessage, []byte("msg")))
+	assert.Len(t, stream.sent, 1)
+
+	assert.Nil(t, conn.Close())
+	assert.Nil(t, conn.Close())
+	assert.False(t, conn.IsOpen())
+	assert.Equal(t, ErrConnectionClosed, conn.WriteMessage(TextMessage, []byte("msg")))
+	assert.Len(t, stream.sent, 1)
+
+	<-conn.Done()
+}
+
+func TestStreamConnectionCloseOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := NewStreamConnection(&streamMock{ctx: ctx})
+
+	done := make(chan struct{})
+	go func() {
+		conn.CloseOnCancel()
+		close(done)
+	}()
+
+	cancel()
+	<-done
+	assert.False(t, conn.IsOpen())
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Server serves the Colonies gRPC service
+type Server struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+// NewServer creates a new gRPC server for the given service, if tls is true the
+// certificate and key are loaded from disk
+func NewServer(port int, service ColoniesServer, tls bool, tlsCertPath string, tlsPrivateKeyPath string) (*Server, error) {
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(MaxMessageSize),
+		grpc.MaxSendMsgSize(MaxMessageSize),
+	}
+
+	if tls {
+		creds, err := credentials.NewServerTLSFromFile(tlsCertPath, tlsPrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	grpcServer.RegisterService(&ServiceDesc, service)
+
+	return &Server{grpcServer: grpcServer, addr: ":" + strconv.Itoa(port)}, nil
+}
+
+// ListenAndServe listens on the configured port and serves until Shutdown is called
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve serves on an existing listener until Shutdown is called
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown stops the server gracefully, open subscription streams are closed
+// if they have not finished when the context expires
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// GetAddr returns the address the server listens on
+func (s *Server) GetAddr() string {
+	return s.addr
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	ServiceName       = "colonies.Colonies"
+	SendMessageMethod = "/" + ServiceName + "/SendMessage"
+	HealthMethod      = "/" + ServiceName + "/Health"
+	SubscribeMethod   = "/" + ServiceName + "/Subscribe"
+)
+
+// ColoniesServer is implemented by the server side of the Colonies gRPC service
+type ColoniesServer interface {
+	// SendMessage handles a RPCMsg and replies with a RPCReplyMsg, same as POST /api
+	SendMessage(ctx context.Context, request *Envelope) (*Envelope, error)
+	// Health replies with an empty envelope if the server is up, same as GET /health
+	Health(ctx context.Context, request *Envelope) (*Envelope, error)
+	// Subscribe handles a subscribe RPCMsg and streams RPCReplyMsgs, same as /pubsub
+	Subscribe(request *Envelope, stream SubscribeStream) error
+}
+
+// SubscribeStream is the server side of a Subscribe call
+type SubscribeStream interface {
+	Send(envelope *Envelope) error
+	Context() context.Context
+}
+
+type subscribeStream struct {
+	grpc.ServerStream
+}
+
+func (stream *subscribeStream) Send(envelope *Envelope) error {
+	return stream.ServerStream.SendMsg(envelope)
+}
+
+// SubscribeStreamDesc describes the server-streaming Subscribe call, it is used by clients to open streams
+var SubscribeStreamDesc = grpc.StreamDesc{
+	StreamName:    "Subscribe",
+	Handler:       subscribeHandler,
+	ServerStreams: true,
+}
+
+// ServiceDesc is the hand-written equivalent of a protoc generated service descriptor
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*ColoniesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "SendMessage", Handler: sendMessageHandler},
+		{MethodName: "Health", Handler: healthHandler},
+	},
+	Streams: []grpc.StreamDesc{SubscribeStreamDesc},
+}
+
+func sendMessageHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	request := new(Envelope)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ColoniesServer).SendMessage(ctx, request)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: SendMessageMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ColoniesServer).SendMessage(ctx, req.(*Envelope))
+	}
+
+	return interceptor(ctx, request, info, handler)
+}
+
+func healthHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	request := new(Envelope)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ColoniesServer).Health(ctx, request)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: HealthMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(ColoniesServer).Health(ctx, req.(*Envelope))
+	}
+
+	return interceptor(ctx, request, info, handler)
+}
+
+func subscribeHandler(srv any, stream grpc.ServerStream) error {
+	request := new(Envelope)
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+
+	return srv.(ColoniesServer).Subscribe(request, &subscribeStream{stream})
+}
@@ -3,8 +3,13 @@ package backends
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +49,9 @@ func (m *MockClientBackend) Close() error {
 	return nil
 }
 
+// dialError is returned by a backend when the server cannot be reached, before the request is sent
+var dialError = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
 // MockClientBackendFactory implements ClientBackendFactory for testing
 type MockClientBackendFactory struct {
 	backend     ClientBackend
@@ -157,7 +165,7 @@ func TestMultiBackendClientSendRawMessageSuccess(t *testing.T) {
 func TestMultiBackendClientSendRawMessageFallback(t *testing.T) {
 	failingBackend := &MockClientBackend{
 		sendRawMessageFunc: func(jsonString string, insecure bool) (string, error) {
-			return "", errors.New("primary failed")
+			return "", dialError
 		},
 	}
 	successBackend := &MockClientBackend{
@@ -183,7 +191,7 @@ func TestMultiBackendClientSendRawMessageFallback(t *testing.T) {
 func TestMultiBackendClientSendRawMessageAllFail(t *testing.T) {
 	failingBackend := &MockClientBackend{
 		sendRawMessageFunc: func(jsonString string, insecure bool) (string, error) {
-			return "", errors.New("backend failed")
+			return "", dialError
 		},
 	}
 
@@ -217,7 +225,7 @@ func TestMultiBackendClientSendMessageSuccess(t *testing.T) {
 func TestMultiBackendClientSendMessageFallback(t *testing.T) {
 	failingBackend := &MockClientBackend{
 		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
-			return "", errors.New("primary failed")
+			return "", dialError
 		},
 	}
 	successBackend := &MockClientBackend{
@@ -242,7 +250,7 @@ func TestMultiBackendClientSendMessageFallback(t *testing.T) {
 func TestMultiBackendClientSendMessageAllFail(t *testing.T) {
 	failingBackend := &MockClientBackend{
 		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
-			return "", errors.New("send failed")
+			return "", dialError
 		},
 	}
 
@@ -338,6 +346,310 @@ func TestMultiBackendClientCloseWithErrors(t *testing.T) {
 	assert.Contains(t, err.Error(), "errors closing backends")
 }
 
+func TestMultiBackendClientSendMessageNoFailoverOnServerError(t *testing.T) {
+	calls := 0
+	serverErrorBackend := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			return "", &core.ColoniesError{Status: 404, Message: "not found"}
+		},
+	}
+	otherBackend := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			calls++
+			return `{}`, nil
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{serverErrorBackend, otherBackend},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}},
+	}
+
+	_, err := client.SendMessage("getprocessmsg", `{}`, "key", false, context.Background())
+	assert.Error(t, err)
+	coloniesErr, ok := err.(*core.ColoniesError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, coloniesErr.Status)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMultiBackendClientHealthAwareOrder(t *testing.T) {
+	primaryCalls := 0
+	primary := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			primaryCalls++
+			return "", dialError
+		},
+	}
+	secondary := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			return `{"node": 2}`, nil
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{primary, secondary},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}},
+	}
+
+	_, err := client.SendMessage("submitfuncspecmsg", `{}`, "key", false, context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, primaryCalls)
+	assert.False(t, client.IsHealthy(0))
+	assert.True(t, client.IsHealthy(1))
+
+	// The unhealthy primary is skipped while in cooldown
+	result, err := client.SendMessage("submitfuncspecmsg", `{}`, "key", false, context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"node": 2}`, result)
+	assert.Equal(t, 1, primaryCalls)
+	assert.Equal(t, []int{1, 0}, client.order())
+}
+
+func TestMultiBackendClientUnhealthyCooldownExpires(t *testing.T) {
+	client := &MultiBackendClient{
+		backends: []ClientBackend{&MockClientBackend{}, &MockClientBackend{}},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}},
+	}
+	client.SetUnhealthyCooldown(10 * time.Millisecond)
+
+	client.markFailure(0)
+	assert.Equal(t, []int{1, 0}, client.order())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []int{0, 1}, client.order())
+}
+
+func TestMultiBackendClientRetryIdempotent(t *testing.T) {
+	calls := 0
+	flakyBackend := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", errors.New("connection reset")
+			}
+			return `{"ok": true}`, nil
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{flakyBackend},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}},
+	}
+	client.SetRetryPolicy(&RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	result, err := client.SendMessage("getprocessmsg", `{}`, "key", false, context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok": true}`, result)
+	assert.Equal(t, 3, calls)
+}
+
+func TestMultiBackendClientNoRetryNonIdempotent(t *testing.T) {
+	calls := 0
+	failingBackend := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			calls++
+			return "", errors.New("connection reset")
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{failingBackend},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}},
+	}
+	client.SetRetryPolicy(&RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond})
+
+	_, err := client.SendMessage("submitfuncspecmsg", `{}`, "key", false, context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMultiBackendClientNoFailoverNonIdempotent(t *testing.T) {
+	secondaryCalls := 0
+	primary := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			return "", context.DeadlineExceeded
+		},
+		sendRawMessageFunc: func(jsonString string, insecure bool) (string, error) {
+			return "", errors.New("connection reset by peer")
+		},
+	}
+	secondary := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			secondaryCalls++
+			return `{}`, nil
+		},
+		sendRawMessageFunc: func(jsonString string, insecure bool) (string, error) {
+			secondaryCalls++
+			return `{}`, nil
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{primary, secondary},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}},
+	}
+
+	// A submit that timed out may already have been applied and must not be sent again
+	_, err := client.SendMessage("submitfuncspecmsg", `{}`, "key", false, context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, secondaryCalls)
+
+	client.markSuccess(0)
+	_, err = client.SendRawMessage(`{"payloadtype":"assignprocessmsg"}`, false)
+	assert.Error(t, err)
+	assert.Equal(t, 0, secondaryCalls)
+
+	// Reads fail over
+	client.markSuccess(0)
+	_, err = client.SendMessage("getprocessmsg", `{}`, "key", false, context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, secondaryCalls)
+}
+
+func TestMultiBackendClientRetryContextCancelled(t *testing.T) {
+	failingBackend := &MockClientBackend{
+		sendMessageFunc: func(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+			return "", errors.New("connection reset")
+		},
+	}
+
+	client := &MultiBackendClient{
+		backends: []ClientBackend{failingBackend},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}},
+	}
+	client.SetRetryPolicy(&RetryPolicy{MaxRetries: 3, InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.SendMessage("getprocessmsg", `{}`, "key", false, ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMultiBackendClientAddEndpoints(t *testing.T) {
+	configs := []*ClientConfig{
+		{BackendType: GinClientBackendType, Host: "node1", Port: 50080},
+	}
+	factories := map[ClientBackendType]ClientBackendFactory{
+		GinClientBackendType: &MockClientBackendFactory{backend: &MockClientBackend{}},
+	}
+
+	client, err := NewMultiBackendClient(configs, factories)
+	assert.NoError(t, err)
+
+	added := client.AddEndpoints([]*ClientConfig{
+		{BackendType: GinClientBackendType, Host: "node1", Port: 50080},
+		{BackendType: GinClientBackendType, Host: "node2", Port: 50080},
+		{BackendType: GRPCClientBackendType, Host: "node3", Port: 50090},
+	})
+	assert.Equal(t, 1, added)
+
+	endpoints := client.Endpoints()
+	assert.Len(t, endpoints, 2)
+	assert.Equal(t, "node2", endpoints[1].Host)
+}
+
+type mockRealtimeConnection struct{}
+
+func (c *mockRealtimeConnection) WriteMessage(messageType int, data []byte) error { return nil }
+func (c *mockRealtimeConnection) ReadMessage() (int, []byte, error)               { return TextMessage, nil, nil }
+func (c *mockRealtimeConnection) Close() error                                    { return nil }
+func (c *mockRealtimeConnection) SetReadLimit(limit int64)                        {}
+
+type mockRealtimeBackend struct {
+	MockClientBackend
+	err error
+}
+
+func (b *mockRealtimeBackend) EstablishRealtimeConn(jsonString string) (RealtimeConnection, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &mockRealtimeConnection{}, nil
+}
+
+func TestMultiBackendClientEstablishRealtimeConnFailover(t *testing.T) {
+	client := &MultiBackendClient{
+		backends: []ClientBackend{
+			&MockClientBackend{},
+			&mockRealtimeBackend{err: errors.New("connection refused")},
+			&mockRealtimeBackend{},
+		},
+		configs: []*ClientConfig{{BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}, {BackendType: GinClientBackendType}},
+	}
+
+	conn, err := client.EstablishRealtimeConn(`{}`)
+	assert.NoError(t, err)
+	assert.NotNil(t, conn)
+	assert.False(t, client.IsHealthy(1))
+
+	client = &MultiBackendClient{
+		backends: []ClientBackend{&MockClientBackend{}},
+		configs:  []*ClientConfig{{BackendType: GinClientBackendType}},
+	}
+	_, err = client.EstablishRealtimeConn(`{}`)
+	assert.Error(t, err)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(10))
+}
+
+func TestIsIdempotent(t *testing.T) {
+	assert.True(t, IsIdempotent("getprocessmsg"))
+	assert.True(t, IsIdempotent("versionmsg"))
+	assert.True(t, IsIdempotent("channelreadmsg"))
+	assert.False(t, IsIdempotent("submitfuncspecmsg"))
+	assert.False(t, IsIdempotent("assignprocessmsg"))
+}
+
+func TestIsPreSendError(t *testing.T) {
+	assert.False(t, IsPreSendError(nil))
+	assert.True(t, IsPreSendError(dialError))
+	assert.True(t, IsPreSendError(fmt.Errorf("post failed: %w", dialError)))
+	assert.True(t, IsPreSendError(&net.DNSError{Err: "no such host", Name: "node1"}))
+	assert.True(t, IsPreSendError(errors.New(`rpc error: code = Unavailable desc = connection error: desc = "transport: Error while dialing: dial tcp 127.0.0.1:50081: connect: connection refused"`)))
+	assert.False(t, IsPreSendError(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}))
+	assert.False(t, IsPreSendError(context.DeadlineExceeded))
+}
+
+func TestIsTransportError(t *testing.T) {
+	assert.False(t, IsTransportError(nil))
+	assert.True(t, IsTransportError(errors.New("connection refused")))
+	assert.False(t, IsTransportError(&core.ColoniesError{Status: 403, Message: "forbidden"}))
+}
+
+// ============== ParseEndpointsFromEnv tests ==============
+
+func TestParseEndpointsFromEnv(t *testing.T) {
+	template := CreateDefaultClientConfig("node1", 50080, true, false)
+
+	configs, err := ParseEndpointsFromEnv("", template)
+	assert.NoError(t, err)
+	assert.Len(t, configs, 0)
+
+	configs, err = ParseEndpointsFromEnv("node2:50081, node3:50082", template)
+	assert.NoError(t, err)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "node2", configs[0].Host)
+	assert.Equal(t, 50081, configs[0].Port)
+	assert.Equal(t, GinClientBackendType, configs[1].BackendType)
+	assert.True(t, configs[1].Insecure)
+
+	_, err = ParseEndpointsFromEnv("node2", template)
+	assert.Error(t, err)
+
+	_, err = ParseEndpointsFromEnv("node2:port", template)
+	assert.Error(t, err)
+}
+
 // ============== ParseClientBackendsFromEnv tests ==============
 
 func TestParseClientBackendsFromEnvEmpty(t *testing.T) {
@@ -365,6 +677,19 @@ func TestParseClientBackendsFromEnvMultiple(t *testing.T) {
 	assert.Equal(t, GinClientBackendType, backends[1])
 }
 
+func TestParseClientBackendsFromEnvGRPC(t *testing.T) {
+	backends := ParseClientBackendsFromEnv("grpc")
+	assert.Len(t, backends, 1)
+	assert.Equal(t, GRPCClientBackendType, backends[0])
+}
+
+func TestParseClientBackendsFromEnvGRPCWithFallback(t *testing.T) {
+	backends := ParseClientBackendsFromEnv("grpc,http")
+	assert.Len(t, backends, 2)
+	assert.Equal(t, GRPCClientBackendType, backends[0])
+	assert.Equal(t, GinClientBackendType, backends[1])
+}
+
 func TestParseClientBackendsFromEnvUnknown(t *testing.T) {
 	backends := ParseClientBackendsFromEnv("unknown")
 	// Should default to gin when no valid backends
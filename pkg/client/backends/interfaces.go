@@ -29,7 +29,8 @@ type ClientBackendWithRealtime interface {
 type ClientBackendType string
 
 const (
-	GinClientBackendType ClientBackendType = "gin"
+	GinClientBackendType  ClientBackendType = "gin"
+	GRPCClientBackendType ClientBackendType = "grpc"
 )
 
 // ClientBackendFactory creates backend-specific clients
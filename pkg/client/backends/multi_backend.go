@@ -2,16 +2,136 @@ package backends
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/sirupsen/logrus"
 )
 
-// MultiBackendClient implements ClientBackend by trying multiple backends in order
+// DefaultUnhealthyCooldown is how long an endpoint is deprioritized after a transport failure
+const DefaultUnhealthyCooldown = 10 * time.Second
+
+// RetryPolicy controls how idempotent requests are retried when all endpoints fail
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none has been set
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// Backoff returns the delay before the given retry (starting at 1), doubling up to MaxBackoff
+func (policy *RetryPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 {
+		return 0
+	}
+
+	backoff := policy.InitialBackoff
+	for i := 1; i < retry; i++ {
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff >= policy.MaxBackoff {
+			return policy.MaxBackoff
+		}
+	}
+
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+
+	return backoff
+}
+
+// IsIdempotent returns true if an RPC method can safely be sent more than once,
+// only read-only methods are retried since a failed write may already have been applied
+func IsIdempotent(method string) bool {
+	switch method {
+	case rpc.VersionPayloadType, rpc.ChannelReadPayloadType:
+		return true
+	}
+
+	return strings.HasPrefix(method, "get")
+}
+
+// IsPreSendError returns true if err is known to have happened before the request reached a server,
+// e.g. the connection was refused or the host could not be resolved, such requests cannot have been
+// applied and are safe to send to another endpoint even if they are not idempotent
+func IsPreSendError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	// gRPC reports connection failures as an Unavailable status without wrapping the dial error
+	return strings.Contains(err.Error(), "Error while dialing")
+}
+
+// canFailover returns true if a request that failed with a transport error may be sent to another endpoint
+func canFailover(method string, err error) bool {
+	return IsIdempotent(method) || IsPreSendError(err)
+}
+
+// rawMessageMethod returns the payload type of a raw RPC message, or an empty string if it cannot be parsed
+func rawMessageMethod(jsonString string) string {
+	rpcMsg, err := rpc.CreateRPCMsgFromJSON(jsonString)
+	if err != nil {
+		return ""
+	}
+
+	return rpcMsg.PayloadType
+}
+
+// IsTransportError returns true if err was caused by the connection to the server rather than
+// an error reply from the server, only transport errors trigger failover to another endpoint
+func IsTransportError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var coloniesErr *core.ColoniesError
+	return !errors.As(err, &coloniesErr)
+}
+
+type endpointHealth struct {
+	unhealthyUntil time.Time
+}
+
+// MultiBackendClient implements ClientBackend by trying multiple backends (endpoints) in order.
+// Endpoints failing with transport errors are marked unhealthy and tried last until the cooldown
+// has expired, and idempotent requests are retried with backoff when all endpoints fail. Other requests
+// are only sent to another endpoint if they failed before reaching the server, since a request that
+// timed out or lost its connection may already have been applied.
 type MultiBackendClient struct {
-	backends []ClientBackend
-	configs  []*ClientConfig
+	backends    []ClientBackend
+	configs     []*ClientConfig
+	health      []endpointHealth
+	factories   map[ClientBackendType]ClientBackendFactory
+	retryPolicy *RetryPolicy
+	cooldown    time.Duration
+	mutex       sync.Mutex
 }
 
 // NewMultiBackendClient creates a client that tries multiple backends with fallback
@@ -21,103 +141,346 @@ func NewMultiBackendClient(configs []*ClientConfig, factories map[ClientBackendT
 	}
 
 	client := &MultiBackendClient{
-		backends: make([]ClientBackend, 0, len(configs)),
-		configs:  configs,
+		backends:  make([]ClientBackend, 0, len(configs)),
+		configs:   make([]*ClientConfig, 0, len(configs)),
+		factories: factories,
 	}
 
-	// Create backends for each config
 	for _, config := range configs {
-		factory, exists := factories[config.BackendType]
-		if !exists {
-			logrus.WithField("backend_type", config.BackendType).Warn("No factory registered for backend type, skipping")
+		client.addEndpoint(config)
+	}
+
+	if len(client.backends) == 0 {
+		return nil, fmt.Errorf("no backends could be initialized")
+	}
+
+	logrus.WithField("backend_count", len(client.backends)).Debug("Multi-backend client initialized")
+	return client, nil
+}
+
+func (m *MultiBackendClient) addEndpoint(config *ClientConfig) bool {
+	factory, exists := m.factories[config.BackendType]
+	if !exists {
+		logrus.WithField("backend_type", config.BackendType).Warn("No factory registered for backend type, skipping")
+		return false
+	}
+
+	backend, err := factory.CreateBackend(config)
+	if err != nil {
+		logrus.WithError(err).WithField("backend_type", config.BackendType).Warn("Failed to create backend, skipping")
+		return false
+	}
+
+	m.backends = append(m.backends, backend)
+	m.configs = append(m.configs, config)
+	m.health = append(m.health, endpointHealth{})
+	logrus.WithFields(logrus.Fields{
+		"backend_type": config.BackendType,
+		"host":         config.Host,
+		"port":         config.Port,
+	}).Debug("Backend initialized successfully")
+
+	return true
+}
+
+// AddEndpoints adds endpoints not already known to the client, e.g. nodes discovered via get_cluster,
+// and returns the number of endpoints added
+func (m *MultiBackendClient) AddEndpoints(configs []*ClientConfig) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	added := 0
+	for _, config := range configs {
+		if m.hasEndpoint(config) {
 			continue
 		}
+		if m.addEndpoint(config) {
+			added++
+		}
+	}
 
-		backend, err := factory.CreateBackend(config)
-		if err != nil {
-			logrus.WithError(err).WithField("backend_type", config.BackendType).Warn("Failed to create backend, skipping")
-			continue
+	return added
+}
+
+func (m *MultiBackendClient) hasEndpoint(config *ClientConfig) bool {
+	for _, existing := range m.configs {
+		if existing.BackendType == config.BackendType && existing.Host == config.Host && existing.Port == config.Port {
+			return true
 		}
+	}
+
+	return false
+}
+
+// Endpoints returns the configurations of all endpoints in the order they were added
+func (m *MultiBackendClient) Endpoints() []*ClientConfig {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	configs := make([]*ClientConfig, len(m.configs))
+	copy(configs, m.configs)
+
+	return configs
+}
 
-		client.backends = append(client.backends, backend)
-		logrus.WithField("backend_type", config.BackendType).Info("Backend initialized successfully")
+// SetRetryPolicy sets the policy used to retry idempotent requests
+func (m *MultiBackendClient) SetRetryPolicy(policy *RetryPolicy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.retryPolicy = policy
+}
+
+// SetUnhealthyCooldown sets how long a failing endpoint is deprioritized
+func (m *MultiBackendClient) SetUnhealthyCooldown(cooldown time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cooldown = cooldown
+}
+
+func (m *MultiBackendClient) getRetryPolicy() *RetryPolicy {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.retryPolicy == nil {
+		return DefaultRetryPolicy()
 	}
 
-	if len(client.backends) == 0 {
-		return nil, fmt.Errorf("no backends could be initialized")
+	return m.retryPolicy
+}
+
+// order returns endpoint indexes to try, healthy endpoints first in the order they were added,
+// followed by unhealthy endpoints ordered by when their cooldown expires
+func (m *MultiBackendClient) order() []int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ensureHealth()
+
+	now := time.Now()
+	healthy := []int{}
+	unhealthy := []int{}
+	for i := range m.backends {
+		if m.health[i].unhealthyUntil.After(now) {
+			unhealthy = append(unhealthy, i)
+		} else {
+			healthy = append(healthy, i)
+		}
 	}
 
-	logrus.WithField("backend_count", len(client.backends)).Info("Multi-backend client initialized")
-	return client, nil
+	sort.SliceStable(unhealthy, func(a, b int) bool {
+		return m.health[unhealthy[a]].unhealthyUntil.Before(m.health[unhealthy[b]].unhealthyUntil)
+	})
+
+	return append(healthy, unhealthy...)
+}
+
+func (m *MultiBackendClient) ensureHealth() {
+	for len(m.health) < len(m.backends) {
+		m.health = append(m.health, endpointHealth{})
+	}
+}
+
+func (m *MultiBackendClient) endpoint(i int) (ClientBackend, *ClientConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.backends[i], m.configs[i]
+}
+
+func (m *MultiBackendClient) markSuccess(i int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ensureHealth()
+	m.health[i] = endpointHealth{}
 }
 
-// SendRawMessage tries each backend in order until one succeeds
+func (m *MultiBackendClient) markFailure(i int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	cooldown := m.cooldown
+	if cooldown == 0 {
+		cooldown = DefaultUnhealthyCooldown
+	}
+
+	m.ensureHealth()
+	m.health[i].unhealthyUntil = time.Now().Add(cooldown)
+}
+
+// IsHealthy returns false if the endpoint at the given index is in its unhealthy cooldown
+func (m *MultiBackendClient) IsHealthy(i int) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ensureHealth()
+	return !m.health[i].unhealthyUntil.After(time.Now())
+}
+
+func waitBackoff(ctx context.Context, backoff time.Duration) error {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendRawMessage tries each backend in health order until one succeeds, following the same failover
+// rules as SendMessage
 func (m *MultiBackendClient) SendRawMessage(jsonString string, insecure bool) (string, error) {
 	var lastErr error
+	method := rawMessageMethod(jsonString)
 
-	for i, backend := range m.backends {
+	for attempt, i := range m.order() {
+		backend, config := m.endpoint(i)
 		result, err := backend.SendRawMessage(jsonString, insecure)
-		if err == nil {
-			if i > 0 {
+		if err == nil || !IsTransportError(err) {
+			m.markSuccess(i)
+			if attempt > 0 {
 				logrus.WithFields(logrus.Fields{
-					"backend_type": m.configs[i].BackendType,
-					"attempt":      i + 1,
+					"backend_type": config.BackendType,
+					"host":         config.Host,
+					"port":         config.Port,
+					"attempt":      attempt + 1,
 				}).Debug("Request succeeded on fallback backend")
 			}
-			return result, nil
+			return result, err
 		}
 
+		m.markFailure(i)
 		logrus.WithError(err).WithFields(logrus.Fields{
-			"backend_type": m.configs[i].BackendType,
-			"attempt":      i + 1,
+			"backend_type": config.BackendType,
+			"host":         config.Host,
+			"port":         config.Port,
+			"attempt":      attempt + 1,
 		}).Debug("Backend request failed, trying next")
 
 		lastErr = err
+		if !canFailover(method, err) {
+			return "", fmt.Errorf("backend failed, request may have been applied and is not retried: %w", err)
+		}
 	}
 
 	return "", fmt.Errorf("all backends failed, last error: %w", lastErr)
 }
 
-// SendMessage tries each backend in order until one succeeds
+// SendMessage tries each backend in health order until one succeeds. Error replies from a server
+// are returned directly, non-idempotent requests only fail over on errors that happened before the
+// request was sent, and idempotent requests are retried with backoff if all backends fail.
 func (m *MultiBackendClient) SendMessage(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rounds := 1
+	policy := m.getRetryPolicy()
+	if IsIdempotent(method) {
+		rounds += policy.MaxRetries
+	}
+
 	var lastErr error
+	for round := 0; round < rounds; round++ {
+		if round > 0 {
+			if err := waitBackoff(ctx, policy.Backoff(round)); err != nil {
+				return "", err
+			}
+		}
 
-	for i, backend := range m.backends {
-		result, err := backend.SendMessage(method, jsonString, prvKey, insecure, ctx)
-		if err == nil {
-			if i > 0 {
-				logrus.WithFields(logrus.Fields{
-					"backend_type": m.configs[i].BackendType,
-					"attempt":      i + 1,
-					"method":       method,
-				}).Debug("Request succeeded on fallback backend")
+		for attempt, i := range m.order() {
+			backend, config := m.endpoint(i)
+			result, err := backend.SendMessage(method, jsonString, prvKey, insecure, ctx)
+			if err == nil || !IsTransportError(err) {
+				m.markSuccess(i)
+				if attempt > 0 || round > 0 {
+					logrus.WithFields(logrus.Fields{
+						"backend_type": config.BackendType,
+						"host":         config.Host,
+						"port":         config.Port,
+						"attempt":      attempt + 1,
+						"retry":        round,
+						"method":       method,
+					}).Debug("Request succeeded on fallback backend")
+				}
+				return result, err
+			}
+
+			m.markFailure(i)
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"backend_type": config.BackendType,
+				"host":         config.Host,
+				"port":         config.Port,
+				"attempt":      attempt + 1,
+				"retry":        round,
+				"method":       method,
+			}).Debug("Backend request failed, trying next")
+
+			lastErr = err
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("all backends failed, last error: %w", lastErr)
 			}
-			return result, nil
+			if !canFailover(method, err) {
+				return "", fmt.Errorf("backend failed, request may have been applied and is not retried: %w", err)
+			}
+		}
+	}
+
+	return "", fmt.Errorf("all backends failed, last error: %w", lastErr)
+}
+
+// EstablishRealtimeConn establishes a realtime connection on the first healthy backend supporting it
+func (m *MultiBackendClient) EstablishRealtimeConn(jsonString string) (RealtimeConnection, error) {
+	var lastErr error
+
+	for _, i := range m.order() {
+		backend, config := m.endpoint(i)
+		realtimeBackend, ok := backend.(RealtimeBackend)
+		if !ok {
+			continue
 		}
 
+		conn, err := realtimeBackend.EstablishRealtimeConn(jsonString)
+		if err == nil {
+			m.markSuccess(i)
+			return conn, nil
+		}
+
+		m.markFailure(i)
 		logrus.WithError(err).WithFields(logrus.Fields{
-			"backend_type": m.configs[i].BackendType,
-			"attempt":      i + 1,
-			"method":       method,
-		}).Debug("Backend request failed, trying next")
+			"backend_type": config.BackendType,
+			"host":         config.Host,
+			"port":         config.Port,
+		}).Debug("Failed to establish realtime connection, trying next")
 
 		lastErr = err
 	}
 
-	return "", fmt.Errorf("all backends failed, last error: %w", lastErr)
+	if lastErr == nil {
+		return nil, errors.New("no backend supports realtime connections")
+	}
+
+	return nil, fmt.Errorf("all backends failed, last error: %w", lastErr)
 }
 
-// CheckHealth checks health of all backends and returns error if all are unhealthy
+// CheckHealth checks health of all backends, updates their health state and returns error if all are unhealthy
 func (m *MultiBackendClient) CheckHealth() error {
 	var errors []string
 	healthyCount := 0
 
-	for i, backend := range m.backends {
+	for _, i := range m.order() {
+		backend, config := m.endpoint(i)
 		err := backend.CheckHealth()
 		if err == nil {
+			m.markSuccess(i)
 			healthyCount++
 		} else {
-			errors = append(errors, fmt.Sprintf("%s: %v", m.configs[i].BackendType, err))
+			m.markFailure(i)
+			errors = append(errors, fmt.Sprintf("%s %s:%d: %v", config.BackendType, config.Host, config.Port, err))
 		}
 	}
 
@@ -125,11 +488,11 @@ func (m *MultiBackendClient) CheckHealth() error {
 		return fmt.Errorf("all backends unhealthy: %s", strings.Join(errors, "; "))
 	}
 
-	if healthyCount < len(m.backends) {
+	if len(errors) > 0 {
 		logrus.WithFields(logrus.Fields{
-			"healthy":   healthyCount,
-			"total":     len(m.backends),
-			"degraded":  errors,
+			"healthy":  healthyCount,
+			"total":    healthyCount + len(errors),
+			"degraded": errors,
 		}).Warn("Some backends are unhealthy")
 	}
 
@@ -138,6 +501,9 @@ func (m *MultiBackendClient) CheckHealth() error {
 
 // Close closes all backends
 func (m *MultiBackendClient) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	var errors []string
 
 	for i, backend := range m.backends {
@@ -154,7 +520,7 @@ func (m *MultiBackendClient) Close() error {
 }
 
 // ParseClientBackendsFromEnv parses comma-separated backend types from environment variable
-// e.g., "http" or "gin"
+// e.g., "http", "gin", "grpc" or "grpc,http"
 func ParseClientBackendsFromEnv(backendsEnv string) []ClientBackendType {
 	if backendsEnv == "" {
 		return []ClientBackendType{GinClientBackendType} // Default to HTTP
@@ -168,6 +534,8 @@ func ParseClientBackendsFromEnv(backendsEnv string) []ClientBackendType {
 		switch part {
 		case "http", "gin":
 			backends = append(backends, GinClientBackendType)
+		case "grpc":
+			backends = append(backends, GRPCClientBackendType)
 		default:
 			logrus.WithField("backend", part).Warn("Unknown backend type, ignoring")
 		}
@@ -180,3 +548,39 @@ func ParseClientBackendsFromEnv(backendsEnv string) []ClientBackendType {
 
 	return backends
 }
+
+// ParseEndpointsFromEnv parses comma-separated host:port endpoints, e.g. "node1:50080,node2:50080",
+// into client configs using the backend type and TLS settings of the given template
+func ParseEndpointsFromEnv(endpointsEnv string, template *ClientConfig) ([]*ClientConfig, error) {
+	configs := []*ClientConfig{}
+	if strings.TrimSpace(endpointsEnv) == "" {
+		return configs, nil
+	}
+
+	for _, part := range strings.Split(endpointsEnv, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		idx := strings.LastIndex(part, ":")
+		if idx <= 0 || idx == len(part)-1 {
+			return nil, fmt.Errorf("invalid endpoint %s, expected host:port", part)
+		}
+
+		port, err := strconv.Atoi(part[idx+1:])
+		if err != nil || port <= 0 {
+			return nil, fmt.Errorf("invalid port in endpoint %s", part)
+		}
+
+		configs = append(configs, &ClientConfig{
+			BackendType:   template.BackendType,
+			Host:          part[:idx],
+			Port:          port,
+			Insecure:      template.Insecure,
+			SkipTLSVerify: template.SkipTLSVerify,
+		})
+	}
+
+	return configs, nil
+}
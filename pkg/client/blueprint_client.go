@@ -2,6 +2,10 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
@@ -71,6 +75,39 @@ func (client *ColoniesClient) GetBlueprintDefinitionByKind(colonyName, kind stri
 	return nil, nil
 }
 
+// UpdateBlueprintDefinition replaces a BlueprintDefinition, e.g. to add a version (requires colony owner privileges)
+func (client *ColoniesClient) UpdateBlueprintDefinition(sd *core.BlueprintDefinition, prvKey string) (*core.BlueprintDefinition, error) {
+	msg := rpc.CreateUpdateBlueprintDefinitionMsg(sd)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.UpdateBlueprintDefinitionPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprintDefinition(respBodyString)
+}
+
+// MigrateBlueprintDefinition converts all blueprints of a BlueprintDefinition to its storage version and
+// validates them against the storage schema (requires colony owner privileges)
+func (client *ColoniesClient) MigrateBlueprintDefinition(namespace, name string, dryRun bool, prvKey string) (*core.BlueprintMigrationReport, error) {
+	msg := rpc.CreateMigrateBlueprintDefinitionMsg(namespace, name, dryRun)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.MigrateBlueprintDefinitionPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprintMigrationReport(respBodyString)
+}
+
 // RemoveBlueprintDefinition removes a BlueprintDefinition by namespace and name (requires colony owner privileges)
 func (client *ColoniesClient) RemoveBlueprintDefinition(namespace, name string, prvKey string) error {
 	msg := rpc.CreateRemoveBlueprintDefinitionMsg(namespace, name)
@@ -119,6 +156,23 @@ func (client *ColoniesClient) GetBlueprint(namespace, name string, prvKey string
 	return core.ConvertJSONToBlueprint(respBodyString)
 }
 
+// GetBlueprintByVersion retrieves a Blueprint converted to the given version of its BlueprintDefinition
+func (client *ColoniesClient) GetBlueprintByVersion(namespace, name, version string, prvKey string) (*core.Blueprint, error) {
+	msg := rpc.CreateGetBlueprintMsg(namespace, name)
+	msg.Version = version
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetBlueprintPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprint(respBodyString)
+}
+
 // GetBlueprints retrieves Blueprints by namespace and optionally by kind
 func (client *ColoniesClient) GetBlueprints(namespace, kind string, prvKey string) ([]*core.Blueprint, error) {
 	return client.GetBlueprintsByLocation(namespace, kind, "", prvKey)
@@ -174,6 +228,150 @@ func (client *ColoniesClient) UpdateBlueprintWithForce(blueprint *core.Blueprint
 	return core.ConvertJSONToBlueprint(respBodyString)
 }
 
+// UpdateBlueprintWithResourceVersion updates an existing Blueprint only if it has not been modified since
+// it was read, i.e. if the stored resource version equals resourceVersion. Otherwise a conflict error
+// (status 409) is returned, see IsConflict.
+func (client *ColoniesClient) UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64, prvKey string) (*core.Blueprint, error) {
+	msg := rpc.CreateUpdateBlueprintMsg(blueprint)
+	msg.CheckResourceVersion = true
+	msg.ResourceVersion = resourceVersion
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.UpdateBlueprintPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprint(respBodyString)
+}
+
+// PatchBlueprint applies a merge patch (core.MergePatchType) or strategic merge patch
+// (core.StrategicPatchType) to a Blueprint on the server and returns the patched Blueprint
+func (client *ColoniesClient) PatchBlueprint(namespace, name string, patchType string, patch map[string]interface{}, prvKey string) (*core.Blueprint, error) {
+	return client.patchBlueprint(rpc.CreatePatchBlueprintMsg(namespace, name, patchType, patch), prvKey)
+}
+
+// PatchBlueprintWithResourceVersion applies a patch only if the stored resource version equals
+// resourceVersion
+func (client *ColoniesClient) PatchBlueprintWithResourceVersion(namespace, name string, patchType string, patch map[string]interface{}, resourceVersion int64, prvKey string) (*core.Blueprint, error) {
+	msg := rpc.CreatePatchBlueprintMsg(namespace, name, patchType, patch)
+	msg.CheckResourceVersion = true
+	msg.ResourceVersion = resourceVersion
+
+	return client.patchBlueprint(msg, prvKey)
+}
+
+func (client *ColoniesClient) patchBlueprint(msg *rpc.PatchBlueprintMsg, prvKey string) (*core.Blueprint, error) {
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.PatchBlueprintPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprint(respBodyString)
+}
+
+// maxFinalizerAttempts is how many times RemoveBlueprintFinalizer retries when the blueprint is modified
+// concurrently
+const maxFinalizerAttempts = 5
+
+// RemoveBlueprintFinalizer removes a finalizer from a Blueprint, typically by a reconciler when it has
+// cleaned up after a blueprint that is being deleted. The blueprint is removed by the server when its
+// last finalizer is removed.
+func (client *ColoniesClient) RemoveBlueprintFinalizer(namespace, name string, finalizer string, prvKey string) error {
+	var err error
+	for attempt := 0; attempt < maxFinalizerAttempts; attempt++ {
+		var blueprint *core.Blueprint
+		blueprint, err = client.GetBlueprint(namespace, name, prvKey)
+		if err != nil {
+			return err
+		}
+
+		if !blueprint.RemoveFinalizer(finalizer) {
+			return nil
+		}
+
+		patch := map[string]interface{}{"metadata": map[string]interface{}{"finalizers": blueprint.Metadata.Finalizers}}
+		_, err = client.PatchBlueprintWithResourceVersion(namespace, name, core.MergePatchType, patch, blueprint.Metadata.ResourceVersion, prvKey)
+		if !IsConflict(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// ApplyBlueprints makes the blueprints in a colony match the given blueprints, and returns the plan that
+// was applied. Either all changes are applied or none. If prune is set, blueprints labelled with
+// core.ManagedByLabel=managedBy that are not among the given blueprints are removed. With dryRun the
+// plan is only computed.
+func (client *ColoniesClient) ApplyBlueprints(namespace string, blueprints []*core.Blueprint, prune bool, managedBy string, dryRun bool, prvKey string) (*core.BlueprintApplyPlan, error) {
+	msg := rpc.CreateApplyBlueprintsMsg(namespace, blueprints)
+	msg.Prune = prune
+	msg.ManagedBy = managedBy
+	msg.DryRun = dryRun
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.ApplyBlueprintsPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprintApplyPlan(respBodyString)
+}
+
+// RollbackBlueprint re-applies the spec of a previous generation of a blueprint as a new generation
+func (client *ColoniesClient) RollbackBlueprint(namespace, name string, generation int64, prvKey string) (*core.Blueprint, error) {
+	msg := rpc.CreateRollbackBlueprintMsg(namespace, name, generation)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.RollbackBlueprintPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToBlueprint(respBodyString)
+}
+
+// blueprintWaitInterval is how often WaitForBlueprintCondition polls the blueprint
+const blueprintWaitInterval = 1 * time.Second
+
+// WaitForBlueprintCondition waits until a Blueprint has a condition with the given type and status that
+// was computed from its current generation, see core.Blueprint.IsConditionMet. A timeout of 0 waits
+// forever.
+func (client *ColoniesClient) WaitForBlueprintCondition(namespace, name, conditionType, status string, timeout time.Duration, prvKey string) (*core.Blueprint, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		blueprint, err := client.GetBlueprint(namespace, name, prvKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if blueprint.IsConditionMet(conditionType, status) {
+			return blueprint, nil
+		}
+
+		if timeout > 0 && time.Now().After(deadline) {
+			return blueprint, fmt.Errorf("timed out waiting for condition %s=%s on blueprint '%s'", conditionType, status, name)
+		}
+
+		time.Sleep(blueprintWaitInterval)
+	}
+}
+
 // GetBlueprintHistory retrieves history for a blueprint
 func (client *ColoniesClient) GetBlueprintHistory(blueprintID string, limit int, prvKey string) ([]*core.BlueprintHistory, error) {
 	msg := rpc.CreateGetBlueprintHistoryMsg(blueprintID, limit)
@@ -206,6 +404,25 @@ func (client *ColoniesClient) RemoveBlueprint(namespace, name string, prvKey str
 	return nil
 }
 
+// RemoveBlueprintWithResourceVersion removes a Blueprint only if the stored resource version equals
+// resourceVersion
+func (client *ColoniesClient) RemoveBlueprintWithResourceVersion(namespace, name string, resourceVersion int64, prvKey string) error {
+	msg := rpc.CreateRemoveBlueprintMsg(namespace, name)
+	msg.CheckResourceVersion = true
+	msg.ResourceVersion = resourceVersion
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveBlueprintPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateBlueprintStatus updates only the status field of a blueprint
 // This is used by reconcilers to report status without triggering a full update or generation bump
 func (client *ColoniesClient) UpdateBlueprintStatus(colonyName, blueprintName string, status map[string]interface{}, prvKey string) error {
@@ -223,6 +440,32 @@ func (client *ColoniesClient) UpdateBlueprintStatus(colonyName, blueprintName st
 	return nil
 }
 
+// UpdateBlueprintStatusWithResourceVersion updates the status of a blueprint only if the stored resource
+// version equals resourceVersion
+func (client *ColoniesClient) UpdateBlueprintStatusWithResourceVersion(colonyName, blueprintName string, status map[string]interface{}, resourceVersion int64, prvKey string) error {
+	msg := rpc.CreateUpdateBlueprintStatusMsg(colonyName, blueprintName, status)
+	msg.CheckResourceVersion = true
+	msg.ResourceVersion = resourceVersion
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.UpdateBlueprintStatusPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsConflict returns true if err is a conflict reported by the server, e.g. because a blueprint was
+// modified after it was read
+func IsConflict(err error) bool {
+	var coloniesErr *core.ColoniesError
+	return errors.As(err, &coloniesErr) && coloniesErr.Status == http.StatusConflict
+}
+
 // ReconcileBlueprint triggers immediate reconciliation of a blueprint
 // The server looks up the executor type from the blueprint's handler configuration
 // If force is true, the generation will be bumped to trigger redeployment
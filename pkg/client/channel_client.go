@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/colonyos/colonies/pkg/channel"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
 )
 
@@ -53,3 +54,71 @@ func (client *ColoniesClient) ChannelRead(processID string, channelName string,
 
 	return entries, nil
 }
+
+// SubscribeChannel streams entries appended to a channel after afterSeq, entries already
+// in the channel are delivered first. The subscription ends after timeout seconds.
+func (client *ColoniesClient) SubscribeChannel(processID string, channelName string, afterSeq int64, timeout int, prvKey string) (*ChannelSubscription, error) {
+	msg := rpc.CreateSubscribeChannelMsg(processID, channelName, afterSeq, timeout)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeChannelPayloadType, jsonString, prvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonString, err = rpcMsg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.establishRealtimeConn(jsonString)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := createChannelSubscription(conn)
+	go func(subscription *ChannelSubscription) {
+		defer close(subscription.EntriesChan)
+		for {
+			_, jsonBytes, err := subscription.conn.ReadMessage()
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			if rpcReplyMsg.Error {
+				failure, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+				if err != nil {
+					subscription.ErrChan <- err
+					return
+				}
+				subscription.ErrChan <- &core.ColoniesError{Status: failure.Status, Message: failure.Message}
+				return
+			}
+
+			var entries []*channel.MsgEntry
+			err = json.Unmarshal([]byte(rpcReplyMsg.DecodePayload()), &entries)
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			subscription.EntriesChan <- entries
+			if len(entries) == 0 {
+				subscription.conn.Close()
+				return
+			}
+		}
+	}(subscription)
+
+	return subscription, nil
+}
@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/colonyos/colonies/pkg/client/backends"
+	"github.com/colonyos/colonies/pkg/cluster"
 )
 
 // ColoniesClient is the main client for interacting with Colonies server
 type ColoniesClient struct {
 	backend backends.ClientBackend
 	config  *backends.ClientConfig
+	mutex   sync.RWMutex
+	pending *sync.WaitGroup // Requests in flight on backend
 }
 
 // CreateColoniesClient creates a new ColoniesClient with default HTTP/Gin backend
@@ -23,7 +27,8 @@ func CreateColoniesClient(host string, port int, insecure bool, skipTLSVerify bo
 // CreateColoniesClientWithConfig creates a new ColoniesClient with specified configuration
 func CreateColoniesClientWithConfig(config *backends.ClientConfig) *ColoniesClient {
 	client := &ColoniesClient{
-		config: config,
+		config:  config,
+		pending: &sync.WaitGroup{},
 	}
 	
 	// Initialize with the appropriate backend
@@ -63,32 +68,140 @@ func CreateColoniesClientWithMultipleBackends(configs []*backends.ClientConfig)
 	return &ColoniesClient{
 		backend: multiBackend,
 		config:  configs[0], // Use first config for compatibility
+		pending: &sync.WaitGroup{},
 	}
 }
 
+// getBackend returns the current backend, it is replaced by DiscoverClusterEndpoints
+func (client *ColoniesClient) getBackend() backends.ClientBackend {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+
+	return client.backend
+}
+
+// acquireBackend returns the current backend and a function that must be called when the request
+// has completed, a replaced backend is not closed until all its requests have completed
+func (client *ColoniesClient) acquireBackend() (backends.ClientBackend, func()) {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+
+	pending := client.pending
+	if pending == nil {
+		return client.backend, func() {}
+	}
+
+	pending.Add(1)
+	return client.backend, pending.Done
+}
+
 // SendRawMessage sends a raw JSON message using the underlying backend
 func (client *ColoniesClient) SendRawMessage(jsonString string, insecure bool) (string, error) {
-	return client.backend.SendRawMessage(jsonString, insecure)
+	backend, release := client.acquireBackend()
+	defer release()
+
+	return backend.SendRawMessage(jsonString, insecure)
 }
 
 // sendMessage sends an RPC message using the underlying backend
 func (client *ColoniesClient) sendMessage(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
-	return client.backend.SendMessage(method, jsonString, prvKey, insecure, ctx)
+	backend, release := client.acquireBackend()
+	defer release()
+
+	return backend.SendMessage(method, jsonString, prvKey, insecure, ctx)
 }
 
 // establishRealtimeConn establishes a realtime connection using the underlying backend
 func (client *ColoniesClient) establishRealtimeConn(jsonString string) (backends.RealtimeConnection, error) {
 	// Check if backend supports realtime connections
-	if realtimeBackend, ok := client.backend.(backends.RealtimeBackend); ok {
+	if realtimeBackend, ok := client.getBackend().(backends.RealtimeBackend); ok {
 		return realtimeBackend.EstablishRealtimeConn(jsonString)
 	}
 	return nil, errors.New("backend does not support realtime connections")
 }
 
+// DiscoverClusterEndpoints adds the API endpoints of all cluster nodes returned by get_cluster,
+// enabling failover between nodes. It requires the server owner private key. Returns the number of
+// endpoints added.
+func (client *ColoniesClient) DiscoverClusterEndpoints(prvKey string) (int, error) {
+	clusterConfig, err := client.GetClusterInfo(prvKey)
+	if err != nil {
+		return 0, err
+	}
+
+	configs := createClusterEndpointConfigs(clusterConfig, client.config)
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if multiBackend, ok := client.backend.(*backends.MultiBackendClient); ok {
+		return multiBackend.AddEndpoints(configs), nil
+	}
+
+	// Replace the single backend with a multi-backend client, keeping the current endpoint first. The
+	// old backend is closed after requests in flight have completed.
+	multiBackend, err := backends.NewMultiBackendClient([]*backends.ClientConfig{client.config}, backendFactories)
+	if err != nil {
+		return 0, err
+	}
+
+	added := multiBackend.AddEndpoints(configs)
+	oldBackend := client.backend
+	oldPending := client.pending
+	client.backend = multiBackend
+	client.pending = &sync.WaitGroup{}
+	go func() {
+		if oldPending != nil {
+			oldPending.Wait()
+		}
+		oldBackend.Close()
+	}()
+
+	return added, nil
+}
+
+// createClusterEndpointConfigs creates a config for each cluster node using the backend type of the client
+// config. Nodes without a port for the backend type, e.g. nodes with gRPC disabled, are skipped.
+func createClusterEndpointConfigs(clusterConfig *cluster.Config, config *backends.ClientConfig) []*backends.ClientConfig {
+	backendType := config.BackendType
+	if backendType == "" {
+		backendType = backends.GinClientBackendType
+	}
+
+	configs := []*backends.ClientConfig{}
+	for _, node := range clusterConfig.Nodes {
+		port := node.APIPort
+		if backendType == backends.GRPCClientBackendType {
+			port = node.GRPCPort
+		}
+		if port <= 0 {
+			continue
+		}
+
+		configs = append(configs, &backends.ClientConfig{
+			BackendType:   backendType,
+			Host:          node.Host,
+			Port:          port,
+			Insecure:      config.Insecure,
+			SkipTLSVerify: config.SkipTLSVerify,
+		})
+	}
+
+	return configs
+}
+
+// SetRetryPolicy sets the policy used to retry idempotent requests, it only has effect when the
+// client has multiple endpoints
+func (client *ColoniesClient) SetRetryPolicy(policy *backends.RetryPolicy) {
+	if multiBackend, ok := client.getBackend().(*backends.MultiBackendClient); ok {
+		multiBackend.SetRetryPolicy(policy)
+	}
+}
+
 // CloseClient closes the client and cleans up blueprints
 func (client *ColoniesClient) CloseClient() error {
-	if client.backend != nil {
-		return client.backend.Close()
+	if backend := client.getBackend(); backend != nil {
+		return backend.Close()
 	}
 	return nil
 }
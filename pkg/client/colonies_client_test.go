@@ -1,9 +1,17 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/client/backends"
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,4 +35,200 @@ func TestClientPauseResumeMessageConstruction(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, jsonString2, "resumeassignmentsmsg")
 	assert.Contains(t, jsonString2, colonyName)
-}
\ No newline at end of file
+}
+
+type scriptedRealtimeConnection struct {
+	messages chan []byte
+	closed   chan struct{}
+}
+
+func (c *scriptedRealtimeConnection) WriteMessage(messageType int, data []byte) error { return nil }
+
+func (c *scriptedRealtimeConnection) ReadMessage() (int, []byte, error) {
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			return 0, nil, errors.New("connection lost")
+		}
+		return backends.TextMessage, msg, nil
+	case <-c.closed:
+		return 0, nil, errors.New("connection closed")
+	}
+}
+
+func (c *scriptedRealtimeConnection) Close() error {
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	return nil
+}
+
+func (c *scriptedRealtimeConnection) SetReadLimit(limit int64) {}
+
+type realtimeBackendMock struct {
+	conns    chan *scriptedRealtimeConnection
+	requests chan string
+}
+
+func (b *realtimeBackendMock) SendRawMessage(jsonString string, insecure bool) (string, error) {
+	return "", nil
+}
+
+func (b *realtimeBackendMock) SendMessage(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (b *realtimeBackendMock) CheckHealth() error { return nil }
+
+func (b *realtimeBackendMock) Close() error { return nil }
+
+func (b *realtimeBackendMock) EstablishRealtimeConn(jsonString string) (backends.RealtimeConnection, error) {
+	b.requests <- jsonString
+	return <-b.conns, nil
+}
+
+func TestSubscribeProcessesResubscribe(t *testing.T) {
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	lostConn := &scriptedRealtimeConnection{messages: make(chan []byte), closed: make(chan struct{})}
+	newConn := &scriptedRealtimeConnection{messages: make(chan []byte, 1), closed: make(chan struct{})}
+	backend := &realtimeBackendMock{conns: make(chan *scriptedRealtimeConnection, 2), requests: make(chan string, 2)}
+	backend.conns <- lostConn
+	backend.conns <- newConn
+
+	client := &ColoniesClient{backend: backend, config: backends.CreateDefaultClientConfig("localhost", 50080, true, false)}
+	subscription, err := client.SubscribeProcesses("test_colony", "test_executor_type", core.WAITING, 100, prvKey)
+	assert.Nil(t, err)
+	<-backend.requests
+
+	// The connection is lost, the subscription is re-established with the remaining timeout
+	close(lostConn.messages)
+	rpcMsg, err := rpc.CreateRPCMsgFromJSON(<-backend.requests)
+	assert.Nil(t, err)
+	assert.Equal(t, rpc.SubscribeProcessesPayloadType, rpcMsg.PayloadType)
+	msg, err := rpc.CreateSubscribeProcessesMsgFromJSON(rpcMsg.DecodePayload())
+	assert.Nil(t, err)
+	assert.True(t, msg.Timeout > 0 && msg.Timeout <= 100)
+
+	process := core.CreateProcess(core.CreateEmptyFunctionSpec())
+	processJSON, err := process.ToJSON()
+	assert.Nil(t, err)
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeProcessesPayloadType, processJSON)
+	assert.Nil(t, err)
+	rpcReplyJSON, err := rpcReplyMsg.ToJSON()
+	assert.Nil(t, err)
+	newConn.messages <- []byte(rpcReplyJSON)
+
+	receivedProcess := <-subscription.ProcessChan
+	assert.Equal(t, process.ID, receivedProcess.ID)
+
+	// The lost connection is closed when it is replaced
+	<-lostConn.closed
+
+	assert.Nil(t, subscription.Close())
+	<-newConn.closed
+}
+
+func TestSubscribeProcessesNoResubscribeAfterTimeout(t *testing.T) {
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	conn := &scriptedRealtimeConnection{messages: make(chan []byte), closed: make(chan struct{})}
+	backend := &realtimeBackendMock{conns: make(chan *scriptedRealtimeConnection, 1), requests: make(chan string, 2)}
+	backend.conns <- conn
+
+	client := &ColoniesClient{backend: backend, config: backends.CreateDefaultClientConfig("localhost", 50080, true, false)}
+	subscription, err := client.SubscribeProcesses("test_colony", "test_executor_type", core.WAITING, 0, prvKey)
+	assert.Nil(t, err)
+	<-backend.requests
+
+	// The server closes the connection when the subscription times out
+	close(conn.messages)
+	assert.NotNil(t, <-subscription.ErrChan)
+	assert.Len(t, backend.requests, 0)
+}
+
+type clusterBackendMock struct {
+	clusterJSON string
+	started     chan struct{}
+	release     chan struct{}
+	closed      chan struct{}
+}
+
+func (b *clusterBackendMock) SendRawMessage(jsonString string, insecure bool) (string, error) {
+	return "", nil
+}
+
+func (b *clusterBackendMock) SendMessage(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+	if method == rpc.GetClusterPayloadType {
+		return b.clusterJSON, nil
+	}
+
+	b.started <- struct{}{}
+	<-b.release
+	return "", nil
+}
+
+func (b *clusterBackendMock) CheckHealth() error { return nil }
+
+func (b *clusterBackendMock) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func TestDiscoverClusterEndpointsClosesReplacedBackend(t *testing.T) {
+	clusterConfig := cluster.Config{}
+	clusterConfig.AddNode(cluster.Node{Name: "node2", Host: "node2", APIPort: 50080})
+	clusterJSON, err := clusterConfig.ToJSON()
+	assert.Nil(t, err)
+
+	backend := &clusterBackendMock{clusterJSON: clusterJSON, started: make(chan struct{}), release: make(chan struct{}), closed: make(chan struct{})}
+	client := &ColoniesClient{backend: backend, config: backends.CreateDefaultClientConfig("localhost", 50080, true, false), pending: &sync.WaitGroup{}}
+
+	done := make(chan struct{})
+	go func() {
+		client.sendMessage(rpc.GetProcessPayloadType, "{}", "", true, context.TODO())
+		close(done)
+	}()
+	<-backend.started
+
+	added, err := client.DiscoverClusterEndpoints("")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, added)
+	_, ok := client.getBackend().(*backends.MultiBackendClient)
+	assert.True(t, ok)
+
+	// The replaced backend is not closed while a request is in flight
+	select {
+	case <-backend.closed:
+		t.Fatal("Backend closed with a request in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(backend.release)
+	<-done
+	<-backend.closed
+}
+
+func TestCreateClusterEndpointConfigs(t *testing.T) {
+	clusterConfig := &cluster.Config{}
+	clusterConfig.AddNode(cluster.Node{Name: "node1", Host: "node1", APIPort: 50080, GRPCPort: 50090})
+	clusterConfig.AddNode(cluster.Node{Name: "node2", Host: "node2", APIPort: 50080})
+
+	configs := createClusterEndpointConfigs(clusterConfig, backends.CreateDefaultClientConfig("localhost", 50080, true, false))
+	assert.Len(t, configs, 2)
+	assert.Equal(t, backends.GinClientBackendType, configs[0].BackendType)
+	assert.Equal(t, 50080, configs[0].Port)
+
+	grpcConfig := backends.CreateDefaultClientConfig("localhost", 50090, true, false)
+	grpcConfig.BackendType = backends.GRPCClientBackendType
+	configs = createClusterEndpointConfigs(clusterConfig, grpcConfig)
+	assert.Len(t, configs, 1)
+	assert.Equal(t, backends.GRPCClientBackendType, configs[0].BackendType)
+	assert.Equal(t, "node1", configs[0].Host)
+	assert.Equal(t, 50090, configs[0].Port)
+	assert.True(t, configs[0].Insecure)
+}
@@ -67,6 +67,36 @@ func (client *ColoniesClient) RunCron(cronID string, prvKey string) (*core.Cron,
 	return core.ConvertJSONToCron(respBodyString)
 }
 
+func (client *ColoniesClient) SuspendCron(cronID string, prvKey string) (*core.Cron, error) {
+	msg := rpc.CreateSuspendCronMsg(cronID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.SuspendCronPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToCron(respBodyString)
+}
+
+func (client *ColoniesClient) ResumeCron(cronID string, prvKey string) (*core.Cron, error) {
+	msg := rpc.CreateResumeCronMsg(cronID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.ResumeCronPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToCron(respBodyString)
+}
+
 func (client *ColoniesClient) RemoveCron(cronID string, prvKey string) error {
 	msg := rpc.CreateRemoveCronMsg(cronID)
 	jsonString, err := msg.ToJSON()
@@ -94,6 +94,22 @@ func (client *ColoniesClient) ApproveExecutor(colonyName string, executorName st
 	return nil
 }
 
+// ExecutorHeartbeat marks the calling executor as alive
+func (client *ColoniesClient) ExecutorHeartbeat(colonyName string, executorName string, prvKey string) error {
+	msg := rpc.CreateExecutorHeartbeatMsg(colonyName, executorName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.ExecutorHeartbeatPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (client *ColoniesClient) RejectExecutor(colonyName string, executorID string, prvKey string) error {
 	msg := rpc.CreateRejectExecutorMsg(colonyName, executorID)
 	jsonString, err := msg.ToJSON()
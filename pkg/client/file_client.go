@@ -2,13 +2,21 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
 )
 
 func (client *ColoniesClient) AddFile(file *core.File, prvKey string) (*core.File, error) {
+	return client.AddProcessFile(file, "", prvKey)
+}
+
+// AddProcessFile adds a file produced by a process, the file is recorded as an output of the process. Only the
+// executor assigned to the process can add files for it, and only while the process is running.
+func (client *ColoniesClient) AddProcessFile(file *core.File, processID string, prvKey string) (*core.File, error) {
 	msg := rpc.CreateAddFileMsg(file)
+	msg.ProcessID = processID
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return nil, err
@@ -122,6 +130,21 @@ func (client *ColoniesClient) GetFileLabelsByName(colonyName string, name string
 	return labels, err
 }
 
+func (client *ColoniesClient) UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string, prvKey string) error {
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg(colonyName, fileID, encryptionKey)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.UpdateFileEncryptionKeyPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (client *ColoniesClient) RemoveFileByID(colonyName string, fileID string, prvKey string) error {
 	msg := rpc.CreateRemoveFileMsg(colonyName, fileID, "", "")
 	jsonString, err := msg.ToJSON()
@@ -150,4 +173,92 @@ func (client *ColoniesClient) RemoveFileByName(colonyName string, label string,
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (client *ColoniesClient) SetFileRetentionPolicy(policy *core.FileRetentionPolicy, prvKey string) (*core.FileRetentionPolicy, error) {
+	msg := rpc.CreateSetFileRetentionPolicyMsg(policy)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.SetFileRetentionPolicyPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileRetentionPolicy(respBodyString)
+}
+
+func (client *ColoniesClient) GetFileRetentionPolicies(colonyName string, prvKey string) ([]*core.FileRetentionPolicy, error) {
+	msg := rpc.CreateGetFileRetentionPoliciesMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFileRetentionPoliciesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileRetentionPolicyArray(respBodyString)
+}
+
+func (client *ColoniesClient) RemoveFileRetentionPolicy(colonyName string, label string, prvKey string) error {
+	msg := rpc.CreateRemoveFileRetentionPolicyMsg(colonyName, label)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveFileRetentionPolicyPayloadType, jsonString, prvKey, false, context.TODO())
+	return err
+}
+
+// GCFiles removes stale file revisions and unreferenced objects issued to the colony, objects issued within
+// gracePeriod are kept. If dryRun is true, nothing is removed.
+func (client *ColoniesClient) GCFiles(colonyName string, gracePeriod time.Duration, dryRun bool, prvKey string) (*core.FileGCReport, error) {
+	msg := rpc.CreateGCFilesMsg(colonyName, int64(gracePeriod/time.Second), dryRun)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GCFilesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileGCReport(respBodyString)
+}
+
+func (client *ColoniesClient) getFileLineage(msg *rpc.GetFileLineageMsg, prvKey string) ([]*core.FileLineage, error) {
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetFileLineagePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToFileLineageArray(respBodyString)
+}
+
+// GetProcessFileLineage returns the file revisions consumed and produced by a process. Direction is
+// core.LineageInput, core.LineageOutput or empty for both.
+func (client *ColoniesClient) GetProcessFileLineage(colonyName string, processID string, direction string, prvKey string) ([]*core.FileLineage, error) {
+	return client.getFileLineage(rpc.CreateGetFileLineageMsg(colonyName, processID, "", "", direction), prvKey)
+}
+
+// GetProcessGraphFileLineage returns the file revisions consumed and produced by the processes of a workflow
+func (client *ColoniesClient) GetProcessGraphFileLineage(colonyName string, processGraphID string, direction string, prvKey string) ([]*core.FileLineage, error) {
+	return client.getFileLineage(rpc.CreateGetFileLineageMsg(colonyName, "", processGraphID, "", direction), prvKey)
+}
+
+// GetFileLineage returns the processes that consumed or produced a file revision
+func (client *ColoniesClient) GetFileLineage(colonyName string, fileID string, direction string, prvKey string) ([]*core.FileLineage, error) {
+	return client.getFileLineage(rpc.CreateGetFileLineageMsg(colonyName, "", "", fileID, direction), prvKey)
+}
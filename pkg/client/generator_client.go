@@ -68,7 +68,13 @@ func (client *ColoniesClient) GetGenerators(colonyName string, count int, prvKey
 }
 
 func (client *ColoniesClient) PackGenerator(generatorID string, arg string, prvKey string) error {
+	return client.PackGeneratorWithKey(generatorID, "", arg, prvKey)
+}
+
+// PackGeneratorWithKey packs an arg that is batched together with other args packed with the same key
+func (client *ColoniesClient) PackGeneratorWithKey(generatorID string, key string, arg string, prvKey string) error {
 	msg := rpc.CreatePackGeneratorMsg(generatorID, arg)
+	msg.Key = key
 	jsonString, err := msg.ToJSON()
 	if err != nil {
 		return err
@@ -0,0 +1,174 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"strconv"
+	"time"
+
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/client/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPCClientBackend implements the client backend using gRPC, RPC messages are sent
+// in the same envelopes as the gin backend
+type GRPCClientBackend struct {
+	conn          *grpc.ClientConn
+	host          string
+	port          int
+	insecure      bool
+	skipTLSVerify bool
+}
+
+// NewGRPCClientBackend creates a new gRPC client backend, the connection is established lazily
+func NewGRPCClientBackend(config *backends.ClientConfig) (*GRPCClientBackend, error) {
+	if config.BackendType != backends.GRPCClientBackendType {
+		return nil, errors.New("invalid backend type for grpc client")
+	}
+
+	creds := insecure.NewCredentials()
+	if !config.Insecure {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: config.SkipTLSVerify})
+	}
+
+	conn, err := grpc.NewClient(config.Host+":"+strconv.Itoa(config.Port),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(
+			grpc.CallContentSubtype(grpcbackend.CodecName),
+			grpc.MaxCallRecvMsgSize(grpcbackend.MaxMessageSize),
+			grpc.MaxCallSendMsgSize(grpcbackend.MaxMessageSize)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCClientBackend{
+		conn:          conn,
+		host:          config.Host,
+		port:          config.Port,
+		insecure:      config.Insecure,
+		skipTLSVerify: config.SkipTLSVerify,
+	}, nil
+}
+
+// SendRawMessage sends a raw JSON message via gRPC
+func (g *GRPCClientBackend) SendRawMessage(jsonString string, insecure bool) (string, error) {
+	reply := &grpcbackend.Envelope{}
+	err := g.conn.Invoke(context.Background(), grpcbackend.SendMessageMethod, &grpcbackend.Envelope{Data: []byte(jsonString)}, reply)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply.Data), nil
+}
+
+// SendMessage sends an RPC message with authentication via gRPC
+func (g *GRPCClientBackend) SendMessage(method string, jsonString string, prvKey string, insecure bool, ctx context.Context) (string, error) {
+	var rpcMsg *rpc.RPCMsg
+	var err error
+	if insecure {
+		rpcMsg, err = rpc.CreateInsecureRPCMsg(method, jsonString)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		rpcMsg, err = rpc.CreateRPCMsg(method, jsonString, prvKey)
+		if err != nil {
+			return "", err
+		}
+	}
+	jsonString, err = rpcMsg.ToJSON()
+	if err != nil {
+		return "", err
+	}
+
+	reply := &grpcbackend.Envelope{}
+	err = g.conn.Invoke(ctx, grpcbackend.SendMessageMethod, &grpcbackend.Envelope{Data: []byte(jsonString)}, reply)
+	if err != nil {
+		return "", err
+	}
+
+	respBodyString := string(reply.Data)
+
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(respBodyString)
+	if err != nil {
+		return "", errors.New("Expected a valid Colonies RPC message, but got this: " + respBodyString)
+	}
+
+	if rpcReplyMsg.Error {
+		failure, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+		if err != nil {
+			return "", err
+		}
+
+		return "", &core.ColoniesError{Status: failure.Status, Message: failure.Message}
+	}
+
+	return rpcReplyMsg.DecodePayload(), nil
+}
+
+// EstablishRealtimeConn opens a server-streaming Subscribe call
+func (g *GRPCClientBackend) EstablishRealtimeConn(jsonString string) (backends.RealtimeConnection, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := g.conn.NewStream(ctx, &grpcbackend.SubscribeStreamDesc, grpcbackend.SubscribeMethod)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	err = stream.SendMsg(&grpcbackend.Envelope{Data: []byte(jsonString)})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	err = stream.CloseSend()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return NewStreamRealtimeConnection(stream, cancel), nil
+}
+
+// CheckHealth checks the health of the server via gRPC
+func (g *GRPCClientBackend) CheckHealth() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return g.conn.Invoke(ctx, grpcbackend.HealthMethod, &grpcbackend.Envelope{}, &grpcbackend.Envelope{})
+}
+
+// Close closes the underlying gRPC connection
+func (g *GRPCClientBackend) Close() error {
+	return g.conn.Close()
+}
+
+// GRPCClientBackendFactory creates gRPC client backends
+type GRPCClientBackendFactory struct{}
+
+// NewGRPCClientBackendFactory creates a new gRPC client backend factory
+func NewGRPCClientBackendFactory() *GRPCClientBackendFactory {
+	return &GRPCClientBackendFactory{}
+}
+
+// CreateBackend creates a new gRPC client backend
+func (f *GRPCClientBackendFactory) CreateBackend(config *backends.ClientConfig) (backends.ClientBackend, error) {
+	return NewGRPCClientBackend(config)
+}
+
+// GetBackendType returns the backend type this factory creates
+func (f *GRPCClientBackendFactory) GetBackendType() backends.ClientBackendType {
+	return backends.GRPCClientBackendType
+}
+
+// Compile-time checks that GRPCClientBackend implements the required interfaces
+var _ backends.ClientBackend = (*GRPCClientBackend)(nil)
+var _ backends.RealtimeBackend = (*GRPCClientBackend)(nil)
+var _ backends.ClientBackendWithRealtime = (*GRPCClientBackend)(nil)
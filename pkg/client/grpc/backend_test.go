@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/client/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type serviceMock struct {
+	requests chan string
+	reply    func(request string) string
+	stream   []string
+}
+
+func (s *serviceMock) SendMessage(ctx context.Context, request *grpcbackend.Envelope) (*grpcbackend.Envelope, error) {
+	s.requests <- string(request.Data)
+	return &grpcbackend.Envelope{Data: []byte(s.reply(string(request.Data)))}, nil
+}
+
+func (s *serviceMock) Health(ctx context.Context, request *grpcbackend.Envelope) (*grpcbackend.Envelope, error) {
+	return &grpcbackend.Envelope{}, nil
+}
+
+func (s *serviceMock) Subscribe(request *grpcbackend.Envelope, stream grpcbackend.SubscribeStream) error {
+	s.requests <- string(request.Data)
+	for _, msg := range s.stream {
+		if err := stream.Send(&grpcbackend.Envelope{Data: []byte(msg)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func startServer(t *testing.T, service *serviceMock) (int, func()) {
+	server, err := grpcbackend.NewServer(0, service, false, "", "")
+	assert.Nil(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	go server.Serve(listener)
+
+	return listener.Addr().(*net.TCPAddr).Port, func() { server.Shutdown(context.Background()) }
+}
+
+func createBackend(t *testing.T, port int) *GRPCClientBackend {
+	backend, err := NewGRPCClientBackend(&backends.ClientConfig{
+		BackendType: backends.GRPCClientBackendType,
+		Host:        "127.0.0.1",
+		Port:        port,
+		Insecure:    true,
+	})
+	assert.Nil(t, err)
+
+	return backend
+}
+
+func createReply(t *testing.T, payloadType string, payload string) string {
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsg(payloadType, payload)
+	assert.Nil(t, err)
+	jsonString, err := rpcReplyMsg.ToJSON()
+	assert.Nil(t, err)
+
+	return jsonString
+}
+
+func TestNewGRPCClientBackend(t *testing.T) {
+	backend, err := NewGRPCClientBackend(&backends.ClientConfig{
+		BackendType: backends.GRPCClientBackendType,
+		Host:        "localhost",
+		Port:        50051,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost", backend.host)
+	assert.Equal(t, 50051, backend.port)
+	assert.False(t, backend.insecure)
+	assert.Nil(t, backend.Close())
+}
+
+func TestNewGRPCClientBackendInvalidType(t *testing.T) {
+	backend, err := NewGRPCClientBackend(&backends.ClientConfig{BackendType: backends.GinClientBackendType})
+	assert.NotNil(t, err)
+	assert.Nil(t, backend)
+}
+
+func TestGRPCClientBackendSendRawMessage(t *testing.T) {
+	service := &serviceMock{requests: make(chan string, 1), reply: func(request string) string { return "reply:" + request }}
+	port, stop := startServer(t, service)
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	reply, err := backend.SendRawMessage(`{"payloadtype":"versionmsg"}`, true)
+	assert.Nil(t, err)
+	assert.Equal(t, `reply:{"payloadtype":"versionmsg"}`, reply)
+	assert.Equal(t, `{"payloadtype":"versionmsg"}`, <-service.requests)
+}
+
+func TestGRPCClientBackendSendMessage(t *testing.T) {
+	service := &serviceMock{requests: make(chan string, 1), reply: func(request string) string {
+		return createReply(t, "testmsg", `{"result":"ok"}`)
+	}}
+	port, stop := startServer(t, service)
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	reply, err := backend.SendMessage("testmsg", `{"msg":"hello"}`, "", true, context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"result":"ok"}`, reply)
+
+	rpcMsg, err := rpc.CreateRPCMsgFromJSON(<-service.requests)
+	assert.Nil(t, err)
+	assert.Equal(t, "testmsg", rpcMsg.PayloadType)
+	assert.Equal(t, `{"msg":"hello"}`, rpcMsg.DecodePayload())
+}
+
+func TestGRPCClientBackendSendMessageWithError(t *testing.T) {
+	service := &serviceMock{requests: make(chan string, 1), reply: func(request string) string {
+		failureJSON, _ := core.CreateFailure(404, "not found").ToJSON()
+		rpcReplyMsg, _ := rpc.CreateRPCErrorReplyMsg(rpc.ErrorPayloadType, failureJSON)
+		jsonString, _ := rpcReplyMsg.ToJSON()
+		return jsonString
+	}}
+	port, stop := startServer(t, service)
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	_, err := backend.SendMessage("testmsg", "{}", "", true, context.Background())
+	assert.NotNil(t, err)
+	coloniesErr, ok := err.(*core.ColoniesError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, coloniesErr.Status)
+	assert.Equal(t, "not found", coloniesErr.Message)
+}
+
+func TestGRPCClientBackendSendMessageNetworkError(t *testing.T) {
+	backend := createBackend(t, 1)
+	defer backend.Close()
+
+	_, err := backend.SendMessage("testmsg", "{}", "", true, context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestGRPCClientBackendCheckHealth(t *testing.T) {
+	port, stop := startServer(t, &serviceMock{})
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	assert.Nil(t, backend.CheckHealth())
+}
+
+func TestGRPCClientBackendEstablishRealtimeConn(t *testing.T) {
+	service := &serviceMock{requests: make(chan string, 1), stream: []string{"msg1", "msg2"}}
+	port, stop := startServer(t, service)
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	conn, err := backend.EstablishRealtimeConn(`{"payloadtype":"subscribeprocessesmsg"}`)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	msgType, data, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, backends.TextMessage, msgType)
+	assert.Equal(t, "msg1", string(data))
+
+	_, data, err = conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "msg2", string(data))
+
+	// The stream ends when the server closes the subscription
+	_, _, err = conn.ReadMessage()
+	assert.Equal(t, io.EOF, err)
+
+	assert.Equal(t, `{"payloadtype":"subscribeprocessesmsg"}`, <-service.requests)
+	assert.NotNil(t, conn.WriteMessage(backends.TextMessage, []byte("not supported")))
+}
+
+func TestGRPCClientBackendReadLimit(t *testing.T) {
+	service := &serviceMock{requests: make(chan string, 1), stream: []string{"0123456789"}}
+	port, stop := startServer(t, service)
+	defer stop()
+
+	backend := createBackend(t, port)
+	defer backend.Close()
+
+	conn, err := backend.EstablishRealtimeConn("{}")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	conn.SetReadLimit(5)
+	_, _, err = conn.ReadMessage()
+	assert.NotNil(t, err)
+}
+
+func TestGRPCClientBackendFactory(t *testing.T) {
+	factory := GetGRPCClientBackendFactory()
+	assert.Equal(t, backends.GRPCClientBackendType, factory.GetBackendType())
+
+	backend, err := factory.CreateBackend(&backends.ClientConfig{BackendType: backends.GRPCClientBackendType, Host: "localhost", Port: 50051, Insecure: true})
+	assert.Nil(t, err)
+	assert.Nil(t, backend.Close())
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/client/backends"
+	"google.golang.org/grpc"
+)
+
+// StreamRealtimeConnection wraps a Subscribe stream to implement RealtimeConnection,
+// the stream ends with io.EOF when the server closes the subscription
+type StreamRealtimeConnection struct {
+	stream    grpc.ClientStream
+	cancel    context.CancelFunc
+	readLimit int64
+}
+
+// NewStreamRealtimeConnection creates a new stream based realtime connection
+func NewStreamRealtimeConnection(stream grpc.ClientStream, cancel context.CancelFunc) *StreamRealtimeConnection {
+	return &StreamRealtimeConnection{
+		stream: stream,
+		cancel: cancel,
+	}
+}
+
+// WriteMessage is not supported, subscriptions are server-streaming
+func (s *StreamRealtimeConnection) WriteMessage(messageType int, data []byte) error {
+	return errors.New("grpc subscriptions are server-streaming, writing is not supported")
+}
+
+// ReadMessage reads the next message from the stream
+func (s *StreamRealtimeConnection) ReadMessage() (messageType int, data []byte, err error) {
+	envelope := &grpcbackend.Envelope{}
+	err = s.stream.RecvMsg(envelope)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if s.readLimit > 0 && int64(len(envelope.Data)) > s.readLimit {
+		return 0, nil, errors.New("message exceeds read limit of " + strconv.FormatInt(s.readLimit, 10) + " bytes")
+	}
+
+	return backends.TextMessage, envelope.Data, nil
+}
+
+// Close cancels the stream
+func (s *StreamRealtimeConnection) Close() error {
+	s.cancel()
+	return nil
+}
+
+// SetReadLimit sets the maximum size for incoming messages
+func (s *StreamRealtimeConnection) SetReadLimit(limit int64) {
+	s.readLimit = limit
+}
+
+// Compile-time check that StreamRealtimeConnection implements RealtimeConnection
+var _ backends.RealtimeConnection = (*StreamRealtimeConnection)(nil)
@@ -2,10 +2,15 @@ package client
 
 import (
 	"github.com/colonyos/colonies/pkg/client/gin"
+	"github.com/colonyos/colonies/pkg/client/grpc"
 )
 
 func init() {
 	// Register the gin backend factory when this package is imported
 	ginFactory := gin.GetGinClientBackendFactory()
 	RegisterBackendFactory(ginFactory)
+
+	// Register the grpc backend factory
+	grpcFactory := grpc.GetGRPCClientBackendFactory()
+	RegisterBackendFactory(grpcFactory)
 }
@@ -22,6 +22,22 @@ func (client *ColoniesClient) AddLog(processID string, logmsg string, prvKey str
 	return nil
 }
 
+// AddStructuredLog adds a log entry with a level, an output stream and key/value fields to a process
+func (client *ColoniesClient) AddStructuredLog(processID string, level string, stream string, fields map[string]string, logmsg string, prvKey string) error {
+	msg := rpc.CreateAddStructuredLogMsg(processID, level, stream, fields, logmsg)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.AddLogPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // AddLogToExecutor adds a log entry for an executor without requiring a process context.
 // This is useful for executor startup logs, background operations, and diagnostics.
 func (client *ColoniesClient) AddLogToExecutor(colonyName, executorName, logmsg, prvKey string) error {
@@ -89,6 +105,110 @@ func (client *ColoniesClient) GetLogsByProcessLatest(colonyName string, processI
 	return core.ConvertJSONToLogArray(respBodyString)
 }
 
+// GetLogs returns logs for a process, or for an executor if filter.ExecutorName is set, matching
+// the level, stream, field and time range given in filter
+func (client *ColoniesClient) GetLogs(filter *core.LogFilter, prvKey string) ([]*core.Log, error) {
+	msg := rpc.CreateGetLogsMsg(filter.ColonyName, filter.ProcessID, filter.Count, filter.Since)
+	msg.ExecutorName = filter.ExecutorName
+	msg.Until = filter.Until
+	msg.Latest = filter.Latest
+	msg.Level = filter.Level
+	msg.Stream = filter.Stream
+	msg.Fields = filter.Fields
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return []*core.Log{}, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetLogsPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return []*core.Log{}, err
+	}
+
+	return core.ConvertJSONToLogArray(respBodyString)
+}
+
+// SubscribeProcessLogs streams logs added to a process after since (unix nano)
+func (client *ColoniesClient) SubscribeProcessLogs(colonyName string, processID string, since int64, timeout int, prvKey string) (*LogSubscription, error) {
+	return client.SubscribeLogs(rpc.CreateSubscribeLogsMsg(colonyName, processID, "", "", since, timeout), prvKey)
+}
+
+// SubscribeExecutorLogs streams logs added by an executor after since (unix nano)
+func (client *ColoniesClient) SubscribeExecutorLogs(colonyName string, executorName string, since int64, timeout int, prvKey string) (*LogSubscription, error) {
+	return client.SubscribeLogs(rpc.CreateSubscribeLogsMsg(colonyName, "", executorName, "", since, timeout), prvKey)
+}
+
+// SubscribeProcessGraphLogs streams logs added to any process in a process graph after since (unix nano)
+func (client *ColoniesClient) SubscribeProcessGraphLogs(colonyName string, processGraphID string, since int64, timeout int, prvKey string) (*LogSubscription, error) {
+	return client.SubscribeLogs(rpc.CreateSubscribeLogsMsg(colonyName, "", "", processGraphID, since, timeout), prvKey)
+}
+
+// SubscribeLogs streams stored log entries selected by msg, followed by new entries as they
+// are added. The subscription ends after msg.Timeout seconds.
+func (client *ColoniesClient) SubscribeLogs(msg *rpc.SubscribeLogsMsg, prvKey string) (*LogSubscription, error) {
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeLogsPayloadType, jsonString, prvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonString, err = rpcMsg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.establishRealtimeConn(jsonString)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := createLogSubscription(conn)
+	go func(subscription *LogSubscription) {
+		defer close(subscription.LogsChan)
+		for {
+			_, jsonBytes, err := subscription.conn.ReadMessage()
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			if rpcReplyMsg.Error {
+				failure, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+				if err != nil {
+					subscription.ErrChan <- err
+					return
+				}
+				subscription.ErrChan <- &core.ColoniesError{Status: failure.Status, Message: failure.Message}
+				return
+			}
+
+			logs, err := core.ConvertJSONToLogArray(rpcReplyMsg.DecodePayload())
+			if err != nil {
+				subscription.ErrChan <- err
+				return
+			}
+
+			subscription.LogsChan <- logs
+			if len(logs) == 0 {
+				subscription.conn.Close()
+				return
+			}
+		}
+	}(subscription)
+
+	return subscription, nil
+}
+
 func (client *ColoniesClient) SearchLogs(colonyName, text string, days int, count int, prvKey string) ([]*core.Log, error) {
 	msg := rpc.CreateSearchLogsMsg(colonyName, text, days, count)
 	msg.ColonyName = colonyName
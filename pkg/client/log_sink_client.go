@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+)
+
+func (client *ColoniesClient) AddLogSink(sink *core.LogSink, prvKey string) (*core.LogSink, error) {
+	msg := rpc.CreateAddLogSinkMsg(sink)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddLogSinkPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	addedSink, err := core.ConvertJSONToLogSink(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return addedSink, nil
+}
+
+func (client *ColoniesClient) GetLogSinks(colonyName string, prvKey string) ([]*core.LogSink, error) {
+	msg := rpc.CreateGetLogSinksMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetLogSinksPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	sinks, err := core.ConvertJSONToLogSinkArray(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return sinks, nil
+}
+
+func (client *ColoniesClient) RemoveLogSink(colonyName string, name string, prvKey string) error {
+	msg := rpc.CreateRemoveLogSinkMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveLogSinkPayloadType, jsonString, prvKey, false, context.TODO())
+	return err
+}
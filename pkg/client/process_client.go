@@ -247,6 +247,22 @@ func (client *ColoniesClient) Fail(processID string, errs []string, prvKey strin
 	return nil
 }
 
+// UnassignProcess moves a process assigned to the calling executor back to the queue
+func (client *ColoniesClient) UnassignProcess(processID string, prvKey string) error {
+	msg := rpc.CreateUnassignProcessMsg(processID)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.UnassignProcessPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (client *ColoniesClient) SetOutput(processID string, output []interface{}, prvKey string) error {
 	msg := rpc.CreateSetOutputMsg(processID, output)
 	jsonString, err := msg.ToJSON()
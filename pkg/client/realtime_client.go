@@ -2,24 +2,50 @@ package client
 
 import (
 	"errors"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/client/backends"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/rpc"
 )
 
 func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
-	msg := rpc.CreateSubscribeProcessesMsg(colonyName, executorType, state, timeout)
-	jsonString, err := msg.ToJSON()
-	if err != nil {
-		return nil, err
-	}
+	return client.subscribeProcesses(timeout, func(timeout int) (string, error) {
+		msg := rpc.CreateSubscribeProcessesMsg(colonyName, executorType, state, timeout)
+		jsonString, err := msg.ToJSON()
+		if err != nil {
+			return "", err
+		}
+
+		return createRealtimeRPCMsg(rpc.SubscribeProcessesPayloadType, jsonString, prvKey)
+	})
+}
+
+func (client *ColoniesClient) SubscribeProcess(colonyName string, processID string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
+	return client.subscribeProcesses(timeout, func(timeout int) (string, error) {
+		msg := rpc.CreateSubscribeProcessMsg(colonyName, processID, executorType, state, timeout)
+		jsonString, err := msg.ToJSON()
+		if err != nil {
+			return "", err
+		}
 
-	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeProcessesPayloadType, jsonString, prvKey)
+		return createRealtimeRPCMsg(rpc.SubscribeProcessPayloadType, jsonString, prvKey)
+	})
+}
+
+func createRealtimeRPCMsg(payloadType string, jsonString string, prvKey string) (string, error) {
+	rpcMsg, err := rpc.CreateRPCMsg(payloadType, jsonString, prvKey)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	jsonString, err = rpcMsg.ToJSON()
+	return rpcMsg.ToJSON()
+}
+
+// subscribeProcesses establishes a subscription, createMsg creates a signed subscribe message with
+// the given timeout and is called again with the remaining timeout if the subscription is re-established
+func (client *ColoniesClient) subscribeProcesses(timeout int, createMsg func(timeout int) (string, error)) (*ProcessSubscription, error) {
+	jsonString, err := createMsg(timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -29,11 +55,18 @@ func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType
 		return nil, err
 	}
 
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
 	subscription := createProcessSubscription(conn)
 	go func(subscription *ProcessSubscription) {
 		for {
-			_, jsonBytes, err := subscription.conn.ReadMessage()
+			_, jsonBytes, err := subscription.getConn().ReadMessage()
 			if err != nil {
+				if subscription.isClosed() {
+					return
+				}
+				if client.resubscribe(subscription, deadline, createMsg) {
+					continue
+				}
 				subscription.ErrChan <- err
 				continue
 			}
@@ -65,60 +98,30 @@ func (client *ColoniesClient) SubscribeProcesses(colonyName string, executorType
 	return subscription, nil
 }
 
-func (client *ColoniesClient) SubscribeProcess(colonyName string, processID string, executorType string, state int, timeout int, prvKey string) (*ProcessSubscription, error) {
-	msg := rpc.CreateSubscribeProcessMsg(colonyName, processID, executorType, state, timeout)
-	jsonString, err := msg.ToJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	rpcMsg, err := rpc.CreateRPCMsg(rpc.SubscribeProcessPayloadType, jsonString, prvKey)
-	if err != nil {
-		return nil, err
-	}
+// resubscribe re-establishes a lost subscription with the remaining timeout, retrying with backoff.
+// Returns false if the subscription has timed out, has been closed or could not be re-established.
+func (client *ColoniesClient) resubscribe(subscription *ProcessSubscription, deadline time.Time, createMsg func(timeout int) (string, error)) bool {
+	policy := backends.DefaultRetryPolicy()
+	for retry := 0; retry <= policy.MaxRetries; retry++ {
+		time.Sleep(policy.Backoff(retry))
 
-	jsonString, err = rpcMsg.ToJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	conn, err := client.establishRealtimeConn(jsonString)
-	if err != nil {
-		return nil, err
-	}
-
-	subscription := createProcessSubscription(conn)
-	go func(subscription *ProcessSubscription) {
-		for {
-			_, jsonBytes, err := subscription.conn.ReadMessage()
-			if err != nil {
-				subscription.ErrChan <- err
-				continue
-			}
-
-			rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(jsonBytes))
-			if err != nil {
-				subscription.ErrChan <- err
-				continue
-			}
-
-			if rpcReplyMsg.Error {
-				failureMsg, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
-				if err != nil {
-					subscription.ErrChan <- err
-				}
-				subscription.ErrChan <- errors.New(failureMsg.Message)
-			}
+		remaining := int(time.Until(deadline).Seconds())
+		if remaining < 1 || subscription.isClosed() {
+			return false
+		}
 
-			process, err := core.ConvertJSONToProcess(rpcReplyMsg.DecodePayload())
-			if err != nil {
-				subscription.ErrChan <- err
-				continue
-			}
+		jsonString, err := createMsg(remaining)
+		if err != nil {
+			return false
+		}
 
-			subscription.ProcessChan <- process
+		conn, err := client.establishRealtimeConn(jsonString)
+		if err != nil {
+			continue
 		}
-	}(subscription)
 
-	return subscription, nil
-}
\ No newline at end of file
+		return subscription.setConn(conn)
+	}
+
+	return false
+}
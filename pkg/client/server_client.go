@@ -64,7 +64,7 @@ func (client *ColoniesClient) GetServerInfo() (*core.ServerInfo, error) {
 }
 
 func (client *ColoniesClient) CheckHealth() error {
-	return client.backend.CheckHealth()
+	return client.getBackend().CheckHealth()
 }
 
 func (client *ColoniesClient) GetClusterInfo(prvKey string) (*cluster.Config, error) {
@@ -130,4 +130,49 @@ func (client *ColoniesClient) RemoveAllSnapshots(colonyName string, prvKey strin
 	}
 
 	return err
-}
\ No newline at end of file
+}
+
+func (client *ColoniesClient) DiffSnapshots(colonyName string, from string, to string, prvKey string) (*core.SnapshotDiff, error) {
+	msg := rpc.CreateDiffSnapshotsMsg(colonyName, from, to)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.DiffSnapshotsPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToSnapshotDiff(respBodyString)
+}
+
+func (client *ColoniesClient) RestoreSnapshot(colonyName string, name string, prune bool, prvKey string) (*core.SnapshotDiff, error) {
+	msg := rpc.CreateRestoreSnapshotMsg(colonyName, name, prune)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.RestoreSnapshotPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToSnapshotDiff(respBodyString)
+}
+
+func (client *ColoniesClient) CloneSnapshot(colonyName string, name string, label string, prvKey string) (*core.SnapshotDiff, error) {
+	msg := rpc.CreateCloneSnapshotMsg(colonyName, name, label)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.CloneSnapshotPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToSnapshotDiff(respBodyString)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+)
+
+func (client *ColoniesClient) AddStorageBackend(backend *core.StorageBackend, prvKey string) (*core.StorageBackend, error) {
+	msg := rpc.CreateAddStorageBackendMsg(backend)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddStorageBackendPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	addedBackend, err := core.ConvertJSONToStorageBackend(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return addedBackend, nil
+}
+
+func (client *ColoniesClient) GetStorageBackends(colonyName string, prvKey string) ([]*core.StorageBackend, error) {
+	msg := rpc.CreateGetStorageBackendsMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetStorageBackendsPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	backends, err := core.ConvertJSONToStorageBackendArray(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return backends, nil
+}
+
+func (client *ColoniesClient) RemoveStorageBackend(colonyName string, name string, prvKey string) error {
+	msg := rpc.CreateRemoveStorageBackendMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveStorageBackendPayloadType, jsonString, prvKey, false, context.TODO())
+	return err
+}
+
+// PresignFile returns a short-lived URL for an object in a storage backend. GET and DELETE URLs refer to
+// the file with fileID, PUT URLs to a new object in the named backend.
+func (client *ColoniesClient) PresignFile(colonyName string, method string, fileID string, backend string, prvKey string) (*core.PresignedURL, error) {
+	msg := rpc.CreatePresignFileMsg(colonyName, method, fileID, backend)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.PresignFilePayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ConvertJSONToPresignedURL(respBodyString)
+}
@@ -1,14 +1,21 @@
 package client
 
 import (
+	"sync"
+
+	"github.com/colonyos/colonies/pkg/channel"
 	"github.com/colonyos/colonies/pkg/client/backends"
 	"github.com/colonyos/colonies/pkg/core"
 )
 
+// ProcessSubscription delivers processes as they change state. If the connection is lost before
+// the subscription has timed out, it is re-established, possibly on another cluster node.
 type ProcessSubscription struct {
 	ProcessChan chan *core.Process
 	ErrChan     chan error
 	conn        backends.RealtimeConnection
+	closed      bool
+	mutex       sync.Mutex
 }
 
 func createProcessSubscription(conn backends.RealtimeConnection) *ProcessSubscription {
@@ -20,6 +27,86 @@ func createProcessSubscription(conn backends.RealtimeConnection) *ProcessSubscri
 	return subscription
 }
 
+func (subscription *ProcessSubscription) getConn() backends.RealtimeConnection {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	return subscription.conn
+}
+
+// setConn replaces the connection and closes the lost connection, returns false and closes conn if the
+// subscription has been closed
+func (subscription *ProcessSubscription) setConn(conn backends.RealtimeConnection) bool {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	if subscription.closed {
+		conn.Close()
+		return false
+	}
+
+	if subscription.conn != nil {
+		subscription.conn.Close()
+	}
+
+	subscription.conn = conn
+	return true
+}
+
+func (subscription *ProcessSubscription) isClosed() bool {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	return subscription.closed
+}
+
 func (subscription *ProcessSubscription) Close() error {
+	subscription.mutex.Lock()
+	defer subscription.mutex.Unlock()
+
+	subscription.closed = true
+	return subscription.conn.Close()
+}
+
+// ChannelSubscription delivers channel entries as they are appended. An empty slice is
+// sent on EntriesChan when the subscription times out, after which the subscription is closed.
+type ChannelSubscription struct {
+	EntriesChan chan []*channel.MsgEntry
+	ErrChan     chan error
+	conn        backends.RealtimeConnection
+}
+
+func createChannelSubscription(conn backends.RealtimeConnection) *ChannelSubscription {
+	subscription := &ChannelSubscription{}
+	subscription.EntriesChan = make(chan []*channel.MsgEntry)
+	subscription.ErrChan = make(chan error, 1)
+	subscription.conn = conn
+
+	return subscription
+}
+
+func (subscription *ChannelSubscription) Close() error {
+	return subscription.conn.Close()
+}
+
+// LogSubscription delivers log entries as they are added. Stored entries are delivered first.
+// An empty slice is sent on LogsChan when the subscription times out, after which the
+// subscription is closed.
+type LogSubscription struct {
+	LogsChan chan []*core.Log
+	ErrChan  chan error
+	conn     backends.RealtimeConnection
+}
+
+func createLogSubscription(conn backends.RealtimeConnection) *LogSubscription {
+	subscription := &LogSubscription{}
+	subscription.LogsChan = make(chan []*core.Log)
+	subscription.ErrChan = make(chan error, 1)
+	subscription.conn = conn
+
+	return subscription
+}
+
+func (subscription *LogSubscription) Close() error {
 	return subscription.conn.Close()
 }
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+)
+
+func (client *ColoniesClient) AddWebhook(webhook *core.Webhook, prvKey string) (*core.Webhook, error) {
+	msg := rpc.CreateAddWebhookMsg(webhook)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.AddWebhookPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	addedWebhook, err := core.ConvertJSONToWebhook(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return addedWebhook, nil
+}
+
+func (client *ColoniesClient) GetWebhook(colonyName string, name string, prvKey string) (*core.Webhook, error) {
+	msg := rpc.CreateGetWebhookMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetWebhookPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := core.ConvertJSONToWebhook(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (client *ColoniesClient) GetWebhooks(colonyName string, prvKey string) ([]*core.Webhook, error) {
+	msg := rpc.CreateGetWebhooksMsg(colonyName)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetWebhooksPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := core.ConvertJSONToWebhookArray(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (client *ColoniesClient) RemoveWebhook(colonyName string, name string, prvKey string) error {
+	msg := rpc.CreateRemoveWebhookMsg(colonyName, name)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.sendMessage(rpc.RemoveWebhookPayloadType, jsonString, prvKey, false, context.TODO())
+	return err
+}
+
+func (client *ColoniesClient) GetWebhookDeliveries(colonyName string, name string, count int, prvKey string) ([]*core.WebhookDelivery, error) {
+	msg := rpc.CreateGetWebhookDeliveriesMsg(colonyName, name, count)
+	jsonString, err := msg.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	respBodyString, err := client.sendMessage(rpc.GetWebhookDeliveriesPayloadType, jsonString, prvKey, false, context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := core.ConvertJSONToWebhookDeliveryArray(respBodyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -9,6 +9,7 @@ type Node struct {
 	EtcdPeerPort   int    `json:"peerport"` // Etcd default 2380
 	RelayPort      int    `json:"relayport"`
 	APIPort        int    `json:"apiport"`
+	GRPCPort       int    `json:"grpcport"` // 0 if gRPC is disabled
 }
 
 func (node *Node) Equals(node2 *Node) bool {
@@ -33,6 +34,10 @@ func (node *Node) Equals(node2 *Node) bool {
 		return false
 	}
 
+	if node.GRPCPort != node2.GRPCPort {
+		return false
+	}
+
 	return true
 }
 
@@ -36,4 +36,32 @@ const CHANNEL_MAX_LOG_ENTRIES = 10000 // Maximum entries per channel, oldest rem
 const CHANNEL_MAX_CHANNELS_PER_PROCESS = 100 // Maximum channels a single process can have
 
 // Executor Cleanup - Configuration for automatic stale executor removal
-const DEFAULT_STALE_EXECUTOR_DURATION = 600 // Default duration in seconds (10 minutes) before an executor is considered stale
\ No newline at end of file
+const DEFAULT_STALE_EXECUTOR_DURATION = 600 // Default duration in seconds (10 minutes) before an executor is considered stale
+
+// Webhooks - Configuration for outbound webhook deliveries
+const WEBHOOK_WORKERS = 4              // Number of concurrent webhook delivery workers per server
+const WEBHOOK_QUEUE_SIZE = 1000        // Number of events buffered before new events are dropped
+const WEBHOOK_TIMEOUT = 10             // Timeout in seconds for a single webhook HTTP request
+const WEBHOOK_DEFAULT_MAX_RETRIES = 5  // Default number of retries if a webhook does not specify MaxRetries
+const WEBHOOK_INITIAL_BACKOFF = 1000   // Initial retry backoff in milliseconds, doubled after every failed attempt
+const WEBHOOK_MAX_BACKOFF = 60000      // Maximum retry backoff in milliseconds
+const WEBHOOK_MAX_DELIVERY_COUNT = 100 // Maximum number of delivery records that can be requested at once
+
+// Function args - Configuration for validating submitted args against registered arg schemas
+const FUNCTION_SCHEMA_CACHE_TTL = 10 // Number of seconds a server remembers that a colony has no functions with arg schemas
+
+// Storage backends - Configuration for presigned object URLs
+const STORAGE_PRESIGN_EXPIRY = 900 // Number of seconds a presigned URL is valid
+
+// Log sinks - Configuration for forwarding logs to external systems
+const LOG_SINK_QUEUE_SIZE = 10000            // Number of log entries buffered per server, and per sink, before new entries are dropped
+const LOG_SINK_DEFAULT_BATCH_SIZE = 100      // Default number of log entries sent in one batch
+const LOG_SINK_MAX_BATCH_SIZE = 5000         // Maximum number of log entries sent in one batch
+const LOG_SINK_DEFAULT_FLUSH_INTERVAL = 1000 // Default time in milliseconds before a partial batch is sent
+const LOG_SINK_DEFAULT_MAX_SIZE = 104857600  // Default size in bytes before a file sink is rotated (100 MiB)
+const LOG_SINK_DEFAULT_MAX_FILES = 5         // Default number of rotated files kept by a file sink
+const LOG_SINK_TIMEOUT = 10                  // Timeout in seconds for a single HTTP request or syslog write
+const LOG_SINK_MAX_RETRIES = 5               // Number of retries before a batch is dropped
+const LOG_SINK_INITIAL_BACKOFF = 1000        // Initial retry backoff in milliseconds, doubled after every failed attempt
+const LOG_SINK_MAX_BACKOFF = 30000           // Maximum retry backoff in milliseconds
+const LOG_SINK_RELOAD_INTERVAL = 10          // Number of seconds sink registrations are cached before being reloaded from the database
@@ -15,6 +15,7 @@ import (
 type Blueprint struct {
 	ID       string                 `json:"blueprintid"`
 	Kind     string                 `json:"kind"`
+	Version  string                 `json:"version,omitempty"` // BlueprintDefinition version, empty means storage version
 	Metadata BlueprintMetadata      `json:"metadata"`
 	Spec     map[string]interface{} `json:"spec"`
 	Status   map[string]interface{} `json:"status,omitempty"`
@@ -28,10 +29,14 @@ type BlueprintMetadata struct {
 	Labels                    map[string]string `json:"labels,omitempty"`
 	Annotations               map[string]string `json:"annotations,omitempty"`
 	Generation                int64             `json:"generation,omitempty"`
+	ResourceVersion           int64             `json:"resourceVersion,omitempty"` // Incremented by the server on every write
 	CreatedAt                 time.Time         `json:"createdAt,omitempty"`
 	UpdatedAt                 time.Time         `json:"updatedAt,omitempty"`
 	LastReconciliationProcess string            `json:"lastReconciliationProcess,omitempty"`
 	LastReconciliationTime    time.Time         `json:"lastReconciliationTime,omitempty"`
+	Finalizers                []string          `json:"finalizers,omitempty"`        // Must all be removed before the blueprint is deleted
+	DeletionTimestamp         time.Time         `json:"deletionTimestamp,omitempty"` // Set by the server when removal is requested
+	OwnerReferences           []OwnerReference  `json:"ownerReferences,omitempty"`
 }
 
 // BlueprintDefinition defines a blueprint type
@@ -50,6 +55,13 @@ type BlueprintDefinitionSpec struct {
 	Scope   string                   `json:"scope"` // "Namespaced" or "Cluster"
 	Schema  *ValidationSchema        `json:"schema,omitempty"`
 	Handler HandlerSpec              `json:"handler"`
+
+	// Versions and Conversion are optional, see blueprint_version.go
+	Versions   []BlueprintDefinitionVersion `json:"versions,omitempty"`
+	Conversion *ConversionSpec              `json:"conversion,omitempty"`
+
+	// HistoryLimit is the number of history entries kept per blueprint, 0 keeps all entries
+	HistoryLimit int `json:"historyLimit,omitempty"`
 }
 
 // BlueprintDefinitionNames defines blueprint names
@@ -68,21 +80,50 @@ type HandlerSpec struct {
 	ReconcileInterval int    `json:"reconcileInterval,omitempty"`
 }
 
-// ValidationSchema defines JSON Schema validation
-type ValidationSchema struct {
-	Type       string                    `json:"type,omitempty"`
-	Properties map[string]SchemaProperty `json:"properties,omitempty"`
-	Required   []string                  `json:"required,omitempty"`
-}
+// ValidationSchema is the root schema of a BlueprintDefinition, it accepts the same keywords as a
+// nested SchemaProperty
+type ValidationSchema = SchemaProperty
 
-// SchemaProperty defines a schema property
+// SchemaProperty defines a schema property, the supported keywords are a subset of JSON Schema
+// draft 2020-12, see docs/SchemaValidation.md
 type SchemaProperty struct {
-	Type        string                    `json:"type,omitempty"`
-	Description string                    `json:"description,omitempty"`
-	Enum        []interface{}             `json:"enum,omitempty"`
-	Default     interface{}               `json:"default,omitempty"`
-	Properties  map[string]SchemaProperty `json:"properties,omitempty"`
-	Items       *SchemaProperty           `json:"items,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Const       interface{}   `json:"const,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Format      string        `json:"format,omitempty"`
+
+	// Numbers
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	// Strings
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	// Objects
+	Properties           map[string]SchemaProperty `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *AdditionalProperties     `json:"additionalProperties,omitempty"`
+	MinProperties        *int                      `json:"minProperties,omitempty"`
+	MaxProperties        *int                      `json:"maxProperties,omitempty"`
+
+	// Arrays
+	Items       *SchemaProperty `json:"items,omitempty"`
+	MinItems    *int            `json:"minItems,omitempty"`
+	MaxItems    *int            `json:"maxItems,omitempty"`
+	UniqueItems bool            `json:"uniqueItems,omitempty"`
+
+	// Composition
+	AllOf []SchemaProperty `json:"allOf,omitempty"`
+	AnyOf []SchemaProperty `json:"anyOf,omitempty"`
+	OneOf []SchemaProperty `json:"oneOf,omitempty"`
+	Not   *SchemaProperty  `json:"not,omitempty"`
 }
 
 // Reconciliation contains the old and new state of a blueprint with computed diff
@@ -244,136 +285,21 @@ func (r *Blueprint) ValidateAgainstSD(sd *BlueprintDefinition) error {
 		return fmt.Errorf("kind mismatch: blueprint has '%s' but BlueprintDefinition defines '%s'", r.Kind, sd.Spec.Names.Kind)
 	}
 
-	// Validate against schema if one is defined
-	if sd.Spec.Schema != nil {
-		if err := validateAgainstSchema(r.Spec, sd.Spec.Schema); err != nil {
-			return fmt.Errorf("spec validation failed: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// validateAgainstSchema validates data against a JSON schema
-func validateAgainstSchema(data map[string]interface{}, schema *ValidationSchema) error {
-	// Check required fields
-	for _, required := range schema.Required {
-		if _, ok := data[required]; !ok {
-			return fmt.Errorf("required field '%s' is missing", required)
-		}
-	}
-
-	// Validate each property
-	for key, value := range data {
-		if propSchema, ok := schema.Properties[key]; ok {
-			if err := validateValue(key, value, &propSchema); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-// validateValue validates a single value against its schema property
-func validateValue(key string, value interface{}, prop *SchemaProperty) error {
-	// Type validation
-	if prop.Type != "" {
-		if err := validateType(key, value, prop.Type); err != nil {
-			return err
-		}
+	version := sd.GetVersion(r.Version)
+	if version == nil || !version.Served {
+		return fmt.Errorf("version '%s' is not served by BlueprintDefinition '%s'", r.Version, sd.Metadata.Name)
 	}
 
-	// Enum validation
-	if len(prop.Enum) > 0 {
-		if err := validateEnum(key, value, prop.Enum); err != nil {
-			return err
-		}
-	}
-
-	// Nested object validation
-	if prop.Type == "object" && prop.Properties != nil {
-		objMap, ok := value.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("field '%s' must be an object", key)
-		}
-		for nestedKey, nestedValue := range objMap {
-			if nestedProp, ok := prop.Properties[nestedKey]; ok {
-				if err := validateValue(key+"."+nestedKey, nestedValue, &nestedProp); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	// Array validation
-	if prop.Type == "array" && prop.Items != nil {
-		arr, ok := value.([]interface{})
-		if !ok {
-			return fmt.Errorf("field '%s' must be an array", key)
-		}
-		for i, item := range arr {
-			if err := validateValue(fmt.Sprintf("%s[%d]", key, i), item, prop.Items); err != nil {
-				return err
-			}
+	// Validate against schema if one is defined
+	if version.Schema != nil {
+		if err := ValidateSpec(r.Spec, version.Schema); err != nil {
+			return fmt.Errorf("spec validation failed: %w", err)
 		}
 	}
 
 	return nil
 }
 
-// validateType checks if value matches the expected type
-func validateType(key string, value interface{}, expectedType string) error {
-	switch expectedType {
-	case "string":
-		if _, ok := value.(string); !ok {
-			return fmt.Errorf("field '%s' must be a string", key)
-		}
-	case "integer":
-		// JSON unmarshaling produces float64 for numbers
-		switch v := value.(type) {
-		case float64:
-			if v != float64(int64(v)) {
-				return fmt.Errorf("field '%s' must be an integer", key)
-			}
-		case int, int32, int64:
-			// Already an integer
-		default:
-			return fmt.Errorf("field '%s' must be an integer", key)
-		}
-	case "number":
-		switch value.(type) {
-		case float64, float32, int, int32, int64:
-			// Valid number types
-		default:
-			return fmt.Errorf("field '%s' must be a number", key)
-		}
-	case "boolean":
-		if _, ok := value.(bool); !ok {
-			return fmt.Errorf("field '%s' must be a boolean", key)
-		}
-	case "object":
-		if _, ok := value.(map[string]interface{}); !ok {
-			return fmt.Errorf("field '%s' must be an object", key)
-		}
-	case "array":
-		if _, ok := value.([]interface{}); !ok {
-			return fmt.Errorf("field '%s' must be an array", key)
-		}
-	}
-	return nil
-}
-
-// validateEnum checks if value is in the allowed enum values
-func validateEnum(key string, value interface{}, enum []interface{}) error {
-	for _, allowed := range enum {
-		if value == allowed {
-			return nil
-		}
-	}
-	return fmt.Errorf("field '%s' has invalid value '%v', must be one of %v", key, value, enum)
-}
-
 // Validate validates the BlueprintDefinition
 func (sd *BlueprintDefinition) Validate() error {
 	if sd.Kind != "BlueprintDefinition" {
@@ -382,7 +308,7 @@ func (sd *BlueprintDefinition) Validate() error {
 	if sd.Spec.Group == "" {
 		return fmt.Errorf("spec.group is required")
 	}
-	if sd.Spec.Version == "" {
+	if sd.Spec.Version == "" && len(sd.Spec.Versions) == 0 {
 		return fmt.Errorf("spec.version is required")
 	}
 	if sd.Spec.Names.Kind == "" {
@@ -403,6 +329,14 @@ func (sd *BlueprintDefinition) Validate() error {
 	if sd.Metadata.Name == "" {
 		return fmt.Errorf("metadata.name is required")
 	}
+	if sd.Spec.Schema != nil {
+		if err := sd.Spec.Schema.Check(); err != nil {
+			return fmt.Errorf("spec.schema is invalid: %w", err)
+		}
+	}
+	if err := sd.ValidateVersions(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -687,97 +621,7 @@ func ValidateBlueprintAgainstSchema(blueprint *Blueprint, schema *ValidationSche
 		return nil // No schema means no validation
 	}
 
-	// Check required fields
-	for _, requiredField := range schema.Required {
-		if _, ok := blueprint.Spec[requiredField]; !ok {
-			return fmt.Errorf("required field '%s' is missing", requiredField)
-		}
-	}
-
-	// Validate each field in the spec
-	for fieldName, fieldValue := range blueprint.Spec {
-		if schemaProp, ok := schema.Properties[fieldName]; ok {
-			if err := validateField(fieldName, fieldValue, &schemaProp); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-// validateField validates a single field against its schema property
-func validateField(fieldName string, value interface{}, prop *SchemaProperty) error {
-	if prop == nil {
-		return nil
-	}
-
-	// Type validation
-	switch prop.Type {
-	case "string":
-		if _, ok := value.(string); !ok {
-			return fmt.Errorf("field '%s' must be a string, got %T", fieldName, value)
-		}
-	case "number":
-		switch value.(type) {
-		case float64, float32, int, int32, int64:
-			// Valid number types
-		default:
-			return fmt.Errorf("field '%s' must be a number, got %T", fieldName, value)
-		}
-	case "boolean":
-		if _, ok := value.(bool); !ok {
-			return fmt.Errorf("field '%s' must be a boolean, got %T", fieldName, value)
-		}
-	case "object":
-		if _, ok := value.(map[string]interface{}); !ok {
-			return fmt.Errorf("field '%s' must be an object, got %T", fieldName, value)
-		}
-	case "array":
-		arr, ok := value.([]interface{})
-		if !ok {
-			return fmt.Errorf("field '%s' must be an array, got %T", fieldName, value)
-		}
-		// Validate array items if schema is provided
-		if prop.Items != nil {
-			for i, item := range arr {
-				if err := validateField(fmt.Sprintf("%s[%d]", fieldName, i), item, prop.Items); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	// Enum validation
-	if len(prop.Enum) > 0 {
-		found := false
-		for _, enumValue := range prop.Enum {
-			if value == enumValue {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("field '%s' must be one of %v, got %v", fieldName, prop.Enum, value)
-		}
-	}
-
-	// Validate nested object properties
-	if prop.Type == "object" && len(prop.Properties) > 0 {
-		objValue, ok := value.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("field '%s' must be an object", fieldName)
-		}
-		for nestedName, nestedValue := range objValue {
-			if nestedProp, ok := prop.Properties[nestedName]; ok {
-				if err := validateField(fmt.Sprintf("%s.%s", fieldName, nestedName), nestedValue, &nestedProp); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	return nil
+	return ValidateSpec(blueprint.Spec, schema)
 }
 
 // BlueprintHistory represents a historical snapshot of a blueprint
@@ -792,7 +636,7 @@ type BlueprintHistory struct {
 	Status     map[string]interface{} `json:"status,omitempty"`
 	Timestamp  time.Time              `json:"timestamp"`
 	ChangedBy  string                 `json:"changedby"` // Executor or User ID
-	ChangeType string                 `json:"changetype"` // "create", "update", "delete"
+	ChangeType string                 `json:"changetype"` // "create", "update", "rollback", "delete"
 }
 
 // CreateBlueprintHistory creates a new BlueprintHistory from a Blueprint
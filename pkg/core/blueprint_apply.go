@@ -0,0 +1,123 @@
+package core
+
+import (
+	"encoding/json"
+)
+
+// ManagedByLabel marks blueprints created by a declarative apply, only blueprints with this label are
+// pruned when they are no longer part of the applied set
+const ManagedByLabel = "colonies.io/managed-by"
+
+// Actions in a BlueprintApplyPlan
+const (
+	ApplyCreate    = "create"
+	ApplyUpdate    = "update"
+	ApplyDelete    = "delete"
+	ApplyUnchanged = "unchanged"
+)
+
+// BlueprintApplyStep is the action planned for a single blueprint
+type BlueprintApplyStep struct {
+	Action string         `json:"action"`
+	Kind   string         `json:"kind"`
+	Name   string         `json:"name"`
+	Diff   *BlueprintDiff `json:"diff,omitempty"`
+}
+
+// BlueprintApplyPlan lists the actions needed to make the blueprints in a colony match a set of desired
+// blueprints
+type BlueprintApplyPlan struct {
+	Namespace string                `json:"namespace"`
+	Steps     []*BlueprintApplyStep `json:"steps"`
+	Applied   bool                  `json:"applied"`
+}
+
+// PlanBlueprintApply compares desired blueprints with the current blueprints in a colony. Blueprints
+// that do not exist are created and blueprints whose spec, labels or annotations differ are updated.
+// Status is owned by reconcilers and is ignored. If prune is set, current blueprints labelled with
+// ManagedByLabel=managedBy that are not desired are deleted.
+func PlanBlueprintApply(namespace string, desired []*Blueprint, current []*Blueprint, prune bool, managedBy string) *BlueprintApplyPlan {
+	plan := &BlueprintApplyPlan{Namespace: namespace, Steps: []*BlueprintApplyStep{}}
+
+	currentByName := make(map[string]*Blueprint)
+	for _, blueprint := range current {
+		currentByName[blueprint.Metadata.Name] = blueprint
+	}
+
+	desiredNames := make(map[string]bool)
+	for _, blueprint := range desired {
+		desiredNames[blueprint.Metadata.Name] = true
+
+		existing, ok := currentByName[blueprint.Metadata.Name]
+		if !ok {
+			plan.Steps = append(plan.Steps, &BlueprintApplyStep{Action: ApplyCreate, Kind: blueprint.Kind, Name: blueprint.Metadata.Name})
+			continue
+		}
+
+		diff := existing.Diff(blueprint)
+		diff.StatusChanges = []FieldChange{}
+		diff.HasChanges = len(diff.SpecChanges) > 0 || len(diff.MetadataChanges) > 0
+
+		action := ApplyUnchanged
+		if diff.HasChanges {
+			action = ApplyUpdate
+		}
+		plan.Steps = append(plan.Steps, &BlueprintApplyStep{Action: action, Kind: blueprint.Kind, Name: blueprint.Metadata.Name, Diff: diff})
+	}
+
+	if prune && managedBy != "" {
+		for _, blueprint := range current {
+			if desiredNames[blueprint.Metadata.Name] || blueprint.IsBeingDeleted() {
+				continue
+			}
+			if blueprint.Metadata.Labels[ManagedByLabel] != managedBy {
+				continue
+			}
+			plan.Steps = append(plan.Steps, &BlueprintApplyStep{Action: ApplyDelete, Kind: blueprint.Kind, Name: blueprint.Metadata.Name})
+		}
+	}
+
+	return plan
+}
+
+// HasChanges returns true if applying the plan would create, update or delete a blueprint
+func (plan *BlueprintApplyPlan) HasChanges() bool {
+	for _, step := range plan.Steps {
+		if step.Action != ApplyUnchanged {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of steps with the given action
+func (plan *BlueprintApplyPlan) Count(action string) int {
+	count := 0
+	for _, step := range plan.Steps {
+		if step.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (plan *BlueprintApplyPlan) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(plan)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func ConvertJSONToBlueprintApplyPlan(jsonString string) (*BlueprintApplyPlan, error) {
+	var plan *BlueprintApplyPlan
+	err := json.Unmarshal([]byte(jsonString), &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanBlueprintApply(t *testing.T) {
+	web := CreateBlueprint("Deployment", "web", "test-colony")
+	web.SetSpec("image", "nginx:1.0")
+	web.SetStatus("ready", true)
+	db := CreateBlueprint("Deployment", "db", "test-colony")
+	db.SetSpec("image", "postgres")
+	old := CreateBlueprint("Deployment", "old", "test-colony")
+	old.Metadata.Labels = map[string]string{ManagedByLabel: "cli"}
+	manual := CreateBlueprint("Deployment", "manual", "test-colony")
+	current := []*Blueprint{web, db, old, manual}
+
+	desiredWeb := CreateBlueprint("Deployment", "web", "test-colony")
+	desiredWeb.SetSpec("image", "nginx:2.0")
+	desiredDB := CreateBlueprint("Deployment", "db", "test-colony")
+	desiredDB.SetSpec("image", "postgres")
+	cache := CreateBlueprint("Deployment", "cache", "test-colony")
+	desired := []*Blueprint{desiredWeb, desiredDB, cache}
+
+	plan := PlanBlueprintApply("test-colony", desired, current, false, "cli")
+	assert.Len(t, plan.Steps, 3)
+	assert.Equal(t, ApplyUpdate, plan.Steps[0].Action)
+	assert.Len(t, plan.Steps[0].Diff.SpecChanges, 1)
+	assert.Equal(t, "spec.image", plan.Steps[0].Diff.SpecChanges[0].Path)
+	assert.Len(t, plan.Steps[0].Diff.StatusChanges, 0)
+	assert.Equal(t, ApplyUnchanged, plan.Steps[1].Action)
+	assert.Equal(t, ApplyCreate, plan.Steps[2].Action)
+	assert.Equal(t, "cache", plan.Steps[2].Name)
+	assert.True(t, plan.HasChanges())
+
+	// Only blueprints managed by the same label value are pruned
+	plan = PlanBlueprintApply("test-colony", desired, current, true, "cli")
+	assert.Len(t, plan.Steps, 4)
+	assert.Equal(t, ApplyDelete, plan.Steps[3].Action)
+	assert.Equal(t, "old", plan.Steps[3].Name)
+	assert.Equal(t, 1, plan.Count(ApplyDelete))
+
+	plan = PlanBlueprintApply("test-colony", []*Blueprint{desiredDB}, []*Blueprint{db}, true, "cli")
+	assert.False(t, plan.HasChanges())
+
+	jsonString, err := plan.ToJSON()
+	assert.Nil(t, err)
+	plan2, err := ConvertJSONToBlueprintApplyPlan(jsonString)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-colony", plan2.Namespace)
+	assert.Equal(t, ApplyUnchanged, plan2.Steps[0].Action)
+
+	_, err = ConvertJSONToBlueprintApplyPlan(jsonString + "error")
+	assert.NotNil(t, err)
+}
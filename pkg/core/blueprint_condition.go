@@ -0,0 +1,241 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StatusConditionsKey is the key in Blueprint.Status holding the conditions of a blueprint
+const StatusConditionsKey = "conditions"
+
+// Standard condition types reported by reconcilers
+const (
+	ConditionReady       = "Ready"
+	ConditionProgressing = "Progressing"
+	ConditionDegraded    = "Degraded"
+)
+
+// Condition statuses
+const (
+	ConditionTrue    = "True"
+	ConditionFalse   = "False"
+	ConditionUnknown = "Unknown"
+)
+
+// ConditionNotReady is reported by ConditionSummary when a blueprint has conditions but none of the
+// standard conditions is true
+const ConditionNotReady = "NotReady"
+
+// BlueprintCondition describes one aspect of the observed state of a blueprint
+type BlueprintCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty"`
+	ObservedGeneration int64     `json:"observedGeneration,omitempty"` // Generation of the spec the condition was computed from
+}
+
+// parseConditions parses the conditions in a status map, strictly, so that misspelled fields are
+// reported instead of silently ignored
+func parseConditions(status map[string]interface{}) ([]BlueprintCondition, error) {
+	value, ok := status[StatusConditionsKey]
+	if !ok || value == nil {
+		return []BlueprintCondition{}, nil
+	}
+
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+
+	var conditions []BlueprintCondition
+	if err := decoder.Decode(&conditions); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", StatusConditionsKey, err)
+	}
+
+	return conditions, nil
+}
+
+// ValidateConditions checks that the conditions in a status map, if any, have a type, a valid status and
+// a non-negative observed generation, and that no condition type occurs more than once
+func ValidateConditions(status map[string]interface{}) error {
+	conditions, err := parseConditions(status)
+	if err != nil {
+		return err
+	}
+
+	types := make(map[string]bool)
+	for _, condition := range conditions {
+		if condition.Type == "" {
+			return errors.New("condition type is required")
+		}
+		if types[condition.Type] {
+			return fmt.Errorf("condition '%s' is defined more than once", condition.Type)
+		}
+		types[condition.Type] = true
+
+		switch condition.Status {
+		case ConditionTrue, ConditionFalse, ConditionUnknown:
+		default:
+			return fmt.Errorf("condition '%s' has invalid status '%s', must be '%s', '%s' or '%s'", condition.Type, condition.Status, ConditionTrue, ConditionFalse, ConditionUnknown)
+		}
+
+		if condition.ObservedGeneration < 0 {
+			return fmt.Errorf("condition '%s' has negative observed generation", condition.Type)
+		}
+	}
+
+	return nil
+}
+
+// NormalizeConditions validates the conditions in status and fills in the last transition time of
+// conditions that do not have one. The time of the previous status is kept if the condition status is
+// unchanged, otherwise it is set to now.
+func NormalizeConditions(previous map[string]interface{}, status map[string]interface{}) error {
+	if err := ValidateConditions(status); err != nil {
+		return err
+	}
+
+	if _, ok := status[StatusConditionsKey]; !ok {
+		return nil
+	}
+
+	conditions, _ := parseConditions(status)
+	previousConditions, err := parseConditions(previous)
+	if err != nil {
+		// The previous status was written before conditions were validated
+		previousConditions = []BlueprintCondition{}
+	}
+
+	now := time.Now()
+	for i := range conditions {
+		if !conditions[i].LastTransitionTime.IsZero() {
+			continue
+		}
+
+		conditions[i].LastTransitionTime = now
+		for _, old := range previousConditions {
+			if old.Type == conditions[i].Type && old.Status == conditions[i].Status && !old.LastTransitionTime.IsZero() {
+				conditions[i].LastTransitionTime = old.LastTransitionTime
+			}
+		}
+	}
+
+	return setConditions(status, conditions)
+}
+
+// setConditions stores conditions in a status map in the same form as they are read from JSON
+func setConditions(status map[string]interface{}, conditions []BlueprintCondition) error {
+	jsonBytes, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+
+	var value []interface{}
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return err
+	}
+
+	status[StatusConditionsKey] = value
+
+	return nil
+}
+
+// GetConditions returns the conditions of the blueprint, an empty list if it has none or if they are
+// malformed
+func (r *Blueprint) GetConditions() []BlueprintCondition {
+	conditions, err := parseConditions(r.Status)
+	if err != nil {
+		return []BlueprintCondition{}
+	}
+
+	return conditions
+}
+
+// GetCondition returns the condition of the given type, or nil if the blueprint does not have it
+func (r *Blueprint) GetCondition(conditionType string) *BlueprintCondition {
+	for _, condition := range r.GetConditions() {
+		if condition.Type == conditionType {
+			return &condition
+		}
+	}
+
+	return nil
+}
+
+// SetCondition adds a condition, or replaces the condition of the same type. The last transition time
+// is set to now if the condition is new or its status changed, and kept otherwise.
+func (r *Blueprint) SetCondition(condition BlueprintCondition) {
+	conditions := r.GetConditions()
+
+	replaced := false
+	for i, old := range conditions {
+		if old.Type != condition.Type {
+			continue
+		}
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = old.LastTransitionTime
+			if old.Status != condition.Status || old.LastTransitionTime.IsZero() {
+				condition.LastTransitionTime = time.Now()
+			}
+		}
+		conditions[i] = condition
+		replaced = true
+	}
+
+	if !replaced {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = time.Now()
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if r.Status == nil {
+		r.Status = make(map[string]interface{})
+	}
+	setConditions(r.Status, conditions)
+}
+
+// IsConditionMet returns true if the blueprint has a condition of the given type and status that was
+// computed from the current generation of the spec. Conditions without an observed generation are
+// assumed to be current.
+func (r *Blueprint) IsConditionMet(conditionType string, status string) bool {
+	condition := r.GetCondition(conditionType)
+	if condition == nil || condition.Status != status {
+		return false
+	}
+
+	return condition.ObservedGeneration == 0 || condition.ObservedGeneration >= r.Metadata.Generation
+}
+
+// ConditionSummary summarizes the standard conditions of a blueprint as Degraded, Progressing, Ready or
+// NotReady, in that order of precedence. A Ready condition from an older generation counts as
+// Progressing. An empty string is returned if the blueprint has no conditions.
+func (r *Blueprint) ConditionSummary() string {
+	if len(r.GetConditions()) == 0 {
+		return ""
+	}
+
+	switch {
+	case r.IsConditionMet(ConditionDegraded, ConditionTrue):
+		return ConditionDegraded
+	case r.IsConditionMet(ConditionProgressing, ConditionTrue):
+		return ConditionProgressing
+	case r.IsConditionMet(ConditionReady, ConditionTrue):
+		return ConditionReady
+	}
+
+	ready := r.GetCondition(ConditionReady)
+	if ready != nil && ready.Status == ConditionTrue {
+		return ConditionProgressing
+	}
+
+	return ConditionNotReady
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintSetCondition(t *testing.T) {
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	assert.Equal(t, "", blueprint.ConditionSummary())
+	assert.Nil(t, blueprint.GetCondition(ConditionReady))
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionReady, Status: ConditionFalse, Reason: "Starting"})
+	ready := blueprint.GetCondition(ConditionReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, "Starting", ready.Reason)
+	assert.False(t, ready.LastTransitionTime.IsZero())
+	assert.Equal(t, ConditionNotReady, blueprint.ConditionSummary())
+
+	// The transition time is kept while the status is unchanged
+	transition := ready.LastTransitionTime
+	time.Sleep(10 * time.Millisecond)
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionReady, Status: ConditionFalse, Reason: "Pulling"})
+	ready = blueprint.GetCondition(ConditionReady)
+	assert.Equal(t, "Pulling", ready.Reason)
+	assert.True(t, transition.Equal(ready.LastTransitionTime))
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionReady, Status: ConditionTrue})
+	ready = blueprint.GetCondition(ConditionReady)
+	assert.True(t, ready.LastTransitionTime.After(transition))
+	assert.Len(t, blueprint.GetConditions(), 1)
+	assert.Equal(t, ConditionReady, blueprint.ConditionSummary())
+
+	// Conditions survive a JSON round trip
+	jsonString, err := blueprint.ToJSON()
+	assert.Nil(t, err)
+	blueprint2, err := ConvertJSONToBlueprint(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, blueprint2.IsConditionMet(ConditionReady, ConditionTrue))
+}
+
+func TestBlueprintConditionSummary(t *testing.T) {
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.Metadata.Generation = 2
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionReady, Status: ConditionTrue, ObservedGeneration: 1})
+	assert.False(t, blueprint.IsConditionMet(ConditionReady, ConditionTrue))
+	assert.Equal(t, ConditionProgressing, blueprint.ConditionSummary())
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionReady, Status: ConditionTrue, ObservedGeneration: 2})
+	assert.True(t, blueprint.IsConditionMet(ConditionReady, ConditionTrue))
+	assert.Equal(t, ConditionReady, blueprint.ConditionSummary())
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionProgressing, Status: ConditionTrue})
+	assert.Equal(t, ConditionProgressing, blueprint.ConditionSummary())
+
+	blueprint.SetCondition(BlueprintCondition{Type: ConditionDegraded, Status: ConditionTrue})
+	assert.Equal(t, ConditionDegraded, blueprint.ConditionSummary())
+}
+
+func TestValidateConditions(t *testing.T) {
+	assert.Nil(t, ValidateConditions(nil))
+	assert.Nil(t, ValidateConditions(map[string]interface{}{"phase": "Running"}))
+
+	valid := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": 2},
+			map[string]interface{}{"type": "Degraded", "status": "False", "reason": "AllHealthy"},
+		},
+	}
+	assert.Nil(t, ValidateConditions(valid))
+
+	invalid := []interface{}{
+		"Ready",
+		[]interface{}{map[string]interface{}{"status": "True"}},
+		[]interface{}{map[string]interface{}{"type": "Ready", "status": "yes"}},
+		[]interface{}{map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": -1}},
+		[]interface{}{map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": "yesterday"}},
+		[]interface{}{map[string]interface{}{"type": "Ready", "status": "True", "mesage": "typo"}},
+		[]interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+			map[string]interface{}{"type": "Ready", "status": "False"},
+		},
+	}
+	for _, conditions := range invalid {
+		assert.NotNil(t, ValidateConditions(map[string]interface{}{"conditions": conditions}), conditions)
+	}
+}
+
+func TestNormalizeConditions(t *testing.T) {
+	transition := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	previous := map[string]interface{}{}
+	setConditions(previous, []BlueprintCondition{
+		{Type: ConditionReady, Status: ConditionTrue, LastTransitionTime: transition},
+		{Type: ConditionDegraded, Status: ConditionFalse, LastTransitionTime: transition},
+	})
+
+	status := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+			map[string]interface{}{"type": "Degraded", "status": "True"},
+			map[string]interface{}{"type": "Progressing", "status": "False"},
+		},
+	}
+	assert.Nil(t, NormalizeConditions(previous, status))
+
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.Status = status
+	assert.True(t, transition.Equal(blueprint.GetCondition(ConditionReady).LastTransitionTime))
+	assert.True(t, blueprint.GetCondition(ConditionDegraded).LastTransitionTime.After(transition))
+	assert.False(t, blueprint.GetCondition(ConditionProgressing).LastTransitionTime.IsZero())
+
+	assert.NotNil(t, NormalizeConditions(previous, map[string]interface{}{"conditions": "Ready"}))
+
+	// Statuses without conditions are left as they are
+	status = map[string]interface{}{"phase": "Running"}
+	assert.Nil(t, NormalizeConditions(previous, status))
+	assert.Len(t, status, 1)
+}
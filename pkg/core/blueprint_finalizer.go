@@ -0,0 +1,74 @@
+package core
+
+// OwnerReference points to the blueprint that owns another blueprint. Owned blueprints are removed
+// when their owner is removed.
+type OwnerReference struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	BlueprintID string `json:"blueprintid"`
+}
+
+// IsBeingDeleted returns true if removal of the blueprint has been requested but is blocked by finalizers
+func (r *Blueprint) IsBeingDeleted() bool {
+	return !r.Metadata.DeletionTimestamp.IsZero()
+}
+
+// HasFinalizer returns true if the blueprint has the given finalizer
+func (r *Blueprint) HasFinalizer(finalizer string) bool {
+	for _, f := range r.Metadata.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFinalizer adds a finalizer unless the blueprint already has it
+func (r *Blueprint) AddFinalizer(finalizer string) {
+	if !r.HasFinalizer(finalizer) {
+		r.Metadata.Finalizers = append(r.Metadata.Finalizers, finalizer)
+	}
+}
+
+// RemoveFinalizer removes a finalizer and returns true if the blueprint had it
+func (r *Blueprint) RemoveFinalizer(finalizer string) bool {
+	finalizers := make([]string, 0, len(r.Metadata.Finalizers))
+	for _, f := range r.Metadata.Finalizers {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+
+	removed := len(finalizers) != len(r.Metadata.Finalizers)
+	if len(finalizers) == 0 {
+		finalizers = nil
+	}
+	r.Metadata.Finalizers = finalizers
+
+	return removed
+}
+
+// SetOwner adds an owner reference to the given blueprint, which must be in the same colony
+func (r *Blueprint) SetOwner(owner *Blueprint) {
+	if r.IsOwnedBy(owner) {
+		return
+	}
+
+	r.Metadata.OwnerReferences = append(r.Metadata.OwnerReferences, OwnerReference{
+		Kind:        owner.Kind,
+		Name:        owner.Metadata.Name,
+		BlueprintID: owner.ID,
+	})
+}
+
+// IsOwnedBy returns true if the blueprint has an owner reference to the given blueprint
+func (r *Blueprint) IsOwnedBy(owner *Blueprint) bool {
+	for _, ref := range r.Metadata.OwnerReferences {
+		if ref.BlueprintID == owner.ID {
+			return true
+		}
+	}
+
+	return false
+}
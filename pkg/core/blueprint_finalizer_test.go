@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintFinalizers(t *testing.T) {
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	assert.False(t, blueprint.IsBeingDeleted())
+	assert.False(t, blueprint.HasFinalizer("a"))
+
+	blueprint.AddFinalizer("a")
+	blueprint.AddFinalizer("b")
+	blueprint.AddFinalizer("a")
+	assert.Equal(t, []string{"a", "b"}, blueprint.Metadata.Finalizers)
+	assert.True(t, blueprint.HasFinalizer("b"))
+
+	assert.True(t, blueprint.RemoveFinalizer("a"))
+	assert.False(t, blueprint.RemoveFinalizer("a"))
+	assert.Equal(t, []string{"b"}, blueprint.Metadata.Finalizers)
+	assert.True(t, blueprint.RemoveFinalizer("b"))
+	assert.Nil(t, blueprint.Metadata.Finalizers)
+
+	blueprint.AddFinalizer("a")
+	blueprint.Metadata.DeletionTimestamp = time.Now()
+	assert.True(t, blueprint.IsBeingDeleted())
+
+	jsonString, err := blueprint.ToJSON()
+	assert.Nil(t, err)
+	blueprint2, err := ConvertJSONToBlueprint(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, blueprint2.IsBeingDeleted())
+	assert.Equal(t, []string{"a"}, blueprint2.Metadata.Finalizers)
+}
+
+func TestBlueprintOwnerReferences(t *testing.T) {
+	owner := CreateBlueprint("Cluster", "prod", "test-colony")
+	other := CreateBlueprint("Cluster", "dev", "test-colony")
+
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	assert.False(t, blueprint.IsOwnedBy(owner))
+
+	blueprint.SetOwner(owner)
+	blueprint.SetOwner(owner)
+	assert.True(t, blueprint.IsOwnedBy(owner))
+	assert.False(t, blueprint.IsOwnedBy(other))
+	assert.Equal(t, []OwnerReference{{Kind: "Cluster", Name: "prod", BlueprintID: owner.ID}}, blueprint.Metadata.OwnerReferences)
+
+	jsonString, err := blueprint.ToJSON()
+	assert.Nil(t, err)
+	blueprint2, err := ConvertJSONToBlueprint(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, blueprint2.IsOwnedBy(owner))
+}
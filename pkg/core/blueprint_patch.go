@@ -0,0 +1,175 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Patch types accepted by Blueprint.ApplyPatch
+const (
+	MergePatchType     = "merge"
+	StrategicPatchType = "strategic"
+)
+
+// PatchMergeKey identifies list items in strategic merge patches, lists of objects that all have this
+// key are merged item by item instead of being replaced
+const PatchMergeKey = "name"
+
+// patchDirective is set on a list item in a strategic merge patch to remove the matching item
+const patchDirective = "$patch"
+
+// ErrResourceVersionConflict is returned when a write is conditioned on a resource version that is no
+// longer the current one, i.e. someone else has modified the blueprint since it was read
+var ErrResourceVersionConflict = errors.New("resource version conflict")
+
+// MergePatch applies a JSON merge patch (RFC 7386) to a value and returns the result. Objects are
+// merged recursively, null removes a key and every other value, including lists, replaces the target.
+// The target is not modified.
+func MergePatch(target interface{}, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return copyValue(patch)
+	}
+
+	targetMap, ok := target.(map[string]interface{})
+	if ok {
+		targetMap = copyMap(targetMap)
+	} else {
+		targetMap = make(map[string]interface{})
+	}
+
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = MergePatch(targetMap[key], value)
+	}
+
+	return targetMap
+}
+
+// StrategicMergePatch works like MergePatch, except that lists of objects keyed by PatchMergeKey are
+// merged by key. Patch items update the item with the same key or are appended, and an item with
+// "$patch": "delete" removes the item with its key. The target is not modified.
+func StrategicMergePatch(target interface{}, patch interface{}) interface{} {
+	switch patchValue := patch.(type) {
+	case map[string]interface{}:
+		targetMap, ok := target.(map[string]interface{})
+		if ok {
+			targetMap = copyMap(targetMap)
+		} else {
+			targetMap = make(map[string]interface{})
+		}
+
+		for key, value := range patchValue {
+			if value == nil {
+				delete(targetMap, key)
+				continue
+			}
+			targetMap[key] = StrategicMergePatch(targetMap[key], value)
+		}
+
+		return targetMap
+	case []interface{}:
+		targetList, ok := target.([]interface{})
+		if !ok || !isKeyedList(targetList) || !isKeyedList(patchValue) {
+			return copyValue(patch)
+		}
+
+		return mergeKeyedLists(targetList, patchValue)
+	}
+
+	return patch
+}
+
+func isKeyedList(list []interface{}) bool {
+	for _, item := range list {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := itemMap[PatchMergeKey]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mergeKeyedLists(target []interface{}, patch []interface{}) []interface{} {
+	result := copySlice(target)
+	for _, item := range patch {
+		patchItem := item.(map[string]interface{})
+		key := patchItem[PatchMergeKey]
+
+		index := -1
+		for i, existing := range result {
+			if deepEqual(existing.(map[string]interface{})[PatchMergeKey], key) {
+				index = i
+				break
+			}
+		}
+
+		if patchItem[patchDirective] == "delete" {
+			if index >= 0 {
+				result = append(result[:index], result[index+1:]...)
+			}
+			continue
+		}
+
+		if index >= 0 {
+			result[index] = StrategicMergePatch(result[index], patchItem)
+		} else {
+			result = append(result, StrategicMergePatch(nil, patchItem))
+		}
+	}
+
+	return result
+}
+
+// ApplyPatch returns a copy of the blueprint with the patch applied to its JSON representation. The
+// identity of the blueprint (ID, kind, name and colony) cannot be changed, and fields maintained by the
+// server are kept.
+func (r *Blueprint) ApplyPatch(patchType string, patch map[string]interface{}) (*Blueprint, error) {
+	jsonString, err := r.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonString), &document); err != nil {
+		return nil, err
+	}
+
+	var patched interface{}
+	switch patchType {
+	case "", MergePatchType:
+		patched = MergePatch(document, patch)
+	case StrategicPatchType:
+		patched = StrategicMergePatch(document, patch)
+	default:
+		return nil, fmt.Errorf("unsupported patch type '%s', must be '%s' or '%s'", patchType, MergePatchType, StrategicPatchType)
+	}
+
+	patchedJSON, err := json.Marshal(patched)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ConvertJSONToBlueprint(string(patchedJSON))
+	if err != nil {
+		return nil, fmt.Errorf("patch does not produce a valid blueprint: %v", err)
+	}
+
+	if result.ID != r.ID || result.Kind != r.Kind || result.Metadata.Name != r.Metadata.Name || result.Metadata.ColonyName != r.Metadata.ColonyName {
+		return nil, errors.New("patch cannot change blueprint ID, kind, name or colony")
+	}
+
+	result.Metadata.Generation = r.Metadata.Generation
+	result.Metadata.ResourceVersion = r.Metadata.ResourceVersion
+	result.Metadata.CreatedAt = r.Metadata.CreatedAt
+
+	return result, nil
+}
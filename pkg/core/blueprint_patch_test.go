@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"image":    "nginx:1.0",
+		"replicas": 1.0,
+		"env":      map[string]interface{}{"A": "1", "B": "2"},
+		"ports":    []interface{}{80.0, 443.0},
+	}
+
+	patch := map[string]interface{}{
+		"replicas": 3.0,
+		"env":      map[string]interface{}{"A": nil, "C": "3"},
+		"ports":    []interface{}{8080.0},
+		"new":      map[string]interface{}{"nested": true},
+	}
+
+	result := MergePatch(target, patch).(map[string]interface{})
+	assert.Equal(t, "nginx:1.0", result["image"])
+	assert.Equal(t, 3.0, result["replicas"])
+	assert.Equal(t, map[string]interface{}{"B": "2", "C": "3"}, result["env"])
+	assert.Equal(t, []interface{}{8080.0}, result["ports"])
+	assert.Equal(t, map[string]interface{}{"nested": true}, result["new"])
+
+	// The target is not modified
+	assert.Equal(t, 1.0, target["replicas"])
+	assert.Equal(t, "1", target["env"].(map[string]interface{})["A"])
+
+	// A patch that is not an object replaces the target
+	assert.Equal(t, "value", MergePatch(target, "value"))
+}
+
+func TestStrategicMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "web", "image": "nginx:1.0", "port": 80.0},
+			map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			map[string]interface{}{"name": "old", "image": "legacy"},
+		},
+		"args": []interface{}{"--a", "--b"},
+	}
+
+	patch := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "web", "image": "nginx:2.0"},
+			map[string]interface{}{"name": "old", "$patch": "delete"},
+			map[string]interface{}{"name": "metrics", "image": "prometheus"},
+		},
+		"args": []interface{}{"--c"},
+	}
+
+	result := StrategicMergePatch(target, patch).(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "web", "image": "nginx:2.0", "port": 80.0},
+		map[string]interface{}{"name": "sidecar", "image": "envoy"},
+		map[string]interface{}{"name": "metrics", "image": "prometheus"},
+	}, result["containers"])
+
+	// Lists without merge keys are replaced like in a merge patch
+	assert.Equal(t, []interface{}{"--c"}, result["args"])
+
+	// The target is not modified
+	assert.Len(t, target["containers"], 3)
+	assert.Equal(t, "nginx:1.0", target["containers"].([]interface{})[0].(map[string]interface{})["image"])
+}
+
+func TestBlueprintApplyPatch(t *testing.T) {
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.SetSpec("image", "nginx:1.0")
+	blueprint.SetSpec("replicas", 1)
+	blueprint.SetStatus("ready", false)
+	blueprint.Metadata.Generation = 4
+	blueprint.Metadata.ResourceVersion = 7
+
+	patched, err := blueprint.ApplyPatch(MergePatchType, map[string]interface{}{
+		"spec":     map[string]interface{}{"replicas": 3},
+		"status":   map[string]interface{}{"ready": true},
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"tier": "frontend"}, "generation": 100},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx:1.0", patched.Spec["image"])
+	assert.Equal(t, 3.0, patched.Spec["replicas"])
+	assert.Equal(t, true, patched.Status["ready"])
+	assert.Equal(t, "frontend", patched.Metadata.Labels["tier"])
+	assert.Equal(t, int64(4), patched.Metadata.Generation)
+	assert.Equal(t, int64(7), patched.Metadata.ResourceVersion)
+	assert.Equal(t, blueprint.ID, patched.ID)
+
+	// The original blueprint is not modified
+	assert.Equal(t, 1, blueprint.Spec["replicas"])
+
+	_, err = blueprint.ApplyPatch(MergePatchType, map[string]interface{}{"metadata": map[string]interface{}{"name": "other"}})
+	assert.NotNil(t, err)
+
+	_, err = blueprint.ApplyPatch(MergePatchType, map[string]interface{}{"kind": "Other"})
+	assert.NotNil(t, err)
+
+	_, err = blueprint.ApplyPatch(MergePatchType, map[string]interface{}{"spec": "not an object"})
+	assert.NotNil(t, err)
+
+	_, err = blueprint.ApplyPatch("json", map[string]interface{}{})
+	assert.NotNil(t, err)
+
+	patched, err = blueprint.ApplyPatch(StrategicPatchType, map[string]interface{}{"spec": map[string]interface{}{"image": "nginx:2.0"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx:2.0", patched.Spec["image"])
+}
@@ -0,0 +1,577 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// AdditionalProperties holds the additionalProperties keyword, which is either a boolean or a schema
+// that all properties not listed in properties must match
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *SchemaProperty
+}
+
+func (ap AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if ap.Schema != nil {
+		return json.Marshal(ap.Schema)
+	}
+
+	return json.Marshal(ap.Allowed)
+}
+
+func (ap *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		ap.Allowed = allowed
+		ap.Schema = nil
+		return nil
+	}
+
+	schema := &SchemaProperty{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return err
+	}
+	ap.Allowed = true
+	ap.Schema = schema
+
+	return nil
+}
+
+// SchemaError is a single validation failure, Path is a JSON pointer (RFC 6901) to the offending value
+type SchemaError struct {
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// SchemaErrors contains all validation failures of a spec
+type SchemaErrors []*SchemaError
+
+func (errs SchemaErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type schemaValidator struct {
+	patterns map[string]*regexp.Regexp
+}
+
+// ValidateSpec validates a blueprint spec against a schema, all failures are returned as SchemaErrors
+// with paths rooted at /spec
+func ValidateSpec(spec map[string]interface{}, schema *ValidationSchema) error {
+	if schema == nil {
+		return nil
+	}
+
+	if spec == nil {
+		spec = make(map[string]interface{})
+	}
+
+	v := &schemaValidator{patterns: make(map[string]*regexp.Regexp)}
+	errs := v.validate("/spec", spec, schema)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ApplySchemaDefaults sets the default value of every property missing from the spec, nested objects
+// and the elements of arrays are defaulted as well. Objects that are absent and have no default of
+// their own are not created.
+func ApplySchemaDefaults(spec map[string]interface{}, schema *ValidationSchema) map[string]interface{} {
+	if spec == nil {
+		spec = make(map[string]interface{})
+	}
+
+	if schema != nil {
+		applyDefaults(spec, schema)
+	}
+
+	return spec
+}
+
+func applyDefaults(value interface{}, schema *SchemaProperty) {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedKeys(schema.Properties) {
+			prop := schema.Properties[name]
+			if _, ok := val[name]; !ok && prop.Default != nil {
+				val[name] = copyValue(prop.Default)
+			}
+			if nested, ok := val[name]; ok {
+				applyDefaults(nested, &prop)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil {
+			for _, item := range val {
+				applyDefaults(item, schema.Items)
+			}
+		}
+	}
+}
+
+func copyValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		return copyMap(val)
+	case []interface{}:
+		return copySlice(val)
+	}
+
+	return value
+}
+
+func sortedKeys(m map[string]SchemaProperty) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func schemaError(path string, format string, args ...interface{}) *SchemaError {
+	return &SchemaError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+func (v *schemaValidator) matches(path string, value interface{}, schema *SchemaProperty) bool {
+	return len(v.validate(path, value, schema)) == 0
+}
+
+func (v *schemaValidator) validate(path string, value interface{}, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	if schema.Type != "" && !hasJSONType(value, schema.Type) {
+		// The remaining keywords are meaningless if the type is wrong
+		return SchemaErrors{schemaError(path, "must be %s, got %s", typeWithArticle(schema.Type), jsonType(value))}
+	}
+
+	if schema.Const != nil && !deepEqual(value, schema.Const) {
+		errs = append(errs, schemaError(path, "must be equal to %v", schema.Const))
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, allowed := range schema.Enum {
+			if deepEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, schemaError(path, "invalid value '%v', must be one of %v", value, schema.Enum))
+		}
+	}
+
+	if number, ok := toFloat(value); ok {
+		errs = append(errs, v.validateNumber(path, number, schema)...)
+	}
+
+	switch val := value.(type) {
+	case string:
+		errs = append(errs, v.validateString(path, val, schema)...)
+	case map[string]interface{}:
+		errs = append(errs, v.validateObject(path, val, schema)...)
+	case []interface{}:
+		errs = append(errs, v.validateArray(path, val, schema)...)
+	}
+
+	errs = append(errs, v.validateComposition(path, value, schema)...)
+
+	return errs
+}
+
+func (v *schemaValidator) validateNumber(path string, number float64, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	if schema.Minimum != nil && number < *schema.Minimum {
+		errs = append(errs, schemaError(path, "must be >= %v, got %v", *schema.Minimum, number))
+	}
+	if schema.Maximum != nil && number > *schema.Maximum {
+		errs = append(errs, schemaError(path, "must be <= %v, got %v", *schema.Maximum, number))
+	}
+	if schema.ExclusiveMinimum != nil && number <= *schema.ExclusiveMinimum {
+		errs = append(errs, schemaError(path, "must be > %v, got %v", *schema.ExclusiveMinimum, number))
+	}
+	if schema.ExclusiveMaximum != nil && number >= *schema.ExclusiveMaximum {
+		errs = append(errs, schemaError(path, "must be < %v, got %v", *schema.ExclusiveMaximum, number))
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		quotient := number / *schema.MultipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			errs = append(errs, schemaError(path, "must be a multiple of %v, got %v", *schema.MultipleOf, number))
+		}
+	}
+
+	return errs
+}
+
+func (v *schemaValidator) validateString(path string, str string, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	length := utf8.RuneCountInString(str)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		errs = append(errs, schemaError(path, "must be at least %d characters long, got %d", *schema.MinLength, length))
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		errs = append(errs, schemaError(path, "must be at most %d characters long, got %d", *schema.MaxLength, length))
+	}
+
+	if schema.Pattern != "" {
+		re, err := v.compile(schema.Pattern)
+		if err != nil {
+			errs = append(errs, schemaError(path, "invalid pattern '%s' in schema", schema.Pattern))
+		} else if !re.MatchString(str) {
+			errs = append(errs, schemaError(path, "'%s' does not match pattern '%s'", str, schema.Pattern))
+		}
+	}
+
+	if schema.Format != "" && !hasFormat(str, schema.Format) {
+		errs = append(errs, schemaError(path, "'%s' is not a valid %s", str, schema.Format))
+	}
+
+	return errs
+}
+
+func (v *schemaValidator) validateObject(path string, obj map[string]interface{}, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	for _, required := range schema.Required {
+		if _, ok := obj[required]; !ok {
+			errs = append(errs, schemaError(path, "required field '%s' is missing", required))
+		}
+	}
+
+	if schema.MinProperties != nil && len(obj) < *schema.MinProperties {
+		errs = append(errs, schemaError(path, "must have at least %d properties, got %d", *schema.MinProperties, len(obj)))
+	}
+	if schema.MaxProperties != nil && len(obj) > *schema.MaxProperties {
+		errs = append(errs, schemaError(path, "must have at most %d properties, got %d", *schema.MaxProperties, len(obj)))
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + escapePointer(key)
+		if prop, ok := schema.Properties[key]; ok {
+			errs = append(errs, v.validate(childPath, obj[key], &prop)...)
+			continue
+		}
+
+		if schema.AdditionalProperties == nil {
+			continue
+		}
+		if schema.AdditionalProperties.Schema != nil {
+			errs = append(errs, v.validate(childPath, obj[key], schema.AdditionalProperties.Schema)...)
+		} else if !schema.AdditionalProperties.Allowed {
+			errs = append(errs, schemaError(childPath, "additional property '%s' is not allowed", key))
+		}
+	}
+
+	return errs
+}
+
+func (v *schemaValidator) validateArray(path string, arr []interface{}, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	if schema.MinItems != nil && len(arr) < *schema.MinItems {
+		errs = append(errs, schemaError(path, "must have at least %d items, got %d", *schema.MinItems, len(arr)))
+	}
+	if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+		errs = append(errs, schemaError(path, "must have at most %d items, got %d", *schema.MaxItems, len(arr)))
+	}
+
+	if schema.UniqueItems {
+	unique:
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if deepEqual(arr[i], arr[j]) {
+					errs = append(errs, schemaError(path, "items at index %d and %d must be unique", i, j))
+					break unique
+				}
+			}
+		}
+	}
+
+	if schema.Items != nil {
+		for i, item := range arr {
+			errs = append(errs, v.validate(fmt.Sprintf("%s/%d", path, i), item, schema.Items)...)
+		}
+	}
+
+	return errs
+}
+
+func (v *schemaValidator) validateComposition(path string, value interface{}, schema *SchemaProperty) SchemaErrors {
+	var errs SchemaErrors
+
+	for i := range schema.AllOf {
+		errs = append(errs, v.validate(path, value, &schema.AllOf[i])...)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for i := range schema.AnyOf {
+			if v.matches(path, value, &schema.AnyOf[i]) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			errs = append(errs, schemaError(path, "must match at least one schema in anyOf"))
+		}
+	}
+
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for i := range schema.OneOf {
+			if v.matches(path, value, &schema.OneOf[i]) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			errs = append(errs, schemaError(path, "must match exactly one schema in oneOf, matched %d", matched))
+		}
+	}
+
+	if schema.Not != nil && v.matches(path, value, schema.Not) {
+		errs = append(errs, schemaError(path, "must not match the schema in not"))
+	}
+
+	return errs
+}
+
+func (v *schemaValidator) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[pattern] = re
+
+	return re, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func hasJSONType(value interface{}, expectedType string) bool {
+	switch expectedType {
+	case "integer":
+		number, ok := toFloat(value)
+		return ok && number == math.Trunc(number) && !math.IsInf(number, 0)
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	}
+
+	return jsonType(value) == expectedType
+}
+
+func typeWithArticle(schemaType string) string {
+	switch schemaType {
+	case "integer", "array", "object":
+		return "an " + schemaType
+	case "null":
+		return "null"
+	}
+
+	return "a " + schemaType
+}
+
+// hasFormat checks the format assertions listed in docs/SchemaValidation.md, unknown formats are
+// treated as annotations and always pass, as required by draft 2020-12
+func hasFormat(str string, format string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", str)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(str)
+		return err == nil && addr.Address == str
+	case "hostname":
+		return len(str) <= 253 && hostnameRegex.MatchString(str)
+	case "ipv4":
+		ip := net.ParseIP(str)
+		return ip != nil && ip.To4() != nil && !strings.Contains(str, ":")
+	case "ipv6":
+		ip := net.ParseIP(str)
+		return ip != nil && strings.Contains(str, ":")
+	case "uri":
+		u, err := url.Parse(str)
+		return err == nil && u.IsAbs()
+	case "uuid":
+		return uuidRegex.MatchString(str)
+	}
+
+	return true
+}
+
+// Check verifies that the schema itself is well-formed, e.g. that types are known, patterns compile
+// and bounds are consistent. The returned error carries a JSON pointer into the schema.
+func (schema *SchemaProperty) Check() error {
+	return schema.check("")
+}
+
+func (schema *SchemaProperty) check(path string) error {
+	switch schema.Type {
+	case "", "string", "integer", "number", "boolean", "object", "array", "null":
+	default:
+		return schemaError(path+"/type", "unknown type '%s'", schema.Type)
+	}
+
+	if schema.Pattern != "" {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			return schemaError(path+"/pattern", "invalid pattern: %v", err)
+		}
+	}
+
+	if schema.MultipleOf != nil && *schema.MultipleOf <= 0 {
+		return schemaError(path+"/multipleOf", "must be greater than 0")
+	}
+	if schema.Minimum != nil && schema.Maximum != nil && *schema.Minimum > *schema.Maximum {
+		return schemaError(path, "minimum must be <= maximum")
+	}
+
+	bounds := []struct {
+		name     string
+		min, max *int
+	}{
+		{"Length", schema.MinLength, schema.MaxLength},
+		{"Items", schema.MinItems, schema.MaxItems},
+		{"Properties", schema.MinProperties, schema.MaxProperties},
+	}
+	for _, bound := range bounds {
+		if bound.min != nil && *bound.min < 0 {
+			return schemaError(path+"/min"+bound.name, "must be >= 0")
+		}
+		if bound.max != nil && *bound.max < 0 {
+			return schemaError(path+"/max"+bound.name, "must be >= 0")
+		}
+		if bound.min != nil && bound.max != nil && *bound.min > *bound.max {
+			return schemaError(path, "min%s must be <= max%s", bound.name, bound.name)
+		}
+	}
+
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		if err := prop.check(path + "/properties/" + escapePointer(name)); err != nil {
+			return err
+		}
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		if err := schema.AdditionalProperties.Schema.check(path + "/additionalProperties"); err != nil {
+			return err
+		}
+	}
+
+	if schema.Items != nil {
+		if err := schema.Items.check(path + "/items"); err != nil {
+			return err
+		}
+	}
+
+	compositions := []struct {
+		keyword    string
+		subschemas []SchemaProperty
+	}{
+		{"allOf", schema.AllOf},
+		{"anyOf", schema.AnyOf},
+		{"oneOf", schema.OneOf},
+	}
+	for _, composition := range compositions {
+		for i := range composition.subschemas {
+			if err := composition.subschemas[i].check(fmt.Sprintf("%s/%s/%d", path, composition.keyword, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if schema.Not != nil {
+		if err := schema.Not.check(path + "/not"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
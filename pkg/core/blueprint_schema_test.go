@@ -0,0 +1,273 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseSchema(t *testing.T, jsonString string) *ValidationSchema {
+	schema := &ValidationSchema{}
+	assert.Nil(t, json.Unmarshal([]byte(jsonString), schema))
+	assert.Nil(t, schema.Check())
+	return schema
+}
+
+func parseSpec(t *testing.T, jsonString string) map[string]interface{} {
+	var spec map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(jsonString), &spec))
+	return spec
+}
+
+func schemaErrorPaths(t *testing.T, err error) []string {
+	errs, ok := err.(SchemaErrors)
+	assert.True(t, ok)
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+func TestValidateSpecNumbers(t *testing.T) {
+	schema := parseSchema(t, `{
+		"type": "object",
+		"properties": {
+			"replicas": {"type": "integer", "minimum": 1, "maximum": 10},
+			"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+			"memory": {"type": "integer", "multipleOf": 256}
+		}
+	}`)
+
+	assert.Nil(t, ValidateSpec(parseSpec(t, `{"replicas": 1, "ratio": 0.5, "memory": 1024}`), schema))
+	assert.Nil(t, ValidateSpec(map[string]interface{}{"replicas": 10}, schema))
+
+	err := ValidateSpec(parseSpec(t, `{"replicas": 0, "ratio": 1, "memory": 1000}`), schema)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"/spec/memory", "/spec/ratio", "/spec/replicas"}, schemaErrorPaths(t, err))
+	assert.Contains(t, err.Error(), "/spec/replicas: must be >= 1, got 0")
+	assert.Contains(t, err.Error(), "/spec/ratio: must be < 1, got 1")
+	assert.Contains(t, err.Error(), "must be a multiple of 256")
+
+	err = ValidateSpec(parseSpec(t, `{"replicas": 2.5}`), schema)
+	assert.NotNil(t, err)
+	assert.Equal(t, "/spec/replicas: must be an integer, got number", err.Error())
+}
+
+func TestValidateSpecStrings(t *testing.T) {
+	schema := parseSchema(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3, "maxLength": 8, "pattern": "^[a-z][a-z0-9-]*$"},
+			"created": {"type": "string", "format": "date-time"},
+			"contact": {"type": "string", "format": "email"},
+			"id": {"type": "string", "format": "uuid"},
+			"host": {"type": "string", "format": "hostname"},
+			"ip": {"type": "string", "format": "ipv4"},
+			"endpoint": {"type": "string", "format": "uri"},
+			"custom": {"type": "string", "format": "unknown-format"}
+		}
+	}`)
+
+	valid := parseSpec(t, `{
+		"name": "web-01",
+		"created": "2024-01-02T15:04:05Z",
+		"contact": "ops@example.com",
+		"id": "0b5f6a3e-4c1d-4a7e-9f0b-1c2d3e4f5a6b",
+		"host": "node-1.example.com",
+		"ip": "10.0.0.1",
+		"endpoint": "https://example.com/api",
+		"custom": "anything"
+	}`)
+	assert.Nil(t, ValidateSpec(valid, schema))
+
+	// Lengths are counted in characters, not bytes
+	assert.Nil(t, ValidateSpec(map[string]interface{}{"name": "åäö"}, parseSchema(t, `{"properties": {"name": {"maxLength": 3}}}`)))
+
+	invalid := parseSpec(t, `{
+		"name": "Web",
+		"created": "yesterday",
+		"contact": "not an email",
+		"id": "1234",
+		"host": "-invalid-",
+		"ip": "::1",
+		"endpoint": "/relative"
+	}`)
+	err := ValidateSpec(invalid, schema)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"/spec/contact", "/spec/created", "/spec/endpoint", "/spec/host", "/spec/id", "/spec/ip", "/spec/name"}, schemaErrorPaths(t, err))
+	assert.Contains(t, err.Error(), "'Web' does not match pattern")
+
+	err = ValidateSpec(map[string]interface{}{"name": "ab"}, schema)
+	assert.Equal(t, "/spec/name: must be at least 3 characters long, got 2", err.Error())
+}
+
+func TestValidateSpecArrays(t *testing.T) {
+	schema := parseSchema(t, `{
+		"properties": {
+			"ports": {"type": "array", "minItems": 1, "maxItems": 3, "uniqueItems": true, "items": {"type": "integer", "maximum": 65535}},
+			"endpoints": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"path": {"type": "string"}, "method": {"enum": ["GET", "POST"]}},
+					"required": ["path"]
+				}
+			}
+		}
+	}`)
+
+	assert.Nil(t, ValidateSpec(parseSpec(t, `{"ports": [80, 443], "endpoints": [{"path": "/", "method": "GET"}]}`), schema))
+
+	err := ValidateSpec(parseSpec(t, `{"ports": []}`), schema)
+	assert.Equal(t, "/spec/ports: must have at least 1 items, got 0", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"ports": [80, 80]}`), schema)
+	assert.Equal(t, "/spec/ports: items at index 0 and 1 must be unique", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"ports": [80, 70000]}`), schema)
+	assert.Equal(t, "/spec/ports/1: must be <= 65535, got 70000", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"endpoints": [{"path": "/"}, {"method": "PUT"}]}`), schema)
+	assert.Equal(t, []string{"/spec/endpoints/1", "/spec/endpoints/1/method"}, schemaErrorPaths(t, err))
+	assert.Contains(t, err.Error(), "/spec/endpoints/1: required field 'path' is missing")
+}
+
+func TestValidateSpecObjects(t *testing.T) {
+	schema := parseSchema(t, `{
+		"type": "object",
+		"properties": {
+			"resources": {
+				"type": "object",
+				"properties": {"cpu": {"type": "string"}, "memory": {"type": "string"}},
+				"required": ["cpu"],
+				"additionalProperties": false
+			},
+			"labels": {
+				"type": "object",
+				"additionalProperties": {"type": "string", "maxLength": 5},
+				"maxProperties": 2
+			}
+		},
+		"additionalProperties": true
+	}`)
+
+	assert.Nil(t, ValidateSpec(parseSpec(t, `{"resources": {"cpu": "1"}, "labels": {"a": "x"}, "other": 1}`), schema))
+
+	err := ValidateSpec(parseSpec(t, `{"resources": {"memory": "1Gi", "gpu": 1}}`), schema)
+	assert.Equal(t, []string{"/spec/resources", "/spec/resources/gpu"}, schemaErrorPaths(t, err))
+	assert.Contains(t, err.Error(), "additional property 'gpu' is not allowed")
+
+	err = ValidateSpec(parseSpec(t, `{"labels": {"a": "x", "b/c": "too long"}}`), schema)
+	assert.Equal(t, "/spec/labels/b~1c: must be at most 5 characters long, got 8", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"labels": {"a": "x", "b": "y", "c": "z"}}`), schema)
+	assert.Equal(t, "/spec/labels: must have at most 2 properties, got 3", err.Error())
+}
+
+func TestValidateSpecComposition(t *testing.T) {
+	schema := parseSchema(t, `{
+		"properties": {
+			"storage": {
+				"oneOf": [
+					{"type": "object", "properties": {"kind": {"const": "s3"}, "bucket": {"type": "string"}}, "required": ["kind", "bucket"]},
+					{"type": "object", "properties": {"kind": {"const": "local"}, "path": {"type": "string"}}, "required": ["kind", "path"]}
+				]
+			},
+			"size": {"anyOf": [{"type": "integer"}, {"type": "string", "pattern": "^[0-9]+Gi$"}]},
+			"name": {"allOf": [{"minLength": 2}, {"maxLength": 4}], "not": {"const": "root"}}
+		}
+	}`)
+
+	assert.Nil(t, ValidateSpec(parseSpec(t, `{"storage": {"kind": "s3", "bucket": "b"}, "size": "10Gi", "name": "web"}`), schema))
+	assert.Nil(t, ValidateSpec(parseSpec(t, `{"storage": {"kind": "local", "path": "/data"}, "size": 10}`), schema))
+
+	err := ValidateSpec(parseSpec(t, `{"storage": {"kind": "nfs"}}`), schema)
+	assert.Equal(t, "/spec/storage: must match exactly one schema in oneOf, matched 0", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"size": "10GB"}`), schema)
+	assert.Equal(t, "/spec/size: must match at least one schema in anyOf", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"name": "a"}`), schema)
+	assert.Equal(t, "/spec/name: must be at least 2 characters long, got 1", err.Error())
+
+	err = ValidateSpec(parseSpec(t, `{"name": "root"}`), schema)
+	assert.Equal(t, "/spec/name: must not match the schema in not", err.Error())
+}
+
+func TestApplySchemaDefaults(t *testing.T) {
+	schema := parseSchema(t, `{
+		"properties": {
+			"replicas": {"type": "integer", "default": 1},
+			"resources": {
+				"type": "object",
+				"default": {"cpu": "1"},
+				"properties": {"cpu": {"type": "string"}, "memory": {"type": "string", "default": "1Gi"}}
+			},
+			"autoscaling": {
+				"type": "object",
+				"properties": {"enabled": {"type": "boolean", "default": false}}
+			},
+			"endpoints": {
+				"type": "array",
+				"items": {"type": "object", "properties": {"method": {"type": "string", "default": "GET"}}}
+			}
+		}
+	}`)
+
+	spec := ApplySchemaDefaults(parseSpec(t, `{"replicas": 3, "endpoints": [{"path": "/"}, {"path": "/x", "method": "POST"}]}`), schema)
+	assert.Equal(t, float64(3), spec["replicas"])
+	assert.Equal(t, map[string]interface{}{"cpu": "1", "memory": "1Gi"}, spec["resources"])
+	assert.NotContains(t, spec, "autoscaling")
+	endpoints := spec["endpoints"].([]interface{})
+	assert.Equal(t, "GET", endpoints[0].(map[string]interface{})["method"])
+	assert.Equal(t, "POST", endpoints[1].(map[string]interface{})["method"])
+	assert.Nil(t, ValidateSpec(spec, schema))
+
+	// Defaults must be copied, not shared between blueprints
+	spec["resources"].(map[string]interface{})["cpu"] = "2"
+	assert.Equal(t, "1", schema.Properties["resources"].Default.(map[string]interface{})["cpu"])
+
+	spec = ApplySchemaDefaults(parseSpec(t, `{"autoscaling": {}}`), schema)
+	assert.Equal(t, map[string]interface{}{"enabled": false}, spec["autoscaling"])
+
+	spec = ApplySchemaDefaults(nil, schema)
+	assert.Equal(t, 1, int(spec["replicas"].(float64)))
+}
+
+func TestSchemaCheck(t *testing.T) {
+	schema := &ValidationSchema{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"properties": {"name": {"pattern": "[a-"}}}`), schema))
+	err := schema.Check()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "/properties/name/pattern: invalid pattern")
+
+	schema = &ValidationSchema{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"properties": {"ports": {"items": {"minItems": 3, "maxItems": 1}}}}`), schema))
+	assert.Equal(t, "/properties/ports/items: minItems must be <= maxItems", schema.Check().Error())
+
+	schema = &ValidationSchema{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"oneOf": [{"type": "str"}]}`), schema))
+	assert.Equal(t, "/oneOf/0/type: unknown type 'str'", schema.Check().Error())
+
+	sd := CreateBlueprintDefinition("test", "test.io", "v1", "Test", "tests", "Namespaced", "executor", "reconcile")
+	sd.Spec.Schema = schema
+	assert.NotNil(t, sd.Validate())
+}
+
+func TestSchemaAdditionalPropertiesJSON(t *testing.T) {
+	schema := parseSchema(t, `{"additionalProperties": false, "properties": {"labels": {"additionalProperties": {"type": "string"}}}}`)
+	assert.False(t, schema.AdditionalProperties.Allowed)
+	assert.Nil(t, schema.AdditionalProperties.Schema)
+	assert.Equal(t, "string", schema.Properties["labels"].AdditionalProperties.Schema.Type)
+
+	jsonBytes, err := json.Marshal(schema)
+	assert.Nil(t, err)
+	assert.Contains(t, string(jsonBytes), `"additionalProperties":false`)
+	assert.Contains(t, string(jsonBytes), `"additionalProperties":{"type":"string"}`)
+
+	schema2 := &ValidationSchema{}
+	assert.Nil(t, json.Unmarshal(jsonBytes, schema2))
+	assert.True(t, deepEqual(schema, schema2))
+}
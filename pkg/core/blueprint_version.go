@@ -0,0 +1,411 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	ConversionStrategyNone     = "None"
+	ConversionStrategyMappings = "Mappings"
+	ConversionStrategyExecutor = "Executor"
+)
+
+const (
+	MigrationUnchanged = "unchanged"
+	MigrationMigrated  = "migrated"
+	MigrationPending   = "pending"
+	MigrationFailed    = "failed"
+)
+
+// ErrNoConversion is returned when a blueprint cannot be converted between two versions by the server
+var ErrNoConversion = errors.New("no conversion available")
+
+// BlueprintDefinitionVersion is a version served by a BlueprintDefinition. Exactly one version is the
+// storage version, blueprints in other versions are converted to it when they are written.
+type BlueprintDefinitionVersion struct {
+	Name       string            `json:"name"`
+	Served     bool              `json:"served"`
+	Storage    bool              `json:"storage"`
+	Deprecated bool              `json:"deprecated,omitempty"`
+	Schema     *ValidationSchema `json:"schema,omitempty"`
+}
+
+// ConversionSpec defines how blueprints are converted between versions. With the Mappings strategy
+// the server converts declaratively, with the Executor strategy a process is submitted to the given
+// executor which is expected to write back the blueprint in the target version.
+type ConversionSpec struct {
+	Strategy string             `json:"strategy"`
+	Mappings []VersionMapping   `json:"mappings,omitempty"`
+	Executor *ConversionHandler `json:"executor,omitempty"`
+}
+
+// ConversionHandler defines the executor function that converts blueprints
+type ConversionHandler struct {
+	ExecutorType string `json:"executorType"`
+	FunctionName string `json:"functionName"`
+}
+
+// VersionMapping converts a spec from one version to another, fields that are not mapped are copied
+type VersionMapping struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Fields []FieldMapping `json:"fields,omitempty"`
+}
+
+// FieldMapping moves the value at the JSON pointer From to the JSON pointer To. If To is empty the
+// field is removed, if From is empty To is set to Value.
+type FieldMapping struct {
+	From  string      `json:"from,omitempty"`
+	To    string      `json:"to,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// BlueprintMigrationResult is the outcome of migrating a single blueprint to the storage version
+type BlueprintMigrationResult struct {
+	Name        string `json:"name"`
+	FromVersion string `json:"fromversion"`
+	ToVersion   string `json:"toversion"`
+	Status      string `json:"status"`
+	ProcessID   string `json:"processid,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// BlueprintMigrationReport lists the migration result of every blueprint of a kind
+type BlueprintMigrationReport struct {
+	Kind           string                      `json:"kind"`
+	StorageVersion string                      `json:"storageversion"`
+	DryRun         bool                        `json:"dryrun"`
+	Results        []*BlueprintMigrationResult `json:"results"`
+}
+
+// GetVersions returns the versions of the definition. Definitions without versions serve a single
+// storage version made from spec.version and spec.schema.
+func (sd *BlueprintDefinition) GetVersions() []BlueprintDefinitionVersion {
+	if len(sd.Spec.Versions) > 0 {
+		return sd.Spec.Versions
+	}
+
+	return []BlueprintDefinitionVersion{{Name: sd.Spec.Version, Served: true, Storage: true, Schema: sd.Spec.Schema}}
+}
+
+// GetVersion returns the named version, or the storage version if name is empty
+func (sd *BlueprintDefinition) GetVersion(name string) *BlueprintDefinitionVersion {
+	versions := sd.GetVersions()
+	for i := range versions {
+		if (name == "" && versions[i].Storage) || (name != "" && versions[i].Name == name) {
+			return &versions[i]
+		}
+	}
+
+	return nil
+}
+
+// StorageVersion returns the name of the version blueprints are stored in
+func (sd *BlueprintDefinition) StorageVersion() string {
+	if version := sd.GetVersion(""); version != nil {
+		return version.Name
+	}
+
+	return sd.Spec.Version
+}
+
+// SchemaForVersion returns the schema of the named version, or of the storage version if name is empty
+func (sd *BlueprintDefinition) SchemaForVersion(name string) *ValidationSchema {
+	if version := sd.GetVersion(name); version != nil {
+		return version.Schema
+	}
+
+	return nil
+}
+
+// ConversionStrategy returns the configured strategy, None if no conversion is configured
+func (sd *BlueprintDefinition) ConversionStrategy() string {
+	if sd.Spec.Conversion == nil || sd.Spec.Conversion.Strategy == "" {
+		return ConversionStrategyNone
+	}
+
+	return sd.Spec.Conversion.Strategy
+}
+
+// ValidateVersions validates spec.versions and spec.conversion
+func (sd *BlueprintDefinition) ValidateVersions() error {
+	if len(sd.Spec.Versions) == 0 {
+		if sd.Spec.Conversion != nil && sd.ConversionStrategy() != ConversionStrategyNone {
+			return fmt.Errorf("spec.conversion requires spec.versions")
+		}
+		return nil
+	}
+
+	if sd.Spec.Schema != nil {
+		return fmt.Errorf("spec.schema cannot be combined with spec.versions, set a schema per version")
+	}
+
+	names := make(map[string]bool)
+	storageVersions := 0
+	for i, version := range sd.Spec.Versions {
+		if version.Name == "" {
+			return fmt.Errorf("spec.versions[%d].name is required", i)
+		}
+		if names[version.Name] {
+			return fmt.Errorf("spec.versions[%d].name '%s' is not unique", i, version.Name)
+		}
+		names[version.Name] = true
+
+		if version.Storage {
+			storageVersions++
+			if !version.Served {
+				return fmt.Errorf("storage version '%s' must be served", version.Name)
+			}
+		}
+
+		if version.Schema != nil {
+			if err := version.Schema.Check(); err != nil {
+				return fmt.Errorf("spec.versions[%d].schema is invalid: %w", i, err)
+			}
+		}
+	}
+
+	if storageVersions != 1 {
+		return fmt.Errorf("exactly one version must be the storage version, got %d", storageVersions)
+	}
+
+	if sd.Spec.Version != "" && !names[sd.Spec.Version] {
+		return fmt.Errorf("spec.version '%s' is not listed in spec.versions", sd.Spec.Version)
+	}
+
+	if sd.Spec.Conversion == nil {
+		return nil
+	}
+
+	switch sd.ConversionStrategy() {
+	case ConversionStrategyNone:
+	case ConversionStrategyMappings:
+		for i, mapping := range sd.Spec.Conversion.Mappings {
+			if !names[mapping.From] || !names[mapping.To] {
+				return fmt.Errorf("spec.conversion.mappings[%d] refers to an unknown version", i)
+			}
+			for j, field := range mapping.Fields {
+				for _, pointer := range []string{field.From, field.To} {
+					if pointer != "" && !strings.HasPrefix(pointer, "/") {
+						return fmt.Errorf("spec.conversion.mappings[%d].fields[%d]: '%s' is not a JSON pointer", i, j, pointer)
+					}
+				}
+				if field.From == "" && field.To == "" {
+					return fmt.Errorf("spec.conversion.mappings[%d].fields[%d]: from or to is required", i, j)
+				}
+			}
+		}
+	case ConversionStrategyExecutor:
+		executor := sd.Spec.Conversion.Executor
+		if executor == nil || executor.ExecutorType == "" || executor.FunctionName == "" {
+			return fmt.Errorf("spec.conversion.executor.executorType and functionName are required")
+		}
+	default:
+		return fmt.Errorf("spec.conversion.strategy must be '%s', '%s' or '%s'", ConversionStrategyNone, ConversionStrategyMappings, ConversionStrategyExecutor)
+	}
+
+	return nil
+}
+
+// CanConvert returns true if the server itself can convert blueprints between the two versions
+func (sd *BlueprintDefinition) CanConvert(from, to string) bool {
+	_, err := sd.conversionPath(from, to)
+	return err == nil
+}
+
+// conversionPath finds the mappings converting from one version to another, either a direct mapping
+// or two mappings via the storage version
+func (sd *BlueprintDefinition) conversionPath(from, to string) ([]*VersionMapping, error) {
+	if from == to {
+		return nil, nil
+	}
+
+	if sd.ConversionStrategy() != ConversionStrategyMappings {
+		return nil, fmt.Errorf("%w from version '%s' to '%s' with strategy %s", ErrNoConversion, from, to, sd.ConversionStrategy())
+	}
+
+	find := func(from, to string) *VersionMapping {
+		for i := range sd.Spec.Conversion.Mappings {
+			mapping := &sd.Spec.Conversion.Mappings[i]
+			if mapping.From == from && mapping.To == to {
+				return mapping
+			}
+		}
+		return nil
+	}
+
+	if mapping := find(from, to); mapping != nil {
+		return []*VersionMapping{mapping}, nil
+	}
+
+	storage := sd.StorageVersion()
+	if from != storage && to != storage {
+		first := find(from, storage)
+		second := find(storage, to)
+		if first != nil && second != nil {
+			return []*VersionMapping{first, second}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w from version '%s' to '%s'", ErrNoConversion, from, to)
+}
+
+// ConvertBlueprint returns a copy of the blueprint converted to the given version, or to the storage
+// version if version is empty. Blueprints without a version are assumed to be in the storage version.
+func (sd *BlueprintDefinition) ConvertBlueprint(blueprint *Blueprint, version string) (*Blueprint, error) {
+	if version == "" {
+		version = sd.StorageVersion()
+	}
+
+	from := blueprint.Version
+	if from == "" {
+		from = sd.StorageVersion()
+	}
+
+	path, err := sd.conversionPath(from, version)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := copyMap(blueprint.Spec)
+	for _, mapping := range path {
+		spec, err = mapping.Apply(spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	converted := *blueprint
+	converted.Spec = spec
+	converted.Version = version
+
+	return &converted, nil
+}
+
+// Apply converts a spec according to the field mappings. Values are always read from the original
+// spec, so fields can be swapped.
+func (mapping *VersionMapping) Apply(spec map[string]interface{}) (map[string]interface{}, error) {
+	result := copyMap(spec)
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+
+	// Remove all moved fields before setting any, otherwise a swap would delete its own result
+	for _, field := range mapping.Fields {
+		if field.From != "" {
+			deletePointer(result, field.From)
+		}
+	}
+
+	for _, field := range mapping.Fields {
+		if field.To == "" {
+			continue
+		}
+
+		var value interface{}
+		if field.From == "" {
+			value = field.Value
+		} else {
+			var ok bool
+			value, ok = getPointer(spec, field.From)
+			if !ok {
+				continue
+			}
+		}
+
+		if err := setPointer(result, field.To, copyValue(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func parsePointer(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
+
+func getPointer(value interface{}, pointer string) (interface{}, bool) {
+	for _, token := range parsePointer(pointer) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// setPointer sets the value at the pointer, missing objects along the path are created
+func setPointer(spec map[string]interface{}, pointer string, value interface{}) error {
+	tokens := parsePointer(pointer)
+	current := spec
+	for _, token := range tokens[:len(tokens)-1] {
+		child, ok := current[token]
+		if !ok {
+			child = make(map[string]interface{})
+			current[token] = child
+		}
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot set '%s', '%s' is not an object", pointer, token)
+		}
+		current = childMap
+	}
+	current[tokens[len(tokens)-1]] = value
+
+	return nil
+}
+
+func deletePointer(spec map[string]interface{}, pointer string) {
+	tokens := parsePointer(pointer)
+	current := spec
+	for _, token := range tokens[:len(tokens)-1] {
+		child, ok := current[token].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = child
+	}
+	delete(current, tokens[len(tokens)-1])
+}
+
+func (report *BlueprintMigrationReport) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func ConvertJSONToBlueprintMigrationReport(jsonString string) (*BlueprintMigrationReport, error) {
+	var report *BlueprintMigrationReport
+	err := json.Unmarshal([]byte(jsonString), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createVersionedBlueprintDefinition() *BlueprintDefinition {
+	sd := CreateBlueprintDefinition("deployments.compute.io", "compute.io", "", "Deployment", "deployments", "Namespaced", "controller", "reconcile")
+	sd.Metadata.ColonyName = "test-colony"
+	sd.Spec.Versions = []BlueprintDefinitionVersion{
+		{
+			Name:   "v1",
+			Served: true,
+			Schema: &ValidationSchema{
+				Type:       "object",
+				Properties: map[string]SchemaProperty{"image": {Type: "string"}, "cpu": {Type: "string"}},
+			},
+		},
+		{
+			Name:    "v2",
+			Served:  true,
+			Storage: true,
+			Schema: &ValidationSchema{
+				Type: "object",
+				Properties: map[string]SchemaProperty{
+					"image":     {Type: "string"},
+					"resources": {Type: "object", Properties: map[string]SchemaProperty{"cpu": {Type: "string"}}},
+					"tier":      {Type: "string"},
+				},
+				Required:             []string{"image"},
+				AdditionalProperties: &AdditionalProperties{Allowed: false},
+			},
+		},
+	}
+	sd.Spec.Conversion = &ConversionSpec{
+		Strategy: ConversionStrategyMappings,
+		Mappings: []VersionMapping{
+			{From: "v1", To: "v2", Fields: []FieldMapping{{From: "/cpu", To: "/resources/cpu"}, {To: "/tier", Value: "standard"}}},
+			{From: "v2", To: "v1", Fields: []FieldMapping{{From: "/resources/cpu", To: "/cpu"}, {From: "/resources"}, {From: "/tier"}}},
+		},
+	}
+
+	return sd
+}
+
+func TestBlueprintDefinitionVersions(t *testing.T) {
+	sd := createVersionedBlueprintDefinition()
+	assert.Nil(t, sd.Validate())
+	assert.Equal(t, "v2", sd.StorageVersion())
+	assert.Equal(t, "v1", sd.GetVersion("v1").Name)
+	assert.Equal(t, "v2", sd.GetVersion("").Name)
+	assert.Nil(t, sd.GetVersion("v3"))
+	assert.Contains(t, sd.SchemaForVersion("").Required, "image")
+
+	// Definitions without versions serve spec.version
+	legacy := CreateBlueprintDefinition("test", "test.io", "v1", "Test", "tests", "Namespaced", "executor", "reconcile")
+	assert.Nil(t, legacy.Validate())
+	assert.Len(t, legacy.GetVersions(), 1)
+	assert.Equal(t, "v1", legacy.StorageVersion())
+	assert.Equal(t, ConversionStrategyNone, legacy.ConversionStrategy())
+
+	jsonString, err := sd.ToJSON()
+	assert.Nil(t, err)
+	sd2, err := ConvertJSONToBlueprintDefinition(jsonString)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", sd2.StorageVersion())
+	assert.Len(t, sd2.Spec.Conversion.Mappings, 2)
+}
+
+func TestBlueprintDefinitionVersionsInvalid(t *testing.T) {
+	sd := createVersionedBlueprintDefinition()
+	sd.Spec.Versions[0].Storage = true
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Versions[1].Served = false
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Versions[1].Name = "v1"
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Schema = &ValidationSchema{}
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Conversion.Mappings[0].To = "v3"
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Conversion.Mappings[0].Fields[0].To = "resources.cpu"
+	assert.NotNil(t, sd.Validate())
+
+	sd = createVersionedBlueprintDefinition()
+	sd.Spec.Conversion = &ConversionSpec{Strategy: ConversionStrategyExecutor}
+	assert.NotNil(t, sd.Validate())
+	sd.Spec.Conversion.Executor = &ConversionHandler{ExecutorType: "converter", FunctionName: "convert"}
+	assert.Nil(t, sd.Validate())
+
+	sd.Spec.Conversion.Strategy = "Magic"
+	assert.NotNil(t, sd.Validate())
+}
+
+func TestConvertBlueprint(t *testing.T) {
+	sd := createVersionedBlueprintDefinition()
+
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.Version = "v1"
+	blueprint.SetSpec("image", "nginx")
+	blueprint.SetSpec("cpu", "500m")
+
+	converted, err := sd.ConvertBlueprint(blueprint, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", converted.Version)
+	assert.Equal(t, map[string]interface{}{"image": "nginx", "resources": map[string]interface{}{"cpu": "500m"}, "tier": "standard"}, converted.Spec)
+	assert.Nil(t, ValidateSpec(converted.Spec, sd.SchemaForVersion("v2")))
+
+	// The original blueprint is not modified
+	assert.Equal(t, "v1", blueprint.Version)
+	assert.Equal(t, "500m", blueprint.Spec["cpu"])
+
+	back, err := sd.ConvertBlueprint(converted, "v1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"image": "nginx", "cpu": "500m"}, back.Spec)
+
+	same, err := sd.ConvertBlueprint(converted, "v2")
+	assert.Nil(t, err)
+	assert.Equal(t, converted.Spec, same.Spec)
+
+	assert.True(t, sd.CanConvert("v1", "v2"))
+	sd.Spec.Conversion.Mappings = sd.Spec.Conversion.Mappings[:1]
+	assert.False(t, sd.CanConvert("v2", "v1"))
+	_, err = sd.ConvertBlueprint(converted, "v1")
+	assert.True(t, errors.Is(err, ErrNoConversion))
+
+	sd.Spec.Conversion.Strategy = ConversionStrategyExecutor
+	_, err = sd.ConvertBlueprint(blueprint, "v2")
+	assert.True(t, errors.Is(err, ErrNoConversion))
+}
+
+func TestConvertBlueprintViaStorageVersion(t *testing.T) {
+	sd := createVersionedBlueprintDefinition()
+	sd.Spec.Versions = append(sd.Spec.Versions, BlueprintDefinitionVersion{Name: "v3", Served: true})
+	sd.Spec.Conversion.Mappings = append(sd.Spec.Conversion.Mappings,
+		VersionMapping{From: "v2", To: "v3", Fields: []FieldMapping{{From: "/image", To: "/container/image"}}})
+	assert.Nil(t, sd.Validate())
+
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.Version = "v1"
+	blueprint.SetSpec("image", "nginx")
+
+	converted, err := sd.ConvertBlueprint(blueprint, "v3")
+	assert.Nil(t, err)
+	assert.Equal(t, "v3", converted.Version)
+	assert.Equal(t, map[string]interface{}{"container": map[string]interface{}{"image": "nginx"}, "tier": "standard"}, converted.Spec)
+}
+
+func TestVersionMappingApply(t *testing.T) {
+	mapping := &VersionMapping{Fields: []FieldMapping{
+		{From: "/a", To: "/b"},
+		{From: "/b", To: "/a"},
+		{From: "/x~1y", To: "/nested/z"},
+		{From: "/missing", To: "/other"},
+		{From: "/list/1"},
+	}}
+
+	spec := map[string]interface{}{"a": 1, "b": 2, "x/y": "slash", "list": []interface{}{"keep"}}
+	result, err := mapping.Apply(spec)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result["a"])
+	assert.Equal(t, 1, result["b"])
+	assert.Equal(t, "slash", result["nested"].(map[string]interface{})["z"])
+	assert.NotContains(t, result, "x/y")
+	assert.NotContains(t, result, "other")
+	assert.Equal(t, 1, spec["a"])
+
+	mapping = &VersionMapping{Fields: []FieldMapping{{From: "/a", To: "/b/c"}}}
+	_, err = mapping.Apply(map[string]interface{}{"a": 1, "b": "not an object"})
+	assert.NotNil(t, err)
+}
+
+func TestBlueprintValidateAgainstVersionedSD(t *testing.T) {
+	sd := createVersionedBlueprintDefinition()
+
+	blueprint := CreateBlueprint("Deployment", "web", "test-colony")
+	blueprint.SetSpec("image", "nginx")
+	assert.Nil(t, blueprint.ValidateAgainstSD(sd))
+
+	blueprint.Version = "v1"
+	blueprint.SetSpec("cpu", "1")
+	assert.Nil(t, blueprint.ValidateAgainstSD(sd))
+
+	blueprint.Version = "v2"
+	assert.NotNil(t, blueprint.ValidateAgainstSD(sd))
+
+	blueprint.Version = "v3"
+	assert.NotNil(t, blueprint.ValidateAgainstSD(sd))
+
+	sd.Spec.Versions[0].Served = false
+	blueprint.Version = "v1"
+	assert.NotNil(t, blueprint.ValidateAgainstSD(sd))
+}
+
+func TestBlueprintMigrationReportJSON(t *testing.T) {
+	report := &BlueprintMigrationReport{
+		Kind:           "Deployment",
+		StorageVersion: "v2",
+		Results: []*BlueprintMigrationResult{
+			{Name: "web", FromVersion: "v1", ToVersion: "v2", Status: MigrationMigrated},
+			{Name: "db", FromVersion: "v1", ToVersion: "v2", Status: MigrationFailed, Error: "/spec: required field 'image' is missing"},
+		},
+	}
+
+	jsonString, err := report.ToJSON()
+	assert.Nil(t, err)
+
+	report2, err := ConvertJSONToBlueprintMigrationReport(jsonString)
+	assert.Nil(t, err)
+	assert.Equal(t, report, report2)
+
+	_, err = ConvertJSONToBlueprintMigrationReport(jsonString + "error")
+	assert.NotNil(t, err)
+}
@@ -5,8 +5,9 @@ import (
 )
 
 type Colony struct {
-	ID   string `json:"colonyid"`
-	Name string `json:"name"`
+	ID             string `json:"colonyid"`
+	Name           string `json:"name"`
+	RequireFuncReg bool   `json:"requirefuncreg,omitempty"` // Validate submitted args against registered functions
 }
 
 func CreateColony(id string, name string) *Colony {
@@ -72,7 +73,8 @@ func (colony *Colony) Equals(colony2 *Colony) bool {
 	}
 
 	if colony.ID == colony2.ID &&
-		colony.Name == colony2.Name {
+		colony.Name == colony2.Name &&
+		colony.RequireFuncReg == colony2.RequireFuncReg {
 		return true
 	}
 
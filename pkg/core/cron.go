@@ -2,24 +2,63 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
 	"time"
 )
 
+// Policies for runs that were missed, e.g. because no leader was running when they were due
+const (
+	CronMissedRunsOnce    = "once"    // Run once when the cron is triggered again, default
+	CronMissedRunsSkip    = "skip"    // Do not run missed runs, continue with the next scheduled run
+	CronMissedRunsCatchUp = "catchup" // Run every missed run
+)
+
+// Policies for starting a run while previous runs of the cron are still running
+const (
+	CronConcurrencyAllow   = "Allow"   // Start the run, limited by MaxConcurrentRuns if set
+	CronConcurrencyForbid  = "Forbid"  // Do not start the run until the previous run has finished
+	CronConcurrencyReplace = "Replace" // Cancel the running runs and start the run
+)
+
+// DefaultCronRunHistoryLimit is the number of runs kept in the history of a cron if RunHistoryLimit is not set
+const DefaultCronRunHistoryLimit = 10
+
+// CronRun is a processgraph started by a cron
+type CronRun struct {
+	CronID         string    `json:"cronid"`
+	ProcessGraphID string    `json:"processgraphid"`
+	StartTime      time.Time `json:"starttime"`
+	State          int       `json:"state"` // State of the processgraph, e.g. RUNNING or SUCCESS
+}
+
 type Cron struct {
-	ID                      string    `json:"cronid"`
-	InitiatorID             string    `json:"initiatorid"`
-	InitiatorName           string    `json:"initiatorname"`
-	ColonyName              string    `json:"colonyname"`
-	Name                    string    `json:"name"`
-	CronExpression          string    `json:"cronexpression"`
-	Interval                int       `json:"interval"`
-	Random                  bool      `json:"random"`
-	NextRun                 time.Time `json:"nextrun"`
-	LastRun                 time.Time `json:"lastrun"`
-	WorkflowSpec            string    `json:"workflowspec"`
-	PrevProcessGraphID      string    `json:"prevprocessgraphid"`
-	WaitForPrevProcessGraph bool      `json:"waitforprevprocessgraph"`
-	CheckerPeriod           int       `json:"checkerperiod"`
+	ID                      string     `json:"cronid"`
+	InitiatorID             string     `json:"initiatorid"`
+	InitiatorName           string     `json:"initiatorname"`
+	ColonyName              string     `json:"colonyname"`
+	Name                    string     `json:"name"`
+	CronExpression          string     `json:"cronexpression"`
+	Interval                int        `json:"interval"`
+	Random                  bool       `json:"random"`
+	NextRun                 time.Time  `json:"nextrun"`
+	LastRun                 time.Time  `json:"lastrun"`
+	WorkflowSpec            string     `json:"workflowspec"`
+	PrevProcessGraphID      string     `json:"prevprocessgraphid"`
+	WaitForPrevProcessGraph bool       `json:"waitforprevprocessgraph"`
+	CheckerPeriod           int        `json:"checkerperiod"`
+	TimeZone                string     `json:"timezone"`          // IANA time zone the cron expression is evaluated in, server local time if empty
+	StartTime               time.Time  `json:"starttime"`         // If set, the cron does not run before this time
+	EndTime                 time.Time  `json:"endtime"`           // If set, the cron does not run after this time
+	Jitter                  int        `json:"jitter"`            // Every run is delayed by a pseudo-random number of seconds up to Jitter, see JitterDelay
+	MissedRunPolicy         string     `json:"missedrunpolicy"`   // CronMissedRunsOnce, CronMissedRunsSkip or CronMissedRunsCatchUp
+	ConcurrencyPolicy       string     `json:"concurrencypolicy"` // CronConcurrencyAllow, CronConcurrencyForbid or CronConcurrencyReplace
+	MaxConcurrentRuns       int        `json:"maxconcurrentruns"` // Maximum number of running runs with the Allow policy, unlimited if 0
+	Suspended               bool       `json:"suspended"`         // A suspended cron is not triggered until it is resumed
+	RunHistoryLimit         int        `json:"runhistorylimit"`   // Number of runs kept in the history, DefaultCronRunHistoryLimit if 0
+	Runs                    []*CronRun `json:"runs,omitempty"`    // Latest runs, newest first, only set when getting a single cron
 }
 
 func CreateCron(colonyName string, name string, cronExpression string, interval int, random bool, workflowSpec string) *Cron {
@@ -93,7 +132,16 @@ func (cron *Cron) Equals(cron2 *Cron) bool {
 		cron.WorkflowSpec != cron2.WorkflowSpec ||
 		cron.PrevProcessGraphID != cron2.PrevProcessGraphID ||
 		cron.WaitForPrevProcessGraph != cron2.WaitForPrevProcessGraph ||
-		cron.CheckerPeriod != cron2.CheckerPeriod {
+		cron.CheckerPeriod != cron2.CheckerPeriod ||
+		cron.TimeZone != cron2.TimeZone ||
+		cron.StartTime.Unix() != cron2.StartTime.Unix() ||
+		cron.EndTime.Unix() != cron2.EndTime.Unix() ||
+		cron.Jitter != cron2.Jitter ||
+		cron.MissedRunPolicy != cron2.MissedRunPolicy ||
+		cron.ConcurrencyPolicy != cron2.ConcurrencyPolicy ||
+		cron.MaxConcurrentRuns != cron2.MaxConcurrentRuns ||
+		cron.Suspended != cron2.Suspended ||
+		cron.RunHistoryLimit != cron2.RunHistoryLimit {
 		same = false
 	}
 
@@ -109,10 +157,120 @@ func (cron *Cron) ToJSON() (string, error) {
 	return string(jsonBytes), nil
 }
 
+// HasExpired returns true if the next run of the cron, delayed by its jitter, is due
 func (cron *Cron) HasExpired() bool {
 	now := time.Now()
-	if now.Sub(cron.NextRun) > 0 {
+	if now.Sub(cron.DueTime()) > 0 {
 		return true
 	}
 	return false
 }
+
+// JitterDelay returns how long the next run of the cron is delayed, a number of seconds up to Jitter. The
+// delay is derived from the ID and the next run of the cron, so it is the same every time the cron is
+// checked, also by other servers.
+func (cron *Cron) JitterDelay() time.Duration {
+	if cron.Jitter <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(cron.ID))
+	h.Write([]byte(strconv.FormatInt(cron.NextRun.Unix(), 10)))
+
+	return time.Duration(h.Sum64()%uint64(cron.Jitter+1)) * time.Second
+}
+
+// DueTime returns when the next run of the cron is started, i.e. the next run delayed by the jitter
+func (cron *Cron) DueTime() time.Time {
+	return cron.NextRun.Add(cron.JitterDelay())
+}
+
+// HasEnded returns true if the end time of the cron has passed, i.e. it will not run again
+func (cron *Cron) HasEnded() bool {
+	return !cron.EndTime.IsZero() && time.Now().After(cron.EndTime)
+}
+
+// Location returns the time zone the cron expression is evaluated in
+func (cron *Cron) Location() (*time.Location, error) {
+	if cron.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(cron.TimeZone)
+}
+
+// ValidateSchedule checks the time zone, validity window, jitter and missed run policy of the cron
+func (cron *Cron) ValidateSchedule() error {
+	if _, err := cron.Location(); err != nil {
+		return fmt.Errorf("invalid time zone '%s': %v", cron.TimeZone, err)
+	}
+
+	if !cron.StartTime.IsZero() && !cron.EndTime.IsZero() && !cron.EndTime.After(cron.StartTime) {
+		return errors.New("cron end time must be after start time")
+	}
+
+	if cron.Jitter < 0 {
+		return errors.New("cron jitter cannot be negative")
+	}
+
+	switch cron.MissedRunPolicy {
+	case "", CronMissedRunsOnce, CronMissedRunsSkip, CronMissedRunsCatchUp:
+	default:
+		return fmt.Errorf("invalid missed run policy '%s', must be '%s', '%s' or '%s'", cron.MissedRunPolicy, CronMissedRunsOnce, CronMissedRunsSkip, CronMissedRunsCatchUp)
+	}
+
+	return nil
+}
+
+// GetConcurrencyPolicy returns the concurrency policy of the cron. Crons without a policy are Forbid if
+// WaitForPrevProcessGraph is set, otherwise Allow.
+func (cron *Cron) GetConcurrencyPolicy() string {
+	if cron.ConcurrencyPolicy != "" {
+		return cron.ConcurrencyPolicy
+	}
+
+	if cron.WaitForPrevProcessGraph {
+		return CronConcurrencyForbid
+	}
+
+	return CronConcurrencyAllow
+}
+
+// GetRunHistoryLimit returns the number of runs kept in the history of the cron
+func (cron *Cron) GetRunHistoryLimit() int {
+	if cron.RunHistoryLimit > 0 {
+		return cron.RunHistoryLimit
+	}
+
+	return DefaultCronRunHistoryLimit
+}
+
+// ValidateConcurrency checks the concurrency policy, max concurrent runs and run history limit of the cron.
+// Running runs are counted among the runs in the history, so max concurrent runs cannot exceed the run
+// history limit.
+func (cron *Cron) ValidateConcurrency() error {
+	switch cron.ConcurrencyPolicy {
+	case "", CronConcurrencyAllow, CronConcurrencyForbid, CronConcurrencyReplace:
+	default:
+		return fmt.Errorf("invalid concurrency policy '%s', must be '%s', '%s' or '%s'", cron.ConcurrencyPolicy, CronConcurrencyAllow, CronConcurrencyForbid, CronConcurrencyReplace)
+	}
+
+	if cron.MaxConcurrentRuns < 0 {
+		return errors.New("cron max concurrent runs cannot be negative")
+	}
+
+	if cron.MaxConcurrentRuns > 0 && cron.GetConcurrencyPolicy() != CronConcurrencyAllow {
+		return fmt.Errorf("cron max concurrent runs is only supported with the '%s' concurrency policy", CronConcurrencyAllow)
+	}
+
+	if cron.RunHistoryLimit < 0 {
+		return errors.New("cron run history limit cannot be negative")
+	}
+
+	if cron.MaxConcurrentRuns > cron.GetRunHistoryLimit() {
+		return fmt.Errorf("cron max concurrent runs cannot exceed the run history limit (%d)", cron.GetRunHistoryLimit())
+	}
+
+	return nil
+}
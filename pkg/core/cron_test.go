@@ -57,6 +57,15 @@ func TestIsCronArraysEquals(t *testing.T) {
 func TestCronToJSON(t *testing.T) {
 	cron := CreateCron(GenerateRandomID(), "test_name1", "* * * * * *", 0, false, "workflow1")
 	cron.CheckerPeriod = 100
+	cron.TimeZone = "Europe/Stockholm"
+	cron.StartTime = time.Now()
+	cron.EndTime = time.Now().Add(time.Hour)
+	cron.Jitter = 10
+	cron.MissedRunPolicy = CronMissedRunsCatchUp
+	cron.ConcurrencyPolicy = CronConcurrencyReplace
+	cron.MaxConcurrentRuns = 2
+	cron.Suspended = true
+	cron.RunHistoryLimit = 5
 	jsonStr, err := cron.ToJSON()
 	assert.Nil(t, err)
 
@@ -90,3 +99,112 @@ func TestCronHasExpire(t *testing.T) {
 	cron.NextRun = time.Now().Add(100 * time.Second)
 	assert.False(t, cron.HasExpired())
 }
+
+func TestCronJitterDelay(t *testing.T) {
+	cron := CreateCron(GenerateRandomID(), "test_name", "", 60, false, "workflow")
+	cron.NextRun = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Duration(0), cron.JitterDelay())
+	assert.Equal(t, cron.NextRun, cron.DueTime())
+
+	cron.Jitter = 10
+	delays := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		delay := cron.JitterDelay()
+		assert.True(t, delay >= 0 && delay <= 10*time.Second, delay)
+		assert.Equal(t, delay, cron.JitterDelay())
+		assert.Equal(t, cron.NextRun.Add(delay), cron.DueTime())
+		delays[delay] = true
+		cron.NextRun = cron.NextRun.Add(time.Minute)
+	}
+	assert.True(t, len(delays) > 1)
+
+	// A run is not due until its jitter delay has passed
+	cron.Jitter = 3600
+	cron.NextRun = time.Now().Add(-500 * time.Millisecond)
+	assert.Equal(t, cron.JitterDelay() == 0, cron.HasExpired())
+}
+
+func TestCronHasEnded(t *testing.T) {
+	cron := CreateCron(GenerateRandomID(), "test_name", "* * * * * *", 0, false, "workflow")
+	assert.False(t, cron.HasEnded())
+	cron.EndTime = time.Now().Add(100 * time.Second)
+	assert.False(t, cron.HasEnded())
+	cron.EndTime = time.Now().Add(-100 * time.Second)
+	assert.True(t, cron.HasEnded())
+}
+
+func TestCronValidateSchedule(t *testing.T) {
+	cron := CreateCron(GenerateRandomID(), "test_name", "0 2 * * *", -1, false, "workflow")
+	assert.Nil(t, cron.ValidateSchedule())
+
+	location, err := cron.Location()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Local, location)
+
+	cron.TimeZone = "America/New_York"
+	assert.Nil(t, cron.ValidateSchedule())
+	location, err = cron.Location()
+	assert.Nil(t, err)
+	assert.Equal(t, "America/New_York", location.String())
+
+	cron.TimeZone = "Mars/Olympus_Mons"
+	assert.NotNil(t, cron.ValidateSchedule())
+	cron.TimeZone = ""
+
+	cron.StartTime = time.Now()
+	cron.EndTime = cron.StartTime.Add(-time.Hour)
+	assert.NotNil(t, cron.ValidateSchedule())
+	cron.EndTime = time.Time{}
+
+	cron.Jitter = -1
+	assert.NotNil(t, cron.ValidateSchedule())
+	cron.Jitter = 0
+
+	cron.MissedRunPolicy = "sometimes"
+	assert.NotNil(t, cron.ValidateSchedule())
+	cron.MissedRunPolicy = CronMissedRunsSkip
+	assert.Nil(t, cron.ValidateSchedule())
+}
+
+func TestCronConcurrencyPolicy(t *testing.T) {
+	cron := CreateCron(GenerateRandomID(), "test_name", "0 2 * * *", -1, false, "workflow")
+	assert.Equal(t, CronConcurrencyAllow, cron.GetConcurrencyPolicy())
+	assert.Equal(t, DefaultCronRunHistoryLimit, cron.GetRunHistoryLimit())
+
+	cron.WaitForPrevProcessGraph = true
+	assert.Equal(t, CronConcurrencyForbid, cron.GetConcurrencyPolicy())
+
+	cron.ConcurrencyPolicy = CronConcurrencyReplace
+	assert.Equal(t, CronConcurrencyReplace, cron.GetConcurrencyPolicy())
+
+	cron.RunHistoryLimit = 3
+	assert.Equal(t, 3, cron.GetRunHistoryLimit())
+}
+
+func TestCronValidateConcurrency(t *testing.T) {
+	cron := CreateCron(GenerateRandomID(), "test_name", "0 2 * * *", -1, false, "workflow")
+	assert.Nil(t, cron.ValidateConcurrency())
+
+	cron.ConcurrencyPolicy = "Sometimes"
+	assert.NotNil(t, cron.ValidateConcurrency())
+
+	cron.ConcurrencyPolicy = CronConcurrencyAllow
+	cron.MaxConcurrentRuns = 2
+	assert.Nil(t, cron.ValidateConcurrency())
+
+	cron.MaxConcurrentRuns = -1
+	assert.NotNil(t, cron.ValidateConcurrency())
+
+	cron.MaxConcurrentRuns = DefaultCronRunHistoryLimit + 1
+	assert.NotNil(t, cron.ValidateConcurrency())
+	cron.RunHistoryLimit = DefaultCronRunHistoryLimit + 1
+	assert.Nil(t, cron.ValidateConcurrency())
+
+	cron.ConcurrencyPolicy = CronConcurrencyForbid
+	assert.NotNil(t, cron.ValidateConcurrency())
+	cron.MaxConcurrentRuns = 0
+	assert.Nil(t, cron.ValidateConcurrency())
+
+	cron.RunHistoryLimit = -1
+	assert.NotNil(t, cron.ValidateConcurrency())
+}
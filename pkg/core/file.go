@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+// S3Object describes where a file is stored. Files stored in a registered StorageBackend only set
+// Object, the endpoint and credentials are looked up from Reference.Backend by the server.
 type S3Object struct {
 	Server        string `json:"server"`
 	Port          int    `json:"port"`
@@ -20,20 +22,37 @@ type S3Object struct {
 
 type Reference struct {
 	Protocol string   `json:"protocol"`
+	Backend  string   `json:"backend,omitempty"`
 	S3Object S3Object `json:"s3object"`
 }
 
+// FileChunk is a content-defined chunk of a file, stored as an object named by ChunkObject. Chunks are
+// shared by all files of a colony with the same content, so they must not be removed when a file is removed.
+type FileChunk struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
 type File struct {
-	ID             string    `json:"fileid"`
-	ColonyName     string    `json:"colonyname"`
-	Label          string    `json:"label"`
-	Name           string    `json:"name"`
-	Size           int64     `json:"size"`
-	SequenceNumber int64     `json:"sequencenr"`
-	Checksum       string    `json:"checksum"`
-	ChecksumAlg    string    `json:"checksumalg"`
-	Reference      Reference `json:"ref"`
-	Added          time.Time `json:"added"`
+	ID             string      `json:"fileid"`
+	ColonyName     string      `json:"colonyname"`
+	Label          string      `json:"label"`
+	Name           string      `json:"name"`
+	Size           int64       `json:"size"`
+	SequenceNumber int64       `json:"sequencenr"`
+	Checksum       string      `json:"checksum"`
+	ChecksumAlg    string      `json:"checksumalg"`
+	Reference      Reference   `json:"ref"`
+	Chunks         []FileChunk `json:"chunks,omitempty"`
+	// Shared is set when the object is referenced by other files, e.g. after a snapshot is cloned or
+	// restored. Shared objects are not removed with the file, they are removed by garbage collection.
+	Shared bool      `json:"shared,omitempty"`
+	Added  time.Time `json:"added"`
+}
+
+// ChunkObject returns the object name of a chunk, chunks are stored in the namespace of the colony
+func ChunkObject(colonyName string, hash string) string {
+	return StorageObjectPrefix(colonyName) + "chunk-" + hash
 }
 
 func ConvertJSONToFile(jsonString string) (*File, error) {
@@ -83,6 +102,23 @@ func (file *File) Equals(file2 *File) bool {
 	if file.Reference.Protocol != file2.Reference.Protocol {
 		same = false
 	}
+	if file.Reference.Backend != file2.Reference.Backend {
+		same = false
+	}
+
+	if len(file.Chunks) != len(file2.Chunks) {
+		same = false
+	} else {
+		for i := range file.Chunks {
+			if file.Chunks[i] != file2.Chunks[i] {
+				same = false
+			}
+		}
+	}
+
+	if file.Shared != file2.Shared {
+		same = false
+	}
 
 	if file.ID != file2.ID {
 		same = false
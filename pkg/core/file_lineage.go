@@ -0,0 +1,117 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	LineageInput  = "input"
+	LineageOutput = "output"
+)
+
+// FileLineage links a process to a file revision it consumed or produced. Inputs are the revisions mounted
+// when the process is assigned, i.e. the files of its snapshot mounts and the latest revisions of its sync
+// dir labels. Outputs are revisions added by the executor assigned to the process while it was running.
+type FileLineage struct {
+	ColonyName     string    `json:"colonyname"`
+	ProcessID      string    `json:"processid"`
+	ProcessGraphID string    `json:"processgraphid,omitempty"`
+	Direction      string    `json:"direction"`
+	FileID         string    `json:"fileid"`
+	SnapshotID     string    `json:"snapshotid,omitempty"`
+	Label          string    `json:"label"`
+	Name           string    `json:"name"`
+	Checksum       string    `json:"checksum"`
+	Added          time.Time `json:"added"`
+}
+
+func CreateFileLineage(process *Process, direction string, file *File, snapshotID string) *FileLineage {
+	return &FileLineage{
+		ColonyName:     process.FunctionSpec.Conditions.ColonyName,
+		ProcessID:      process.ID,
+		ProcessGraphID: process.ProcessGraphID,
+		Direction:      direction,
+		FileID:         file.ID,
+		SnapshotID:     snapshotID,
+		Label:          file.Label,
+		Name:           file.Name,
+		Checksum:       file.Checksum,
+	}
+}
+
+func ConvertJSONToFileLineage(jsonString string) (*FileLineage, error) {
+	var lineage *FileLineage
+	err := json.Unmarshal([]byte(jsonString), &lineage)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineage, nil
+}
+
+func ConvertJSONToFileLineageArray(jsonString string) ([]*FileLineage, error) {
+	var lineage []*FileLineage
+
+	err := json.Unmarshal([]byte(jsonString), &lineage)
+	if err != nil {
+		return lineage, err
+	}
+
+	return lineage, nil
+}
+
+func ConvertFileLineageArrayToJSON(lineage []*FileLineage) (string, error) {
+	jsonBytes, err := json.Marshal(lineage)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsFileLineageArraysEqual(lineage1 []*FileLineage, lineage2 []*FileLineage) bool {
+	counter := 0
+	for _, l1 := range lineage1 {
+		for _, l2 := range lineage2 {
+			if l1.Equals(l2) {
+				counter++
+			}
+		}
+	}
+
+	if counter == len(lineage1) && counter == len(lineage2) {
+		return true
+	}
+
+	return false
+}
+
+func (lineage *FileLineage) Equals(lineage2 *FileLineage) bool {
+	if lineage2 == nil {
+		return false
+	}
+
+	if lineage.ColonyName != lineage2.ColonyName ||
+		lineage.ProcessID != lineage2.ProcessID ||
+		lineage.ProcessGraphID != lineage2.ProcessGraphID ||
+		lineage.Direction != lineage2.Direction ||
+		lineage.FileID != lineage2.FileID ||
+		lineage.SnapshotID != lineage2.SnapshotID ||
+		lineage.Label != lineage2.Label ||
+		lineage.Name != lineage2.Name ||
+		lineage.Checksum != lineage2.Checksum {
+		return false
+	}
+
+	return true
+}
+
+func (lineage *FileLineage) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(lineage)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
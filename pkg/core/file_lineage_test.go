@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestLineageProcess() *Process {
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = "test_colony"
+	process := CreateProcess(funcSpec)
+	process.ProcessGraphID = GenerateRandomID()
+
+	return process
+}
+
+func TestFileLineageToJSON(t *testing.T) {
+	process := createTestLineageProcess()
+	file := &File{ID: GenerateRandomID(), Label: "/data", Name: "file1", Checksum: "checksum1"}
+	lineage := CreateFileLineage(process, LineageInput, file, GenerateRandomID())
+
+	assert.Equal(t, "test_colony", lineage.ColonyName)
+	assert.Equal(t, process.ID, lineage.ProcessID)
+	assert.Equal(t, process.ProcessGraphID, lineage.ProcessGraphID)
+	assert.Equal(t, file.ID, lineage.FileID)
+
+	jsonStr, err := lineage.ToJSON()
+	assert.Nil(t, err)
+
+	lineage2, err := ConvertJSONToFileLineage(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, lineage.Equals(lineage2))
+	assert.False(t, lineage.Equals(nil))
+
+	lineage2.Direction = LineageOutput
+	assert.False(t, lineage.Equals(lineage2))
+
+	_, err = ConvertJSONToFileLineage("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestFileLineageArrayToJSON(t *testing.T) {
+	process := createTestLineageProcess()
+	lineage1 := CreateFileLineage(process, LineageInput, &File{ID: GenerateRandomID(), Label: "/data", Name: "file1"}, "")
+	lineage2 := CreateFileLineage(process, LineageOutput, &File{ID: GenerateRandomID(), Label: "/result", Name: "file2"}, "")
+	lineage := []*FileLineage{lineage1, lineage2}
+
+	jsonStr, err := ConvertFileLineageArrayToJSON(lineage)
+	assert.Nil(t, err)
+
+	lineage3, err := ConvertJSONToFileLineageArray(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, IsFileLineageArraysEqual(lineage, lineage3))
+	assert.False(t, IsFileLineageArraysEqual(lineage, []*FileLineage{lineage1}))
+
+	_, err = ConvertJSONToFileLineageArray("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileRetentionPolicy limits the number of revisions kept of each file with a label, or a sub label of it.
+// A revision is kept if it is one of the KeepLast latest revisions, or if it was added within KeepDays days.
+// Zero disables a rule. The latest revision and revisions referenced by snapshots are always kept.
+type FileRetentionPolicy struct {
+	ColonyName string    `json:"colonyname"`
+	Label      string    `json:"label"`
+	KeepLast   int       `json:"keeplast"`
+	KeepDays   int       `json:"keepdays"`
+	Added      time.Time `json:"added"`
+}
+
+func CreateFileRetentionPolicy(colonyName string, label string, keepLast int, keepDays int) *FileRetentionPolicy {
+	return &FileRetentionPolicy{
+		ColonyName: colonyName,
+		Label:      label,
+		KeepLast:   keepLast,
+		KeepDays:   keepDays,
+	}
+}
+
+func ConvertJSONToFileRetentionPolicy(jsonString string) (*FileRetentionPolicy, error) {
+	var policy *FileRetentionPolicy
+	err := json.Unmarshal([]byte(jsonString), &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func ConvertJSONToFileRetentionPolicyArray(jsonString string) ([]*FileRetentionPolicy, error) {
+	var policies []*FileRetentionPolicy
+
+	err := json.Unmarshal([]byte(jsonString), &policies)
+	if err != nil {
+		return policies, err
+	}
+
+	return policies, nil
+}
+
+func ConvertFileRetentionPolicyArrayToJSON(policies []*FileRetentionPolicy) (string, error) {
+	jsonBytes, err := json.Marshal(policies)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsFileRetentionPolicyArraysEqual(policies1 []*FileRetentionPolicy, policies2 []*FileRetentionPolicy) bool {
+	counter := 0
+	for _, policy1 := range policies1 {
+		for _, policy2 := range policies2 {
+			if policy1.Equals(policy2) {
+				counter++
+			}
+		}
+	}
+
+	if counter == len(policies1) && counter == len(policies2) {
+		return true
+	}
+
+	return false
+}
+
+// FindFileRetentionPolicy returns the policy of the longest label that label is equal to or a sub label of,
+// or nil if no policy applies
+func FindFileRetentionPolicy(policies []*FileRetentionPolicy, label string) *FileRetentionPolicy {
+	var found *FileRetentionPolicy
+	for _, policy := range policies {
+		if policy.Label != label && !strings.HasPrefix(label, strings.TrimRight(policy.Label, "/")+"/") {
+			continue
+		}
+		if found == nil || len(policy.Label) > len(found.Label) {
+			found = policy
+		}
+	}
+
+	return found
+}
+
+// StaleRevisions returns the revisions of a file not retained by the policy. Revisions with an ID in
+// retainedIDs, e.g. revisions referenced by snapshots, are always retained.
+func (policy *FileRetentionPolicy) StaleRevisions(revisions []*File, retainedIDs map[string]bool, now time.Time) []*File {
+	if policy.KeepLast == 0 && policy.KeepDays == 0 {
+		return nil
+	}
+
+	sorted := make([]*File, len(revisions))
+	copy(sorted, revisions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SequenceNumber > sorted[j].SequenceNumber
+	})
+
+	var stale []*File
+	for i, revision := range sorted {
+		if i == 0 || retainedIDs[revision.ID] {
+			continue
+		}
+		if policy.KeepLast > 0 && i < policy.KeepLast {
+			continue
+		}
+		if policy.KeepDays > 0 && now.Sub(revision.Added) < time.Duration(policy.KeepDays)*24*time.Hour {
+			continue
+		}
+		stale = append(stale, revision)
+	}
+
+	return stale
+}
+
+func (policy *FileRetentionPolicy) Equals(policy2 *FileRetentionPolicy) bool {
+	if policy2 == nil {
+		return false
+	}
+
+	if policy.ColonyName != policy2.ColonyName ||
+		policy.Label != policy2.Label ||
+		policy.KeepLast != policy2.KeepLast ||
+		policy.KeepDays != policy2.KeepDays {
+		return false
+	}
+
+	return true
+}
+
+func (policy *FileRetentionPolicy) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+// FileGCReport describes the file revisions and storage objects removed by garbage collection, or the ones
+// that would be removed if DryRun is set
+type FileGCReport struct {
+	DryRun              bool             `json:"dryrun"`
+	StaleRevisions      []*File          `json:"stalerevisions"`
+	UnreferencedObjects []*StorageObject `json:"unreferencedobjects"`
+	// RetainedObjects are the objects in the namespace of the colony that must be kept, i.e. objects and
+	// chunks of retained revisions and snapshots, and objects issued within the grace period. Clients use
+	// them to sweep objects stored with their storage drivers.
+	RetainedObjects []string `json:"retainedobjects"`
+	// UnreferencedDriverObjects are set by clients, see pkg/fs
+	UnreferencedDriverObjects []*DriverObject `json:"unreferenceddriverobjects"`
+	ReclaimedBytes            int64           `json:"reclaimedbytes"`
+}
+
+// DriverObject is an object stored by a client using a storage driver instead of a storage backend
+type DriverObject struct {
+	Protocol string    `json:"protocol"`
+	Object   string    `json:"object"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+func ConvertJSONToFileGCReport(jsonString string) (*FileGCReport, error) {
+	var report *FileGCReport
+	err := json.Unmarshal([]byte(jsonString), &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (report *FileGCReport) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
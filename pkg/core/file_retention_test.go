@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRetentionPolicyToJSON(t *testing.T) {
+	policy := CreateFileRetentionPolicy("test_colony", "/test", 5, 30)
+
+	jsonStr, err := policy.ToJSON()
+	assert.Nil(t, err)
+
+	policy2, err := ConvertJSONToFileRetentionPolicy(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(policy2))
+	assert.False(t, policy.Equals(nil))
+
+	_, err = ConvertJSONToFileRetentionPolicy("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestFileRetentionPolicyArrayToJSON(t *testing.T) {
+	policy1 := CreateFileRetentionPolicy("test_colony", "/test1", 5, 0)
+	policy2 := CreateFileRetentionPolicy("test_colony", "/test2", 0, 30)
+	policies := []*FileRetentionPolicy{policy1, policy2}
+
+	jsonStr, err := ConvertFileRetentionPolicyArrayToJSON(policies)
+	assert.Nil(t, err)
+
+	policies2, err := ConvertJSONToFileRetentionPolicyArray(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, IsFileRetentionPolicyArraysEqual(policies, policies2))
+	assert.False(t, IsFileRetentionPolicyArraysEqual(policies, []*FileRetentionPolicy{policy1}))
+
+	_, err = ConvertJSONToFileRetentionPolicyArray("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestFindFileRetentionPolicy(t *testing.T) {
+	policy1 := CreateFileRetentionPolicy("test_colony", "/data", 5, 0)
+	policy2 := CreateFileRetentionPolicy("test_colony", "/data/models", 2, 0)
+	policies := []*FileRetentionPolicy{policy1, policy2}
+
+	assert.Equal(t, policy1, FindFileRetentionPolicy(policies, "/data"))
+	assert.Equal(t, policy1, FindFileRetentionPolicy(policies, "/data/images"))
+	assert.Equal(t, policy2, FindFileRetentionPolicy(policies, "/data/models"))
+	assert.Equal(t, policy2, FindFileRetentionPolicy(policies, "/data/models/v1"))
+	assert.Nil(t, FindFileRetentionPolicy(policies, "/dataset"))
+	assert.Nil(t, FindFileRetentionPolicy(policies, "/other"))
+}
+
+func TestFileRetentionPolicyStaleRevisions(t *testing.T) {
+	now := time.Now()
+	var revisions []*File
+	for i := 0; i < 5; i++ {
+		revision := &File{ID: GenerateRandomID(), SequenceNumber: int64(5 - i), Added: now.Add(-time.Duration(i) * 24 * time.Hour)}
+		revisions = append([]*File{revision}, revisions...) // Oldest first
+	}
+	// revisions[4] is the latest revision, revisions[0] was added 4 days ago
+
+	policy := CreateFileRetentionPolicy("test_colony", "/test", 2, 0)
+	stale := policy.StaleRevisions(revisions, map[string]bool{}, now)
+	assert.Equal(t, []*File{revisions[2], revisions[1], revisions[0]}, stale)
+
+	// Revisions referenced by snapshots are retained
+	stale = policy.StaleRevisions(revisions, map[string]bool{revisions[1].ID: true}, now)
+	assert.Equal(t, []*File{revisions[2], revisions[0]}, stale)
+
+	policy = CreateFileRetentionPolicy("test_colony", "/test", 0, 2)
+	stale = policy.StaleRevisions(revisions, map[string]bool{}, now)
+	assert.Equal(t, []*File{revisions[2], revisions[1], revisions[0]}, stale)
+
+	// A revision is retained if any rule retains it
+	policy = CreateFileRetentionPolicy("test_colony", "/test", 4, 2)
+	stale = policy.StaleRevisions(revisions, map[string]bool{}, now)
+	assert.Equal(t, []*File{revisions[0]}, stale)
+
+	// The latest revision is always retained
+	policy = CreateFileRetentionPolicy("test_colony", "/test", 0, 0)
+	assert.Len(t, policy.StaleRevisions(revisions, map[string]bool{}, now), 0)
+	policy = CreateFileRetentionPolicy("test_colony", "/test", 1, 0)
+	assert.Len(t, policy.StaleRevisions(revisions, map[string]bool{}, now), 4)
+}
+
+func TestFileGCReportToJSON(t *testing.T) {
+	report := &FileGCReport{
+		DryRun:              true,
+		StaleRevisions:      []*File{{ID: "test_fileid", ColonyName: "test_colony", Label: "/test", Name: "test_file", Size: 10}},
+		UnreferencedObjects: []*StorageObject{CreateStorageObject("test_colony", "test_backend")},
+		ReclaimedBytes:      10,
+	}
+
+	jsonStr, err := report.ToJSON()
+	assert.Nil(t, err)
+
+	report2, err := ConvertJSONToFileGCReport(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, report2.DryRun)
+	assert.Len(t, report2.StaleRevisions, 1)
+	assert.True(t, report.StaleRevisions[0].Equals(report2.StaleRevisions[0]))
+	assert.Len(t, report2.UnreferencedObjects, 1)
+	assert.Equal(t, report.UnreferencedObjects[0].Object, report2.UnreferencedObjects[0].Object)
+	assert.Equal(t, int64(10), report2.ReclaimedBytes)
+
+	_, err = ConvertJSONToFileGCReport("invalid json")
+	assert.NotNil(t, err)
+}
@@ -60,6 +60,9 @@ func TestFileEqualsAllFields(t *testing.T) {
 		{"Object", func(f *File) { f.Reference.S3Object.Object = "different" }},
 		{"Bucket", func(f *File) { f.Reference.S3Object.Bucket = "different" }},
 		{"Protocol", func(f *File) { f.Reference.Protocol = "different" }},
+		{"Backend", func(f *File) { f.Reference.Backend = "different" }},
+		{"Chunks", func(f *File) { f.Chunks = []FileChunk{{Hash: "different", Size: 1}} }},
+		{"Shared", func(f *File) { f.Shared = true }},
 		{"ID", func(f *File) { f.ID = "different" }},
 		{"ColonyName", func(f *File) { f.ColonyName = "different" }},
 		{"Label", func(f *File) { f.Label = "different" }},
@@ -82,6 +85,8 @@ func TestFileEqualsAllFields(t *testing.T) {
 
 func TestFileToJSON(t *testing.T) {
 	file1 := createTestFile()
+	file1.Chunks = []FileChunk{{Hash: "hash1", Size: 100}, {Hash: "hash2", Size: 200}}
+	file1.Shared = true
 	jsonStr, err := file1.ToJSON()
 	assert.Nil(t, err)
 
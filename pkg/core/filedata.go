@@ -3,10 +3,17 @@ package core
 import "encoding/json"
 
 type FileData struct {
-	Name       string `json:"name"`
-	Checksum   string `json:"checksum"`
-	Size       int64  `json:"size"`
-	S3Filename string `json:"s3filename"`
+	FileID        string `json:"fileid,omitempty"`
+	Name          string `json:"name"`
+	Checksum      string `json:"checksum"`
+	Size          int64  `json:"size"`
+	S3Filename    string `json:"s3filename"`
+	Protocol      string `json:"protocol,omitempty"`
+	Backend       string `json:"backend,omitempty"`
+	Chunked       bool   `json:"chunked,omitempty"`
+	Shared        bool   `json:"shared,omitempty"`
+	EncryptionKey string `json:"encryptionkey,omitempty"`
+	EncryptionAlg string `json:"encryptionalg,omitempty"`
 }
 
 func ConvertJSONToFileData(jsonString string) (*FileData, error) {
@@ -27,6 +27,8 @@ type Function struct {
 	AvgWaitTime  float64 `json:"avgwaittime"`
 	AvgExecTime  float64 `json:"avgexectime"`
 	LocationName string  `json:"locationname,omitempty"`
+	// RequireFuncReg enables validation of submitted args against Args
+	RequireFuncReg bool `json:"requirefuncreg,omitempty"`
 }
 
 func CreateFunction(functionID string,
@@ -150,7 +152,8 @@ func (function *Function) Equals(function2 *Function) bool {
 		function.MaxExecTime != function2.MaxExecTime ||
 		function.AvgWaitTime != function2.AvgWaitTime ||
 		function.AvgExecTime != function2.AvgExecTime ||
-		function.LocationName != function2.LocationName {
+		function.LocationName != function2.LocationName ||
+		function.RequireFuncReg != function2.RequireFuncReg {
 		return false
 	}
 
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Function argument types, the types follow JSON Schema naming
+const (
+	FunctionArgString  = "string"
+	FunctionArgInteger = "integer"
+	FunctionArgNumber  = "number"
+	FunctionArgBoolean = "boolean"
+	FunctionArgObject  = "object"
+	FunctionArgArray   = "array"
+)
+
+// Decode converts a value to the type declared by the argument and checks that it is one of the enum values, if any.
+// Integers are returned as int64, numbers as float64, objects as map[string]interface{} and arrays as []interface{}.
+// String values are parsed so that arguments submitted from the CLI can be used.
+func (arg *FunctionArg) Decode(value interface{}) (interface{}, error) {
+	var decoded interface{}
+	var err error
+
+	switch arg.Type {
+	case FunctionArgString:
+		decoded, err = argToString(value)
+	case FunctionArgInteger, "int":
+		decoded, err = argToInt(value)
+	case FunctionArgNumber, "float":
+		decoded, err = argToFloat(value)
+	case FunctionArgBoolean, "bool":
+		decoded, err = argToBool(value)
+	case FunctionArgObject:
+		decoded, err = argToObject(value)
+	case FunctionArgArray:
+		decoded, err = argToArray(value)
+	case "", "any":
+		decoded = value
+	default:
+		return nil, errors.New("Argument <" + arg.Name + "> has unsupported type <" + arg.Type + ">")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid argument <%s>, expected %s: %w", arg.Name, arg.Type, err)
+	}
+
+	if len(arg.Enum) > 0 {
+		str := fmt.Sprint(decoded)
+		for _, enum := range arg.Enum {
+			if str == enum {
+				return decoded, nil
+			}
+		}
+		return nil, fmt.Errorf("Invalid argument <%s>, %v is not one of %v", arg.Name, decoded, arg.Enum)
+	}
+
+	return decoded, nil
+}
+
+// DecodeFunctionArgs decodes the arguments of a function spec as described by args. An argument is taken
+// from KwArgs by name, or from Args by its position in args. Arguments not described by args are ignored.
+func DecodeFunctionArgs(funcSpec *FunctionSpec, args []*FunctionArg) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{})
+
+	for i, arg := range args {
+		if arg == nil {
+			continue
+		}
+
+		value, found := funcSpec.KwArgs[arg.Name]
+		if !found && i < len(funcSpec.Args) {
+			value = funcSpec.Args[i]
+			found = true
+		}
+
+		if !found || value == nil {
+			if arg.Required {
+				return nil, errors.New("Missing required argument <" + arg.Name + ">")
+			}
+			continue
+		}
+
+		decodedValue, err := arg.Decode(value)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded[arg.Name] = decodedValue
+	}
+
+	return decoded, nil
+}
+
+// ValidateFunctionArgs checks the arguments of a function spec against args. Unlike DecodeFunctionArgs,
+// unknown kwargs, extra positional args and args given both by position and name are rejected.
+func ValidateFunctionArgs(funcSpec *FunctionSpec, args []*FunctionArg) error {
+	known := make(map[string]int)
+	for i, arg := range args {
+		if arg != nil {
+			known[arg.Name] = i
+		}
+	}
+
+	if len(funcSpec.Args) > len(args) {
+		return fmt.Errorf("Too many arguments to function <%s>, expected at most %d but got %d", funcSpec.FuncName, len(args), len(funcSpec.Args))
+	}
+
+	for name := range funcSpec.KwArgs {
+		i, ok := known[name]
+		if !ok {
+			return errors.New("Unknown argument <" + name + "> to function <" + funcSpec.FuncName + ">")
+		}
+		if i < len(funcSpec.Args) {
+			return errors.New("Argument <" + name + "> to function <" + funcSpec.FuncName + "> given both by position and name")
+		}
+	}
+
+	_, err := DecodeFunctionArgs(funcSpec, args)
+
+	return err
+}
+
+func argToString(value interface{}) (string, error) {
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("got %T", value)
+}
+
+func argToInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("got %v", v)
+		}
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	return 0, fmt.Errorf("got %T", value)
+}
+
+func argToFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return 0, fmt.Errorf("got %T", value)
+}
+
+func argToBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+
+	return false, fmt.Errorf("got %T", value)
+}
+
+func argToObject(value interface{}) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &obj); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("got %T", value)
+}
+
+func argToArray(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		var arr []interface{}
+		if err := json.Unmarshal([]byte(v), &arr); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+
+	return nil, fmt.Errorf("got %T", value)
+}
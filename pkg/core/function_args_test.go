@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestFunctionArgs() []*FunctionArg {
+	return []*FunctionArg{
+		CreateFunctionArg("query", FunctionArgString, "The search query", true, nil),
+		CreateFunctionArg("limit", FunctionArgInteger, "Maximum results", false, nil),
+		CreateFunctionArg("format", FunctionArgString, "Output format", false, []string{"json", "text"}),
+	}
+}
+
+func TestFunctionArgDecode(t *testing.T) {
+	arg := CreateFunctionArg("limit", FunctionArgInteger, "", false, []string{"10", "20"})
+
+	decoded, err := arg.Decode("10")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), decoded)
+
+	decoded, err = arg.Decode(float64(20))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), decoded)
+
+	_, err = arg.Decode(float64(30))
+	assert.NotNil(t, err)
+
+	_, err = arg.Decode(true)
+	assert.NotNil(t, err)
+
+	// Only strings are accepted for string args
+	arg = CreateFunctionArg("query", FunctionArgString, "", false, nil)
+	decoded, err = arg.Decode("colonies")
+	assert.Nil(t, err)
+	assert.Equal(t, "colonies", decoded)
+
+	_, err = arg.Decode(float64(10))
+	assert.NotNil(t, err)
+
+	_, err = arg.Decode(true)
+	assert.NotNil(t, err)
+}
+
+func TestValidateFunctionArgs(t *testing.T) {
+	args := createTestFunctionArgs()
+
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.FuncName = "search"
+	funcSpec.Args = []interface{}{"colonies"}
+	funcSpec.KwArgs["limit"] = float64(10)
+	funcSpec.KwArgs["format"] = "text"
+	assert.Nil(t, ValidateFunctionArgs(funcSpec, args))
+
+	// Unknown kwarg
+	funcSpec.KwArgs["limt"] = float64(10)
+	err := ValidateFunctionArgs(funcSpec, args)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "limt")
+	delete(funcSpec.KwArgs, "limt")
+
+	// Given both by position and name
+	funcSpec.KwArgs["query"] = "colonies"
+	assert.NotNil(t, ValidateFunctionArgs(funcSpec, args))
+	delete(funcSpec.KwArgs, "query")
+
+	// Too many positional args
+	funcSpec.Args = []interface{}{"colonies", "10", "json", "extra"}
+	funcSpec.KwArgs = make(map[string]interface{})
+	assert.NotNil(t, ValidateFunctionArgs(funcSpec, args))
+
+	// Missing required arg
+	funcSpec.Args = nil
+	err = ValidateFunctionArgs(funcSpec, args)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "query")
+
+	// Ill-typed arg
+	funcSpec.KwArgs["query"] = "colonies"
+	funcSpec.KwArgs["limit"] = "ten"
+	assert.NotNil(t, ValidateFunctionArgs(funcSpec, args))
+
+	// Enum violation
+	funcSpec.KwArgs["limit"] = float64(10)
+	funcSpec.KwArgs["format"] = "xml"
+	assert.NotNil(t, ValidateFunctionArgs(funcSpec, args))
+}
@@ -2,9 +2,21 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
+const (
+	GeneratorWindowTumbling = "tumbling" // Fixed size, non-overlapping windows
+	GeneratorWindowSliding  = "sliding"  // Fixed size windows advancing by WindowSlide, may overlap
+)
+
+const (
+	GeneratorBatchArgs       = "args"       // Each arg is passed as a positional arg to the workflow root, default
+	GeneratorBatchStructured = "structured" // The batch is passed as a single GeneratorBatch arg to the workflow root
+)
+
 type Generator struct {
 	ID            string    `json:"generatorid"`
 	InitiatorID   string    `json:"initiatorid"`
@@ -18,6 +30,24 @@ type Generator struct {
 	LastRun       time.Time `json:"lastrun"`
 	QueueSize     int       `json:"queuesize"`
 	CheckerPeriod int       `json:"checkerperiod"`
+	TriggerSize   int       `json:"triggersize"`   // Submit a batch when the args of a key reach this many bytes
+	WindowType    string    `json:"windowtype"`    // GeneratorWindowTumbling or GeneratorWindowSliding, empty if not windowed
+	WindowSize    int       `json:"windowsize"`    // Window length in seconds
+	WindowSlide   int       `json:"windowslide"`   // Seconds between the start of two sliding windows
+	LastWindowEnd time.Time `json:"lastwindowend"` // End of the last window that has been submitted
+	BatchFormat   string    `json:"batchformat"`   // GeneratorBatchArgs or GeneratorBatchStructured
+	OpenWindowEnd time.Time `json:"openwindowend"` // End of a window that has only been submitted for some keys
+	WindowKeys    []string  `json:"windowkeys"`    // Keys that have been submitted for the window ending at OpenWindowEnd
+}
+
+// GeneratorBatch is passed to the workflow root of generators using the GeneratorBatchStructured format.
+// WindowStart and WindowEnd are only set by windowed generators.
+type GeneratorBatch struct {
+	Key         string     `json:"key"`
+	Args        []string   `json:"args"`
+	Size        int        `json:"size"`
+	WindowStart *time.Time `json:"windowstart,omitempty"`
+	WindowEnd   *time.Time `json:"windowend,omitempty"`
 }
 
 func CreateGenerator(colonyName string, name string, workflowSpec string, trigger int, timeout int) *Generator {
@@ -96,13 +126,70 @@ func (generator *Generator) Equals(generator2 *Generator) bool {
 		generator.Trigger != generator2.Trigger ||
 		generator.Timeout != generator2.Timeout ||
 		generator.CheckerPeriod != generator2.CheckerPeriod ||
-		generator.QueueSize != generator2.QueueSize {
+		generator.QueueSize != generator2.QueueSize ||
+		generator.TriggerSize != generator2.TriggerSize ||
+		generator.WindowType != generator2.WindowType ||
+		generator.WindowSize != generator2.WindowSize ||
+		generator.WindowSlide != generator2.WindowSlide ||
+		generator.BatchFormat != generator2.BatchFormat {
 		same = false
 	}
 
 	return same
 }
 
+// IsWindowed returns true if the generator submits batches per time window instead of count, size or timeout.
+func (generator *Generator) IsWindowed() bool {
+	return generator.WindowType != ""
+}
+
+// GetWindowSlide returns the time between the start of two windows. Tumbling windows slide by their size.
+func (generator *Generator) GetWindowSlide() time.Duration {
+	if generator.WindowType == GeneratorWindowSliding {
+		return time.Duration(generator.WindowSlide) * time.Second
+	}
+
+	return time.Duration(generator.WindowSize) * time.Second
+}
+
+func (generator *Generator) ValidateTriggers() error {
+	if generator.TriggerSize < 0 {
+		return errors.New("generator trigger size cannot be negative")
+	}
+
+	switch generator.BatchFormat {
+	case "", GeneratorBatchArgs, GeneratorBatchStructured:
+	default:
+		return fmt.Errorf("invalid batch format '%s', must be '%s' or '%s'", generator.BatchFormat, GeneratorBatchArgs, GeneratorBatchStructured)
+	}
+
+	switch generator.WindowType {
+	case "":
+		return nil
+	case GeneratorWindowTumbling, GeneratorWindowSliding:
+	default:
+		return fmt.Errorf("invalid window type '%s', must be '%s' or '%s'", generator.WindowType, GeneratorWindowTumbling, GeneratorWindowSliding)
+	}
+
+	if generator.WindowSize <= 0 {
+		return errors.New("generator window size must be positive")
+	}
+
+	if generator.WindowType == GeneratorWindowSliding && (generator.WindowSlide <= 0 || generator.WindowSlide > generator.WindowSize) {
+		return errors.New("generator window slide must be positive and cannot exceed the window size")
+	}
+
+	if generator.WindowType == GeneratorWindowTumbling && generator.WindowSlide != 0 && generator.WindowSlide != generator.WindowSize {
+		return errors.New("generator window slide is only supported with sliding windows")
+	}
+
+	if generator.Trigger > 0 || generator.TriggerSize > 0 || generator.Timeout > 0 {
+		return errors.New("generator trigger, trigger size and timeout cannot be combined with a window")
+	}
+
+	return nil
+}
+
 func (generator *Generator) ToJSON() (string, error) {
 	jsonBytes, err := json.Marshal(generator)
 	if err != nil {
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/colonyos/colonies/pkg/security/crypto"
 	"github.com/google/uuid"
 )
@@ -10,6 +12,16 @@ type GeneratorArg struct {
 	GeneratorID string
 	ColonyName  string
 	Arg         string
+	Key         string    // Args with the same key are batched together, the empty key is the default batch
+	Added       time.Time // Set by the database when the arg is packed
+}
+
+// GeneratorArgGroup summarizes the pending args of a generator with the same key.
+type GeneratorArgGroup struct {
+	Key    string
+	Count  int
+	Size   int       // Total size of the args in bytes
+	Oldest time.Time // When the oldest arg was packed
 }
 
 func CreateGeneratorArg(generatorID string, colonyName string, arg string) *GeneratorArg {
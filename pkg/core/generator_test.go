@@ -2,6 +2,7 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +22,8 @@ func TestCreateGenerator(t *testing.T) {
 	generator.ID = GenerateRandomID()
 	generator.QueueSize = 100
 	generator.CheckerPeriod = 200
+	generator.TriggerSize = 1024
+	generator.BatchFormat = GeneratorBatchStructured
 	jsonStr, err = generator.ToJSON()
 	assert.Nil(t, err)
 
@@ -67,3 +70,50 @@ func TestCreateGeneratorSpecArray(t *testing.T) {
 	assert.False(t, IsGeneratorArraysEqual(nil, arr2))
 	assert.False(t, IsGeneratorArraysEqual(nil, nil))
 }
+
+func TestGeneratorValidateTriggers(t *testing.T) {
+	generator := CreateGenerator(GenerateRandomID(), "test_genname", "", 10, 10)
+	generator.TriggerSize = 1024
+	generator.BatchFormat = GeneratorBatchStructured
+	assert.Nil(t, generator.ValidateTriggers())
+	assert.False(t, generator.IsWindowed())
+
+	generator.TriggerSize = -1
+	assert.NotNil(t, generator.ValidateTriggers())
+	generator.TriggerSize = 0
+
+	generator.BatchFormat = "invalid"
+	assert.NotNil(t, generator.ValidateTriggers())
+	generator.BatchFormat = ""
+
+	// Count and timeout triggers cannot be combined with windows
+	generator.WindowType = GeneratorWindowTumbling
+	generator.WindowSize = 60
+	assert.NotNil(t, generator.ValidateTriggers())
+
+	generator.Trigger = -1
+	generator.Timeout = -1
+	assert.Nil(t, generator.ValidateTriggers())
+	assert.True(t, generator.IsWindowed())
+	assert.Equal(t, 60*time.Second, generator.GetWindowSlide())
+
+	generator.WindowSlide = 10
+	assert.NotNil(t, generator.ValidateTriggers())
+
+	generator.WindowType = GeneratorWindowSliding
+	assert.Nil(t, generator.ValidateTriggers())
+	assert.Equal(t, 10*time.Second, generator.GetWindowSlide())
+
+	generator.WindowSlide = 120
+	assert.NotNil(t, generator.ValidateTriggers())
+
+	generator.WindowSlide = 0
+	assert.NotNil(t, generator.ValidateTriggers())
+
+	generator.WindowSlide = 10
+	generator.WindowSize = 0
+	assert.NotNil(t, generator.ValidateTriggers())
+
+	generator.WindowType = "invalid"
+	assert.NotNil(t, generator.ValidateTriggers())
+}
@@ -2,14 +2,141 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
+)
+
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
 )
 
 type Log struct {
-	ProcessID    string `json:"processid"`
-	ColonyName   string `json:"colonyname"`
-	ExecutorName string `json:"executorname"`
-	Message      string `json:"message"`
-	Timestamp    int64  `json:"timestamp"` // UTC Unix time
+	ProcessID      string            `json:"processid"`
+	ProcessGraphID string            `json:"processgraphid,omitempty"`
+	ColonyName     string            `json:"colonyname"`
+	ExecutorName   string            `json:"executorname"`
+	Level          string            `json:"level,omitempty"`
+	Stream         string            `json:"stream,omitempty"`
+	Fields         map[string]string `json:"fields,omitempty"`
+	Message        string            `json:"message"`
+	Timestamp      int64             `json:"timestamp"` // UTC Unix time
+}
+
+// LogFilter selects log entries, empty fields are ignored. Entries must match every
+// key/value pair in Fields and have a level at or above Level.
+type LogFilter struct {
+	ColonyName     string            `json:"colonyname"`
+	ProcessID      string            `json:"processid,omitempty"`
+	ProcessGraphID string            `json:"processgraphid,omitempty"`
+	ExecutorName   string            `json:"executorname,omitempty"`
+	Level          string            `json:"level,omitempty"`
+	Stream         string            `json:"stream,omitempty"`
+	Fields         map[string]string `json:"fields,omitempty"`
+	Since          int64             `json:"since,omitempty"` // Exclusive, UTC Unix time
+	Until          int64             `json:"until,omitempty"` // Inclusive, UTC Unix time
+	Latest         bool              `json:"latest,omitempty"`
+	Count          int               `json:"count"`
+}
+
+var logLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+// ValidateLogLevel returns an error if level is not empty and not a known log level
+func ValidateLogLevel(level string) error {
+	if level == "" || LogLevelsFrom(level) != nil {
+		return nil
+	}
+
+	return errors.New("Invalid log level <" + level + ">, must be one of " + strings.Join(logLevels, ", "))
+}
+
+// ValidateLogStream returns an error if stream is not empty, stdout or stderr
+func ValidateLogStream(stream string) error {
+	if stream == "" || stream == LogStreamStdout || stream == LogStreamStderr {
+		return nil
+	}
+
+	return errors.New("Invalid log stream <" + stream + ">, must be " + LogStreamStdout + " or " + LogStreamStderr)
+}
+
+// LogLevelsFrom returns level and all levels more severe than it, or nil if level is unknown
+func LogLevelsFrom(level string) []string {
+	for i, l := range logLevels {
+		if l == level {
+			return logLevels[i:]
+		}
+	}
+
+	return nil
+}
+
+// ParseLogFields parses key=value pairs, as given on the command line, into a field map
+func ParseLogFields(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	fields := make(map[string]string)
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.New("Invalid log field <" + pair + ">, must be key=value")
+		}
+		fields[kv[0]] = kv[1]
+	}
+
+	return fields, nil
+}
+
+// Match returns true if the log entry is selected by filter
+func (log *Log) Match(filter *LogFilter) bool {
+	if filter.ColonyName != "" && log.ColonyName != filter.ColonyName {
+		return false
+	}
+	if filter.ProcessID != "" && log.ProcessID != filter.ProcessID {
+		return false
+	}
+	if filter.ProcessGraphID != "" && log.ProcessGraphID != filter.ProcessGraphID {
+		return false
+	}
+	if filter.ExecutorName != "" && log.ExecutorName != filter.ExecutorName {
+		return false
+	}
+	if filter.Stream != "" && log.Stream != filter.Stream {
+		return false
+	}
+	if filter.Level != "" {
+		found := false
+		for _, level := range LogLevelsFrom(filter.Level) {
+			if log.Level == level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, v := range filter.Fields {
+		if value, ok := log.Fields[k]; !ok || value != v {
+			return false
+		}
+	}
+	if filter.Since > 0 && log.Timestamp <= filter.Since {
+		return false
+	}
+	if filter.Until > 0 && log.Timestamp > filter.Until {
+		return false
+	}
+
+	return true
 }
 
 func ConvertJSONToLog(jsonString string) (*Log, error) {
@@ -46,8 +173,12 @@ func (log *Log) Equals(log2 *Log) bool {
 	if log.ProcessID != log2.ProcessID ||
 		log.ColonyName != log2.ColonyName ||
 		log.ExecutorName != log2.ExecutorName ||
+		log.ProcessGraphID != log2.ProcessGraphID ||
+		log.Level != log2.Level ||
+		log.Stream != log2.Stream ||
 		log.Message != log2.Message ||
-		log.Timestamp != log2.Timestamp {
+		log.Timestamp != log2.Timestamp ||
+		!IsStringMapsEqual(log.Fields, log2.Fields) {
 		same = false
 	}
 
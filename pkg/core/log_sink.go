@@ -0,0 +1,205 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	LogSinkTypeFile   = "file"
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeHTTP   = "http"
+	LogSinkTypeOTLP   = "otlp"
+)
+
+// LogSink is a colony scoped registration of an external destination that log entries are forwarded to
+// in batches, in addition to being stored in the database.
+//
+// Target depends on Type. File sinks take a path relative to the directory of the colony in the log sink directory of the server,
+// syslog sinks an address such as udp://host:514 or tcp://host:601, and HTTP and OTLP sinks an endpoint
+// URL, e.g. http://collector:4318/v1/logs. Headers are only used by HTTP and OTLP sinks and are never
+// sent back to clients since they typically contain credentials.
+type LogSink struct {
+	ID            string            `json:"logsinkid"`
+	ColonyName    string            `json:"colonyname"`
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	Target        string            `json:"target"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Level         string            `json:"level"`
+	BatchSize     int               `json:"batchsize"`
+	FlushInterval int               `json:"flushinterval"`
+	MaxSize       int64             `json:"maxsize"`
+	MaxFiles      int               `json:"maxfiles"`
+	Added         time.Time         `json:"added"`
+}
+
+func CreateLogSink(colonyName string, name string, sinkType string, target string) *LogSink {
+	return &LogSink{
+		ID:         GenerateRandomID(),
+		ColonyName: colonyName,
+		Name:       name,
+		Type:       sinkType,
+		Target:     target,
+		Headers:    make(map[string]string),
+	}
+}
+
+func ConvertJSONToLogSink(jsonString string) (*LogSink, error) {
+	var sink *LogSink
+	err := json.Unmarshal([]byte(jsonString), &sink)
+	if err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func ConvertJSONToLogSinkArray(jsonString string) ([]*LogSink, error) {
+	var sinks []*LogSink
+
+	err := json.Unmarshal([]byte(jsonString), &sinks)
+	if err != nil {
+		return sinks, err
+	}
+
+	return sinks, nil
+}
+
+func ConvertLogSinkArrayToJSON(sinks []*LogSink) (string, error) {
+	jsonBytes, err := json.Marshal(sinks)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsLogSinkArraysEqual(sinks1 []*LogSink, sinks2 []*LogSink) bool {
+	counter := 0
+	for _, sink1 := range sinks1 {
+		for _, sink2 := range sinks2 {
+			if sink1.Equals(sink2) {
+				counter++
+			}
+		}
+	}
+
+	if counter == len(sinks1) && counter == len(sinks2) {
+		return true
+	}
+
+	return false
+}
+
+// Validate checks that the sink type is known and that the target is valid for that type
+func (sink *LogSink) Validate() error {
+	if sink.Name == "" {
+		return errors.New("Log sink name is empty")
+	}
+
+	if sink.Target == "" {
+		return errors.New("Log sink target is empty")
+	}
+
+	if sink.BatchSize < 0 || sink.FlushInterval < 0 || sink.MaxSize < 0 || sink.MaxFiles < 0 {
+		return errors.New("Log sink batchsize, flushinterval, maxsize and maxfiles cannot be negative")
+	}
+
+	if err := ValidateLogLevel(sink.Level); err != nil {
+		return err
+	}
+
+	switch sink.Type {
+	case LogSinkTypeFile:
+		if filepath.IsAbs(sink.Target) {
+			return errors.New("Log sink target must be a path relative to the log sink directory")
+		}
+		cleaned := filepath.Clean(sink.Target)
+		if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+			return errors.New("Log sink target must be a file inside the log sink directory")
+		}
+	case LogSinkTypeSyslog:
+		u, err := url.Parse(sink.Target)
+		if err != nil {
+			return errors.New("Invalid log sink target, expected udp://host:port or tcp://host:port")
+		}
+		if (u.Scheme != "udp" && u.Scheme != "tcp") || u.Host == "" {
+			return errors.New("Invalid log sink target, expected udp://host:port or tcp://host:port")
+		}
+	case LogSinkTypeHTTP, LogSinkTypeOTLP:
+		u, err := url.Parse(sink.Target)
+		if err != nil {
+			return errors.New("Invalid log sink target, expected an http or https url")
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("Invalid log sink target, expected an http or https url")
+		}
+	default:
+		return errors.New("Invalid log sink type <" + sink.Type + ">, must be file, syslog, http or otlp")
+	}
+
+	return nil
+}
+
+// Matches returns true if the log entry should be forwarded to the sink
+func (sink *LogSink) Matches(log *Log) bool {
+	if log == nil || log.ColonyName != sink.ColonyName {
+		return false
+	}
+
+	if sink.Level == "" {
+		return true
+	}
+
+	// Entries without a level are plain output and are only filtered out by an explicit level
+	return containsString(LogLevelsFrom(sink.Level), log.Level)
+}
+
+// Redact returns a copy of the sink without header values
+func (sink *LogSink) Redact() *LogSink {
+	redacted := *sink
+	if sink.Headers != nil {
+		redacted.Headers = make(map[string]string, len(sink.Headers))
+		for key := range sink.Headers {
+			redacted.Headers[key] = ""
+		}
+	}
+
+	return &redacted
+}
+
+func (sink *LogSink) Equals(sink2 *LogSink) bool {
+	if sink2 == nil {
+		return false
+	}
+
+	if sink.ID != sink2.ID ||
+		sink.ColonyName != sink2.ColonyName ||
+		sink.Name != sink2.Name ||
+		sink.Type != sink2.Type ||
+		sink.Target != sink2.Target ||
+		sink.Level != sink2.Level ||
+		sink.BatchSize != sink2.BatchSize ||
+		sink.FlushInterval != sink2.FlushInterval ||
+		sink.MaxSize != sink2.MaxSize ||
+		sink.MaxFiles != sink2.MaxFiles ||
+		!IsStringMapsEqual(sink.Headers, sink2.Headers) {
+		return false
+	}
+
+	return true
+}
+
+func (sink *LogSink) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(sink)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
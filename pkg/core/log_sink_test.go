@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSinkToJSON(t *testing.T) {
+	sink := CreateLogSink("test_colony", "test_sink", LogSinkTypeHTTP, "http://localhost:8080/logs")
+	sink.Headers["Authorization"] = "Bearer test_token"
+	sink.Level = LogLevelWarn
+	sink.BatchSize = 10
+
+	jsonStr, err := sink.ToJSON()
+	assert.Nil(t, err)
+
+	sink2, err := ConvertJSONToLogSink(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, sink.Equals(sink2))
+	assert.False(t, sink.Equals(nil))
+
+	sink2.Headers["Authorization"] = "Bearer other_token"
+	assert.False(t, sink.Equals(sink2))
+
+	_, err = ConvertJSONToLogSink("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestLogSinkArrayToJSON(t *testing.T) {
+	sink1 := CreateLogSink("test_colony", "test_sink1", LogSinkTypeFile, "colony.jsonl")
+	sink2 := CreateLogSink("test_colony", "test_sink2", LogSinkTypeSyslog, "udp://localhost:514")
+	sinks := []*LogSink{sink1, sink2}
+
+	jsonStr, err := ConvertLogSinkArrayToJSON(sinks)
+	assert.Nil(t, err)
+
+	sinks2, err := ConvertJSONToLogSinkArray(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, IsLogSinkArraysEqual(sinks, sinks2))
+	assert.False(t, IsLogSinkArraysEqual(sinks, []*LogSink{sink1}))
+
+	_, err = ConvertJSONToLogSinkArray("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestLogSinkValidate(t *testing.T) {
+	assert.Nil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "colony/logs.jsonl").Validate())
+	assert.Nil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeSyslog, "udp://localhost:514").Validate())
+	assert.Nil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeSyslog, "tcp://localhost:601").Validate())
+	assert.Nil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeHTTP, "https://localhost/logs").Validate())
+	assert.Nil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeOTLP, "http://localhost:4318/v1/logs").Validate())
+
+	assert.NotNil(t, CreateLogSink("test_colony", "", LogSinkTypeFile, "logs.jsonl").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", "kafka", "localhost:9092").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "/etc/passwd").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "../logs.jsonl").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "a/../../logs.jsonl").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeSyslog, "localhost:514").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeHTTP, "ftp://localhost/logs").Validate())
+	assert.NotNil(t, CreateLogSink("test_colony", "test_sink", LogSinkTypeOTLP, "http://").Validate())
+
+	sink := CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "logs.jsonl")
+	sink.Level = "fatal"
+	assert.NotNil(t, sink.Validate())
+
+	sink = CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "logs.jsonl")
+	sink.BatchSize = -1
+	assert.NotNil(t, sink.Validate())
+}
+
+func TestLogSinkMatches(t *testing.T) {
+	sink := CreateLogSink("test_colony", "test_sink", LogSinkTypeFile, "logs.jsonl")
+
+	assert.True(t, sink.Matches(&Log{ColonyName: "test_colony", Message: "plain"}))
+	assert.False(t, sink.Matches(&Log{ColonyName: "other_colony", Message: "plain"}))
+	assert.False(t, sink.Matches(nil))
+
+	sink.Level = LogLevelWarn
+	assert.False(t, sink.Matches(&Log{ColonyName: "test_colony", Message: "plain"}))
+	assert.False(t, sink.Matches(&Log{ColonyName: "test_colony", Level: LogLevelInfo}))
+	assert.True(t, sink.Matches(&Log{ColonyName: "test_colony", Level: LogLevelWarn}))
+	assert.True(t, sink.Matches(&Log{ColonyName: "test_colony", Level: LogLevelError}))
+}
+
+func TestLogSinkRedact(t *testing.T) {
+	sink := CreateLogSink("test_colony", "test_sink", LogSinkTypeOTLP, "http://localhost:4318/v1/logs")
+	sink.Headers["Authorization"] = "Bearer test_token"
+
+	redacted := sink.Redact()
+	value, ok := redacted.Headers["Authorization"]
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+	assert.Equal(t, "Bearer test_token", sink.Headers["Authorization"])
+}
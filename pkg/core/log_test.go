@@ -56,3 +56,73 @@ func TestLogArrayToJSON(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, IsLogArraysEqual(logs1, logs2))
 }
+
+func TestStructuredLogToJSON(t *testing.T) {
+	log1 := &Log{ProcessID: "test_process_id", ProcessGraphID: "test_processgraph_id", ColonyName: "test_colony", ExecutorName: "test_executor_name", Level: LogLevelWarn, Stream: LogStreamStderr, Fields: map[string]string{"step": "1"}, Message: "test_msg", Timestamp: time.Now().UnixNano()}
+	jsonStr, err := log1.ToJSON()
+	assert.Nil(t, err)
+
+	log2, err := ConvertJSONToLog(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, log1.Equals(log2))
+
+	log2.Fields["step"] = "2"
+	assert.False(t, log1.Equals(log2))
+	log2.Fields = nil
+	assert.False(t, log1.Equals(log2))
+}
+
+func TestLogMatch(t *testing.T) {
+	log := &Log{ProcessID: "test_process_id", ProcessGraphID: "test_processgraph_id", ColonyName: "test_colony", ExecutorName: "test_executor_name", Level: LogLevelWarn, Stream: LogStreamStderr, Fields: map[string]string{"step": "1", "host": "a"}, Message: "test_msg", Timestamp: 100}
+
+	assert.True(t, log.Match(&LogFilter{}))
+	assert.True(t, log.Match(&LogFilter{ColonyName: "test_colony", ProcessID: "test_process_id", ProcessGraphID: "test_processgraph_id", ExecutorName: "test_executor_name"}))
+	assert.False(t, log.Match(&LogFilter{ColonyName: "other_colony"}))
+	assert.False(t, log.Match(&LogFilter{ProcessID: "other_process_id"}))
+	assert.False(t, log.Match(&LogFilter{ProcessGraphID: "other_processgraph_id"}))
+	assert.False(t, log.Match(&LogFilter{ExecutorName: "other_executor_name"}))
+
+	assert.True(t, log.Match(&LogFilter{Level: LogLevelDebug}))
+	assert.True(t, log.Match(&LogFilter{Level: LogLevelWarn}))
+	assert.False(t, log.Match(&LogFilter{Level: LogLevelError}))
+
+	assert.True(t, log.Match(&LogFilter{Stream: LogStreamStderr}))
+	assert.False(t, log.Match(&LogFilter{Stream: LogStreamStdout}))
+
+	assert.True(t, log.Match(&LogFilter{Fields: map[string]string{"step": "1"}}))
+	assert.True(t, log.Match(&LogFilter{Fields: map[string]string{"step": "1", "host": "a"}}))
+	assert.False(t, log.Match(&LogFilter{Fields: map[string]string{"step": "2"}}))
+	assert.False(t, log.Match(&LogFilter{Fields: map[string]string{"missing": "1"}}))
+
+	assert.True(t, log.Match(&LogFilter{Since: 99, Until: 100}))
+	assert.False(t, log.Match(&LogFilter{Since: 100}))
+	assert.False(t, log.Match(&LogFilter{Until: 99}))
+}
+
+func TestValidateLogLevelAndStream(t *testing.T) {
+	assert.Nil(t, ValidateLogLevel(""))
+	assert.Nil(t, ValidateLogLevel(LogLevelInfo))
+	assert.NotNil(t, ValidateLogLevel("verbose"))
+
+	assert.Nil(t, ValidateLogStream(""))
+	assert.Nil(t, ValidateLogStream(LogStreamStdout))
+	assert.NotNil(t, ValidateLogStream("stdin"))
+
+	assert.Equal(t, []string{LogLevelWarn, LogLevelError}, LogLevelsFrom(LogLevelWarn))
+	assert.Nil(t, LogLevelsFrom("verbose"))
+}
+
+func TestParseLogFields(t *testing.T) {
+	fields, err := ParseLogFields([]string{"step=1", "query=a=b"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"step": "1", "query": "a=b"}, fields)
+
+	fields, err = ParseLogFields(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, fields)
+
+	_, err = ParseLogFields([]string{"step"})
+	assert.NotNil(t, err)
+	_, err = ParseLogFields([]string{"=1"})
+	assert.NotNil(t, err)
+}
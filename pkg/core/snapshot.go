@@ -2,9 +2,14 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
+// ErrSnapshotConflict is returned when a snapshot cannot be restored or cloned because of the current
+// state of the files, e.g. the target label is not empty, rather than because of a database failure
+var ErrSnapshotConflict = errors.New("snapshot conflict")
+
 type Snapshot struct {
 	ID         string    `json:"snapshotid"`
 	ColonyName string    `json:"colonyname"`
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SnapshotDiffEntry is a file that differs between two sets of files, e.g. two snapshots. The path is relative
+// to the label of each set, so that sets with different labels can be compared.
+type SnapshotDiffEntry struct {
+	Path         string `json:"path"`
+	FromFileID   string `json:"fromfileid,omitempty"`
+	FromChecksum string `json:"fromchecksum,omitempty"`
+	FromSize     int64  `json:"fromsize,omitempty"`
+	ToFileID     string `json:"tofileid,omitempty"`
+	ToChecksum   string `json:"tochecksum,omitempty"`
+	ToSize       int64  `json:"tosize,omitempty"`
+}
+
+type SnapshotDiff struct {
+	Added   []*SnapshotDiffEntry `json:"added"`
+	Removed []*SnapshotDiffEntry `json:"removed"`
+	Changed []*SnapshotDiffEntry `json:"changed"`
+}
+
+// SnapshotFilePath returns the path of a file relative to label, e.g. /sub/file for a file named file
+// with the label /data/sub, when label is /data
+func SnapshotFilePath(label string, file *File) string {
+	return path.Join("/", strings.TrimPrefix(file.Label, label), file.Name)
+}
+
+// DiffFiles compares two sets of files with the labels fromLabel and toLabel, or sub labels of them. Files
+// are matched by path and are changed if their checksums differ.
+func DiffFiles(fromLabel string, from []*File, toLabel string, to []*File) *SnapshotDiff {
+	diff := &SnapshotDiff{Added: []*SnapshotDiffEntry{}, Removed: []*SnapshotDiffEntry{}, Changed: []*SnapshotDiffEntry{}}
+
+	fromFiles := make(map[string]*File)
+	for _, file := range from {
+		fromFiles[SnapshotFilePath(fromLabel, file)] = file
+	}
+
+	toFiles := make(map[string]*File)
+	for _, file := range to {
+		toFiles[SnapshotFilePath(toLabel, file)] = file
+	}
+
+	for filePath, toFile := range toFiles {
+		fromFile, ok := fromFiles[filePath]
+		if !ok {
+			diff.Added = append(diff.Added, &SnapshotDiffEntry{Path: filePath, ToFileID: toFile.ID, ToChecksum: toFile.Checksum, ToSize: toFile.Size})
+		} else if fromFile.Checksum != toFile.Checksum {
+			diff.Changed = append(diff.Changed, &SnapshotDiffEntry{
+				Path:         filePath,
+				FromFileID:   fromFile.ID,
+				FromChecksum: fromFile.Checksum,
+				FromSize:     fromFile.Size,
+				ToFileID:     toFile.ID,
+				ToChecksum:   toFile.Checksum,
+				ToSize:       toFile.Size})
+		}
+	}
+
+	for filePath, fromFile := range fromFiles {
+		if _, ok := toFiles[filePath]; !ok {
+			diff.Removed = append(diff.Removed, &SnapshotDiffEntry{Path: filePath, FromFileID: fromFile.ID, FromChecksum: fromFile.Checksum, FromSize: fromFile.Size})
+		}
+	}
+
+	for _, entries := range [][]*SnapshotDiffEntry{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Path < entries[j].Path
+		})
+	}
+
+	return diff
+}
+
+func ConvertJSONToSnapshotDiff(jsonString string) (*SnapshotDiff, error) {
+	var diff *SnapshotDiff
+	err := json.Unmarshal([]byte(jsonString), &diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// IsEmpty returns true if the sets of files are identical
+func (diff *SnapshotDiff) IsEmpty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+func (diff *SnapshotDiff) Equals(diff2 *SnapshotDiff) bool {
+	if diff2 == nil {
+		return false
+	}
+
+	equals := func(entries1 []*SnapshotDiffEntry, entries2 []*SnapshotDiffEntry) bool {
+		if len(entries1) != len(entries2) {
+			return false
+		}
+		for i := range entries1 {
+			if *entries1[i] != *entries2[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return equals(diff.Added, diff2.Added) && equals(diff.Removed, diff2.Removed) && equals(diff.Changed, diff2.Changed)
+}
+
+func (diff *SnapshotDiff) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(diff)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotFilePath(t *testing.T) {
+	assert.Equal(t, "/file", SnapshotFilePath("/data", &File{Label: "/data", Name: "file"}))
+	assert.Equal(t, "/sub/file", SnapshotFilePath("/data", &File{Label: "/data/sub", Name: "file"}))
+	assert.Equal(t, "/data/file", SnapshotFilePath("", &File{Label: "/data", Name: "file"}))
+}
+
+func TestDiffFiles(t *testing.T) {
+	from := []*File{
+		{ID: "id1", Label: "/a", Name: "unchanged", Checksum: "c1"},
+		{ID: "id2", Label: "/a/sub", Name: "changed", Checksum: "c2", Size: 1},
+		{ID: "id3", Label: "/a", Name: "removed", Checksum: "c3"},
+	}
+	to := []*File{
+		{ID: "id4", Label: "/b", Name: "unchanged", Checksum: "c1"},
+		{ID: "id5", Label: "/b/sub", Name: "changed", Checksum: "c5", Size: 2},
+		{ID: "id6", Label: "/b", Name: "added", Checksum: "c6"},
+	}
+
+	diff := DiffFiles("/a", from, "/b", to)
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, &SnapshotDiffEntry{Path: "/added", ToFileID: "id6", ToChecksum: "c6"}, diff.Added[0])
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, &SnapshotDiffEntry{Path: "/removed", FromFileID: "id3", FromChecksum: "c3"}, diff.Removed[0])
+	assert.Len(t, diff.Changed, 1)
+	assert.Equal(t, &SnapshotDiffEntry{Path: "/sub/changed", FromFileID: "id2", FromChecksum: "c2", FromSize: 1, ToFileID: "id5", ToChecksum: "c5", ToSize: 2}, diff.Changed[0])
+	assert.False(t, diff.IsEmpty())
+
+	assert.True(t, DiffFiles("/a", from, "/a", from).IsEmpty())
+	assert.Len(t, DiffFiles("/a", from, "/b", []*File{}).Removed, 3)
+}
+
+func TestSnapshotDiffToJSON(t *testing.T) {
+	from := []*File{{ID: "id1", Label: "/a", Name: "file1", Checksum: "c1"}}
+	to := []*File{{ID: "id2", Label: "/a", Name: "file2", Checksum: "c2"}}
+	diff := DiffFiles("/a", from, "/a", to)
+
+	jsonStr, err := diff.ToJSON()
+	assert.Nil(t, err)
+
+	diff2, err := ConvertJSONToSnapshotDiff(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, diff.Equals(diff2))
+	assert.False(t, diff.Equals(nil))
+	assert.False(t, diff.Equals(DiffFiles("/a", from, "/a", from)))
+
+	_, err = ConvertJSONToSnapshotDiff("invalid json")
+	assert.NotNil(t, err)
+}
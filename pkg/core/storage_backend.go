@@ -0,0 +1,192 @@
+package core
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	PresignGet    = "GET"
+	PresignPut    = "PUT"
+	PresignDelete = "DELETE"
+)
+
+// StorageBackend is a colony scoped object store registration. Files reference a backend by name
+// and only store the object key, the endpoint and credentials are kept by the server and never sent
+// to executors. Executors instead obtain short-lived presigned URLs to transfer objects.
+type StorageBackend struct {
+	ID         string    `json:"backendid"`
+	ColonyName string    `json:"colonyname"`
+	Name       string    `json:"name"`
+	Protocol   string    `json:"protocol"`
+	Server     string    `json:"server"`
+	TLS        bool      `json:"tls"`
+	SkipVerify bool      `json:"skipverify"`
+	AccessKey  string    `json:"accesskey"`
+	SecretKey  string    `json:"secretkey"`
+	Region     string    `json:"region"`
+	Bucket     string    `json:"bucket"`
+	Added      time.Time `json:"added"`
+}
+
+// PresignedURL is a short-lived URL that can be used to transfer an object without credentials
+type PresignedURL struct {
+	URL     string    `json:"url"`
+	Method  string    `json:"method"`
+	Backend string    `json:"backend"`
+	Object  string    `json:"object"`
+	Expires time.Time `json:"expires"`
+}
+
+// StorageObject is an object key issued by the server when presigning a PUT URL. Only issued keys can be
+// referenced by files, and each key can only be registered by one file.
+type StorageObject struct {
+	ColonyName string    `json:"colonyname"`
+	Backend    string    `json:"backend"`
+	Object     string    `json:"object"`
+	Registered bool      `json:"registered"`
+	Issued     time.Time `json:"issued"`
+}
+
+// StorageObjectPrefix returns the key prefix of all objects issued to a colony, objects of different
+// colonies never share a prefix even if the colonies use the same bucket
+func StorageObjectPrefix(colonyName string) string {
+	return "colonies/" + url.PathEscape(colonyName) + "/"
+}
+
+// IsColonyStorageObject returns true if the object key is in the namespace of the colony
+func IsColonyStorageObject(colonyName string, object string) bool {
+	prefix := StorageObjectPrefix(colonyName)
+	return strings.HasPrefix(object, prefix) && len(object) > len(prefix) && !strings.Contains(object[len(prefix):], "/")
+}
+
+// CreateStorageObject issues a new object key in the namespace of the colony
+func CreateStorageObject(colonyName string, backend string) *StorageObject {
+	return &StorageObject{
+		ColonyName: colonyName,
+		Backend:    backend,
+		Object:     StorageObjectPrefix(colonyName) + GenerateRandomID(),
+		Issued:     time.Now().UTC(),
+	}
+}
+
+func CreateStorageBackend(colonyName string, name string, server string, bucket string, accessKey string, secretKey string) *StorageBackend {
+	return &StorageBackend{
+		ID:         GenerateRandomID(),
+		ColonyName: colonyName,
+		Name:       name,
+		Protocol:   "s3",
+		Server:     server,
+		AccessKey:  accessKey,
+		SecretKey:  secretKey,
+		Bucket:     bucket,
+	}
+}
+
+func ConvertJSONToStorageBackend(jsonString string) (*StorageBackend, error) {
+	var backend *StorageBackend
+	err := json.Unmarshal([]byte(jsonString), &backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend, nil
+}
+
+func ConvertJSONToStorageBackendArray(jsonString string) ([]*StorageBackend, error) {
+	var backends []*StorageBackend
+
+	err := json.Unmarshal([]byte(jsonString), &backends)
+	if err != nil {
+		return backends, err
+	}
+
+	return backends, nil
+}
+
+func ConvertStorageBackendArrayToJSON(backends []*StorageBackend) (string, error) {
+	jsonBytes, err := json.Marshal(backends)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsStorageBackendArraysEqual(backends1 []*StorageBackend, backends2 []*StorageBackend) bool {
+	counter := 0
+	for _, backend1 := range backends1 {
+		for _, backend2 := range backends2 {
+			if backend1.Equals(backend2) {
+				counter++
+			}
+		}
+	}
+
+	if counter == len(backends1) && counter == len(backends2) {
+		return true
+	}
+
+	return false
+}
+
+// Redact returns a copy of the backend without credentials
+func (backend *StorageBackend) Redact() *StorageBackend {
+	redacted := *backend
+	redacted.AccessKey = ""
+	redacted.SecretKey = ""
+
+	return &redacted
+}
+
+func (backend *StorageBackend) Equals(backend2 *StorageBackend) bool {
+	if backend2 == nil {
+		return false
+	}
+
+	if backend.ID != backend2.ID ||
+		backend.ColonyName != backend2.ColonyName ||
+		backend.Name != backend2.Name ||
+		backend.Protocol != backend2.Protocol ||
+		backend.Server != backend2.Server ||
+		backend.TLS != backend2.TLS ||
+		backend.SkipVerify != backend2.SkipVerify ||
+		backend.AccessKey != backend2.AccessKey ||
+		backend.SecretKey != backend2.SecretKey ||
+		backend.Region != backend2.Region ||
+		backend.Bucket != backend2.Bucket {
+		return false
+	}
+
+	return true
+}
+
+func (backend *StorageBackend) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(backend)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func ConvertJSONToPresignedURL(jsonString string) (*PresignedURL, error) {
+	var presignedURL *PresignedURL
+	err := json.Unmarshal([]byte(jsonString), &presignedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return presignedURL, nil
+}
+
+func (presignedURL *PresignedURL) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(presignedURL)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
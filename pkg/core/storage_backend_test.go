@@ -0,0 +1,94 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageBackendToJSON(t *testing.T) {
+	backend := CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	backend.TLS = true
+	backend.Region = "test_region"
+
+	jsonStr, err := backend.ToJSON()
+	assert.Nil(t, err)
+
+	backend2, err := ConvertJSONToStorageBackend(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, backend.Equals(backend2))
+	assert.False(t, backend.Equals(nil))
+
+	_, err = ConvertJSONToStorageBackend("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestStorageBackendArrayToJSON(t *testing.T) {
+	backend1 := CreateStorageBackend("test_colony", "test_backend1", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	backend2 := CreateStorageBackend("test_colony", "test_backend2", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	backends := []*StorageBackend{backend1, backend2}
+
+	jsonStr, err := ConvertStorageBackendArrayToJSON(backends)
+	assert.Nil(t, err)
+
+	backends2, err := ConvertJSONToStorageBackendArray(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, IsStorageBackendArraysEqual(backends, backends2))
+	assert.False(t, IsStorageBackendArraysEqual(backends, []*StorageBackend{backend1}))
+
+	_, err = ConvertJSONToStorageBackendArray("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestStorageBackendEquals(t *testing.T) {
+	backend1 := CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	backend2 := *backend1
+	assert.True(t, backend1.Equals(&backend2))
+
+	backend2.Bucket = "other_bucket"
+	assert.False(t, backend1.Equals(&backend2))
+
+	backend2.Bucket = backend1.Bucket
+	backend2.SecretKey = "other_secretkey"
+	assert.False(t, backend1.Equals(&backend2))
+}
+
+func TestStorageBackendRedact(t *testing.T) {
+	backend := CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+
+	redacted := backend.Redact()
+	assert.Equal(t, "", redacted.AccessKey)
+	assert.Equal(t, "", redacted.SecretKey)
+	assert.Equal(t, backend.Bucket, redacted.Bucket)
+	assert.Equal(t, "test_secretkey", backend.SecretKey)
+}
+
+func TestPresignedURLToJSON(t *testing.T) {
+	presignedURL := &PresignedURL{URL: "http://localhost:9000/bucket/object?X-Amz-Signature=abc", Method: PresignGet, Backend: "test_backend", Object: "object", Expires: time.Now().UTC()}
+
+	jsonStr, err := presignedURL.ToJSON()
+	assert.Nil(t, err)
+
+	presignedURL2, err := ConvertJSONToPresignedURL(jsonStr)
+	assert.Nil(t, err)
+	assert.Equal(t, presignedURL.URL, presignedURL2.URL)
+	assert.Equal(t, presignedURL.Method, presignedURL2.Method)
+	assert.True(t, presignedURL.Expires.Equal(presignedURL2.Expires))
+
+	_, err = ConvertJSONToPresignedURL("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestStorageObject(t *testing.T) {
+	object := CreateStorageObject("test_colony", "test_backend")
+	assert.Equal(t, "test_colony", object.ColonyName)
+	assert.Equal(t, "test_backend", object.Backend)
+	assert.False(t, object.Registered)
+	assert.True(t, IsColonyStorageObject("test_colony", object.Object))
+	assert.False(t, IsColonyStorageObject("test_colony2", object.Object))
+	assert.False(t, IsColonyStorageObject("test_colony", "test_object"))
+	assert.False(t, IsColonyStorageObject("test_colony", StorageObjectPrefix("test_colony")))
+	assert.False(t, IsColonyStorageObject("test", StorageObjectPrefix("test")+"colony/object"))
+	assert.NotEqual(t, StorageObjectPrefix("a/b"), StorageObjectPrefix("a")+"b/")
+}
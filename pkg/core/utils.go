@@ -11,3 +11,18 @@ func GenerateRandomID() string {
 	crypto := crypto.CreateCrypto()
 	return crypto.GenerateHash(uuid.String())
 }
+
+// IsStringMapsEqual returns true if both maps hold the same key/value pairs, a nil map equals an empty map
+func IsStringMapsEqual(m1 map[string]string, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+
+	for k, v := range m1 {
+		if value, ok := m2[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -10,3 +10,11 @@ func TestGenerateRandomID(t *testing.T) {
 	str := GenerateRandomID()
 	assert.Len(t, str, 64)
 }
+
+func TestIsStringMapsEqual(t *testing.T) {
+	assert.True(t, IsStringMapsEqual(nil, map[string]string{}))
+	assert.True(t, IsStringMapsEqual(map[string]string{"a": "1"}, map[string]string{"a": "1"}))
+	assert.False(t, IsStringMapsEqual(map[string]string{"a": "1"}, map[string]string{"a": "2"}))
+	assert.False(t, IsStringMapsEqual(map[string]string{"a": "1"}, map[string]string{"b": "1"}))
+	assert.False(t, IsStringMapsEqual(map[string]string{"a": "1"}, nil))
+}
@@ -0,0 +1,330 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookKindProcess      = "process"
+	WebhookKindProcessGraph = "processgraph"
+)
+
+const (
+	WebhookEventProcessSuccessful      = "process.successful"
+	WebhookEventProcessFailed          = "process.failed"
+	WebhookEventProcessCancelled       = "process.cancelled"
+	WebhookEventProcessGraphSuccessful = "processgraph.successful"
+	WebhookEventProcessGraphFailed     = "processgraph.failed"
+	WebhookEventProcessGraphCancelled  = "processgraph.cancelled"
+)
+
+// Webhook is a colony scoped registration of an external HTTP endpoint that is
+// notified when processes or process graphs reach a final state.
+//
+// All filters are optional, an empty filter matches everything. ExecutorTypes and
+// Labels only match process events since process graphs have no executor type or label.
+type Webhook struct {
+	ID             string    `json:"webhookid"`
+	ColonyName     string    `json:"colonyname"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"`
+	Secret         string    `json:"secret"`
+	Kinds          []string  `json:"kinds"`
+	States         []int     `json:"states"`
+	ExecutorTypes  []string  `json:"executortypes"`
+	Labels         []string  `json:"labels"`
+	ProcessGraphID string    `json:"processgraphid"`
+	MaxRetries     int       `json:"maxretries"`
+	Added          time.Time `json:"added"`
+}
+
+// WebhookEvent is the payload POSTed to a webhook endpoint
+type WebhookEvent struct {
+	ID           string        `json:"eventid"`
+	Type         string        `json:"type"`
+	Kind         string        `json:"kind"`
+	State        int           `json:"state"`
+	ColonyName   string        `json:"colonyname"`
+	Timestamp    time.Time     `json:"timestamp"`
+	Process      *Process      `json:"process,omitempty"`
+	ProcessGraph *ProcessGraph `json:"processgraph,omitempty"`
+}
+
+// WebhookDelivery records the outcome of delivering an event to a webhook
+type WebhookDelivery struct {
+	ID          string    `json:"deliveryid"`
+	WebhookID   string    `json:"webhookid"`
+	ColonyName  string    `json:"colonyname"`
+	EventID     string    `json:"eventid"`
+	EventType   string    `json:"eventtype"`
+	Attempts    int       `json:"attempts"`
+	StatusCode  int       `json:"statuscode"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error"`
+	Added       time.Time `json:"added"`
+	LastAttempt time.Time `json:"lastattempt"`
+}
+
+func CreateWebhook(colonyName string, name string, url string, secret string) *Webhook {
+	return &Webhook{
+		ID:            GenerateRandomID(),
+		ColonyName:    colonyName,
+		Name:          name,
+		URL:           url,
+		Secret:        secret,
+		Kinds:         make([]string, 0),
+		States:        make([]int, 0),
+		ExecutorTypes: make([]string, 0),
+		Labels:        make([]string, 0),
+	}
+}
+
+func CreateProcessWebhookEvent(process *Process) *WebhookEvent {
+	event := &WebhookEvent{
+		ID:         GenerateRandomID(),
+		Kind:       WebhookKindProcess,
+		State:      process.State,
+		ColonyName: process.FunctionSpec.Conditions.ColonyName,
+		Timestamp:  time.Now(),
+		Process:    process,
+	}
+
+	switch process.State {
+	case SUCCESS:
+		event.Type = WebhookEventProcessSuccessful
+	case FAILED:
+		event.Type = WebhookEventProcessFailed
+	case CANCELLED:
+		event.Type = WebhookEventProcessCancelled
+	}
+
+	return event
+}
+
+func CreateProcessGraphWebhookEvent(graph *ProcessGraph) *WebhookEvent {
+	event := &WebhookEvent{
+		ID:           GenerateRandomID(),
+		Kind:         WebhookKindProcessGraph,
+		State:        graph.State,
+		ColonyName:   graph.ColonyName,
+		Timestamp:    time.Now(),
+		ProcessGraph: graph,
+	}
+
+	switch graph.State {
+	case SUCCESS:
+		event.Type = WebhookEventProcessGraphSuccessful
+	case FAILED:
+		event.Type = WebhookEventProcessGraphFailed
+	case CANCELLED:
+		event.Type = WebhookEventProcessGraphCancelled
+	}
+
+	return event
+}
+
+func ConvertJSONToWebhook(jsonString string) (*Webhook, error) {
+	var webhook *Webhook
+	err := json.Unmarshal([]byte(jsonString), &webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func ConvertJSONToWebhookArray(jsonString string) ([]*Webhook, error) {
+	var webhooks []*Webhook
+
+	err := json.Unmarshal([]byte(jsonString), &webhooks)
+	if err != nil {
+		return webhooks, err
+	}
+
+	return webhooks, nil
+}
+
+func ConvertWebhookArrayToJSON(webhooks []*Webhook) (string, error) {
+	jsonBytes, err := json.Marshal(webhooks)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func ConvertJSONToWebhookEvent(jsonString string) (*WebhookEvent, error) {
+	var event *WebhookEvent
+	err := json.Unmarshal([]byte(jsonString), &event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func ConvertJSONToWebhookDeliveryArray(jsonString string) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+
+	err := json.Unmarshal([]byte(jsonString), &deliveries)
+	if err != nil {
+		return deliveries, err
+	}
+
+	return deliveries, nil
+}
+
+func ConvertWebhookDeliveryArrayToJSON(deliveries []*WebhookDelivery) (string, error) {
+	jsonBytes, err := json.Marshal(deliveries)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func IsWebhookArraysEqual(webhooks1 []*Webhook, webhooks2 []*Webhook) bool {
+	counter := 0
+	for _, webhook1 := range webhooks1 {
+		for _, webhook2 := range webhooks2 {
+			if webhook1.Equals(webhook2) {
+				counter++
+			}
+		}
+	}
+
+	if counter == len(webhooks1) && counter == len(webhooks2) {
+		return true
+	}
+
+	return false
+}
+
+// Matches returns true if the event passes all filters of the webhook
+func (webhook *Webhook) Matches(event *WebhookEvent) bool {
+	if event == nil || event.ColonyName != webhook.ColonyName {
+		return false
+	}
+
+	if len(webhook.Kinds) > 0 && !containsString(webhook.Kinds, event.Kind) {
+		return false
+	}
+
+	if len(webhook.States) > 0 {
+		found := false
+		for _, state := range webhook.States {
+			if state == event.State {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	switch event.Kind {
+	case WebhookKindProcess:
+		if event.Process == nil {
+			return false
+		}
+		if len(webhook.ExecutorTypes) > 0 && !containsString(webhook.ExecutorTypes, event.Process.FunctionSpec.Conditions.ExecutorType) {
+			return false
+		}
+		if len(webhook.Labels) > 0 && !containsString(webhook.Labels, event.Process.FunctionSpec.Label) {
+			return false
+		}
+		if webhook.ProcessGraphID != "" && webhook.ProcessGraphID != event.Process.ProcessGraphID {
+			return false
+		}
+	case WebhookKindProcessGraph:
+		if event.ProcessGraph == nil {
+			return false
+		}
+		if len(webhook.ExecutorTypes) > 0 || len(webhook.Labels) > 0 {
+			return false
+		}
+		if webhook.ProcessGraphID != "" && webhook.ProcessGraphID != event.ProcessGraph.ID {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (webhook *Webhook) Equals(webhook2 *Webhook) bool {
+	if webhook2 == nil {
+		return false
+	}
+
+	if webhook.ID != webhook2.ID ||
+		webhook.ColonyName != webhook2.ColonyName ||
+		webhook.Name != webhook2.Name ||
+		webhook.URL != webhook2.URL ||
+		webhook.Secret != webhook2.Secret ||
+		webhook.ProcessGraphID != webhook2.ProcessGraphID ||
+		webhook.MaxRetries != webhook2.MaxRetries {
+		return false
+	}
+
+	if !isStringSliceEqual(webhook.Kinds, webhook2.Kinds) ||
+		!isStringSliceEqual(webhook.ExecutorTypes, webhook2.ExecutorTypes) ||
+		!isStringSliceEqual(webhook.Labels, webhook2.Labels) {
+		return false
+	}
+
+	if len(webhook.States) != len(webhook2.States) {
+		return false
+	}
+	for i := range webhook.States {
+		if webhook.States[i] != webhook2.States[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (webhook *Webhook) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(webhook)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (event *WebhookEvent) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStringSliceEqual(values1 []string, values2 []string) bool {
+	if len(values1) != len(values2) {
+		return false
+	}
+	for i := range values1 {
+		if values1[i] != values2[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestWebhookProcess(colonyName string, executorType string, label string, state int) *Process {
+	funcSpec := CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = colonyName
+	funcSpec.Conditions.ExecutorType = executorType
+	funcSpec.Label = label
+	process := CreateProcess(funcSpec)
+	process.State = state
+
+	return process
+}
+
+func TestWebhookToJSON(t *testing.T) {
+	webhook := CreateWebhook("test_colony", "test_webhook", "http://localhost:8080/hook", "secret")
+	webhook.Kinds = []string{WebhookKindProcess}
+	webhook.States = []int{FAILED}
+	webhook.ExecutorTypes = []string{"test_executor_type"}
+	webhook.Labels = []string{"test_label"}
+	webhook.MaxRetries = 3
+
+	jsonStr, err := webhook.ToJSON()
+	assert.Nil(t, err)
+
+	webhook2, err := ConvertJSONToWebhook(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, webhook.Equals(webhook2))
+	assert.False(t, webhook.Equals(nil))
+
+	_, err = ConvertJSONToWebhook("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestWebhookArrayToJSON(t *testing.T) {
+	webhook1 := CreateWebhook("test_colony", "test_webhook1", "http://localhost:8080/hook1", "secret")
+	webhook2 := CreateWebhook("test_colony", "test_webhook2", "http://localhost:8080/hook2", "secret")
+	webhooks := []*Webhook{webhook1, webhook2}
+
+	jsonStr, err := ConvertWebhookArrayToJSON(webhooks)
+	assert.Nil(t, err)
+
+	webhooks2, err := ConvertJSONToWebhookArray(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, IsWebhookArraysEqual(webhooks, webhooks2))
+	assert.False(t, IsWebhookArraysEqual(webhooks, []*Webhook{webhook1}))
+
+	_, err = ConvertJSONToWebhookArray("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestWebhookEquals(t *testing.T) {
+	webhook1 := CreateWebhook("test_colony", "test_webhook", "http://localhost:8080/hook", "secret")
+	webhook2 := CreateWebhook("test_colony", "test_webhook", "http://localhost:8080/hook", "secret")
+	webhook2.ID = webhook1.ID
+	assert.True(t, webhook1.Equals(webhook2))
+
+	webhook2.URL = "http://localhost:8080/other"
+	assert.False(t, webhook1.Equals(webhook2))
+
+	webhook2.URL = webhook1.URL
+	webhook2.States = []int{SUCCESS}
+	assert.False(t, webhook1.Equals(webhook2))
+
+	webhook2.States = webhook1.States
+	webhook2.Labels = []string{"label"}
+	assert.False(t, webhook1.Equals(webhook2))
+}
+
+func TestWebhookMatchesProcess(t *testing.T) {
+	webhook := CreateWebhook("test_colony", "test_webhook", "http://localhost:8080/hook", "secret")
+
+	process := createTestWebhookProcess("test_colony", "test_executor_type", "test_label", SUCCESS)
+	event := CreateProcessWebhookEvent(process)
+	assert.Equal(t, WebhookEventProcessSuccessful, event.Type)
+	assert.Equal(t, WebhookKindProcess, event.Kind)
+	assert.True(t, webhook.Matches(event))
+
+	otherColonyProcess := createTestWebhookProcess("other_colony", "test_executor_type", "test_label", SUCCESS)
+	assert.False(t, webhook.Matches(CreateProcessWebhookEvent(otherColonyProcess)))
+	assert.False(t, webhook.Matches(nil))
+
+	webhook.States = []int{FAILED}
+	assert.False(t, webhook.Matches(event))
+
+	failedProcess := createTestWebhookProcess("test_colony", "test_executor_type", "test_label", FAILED)
+	failedEvent := CreateProcessWebhookEvent(failedProcess)
+	assert.Equal(t, WebhookEventProcessFailed, failedEvent.Type)
+	assert.True(t, webhook.Matches(failedEvent))
+
+	webhook.ExecutorTypes = []string{"other_executor_type"}
+	assert.False(t, webhook.Matches(failedEvent))
+	webhook.ExecutorTypes = []string{"other_executor_type", "test_executor_type"}
+	assert.True(t, webhook.Matches(failedEvent))
+
+	webhook.Labels = []string{"other_label"}
+	assert.False(t, webhook.Matches(failedEvent))
+	webhook.Labels = []string{"test_label"}
+	assert.True(t, webhook.Matches(failedEvent))
+
+	webhook.ProcessGraphID = "test_processgraph_id"
+	assert.False(t, webhook.Matches(failedEvent))
+	failedProcess.ProcessGraphID = "test_processgraph_id"
+	assert.True(t, webhook.Matches(failedEvent))
+
+	webhook.Kinds = []string{WebhookKindProcessGraph}
+	assert.False(t, webhook.Matches(failedEvent))
+}
+
+func TestWebhookMatchesProcessGraph(t *testing.T) {
+	webhook := CreateWebhook("test_colony", "test_webhook", "http://localhost:8080/hook", "secret")
+
+	graph, err := CreateProcessGraph("test_colony")
+	assert.Nil(t, err)
+	graph.State = CANCELLED
+
+	event := CreateProcessGraphWebhookEvent(graph)
+	assert.Equal(t, WebhookEventProcessGraphCancelled, event.Type)
+	assert.Equal(t, WebhookKindProcessGraph, event.Kind)
+	assert.True(t, webhook.Matches(event))
+
+	webhook.Kinds = []string{WebhookKindProcess}
+	assert.False(t, webhook.Matches(event))
+
+	webhook.Kinds = []string{WebhookKindProcessGraph}
+	webhook.ProcessGraphID = graph.ID
+	assert.True(t, webhook.Matches(event))
+
+	// Executor type and label filters can never match a process graph event
+	webhook.ExecutorTypes = []string{"test_executor_type"}
+	assert.False(t, webhook.Matches(event))
+}
+
+func TestWebhookEventToJSON(t *testing.T) {
+	process := createTestWebhookProcess("test_colony", "test_executor_type", "test_label", CANCELLED)
+	event := CreateProcessWebhookEvent(process)
+	assert.Equal(t, WebhookEventProcessCancelled, event.Type)
+
+	jsonStr, err := event.ToJSON()
+	assert.Nil(t, err)
+
+	event2, err := ConvertJSONToWebhookEvent(jsonStr)
+	assert.Nil(t, err)
+	assert.Equal(t, event.ID, event2.ID)
+	assert.Equal(t, event.Type, event2.Type)
+	assert.Equal(t, process.ID, event2.Process.ID)
+	assert.Nil(t, event2.ProcessGraph)
+
+	_, err = ConvertJSONToWebhookEvent("invalid json")
+	assert.NotNil(t, err)
+}
+
+func TestWebhookDeliveryArrayToJSON(t *testing.T) {
+	delivery := &WebhookDelivery{ID: GenerateRandomID(), WebhookID: GenerateRandomID(), ColonyName: "test_colony", EventType: WebhookEventProcessFailed, Attempts: 2, StatusCode: 500}
+	jsonStr, err := ConvertWebhookDeliveryArrayToJSON([]*WebhookDelivery{delivery})
+	assert.Nil(t, err)
+
+	deliveries, err := ConvertJSONToWebhookDeliveryArray(jsonStr)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, delivery.ID, deliveries[0].ID)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+
+	_, err = ConvertJSONToWebhookDeliveryArray("invalid json")
+	assert.NotNil(t, err)
+}
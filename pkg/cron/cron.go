@@ -2,19 +2,32 @@ package cron
 
 import (
 	"math/rand"
+	"strings"
 	"time"
 )
 
+// Cron expressions have 5 fields, or 6 fields if the first field is seconds
+var optionalSecondParser = NewParser(SecondOptional | Minute | Hour | Dom | Month | Dow | Descriptor)
+
 func Next(cronExpr string) (time.Time, error) {
-	parser := NewParser(Second | Minute | Hour | Dom | Month | Dow | Descriptor)
+	return NextInLocation(cronExpr, time.Local, time.Now())
+}
 
-	p, err := parser.Parse(cronExpr)
+// NextInLocation returns the first activation of a cron expression after t. The expression is evaluated
+// in loc, e.g. 0 2 * * * is 02:00 in loc also across daylight saving time changes, unless the expression
+// starts with TZ= or CRON_TZ=.
+func NextInLocation(cronExpr string, loc *time.Location, t time.Time) (time.Time, error) {
+	schedule, err := optionalSecondParser.Parse(cronExpr)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	now := time.Now()
-	return p.Next(now), nil
+	hasTimeZone := strings.HasPrefix(cronExpr, "TZ=") || strings.HasPrefix(cronExpr, "CRON_TZ=")
+	if spec, ok := schedule.(*SpecSchedule); ok && loc != nil && !hasTimeZone {
+		spec.Location = loc
+	}
+
+	return schedule.Next(t), nil
 }
 
 func NextInterval(interval int) (time.Time, error) {
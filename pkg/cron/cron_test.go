@@ -35,3 +35,32 @@ func TestCronRandom(t *testing.T) {
 	nextTime2, err := Random(60 * 60 * 24 * 7) // random time the coming week
 	assert.NotEqual(t, nextTime, nextTime2)
 }
+
+func TestCronNextInLocation(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	assert.Nil(t, err)
+
+	// 02:00 in Stockholm is 00:00 UTC in summer and 01:00 UTC in winter
+	summer := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	next, err := NextInLocation("0 2 * * *", stockholm, summer)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+
+	winter := time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+	next, err = NextInLocation("0 2 * * *", stockholm, winter)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 12, 2, 1, 0, 0, 0, time.UTC), next.UTC())
+
+	// Seconds are optional
+	next, err = NextInLocation("30 0 2 * * *", stockholm, winter)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 12, 2, 1, 0, 30, 0, time.UTC), next.UTC())
+
+	// A time zone in the expression overrides the location
+	next, err = NextInLocation("CRON_TZ=UTC 0 2 * * *", stockholm, winter)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 12, 2, 2, 0, 0, 0, time.UTC), next.UTC())
+
+	_, err = NextInLocation("0 2 * *", stockholm, winter)
+	assert.NotNil(t, err)
+}
@@ -15,4 +15,8 @@ type CronDatabase interface {
 	FindAllCrons() ([]*core.Cron, error)
 	RemoveCronByID(cronID string) error
 	RemoveAllCronsByColonyName(colonyName string) error
+	SetCronSuspended(cronID string, suspended bool) error
+	AddCronRun(run *core.CronRun) error
+	GetCronRuns(cronID string, count int) ([]*core.CronRun, error)
+	PruneCronRuns(cronID string, keep int) error
 }
\ No newline at end of file
@@ -17,4 +17,9 @@ type Database interface {
 	BlueprintDatabase
 	SecurityDatabase
 	LocationDatabase
+	WebhookDatabase
+	StorageBackendDatabase
+	FileRetentionDatabase
+	FileLineageDatabase
+	LogSinkDatabase
 }
\ No newline at end of file
@@ -7,8 +7,11 @@ type FileDatabase interface {
 	GetFileByID(colonyName string, fileID string) (*core.File, error)
 	GetLatestFileByName(colonyName string, label string, name string) ([]*core.File, error)
 	GetFileByName(colonyName string, label string, name string) ([]*core.File, error)
+	GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error)
+	GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error)
 	GetFilenamesByLabel(colonyName string, label string) ([]string, error)
 	GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error)
+	UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string) error
 	RemoveFileByID(colonyName string, fileID string) error
 	RemoveFileByName(colonyName string, label string, name string) error
 	GetFileLabels(colonyName string) ([]*core.Label, error)
@@ -0,0 +1,11 @@
+package database
+
+import "github.com/colonyos/colonies/pkg/core"
+
+type FileLineageDatabase interface {
+	AddFileLineages(lineages []*core.FileLineage) error
+	GetFileLineageByProcessID(colonyName string, processID string) ([]*core.FileLineage, error)
+	GetFileLineageByProcessGraphID(colonyName string, processGraphID string) ([]*core.FileLineage, error)
+	GetFileLineageByFileID(colonyName string, fileID string) ([]*core.FileLineage, error)
+	RemoveFileLineageByColonyName(colonyName string) error
+}
@@ -0,0 +1,11 @@
+package database
+
+import "github.com/colonyos/colonies/pkg/core"
+
+type FileRetentionDatabase interface {
+	SetFileRetentionPolicy(policy *core.FileRetentionPolicy) error
+	GetFileRetentionPolicyByLabel(colonyName string, label string) (*core.FileRetentionPolicy, error)
+	GetFileRetentionPoliciesByColonyName(colonyName string) ([]*core.FileRetentionPolicy, error)
+	RemoveFileRetentionPolicyByLabel(colonyName string, label string) error
+	RemoveFileRetentionPoliciesByColonyName(colonyName string) error
+}
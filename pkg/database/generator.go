@@ -1,11 +1,17 @@
 package database
 
-import "github.com/colonyos/colonies/pkg/core"
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
 
 type GeneratorDatabase interface {
 	AddGenerator(generator *core.Generator) error
 	SetGeneratorLastRun(generatorID string) error
 	SetGeneratorFirstPack(generatorID string) error
+	SetGeneratorLastWindowEnd(generatorID string, windowEnd time.Time) error
+	SetGeneratorWindowKeys(generatorID string, windowEnd time.Time, keys []string) error
 	GetGeneratorByID(generatorID string) (*core.Generator, error)
 	GetGeneratorByName(colonyName string, name string) (*core.Generator, error)
 	FindGeneratorsByColonyName(colonyName string, count int) ([]*core.Generator, error)
@@ -14,8 +20,12 @@ type GeneratorDatabase interface {
 	RemoveAllGeneratorsByColonyName(colonyName string) error
 	AddGeneratorArg(generatorArg *core.GeneratorArg) error
 	GetGeneratorArgs(generatorID string, count int) ([]*core.GeneratorArg, error)
+	GetGeneratorArgsByKey(generatorID string, key string, count int) ([]*core.GeneratorArg, error)
+	GetGeneratorArgsByKeyAndTime(generatorID string, key string, from time.Time, to time.Time) ([]*core.GeneratorArg, error)
+	GetGeneratorArgGroups(generatorID string) ([]*core.GeneratorArgGroup, error)
 	CountGeneratorArgs(generatorID string) (int, error)
 	RemoveGeneratorArgByID(generatorArgsID string) error
+	RemoveGeneratorArgsAddedBefore(generatorID string, before time.Time) error
 	RemoveAllGeneratorArgsByGeneratorID(generatorID string) error
 	RemoveAllGeneratorArgsByColonyName(generatorID string) error
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+package database
+
+import "github.com/colonyos/colonies/pkg/core"
+
+type LogSinkDatabase interface {
+	AddLogSink(sink *core.LogSink) error
+	GetLogSinkByName(colonyName string, name string) (*core.LogSink, error)
+	GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error)
+	RemoveLogSinkByName(colonyName string, name string) error
+	RemoveLogSinksByColonyName(colonyName string) error
+}
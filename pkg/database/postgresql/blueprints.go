@@ -231,6 +231,8 @@ func (db *PQDatabase) AddBlueprint(blueprint *core.Blueprint) error {
 		return errors.New("Blueprint with name <" + blueprint.Metadata.Name + "> in namespace <" + blueprint.Metadata.ColonyName + "> already exists")
 	}
 
+	blueprint.Metadata.ResourceVersion = 1
+
 	blueprintJSON, err := blueprint.ToJSON()
 	if err != nil {
 		return err
@@ -386,7 +388,24 @@ func (db *PQDatabase) GetBlueprintsByNamespaceKindAndLocation(namespace, kind, l
 	return db.parseBlueprints(rows)
 }
 
+// blueprintResourceVersion extracts the resource version from the DATA column, blueprints written
+// before resource versions were introduced have version 0
+const blueprintResourceVersion = `COALESCE((DATA::jsonb->'metadata'->>'resourceVersion')::bigint, 0)`
+
 func (db *PQDatabase) UpdateBlueprint(blueprint *core.Blueprint) error {
+	return db.updateBlueprint(blueprint, 0, true)
+}
+
+// UpdateBlueprintWithResourceVersion replaces a blueprint if its stored resource version matches
+// resourceVersion. Blueprints written before resource versions were introduced have version 0. The
+// resource version is incremented atomically and set on the blueprint.
+func (db *PQDatabase) UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64) error {
+	return db.updateBlueprint(blueprint, resourceVersion, false)
+}
+
+// updateBlueprint replaces a blueprint, if unconditional is false only if its stored resource version
+// matches resourceVersion
+func (db *PQDatabase) updateBlueprint(blueprint *core.Blueprint, resourceVersion int64, unconditional bool) error {
 	if blueprint == nil {
 		return errors.New("Blueprint is nil")
 	}
@@ -396,16 +415,38 @@ func (db *PQDatabase) UpdateBlueprint(blueprint *core.Blueprint) error {
 		return err
 	}
 
-	sqlStatement := `UPDATE ` + db.dbPrefix + `BLUEPRINTS SET COLONY_NAME=$1, NAME=$2, KIND=$3, DATA=$4 WHERE ID=$5`
-	_, err = db.postgresql.Exec(sqlStatement, blueprint.Metadata.ColonyName, blueprint.Metadata.Name, blueprint.Kind, blueprintJSON, blueprint.ID)
+	sqlStatement := `UPDATE ` + db.dbPrefix + `BLUEPRINTS SET COLONY_NAME=$1, NAME=$2, KIND=$3,
+		DATA = jsonb_set($4::jsonb, '{metadata,resourceVersion}', to_jsonb(` + blueprintResourceVersion + ` + 1))
+		WHERE ID=$5 AND ($6::boolean OR ` + blueprintResourceVersion + ` = $7::bigint)
+		RETURNING ` + blueprintResourceVersion
+	var newResourceVersion int64
+	err = db.postgresql.QueryRow(sqlStatement, blueprint.Metadata.ColonyName, blueprint.Metadata.Name, blueprint.Kind, blueprintJSON, blueprint.ID, unconditional, resourceVersion).Scan(&newResourceVersion)
+	if err == sql.ErrNoRows {
+		if unconditional {
+			return nil
+		}
+		return db.resourceVersionConflict(blueprint.ID, resourceVersion)
+	}
 	if err != nil {
 		return err
 	}
 
+	blueprint.Metadata.ResourceVersion = newResourceVersion
+
 	return nil
 }
 
 func (db *PQDatabase) UpdateBlueprintStatus(id string, status map[string]interface{}) error {
+	return db.updateBlueprintStatus(id, status, 0, true)
+}
+
+// UpdateBlueprintStatusWithResourceVersion replaces the status of a blueprint if its stored resource
+// version matches resourceVersion
+func (db *PQDatabase) UpdateBlueprintStatusWithResourceVersion(id string, status map[string]interface{}, resourceVersion int64) error {
+	return db.updateBlueprintStatus(id, status, resourceVersion, false)
+}
+
+func (db *PQDatabase) updateBlueprintStatus(id string, status map[string]interface{}, resourceVersion int64, unconditional bool) error {
 	// Convert status to JSON
 	statusJSON, err := json.Marshal(status)
 	if err != nil {
@@ -415,8 +456,10 @@ func (db *PQDatabase) UpdateBlueprintStatus(id string, status map[string]interfa
 	// Use PostgreSQL's jsonb_set to atomically update only the status field
 	// This eliminates the read-modify-write race condition by doing the update
 	// in a single atomic SQL statement
-	updateStatement := `UPDATE ` + db.dbPrefix + `BLUEPRINTS SET DATA = jsonb_set(DATA::jsonb, '{status}', $1::jsonb) WHERE ID=$2`
-	result, err := db.postgresql.Exec(updateStatement, string(statusJSON), id)
+	updateStatement := `UPDATE ` + db.dbPrefix + `BLUEPRINTS
+		SET DATA = jsonb_set(jsonb_set(DATA::jsonb, '{status}', $1::jsonb), '{metadata,resourceVersion}', to_jsonb(` + blueprintResourceVersion + ` + 1))
+		WHERE ID=$2 AND ($3::boolean OR ` + blueprintResourceVersion + ` = $4::bigint)`
+	result, err := db.postgresql.Exec(updateStatement, string(statusJSON), id, unconditional, resourceVersion)
 	if err != nil {
 		return err
 	}
@@ -427,12 +470,30 @@ func (db *PQDatabase) UpdateBlueprintStatus(id string, status map[string]interfa
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("Blueprint not found")
+		if unconditional {
+			return errors.New("Blueprint not found")
+		}
+		return db.resourceVersionConflict(id, resourceVersion)
 	}
 
 	return nil
 }
 
+// resourceVersionConflict is called when a conditional write did not match any row, and tells a
+// missing blueprint apart from one that has been modified
+func (db *PQDatabase) resourceVersionConflict(id string, resourceVersion int64) error {
+	blueprint, err := db.GetBlueprintByID(id)
+	if err != nil {
+		return err
+	}
+
+	if blueprint == nil {
+		return errors.New("Blueprint not found")
+	}
+
+	return fmt.Errorf("%w: blueprint '%s' has resource version %d, expected %d", core.ErrResourceVersionConflict, blueprint.Metadata.Name, blueprint.Metadata.ResourceVersion, resourceVersion)
+}
+
 func (db *PQDatabase) RemoveBlueprintByID(id string) error {
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `BLUEPRINTS WHERE ID=$1`
 	_, err := db.postgresql.Exec(sqlStatement, id)
@@ -453,6 +514,124 @@ func (db *PQDatabase) RemoveBlueprintByName(namespace, name string) error {
 	return nil
 }
 
+// RemoveBlueprintByNameWithResourceVersion removes a blueprint if its stored resource version matches
+// resourceVersion
+func (db *PQDatabase) RemoveBlueprintByNameWithResourceVersion(namespace, name string, resourceVersion int64) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `BLUEPRINTS WHERE COLONY_NAME=$1 AND NAME=$2 AND ` + blueprintResourceVersion + ` = $3::bigint`
+	result, err := db.postgresql.Exec(sqlStatement, namespace, name, resourceVersion)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		blueprint, err := db.GetBlueprintByName(namespace, name)
+		if err != nil {
+			return err
+		}
+		if blueprint != nil {
+			return db.resourceVersionConflict(blueprint.ID, resourceVersion)
+		}
+	}
+
+	return nil
+}
+
+// ApplyBlueprints adds, updates and removes blueprints in a single transaction, if any of the writes
+// fails none of them are made. Updates and removes are conditioned on the resource version of each
+// blueprint, i.e. the version it had when it was read.
+func (db *PQDatabase) ApplyBlueprints(creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error {
+	tx, err := db.postgresql.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = db.applyBlueprints(tx, creates, updates, removes)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, blueprint := range creates {
+		blueprint.Metadata.ResourceVersion = 1
+	}
+	for _, blueprint := range updates {
+		blueprint.Metadata.ResourceVersion++
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) applyBlueprints(tx *sql.Tx, creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error {
+	for _, blueprint := range creates {
+		created := *blueprint
+		created.Metadata.ResourceVersion = 1
+		blueprintJSON, err := created.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		sqlStatement := `INSERT INTO ` + db.dbPrefix + `BLUEPRINTS (ID, COLONY_NAME, NAME, KIND, DATA) VALUES ($1, $2, $3, $4, $5)`
+		_, err = tx.Exec(sqlStatement, blueprint.ID, blueprint.Metadata.ColonyName, blueprint.Metadata.Name, blueprint.Kind, blueprintJSON)
+		if err != nil {
+			return fmt.Errorf("failed to add blueprint '%s': %w", blueprint.Metadata.Name, err)
+		}
+	}
+
+	for _, blueprint := range updates {
+		blueprintJSON, err := blueprint.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		sqlStatement := `UPDATE ` + db.dbPrefix + `BLUEPRINTS SET COLONY_NAME=$1, NAME=$2, KIND=$3,
+			DATA = jsonb_set($4::jsonb, '{metadata,resourceVersion}', to_jsonb(` + blueprintResourceVersion + ` + 1))
+			WHERE ID=$5 AND ` + blueprintResourceVersion + ` = $6::bigint`
+		result, err := tx.Exec(sqlStatement, blueprint.Metadata.ColonyName, blueprint.Metadata.Name, blueprint.Kind, blueprintJSON, blueprint.ID, blueprint.Metadata.ResourceVersion)
+		if err != nil {
+			return fmt.Errorf("failed to update blueprint '%s': %w", blueprint.Metadata.Name, err)
+		}
+		if err := checkApplied(result, blueprint); err != nil {
+			return err
+		}
+	}
+
+	for _, blueprint := range removes {
+		sqlStatement := `DELETE FROM ` + db.dbPrefix + `BLUEPRINTS WHERE ID=$1 AND ` + blueprintResourceVersion + ` = $2::bigint`
+		result, err := tx.Exec(sqlStatement, blueprint.ID, blueprint.Metadata.ResourceVersion)
+		if err != nil {
+			return fmt.Errorf("failed to remove blueprint '%s': %w", blueprint.Metadata.Name, err)
+		}
+		if err := checkApplied(result, blueprint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkApplied returns a conflict if a conditional write in ApplyBlueprints did not match the blueprint
+func checkApplied(result sql.Result, blueprint *core.Blueprint) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: blueprint '%s' was modified or removed after resource version %d", core.ErrResourceVersionConflict, blueprint.Metadata.Name, blueprint.Metadata.ResourceVersion)
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) RemoveBlueprintsByNamespace(namespace string) error {
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `BLUEPRINTS WHERE COLONY_NAME=$1`
 	_, err := db.postgresql.Exec(sqlStatement, namespace)
@@ -656,3 +835,15 @@ func (db *PQDatabase) RemoveBlueprintHistory(blueprintID string) error {
 	_, err := db.postgresql.Exec(sqlStatement, blueprintID)
 	return err
 }
+
+// PruneBlueprintHistory removes all but the keep most recent history entries of a blueprint
+func (db *PQDatabase) PruneBlueprintHistory(blueprintID string, keep int) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `BLUEPRINT_HISTORY
+		WHERE BLUEPRINT_ID=$1 AND ID NOT IN (
+			SELECT ID FROM ` + db.dbPrefix + `BLUEPRINT_HISTORY
+			WHERE BLUEPRINT_ID=$1
+			ORDER BY TIMESTAMP DESC
+			LIMIT $2)`
+	_, err := db.postgresql.Exec(sqlStatement, blueprintID, keep)
+	return err
+}
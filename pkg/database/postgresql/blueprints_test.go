@@ -1,8 +1,10 @@
 package postgresql
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -286,6 +288,103 @@ func TestUpdateBlueprintStatus(t *testing.T) {
 	assert.Equal(t, float64(3), ready)
 }
 
+func TestBlueprintResourceVersion(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	blueprint := core.CreateBlueprint("ExecutorDeployment", "web-server", "production")
+	blueprint.SetSpec("replicas", 3)
+
+	err = db.AddBlueprint(blueprint)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), blueprint.Metadata.ResourceVersion)
+
+	// 0 is a resource version like any other, not a wildcard
+	err = db.UpdateBlueprintWithResourceVersion(blueprint, 0)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+	err = db.RemoveBlueprintByNameWithResourceVersion("production", "web-server", 0)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+
+	blueprint.SetSpec("replicas", 5)
+	err = db.UpdateBlueprintWithResourceVersion(blueprint, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), blueprint.Metadata.ResourceVersion)
+
+	err = db.UpdateBlueprintWithResourceVersion(blueprint, 1)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+
+	// Unconditional writes still bump the resource version
+	err = db.UpdateBlueprintStatus(blueprint.ID, map[string]interface{}{"phase": "Running"})
+	assert.Nil(t, err)
+
+	err = db.UpdateBlueprintStatusWithResourceVersion(blueprint.ID, map[string]interface{}{"phase": "Failed"}, 2)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+
+	blueprintFromDB, err := db.GetBlueprintByID(blueprint.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), blueprintFromDB.Metadata.ResourceVersion)
+	assert.Equal(t, "Running", blueprintFromDB.Status["phase"])
+	assert.Equal(t, float64(5), blueprintFromDB.Spec["replicas"])
+
+	err = db.RemoveBlueprintByNameWithResourceVersion("production", "web-server", 2)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+
+	err = db.RemoveBlueprintByNameWithResourceVersion("production", "web-server", 3)
+	assert.Nil(t, err)
+
+	err = db.UpdateBlueprintWithResourceVersion(blueprint, 3)
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, core.ErrResourceVersionConflict))
+}
+
+func TestApplyBlueprints(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	existing := core.CreateBlueprint("ExecutorDeployment", "existing", "production")
+	err = db.AddBlueprint(existing)
+	assert.Nil(t, err)
+
+	pruned := core.CreateBlueprint("ExecutorDeployment", "pruned", "production")
+	err = db.AddBlueprint(pruned)
+	assert.Nil(t, err)
+
+	created := core.CreateBlueprint("ExecutorDeployment", "created", "production")
+	existing.SetSpec("replicas", 2)
+
+	err = db.ApplyBlueprints([]*core.Blueprint{created}, []*core.Blueprint{existing}, []*core.Blueprint{pruned})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), created.Metadata.ResourceVersion)
+	assert.Equal(t, int64(2), existing.Metadata.ResourceVersion)
+
+	blueprints, err := db.GetBlueprintsByNamespace("production")
+	assert.Nil(t, err)
+	assert.Len(t, blueprints, 2)
+
+	existingFromDB, err := db.GetBlueprintByName("production", "existing")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), existingFromDB.Spec["replicas"])
+
+	// A conflicting update rolls back the whole apply
+	another := core.CreateBlueprint("ExecutorDeployment", "another", "production")
+	existing.SetSpec("replicas", 3)
+	existing.Metadata.ResourceVersion = 1
+	err = db.ApplyBlueprints([]*core.Blueprint{another}, []*core.Blueprint{existing}, nil)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+
+	anotherFromDB, err := db.GetBlueprintByName("production", "another")
+	assert.Nil(t, err)
+	assert.Nil(t, anotherFromDB)
+
+	existingFromDB, err = db.GetBlueprintByName("production", "existing")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), existingFromDB.Spec["replicas"])
+}
+
 func TestRemoveBlueprint(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
@@ -523,6 +622,45 @@ func TestRemoveBlueprintHistory(t *testing.T) {
 	assert.Equal(t, 0, len(historiesAfter))
 }
 
+func TestPruneBlueprintHistory(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	blueprint := core.CreateBlueprint("ExecutorDeployment", "web-server", "production")
+	err = db.AddBlueprint(blueprint)
+	assert.Nil(t, err)
+
+	other := core.CreateBlueprint("ExecutorDeployment", "database", "production")
+	err = db.AddBlueprint(other)
+	assert.Nil(t, err)
+
+	err = db.AddBlueprintHistory(core.CreateBlueprintHistory(other, "user1", "create"))
+	assert.Nil(t, err)
+
+	for generation := int64(1); generation <= 4; generation++ {
+		blueprint.Metadata.Generation = generation
+		history := core.CreateBlueprintHistory(blueprint, "user1", "update")
+		history.Timestamp = time.Now().Add(time.Duration(generation) * time.Second)
+		err = db.AddBlueprintHistory(history)
+		assert.Nil(t, err)
+	}
+
+	err = db.PruneBlueprintHistory(blueprint.ID, 2)
+	assert.Nil(t, err)
+
+	histories, err := db.GetBlueprintHistory(blueprint.ID, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, int64(4), histories[0].Generation)
+	assert.Equal(t, int64(3), histories[1].Generation)
+
+	otherHistories, err := db.GetBlueprintHistory(other.ID, 0)
+	assert.Nil(t, err)
+	assert.Len(t, otherHistories, 1)
+}
+
 func TestBlueprintHistoryWithStatusChanges(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
@@ -22,8 +22,8 @@ func (db *PQDatabase) AddColony(colony *core.Colony) error {
 		return errors.New("Colony with name <" + colony.Name + "> already exists")
 	}
 
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `COLONIES (COLONY_ID, NAME) VALUES ($1, $2)`
-	_, err = db.postgresql.Exec(sqlStatement, colony.ID, colony.Name)
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `COLONIES (COLONY_ID, NAME, REQUIRE_FUNC_REG) VALUES ($1, $2, $3)`
+	_, err = db.postgresql.Exec(sqlStatement, colony.ID, colony.Name, colony.RequireFuncReg)
 	if err != nil {
 		return err
 	}
@@ -37,11 +37,13 @@ func (db *PQDatabase) parseColonies(rows *sql.Rows) ([]*core.Colony, error) {
 	for rows.Next() {
 		var name string
 		var colonyID string
-		if err := rows.Scan(&name, &colonyID); err != nil {
+		var requireFuncReg sql.NullBool
+		if err := rows.Scan(&name, &colonyID, &requireFuncReg); err != nil {
 			return nil, err
 		}
 
 		colony := core.CreateColony(colonyID, name)
+		colony.RequireFuncReg = requireFuncReg.Bool
 		colonies = append(colonies, colony)
 	}
 
@@ -193,6 +195,36 @@ func (db *PQDatabase) RemoveColonyByName(colonyName string) error {
 		return err
 	}
 
+	err = db.RemoveWebhooksByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveStorageBackendsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveStorageObjectsByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFileRetentionPoliciesByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveFileLineageByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
+	err = db.RemoveLogSinksByColonyName(colony.Name)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
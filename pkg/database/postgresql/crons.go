@@ -10,8 +10,8 @@ import (
 )
 
 func (db *PQDatabase) AddCron(cron *core.Cron) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `CRONS (CRON_ID, COLONY_NAME, NAME, CRON_EXPR, INTERVAL, RANDOM, NEXT_RUN, LAST_RUN, WORKFLOW_SPEC, PREV_PROCESSGRAPH_ID, WAIT_FOR_PREV_PROCESSGRAPH, INITIATOR_ID, INITIATOR_NAME) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
-	_, err := db.postgresql.Exec(sqlStatement, cron.ID, cron.ColonyName, cron.Name, cron.CronExpression, cron.Interval, cron.Random, cron.NextRun, cron.LastRun, cron.WorkflowSpec, cron.PrevProcessGraphID, cron.WaitForPrevProcessGraph, cron.InitiatorID, cron.InitiatorName)
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `CRONS (CRON_ID, COLONY_NAME, NAME, CRON_EXPR, INTERVAL, RANDOM, NEXT_RUN, LAST_RUN, WORKFLOW_SPEC, PREV_PROCESSGRAPH_ID, WAIT_FOR_PREV_PROCESSGRAPH, INITIATOR_ID, INITIATOR_NAME, TIMEZONE, START_TIME, END_TIME, JITTER, MISSED_RUN_POLICY, CONCURRENCY_POLICY, MAX_CONCURRENT_RUNS, SUSPENDED, RUN_HISTORY_LIMIT) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
+	_, err := db.postgresql.Exec(sqlStatement, cron.ID, cron.ColonyName, cron.Name, cron.CronExpression, cron.Interval, cron.Random, cron.NextRun, cron.LastRun, cron.WorkflowSpec, cron.PrevProcessGraphID, cron.WaitForPrevProcessGraph, cron.InitiatorID, cron.InitiatorName, cron.TimeZone, cron.StartTime, cron.EndTime, cron.Jitter, cron.MissedRunPolicy, cron.ConcurrencyPolicy, cron.MaxConcurrentRuns, cron.Suspended, cron.RunHistoryLimit)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
 			return errors.New("Cron with name <" + cron.Name + "> in Colony <" + cron.ColonyName + "> already exists")
@@ -49,8 +49,17 @@ func (db *PQDatabase) parseCrons(rows *sql.Rows) ([]*core.Cron, error) {
 		var waitForPrevProcessGraph bool
 		var initiatorID string
 		var initiatorName string
-
-		if err := rows.Scan(&cronID, &colonyName, &name, &cronExpr, &interval, &random, &nextRun, &lastRun, &workflowSpec, &prevProcessGraphID, &waitForPrevProcessGraph, &initiatorID, &initiatorName); err != nil {
+		var timeZone string
+		var startTime time.Time
+		var endTime time.Time
+		var jitter int
+		var missedRunPolicy string
+		var concurrencyPolicy string
+		var maxConcurrentRuns int
+		var suspended bool
+		var runHistoryLimit int
+
+		if err := rows.Scan(&cronID, &colonyName, &name, &cronExpr, &interval, &random, &nextRun, &lastRun, &workflowSpec, &prevProcessGraphID, &waitForPrevProcessGraph, &initiatorID, &initiatorName, &timeZone, &startTime, &endTime, &jitter, &missedRunPolicy, &concurrencyPolicy, &maxConcurrentRuns, &suspended, &runHistoryLimit); err != nil {
 			return nil, err
 		}
 
@@ -58,6 +67,15 @@ func (db *PQDatabase) parseCrons(rows *sql.Rows) ([]*core.Cron, error) {
 
 		cron.InitiatorID = initiatorID
 		cron.InitiatorName = initiatorName
+		cron.TimeZone = timeZone
+		cron.StartTime = startTime
+		cron.EndTime = endTime
+		cron.Jitter = jitter
+		cron.MissedRunPolicy = missedRunPolicy
+		cron.ConcurrencyPolicy = concurrencyPolicy
+		cron.MaxConcurrentRuns = maxConcurrentRuns
+		cron.Suspended = suspended
+		cron.RunHistoryLimit = runHistoryLimit
 
 		crons = append(crons, cron)
 	}
@@ -145,15 +163,80 @@ func (db *PQDatabase) RemoveCronByID(cronID string) error {
 		return err
 	}
 
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `CRONRUNS WHERE CRON_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, cronID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (db *PQDatabase) RemoveAllCronsByColonyName(colonyName string) error {
-	sqlStatement := `DELETE FROM ` + db.dbPrefix + `CRONS WHERE COLONY_NAME=$1`
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `CRONRUNS WHERE CRON_ID IN (SELECT CRON_ID FROM ` + db.dbPrefix + `CRONS WHERE COLONY_NAME=$1)`
 	_, err := db.postgresql.Exec(sqlStatement, colonyName)
 	if err != nil {
 		return err
 	}
 
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `CRONS WHERE COLONY_NAME=$1`
+	_, err = db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) SetCronSuspended(cronID string, suspended bool) error {
+	sqlStatement := `UPDATE  ` + db.dbPrefix + `CRONS SET SUSPENDED=$1 WHERE CRON_ID=$2`
+	_, err := db.postgresql.Exec(sqlStatement, suspended, cronID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) AddCronRun(run *core.CronRun) error {
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `CRONRUNS (CRON_ID, PROCESSGRAPH_ID, START_TIME) VALUES ($1, $2, $3)`
+	_, err := db.postgresql.Exec(sqlStatement, run.CronID, run.ProcessGraphID, run.StartTime)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCronRuns returns the latest runs of a cron, newest first. The state of the runs is not stored and is
+// left as WAITING.
+func (db *PQDatabase) GetCronRuns(cronID string, count int) ([]*core.CronRun, error) {
+	sqlStatement := `SELECT CRON_ID, PROCESSGRAPH_ID, START_TIME FROM ` + db.dbPrefix + `CRONRUNS WHERE CRON_ID=$1 ORDER BY START_TIME DESC LIMIT $2`
+	rows, err := db.postgresql.Query(sqlStatement, cronID, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*core.CronRun{}
+	for rows.Next() {
+		run := &core.CronRun{}
+		if err := rows.Scan(&run.CronID, &run.ProcessGraphID, &run.StartTime); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// PruneCronRuns removes all but the keep latest runs of a cron
+func (db *PQDatabase) PruneCronRuns(cronID string, keep int) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `CRONRUNS WHERE CRON_ID=$1 AND PROCESSGRAPH_ID NOT IN (SELECT PROCESSGRAPH_ID FROM ` + db.dbPrefix + `CRONRUNS WHERE CRON_ID=$1 ORDER BY START_TIME DESC LIMIT $2)`
+	_, err := db.postgresql.Exec(sqlStatement, cronID, keep)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -57,6 +57,115 @@ func TestAddCron(t *testing.T) {
 	assert.True(t, cron.Equals(cronFromDB))
 }
 
+func TestAddCronWithSchedule(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "0 2 * * *", 0, false, "workflow")
+	cron.ID = core.GenerateRandomID()
+	cron.TimeZone = "Europe/Stockholm"
+	cron.StartTime = time.Now()
+	cron.EndTime = time.Now().Add(24 * time.Hour)
+	cron.Jitter = 30
+	cron.MissedRunPolicy = core.CronMissedRunsCatchUp
+
+	err = db.AddCron(cron)
+	assert.Nil(t, err)
+
+	cronFromDB, err := db.GetCronByID(cron.ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, cronFromDB)
+	assert.True(t, cron.Equals(cronFromDB))
+	assert.Equal(t, "Europe/Stockholm", cronFromDB.TimeZone)
+	assert.Equal(t, 30, cronFromDB.Jitter)
+	assert.Equal(t, core.CronMissedRunsCatchUp, cronFromDB.MissedRunPolicy)
+}
+
+func TestSetCronSuspended(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "0 2 * * *", 0, false, "workflow")
+	cron.ID = core.GenerateRandomID()
+	cron.ConcurrencyPolicy = core.CronConcurrencyAllow
+	cron.MaxConcurrentRuns = 2
+	cron.RunHistoryLimit = 5
+
+	err = db.AddCron(cron)
+	assert.Nil(t, err)
+
+	cronFromDB, err := db.GetCronByID(cron.ID)
+	assert.Nil(t, err)
+	assert.True(t, cron.Equals(cronFromDB))
+	assert.False(t, cronFromDB.Suspended)
+
+	err = db.SetCronSuspended(cron.ID, true)
+	assert.Nil(t, err)
+
+	cronFromDB, err = db.GetCronByID(cron.ID)
+	assert.Nil(t, err)
+	assert.True(t, cronFromDB.Suspended)
+
+	err = db.SetCronSuspended(cron.ID, false)
+	assert.Nil(t, err)
+
+	cronFromDB, err = db.GetCronByID(cron.ID)
+	assert.Nil(t, err)
+	assert.False(t, cronFromDB.Suspended)
+}
+
+func TestCronRuns(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+	cron := core.CreateCron(colonyName, "test_name", "0 2 * * *", 0, false, "workflow")
+	cron.ID = core.GenerateRandomID()
+
+	err = db.AddCron(cron)
+	assert.Nil(t, err)
+
+	startTime := time.Now()
+	var processGraphIDs []string
+	for i := 0; i < 5; i++ {
+		processGraphID := core.GenerateRandomID()
+		processGraphIDs = append(processGraphIDs, processGraphID)
+		err = db.AddCronRun(&core.CronRun{CronID: cron.ID, ProcessGraphID: processGraphID, StartTime: startTime.Add(time.Duration(i) * time.Second)})
+		assert.Nil(t, err)
+	}
+
+	runs, err := db.GetCronRuns(cron.ID, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 5)
+	assert.Equal(t, processGraphIDs[4], runs[0].ProcessGraphID) // Newest first
+
+	runs, err = db.GetCronRuns(cron.ID, 2)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 2)
+
+	err = db.PruneCronRuns(cron.ID, 3)
+	assert.Nil(t, err)
+
+	runs, err = db.GetCronRuns(cron.ID, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 3)
+	assert.Equal(t, processGraphIDs[4], runs[0].ProcessGraphID)
+	assert.Equal(t, processGraphIDs[2], runs[2].ProcessGraphID)
+
+	err = db.RemoveCronByID(cron.ID)
+	assert.Nil(t, err)
+
+	runs, err = db.GetCronRuns(cron.ID, 100)
+	assert.Nil(t, err)
+	assert.Len(t, runs, 0)
+}
+
 func TestUpdateCron(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
@@ -265,6 +265,16 @@ func (db *PQDatabase) dropCronsTable() error {
 	return nil
 }
 
+func (db *PQDatabase) dropCronRunsTable() error {
+	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `CRONRUNS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) dropBlueprintDefinitionsTable() error {
 	sqlStatement := `DROP TABLE IF EXISTS ` + db.dbPrefix + `BLUEPRINTDEFINITIONS`
 	_, err := db.postgresql.Exec(sqlStatement)
@@ -305,6 +315,76 @@ func (db *PQDatabase) dropLocationsTable() error {
 	return nil
 }
 
+func (db *PQDatabase) dropWebhooksTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `WEBHOOKS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropWebhookDeliveriesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `WEBHOOK_DELIVERIES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropStorageBackendsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `STORAGE_BACKENDS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropStorageObjectsTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `STORAGE_OBJECTS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropFileRetentionPoliciesTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `FILE_RETENTION_POLICIES`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropFileLineageTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `FILE_LINEAGE`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) dropLogSinksTable() error {
+	sqlStatement := `DROP TABLE ` + db.dbPrefix + `LOG_SINKS`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) dropServerTable() error {
 	sqlStatement := `DROP TABLE ` + db.dbPrefix + `SERVER`
 	_, err := db.postgresql.Exec(sqlStatement)
@@ -386,6 +466,11 @@ func (db *PQDatabase) Drop() error {
 		return err
 	}
 
+	err = db.dropCronRunsTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.dropBlueprintDefinitionsTable()
 	if err != nil {
 		return err
@@ -406,6 +491,41 @@ func (db *PQDatabase) Drop() error {
 		return err
 	}
 
+	err = db.dropWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropStorageBackendsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropStorageObjectsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFileRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropFileLineageTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.dropLogSinksTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.dropServerTable()
 	if err != nil {
 		return err
@@ -437,7 +557,7 @@ func (db *PQDatabase) createServerTable() error {
 }
 
 func (db *PQDatabase) createColoniesTable() error {
-	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `COLONIES (NAME TEXT PRIMARY KEY NOT NULL, COLONY_ID TEXT NOT NULL)`
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `COLONIES (NAME TEXT PRIMARY KEY NOT NULL, COLONY_ID TEXT NOT NULL, REQUIRE_FUNC_REG BOOLEAN)`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -476,7 +596,7 @@ func (db *PQDatabase) createNodesTable() error {
 }
 
 func (db *PQDatabase) createFunctionsTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FUNCTIONS (FUNCTION_ID TEXT PRIMARY KEY NOT NULL, EXECUTOR_NAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, DESCRIPTION TEXT, ARGS TEXT, COUNTER INTEGER, MINWAITTIME FLOAT, MAXWAITTIME FLOAT, MINEXECTIME FLOAT, MAXEXECTIME FLOAT, AVGWAITTIME FLOAT, AVGEXECTIME FLOAT, LOCATION_NAME TEXT)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `FUNCTIONS (FUNCTION_ID TEXT PRIMARY KEY NOT NULL, EXECUTOR_NAME TEXT NOT NULL, EXECUTOR_TYPE TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, FUNCNAME TEXT NOT NULL, DESCRIPTION TEXT, ARGS TEXT, COUNTER INTEGER, MINWAITTIME FLOAT, MAXWAITTIME FLOAT, MINEXECTIME FLOAT, MAXEXECTIME FLOAT, AVGWAITTIME FLOAT, AVGEXECTIME FLOAT, LOCATION_NAME TEXT, REQUIRE_FUNC_REG BOOLEAN)`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -496,7 +616,7 @@ func (db *PQDatabase) createProcessesTable() error {
 }
 
 func (db *PQDatabase) createLogTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `LOGS (PROCESS_ID TEXT, COLONY_NAME TEXT NOT NULL, EXECUTOR_NAME TEXT NOT NULL, TS BIGINT, MSG TEXT NOT NULL, ADDED TIMESTAMPTZ)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `LOGS (PROCESS_ID TEXT, COLONY_NAME TEXT NOT NULL, EXECUTOR_NAME TEXT NOT NULL, TS BIGINT, MSG TEXT NOT NULL, ADDED TIMESTAMPTZ, PROCESSGRAPH_ID TEXT, LEVEL TEXT, STREAM TEXT, FIELDS JSONB)`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -512,7 +632,7 @@ func (db *PQDatabase) createFileTable() error {
 		return err
 	}
 
-	sqlStatement = `CREATE TABLE ` + db.dbPrefix + `FILES (FILE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, LABEL TEXT NOT NULL, NAME TEXT NOT NULL, SIZE BIGINT, SEQNR BIGINT, CHECKSUM TEXT, CHECKSUM_ALG TEXT, ADDED TIMESTAMPTZ, PROTOCOL TEXT, S3_SERVER TEXT, S3_PORT INTEGER, S3_TLS BOOLEAN, S3_ACCESSKEY TEXT, S3_SECRETKEY TEXT, S3_REGION TEXT, S3_ENCKEY TEXT, S3_ENCALG TEXT, S3_OBJ TEXT, S3_BUCKET TEXT)`
+	sqlStatement = `CREATE TABLE ` + db.dbPrefix + `FILES (FILE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, LABEL TEXT NOT NULL, NAME TEXT NOT NULL, SIZE BIGINT, SEQNR BIGINT, CHECKSUM TEXT, CHECKSUM_ALG TEXT, ADDED TIMESTAMPTZ, PROTOCOL TEXT, S3_SERVER TEXT, S3_PORT INTEGER, S3_TLS BOOLEAN, S3_ACCESSKEY TEXT, S3_SECRETKEY TEXT, S3_REGION TEXT, S3_ENCKEY TEXT, S3_ENCALG TEXT, S3_OBJ TEXT, S3_BUCKET TEXT, BACKEND TEXT, CHUNKS TEXT, SHARED BOOLEAN)`
 	_, err = db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -552,7 +672,7 @@ func (db *PQDatabase) createProcessGraphsTable() error {
 }
 
 func (db *PQDatabase) createGeneratorsTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORS (GENERATOR_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, WORKFLOW_SPEC TEXT NOT NULL, TRIGGER INTEGER, TIMEOUT INTEGER, LASTRUN TIMESTAMPTZ, FIRSTPACK TIMESTAMPTZ, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORS (GENERATOR_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, WORKFLOW_SPEC TEXT NOT NULL, TRIGGER INTEGER, TIMEOUT INTEGER, LASTRUN TIMESTAMPTZ, FIRSTPACK TIMESTAMPTZ, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL, TRIGGER_SIZE INTEGER, WINDOW_TYPE TEXT NOT NULL, WINDOW_SIZE INTEGER, WINDOW_SLIDE INTEGER, LAST_WINDOW_END TIMESTAMPTZ, BATCH_FORMAT TEXT NOT NULL, OPEN_WINDOW_END TIMESTAMPTZ, WINDOW_KEYS TEXT[])`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
@@ -562,22 +682,44 @@ func (db *PQDatabase) createGeneratorsTable() error {
 }
 
 func (db *PQDatabase) createGeneratorArgsTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORARGS (GENERATORARG_ID TEXT PRIMARY KEY NOT NULL, GENERATOR_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, ARG TEXT NOT NULL)`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `GENERATORARGS (GENERATORARG_ID TEXT PRIMARY KEY NOT NULL, GENERATOR_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, ARG TEXT NOT NULL, ARG_KEY TEXT NOT NULL, ADDED TIMESTAMPTZ NOT NULL, SEQ BIGSERIAL)`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
 	}
 
+	indexStatement := `CREATE INDEX ` + db.dbPrefix + `GENERATORARGS_INDEX1 ON ` + db.dbPrefix + `GENERATORARGS (GENERATOR_ID, ARG_KEY, SEQ)`
+	_, err = db.postgresql.Exec(indexStatement)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (db *PQDatabase) createCronsTable() error {
-	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `CRONS (CRON_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, CRON_EXPR TEXT NOT NULL, INTERVAL INT, RANDOM BOOLEAN, NEXT_RUN TIMESTAMPTZ, LAST_RUN TIMESTAMPTZ, WORKFLOW_SPEC TEXT NOT NULL, PREV_PROCESSGRAPH_ID TEXT NOT NULL, WAIT_FOR_PREV_PROCESSGRAPH BOOLEAN, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL, UNIQUE(COLONY_NAME, NAME))`
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `CRONS (CRON_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, CRON_EXPR TEXT NOT NULL, INTERVAL INT, RANDOM BOOLEAN, NEXT_RUN TIMESTAMPTZ, LAST_RUN TIMESTAMPTZ, WORKFLOW_SPEC TEXT NOT NULL, PREV_PROCESSGRAPH_ID TEXT NOT NULL, WAIT_FOR_PREV_PROCESSGRAPH BOOLEAN, INITIATOR_ID TEXT NOT NULL, INITIATOR_NAME TEXT NOT NULL, TIMEZONE TEXT NOT NULL, START_TIME TIMESTAMPTZ, END_TIME TIMESTAMPTZ, JITTER INT, MISSED_RUN_POLICY TEXT NOT NULL, CONCURRENCY_POLICY TEXT NOT NULL, MAX_CONCURRENT_RUNS INT, SUSPENDED BOOLEAN, RUN_HISTORY_LIMIT INT, UNIQUE(COLONY_NAME, NAME))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createCronRunsTable() error {
+	sqlStatement := `CREATE TABLE ` + db.dbPrefix + `CRONRUNS (CRON_ID TEXT NOT NULL, PROCESSGRAPH_ID TEXT NOT NULL, START_TIME TIMESTAMPTZ NOT NULL, PRIMARY KEY (CRON_ID, PROCESSGRAPH_ID))`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
 	}
 
+	indexStatement := `CREATE INDEX ` + db.dbPrefix + `CRONRUNS_INDEX1 ON ` + db.dbPrefix + `CRONRUNS (CRON_ID, START_TIME DESC)`
+	_, err = db.postgresql.Exec(indexStatement)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -611,6 +753,88 @@ func (db *PQDatabase) createLocationsTable() error {
 	return nil
 }
 
+func (db *PQDatabase) createWebhooksTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, DATA TEXT NOT NULL, ADDED TIMESTAMPTZ, UNIQUE(COLONY_NAME, NAME))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createWebhookDeliveriesTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `WEBHOOK_DELIVERIES (DELIVERY_ID TEXT PRIMARY KEY NOT NULL, WEBHOOK_ID TEXT NOT NULL, COLONY_NAME TEXT NOT NULL, EVENT_ID TEXT NOT NULL, EVENT_TYPE TEXT NOT NULL, ATTEMPTS INTEGER, STATUS_CODE INTEGER, SUCCESS BOOLEAN, ERROR TEXT, ADDED TIMESTAMPTZ, LAST_ATTEMPT TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	indexStatement := `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `WEBHOOK_DELIVERIES_INDEX1 ON ` + db.dbPrefix + `WEBHOOK_DELIVERIES (WEBHOOK_ID, ADDED DESC)`
+	_, err = db.postgresql.Exec(indexStatement)
+	return err
+}
+
+func (db *PQDatabase) createStorageBackendsTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `STORAGE_BACKENDS (BACKEND_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, DATA TEXT NOT NULL, ADDED TIMESTAMPTZ, UNIQUE(COLONY_NAME, NAME))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createStorageObjectsTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `STORAGE_OBJECTS (OBJECT TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, BACKEND TEXT NOT NULL, REGISTERED BOOLEAN NOT NULL, ISSUED TIMESTAMPTZ)`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	indexStatement := `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `STORAGE_OBJECTS_INDEX1 ON ` + db.dbPrefix + `STORAGE_OBJECTS (COLONY_NAME, ISSUED)`
+	_, err = db.postgresql.Exec(indexStatement)
+	return err
+}
+
+func (db *PQDatabase) createFileRetentionPoliciesTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FILE_RETENTION_POLICIES (COLONY_NAME TEXT NOT NULL, LABEL TEXT NOT NULL, DATA TEXT NOT NULL, ADDED TIMESTAMPTZ, PRIMARY KEY (COLONY_NAME, LABEL))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) createFileLineageTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `FILE_LINEAGE (LINEAGE_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, PROCESS_ID TEXT NOT NULL, PROCESSGRAPH_ID TEXT NOT NULL, FILE_ID TEXT NOT NULL, DATA TEXT NOT NULL, ADDED TIMESTAMPTZ, UNIQUE(PROCESS_ID, FILE_ID))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	indexStatement := `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `FILE_LINEAGE_INDEX1 ON ` + db.dbPrefix + `FILE_LINEAGE (COLONY_NAME, FILE_ID)`
+	_, err = db.postgresql.Exec(indexStatement)
+	if err != nil {
+		return err
+	}
+
+	indexStatement = `CREATE INDEX IF NOT EXISTS ` + db.dbPrefix + `FILE_LINEAGE_INDEX2 ON ` + db.dbPrefix + `FILE_LINEAGE (COLONY_NAME, PROCESSGRAPH_ID)`
+	_, err = db.postgresql.Exec(indexStatement)
+	return err
+}
+
+func (db *PQDatabase) createLogSinksTable() error {
+	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `LOG_SINKS (LOGSINK_ID TEXT PRIMARY KEY NOT NULL, COLONY_NAME TEXT NOT NULL, NAME TEXT NOT NULL, DATA TEXT NOT NULL, ADDED TIMESTAMPTZ, UNIQUE(COLONY_NAME, NAME))`
+	_, err := db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) createBlueprintHistoryTable() error {
 	sqlStatement := `CREATE TABLE IF NOT EXISTS ` + db.dbPrefix + `BLUEPRINT_HISTORY (
 		ID TEXT PRIMARY KEY NOT NULL,
@@ -1000,6 +1224,11 @@ func (db *PQDatabase) Initialize() error {
 		return err
 	}
 
+	err = db.createCronRunsTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.createBlueprintDefinitionsTable()
 	if err != nil {
 		return err
@@ -1020,6 +1249,41 @@ func (db *PQDatabase) Initialize() error {
 		return err
 	}
 
+	err = db.createWebhooksTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createWebhookDeliveriesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStorageBackendsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createStorageObjectsTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileRetentionPoliciesTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createFileLineageTable()
+	if err != nil {
+		return err
+	}
+
+	err = db.createLogSinksTable()
+	if err != nil {
+		return err
+	}
+
 	err = db.createProcessesIndex1()
 	if err != nil {
 		return err
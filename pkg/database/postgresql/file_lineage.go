@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	_ "github.com/lib/pq"
+)
+
+// Maximum number of rows added per statement, Postgres allows at most 65535 parameters in a statement
+const maxFileLineageBatchSize = 1000
+
+// AddFileLineages adds the lineage using one statement per batch. Lineage already recorded for the same
+// process and file is ignored, so recording the lineage of a reassigned process does not add duplicates.
+func (db *PQDatabase) AddFileLineages(lineages []*core.FileLineage) error {
+	for start := 0; start < len(lineages); start += maxFileLineageBatchSize {
+		end := start + maxFileLineageBatchSize
+		if end > len(lineages) {
+			end = len(lineages)
+		}
+
+		err := db.addFileLineageBatch(lineages[start:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) addFileLineageBatch(lineages []*core.FileLineage) error {
+	var values []string
+	var args []interface{}
+	for _, lineage := range lineages {
+		if lineage == nil {
+			return errors.New("File lineage is nil")
+		}
+
+		if lineage.Added.IsZero() {
+			lineage.Added = time.Now().UTC()
+		}
+
+		lineageJSON, err := lineage.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7))
+		args = append(args, core.GenerateRandomID(), lineage.ColonyName, lineage.ProcessID, lineage.ProcessGraphID, lineage.FileID, lineageJSON, lineage.Added)
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `FILE_LINEAGE (LINEAGE_ID, COLONY_NAME, PROCESS_ID, PROCESSGRAPH_ID, FILE_ID, DATA, ADDED) VALUES ` + strings.Join(values, ", ") + ` ON CONFLICT (PROCESS_ID, FILE_ID) DO NOTHING`
+	_, err := db.postgresql.Exec(sqlStatement, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseFileLineage(rows *sql.Rows) ([]*core.FileLineage, error) {
+	lineage := []*core.FileLineage{}
+
+	for rows.Next() {
+		var lineageID string
+		var colonyName string
+		var processID string
+		var processGraphID string
+		var fileID string
+		var data string
+		var added time.Time
+		if err := rows.Scan(&lineageID, &colonyName, &processID, &processGraphID, &fileID, &data, &added); err != nil {
+			return nil, err
+		}
+
+		l, err := core.ConvertJSONToFileLineage(data)
+		if err != nil {
+			return nil, err
+		}
+
+		lineage = append(lineage, l)
+	}
+
+	return lineage, nil
+}
+
+func (db *PQDatabase) getFileLineage(sqlStatement string, args ...interface{}) ([]*core.FileLineage, error) {
+	rows, err := db.postgresql.Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFileLineage(rows)
+}
+
+func (db *PQDatabase) GetFileLineageByProcessID(colonyName string, processID string) ([]*core.FileLineage, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILE_LINEAGE WHERE COLONY_NAME=$1 AND PROCESS_ID=$2 ORDER BY ADDED ASC`
+	return db.getFileLineage(sqlStatement, colonyName, processID)
+}
+
+func (db *PQDatabase) GetFileLineageByProcessGraphID(colonyName string, processGraphID string) ([]*core.FileLineage, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILE_LINEAGE WHERE COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 ORDER BY ADDED ASC`
+	return db.getFileLineage(sqlStatement, colonyName, processGraphID)
+}
+
+func (db *PQDatabase) GetFileLineageByFileID(colonyName string, fileID string) ([]*core.FileLineage, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILE_LINEAGE WHERE COLONY_NAME=$1 AND FILE_ID=$2 ORDER BY ADDED ASC`
+	return db.getFileLineage(sqlStatement, colonyName, fileID)
+}
+
+func (db *PQDatabase) RemoveFileLineageByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILE_LINEAGE WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// removeFileLineageByProcesses removes the lineage of the processes matching the condition, it must be called
+// before the processes are removed
+func (db *PQDatabase) removeFileLineageByProcesses(condition string, args ...interface{}) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILE_LINEAGE WHERE PROCESS_ID IN (SELECT PROCESS_ID FROM ` + db.dbPrefix + `PROCESSES WHERE ` + condition + `)`
+	_, err := db.postgresql.Exec(sqlStatement, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFileLineages(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(colony.Name)
+	process1 := core.CreateProcess(funcSpec)
+	process1.ProcessGraphID = core.GenerateRandomID()
+	process2 := core.CreateProcess(funcSpec)
+	process2.ProcessGraphID = process1.ProcessGraphID
+
+	input := &core.File{ID: core.GenerateRandomID(), Label: "/data", Name: "input", Checksum: "checksum1"}
+	output := &core.File{ID: core.GenerateRandomID(), Label: "/result", Name: "output", Checksum: "checksum2"}
+
+	lineage1 := core.CreateFileLineage(process1, core.LineageInput, input, "")
+	err = db.AddFileLineages([]*core.FileLineage{lineage1})
+	assert.Nil(t, err)
+
+	lineage2 := core.CreateFileLineage(process1, core.LineageOutput, output, "")
+	err = db.AddFileLineages([]*core.FileLineage{lineage2})
+	assert.Nil(t, err)
+
+	lineage3 := core.CreateFileLineage(process2, core.LineageInput, output, "")
+	err = db.AddFileLineages([]*core.FileLineage{lineage3})
+	assert.Nil(t, err)
+
+	err = db.AddFileLineages([]*core.FileLineage{nil})
+	assert.NotNil(t, err)
+
+	// Lineage already recorded for the same process and file is ignored, e.g. when a process is reassigned
+	err = db.AddFileLineages([]*core.FileLineage{core.CreateFileLineage(process1, core.LineageInput, input, ""), lineage2})
+	assert.Nil(t, err)
+
+	lineage, err := db.GetFileLineageByProcessID(colony.Name, process1.ID)
+	assert.Nil(t, err)
+	assert.True(t, core.IsFileLineageArraysEqual(lineage, []*core.FileLineage{lineage1, lineage2}))
+
+	lineage, err = db.GetFileLineageByProcessGraphID(colony.Name, process1.ProcessGraphID)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 3)
+
+	lineage, err = db.GetFileLineageByFileID(colony.Name, output.ID)
+	assert.Nil(t, err)
+	assert.True(t, core.IsFileLineageArraysEqual(lineage, []*core.FileLineage{lineage2, lineage3}))
+
+	lineage, err = db.GetFileLineageByFileID(colony.Name, "does_not_exists")
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 0)
+}
+
+func TestRemoveFileLineageByColonyName(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	process := core.CreateProcess(utils.CreateTestFunctionSpec(colony.Name))
+	file := &core.File{ID: core.GenerateRandomID(), Label: "/data", Name: "input"}
+	err = db.AddFileLineages([]*core.FileLineage{core.CreateFileLineage(process, core.LineageInput, file, "")})
+	assert.Nil(t, err)
+
+	err = db.RemoveFileLineageByColonyName(colony.Name)
+	assert.Nil(t, err)
+
+	lineage, err := db.GetFileLineageByProcessID(colony.Name, process.ID)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 0)
+}
+
+func TestRemoveFileLineageWithProcess(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	process1 := core.CreateProcess(utils.CreateTestFunctionSpec(colony.Name))
+	err = db.AddProcess(process1)
+	assert.Nil(t, err)
+	process2 := core.CreateProcess(utils.CreateTestFunctionSpec(colony.Name))
+	err = db.AddProcess(process2)
+	assert.Nil(t, err)
+
+	file := &core.File{ID: core.GenerateRandomID(), Label: "/data", Name: "input"}
+	err = db.AddFileLineages([]*core.FileLineage{core.CreateFileLineage(process1, core.LineageInput, file, ""), core.CreateFileLineage(process2, core.LineageInput, file, "")})
+	assert.Nil(t, err)
+
+	err = db.RemoveProcessByID(process1.ID)
+	assert.Nil(t, err)
+
+	lineage, err := db.GetFileLineageByFileID(colony.Name, file.ID)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 1)
+	assert.Equal(t, process2.ID, lineage[0].ProcessID)
+
+	err = db.RemoveAllProcessesByColonyName(colony.Name)
+	assert.Nil(t, err)
+
+	lineage, err = db.GetFileLineageByFileID(colony.Name, file.ID)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 0)
+}
@@ -0,0 +1,119 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	_ "github.com/lib/pq"
+)
+
+// SetFileRetentionPolicy adds a policy, or replaces the policy of the label if one exists
+func (db *PQDatabase) SetFileRetentionPolicy(policy *core.FileRetentionPolicy) error {
+	if policy == nil {
+		return errors.New("File retention policy is nil")
+	}
+
+	if policy.Added.IsZero() {
+		policy.Added = time.Now().UTC()
+	}
+
+	policyJSON, err := policy.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `FILE_RETENTION_POLICIES (COLONY_NAME, LABEL, DATA, ADDED) VALUES ($1, $2, $3, $4) ON CONFLICT (COLONY_NAME, LABEL) DO UPDATE SET DATA=$3, ADDED=$4`
+	_, err = db.postgresql.Exec(sqlStatement, policy.ColonyName, policy.Label, policyJSON, policy.Added)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseFileRetentionPolicies(rows *sql.Rows) ([]*core.FileRetentionPolicy, error) {
+	var policies []*core.FileRetentionPolicy
+
+	for rows.Next() {
+		var colonyName string
+		var label string
+		var data string
+		var added time.Time
+		if err := rows.Scan(&colonyName, &label, &data, &added); err != nil {
+			return nil, err
+		}
+
+		policy, err := core.ConvertJSONToFileRetentionPolicy(data)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func (db *PQDatabase) GetFileRetentionPolicyByLabel(colonyName string, label string) (*core.FileRetentionPolicy, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILE_RETENTION_POLICIES WHERE COLONY_NAME=$1 AND LABEL=$2`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, label)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	policies, err := db.parseFileRetentionPolicies(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	return policies[0], nil
+}
+
+func (db *PQDatabase) GetFileRetentionPoliciesByColonyName(colonyName string) ([]*core.FileRetentionPolicy, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILE_RETENTION_POLICIES WHERE COLONY_NAME=$1 ORDER BY LABEL ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseFileRetentionPolicies(rows)
+}
+
+func (db *PQDatabase) RemoveFileRetentionPolicyByLabel(colonyName string, label string) error {
+	policy, err := db.GetFileRetentionPolicyByLabel(colonyName, label)
+	if err != nil {
+		return err
+	}
+
+	if policy == nil {
+		return errors.New("File retention policy with label <" + label + "> does not exists in Colony with name <" + colonyName + ">")
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILE_RETENTION_POLICIES WHERE COLONY_NAME=$1 AND LABEL=$2`
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, label)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveFileRetentionPoliciesByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILE_RETENTION_POLICIES WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFileRetentionPolicy(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	policy := core.CreateFileRetentionPolicy(colony.Name, "/test", 5, 30)
+	err = db.SetFileRetentionPolicy(policy)
+	assert.Nil(t, err)
+
+	policyFromDB, err := db.GetFileRetentionPolicyByLabel(colony.Name, "/test")
+	assert.Nil(t, err)
+	assert.True(t, policy.Equals(policyFromDB))
+
+	policyFromDB, err = db.GetFileRetentionPolicyByLabel(colony.Name, "/does_not_exists")
+	assert.Nil(t, err)
+	assert.Nil(t, policyFromDB)
+
+	// Setting a policy again replaces it
+	policy2 := core.CreateFileRetentionPolicy(colony.Name, "/test", 2, 0)
+	err = db.SetFileRetentionPolicy(policy2)
+	assert.Nil(t, err)
+
+	policies, err := db.GetFileRetentionPoliciesByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+	assert.True(t, policy2.Equals(policies[0]))
+
+	err = db.SetFileRetentionPolicy(nil)
+	assert.NotNil(t, err)
+}
+
+func TestRemoveFileRetentionPolicy(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony1, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony1)
+	assert.Nil(t, err)
+
+	colony2, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony2)
+	assert.Nil(t, err)
+
+	err = db.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(colony1.Name, "/test1", 5, 0))
+	assert.Nil(t, err)
+	err = db.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(colony1.Name, "/test2", 5, 0))
+	assert.Nil(t, err)
+	err = db.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(colony2.Name, "/test1", 5, 0))
+	assert.Nil(t, err)
+
+	err = db.RemoveFileRetentionPolicyByLabel(colony1.Name, "/test1")
+	assert.Nil(t, err)
+
+	err = db.RemoveFileRetentionPolicyByLabel(colony1.Name, "/test1")
+	assert.NotNil(t, err)
+
+	policies, err := db.GetFileRetentionPoliciesByColonyName(colony1.Name)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+
+	err = db.RemoveFileRetentionPoliciesByColonyName(colony1.Name)
+	assert.Nil(t, err)
+
+	policies, err = db.GetFileRetentionPoliciesByColonyName(colony1.Name)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 0)
+
+	policies, err = db.GetFileRetentionPoliciesByColonyName(colony2.Name)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+}
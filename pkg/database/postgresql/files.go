@@ -2,15 +2,35 @@ package postgresql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/lib/pq"
 	_ "github.com/lib/pq"
 )
 
+// sqlExecer is implemented by both the database and transactions, so that writes can be part of a transaction
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
 func (db *PQDatabase) AddFile(file *core.File) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `FILES (FILE_ID, COLONY_NAME, LABEL, NAME, SIZE, SEQNR, CHECKSUM, CHECKSUM_ALG, ADDED, PROTOCOL, S3_SERVER, S3_PORT, S3_TLS, S3_ACCESSKEY, S3_SECRETKEY, S3_REGION, S3_ENCKEY, S3_ENCALG, S3_OBJ, S3_BUCKET) VALUES ($1, $2, $3, $4, $5, nextval('` + db.dbPrefix + `FILE_SEQ'), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`
-	_, err := db.postgresql.Exec(sqlStatement, file.ID, file.ColonyName, file.Label, file.Name, file.Size, file.Checksum, file.ChecksumAlg, time.Now(), file.Reference.Protocol, file.Reference.S3Object.Server, file.Reference.S3Object.Port, file.Reference.S3Object.TLS, file.Reference.S3Object.AccessKey, file.Reference.S3Object.SecretKey, file.Reference.S3Object.Region, file.Reference.S3Object.EncryptionKey, file.Reference.S3Object.EncryptionAlg, file.Reference.S3Object.Object, file.Reference.S3Object.Bucket)
+	return db.addFile(db.postgresql, file)
+}
+
+func (db *PQDatabase) addFile(execer sqlExecer, file *core.File) error {
+	chunksJSON := ""
+	if len(file.Chunks) > 0 {
+		chunksBytes, err := json.Marshal(file.Chunks)
+		if err != nil {
+			return err
+		}
+		chunksJSON = string(chunksBytes)
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `FILES (FILE_ID, COLONY_NAME, LABEL, NAME, SIZE, SEQNR, CHECKSUM, CHECKSUM_ALG, ADDED, PROTOCOL, S3_SERVER, S3_PORT, S3_TLS, S3_ACCESSKEY, S3_SECRETKEY, S3_REGION, S3_ENCKEY, S3_ENCALG, S3_OBJ, S3_BUCKET, BACKEND, CHUNKS, SHARED) VALUES ($1, $2, $3, $4, $5, nextval('` + db.dbPrefix + `FILE_SEQ'), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
+	_, err := execer.Exec(sqlStatement, file.ID, file.ColonyName, file.Label, file.Name, file.Size, file.Checksum, file.ChecksumAlg, time.Now(), file.Reference.Protocol, file.Reference.S3Object.Server, file.Reference.S3Object.Port, file.Reference.S3Object.TLS, file.Reference.S3Object.AccessKey, file.Reference.S3Object.SecretKey, file.Reference.S3Object.Region, file.Reference.S3Object.EncryptionKey, file.Reference.S3Object.EncryptionAlg, file.Reference.S3Object.Object, file.Reference.S3Object.Bucket, file.Reference.Backend, chunksJSON, file.Shared)
 	if err != nil {
 		return err
 	}
@@ -42,11 +62,21 @@ func (db *PQDatabase) parseFiles(rows *sql.Rows) ([]*core.File, error) {
 		var s3EncryptionAlg string
 		var s3Object string
 		var s3Bucket string
+		var backend sql.NullString
+		var chunksJSON sql.NullString
+		var shared sql.NullBool
 
-		if err := rows.Scan(&fileID, &colonyName, &label, &name, &size, &seqnr, &checksum, &checksumAlg, &added, &protocol, &s3Server, &s3Port, &s3TLS, &s3AccessKey, &s3SecretKey, &s3Region, &s3EncryptionKey, &s3EncryptionAlg, &s3Object, &s3Bucket); err != nil {
+		if err := rows.Scan(&fileID, &colonyName, &label, &name, &size, &seqnr, &checksum, &checksumAlg, &added, &protocol, &s3Server, &s3Port, &s3TLS, &s3AccessKey, &s3SecretKey, &s3Region, &s3EncryptionKey, &s3EncryptionAlg, &s3Object, &s3Bucket, &backend, &chunksJSON, &shared); err != nil {
 			return nil, err
 		}
 
+		var chunks []core.FileChunk
+		if chunksJSON.String != "" {
+			if err := json.Unmarshal([]byte(chunksJSON.String), &chunks); err != nil {
+				return nil, err
+			}
+		}
+
 		s3ObjectStruct := core.S3Object{
 			Server:        s3Server,
 			Port:          s3Port,
@@ -59,7 +89,7 @@ func (db *PQDatabase) parseFiles(rows *sql.Rows) ([]*core.File, error) {
 			Object:        s3Object,
 			Bucket:        s3Bucket,
 		}
-		ref := core.Reference{Protocol: "s3", S3Object: s3ObjectStruct}
+		ref := core.Reference{Protocol: protocol, Backend: backend.String, S3Object: s3ObjectStruct}
 		file := core.File{
 			ID:             fileID,
 			ColonyName:     colonyName,
@@ -70,6 +100,8 @@ func (db *PQDatabase) parseFiles(rows *sql.Rows) ([]*core.File, error) {
 			Checksum:       checksum,
 			ChecksumAlg:    checksumAlg,
 			Reference:      ref,
+			Chunks:         chunks,
+			Shared:         shared.Bool,
 			Added:          added}
 
 		files = append(files, &file)
@@ -182,41 +214,60 @@ func (db *PQDatabase) GetFilenamesByLabel(colonyName string, label string) ([]st
 	return filenames, nil
 }
 
-func (db *PQDatabase) GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error) {
-	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2`
-	rows, err := db.postgresql.Query(sqlStatement, colonyName, label)
+func (db *PQDatabase) GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error) {
+	if len(fileIDs) == 0 {
+		return []*core.File{}, nil
+	}
+
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND FILE_ID=ANY($2)`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, pq.Array(fileIDs))
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	files, err := db.parseFiles(rows)
+	return db.parseFiles(rows)
+}
+
+// GetLatestFilesByLabel returns the revision with the highest sequence number of every file with the label
+func (db *PQDatabase) GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error) {
+	sqlStatement := `SELECT DISTINCT ON (NAME) * FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2 ORDER BY NAME, SEQNR DESC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, label)
 	if err != nil {
 		return nil, err
 	}
 
-	// Keep file with the highest sequence number, remove duplicates with the same name
-	filemap := make(map[string]*core.File)
-	for _, file := range files {
-		if _, ok := filemap[file.Name]; !ok {
-			filemap[file.Name] = file
-		} else {
-			if filemap[file.Name].SequenceNumber < file.SequenceNumber {
-				filemap[file.Name] = file
-			}
-		}
+	defer rows.Close()
+
+	return db.parseFiles(rows)
+}
+
+func (db *PQDatabase) GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error) {
+	files, err := db.GetLatestFilesByLabel(colonyName, label)
+	if err != nil {
+		return nil, err
 	}
 
 	fileDataArr := []*core.FileData{}
-	for _, file := range filemap {
-		fileData := &core.FileData{Name: file.Name, Checksum: file.Checksum, Size: file.Size, S3Filename: file.Reference.S3Object.Object}
+	for _, file := range files {
+		fileData := &core.FileData{Name: file.Name, Checksum: file.Checksum, Size: file.Size, FileID: file.ID, Protocol: file.Reference.Protocol, Backend: file.Reference.Backend, Chunked: len(file.Chunks) > 0, Shared: file.Shared, S3Filename: file.Reference.S3Object.Object, EncryptionKey: file.Reference.S3Object.EncryptionKey, EncryptionAlg: file.Reference.S3Object.EncryptionAlg}
 		fileDataArr = append(fileDataArr, fileData)
 	}
 
 	return fileDataArr, nil
 }
 
+func (db *PQDatabase) UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string) error {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `FILES SET S3_ENCKEY=$1 WHERE COLONY_NAME=$2 AND FILE_ID=$3`
+	_, err := db.postgresql.Exec(sqlStatement, encryptionKey, colonyName, fileID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) RemoveFileByID(colonyName string, fileID string) error {
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND FILE_ID=$2`
 	_, err := db.postgresql.Exec(sqlStatement, colonyName, fileID)
@@ -228,8 +279,12 @@ func (db *PQDatabase) RemoveFileByID(colonyName string, fileID string) error {
 }
 
 func (db *PQDatabase) RemoveFileByName(colonyName string, label string, name string) error {
+	return db.removeFileByName(db.postgresql, colonyName, label, name)
+}
+
+func (db *PQDatabase) removeFileByName(execer sqlExecer, colonyName string, label string, name string) error {
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILES WHERE COLONY_NAME=$1 AND LABEL=$2 AND NAME=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, label, name)
+	_, err := execer.Exec(sqlStatement, colonyName, label, name)
 	if err != nil {
 		return err
 	}
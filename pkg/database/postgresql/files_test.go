@@ -32,6 +32,29 @@ func TestAddGetFile(t *testing.T) {
 	assert.True(t, file.Equals(fileFromDB))
 }
 
+func TestAddGetChunkedFile(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	file := utils.CreateTestFileWithID("test_id", "test_colonyid", time.Now())
+	file.Label = "/testlabel"
+	file.Size = 300
+	file.Chunks = []core.FileChunk{{Hash: "hash1", Size: 100}, {Hash: "hash2", Size: 200}}
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	fileFromDB, err := db.GetFileByID("test_colonyid", file.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, file.Chunks, fileFromDB.Chunks)
+
+	fileDataArr, err := db.GetFileDataByLabel("test_colonyid", "/testlabel")
+	assert.Nil(t, err)
+	assert.Len(t, fileDataArr, 1)
+	assert.True(t, fileDataArr[0].Chunked)
+}
+
 func TestGetFileByName(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
@@ -204,6 +227,46 @@ func TestGetFileDataByLabelMultipleRevisions(t *testing.T) {
 	assert.Len(t, fileDataArr, 1)
 }
 
+func TestGetFilesByIDsAndLatestFilesByLabel(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	now := time.Now()
+	file1 := utils.CreateTestFileWithID(core.GenerateRandomID(), "test_colonyid", now)
+	file1.Label = "/testdir"
+	file1.Name = "test_file.txt"
+	err = db.AddFile(file1)
+	assert.Nil(t, err)
+
+	file2 := utils.CreateTestFileWithID(core.GenerateRandomID(), "test_colonyid", now)
+	file2.Label = "/testdir"
+	file2.Name = "test_file.txt"
+	err = db.AddFile(file2)
+	assert.Nil(t, err)
+
+	file3 := utils.CreateTestFileWithID(core.GenerateRandomID(), "test_colonyid", now)
+	file3.Label = "/testdir"
+	file3.Name = "test_file2.txt"
+	err = db.AddFile(file3)
+	assert.Nil(t, err)
+
+	files, err := db.GetFilesByIDs("test_colonyid", []string{file1.ID, file3.ID, "invalid_id"})
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+
+	files, err = db.GetFilesByIDs("another_colonyid", []string{file1.ID})
+	assert.Nil(t, err)
+	assert.Len(t, files, 0)
+
+	files, err = db.GetLatestFilesByLabel("test_colonyid", "/testdir")
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, file2.ID, files[0].ID)
+	assert.Equal(t, file3.ID, files[1].ID)
+}
+
 func TestRemoveFileByID(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
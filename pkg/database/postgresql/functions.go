@@ -17,8 +17,8 @@ func (db *PQDatabase) AddFunction(function *core.Function) error {
 		argsJSON = string(argsBytes)
 	}
 
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `FUNCTIONS (FUNCTION_ID, EXECUTOR_NAME, EXECUTOR_TYPE, COLONY_NAME, FUNCNAME, DESCRIPTION, ARGS, COUNTER, MINWAITTIME, MAXWAITTIME, MINEXECTIME, MAXEXECTIME, AVGWAITTIME, AVGEXECTIME, LOCATION_NAME) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
-	_, err := db.postgresql.Exec(sqlStatement, function.FunctionID, function.ExecutorName, function.ExecutorType, function.ColonyName, function.FuncName, function.Description, argsJSON, function.Counter, function.MinWaitTime, function.MaxWaitTime, function.MinExecTime, function.MaxExecTime, function.AvgWaitTime, function.AvgExecTime, function.LocationName)
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `FUNCTIONS (FUNCTION_ID, EXECUTOR_NAME, EXECUTOR_TYPE, COLONY_NAME, FUNCNAME, DESCRIPTION, ARGS, COUNTER, MINWAITTIME, MAXWAITTIME, MINEXECTIME, MAXEXECTIME, AVGWAITTIME, AVGEXECTIME, LOCATION_NAME, REQUIRE_FUNC_REG) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	_, err := db.postgresql.Exec(sqlStatement, function.FunctionID, function.ExecutorName, function.ExecutorType, function.ColonyName, function.FuncName, function.Description, argsJSON, function.Counter, function.MinWaitTime, function.MaxWaitTime, function.MinExecTime, function.MaxExecTime, function.AvgWaitTime, function.AvgExecTime, function.LocationName, function.RequireFuncReg)
 	if err != nil {
 		return err
 	}
@@ -45,7 +45,8 @@ func (db *PQDatabase) parseFunctions(rows *sql.Rows) ([]*core.Function, error) {
 		var avgWaitTime float64
 		var avgExecTime float64
 		var locationName sql.NullString
-		if err := rows.Scan(&functionID, &executorID, &executorType, &colonyName, &name, &description, &argsJSON, &counter, &minWaitTime, &maxWaitTime, &minExecTime, &maxExecTime, &avgWaitTime, &avgExecTime, &locationName); err != nil {
+		var requireFuncReg sql.NullBool
+		if err := rows.Scan(&functionID, &executorID, &executorType, &colonyName, &name, &description, &argsJSON, &counter, &minWaitTime, &maxWaitTime, &minExecTime, &maxExecTime, &avgWaitTime, &avgExecTime, &locationName, &requireFuncReg); err != nil {
 			return nil, err
 		}
 
@@ -53,6 +54,7 @@ func (db *PQDatabase) parseFunctions(rows *sql.Rows) ([]*core.Function, error) {
 		if locationName.Valid {
 			function.LocationName = locationName.String
 		}
+		function.RequireFuncReg = requireFuncReg.Bool
 
 		if description.Valid {
 			function.Description = description.String
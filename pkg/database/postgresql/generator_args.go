@@ -2,13 +2,20 @@ package postgresql
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 )
 
+const generatorArgColumns = `GENERATORARG_ID, GENERATOR_ID, COLONY_NAME, ARG, ARG_KEY, ADDED`
+
 func (db *PQDatabase) AddGeneratorArg(generatorArg *core.GeneratorArg) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `GENERATORARGS (GENERATORARG_ID, GENERATOR_ID, COLONY_NAME, ARG) VALUES ($1, $2, $3, $4)`
-	_, err := db.postgresql.Exec(sqlStatement, generatorArg.ID, generatorArg.GeneratorID, generatorArg.ColonyName, generatorArg.Arg)
+	if generatorArg.Added.IsZero() {
+		generatorArg.Added = time.Now()
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `GENERATORARGS (` + generatorArgColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := db.postgresql.Exec(sqlStatement, generatorArg.ID, generatorArg.GeneratorID, generatorArg.ColonyName, generatorArg.Arg, generatorArg.Key, generatorArg.Added)
 	if err != nil {
 		return err
 	}
@@ -24,11 +31,13 @@ func (db *PQDatabase) parseGeneratorArgs(rows *sql.Rows) ([]*core.GeneratorArg,
 		var generatorID string
 		var colonyName string
 		var arg string
-		if err := rows.Scan(&generatorArgID, &generatorID, &colonyName, &arg); err != nil {
+		var key string
+		var added time.Time
+		if err := rows.Scan(&generatorArgID, &generatorID, &colonyName, &arg, &key, &added); err != nil {
 			return nil, err
 		}
 
-		generatorArg := &core.GeneratorArg{ID: generatorArgID, GeneratorID: generatorID, ColonyName: colonyName, Arg: arg}
+		generatorArg := &core.GeneratorArg{ID: generatorArgID, GeneratorID: generatorID, ColonyName: colonyName, Arg: arg, Key: key, Added: added}
 
 		generatorArgs = append(generatorArgs, generatorArg)
 	}
@@ -37,7 +46,7 @@ func (db *PQDatabase) parseGeneratorArgs(rows *sql.Rows) ([]*core.GeneratorArg,
 }
 
 func (db *PQDatabase) GetGeneratorArgs(generatorID string, count int) ([]*core.GeneratorArg, error) {
-	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 LIMIT $2`
+	sqlStatement := `SELECT ` + generatorArgColumns + ` FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 ORDER BY SEQ LIMIT $2`
 	rows, err := db.postgresql.Query(sqlStatement, generatorID, count)
 	if err != nil {
 		return nil, err
@@ -52,6 +61,60 @@ func (db *PQDatabase) GetGeneratorArgs(generatorID string, count int) ([]*core.G
 	return generatorArgs, nil
 }
 
+func (db *PQDatabase) GetGeneratorArgsByKey(generatorID string, key string, count int) ([]*core.GeneratorArg, error) {
+	sqlStatement := `SELECT ` + generatorArgColumns + ` FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 AND ARG_KEY=$2 ORDER BY SEQ LIMIT $3`
+	rows, err := db.postgresql.Query(sqlStatement, generatorID, key, count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	generatorArgs, err := db.parseGeneratorArgs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return generatorArgs, nil
+}
+
+// GetGeneratorArgsByKeyAndTime returns the args packed in [from, to) in insertion order
+func (db *PQDatabase) GetGeneratorArgsByKeyAndTime(generatorID string, key string, from time.Time, to time.Time) ([]*core.GeneratorArg, error) {
+	sqlStatement := `SELECT ` + generatorArgColumns + ` FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 AND ARG_KEY=$2 AND ADDED>=$3 AND ADDED<$4 ORDER BY SEQ`
+	rows, err := db.postgresql.Query(sqlStatement, generatorID, key, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	generatorArgs, err := db.parseGeneratorArgs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return generatorArgs, nil
+}
+
+// GetGeneratorArgGroups returns the pending args of a generator grouped by key, ordered by when the group was first packed
+func (db *PQDatabase) GetGeneratorArgGroups(generatorID string) ([]*core.GeneratorArgGroup, error) {
+	sqlStatement := `SELECT ARG_KEY, COUNT(*), SUM(OCTET_LENGTH(ARG)), MIN(ADDED) FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 GROUP BY ARG_KEY ORDER BY MIN(SEQ)`
+	rows, err := db.postgresql.Query(sqlStatement, generatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*core.GeneratorArgGroup
+	for rows.Next() {
+		group := &core.GeneratorArgGroup{}
+		if err := rows.Scan(&group.Key, &group.Count, &group.Size, &group.Oldest); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
 func (db *PQDatabase) CountGeneratorArgs(generatorID string) (int, error) {
 	sqlStatement := `SELECT COUNT(*) FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1`
 	rows, err := db.postgresql.Query(sqlStatement, generatorID)
@@ -80,6 +143,16 @@ func (db *PQDatabase) RemoveGeneratorArgByID(generatorArgsID string) error {
 	return nil
 }
 
+func (db *PQDatabase) RemoveGeneratorArgsAddedBefore(generatorID string, before time.Time) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1 AND ADDED<$2`
+	_, err := db.postgresql.Exec(sqlStatement, generatorID, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) RemoveAllGeneratorArgsByGeneratorID(generatorID string) error {
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `GENERATORARGS WHERE GENERATOR_ID=$1`
 	_, err := db.postgresql.Exec(sqlStatement, generatorID)
@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -20,9 +21,21 @@ func TestGeneratorArgClosedDB(t *testing.T) {
 	_, err = db.GetGeneratorArgs("invalid_id", 1)
 	assert.NotNil(t, err)
 
+	_, err = db.GetGeneratorArgsByKey("invalid_id", "invalid_key", 1)
+	assert.NotNil(t, err)
+
+	_, err = db.GetGeneratorArgsByKeyAndTime("invalid_id", "invalid_key", time.Time{}, time.Now())
+	assert.NotNil(t, err)
+
+	_, err = db.GetGeneratorArgGroups("invalid_id")
+	assert.NotNil(t, err)
+
 	_, err = db.CountGeneratorArgs("invalid_id")
 	assert.NotNil(t, err)
 
+	err = db.RemoveGeneratorArgsAddedBefore("invalid_id", time.Now())
+	assert.NotNil(t, err)
+
 	err = db.RemoveGeneratorArgByID("invalid_id")
 	assert.NotNil(t, err)
 
@@ -140,3 +153,50 @@ func TestRemoveGeneratorArgByColonyName(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, count, 0)
 }
+
+func TestGeneratorArgsByKey(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := core.GenerateRandomID()
+	generatorID := core.GenerateRandomID()
+
+	now := time.Now()
+	for i, key := range []string{"tenant1", "tenant2", "tenant1", "tenant1"} {
+		generatorArg := core.CreateGeneratorArg(generatorID, colonyName, "arg"+key)
+		generatorArg.Key = key
+		generatorArg.Added = now.Add(time.Duration(i) * time.Second)
+		err = db.AddGeneratorArg(generatorArg)
+		assert.Nil(t, err)
+	}
+
+	groups, err := db.GetGeneratorArgGroups(generatorID)
+	assert.Nil(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "tenant1", groups[0].Key)
+	assert.Equal(t, 3, groups[0].Count)
+	assert.Equal(t, 3*len("argtenant1"), groups[0].Size)
+	assert.Equal(t, now.Unix(), groups[0].Oldest.Unix())
+	assert.Equal(t, "tenant2", groups[1].Key)
+	assert.Equal(t, 1, groups[1].Count)
+
+	generatorArgs, err := db.GetGeneratorArgsByKey(generatorID, "tenant1", 2)
+	assert.Nil(t, err)
+	assert.Len(t, generatorArgs, 2)
+	assert.Equal(t, "tenant1", generatorArgs[0].Key)
+	assert.Equal(t, now.Unix(), generatorArgs[0].Added.Unix())
+	assert.Equal(t, now.Add(2*time.Second).Unix(), generatorArgs[1].Added.Unix())
+
+	generatorArgs, err = db.GetGeneratorArgsByKeyAndTime(generatorID, "tenant1", now.Add(time.Second), now.Add(3*time.Second))
+	assert.Nil(t, err)
+	assert.Len(t, generatorArgs, 1)
+
+	err = db.RemoveGeneratorArgsAddedBefore(generatorID, now.Add(2*time.Second))
+	assert.Nil(t, err)
+
+	count, err := db.CountGeneratorArgs(generatorID)
+	assert.Nil(t, err)
+	assert.Equal(t, count, 2)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/lib/pq"
 )
 
 func (db *PQDatabase) AddGenerator(generator *core.Generator) error {
@@ -18,8 +19,8 @@ func (db *PQDatabase) AddGenerator(generator *core.Generator) error {
 		return errors.New("Generator with name <" + generator.Name + "> in Colony <" + generator.ColonyName + "> already exists")
 	}
 
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `GENERATORS (GENERATOR_ID, COLONY_NAME, NAME, WORKFLOW_SPEC, TRIGGER, TIMEOUT, LASTRUN, FIRSTPACK, INITIATOR_ID, INITIATOR_NAME) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
-	_, err = db.postgresql.Exec(sqlStatement, generator.ID, generator.ColonyName, generator.Name, generator.WorkflowSpec, generator.Trigger, generator.Timeout, time.Time{}, time.Time{}, generator.InitiatorID, generator.InitiatorName)
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `GENERATORS (GENERATOR_ID, COLONY_NAME, NAME, WORKFLOW_SPEC, TRIGGER, TIMEOUT, LASTRUN, FIRSTPACK, INITIATOR_ID, INITIATOR_NAME, TRIGGER_SIZE, WINDOW_TYPE, WINDOW_SIZE, WINDOW_SLIDE, LAST_WINDOW_END, BATCH_FORMAT, OPEN_WINDOW_END, WINDOW_KEYS) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+	_, err = db.postgresql.Exec(sqlStatement, generator.ID, generator.ColonyName, generator.Name, generator.WorkflowSpec, generator.Trigger, generator.Timeout, time.Time{}, time.Time{}, generator.InitiatorID, generator.InitiatorName, generator.TriggerSize, generator.WindowType, generator.WindowSize, generator.WindowSlide, time.Time{}, generator.BatchFormat, time.Time{}, pq.Array([]string{}))
 	if err != nil {
 		return err
 	}
@@ -41,8 +42,16 @@ func (db *PQDatabase) parseGenerators(rows *sql.Rows) ([]*core.Generator, error)
 		var firstPack time.Time
 		var initiatorID string
 		var initiatorName string
-
-		if err := rows.Scan(&generatorID, &colonyName, &name, &workflowSpec, &trigger, &timeout, &lastRun, &firstPack, &initiatorID, &initiatorName); err != nil {
+		var triggerSize int
+		var windowType string
+		var windowSize int
+		var windowSlide int
+		var lastWindowEnd time.Time
+		var batchFormat string
+		var openWindowEnd time.Time
+		var windowKeys []string
+
+		if err := rows.Scan(&generatorID, &colonyName, &name, &workflowSpec, &trigger, &timeout, &lastRun, &firstPack, &initiatorID, &initiatorName, &triggerSize, &windowType, &windowSize, &windowSlide, &lastWindowEnd, &batchFormat, &openWindowEnd, pq.Array(&windowKeys)); err != nil {
 			return nil, err
 		}
 
@@ -50,6 +59,14 @@ func (db *PQDatabase) parseGenerators(rows *sql.Rows) ([]*core.Generator, error)
 
 		generator.InitiatorID = initiatorID
 		generator.InitiatorName = initiatorName
+		generator.TriggerSize = triggerSize
+		generator.WindowType = windowType
+		generator.WindowSize = windowSize
+		generator.WindowSlide = windowSlide
+		generator.LastWindowEnd = lastWindowEnd
+		generator.BatchFormat = batchFormat
+		generator.OpenWindowEnd = openWindowEnd
+		generator.WindowKeys = windowKeys
 
 		generators = append(generators, generator)
 	}
@@ -116,6 +133,27 @@ func (db *PQDatabase) SetGeneratorLastRun(generatorID string) error {
 	return nil
 }
 
+func (db *PQDatabase) SetGeneratorLastWindowEnd(generatorID string, windowEnd time.Time) error {
+	sqlStatement := `UPDATE  ` + db.dbPrefix + `GENERATORS SET LAST_WINDOW_END=$1, OPEN_WINDOW_END=$2, WINDOW_KEYS=$3 WHERE GENERATOR_ID=$4`
+	_, err := db.postgresql.Exec(sqlStatement, windowEnd, time.Time{}, pq.Array([]string{}), generatorID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetGeneratorWindowKeys records the keys that have been submitted for a window not yet submitted for all keys
+func (db *PQDatabase) SetGeneratorWindowKeys(generatorID string, windowEnd time.Time, keys []string) error {
+	sqlStatement := `UPDATE  ` + db.dbPrefix + `GENERATORS SET OPEN_WINDOW_END=$1, WINDOW_KEYS=$2 WHERE GENERATOR_ID=$3`
+	_, err := db.postgresql.Exec(sqlStatement, windowEnd, pq.Array(keys), generatorID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (db *PQDatabase) SetGeneratorFirstPack(generatorID string) error {
 	generator, err := db.GetGeneratorByID(generatorID)
 	if err != nil {
@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
@@ -25,6 +26,12 @@ func TestGeneratorClosedDB(t *testing.T) {
 	err = db.SetGeneratorFirstPack("invalid_id")
 	assert.NotNil(t, err)
 
+	err = db.SetGeneratorLastWindowEnd("invalid_id", time.Now())
+	assert.NotNil(t, err)
+
+	err = db.SetGeneratorWindowKeys("invalid_id", time.Now(), []string{"key1"})
+	assert.NotNil(t, err)
+
 	_, err = db.GetGeneratorByID("invalid_id")
 	assert.NotNil(t, err)
 
@@ -147,6 +154,48 @@ func TestSetGeneratorFirstPack(t *testing.T) {
 	assert.True(t, generatorFromDB.FirstPack.Unix() > 0)
 }
 
+func TestSetGeneratorLastWindowEnd(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	generator := utils.FakeGenerator(t, core.GenerateRandomID(), "test_initiator_id", "test_initiator_name")
+	generator.ID = core.GenerateRandomID()
+	generator.Trigger = -1
+	generator.WindowType = core.GeneratorWindowSliding
+	generator.WindowSize = 60
+	generator.WindowSlide = 10
+	generator.BatchFormat = core.GeneratorBatchStructured
+	err = db.AddGenerator(generator)
+	assert.Nil(t, err)
+
+	generatorFromDB, err := db.GetGeneratorByID(generator.ID)
+	assert.Nil(t, err)
+	assert.True(t, generator.Equals(generatorFromDB))
+	assert.True(t, generatorFromDB.LastWindowEnd.Unix() <= 0)
+
+	assert.Len(t, generatorFromDB.WindowKeys, 0)
+
+	windowEnd := time.Now().Truncate(time.Minute)
+	err = db.SetGeneratorWindowKeys(generator.ID, windowEnd, []string{"key1", "key2"})
+	assert.Nil(t, err)
+
+	generatorFromDB, err = db.GetGeneratorByID(generator.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, windowEnd.Unix(), generatorFromDB.OpenWindowEnd.Unix())
+	assert.Equal(t, []string{"key1", "key2"}, generatorFromDB.WindowKeys)
+	assert.True(t, generatorFromDB.LastWindowEnd.Unix() <= 0)
+
+	err = db.SetGeneratorLastWindowEnd(generator.ID, windowEnd)
+	assert.Nil(t, err)
+
+	generatorFromDB, err = db.GetGeneratorByID(generator.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, windowEnd.Unix(), generatorFromDB.LastWindowEnd.Unix())
+	assert.Len(t, generatorFromDB.WindowKeys, 0)
+}
+
 func TestFindGeneratorsByColonyName(t *testing.T) {
 	db, err := PrepareTests()
 	assert.Nil(t, err)
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	_ "github.com/lib/pq"
+)
+
+func (db *PQDatabase) AddLogSink(sink *core.LogSink) error {
+	if sink == nil {
+		return errors.New("Log sink is nil")
+	}
+
+	existingSink, err := db.GetLogSinkByName(sink.ColonyName, sink.Name)
+	if err != nil {
+		return err
+	}
+
+	if existingSink != nil {
+		return errors.New("Log sink with name <" + sink.Name + "> already exists in Colony with name <" + sink.ColonyName + ">")
+	}
+
+	if sink.Added.IsZero() {
+		sink.Added = time.Now().UTC()
+	}
+
+	sinkJSON, err := sink.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `LOG_SINKS (LOGSINK_ID, COLONY_NAME, NAME, DATA, ADDED) VALUES ($1, $2, $3, $4, $5)`
+	_, err = db.postgresql.Exec(sqlStatement, sink.ID, sink.ColonyName, sink.Name, sinkJSON, sink.Added)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseLogSinks(rows *sql.Rows) ([]*core.LogSink, error) {
+	var sinks []*core.LogSink
+
+	for rows.Next() {
+		var sinkID string
+		var colonyName string
+		var name string
+		var data string
+		var added time.Time
+		if err := rows.Scan(&sinkID, &colonyName, &name, &data, &added); err != nil {
+			return nil, err
+		}
+
+		sink, err := core.ConvertJSONToLogSink(data)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+func (db *PQDatabase) GetLogSinkByName(colonyName string, name string) (*core.LogSink, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOG_SINKS WHERE COLONY_NAME=$1 AND NAME=$2`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	sinks, err := db.parseLogSinks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	return sinks[0], nil
+}
+
+func (db *PQDatabase) GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOG_SINKS WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseLogSinks(rows)
+}
+
+func (db *PQDatabase) RemoveLogSinkByName(colonyName string, name string) error {
+	sink, err := db.GetLogSinkByName(colonyName, name)
+	if err != nil {
+		return err
+	}
+
+	if sink == nil {
+		return errors.New("Log sink with name <" + name + "> does not exists in Colony with name <" + colonyName + ">")
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `LOG_SINKS WHERE LOGSINK_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, sink.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveLogSinksByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `LOG_SINKS WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddLogSink(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	sink := utils.CreateTestLogSink(colony.Name, "test_sink")
+	err = db.AddLogSink(sink)
+	assert.Nil(t, err)
+
+	sinkFromDB, err := db.GetLogSinkByName(colony.Name, "test_sink")
+	assert.Nil(t, err)
+	assert.True(t, sink.Equals(sinkFromDB))
+
+	sinkFromDB, err = db.GetLogSinkByName(colony.Name, "does_not_exists")
+	assert.Nil(t, err)
+	assert.Nil(t, sinkFromDB)
+
+	err = db.AddLogSink(nil)
+	assert.NotNil(t, err)
+
+	// Names must be unique within a colony
+	err = db.AddLogSink(utils.CreateTestLogSink(colony.Name, "test_sink"))
+	assert.NotNil(t, err)
+}
+
+func TestGetLogSinksByColonyName(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	sink1 := utils.CreateTestLogSink(colony.Name, "test_sink1")
+	err = db.AddLogSink(sink1)
+	assert.Nil(t, err)
+
+	sink2 := utils.CreateTestLogSink(colony.Name, "test_sink2")
+	err = db.AddLogSink(sink2)
+	assert.Nil(t, err)
+
+	sinks, err := db.GetLogSinksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.True(t, core.IsLogSinkArraysEqual([]*core.LogSink{sink1, sink2}, sinks))
+
+	sinks, err = db.GetLogSinksByColonyName("does_not_exists")
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 0)
+}
+
+func TestRemoveLogSink(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	err = db.AddLogSink(utils.CreateTestLogSink(colony.Name, "test_sink1"))
+	assert.Nil(t, err)
+	err = db.AddLogSink(utils.CreateTestLogSink(colony.Name, "test_sink2"))
+	assert.Nil(t, err)
+
+	err = db.RemoveLogSinkByName(colony.Name, "test_sink1")
+	assert.Nil(t, err)
+
+	err = db.RemoveLogSinkByName(colony.Name, "test_sink1")
+	assert.NotNil(t, err)
+
+	sinks, err := db.GetLogSinksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 1)
+
+	err = db.RemoveColonyByName(colony.Name)
+	assert.Nil(t, err)
+
+	sinks, err = db.GetLogSinksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 0)
+}
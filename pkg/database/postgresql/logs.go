@@ -2,15 +2,35 @@ package postgresql
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/colonyos/colonies/pkg/core"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func (db *PQDatabase) AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error {
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := db.postgresql.Exec(sqlStatement, processID, colonyName, executorName, timestamp, msg, time.Now())
+	return db.AddStructuredLog(&core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: timestamp, Message: msg})
+}
+
+func (db *PQDatabase) AddStructuredLog(log *core.Log) error {
+	if log == nil {
+		return errors.New("Log is nil")
+	}
+
+	var fieldsJSON sql.NullString
+	if len(log.Fields) > 0 {
+		fieldsJSONBytes, err := json.Marshal(log.Fields)
+		if err != nil {
+			return err
+		}
+		fieldsJSON = sql.NullString{String: string(fieldsJSONBytes), Valid: true}
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `LOGS (PROCESS_ID, COLONY_NAME, EXECUTOR_NAME, TS, MSG, ADDED, PROCESSGRAPH_ID, LEVEL, STREAM, FIELDS) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := db.postgresql.Exec(sqlStatement, log.ProcessID, log.ColonyName, log.ExecutorName, log.Timestamp, log.Message, time.Now(), log.ProcessGraphID, log.Level, log.Stream, fieldsJSON)
 	if err != nil {
 		return err
 	}
@@ -38,10 +58,19 @@ func (db *PQDatabase) parseLogs(rows *sql.Rows) ([]*core.Log, error) {
 		var ts int64
 		var msg string
 		var added time.Time
-		if err := rows.Scan(&processID, &colonyName, &executorName, &ts, &msg, &added); err != nil {
+		var processGraphID sql.NullString
+		var level sql.NullString
+		var stream sql.NullString
+		var fieldsJSON sql.NullString
+		if err := rows.Scan(&processID, &colonyName, &executorName, &ts, &msg, &added, &processGraphID, &level, &stream, &fieldsJSON); err != nil {
 			return nil, err
 		}
-		log := &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Timestamp: ts, Message: msg}
+		log := &core.Log{ProcessID: processID, ProcessGraphID: processGraphID.String, ColonyName: colonyName, ExecutorName: executorName, Level: level.String, Stream: stream.String, Timestamp: ts, Message: msg}
+		if fieldsJSON.Valid && fieldsJSON.String != "" {
+			if err := json.Unmarshal([]byte(fieldsJSON.String), &log.Fields); err != nil {
+				return nil, err
+			}
+		}
 		logs = append(logs, log)
 	}
 
@@ -206,19 +235,95 @@ func (db *PQDatabase) SearchLogs(colonyName string, text string, days int, count
 
 	defer rows.Close()
 
-	var results []*core.Log
-	for rows.Next() {
-		var processID string
-		var colonyName string
-		var executorName string
-		var timestamp int64
-		var message string
-		var added time.Time
-		if err := rows.Scan(&processID, &colonyName, &executorName, &timestamp, &message, &added); err != nil {
+	return db.parseLogs(rows)
+}
+
+// GetLogs returns log entries matching filter in chronological order. If filter.Latest is set,
+// the most recent filter.Count entries are returned instead of the first ones.
+func (db *PQDatabase) GetLogs(filter *core.LogFilter) ([]*core.Log, error) {
+	if filter == nil {
+		return nil, errors.New("Log filter is nil")
+	}
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, condition+"$"+strconv.Itoa(len(args)))
+	}
+
+	if filter.ColonyName != "" {
+		addCondition("COLONY_NAME=", filter.ColonyName)
+	}
+	if filter.ProcessID != "" {
+		addCondition("PROCESS_ID=", filter.ProcessID)
+	}
+	if filter.ProcessGraphID != "" {
+		addCondition("PROCESSGRAPH_ID=", filter.ProcessGraphID)
+	}
+	if filter.ExecutorName != "" {
+		addCondition("EXECUTOR_NAME=", filter.ExecutorName)
+	}
+	if filter.Level != "" {
+		levels := core.LogLevelsFrom(filter.Level)
+		if levels == nil {
+			return nil, core.ValidateLogLevel(filter.Level)
+		}
+		args = append(args, pq.Array(levels))
+		conditions = append(conditions, "LEVEL=ANY($"+strconv.Itoa(len(args))+")")
+	}
+	if filter.Stream != "" {
+		addCondition("STREAM=", filter.Stream)
+	}
+	if len(filter.Fields) > 0 {
+		fieldsJSONBytes, err := json.Marshal(filter.Fields)
+		if err != nil {
 			return nil, err
 		}
-		results = append(results, &core.Log{ProcessID: processID, ColonyName: colonyName, ExecutorName: executorName, Message: message, Timestamp: timestamp})
+		args = append(args, string(fieldsJSONBytes))
+		conditions = append(conditions, "FIELDS @> $"+strconv.Itoa(len(args))+"::jsonb")
+	}
+	if filter.Since > 0 {
+		addCondition("TS>", filter.Since)
+	}
+	if filter.Until > 0 {
+		addCondition("TS<=", filter.Until)
+	}
+
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `LOGS`
+	for i, condition := range conditions {
+		if i == 0 {
+			sqlStatement += ` WHERE ` + condition
+		} else {
+			sqlStatement += ` AND ` + condition
+		}
+	}
+	if filter.Latest {
+		sqlStatement += ` ORDER BY TS DESC`
+	} else {
+		sqlStatement += ` ORDER BY TS ASC`
+	}
+	args = append(args, filter.Count)
+	sqlStatement += ` LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := db.postgresql.Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	logs, err := db.parseLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Latest {
+		// Reverse to get chronological order (oldest of the latest first)
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
 	}
 
-	return results, nil
+	return logs, nil
 }
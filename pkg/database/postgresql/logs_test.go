@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -169,3 +170,86 @@ func TestSearchLogs(t *testing.T) {
 		assert.Equal(t, log.ExecutorName, "test_executor_name1")
 	}
 }
+
+func TestAddStructuredLog(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	log := &core.Log{ProcessID: "test_processid", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor_name", Level: core.LogLevelWarn, Stream: core.LogStreamStderr, Fields: map[string]string{"step": "1"}, Message: "1", Timestamp: time.Now().UTC().UnixNano()}
+	err = db.AddStructuredLog(log)
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid", "test_colony", "test_executor_name", time.Now().UTC().UnixNano(), "2")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogsByProcessID("test_processid", 100)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	assert.True(t, logs[0].Equals(log))
+	assert.Equal(t, "", logs[1].Level)
+	assert.Nil(t, logs[1].Fields)
+
+	err = db.AddStructuredLog(nil)
+	assert.NotNil(t, err)
+}
+
+func TestGetLogsWithFilter(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid1", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor_name1", Level: core.LogLevelDebug, Stream: core.LogStreamStdout, Fields: map[string]string{"step": "1"}, Message: "1", Timestamp: 1})
+	assert.Nil(t, err)
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid1", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor_name1", Level: core.LogLevelInfo, Stream: core.LogStreamStdout, Fields: map[string]string{"step": "2", "host": "a"}, Message: "2", Timestamp: 2})
+	assert.Nil(t, err)
+	err = db.AddStructuredLog(&core.Log{ProcessID: "test_processid2", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor_name2", Level: core.LogLevelError, Stream: core.LogStreamStderr, Fields: map[string]string{"step": "2"}, Message: "3", Timestamp: 3})
+	assert.Nil(t, err)
+	err = db.AddLog("test_processid3", "test_colony2", "test_executor_name3", 4, "4")
+	assert.Nil(t, err)
+
+	logs, err := db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, "1", logs[0].Message)
+
+	logs, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Level: core.LogLevelInfo, Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Stream: core.LogStreamStderr, Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "3", logs[0].Message)
+
+	logs, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Fields: map[string]string{"step": "2"}, Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Fields: map[string]string{"step": "2", "host": "a"}, Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "2", logs[0].Message)
+
+	logs, err = db.GetLogs(&core.LogFilter{ProcessGraphID: "test_processgraphid", ExecutorName: "test_executor_name1", Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = db.GetLogs(&core.LogFilter{ProcessID: "test_processid1", Since: 1, Until: 2, Count: 100})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "2", logs[0].Message)
+
+	logs, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Latest: true, Count: 2})
+	assert.Nil(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "2", logs[0].Message)
+	assert.Equal(t, "3", logs[1].Message)
+
+	_, err = db.GetLogs(&core.LogFilter{ColonyName: "test_colony", Level: "verbose", Count: 100})
+	assert.NotNil(t, err)
+
+	_, err = db.GetLogs(nil)
+	assert.NotNil(t, err)
+}
@@ -579,8 +579,13 @@ func (db *PQDatabase) FindCandidatesByName(colonyName string, executorName strin
 }
 
 func (db *PQDatabase) RemoveProcessByID(processID string) error {
+	err := db.removeFileLineageByProcesses("PROCESS_ID=$1", processID)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE PROCESS_ID=$1`
-	_, err := db.postgresql.Exec(sqlStatement, processID)
+	_, err = db.postgresql.Exec(sqlStatement, processID)
 	if err != nil {
 		return err
 	}
@@ -595,12 +600,18 @@ func (db *PQDatabase) RemoveProcessByID(processID string) error {
 }
 
 func (db *PQDatabase) RemoveAllProcesses() error {
-	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES`
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `FILE_LINEAGE`
 	_, err := db.postgresql.Exec(sqlStatement)
 	if err != nil {
 		return err
 	}
 
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSES`
+	_, err = db.postgresql.Exec(sqlStatement)
+	if err != nil {
+		return err
+	}
+
 	err = db.RemoveAllAttributes()
 	if err != nil {
 		return err
@@ -610,8 +621,13 @@ func (db *PQDatabase) RemoveAllProcesses() error {
 }
 
 func (db *PQDatabase) RemoveAllWaitingProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3", colonyName, "", core.WAITING)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "", core.WAITING)
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", core.WAITING)
 	if err != nil {
 		return err
 	}
@@ -625,8 +641,13 @@ func (db *PQDatabase) RemoveAllWaitingProcessesByColonyName(colonyName string) e
 }
 
 func (db *PQDatabase) RemoveAllRunningProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3", colonyName, "", core.RUNNING)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "", core.RUNNING)
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", core.RUNNING)
 	if err != nil {
 		return err
 	}
@@ -640,8 +661,13 @@ func (db *PQDatabase) RemoveAllRunningProcessesByColonyName(colonyName string) e
 }
 
 func (db *PQDatabase) RemoveAllSuccessfulProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3", colonyName, "", core.SUCCESS)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "", core.SUCCESS)
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", core.SUCCESS)
 	if err != nil {
 		return err
 	}
@@ -655,8 +681,13 @@ func (db *PQDatabase) RemoveAllSuccessfulProcessesByColonyName(colonyName string
 }
 
 func (db *PQDatabase) RemoveAllFailedProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3", colonyName, "", core.FAILED)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "", core.FAILED)
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", core.FAILED)
 	if err != nil {
 		return err
 	}
@@ -670,8 +701,13 @@ func (db *PQDatabase) RemoveAllFailedProcessesByColonyName(colonyName string) er
 }
 
 func (db *PQDatabase) RemoveAllProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2", colonyName, "")
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "")
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "")
 	if err != nil {
 		return err
 	}
@@ -685,8 +721,13 @@ func (db *PQDatabase) RemoveAllProcessesByColonyName(colonyName string) error {
 }
 
 func (db *PQDatabase) RemoveAllProcessesByProcessGraphID(processGraphID string) error {
+	err := db.removeFileLineageByProcesses("PROCESSGRAPH_ID=$1", processGraphID)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE PROCESSGRAPH_ID=$1`
-	_, err := db.postgresql.Exec(sqlStatement, processGraphID)
+	_, err = db.postgresql.Exec(sqlStatement, processGraphID)
 	if err != nil {
 		return err
 	}
@@ -705,6 +746,11 @@ func (db *PQDatabase) RemoveAllProcessesInProcessGraphsByColonyName(colonyName s
 		return err
 	}
 
+	err = db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2", colonyName, "")
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2`
 	_, err = db.postgresql.Exec(sqlStatement, colonyName, "")
 	if err != nil {
@@ -720,6 +766,11 @@ func (db *PQDatabase) RemoveAllProcessesInProcessGraphsByColonyNameWithState(col
 		return err
 	}
 
+	err = db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2 AND STATE=$3", colonyName, "", state)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID!=$2 AND STATE=$3`
 	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", state)
 	if err != nil {
@@ -996,6 +1047,16 @@ func (db *PQDatabase) SelectAndAssign(colonyName string, executorID string, exec
 }
 
 func (db *PQDatabase) Unassign(process *core.Process) error {
+	return db.unassign(process, process.Retries+1)
+}
+
+// Release moves a running process back to the queue like Unassign, but does not count a retry since the
+// executor released the process on purpose, e.g. when shutting down
+func (db *PQDatabase) Release(process *core.Process) error {
+	return db.unassign(process, process.Retries)
+}
+
+func (db *PQDatabase) unassign(process *core.Process, retries int) error {
 	endTime := time.Now()
 
 	maxWaitTime := process.FunctionSpec.MaxWaitTime
@@ -1003,13 +1064,13 @@ func (db *PQDatabase) Unassign(process *core.Process) error {
 		deadline := time.Now().Add(time.Duration(maxWaitTime) * time.Second)
 
 		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, END_TIME=$1, STATE=$2, RETRIES=$3, ASSIGNED_EXECUTOR_ID=$4, WAIT_DEADLINE=$5 WHERE PROCESS_ID=$6`
-		_, err := db.postgresql.Exec(sqlStatement, endTime, core.WAITING, process.Retries+1, "", deadline, process.ID)
+		_, err := db.postgresql.Exec(sqlStatement, endTime, core.WAITING, retries, "", deadline, process.ID)
 		if err != nil {
 			return err
 		}
 	} else {
 		sqlStatement := `UPDATE ` + db.dbPrefix + `PROCESSES SET IS_ASSIGNED=FALSE, END_TIME=$1, STATE=$2, RETRIES=$3, ASSIGNED_EXECUTOR_ID=$4 WHERE PROCESS_ID=$5`
-		_, err := db.postgresql.Exec(sqlStatement, endTime, core.WAITING, process.Retries+1, "", process.ID)
+		_, err := db.postgresql.Exec(sqlStatement, endTime, core.WAITING, retries, "", process.ID)
 		if err != nil {
 			return err
 		}
@@ -1285,8 +1346,13 @@ func (db *PQDatabase) FindCancelledProcesses(colonyName string, executorType str
 }
 
 func (db *PQDatabase) RemoveAllCancelledProcessesByColonyName(colonyName string) error {
+	err := db.removeFileLineageByProcesses("TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3", colonyName, "", core.CANCELLED)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement := `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE TARGET_COLONY_NAME=$1 AND PROCESSGRAPH_ID=$2 AND STATE=$3`
-	_, err := db.postgresql.Exec(sqlStatement, colonyName, "", core.CANCELLED)
+	_, err = db.postgresql.Exec(sqlStatement, colonyName, "", core.CANCELLED)
 	if err != nil {
 		return err
 	}
@@ -832,6 +832,36 @@ func TestAssign(t *testing.T) {
 	assert.Nil(t, err)
 	assert.False(t, processFromDB.IsAssigned)
 	assert.False(t, int64(processFromDB.EndTime.Sub(processFromDB.StartTime)) < 0)
+	assert.Equal(t, 1, processFromDB.Retries)
+}
+
+func TestRelease(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colony := core.CreateColony(core.GenerateRandomID(), "test_colony_name")
+
+	executor := utils.CreateTestExecutor(colony.Name)
+	err = db.AddExecutor(executor)
+	assert.Nil(t, err)
+
+	process := utils.CreateTestProcess(colony.Name)
+	err = db.AddProcess(process)
+	assert.Nil(t, err)
+
+	err = db.Assign(executor.ID, process)
+	assert.Nil(t, err)
+
+	err = db.Release(process)
+	assert.Nil(t, err)
+
+	processFromDB, err := db.GetProcessByID(process.ID)
+	assert.Nil(t, err)
+	assert.False(t, processFromDB.IsAssigned)
+	assert.Equal(t, core.WAITING, processFromDB.State)
+	assert.Equal(t, 0, processFromDB.Retries) // Releasing a process does not count as a retry
 }
 
 func TestMarkSuccessful(t *testing.T) {
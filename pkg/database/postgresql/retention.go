@@ -30,6 +30,11 @@ func (db *PQDatabase) ApplyRetentionPolicy(retentionPeriod int64) error {
 		return err
 	}
 
+	err = db.removeFileLineageByProcesses("SUBMISSION_TIME<$1 AND STATE=$2", timestamp, core.SUCCESS)
+	if err != nil {
+		return err
+	}
+
 	sqlStatement = `DELETE FROM ` + db.dbPrefix + `PROCESSES WHERE SUBMISSION_TIME<$1 AND STATE=$2`
 	_, err = db.postgresql.Exec(sqlStatement, timestamp, core.SUCCESS)
 	if err != nil {
@@ -42,5 +47,11 @@ func (db *PQDatabase) ApplyRetentionPolicy(retentionPeriod int64) error {
 		return err
 	}
 
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `WEBHOOK_DELIVERIES WHERE ADDED<$1`
+	_, err = db.postgresql.Exec(sqlStatement, timestamp)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -20,7 +21,7 @@ func (db *PQDatabase) CreateSnapshot(colonyName string, label string, name strin
 
 	label = strings.TrimSuffix(label, "/")
 
-	allLabels, err := db.GetFileLabelsByName(colonyName, label, true)
+	files, err := db.getLatestFiles(colonyName, label)
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +30,28 @@ func (db *PQDatabase) CreateSnapshot(colonyName string, label string, name strin
 	now := time.Now().UTC()
 
 	var fileIDs []string
+	for _, file := range files {
+		fileIDs = append(fileIDs, file.ID)
+	}
+
+	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `SNAPSHOTS (SNAPSHOT_ID, COLONY_NAME, LABEL, NAME, FILE_IDS, ADDED) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = db.postgresql.Exec(sqlStatement, snapshotID, colonyName, label, colonyName+":"+name, pq.Array(fileIDs), now)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &core.Snapshot{ID: snapshotID, ColonyName: colonyName, Label: label, Name: name, FileIDs: fileIDs, Added: now}
+
+	return snapshot, nil
+}
+
+// getLatestFiles returns the latest revision of all files with the given label or a sub label of it
+func (db *PQDatabase) getLatestFiles(colonyName string, label string) ([]*core.File, error) {
+	allLabels, err := db.GetFileLabelsByName(colonyName, label, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*core.File
 	for _, l := range allLabels {
 		filenames, err := db.GetFilenamesByLabel(colonyName, l.Name)
 		if err != nil {
@@ -43,19 +66,219 @@ func (db *PQDatabase) CreateSnapshot(colonyName string, label string, name strin
 			if len(file) != 1 {
 				return nil, errors.New("failed to get file info, len>1")
 			}
-			fileIDs = append(fileIDs, file[0].ID)
+			files = append(files, file[0])
 		}
+	}
+
+	return files, nil
+}
 
+func (db *PQDatabase) getSnapshotFiles(snapshot *core.Snapshot) ([]*core.File, error) {
+	var files []*core.File
+	for _, fileID := range snapshot.FileIDs {
+		file, err := db.GetFileByID(snapshot.ColonyName, fileID)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			return nil, errors.New("File with Id <" + fileID + "> in snapshot <" + snapshot.Name + "> no longer exists")
+		}
+		files = append(files, file)
 	}
 
-	sqlStatement := `INSERT INTO  ` + db.dbPrefix + `SNAPSHOTS (SNAPSHOT_ID, COLONY_NAME, LABEL, NAME, FILE_IDS, ADDED) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = db.postgresql.Exec(sqlStatement, snapshotID, colonyName, label, colonyName+":"+name, pq.Array(fileIDs), now)
+	return files, nil
+}
+
+// copyFile adds a new revision of file with the given label, referencing the same object as file. Both
+// files are marked as shared, since the object is now referenced by more than one file.
+func (db *PQDatabase) copyFile(tx *sql.Tx, file *core.File, label string) (*core.File, error) {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `FILES SET SHARED=TRUE WHERE COLONY_NAME=$1 AND FILE_ID=$2`
+	_, err := tx.Exec(sqlStatement, file.ColonyName, file.ID)
 	if err != nil {
 		return nil, err
 	}
-	snapshot := &core.Snapshot{ID: snapshotID, ColonyName: colonyName, Label: label, Name: name, FileIDs: fileIDs, Added: now}
 
-	return snapshot, nil
+	fileCopy := *file
+	fileCopy.ID = core.GenerateRandomID()
+	fileCopy.Label = label
+	fileCopy.Shared = true
+	err = db.addFile(tx, &fileCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileCopy, nil
+}
+
+func (db *PQDatabase) DiffSnapshots(colonyName string, fromSnapshotID string, toSnapshotID string) (*core.SnapshotDiff, error) {
+	fromSnapshot, err := db.GetSnapshotByID(colonyName, fromSnapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	toSnapshot, err := db.GetSnapshotByID(colonyName, toSnapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFiles, err := db.getSnapshotFiles(fromSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	toFiles, err := db.getSnapshotFiles(toSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.DiffFiles(fromSnapshot.Label, fromFiles, toSnapshot.Label, toFiles), nil
+}
+
+func (db *PQDatabase) RestoreSnapshot(colonyName string, snapshotID string, prune bool) (*core.SnapshotDiff, error) {
+	snapshot, err := db.GetSnapshotByID(colonyName, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotFiles, err := db.getSnapshotFiles(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFiles, err := db.getLatestFiles(colonyName, snapshot.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := core.DiffFiles(snapshot.Label, currentFiles, snapshot.Label, snapshotFiles)
+
+	currentFileMap := make(map[string]*core.File)
+	for _, file := range currentFiles {
+		currentFileMap[core.SnapshotFilePath(snapshot.Label, file)] = file
+	}
+
+	if prune {
+		snapshots, err := db.GetSnapshotsByColonyName(colonyName)
+		if err != nil {
+			return nil, err
+		}
+
+		referenced := make(map[string]string)
+		for _, s := range snapshots {
+			for _, fileID := range s.FileIDs {
+				referenced[fileID] = s.Name
+			}
+		}
+
+		// Files referenced by snapshots are never pruned, since the snapshots would no longer be restorable
+		for _, entry := range diff.Removed {
+			file := currentFileMap[entry.Path]
+			revisions, err := db.GetFileByName(colonyName, file.Label, file.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, revision := range revisions {
+				if snapshotName, ok := referenced[revision.ID]; ok {
+					return nil, fmt.Errorf("%w: failed to prune file <%s>, it is referenced by snapshot <%s>", core.ErrSnapshotConflict, entry.Path, snapshotName)
+				}
+			}
+		}
+	} else {
+		diff.Removed = []*core.SnapshotDiffEntry{}
+	}
+
+	snapshotFileMap := make(map[string]*core.File)
+	for _, file := range snapshotFiles {
+		snapshotFileMap[core.SnapshotFilePath(snapshot.Label, file)] = file
+	}
+
+	// All files are pruned and restored in a single transaction, a failed restore leaves the label unchanged
+	tx, err := db.postgresql.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range diff.Removed {
+		file := currentFileMap[entry.Path]
+		err = db.removeFileByName(tx, colonyName, file.Label, file.Name)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	for _, entries := range [][]*core.SnapshotDiffEntry{diff.Added, diff.Changed} {
+		for _, entry := range entries {
+			file := snapshotFileMap[entry.Path]
+			restoredFile, err := db.copyFile(tx, file, file.Label)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			entry.ToFileID = restoredFile.ID
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+func (db *PQDatabase) CloneSnapshot(colonyName string, snapshotID string, label string) (*core.SnapshotDiff, error) {
+	snapshot, err := db.GetSnapshotByID(colonyName, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	label = strings.TrimSuffix(label, "/")
+	if !strings.HasPrefix(label, "/") {
+		return nil, fmt.Errorf("%w: invalid label <%s>, label must start with /", core.ErrSnapshotConflict, label)
+	}
+
+	count, err := db.CountFilesWithLabel(colonyName, label)
+	if err != nil {
+		return nil, err
+	}
+
+	subLabels, err := db.GetFileLabelsByName(colonyName, label+"/", false)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 || len(subLabels) > 0 {
+		return nil, fmt.Errorf("%w: failed to clone snapshot <%s>, label <%s> is not empty", core.ErrSnapshotConflict, snapshot.Name, label)
+	}
+
+	snapshotFiles, err := db.getSnapshotFiles(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// All files are cloned in a single transaction, a failed clone leaves the label empty
+	tx, err := db.postgresql.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	var clonedFiles []*core.File
+	for _, file := range snapshotFiles {
+		clonedFile, err := db.copyFile(tx, file, label+strings.TrimPrefix(file.Label, snapshot.Label))
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		clonedFiles = append(clonedFiles, clonedFile)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return core.DiffFiles(label, []*core.File{}, label, clonedFiles), nil
 }
 
 func (db *PQDatabase) parseSnapshots(rows *sql.Rows) ([]*core.Snapshot, error) {
@@ -1,9 +1,11 @@
 package postgresql
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -288,3 +290,262 @@ func TestRemoveSnapshotsByColonyName(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, snapshotsFromDB, 2)
 }
+
+func TestDiffSnapshots(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := "test_colony"
+	now := time.Now()
+
+	file := utils.CreateTestFileWithID("test_file1_id", colonyName, now)
+	file.Label = "/data"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id"
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot1, err := db.CreateSnapshot(colonyName, "/data", "snapshot1")
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id_rev2"
+	file.Name = "file2"
+	file.Checksum = "checksum2_rev2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file3_id"
+	file.Label = "/data/sub"
+	file.Name = "file3"
+	file.Checksum = "checksum3"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	err = db.RemoveFileByName(colonyName, "/data", "file1")
+	assert.Nil(t, err)
+
+	snapshot2, err := db.CreateSnapshot(colonyName, "/data", "snapshot2")
+	assert.Nil(t, err)
+
+	diff, err := db.DiffSnapshots(colonyName, snapshot1.ID, snapshot2.ID)
+	assert.NotNil(t, err) // file1 has been removed, snapshot1 is broken
+	assert.Nil(t, diff)
+
+	snapshot3, err := db.CreateSnapshot(colonyName, "/data", "snapshot3")
+	assert.Nil(t, err)
+
+	diff, err = db.DiffSnapshots(colonyName, snapshot2.ID, snapshot3.ID)
+	assert.Nil(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	_, err = db.DiffSnapshots(colonyName, snapshot2.ID, "invalid_id")
+	assert.NotNil(t, err)
+}
+
+func TestDiffSnapshotsChanges(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := "test_colony"
+	now := time.Now()
+
+	file := utils.CreateTestFileWithID("test_file1_id", colonyName, now)
+	file.Label = "/data"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id"
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot1, err := db.CreateSnapshot(colonyName, "/data", "snapshot1")
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id_rev2"
+	file.Name = "file2"
+	file.Checksum = "checksum2_rev2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file3_id"
+	file.Label = "/data/sub"
+	file.Name = "file3"
+	file.Checksum = "checksum3"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot2, err := db.CreateSnapshot(colonyName, "/data", "snapshot2")
+	assert.Nil(t, err)
+
+	diff, err := db.DiffSnapshots(colonyName, snapshot1.ID, snapshot2.ID)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "/sub/file3", diff.Added[0].Path)
+	assert.Len(t, diff.Removed, 0)
+	assert.Len(t, diff.Changed, 1)
+	assert.Equal(t, "/file2", diff.Changed[0].Path)
+	assert.Equal(t, "test_file2_id", diff.Changed[0].FromFileID)
+	assert.Equal(t, "test_file2_id_rev2", diff.Changed[0].ToFileID)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := "test_colony"
+	now := time.Now()
+
+	file := utils.CreateTestFileWithID("test_file1_id", colonyName, now)
+	file.Label = "/data"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot, err := db.CreateSnapshot(colonyName, "/data", "snapshot1")
+	assert.Nil(t, err)
+
+	file.ID = "test_file1_id_rev2"
+	file.Checksum = "checksum1_rev2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id"
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	diff, err := db.RestoreSnapshot(colonyName, snapshot.ID, false)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Changed, 1)
+	assert.Len(t, diff.Removed, 0) // file2 is kept, since prune is false
+
+	files, err := db.GetLatestFileByName(colonyName, "/data", "file1")
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "checksum1", files[0].Checksum)
+	assert.Equal(t, diff.Changed[0].ToFileID, files[0].ID)
+	assert.True(t, files[0].Shared)
+
+	source, err := db.GetFileByID(colonyName, "test_file1_id")
+	assert.Nil(t, err)
+	assert.True(t, source.Shared)
+
+	filenames, err := db.GetFilenamesByLabel(colonyName, "/data")
+	assert.Nil(t, err)
+	assert.Len(t, filenames, 2)
+
+	diff, err = db.RestoreSnapshot(colonyName, snapshot.ID, true)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Changed, 0)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "/file2", diff.Removed[0].Path)
+
+	filenames, err = db.GetFilenamesByLabel(colonyName, "/data")
+	assert.Nil(t, err)
+	assert.Len(t, filenames, 1)
+}
+
+func TestRestoreSnapshotPruneReferenced(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := "test_colony"
+	now := time.Now()
+
+	file := utils.CreateTestFileWithID("test_file1_id", colonyName, now)
+	file.Label = "/data"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot1, err := db.CreateSnapshot(colonyName, "/data", "snapshot1")
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id"
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	_, err = db.CreateSnapshot(colonyName, "/data", "snapshot2")
+	assert.Nil(t, err)
+
+	_, err = db.RestoreSnapshot(colonyName, snapshot1.ID, true)
+	assert.NotNil(t, err) // file2 is referenced by snapshot2
+	assert.True(t, errors.Is(err, core.ErrSnapshotConflict))
+
+	filenames, err := db.GetFilenamesByLabel(colonyName, "/data")
+	assert.Nil(t, err)
+	assert.Len(t, filenames, 2)
+}
+
+func TestCloneSnapshot(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+
+	defer db.Close()
+
+	colonyName := "test_colony"
+	now := time.Now()
+
+	file := utils.CreateTestFileWithID("test_file1_id", colonyName, now)
+	file.Label = "/data"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	file.ID = "test_file2_id"
+	file.Label = "/data/sub"
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	err = db.AddFile(file)
+	assert.Nil(t, err)
+
+	snapshot, err := db.CreateSnapshot(colonyName, "/data", "snapshot1")
+	assert.Nil(t, err)
+
+	diff, err := db.CloneSnapshot(colonyName, snapshot.ID, "/clone/")
+	assert.Nil(t, err)
+	assert.Len(t, diff.Added, 2)
+	assert.Equal(t, "/file1", diff.Added[0].Path)
+	assert.Equal(t, "/sub/file2", diff.Added[1].Path)
+
+	files, err := db.GetLatestFileByName(colonyName, "/clone/sub", "file2")
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "checksum2", files[0].Checksum)
+	assert.True(t, files[0].Shared)
+
+	_, err = db.CloneSnapshot(colonyName, snapshot.ID, "/clone")
+	assert.NotNil(t, err) // label is not empty
+	assert.True(t, errors.Is(err, core.ErrSnapshotConflict))
+
+	_, err = db.CloneSnapshot(colonyName, snapshot.ID, "clone2")
+	assert.NotNil(t, err) // label must start with /
+	assert.True(t, errors.Is(err, core.ErrSnapshotConflict))
+
+	_, err = db.CloneSnapshot(colonyName, "invalid_id", "/clone2")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,223 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/secrets"
+	_ "github.com/lib/pq"
+)
+
+func (db *PQDatabase) AddStorageBackend(backend *core.StorageBackend) error {
+	if backend == nil {
+		return errors.New("Storage backend is nil")
+	}
+
+	existingBackend, err := db.GetStorageBackendByName(backend.ColonyName, backend.Name)
+	if err != nil {
+		return err
+	}
+
+	if existingBackend != nil {
+		return errors.New("Storage backend with name <" + backend.Name + "> already exists in Colony with name <" + backend.ColonyName + ">")
+	}
+
+	if backend.Added.IsZero() {
+		backend.Added = time.Now().UTC()
+	}
+
+	// The credentials are sealed before they are stored, the caller's backend keeps the plaintext credentials
+	sealedBackend := *backend
+	sealedBackend.AccessKey, err = secrets.Seal(backend.AccessKey)
+	if err != nil {
+		return err
+	}
+
+	sealedBackend.SecretKey, err = secrets.Seal(backend.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	backendJSON, err := sealedBackend.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `STORAGE_BACKENDS (BACKEND_ID, COLONY_NAME, NAME, DATA, ADDED) VALUES ($1, $2, $3, $4, $5)`
+	_, err = db.postgresql.Exec(sqlStatement, backend.ID, backend.ColonyName, backend.Name, backendJSON, backend.Added)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseStorageBackends(rows *sql.Rows) ([]*core.StorageBackend, error) {
+	var backends []*core.StorageBackend
+
+	for rows.Next() {
+		var backendID string
+		var colonyName string
+		var name string
+		var data string
+		var added time.Time
+		if err := rows.Scan(&backendID, &colonyName, &name, &data, &added); err != nil {
+			return nil, err
+		}
+
+		backend, err := core.ConvertJSONToStorageBackend(data)
+		if err != nil {
+			return nil, err
+		}
+
+		backend.AccessKey, err = secrets.Open(backend.AccessKey)
+		if err != nil {
+			return nil, err
+		}
+
+		backend.SecretKey, err = secrets.Open(backend.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+
+		backends = append(backends, backend)
+	}
+
+	return backends, nil
+}
+
+func (db *PQDatabase) GetStorageBackendByName(colonyName string, name string) (*core.StorageBackend, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `STORAGE_BACKENDS WHERE COLONY_NAME=$1 AND NAME=$2`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	backends, err := db.parseStorageBackends(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backends) == 0 {
+		return nil, nil
+	}
+
+	return backends[0], nil
+}
+
+func (db *PQDatabase) GetStorageBackendsByColonyName(colonyName string) ([]*core.StorageBackend, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `STORAGE_BACKENDS WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseStorageBackends(rows)
+}
+
+func (db *PQDatabase) RemoveStorageBackendByName(colonyName string, name string) error {
+	backend, err := db.GetStorageBackendByName(colonyName, name)
+	if err != nil {
+		return err
+	}
+
+	if backend == nil {
+		return errors.New("Storage backend with name <" + name + "> does not exists in Colony with name <" + colonyName + ">")
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `STORAGE_BACKENDS WHERE BACKEND_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, backend.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveStorageBackendsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `STORAGE_BACKENDS WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) AddStorageObject(object *core.StorageObject) error {
+	if object == nil {
+		return errors.New("Storage object is nil")
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `STORAGE_OBJECTS (OBJECT, COLONY_NAME, BACKEND, REGISTERED, ISSUED) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.postgresql.Exec(sqlStatement, object.Object, object.ColonyName, object.Backend, object.Registered, object.Issued)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RegisterStorageObject marks an issued object as referenced by a file. It returns false if the object was
+// not issued to the colony and backend, or if it has already been registered.
+func (db *PQDatabase) RegisterStorageObject(colonyName string, backend string, object string) (bool, error) {
+	sqlStatement := `UPDATE ` + db.dbPrefix + `STORAGE_OBJECTS SET REGISTERED=TRUE WHERE OBJECT=$1 AND COLONY_NAME=$2 AND BACKEND=$3 AND REGISTERED=FALSE`
+	result, err := db.postgresql.Exec(sqlStatement, object, colonyName, backend)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (db *PQDatabase) GetStorageObjectsByColonyName(colonyName string) ([]*core.StorageObject, error) {
+	sqlStatement := `SELECT OBJECT, COLONY_NAME, BACKEND, REGISTERED, ISSUED FROM ` + db.dbPrefix + `STORAGE_OBJECTS WHERE COLONY_NAME=$1 ORDER BY ISSUED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var objects []*core.StorageObject
+	for rows.Next() {
+		object := &core.StorageObject{}
+		if err := rows.Scan(&object.Object, &object.ColonyName, &object.Backend, &object.Registered, &object.Issued); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+func (db *PQDatabase) RemoveStorageObject(colonyName string, object string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `STORAGE_OBJECTS WHERE COLONY_NAME=$1 AND OBJECT=$2`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName, object)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveStorageObjectsByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `STORAGE_OBJECTS WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStorageBackend(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	backend := utils.CreateTestStorageBackend(colony.Name, "test_backend")
+	err = db.AddStorageBackend(backend)
+	assert.Nil(t, err)
+
+	backendFromDB, err := db.GetStorageBackendByName(colony.Name, "test_backend")
+	assert.Nil(t, err)
+	assert.True(t, backend.Equals(backendFromDB))
+
+	backendFromDB, err = db.GetStorageBackendByName(colony.Name, "does_not_exists")
+	assert.Nil(t, err)
+	assert.Nil(t, backendFromDB)
+
+	err = db.AddStorageBackend(nil)
+	assert.NotNil(t, err)
+
+	// Names must be unique within a colony
+	err = db.AddStorageBackend(utils.CreateTestStorageBackend(colony.Name, "test_backend"))
+	assert.NotNil(t, err)
+}
+
+func TestAddStorageBackendSealsCredentials(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	backend := utils.CreateTestStorageBackend(colony.Name, "test_backend")
+	err = db.AddStorageBackend(backend)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_secretkey", backend.SecretKey)
+
+	var data string
+	err = db.postgresql.QueryRow(`SELECT DATA FROM `+db.dbPrefix+`STORAGE_BACKENDS WHERE BACKEND_ID=$1`, backend.ID).Scan(&data)
+	assert.Nil(t, err)
+	assert.NotContains(t, data, "test_accesskey")
+	assert.NotContains(t, data, "test_secretkey")
+
+	backendFromDB, err := db.GetStorageBackendByName(colony.Name, "test_backend")
+	assert.Nil(t, err)
+	assert.Equal(t, "test_accesskey", backendFromDB.AccessKey)
+	assert.Equal(t, "test_secretkey", backendFromDB.SecretKey)
+}
+
+func TestGetStorageBackendsByColonyName(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	backend1 := utils.CreateTestStorageBackend(colony.Name, "test_backend1")
+	err = db.AddStorageBackend(backend1)
+	assert.Nil(t, err)
+
+	backend2 := utils.CreateTestStorageBackend(colony.Name, "test_backend2")
+	err = db.AddStorageBackend(backend2)
+	assert.Nil(t, err)
+
+	backends, err := db.GetStorageBackendsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.True(t, core.IsStorageBackendArraysEqual([]*core.StorageBackend{backend1, backend2}, backends))
+
+	backends, err = db.GetStorageBackendsByColonyName("does_not_exists")
+	assert.Nil(t, err)
+	assert.Len(t, backends, 0)
+}
+
+func TestRemoveStorageBackend(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	err = db.AddStorageBackend(utils.CreateTestStorageBackend(colony.Name, "test_backend1"))
+	assert.Nil(t, err)
+	err = db.AddStorageBackend(utils.CreateTestStorageBackend(colony.Name, "test_backend2"))
+	assert.Nil(t, err)
+
+	err = db.RemoveStorageBackendByName(colony.Name, "test_backend1")
+	assert.Nil(t, err)
+
+	err = db.RemoveStorageBackendByName(colony.Name, "test_backend1")
+	assert.NotNil(t, err)
+
+	backends, err := db.GetStorageBackendsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 1)
+
+	err = db.RemoveColonyByName(colony.Name)
+	assert.Nil(t, err)
+
+	backends, err = db.GetStorageBackendsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 0)
+}
+
+func TestStorageObjects(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	object1 := core.CreateStorageObject(colony.Name, "test_backend")
+	err = db.AddStorageObject(object1)
+	assert.Nil(t, err)
+
+	object2 := core.CreateStorageObject(colony.Name, "test_backend")
+	err = db.AddStorageObject(object2)
+	assert.Nil(t, err)
+
+	err = db.AddStorageObject(nil)
+	assert.NotNil(t, err)
+
+	// Objects can only be registered once, by the colony and backend they were issued to
+	registered, err := db.RegisterStorageObject("other_colony", "test_backend", object1.Object)
+	assert.Nil(t, err)
+	assert.False(t, registered)
+
+	registered, err = db.RegisterStorageObject(colony.Name, "other_backend", object1.Object)
+	assert.Nil(t, err)
+	assert.False(t, registered)
+
+	registered, err = db.RegisterStorageObject(colony.Name, "test_backend", object1.Object)
+	assert.Nil(t, err)
+	assert.True(t, registered)
+
+	registered, err = db.RegisterStorageObject(colony.Name, "test_backend", object1.Object)
+	assert.Nil(t, err)
+	assert.False(t, registered)
+
+	registered, err = db.RegisterStorageObject(colony.Name, "test_backend", "not_issued")
+	assert.Nil(t, err)
+	assert.False(t, registered)
+
+	objects, err := db.GetStorageObjectsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 2)
+
+	err = db.RemoveStorageObject(colony.Name, object1.Object)
+	assert.Nil(t, err)
+
+	objects, err = db.GetStorageObjectsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, object2.Object, objects[0].Object)
+	assert.False(t, objects[0].Registered)
+
+	err = db.RemoveStorageObjectsByColonyName(colony.Name)
+	assert.Nil(t, err)
+
+	objects, err = db.GetStorageObjectsByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 0)
+}
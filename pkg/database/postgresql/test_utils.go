@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"os"
 	"time"
+
+	"github.com/colonyos/colonies/pkg/security/secrets"
 )
 
 func PrepareTests() (*PQDatabase, error) {
@@ -31,6 +33,9 @@ func PrepareTestsWithPrefix(prefix string) (*PQDatabase, error) {
 		dbPassword = "rFcLGNkgsNtksg6Pgtn9CumL4xXBQ7"
 	}
 	dbName := "postgres"
+	if os.Getenv(secrets.KeyEnv) == "" {
+		os.Setenv(secrets.KeyEnv, "test_secrets_key")
+	}
 	dbPrefix := prefix
 
 	db := CreatePQDatabase(dbHost, dbPort, dbUser, dbPassword, dbName, dbPrefix, false)
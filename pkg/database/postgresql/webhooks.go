@@ -0,0 +1,209 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/secrets"
+	_ "github.com/lib/pq"
+)
+
+func (db *PQDatabase) AddWebhook(webhook *core.Webhook) error {
+	if webhook == nil {
+		return errors.New("Webhook is nil")
+	}
+
+	existingWebhook, err := db.GetWebhookByName(webhook.ColonyName, webhook.Name)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook != nil {
+		return errors.New("Webhook with name <" + webhook.Name + "> already exists in Colony with name <" + webhook.ColonyName + ">")
+	}
+
+	if webhook.Added.IsZero() {
+		webhook.Added = time.Now().UTC()
+	}
+
+	// The signing secret is sealed before it is stored, the caller's webhook keeps the plaintext secret
+	sealedWebhook := *webhook
+	sealedWebhook.Secret, err = secrets.Seal(webhook.Secret)
+	if err != nil {
+		return err
+	}
+
+	webhookJSON, err := sealedWebhook.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `WEBHOOKS (WEBHOOK_ID, COLONY_NAME, NAME, DATA, ADDED) VALUES ($1, $2, $3, $4, $5)`
+	_, err = db.postgresql.Exec(sqlStatement, webhook.ID, webhook.ColonyName, webhook.Name, webhookJSON, webhook.Added)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) parseWebhooks(rows *sql.Rows) ([]*core.Webhook, error) {
+	var webhooks []*core.Webhook
+
+	for rows.Next() {
+		var webhookID string
+		var colonyName string
+		var name string
+		var data string
+		var added time.Time
+		if err := rows.Scan(&webhookID, &colonyName, &name, &data, &added); err != nil {
+			return nil, err
+		}
+
+		webhook, err := core.ConvertJSONToWebhook(data)
+		if err != nil {
+			return nil, err
+		}
+
+		webhook.Secret, err = secrets.Open(webhook.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (db *PQDatabase) GetWebhookByID(webhookID string) (*core.Webhook, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKS WHERE WEBHOOK_ID=$1`
+	rows, err := db.postgresql.Query(sqlStatement, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	webhooks, err := db.parseWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	return webhooks[0], nil
+}
+
+func (db *PQDatabase) GetWebhookByName(colonyName string, name string) (*core.Webhook, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKS WHERE COLONY_NAME=$1 AND NAME=$2`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	webhooks, err := db.parseWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	return webhooks[0], nil
+}
+
+func (db *PQDatabase) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOKS WHERE COLONY_NAME=$1 ORDER BY ADDED ASC`
+	rows, err := db.postgresql.Query(sqlStatement, colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return db.parseWebhooks(rows)
+}
+
+func (db *PQDatabase) RemoveWebhookByName(colonyName string, name string) error {
+	webhook, err := db.GetWebhookByName(colonyName, name)
+	if err != nil {
+		return err
+	}
+
+	if webhook == nil {
+		return errors.New("Webhook with name <" + name + "> does not exists in Colony with name <" + colonyName + ">")
+	}
+
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOK_DELIVERIES WHERE WEBHOOK_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `WEBHOOKS WHERE WEBHOOK_ID=$1`
+	_, err = db.postgresql.Exec(sqlStatement, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) RemoveWebhooksByColonyName(colonyName string) error {
+	sqlStatement := `DELETE FROM ` + db.dbPrefix + `WEBHOOK_DELIVERIES WHERE COLONY_NAME=$1`
+	_, err := db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement = `DELETE FROM ` + db.dbPrefix + `WEBHOOKS WHERE COLONY_NAME=$1`
+	_, err = db.postgresql.Exec(sqlStatement, colonyName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) AddWebhookDelivery(delivery *core.WebhookDelivery) error {
+	if delivery == nil {
+		return errors.New("Webhook delivery is nil")
+	}
+
+	sqlStatement := `INSERT INTO ` + db.dbPrefix + `WEBHOOK_DELIVERIES (DELIVERY_ID, WEBHOOK_ID, COLONY_NAME, EVENT_ID, EVENT_TYPE, ATTEMPTS, STATUS_CODE, SUCCESS, ERROR, ADDED, LAST_ATTEMPT) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := db.postgresql.Exec(sqlStatement, delivery.ID, delivery.WebhookID, delivery.ColonyName, delivery.EventID, delivery.EventType, delivery.Attempts, delivery.StatusCode, delivery.Success, delivery.Error, delivery.Added, delivery.LastAttempt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *PQDatabase) GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error) {
+	sqlStatement := `SELECT * FROM ` + db.dbPrefix + `WEBHOOK_DELIVERIES WHERE WEBHOOK_ID=$1 ORDER BY ADDED DESC LIMIT $2`
+	rows, err := db.postgresql.Query(sqlStatement, webhookID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []*core.WebhookDelivery
+	for rows.Next() {
+		delivery := &core.WebhookDelivery{}
+		if err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.ColonyName, &delivery.EventID, &delivery.EventType, &delivery.Attempts, &delivery.StatusCode, &delivery.Success, &delivery.Error, &delivery.Added, &delivery.LastAttempt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
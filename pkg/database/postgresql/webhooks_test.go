@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWebhook(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	webhook := utils.CreateTestWebhook(colony.Name, "test_webhook")
+	webhook.States = []int{core.FAILED}
+	webhook.ExecutorTypes = []string{"test_executor_type"}
+	err = db.AddWebhook(webhook)
+	assert.Nil(t, err)
+
+	webhookFromDB, err := db.GetWebhookByID(webhook.ID)
+	assert.Nil(t, err)
+	assert.True(t, webhook.Equals(webhookFromDB))
+
+	webhookFromDB, err = db.GetWebhookByName(colony.Name, "test_webhook")
+	assert.Nil(t, err)
+	assert.True(t, webhook.Equals(webhookFromDB))
+
+	err = db.AddWebhook(nil)
+	assert.NotNil(t, err)
+
+	// Names must be unique within a colony
+	err = db.AddWebhook(utils.CreateTestWebhook(colony.Name, "test_webhook"))
+	assert.NotNil(t, err)
+}
+
+func TestGetWebhooksByColonyName(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	webhook1 := utils.CreateTestWebhook(colony.Name, "test_webhook1")
+	err = db.AddWebhook(webhook1)
+	assert.Nil(t, err)
+
+	webhook2 := utils.CreateTestWebhook(colony.Name, "test_webhook2")
+	err = db.AddWebhook(webhook2)
+	assert.Nil(t, err)
+
+	webhooks, err := db.GetWebhooksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.True(t, core.IsWebhookArraysEqual([]*core.Webhook{webhook1, webhook2}, webhooks))
+
+	webhook, err := db.GetWebhookByName(colony.Name, "does_not_exist")
+	assert.Nil(t, err)
+	assert.Nil(t, webhook)
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	webhook1 := utils.CreateTestWebhook(colony.Name, "test_webhook1")
+	err = db.AddWebhook(webhook1)
+	assert.Nil(t, err)
+
+	webhook2 := utils.CreateTestWebhook(colony.Name, "test_webhook2")
+	err = db.AddWebhook(webhook2)
+	assert.Nil(t, err)
+
+	err = db.RemoveWebhookByName(colony.Name, "test_webhook1")
+	assert.Nil(t, err)
+
+	err = db.RemoveWebhookByName(colony.Name, "test_webhook1")
+	assert.NotNil(t, err)
+
+	webhooks, err := db.GetWebhooksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+
+	err = db.RemoveWebhooksByColonyName(colony.Name)
+	assert.Nil(t, err)
+
+	webhooks, err = db.GetWebhooksByColonyName(colony.Name)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 0)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	db, err := PrepareTests()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	colony, _, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	err = db.AddColony(colony)
+	assert.Nil(t, err)
+
+	webhook := utils.CreateTestWebhook(colony.Name, "test_webhook")
+	err = db.AddWebhook(webhook)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		delivery := &core.WebhookDelivery{
+			ID:          core.GenerateRandomID(),
+			WebhookID:   webhook.ID,
+			ColonyName:  colony.Name,
+			EventID:     core.GenerateRandomID(),
+			EventType:   core.WebhookEventProcessFailed,
+			Attempts:    i + 1,
+			StatusCode:  200,
+			Success:     true,
+			Added:       now.Add(time.Duration(i) * time.Second),
+			LastAttempt: now.Add(time.Duration(i) * time.Second),
+		}
+		err = db.AddWebhookDelivery(delivery)
+		assert.Nil(t, err)
+	}
+
+	deliveries, err := db.GetWebhookDeliveries(webhook.ID, 3)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 3)
+	assert.Equal(t, 5, deliveries[0].Attempts) // Latest first
+
+	err = db.AddWebhookDelivery(nil)
+	assert.NotNil(t, err)
+
+	// Removing the webhook also removes the delivery history
+	err = db.RemoveWebhookByName(colony.Name, webhook.Name)
+	assert.Nil(t, err)
+
+	deliveries, err = db.GetWebhookDeliveries(webhook.ID, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 0)
+}
@@ -38,6 +38,7 @@ type ProcessDatabase interface {
 	Assign(executorID string, process *core.Process) error
 	SelectAndAssign(colonyName string, executorID string, executorName string, executorType string, executorLocation string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) (*core.Process, error)
 	Unassign(process *core.Process) error
+	Release(process *core.Process) error
 	MarkSuccessful(processID string) (float64, float64, error)
 	MarkFailed(processID string, errs []string) error
 	MarkCancelled(processID string) error
@@ -26,10 +26,14 @@ type BlueprintDatabase interface {
 	GetBlueprintsByNamespaceAndKind(namespace, kind string) ([]*core.Blueprint, error)
 	GetBlueprintsByNamespaceKindAndLocation(namespace, kind, locationName string) ([]*core.Blueprint, error)
 	UpdateBlueprint(blueprint *core.Blueprint) error
+	UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64) error
 	UpdateBlueprintStatus(id string, status map[string]interface{}) error
+	UpdateBlueprintStatusWithResourceVersion(id string, status map[string]interface{}, resourceVersion int64) error
 	RemoveBlueprintByID(id string) error
 	RemoveBlueprintByName(namespace, name string) error
+	RemoveBlueprintByNameWithResourceVersion(namespace, name string, resourceVersion int64) error
 	RemoveBlueprintsByNamespace(namespace string) error
+	ApplyBlueprints(creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error
 	CountBlueprints() (int, error)
 	CountBlueprintsByNamespace(namespace string) (int, error)
 
@@ -38,4 +42,5 @@ type BlueprintDatabase interface {
 	GetBlueprintHistory(blueprintID string, limit int) ([]*core.BlueprintHistory, error)
 	GetBlueprintHistoryByGeneration(blueprintID string, generation int64) (*core.BlueprintHistory, error)
 	RemoveBlueprintHistory(blueprintID string) error
+	PruneBlueprintHistory(blueprintID string, keep int) error
 }
@@ -10,4 +10,7 @@ type SnapshotDatabase interface {
 	GetSnapshotByName(colonyName string, name string) (*core.Snapshot, error)
 	RemoveSnapshotByName(colonyName string, name string) error
 	RemoveSnapshotsByColonyName(colonyName string) error
+	DiffSnapshots(colonyName string, fromSnapshotID string, toSnapshotID string) (*core.SnapshotDiff, error)
+	RestoreSnapshot(colonyName string, snapshotID string, prune bool) (*core.SnapshotDiff, error)
+	CloneSnapshot(colonyName string, snapshotID string, label string) (*core.SnapshotDiff, error)
 }
\ No newline at end of file
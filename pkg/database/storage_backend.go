@@ -0,0 +1,16 @@
+package database
+
+import "github.com/colonyos/colonies/pkg/core"
+
+type StorageBackendDatabase interface {
+	AddStorageBackend(backend *core.StorageBackend) error
+	GetStorageBackendByName(colonyName string, name string) (*core.StorageBackend, error)
+	GetStorageBackendsByColonyName(colonyName string) ([]*core.StorageBackend, error)
+	RemoveStorageBackendByName(colonyName string, name string) error
+	RemoveStorageBackendsByColonyName(colonyName string) error
+	AddStorageObject(object *core.StorageObject) error
+	RegisterStorageObject(colonyName string, backend string, object string) (bool, error)
+	GetStorageObjectsByColonyName(colonyName string) ([]*core.StorageObject, error)
+	RemoveStorageObject(colonyName string, object string) error
+	RemoveStorageObjectsByColonyName(colonyName string) error
+}
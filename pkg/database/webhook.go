@@ -0,0 +1,14 @@
+package database
+
+import "github.com/colonyos/colonies/pkg/core"
+
+type WebhookDatabase interface {
+	AddWebhook(webhook *core.Webhook) error
+	GetWebhookByID(webhookID string) (*core.Webhook, error)
+	GetWebhookByName(colonyName string, name string) (*core.Webhook, error)
+	GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error)
+	RemoveWebhookByName(colonyName string, name string) error
+	RemoveWebhooksByColonyName(colonyName string) error
+	AddWebhookDelivery(delivery *core.WebhookDelivery) error
+	GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error)
+}
@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// Argument types supported by DecodeArgs, the types follow JSON Schema naming
+const (
+	StringArg  = core.FunctionArgString
+	IntegerArg = core.FunctionArgInteger
+	NumberArg  = core.FunctionArgNumber
+	BooleanArg = core.FunctionArgBoolean
+	ObjectArg  = core.FunctionArgObject
+	ArrayArg   = core.FunctionArgArray
+)
+
+// DecodeArgs decodes the arguments of a function spec as described by args. An argument is taken
+// from KwArgs by name, or from Args by its position in args. Values are converted to the declared
+// type, string values are parsed so that arguments submitted from the CLI can be used.
+func DecodeArgs(funcSpec *core.FunctionSpec, args []*core.FunctionArg) (map[string]interface{}, error) {
+	return core.DecodeFunctionArgs(funcSpec, args)
+}
+
+// DecodeArg converts a value to the type declared by arg and checks that it is one of the enum values, if any.
+// Integers are returned as int64, numbers as float64, objects as map[string]interface{} and arrays as []interface{}.
+func DecodeArg(arg *core.FunctionArg, value interface{}) (interface{}, error) {
+	return arg.Decode(value)
+}
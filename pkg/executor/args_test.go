@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeArgs(t *testing.T) {
+	args := []*core.FunctionArg{
+		core.CreateFunctionArg("query", StringArg, "The search query", true, nil),
+		core.CreateFunctionArg("limit", IntegerArg, "Maximum results", false, nil),
+		core.CreateFunctionArg("format", StringArg, "Output format", false, []string{"json", "text"}),
+	}
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.Args = []interface{}{"colonies", "10"}
+	funcSpec.KwArgs["format"] = "json"
+
+	decoded, err := DecodeArgs(funcSpec, args)
+	assert.Nil(t, err)
+	assert.Equal(t, "colonies", decoded["query"])
+	assert.Equal(t, int64(10), decoded["limit"])
+	assert.Equal(t, "json", decoded["format"])
+
+	// KwArgs take precedence over positional args
+	funcSpec.KwArgs["limit"] = float64(5)
+	decoded, err = DecodeArgs(funcSpec, args)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), decoded["limit"])
+
+	// Optional arguments may be omitted
+	funcSpec = core.CreateEmptyFunctionSpec()
+	funcSpec.KwArgs["query"] = "colonies"
+	decoded, err = DecodeArgs(funcSpec, args)
+	assert.Nil(t, err)
+	assert.Len(t, decoded, 1)
+}
+
+func TestDecodeArgsInvalid(t *testing.T) {
+	args := []*core.FunctionArg{
+		core.CreateFunctionArg("query", StringArg, "", true, nil),
+		core.CreateFunctionArg("format", StringArg, "", false, []string{"json", "text"}),
+	}
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	_, err := DecodeArgs(funcSpec, args)
+	assert.NotNil(t, err)
+
+	funcSpec.KwArgs["query"] = "colonies"
+	funcSpec.KwArgs["format"] = "xml"
+	_, err = DecodeArgs(funcSpec, args)
+	assert.NotNil(t, err)
+}
+
+func TestDecodeArg(t *testing.T) {
+	tests := []struct {
+		argType  string
+		value    interface{}
+		expected interface{}
+		valid    bool
+	}{
+		{StringArg, "hello", "hello", true},
+		{StringArg, float64(1), nil, false},
+		{StringArg, []interface{}{}, nil, false},
+		{IntegerArg, float64(42), int64(42), true},
+		{IntegerArg, "42", int64(42), true},
+		{IntegerArg, float64(4.2), nil, false},
+		{IntegerArg, "abc", nil, false},
+		{NumberArg, float64(4.2), 4.2, true},
+		{NumberArg, "4.2", 4.2, true},
+		{NumberArg, true, nil, false},
+		{BooleanArg, true, true, true},
+		{BooleanArg, "false", false, true},
+		{BooleanArg, "maybe", nil, false},
+		{ObjectArg, map[string]interface{}{"a": "b"}, map[string]interface{}{"a": "b"}, true},
+		{ObjectArg, `{"a":"b"}`, map[string]interface{}{"a": "b"}, true},
+		{ObjectArg, "[]", nil, false},
+		{ArrayArg, []interface{}{"a"}, []interface{}{"a"}, true},
+		{ArrayArg, `["a"]`, []interface{}{"a"}, true},
+		{ArrayArg, float64(1), nil, false},
+		{"", float64(1), float64(1), true},
+		{"unknown", float64(1), nil, false},
+	}
+
+	for _, test := range tests {
+		decoded, err := DecodeArg(&core.FunctionArg{Name: "arg", Type: test.argType}, test.value)
+		if test.valid {
+			assert.Nil(t, err, test.argType)
+			assert.Equal(t, test.expected, decoded, test.argType)
+		} else {
+			assert.NotNil(t, err, test.argType)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/colonyos/colonies/pkg/channel"
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// Context is passed to function handlers. It is cancelled when the executor shuts down and the
+// process is unassigned, and provides decoded arguments, logging and channel helpers.
+type Context struct {
+	context.Context
+	Process *core.Process
+
+	args       map[string]interface{}
+	client     coloniesClient
+	prvKey     string
+	cancel     context.CancelFunc
+	unassigned atomic.Bool
+	sequences  map[string]int64
+	mutex      sync.Mutex
+	logBuf     bytes.Buffer
+}
+
+func newContext(parent context.Context, process *core.Process, args map[string]interface{}, client coloniesClient, prvKey string) *Context {
+	ctx, cancel := context.WithCancel(parent)
+	return &Context{
+		Context:   ctx,
+		Process:   process,
+		args:      args,
+		client:    client,
+		prvKey:    prvKey,
+		cancel:    cancel,
+		sequences: make(map[string]int64),
+	}
+}
+
+// Args returns all decoded arguments
+func (c *Context) Args() map[string]interface{} {
+	return c.args
+}
+
+// Arg returns a decoded argument and whether it was set
+func (c *Context) Arg(name string) (interface{}, bool) {
+	value, ok := c.args[name]
+	return value, ok
+}
+
+// String returns a string argument, or an empty string if it was not set
+func (c *Context) String(name string) string {
+	value, _ := c.args[name].(string)
+	return value
+}
+
+// Int returns an integer argument, or 0 if it was not set
+func (c *Context) Int(name string) int64 {
+	value, _ := c.args[name].(int64)
+	return value
+}
+
+// Float returns a number argument, or 0 if it was not set
+func (c *Context) Float(name string) float64 {
+	value, _ := c.args[name].(float64)
+	return value
+}
+
+// Bool returns a boolean argument, or false if it was not set
+func (c *Context) Bool(name string) bool {
+	value, _ := c.args[name].(bool)
+	return value
+}
+
+// Bind decodes all arguments into v, which is typically a pointer to a struct with json tags
+func (c *Context) Bind(v interface{}) error {
+	jsonBytes, err := json.Marshal(c.args)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonBytes, v)
+}
+
+// Log adds a log message to the process
+func (c *Context) Log(format string, args ...interface{}) error {
+	return c.client.AddLog(c.Process.ID, fmt.Sprintf(format, args...), c.prvKey)
+}
+
+// Write implements io.Writer, output is streamed to the process log one line at a time
+func (c *Context) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.logBuf.Write(p)
+	for {
+		line, err := c.logBuf.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line until more output arrives or the log is flushed
+			c.logBuf.Reset()
+			c.logBuf.WriteString(line)
+			break
+		}
+		if err := c.client.AddLog(c.Process.ID, line, c.prvKey); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// flushLog sends any incomplete line written to the context
+func (c *Context) flushLog() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.logBuf.Len() == 0 {
+		return nil
+	}
+
+	line := c.logBuf.String()
+	c.logBuf.Reset()
+
+	return c.client.AddLog(c.Process.ID, line, c.prvKey)
+}
+
+// Send appends a message to a process channel, sequence numbers are assigned automatically
+func (c *Context) Send(channelName string, payload []byte) error {
+	c.mutex.Lock()
+	c.sequences[channelName]++
+	sequence := c.sequences[channelName]
+	c.mutex.Unlock()
+
+	return c.client.ChannelAppend(c.Process.ID, channelName, sequence, 0, payload, c.prvKey)
+}
+
+// Reply appends a message to a process channel in reply to the message with the given sequence number
+func (c *Context) Reply(channelName string, inReplyTo int64, payload []byte) error {
+	c.mutex.Lock()
+	c.sequences[channelName]++
+	sequence := c.sequences[channelName]
+	c.mutex.Unlock()
+
+	return c.client.ChannelAppend(c.Process.ID, channelName, sequence, inReplyTo, payload, c.prvKey)
+}
+
+// Read reads up to limit messages after the given index from a process channel
+func (c *Context) Read(channelName string, afterIndex int64, limit int) ([]*channel.MsgEntry, error) {
+	return c.client.ChannelRead(c.Process.ID, channelName, afterIndex, limit, c.prvKey)
+}
+
+// Subscribe subscribes to messages appended to a process channel after the given sequence number
+func (c *Context) Subscribe(channelName string, afterSeq int64, timeout int) (*client.ChannelSubscription, error) {
+	return c.client.SubscribeChannel(c.Process.ID, channelName, afterSeq, timeout, c.prvKey)
+}
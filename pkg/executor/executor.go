@@ -0,0 +1,465 @@
+// Package executor implements a framework for writing Colonies executors. It registers the
+// executor and its functions, assigns processes, dispatches them to function handlers and
+// closes them, sends heartbeats and unassigns unfinished processes on shutdown.
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/channel"
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultConcurrency       = 1
+	DefaultAssignTimeout     = 10 // seconds
+	DefaultHeartbeatInterval = 30 * time.Second
+	DefaultRetryDelay        = 1 * time.Second
+	DefaultMaxRetryDelay     = 30 * time.Second
+	DefaultShutdownTimeout   = 10 * time.Second
+)
+
+// HandlerFunc executes a process, the returned output is set on the process when it is closed.
+// If an error is returned the process is closed as failed.
+type HandlerFunc func(ctx *Context) ([]interface{}, error)
+
+// Config configures an executor
+type Config struct {
+	ColonyName     string
+	ExecutorName   string
+	ExecutorType   string
+	ExecutorPrvKey string
+	// ColonyPrvKey is optional, if set the executor registers and approves itself, and re-registers
+	// if it has been removed from the colony. If not set the executor must already be registered.
+	ColonyPrvKey string
+	LocationName string
+	// RequireFuncReg makes the server validate submitted args against the args of registered functions
+	RequireFuncReg bool
+	// Concurrency is the maximum number of processes executed in parallel
+	Concurrency int
+	// AssignTimeout is how many seconds an assign request waits for a process
+	AssignTimeout     int
+	HeartbeatInterval time.Duration
+	RetryDelay        time.Duration
+	MaxRetryDelay     time.Duration
+	// ShutdownTimeout is how long running processes may continue after shutdown has started
+	// before they are cancelled, unassigned and moved back to the queue. Unassigned processes do
+	// not count as retries.
+	ShutdownTimeout time.Duration
+}
+
+// coloniesClient is the subset of client.ColoniesClient used by the executor
+type coloniesClient interface {
+	AddExecutor(executor *core.Executor, prvKey string) (*core.Executor, error)
+	ApproveExecutor(colonyName string, executorName string, prvKey string) error
+	ExecutorHeartbeat(colonyName string, executorName string, prvKey string) error
+	AddFunction(function *core.Function, prvKey string) (*core.Function, error)
+	GetFunctionsByExecutor(colonyName string, executorName string, prvKey string) ([]*core.Function, error)
+	AssignWithContext(colonyName string, timeout int, ctx context.Context, availableCPU string, availableMem string, prvKey string) (*core.Process, error)
+	UnassignProcess(processID string, prvKey string) error
+	Close(processID string, prvKey string) error
+	CloseWithOutput(processID string, output []interface{}, prvKey string) error
+	Fail(processID string, errs []string, prvKey string) error
+	AddLog(processID string, logmsg string, prvKey string) error
+	ChannelAppend(processID string, channelName string, sequence int64, inReplyTo int64, payload []byte, prvKey string) error
+	ChannelRead(processID string, channelName string, afterIndex int64, limit int, prvKey string) ([]*channel.MsgEntry, error)
+	SubscribeChannel(processID string, channelName string, afterSeq int64, timeout int, prvKey string) (*client.ChannelSubscription, error)
+}
+
+type function struct {
+	description string
+	args        []*core.FunctionArg
+	handler     HandlerFunc
+}
+
+// Executor assigns processes and dispatches them to registered function handlers
+type Executor struct {
+	client     coloniesClient
+	config     Config
+	executorID string
+	functions  map[string]*function
+	inflight   map[string]*Context
+	wg         sync.WaitGroup
+	mutex      sync.Mutex
+}
+
+// New creates an executor using the given client
+func New(coloniesClient *client.ColoniesClient, config Config) (*Executor, error) {
+	return newExecutor(coloniesClient, config)
+}
+
+func newExecutor(coloniesClient coloniesClient, config Config) (*Executor, error) {
+	if config.ColonyName == "" {
+		return nil, errors.New("Colony name must be set")
+	}
+	if config.ExecutorName == "" {
+		return nil, errors.New("Executor name must be set")
+	}
+	if config.ExecutorPrvKey == "" {
+		return nil, errors.New("Executor private key must be set")
+	}
+	if config.ColonyPrvKey != "" && config.ExecutorType == "" {
+		return nil, errors.New("Executor type must be set to register the executor")
+	}
+
+	executorID, err := crypto.CreateCrypto().GenerateID(config.ExecutorPrvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.AssignTimeout <= 0 {
+		config.AssignTimeout = DefaultAssignTimeout
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+	if config.MaxRetryDelay <= 0 {
+		config.MaxRetryDelay = DefaultMaxRetryDelay
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &Executor{
+		client:     coloniesClient,
+		config:     config,
+		executorID: executorID,
+		functions:  make(map[string]*function),
+		inflight:   make(map[string]*Context),
+	}, nil
+}
+
+// ExecutorID returns the executor id derived from the executor private key
+func (e *Executor) ExecutorID() string {
+	return e.executorID
+}
+
+// Register registers a function handler, args describes the function arguments and is used to
+// decode the arguments of assigned processes. Functions must be registered before Run is called.
+func (e *Executor) Register(funcName string, description string, args []*core.FunctionArg, handler HandlerFunc) error {
+	if funcName == "" {
+		return errors.New("Function name must be set")
+	}
+	if handler == nil {
+		return errors.New("Function handler must be set")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, exists := e.functions[funcName]; exists {
+		return errors.New("Function <" + funcName + "> is already registered")
+	}
+
+	e.functions[funcName] = &function{description: description, args: args, handler: handler}
+
+	return nil
+}
+
+// Run registers the executor and its functions, then assigns and executes processes until ctx
+// is cancelled. On shutdown, running processes are given ShutdownTimeout to complete before they
+// are unassigned.
+func (e *Executor) Run(ctx context.Context) error {
+	if e.config.ColonyPrvKey != "" {
+		if err := e.registerExecutor(); err != nil {
+			return err
+		}
+	}
+
+	if err := e.registerFunctions(); err != nil {
+		return err
+	}
+
+	heartbeatCtx, cancelHeartbeat := context.WithCancel(context.Background())
+	defer cancelHeartbeat()
+	go e.heartbeatLoop(heartbeatCtx)
+
+	slots := make(chan struct{}, e.config.Concurrency)
+	retryDelay := e.config.RetryDelay
+
+	for {
+		if ctx.Err() != nil {
+			e.shutdown()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			continue
+		case slots <- struct{}{}:
+		}
+
+		process, err := e.client.AssignWithContext(e.config.ColonyName, e.config.AssignTimeout, ctx, "", "", e.config.ExecutorPrvKey)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				continue
+			}
+
+			if !e.handleAssignError(err) {
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+				retryDelay = min(retryDelay*2, e.config.MaxRetryDelay)
+			}
+			continue
+		}
+
+		retryDelay = e.config.RetryDelay
+
+		e.wg.Add(1)
+		go func() {
+			defer func() { <-slots }()
+			defer e.wg.Done()
+			e.execute(process)
+		}()
+	}
+}
+
+// handleAssignError returns true if the error is expected and the executor can assign again immediately
+func (e *Executor) handleAssignError(err error) bool {
+	var coloniesErr *core.ColoniesError
+	if errors.As(err, &coloniesErr) {
+		switch coloniesErr.Status {
+		case 404, 408:
+			// No process available within the assign timeout
+			return true
+		case 403:
+			e.reregister(err)
+			return false
+		}
+
+		if strings.Contains(coloniesErr.Message, "executor not found") {
+			e.reregister(err)
+			return false
+		}
+	}
+
+	log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": err}).Warn("Failed to assign process, retrying")
+
+	return false
+}
+
+func (e *Executor) reregister(cause error) {
+	if e.config.ColonyPrvKey == "" {
+		log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": cause}).Error("Executor is not a member of the colony, colony private key required to re-register")
+		return
+	}
+
+	log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": cause}).Warn("Executor is not a member of the colony, re-registering")
+
+	if err := e.registerExecutor(); err != nil {
+		log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": err}).Error("Failed to re-register executor")
+		return
+	}
+
+	if err := e.registerFunctions(); err != nil {
+		log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": err}).Error("Failed to register functions")
+	}
+}
+
+func (e *Executor) registerExecutor() error {
+	executor := core.CreateExecutor(e.executorID, e.config.ExecutorType, e.config.ExecutorName, e.config.ColonyName, time.Now(), time.Now())
+	executor.LocationName = e.config.LocationName
+	executor.RequireFuncReg = e.config.RequireFuncReg
+
+	_, err := e.client.AddExecutor(executor, e.config.ColonyPrvKey)
+	if err != nil {
+		return fmt.Errorf("Failed to register executor: %w", err)
+	}
+
+	err = e.client.ApproveExecutor(e.config.ColonyName, e.config.ExecutorName, e.config.ColonyPrvKey)
+	if err != nil {
+		return fmt.Errorf("Failed to approve executor: %w", err)
+	}
+
+	log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "ExecutorID": e.executorID, "ColonyName": e.config.ColonyName}).Info("Executor registered")
+
+	return nil
+}
+
+func (e *Executor) registerFunctions() error {
+	existingFunctions, err := e.client.GetFunctionsByExecutor(e.config.ColonyName, e.config.ExecutorName, e.config.ExecutorPrvKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get registered functions: %w", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, existingFunction := range existingFunctions {
+		registered[existingFunction.FuncName] = true
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for funcName, fn := range e.functions {
+		if registered[funcName] {
+			continue
+		}
+
+		f := core.CreateFunctionWithDesc(e.config.ExecutorName, e.config.ExecutorType, e.config.ColonyName, funcName, fn.description, fn.args)
+		if _, err := e.client.AddFunction(f, e.config.ExecutorPrvKey); err != nil {
+			return fmt.Errorf("Failed to register function <%s>: %w", funcName, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Executor) heartbeatLoop(ctx context.Context) {
+	ticker := time.NewTicker(e.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := e.client.ExecutorHeartbeat(e.config.ColonyName, e.config.ExecutorName, e.config.ExecutorPrvKey)
+			if err != nil {
+				log.WithFields(log.Fields{"ExecutorName": e.config.ExecutorName, "Error": err}).Warn("Failed to send heartbeat")
+			}
+		}
+	}
+}
+
+func (e *Executor) execute(process *core.Process) {
+	funcName := process.FunctionSpec.FuncName
+
+	e.mutex.Lock()
+	fn, exists := e.functions[funcName]
+	e.mutex.Unlock()
+
+	if !exists {
+		e.fail(process, errors.New("Function <"+funcName+"> is not registered by executor <"+e.config.ExecutorName+">"))
+		return
+	}
+
+	args, err := DecodeArgs(&process.FunctionSpec, fn.args)
+	if err != nil {
+		e.fail(process, err)
+		return
+	}
+
+	ctx := newContext(context.Background(), process, args, e.client, e.config.ExecutorPrvKey)
+	defer ctx.cancel()
+
+	e.mutex.Lock()
+	e.inflight[process.ID] = ctx
+	e.mutex.Unlock()
+
+	defer func() {
+		e.mutex.Lock()
+		delete(e.inflight, process.ID)
+		e.mutex.Unlock()
+	}()
+
+	output, err := e.callHandler(fn.handler, ctx)
+
+	if err := ctx.flushLog(); err != nil {
+		log.WithFields(log.Fields{"ProcessID": process.ID, "Error": err}).Warn("Failed to flush process log")
+	}
+
+	if ctx.unassigned.Load() {
+		// The process was moved back to the queue during shutdown
+		return
+	}
+
+	if err != nil {
+		e.fail(process, err)
+		return
+	}
+
+	if output != nil {
+		err = e.client.CloseWithOutput(process.ID, output, e.config.ExecutorPrvKey)
+	} else {
+		err = e.client.Close(process.ID, e.config.ExecutorPrvKey)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"ProcessID": process.ID, "Error": err}).Error("Failed to close process")
+	}
+}
+
+func (e *Executor) callHandler(handler HandlerFunc, ctx *Context) (output []interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Function handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx)
+}
+
+func (e *Executor) fail(process *core.Process, cause error) {
+	log.WithFields(log.Fields{"ProcessID": process.ID, "FuncName": process.FunctionSpec.FuncName, "Error": cause}).Debug("Process failed")
+
+	if err := e.client.Fail(process.ID, []string{cause.Error()}, e.config.ExecutorPrvKey); err != nil {
+		log.WithFields(log.Fields{"ProcessID": process.ID, "Error": err}).Error("Failed to close process as failed")
+	}
+}
+
+// shutdown waits for running processes to complete. Processes still running after ShutdownTimeout are
+// cancelled, and their handlers are given another ShutdownTimeout to return before the processes are
+// unassigned, so that handlers do not use processes that have been moved back to the queue.
+func (e *Executor) shutdown() {
+	if e.waitForHandlers(e.config.ShutdownTimeout) {
+		return
+	}
+
+	e.mutex.Lock()
+	inflight := make([]*Context, 0, len(e.inflight))
+	for _, ctx := range e.inflight {
+		inflight = append(inflight, ctx)
+	}
+	e.mutex.Unlock()
+
+	for _, ctx := range inflight {
+		ctx.unassigned.Store(true)
+		ctx.cancel()
+	}
+
+	if !e.waitForHandlers(e.config.ShutdownTimeout) {
+		log.Warn("Function handlers did not return after being cancelled, unassigning processes anyway")
+	}
+
+	for _, ctx := range inflight {
+		err := e.client.UnassignProcess(ctx.Process.ID, e.config.ExecutorPrvKey)
+		if err != nil {
+			log.WithFields(log.Fields{"ProcessID": ctx.Process.ID, "Error": err}).Error("Failed to unassign process")
+			continue
+		}
+
+		log.WithFields(log.Fields{"ProcessID": ctx.Process.ID}).Info("Unassigned process during shutdown")
+	}
+}
+
+// waitForHandlers waits for all running handlers to return, returns false if they did not return within timeout
+func (e *Executor) waitForHandlers(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
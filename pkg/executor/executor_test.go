@@ -0,0 +1,389 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/channel"
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type clientMock struct {
+	mutex        sync.Mutex
+	queue        chan *core.Process
+	assignErrs   []error
+	executors    []*core.Executor
+	approved     []string
+	functions    []*core.Function
+	heartbeats   int
+	closed       map[string][]interface{}
+	failed       map[string][]string
+	unassigned   []string
+	logs         map[string][]string
+	channelMsgs  map[string][]int64
+	addFunctions int
+}
+
+func newClientMock() *clientMock {
+	return &clientMock{
+		queue:       make(chan *core.Process, 10),
+		closed:      make(map[string][]interface{}),
+		failed:      make(map[string][]string),
+		logs:        make(map[string][]string),
+		channelMsgs: make(map[string][]int64),
+	}
+}
+
+func (m *clientMock) AddExecutor(executor *core.Executor, prvKey string) (*core.Executor, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.executors = append(m.executors, executor)
+	return executor, nil
+}
+
+func (m *clientMock) ApproveExecutor(colonyName string, executorName string, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.approved = append(m.approved, executorName)
+	return nil
+}
+
+func (m *clientMock) ExecutorHeartbeat(colonyName string, executorName string, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.heartbeats++
+	return nil
+}
+
+func (m *clientMock) AddFunction(function *core.Function, prvKey string) (*core.Function, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.functions = append(m.functions, function)
+	m.addFunctions++
+	return function, nil
+}
+
+func (m *clientMock) GetFunctionsByExecutor(colonyName string, executorName string, prvKey string) ([]*core.Function, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.functions, nil
+}
+
+func (m *clientMock) AssignWithContext(colonyName string, timeout int, ctx context.Context, availableCPU string, availableMem string, prvKey string) (*core.Process, error) {
+	m.mutex.Lock()
+	if len(m.assignErrs) > 0 {
+		err := m.assignErrs[0]
+		m.assignErrs = m.assignErrs[1:]
+		m.mutex.Unlock()
+		return nil, err
+	}
+	m.mutex.Unlock()
+
+	select {
+	case process := <-m.queue:
+		return process, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(50 * time.Millisecond):
+		return nil, &core.ColoniesError{Status: 404, Message: "No process available for assignment"}
+	}
+}
+
+func (m *clientMock) UnassignProcess(processID string, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.unassigned = append(m.unassigned, processID)
+	return nil
+}
+
+func (m *clientMock) Close(processID string, prvKey string) error {
+	return m.CloseWithOutput(processID, nil, prvKey)
+}
+
+func (m *clientMock) CloseWithOutput(processID string, output []interface{}, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.closed[processID] = output
+	return nil
+}
+
+func (m *clientMock) Fail(processID string, errs []string, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.failed[processID] = errs
+	return nil
+}
+
+func (m *clientMock) AddLog(processID string, logmsg string, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.logs[processID] = append(m.logs[processID], logmsg)
+	return nil
+}
+
+func (m *clientMock) ChannelAppend(processID string, channelName string, sequence int64, inReplyTo int64, payload []byte, prvKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.channelMsgs[channelName] = append(m.channelMsgs[channelName], sequence)
+	return nil
+}
+
+func (m *clientMock) ChannelRead(processID string, channelName string, afterIndex int64, limit int, prvKey string) ([]*channel.MsgEntry, error) {
+	return nil, nil
+}
+
+func (m *clientMock) SubscribeChannel(processID string, channelName string, afterSeq int64, timeout int, prvKey string) (*client.ChannelSubscription, error) {
+	return nil, errors.New("not supported")
+}
+
+func (m *clientMock) state(f func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	f()
+}
+
+func createTestExecutor(t *testing.T, mock *clientMock, config Config) *Executor {
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+
+	config.ColonyName = "test_colony"
+	config.ExecutorName = "test_executor"
+	config.ExecutorType = "test_executor_type"
+	config.ExecutorPrvKey = prvKey
+
+	e, err := newExecutor(mock, config)
+	assert.Nil(t, err)
+
+	return e
+}
+
+func createTestProcess(funcName string, kwargs map[string]interface{}) *core.Process {
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.FuncName = funcName
+	funcSpec.KwArgs = kwargs
+	return core.CreateProcess(funcSpec)
+}
+
+func waitFor(t *testing.T, mock *clientMock, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		done := false
+		mock.state(func() { done = condition() })
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
+
+func TestNewExecutorInvalidConfig(t *testing.T) {
+	_, err := newExecutor(newClientMock(), Config{})
+	assert.NotNil(t, err)
+
+	_, err = newExecutor(newClientMock(), Config{ColonyName: "test_colony"})
+	assert.NotNil(t, err)
+
+	_, err = newExecutor(newClientMock(), Config{ColonyName: "test_colony", ExecutorName: "test_executor"})
+	assert.NotNil(t, err)
+
+	_, err = newExecutor(newClientMock(), Config{ColonyName: "test_colony", ExecutorName: "test_executor", ExecutorPrvKey: "invalid"})
+	assert.NotNil(t, err)
+}
+
+func TestExecutorRegister(t *testing.T) {
+	e := createTestExecutor(t, newClientMock(), Config{})
+	handler := func(ctx *Context) ([]interface{}, error) { return nil, nil }
+
+	assert.Nil(t, e.Register("echo", "", nil, handler))
+	assert.NotNil(t, e.Register("echo", "", nil, handler))
+	assert.NotNil(t, e.Register("", "", nil, handler))
+	assert.NotNil(t, e.Register("other", "", nil, nil))
+}
+
+func TestExecutorRun(t *testing.T) {
+	mock := newClientMock()
+	colonyPrvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+	e := createTestExecutor(t, mock, Config{ColonyPrvKey: colonyPrvKey, Concurrency: 2})
+
+	args := []*core.FunctionArg{core.CreateFunctionArg("msg", StringArg, "Message to echo", true, nil)}
+	assert.Nil(t, e.Register("echo", "Echoes a message", args, func(ctx *Context) ([]interface{}, error) {
+		fmt.Fprintf(ctx, "echo %s\n", ctx.String("msg"))
+		assert.Nil(t, ctx.Send("out", []byte(ctx.String("msg"))))
+		assert.Nil(t, ctx.Send("out", []byte(ctx.String("msg"))))
+		return []interface{}{ctx.String("msg")}, nil
+	}))
+	assert.Nil(t, e.Register("fail", "", nil, func(ctx *Context) ([]interface{}, error) {
+		return nil, errors.New("failed")
+	}))
+	assert.Nil(t, e.Register("panic", "", nil, func(ctx *Context) ([]interface{}, error) {
+		panic("oops")
+	}))
+
+	echo := createTestProcess("echo", map[string]interface{}{"msg": "hello"})
+	invalidArgs := createTestProcess("echo", map[string]interface{}{})
+	fail := createTestProcess("fail", nil)
+	panicking := createTestProcess("panic", nil)
+	unknown := createTestProcess("unknown", nil)
+	for _, process := range []*core.Process{echo, invalidArgs, fail, panicking, unknown} {
+		mock.queue <- process
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	waitFor(t, mock, func() bool { return len(mock.closed)+len(mock.failed) == 5 })
+	cancel()
+	assert.Nil(t, <-done)
+
+	mock.state(func() {
+		assert.Len(t, mock.executors, 1)
+		assert.Equal(t, e.ExecutorID(), mock.executors[0].ID)
+		assert.Equal(t, []string{"test_executor"}, mock.approved)
+		assert.Len(t, mock.functions, 3)
+
+		assert.Equal(t, []interface{}{"hello"}, mock.closed[echo.ID])
+		assert.Equal(t, []string{"echo hello\n"}, mock.logs[echo.ID])
+		assert.Equal(t, []int64{1, 2}, mock.channelMsgs["out"])
+
+		assert.Contains(t, mock.failed, invalidArgs.ID)
+		assert.Equal(t, []string{"failed"}, mock.failed[fail.ID])
+		assert.Contains(t, mock.failed[panicking.ID][0], "oops")
+		assert.Contains(t, mock.failed, unknown.ID)
+	})
+}
+
+func TestExecutorReregister(t *testing.T) {
+	mock := newClientMock()
+	mock.assignErrs = []error{&core.ColoniesError{Status: 403, Message: "Access denied, not a member of colony"}}
+	colonyPrvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+	e := createTestExecutor(t, mock, Config{ColonyPrvKey: colonyPrvKey, RetryDelay: time.Millisecond})
+	assert.Nil(t, e.Register("echo", "", nil, func(ctx *Context) ([]interface{}, error) { return nil, nil }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	waitFor(t, mock, func() bool { return len(mock.executors) == 2 })
+
+	// Functions already registered are not added again
+	mock.queue <- createTestProcess("echo", nil)
+	waitFor(t, mock, func() bool { return len(mock.closed) == 1 })
+	cancel()
+	assert.Nil(t, <-done)
+
+	mock.state(func() { assert.Equal(t, 1, mock.addFunctions) })
+}
+
+func TestExecutorShutdownUnassigns(t *testing.T) {
+	mock := newClientMock()
+	e := createTestExecutor(t, mock, Config{ShutdownTimeout: 50 * time.Millisecond})
+
+	started := make(chan struct{})
+	var returned atomic.Bool
+	assert.Nil(t, e.Register("sleep", "", nil, func(ctx *Context) ([]interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		returned.Store(true)
+		return nil, ctx.Err()
+	}))
+
+	process := createTestProcess("sleep", nil)
+	mock.queue <- process
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	<-started
+	cancel()
+	assert.Nil(t, <-done)
+
+	// The handler has returned before Run returns, and the process is not closed after being unassigned
+	assert.True(t, returned.Load())
+	mock.state(func() {
+		assert.Equal(t, []string{process.ID}, mock.unassigned)
+		assert.Len(t, mock.closed, 0)
+		assert.Len(t, mock.failed, 0)
+	})
+}
+
+func TestExecutorShutdownWaitsForRunningProcesses(t *testing.T) {
+	mock := newClientMock()
+	e := createTestExecutor(t, mock, Config{ShutdownTimeout: 5 * time.Second})
+
+	started := make(chan struct{})
+	assert.Nil(t, e.Register("work", "", nil, func(ctx *Context) ([]interface{}, error) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		return nil, nil
+	}))
+
+	process := createTestProcess("work", nil)
+	mock.queue <- process
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	<-started
+	cancel()
+	assert.Nil(t, <-done)
+
+	mock.state(func() {
+		assert.Len(t, mock.unassigned, 0)
+		assert.Contains(t, mock.closed, process.ID)
+	})
+}
+
+func TestExecutorHeartbeat(t *testing.T) {
+	mock := newClientMock()
+	e := createTestExecutor(t, mock, Config{HeartbeatInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	waitFor(t, mock, func() bool { return mock.heartbeats >= 2 })
+	cancel()
+	assert.Nil(t, <-done)
+}
+
+func TestContextBindAndLog(t *testing.T) {
+	mock := newClientMock()
+	process := createTestProcess("test", nil)
+	ctx := newContext(context.Background(), process, map[string]interface{}{"name": "test", "count": int64(3)}, mock, "")
+
+	var params struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	assert.Nil(t, ctx.Bind(&params))
+	assert.Equal(t, "test", params.Name)
+	assert.Equal(t, 3, params.Count)
+	assert.Equal(t, int64(3), ctx.Int("count"))
+	assert.Equal(t, "", ctx.String("missing"))
+
+	_, err := ctx.Write([]byte("line1\nline"))
+	assert.Nil(t, err)
+	_, err = ctx.Write([]byte("2\n"))
+	assert.Nil(t, err)
+	_, err = ctx.Write([]byte("partial"))
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.flushLog())
+	assert.Nil(t, ctx.Log("done %d", 1))
+
+	assert.Equal(t, []string{"line1\n", "line2\n", "partial", "done 1"}, mock.logs[process.ID])
+}
@@ -0,0 +1,261 @@
+package fs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/jedib0t/go-pretty/v6/progress"
+	log "github.com/sirupsen/logrus"
+)
+
+// Number of chunks transferred in parallel for a single file
+const chunkConcurrency = 8
+
+// chunkTransfers runs chunk transfers in parallel with bounded concurrency, the first error is kept
+type chunkTransfers struct {
+	wg    sync.WaitGroup
+	sem   chan struct{}
+	mutex sync.Mutex
+	err   error
+}
+
+func newChunkTransfers() *chunkTransfers {
+	return &chunkTransfers{sem: make(chan struct{}, chunkConcurrency)}
+}
+
+func (t *chunkTransfers) run(transfer func() error) {
+	t.sem <- struct{}{}
+	t.wg.Add(1)
+	go func() {
+		defer func() {
+			<-t.sem
+			t.wg.Done()
+		}()
+		if err := transfer(); err != nil {
+			t.mutex.Lock()
+			if t.err == nil {
+				t.err = err
+			}
+			t.mutex.Unlock()
+		}
+	}()
+}
+
+func (t *chunkTransfers) failed() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.err != nil
+}
+
+func (t *chunkTransfers) wait() error {
+	t.wg.Wait()
+	return t.err
+}
+
+// uploadChunks splits a file into content-defined chunks and uploads the chunks not already stored. Chunks
+// are named by their hash, so chunks are shared between files, labels and snapshots, and an interrupted
+// upload is resumed by uploading the file again.
+func (fsClient *FSClient) uploadChunks(dir string, filename string, tracker *progress.Tracker, quiet bool) ([]core.FileChunk, error) {
+	f, err := os.Open(dir + "/" + filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	transfers := newChunkTransfers()
+	seen := make(map[string]bool)
+	chunks := []core.FileChunk{}
+	c := newChunker(bufio.NewReader(f))
+	for !transfers.failed() {
+		data, err := c.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			transfers.wait()
+			return nil, err
+		}
+
+		hash := sha256.Sum256(data)
+		chunk := core.FileChunk{Hash: hex.EncodeToString(hash[:]), Size: int64(len(data))}
+		chunks = append(chunks, chunk)
+
+		object := core.ChunkObject(fsClient.colonyName, chunk.Hash)
+		if seen[chunk.Hash] || fsClient.driver.Exists(object) {
+			log.WithFields(log.Fields{"Filename": filename, "Chunk": chunk.Hash}).Debug("Skipping chunk, already stored")
+			if !quiet {
+				tracker.Increment(chunk.Size)
+			}
+			seen[chunk.Hash] = true
+			continue
+		}
+		seen[chunk.Hash] = true
+
+		data = append([]byte{}, data...) // The chunker reuses its buffer
+		transfers.run(func() error {
+			err := fsClient.driver.Put(object, bytes.NewReader(data), int64(len(data)))
+			if err == nil && !quiet {
+				tracker.Increment(int64(len(data)))
+			}
+			return err
+		})
+	}
+
+	if err := transfers.wait(); err != nil {
+		return nil, err
+	}
+
+	return chunks, nil
+}
+
+// downloadChunks downloads a chunked file to downloadDir. Chunks found in an existing local copy of the file
+// are reused, other chunks are downloaded in parallel and verified against their hash.
+func (fsClient *FSClient) downloadChunks(fileInfo *FileInfo, downloadDir string, tracker *progress.Tracker) error {
+	chunks := fileInfo.Chunks
+	if chunks == nil {
+		// Sync plans only tell if a file is chunked, the chunk index is part of the file
+		file, err := fsClient.coloniesClient.GetFileByID(fsClient.colonyName, fileInfo.FileID, fsClient.executorPrvKey)
+		if err != nil {
+			return err
+		}
+		if len(file) != 1 {
+			return errors.New("Failed to get file info")
+		}
+		chunks = file[0].Chunks
+	}
+
+	driver, err := fsClient.driverFor(fileInfo.Protocol)
+	if err != nil {
+		return err
+	}
+
+	localPath := downloadDir + "/" + fileInfo.Name
+	localChunks, err := indexLocalChunks(localPath)
+	if err != nil {
+		return err
+	}
+
+	var localFile *os.File
+	if len(localChunks) > 0 {
+		localFile, err = os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer localFile.Close()
+	}
+
+	tmpFile, err := os.CreateTemp(downloadDir, "."+fileInfo.Name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	transfers := newChunkTransfers()
+	offset := int64(0)
+	for _, chunk := range chunks {
+		chunk := chunk
+		chunkOffset := offset
+		offset += chunk.Size
+
+		if localOffset, ok := localChunks[chunk.Hash]; ok {
+			data := make([]byte, chunk.Size)
+			if _, err = localFile.ReadAt(data, localOffset); err != nil {
+				break
+			}
+			if _, err = tmpFile.WriteAt(data, chunkOffset); err != nil {
+				break
+			}
+			if !fsClient.Quiet {
+				tracker.Increment(chunk.Size)
+			}
+			continue
+		}
+
+		transfers.run(func() error {
+			data, err := getChunk(driver, fsClient.colonyName, chunk)
+			if err != nil {
+				return err
+			}
+			if _, err := tmpFile.WriteAt(data, chunkOffset); err != nil {
+				return err
+			}
+			if !fsClient.Quiet {
+				tracker.Increment(chunk.Size)
+			}
+			return nil
+		})
+	}
+
+	if waitErr := transfers.wait(); err == nil {
+		err = waitErr
+	}
+	if err == nil {
+		err = tmpFile.Truncate(offset)
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), localPath)
+}
+
+// getChunk downloads a chunk and verifies its size and hash
+func getChunk(driver StorageDriver, colonyName string, chunk core.FileChunk) ([]byte, error) {
+	object, err := driver.Get(core.ChunkObject(colonyName, chunk.Hash))
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, chunk.Size+1))
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	if int64(len(data)) != chunk.Size || hex.EncodeToString(hash[:]) != chunk.Hash {
+		return nil, errors.New("Chunk <" + chunk.Hash + "> is corrupt")
+	}
+
+	return data, nil
+}
+
+// indexLocalChunks returns the offsets of the chunks of a local file, or an empty index if the file does not exist
+func indexLocalChunks(path string) (map[string]int64, error) {
+	index := make(map[string]int64)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	offset := int64(0)
+	c := newChunker(bufio.NewReader(f))
+	for {
+		data, err := c.next()
+		if err == io.EOF {
+			return index, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		hash := sha256.Sum256(data)
+		index[hex.EncodeToString(hash[:])] = offset
+		offset += int64(len(data))
+	}
+}
@@ -0,0 +1,109 @@
+package fs
+
+import (
+	"io"
+)
+
+// Content-defined chunking using a gear rolling hash with normalized chunking (FastCDC). Chunk boundaries
+// depend on the content only, so an insertion or modification only changes the chunks around it.
+const (
+	minChunkSize = 256 * 1024
+	avgChunkSize = 1024 * 1024
+	maxChunkSize = 4 * 1024 * 1024
+
+	// The mask used before the average chunk size has more bits than the mask used after, which makes
+	// chunk sizes cluster around the average
+	chunkMaskS = uint64(1<<22-1) << (64 - 22)
+	chunkMaskL = uint64(1<<18-1) << (64 - 18)
+)
+
+var gearTable = createGearTable()
+
+// createGearTable creates the random values of the gear hash. The values must never change, since chunk
+// boundaries, and thereby deduplication, depend on them.
+func createGearTable() [256]uint64 {
+	var table [256]uint64
+	state := uint64(0x436f6c6f6e794653) // splitmix64
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+
+	return table
+}
+
+// cutPoint returns the length of the first chunk of data
+func cutPoint(data []byte) int {
+	n := len(data)
+	if n <= minChunkSize {
+		return n
+	}
+	if n > maxChunkSize {
+		n = maxChunkSize
+	}
+
+	normal := avgChunkSize
+	if normal > n {
+		normal = n
+	}
+
+	var hash uint64
+	i := minChunkSize
+	for ; i < normal; i++ {
+		hash = (hash << 1) + gearTable[data[i]]
+		if hash&chunkMaskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		hash = (hash << 1) + gearTable[data[i]]
+		if hash&chunkMaskL == 0 {
+			return i + 1
+		}
+	}
+
+	return n
+}
+
+type chunker struct {
+	src  io.Reader
+	buf  []byte
+	data []byte
+	eof  bool
+}
+
+func newChunker(src io.Reader) *chunker {
+	buf := make([]byte, 2*maxChunkSize)
+	return &chunker{src: src, buf: buf, data: buf[:0]}
+}
+
+// next returns the next chunk, or io.EOF when all chunks have been returned. The returned slice is only
+// valid until the next call.
+func (c *chunker) next() ([]byte, error) {
+	if len(c.data) < maxChunkSize && !c.eof {
+		// Move remaining data to the beginning of the buffer and fill it
+		c.data = c.buf[:copy(c.buf, c.data)]
+		for len(c.data) < maxChunkSize && !c.eof {
+			n, err := c.src.Read(c.buf[len(c.data):])
+			c.data = c.buf[:len(c.data)+n]
+			if err == io.EOF {
+				c.eof = true
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(c.data) == 0 {
+		return nil, io.EOF
+	}
+
+	cut := cutPoint(c.data)
+	chunk := c.data[:cut]
+	c.data = c.data[cut:]
+
+	return chunk, nil
+}
@@ -0,0 +1,152 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func chunkHashes(t *testing.T, data []byte) ([]string, []int) {
+	c := newChunker(bytes.NewReader(data))
+	var hashes []string
+	var sizes []int
+	for {
+		chunk, err := c.next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		hash := sha256.Sum256(chunk)
+		hashes = append(hashes, hex.EncodeToString(hash[:]))
+		sizes = append(sizes, len(chunk))
+	}
+
+	return hashes, sizes
+}
+
+func TestChunker(t *testing.T) {
+	data := randomBytes(t, 20*1024*1024)
+	hashes, sizes := chunkHashes(t, data)
+
+	total := 0
+	for i, size := range sizes {
+		total += size
+		assert.LessOrEqual(t, size, maxChunkSize)
+		if i < len(sizes)-1 {
+			assert.GreaterOrEqual(t, size, minChunkSize)
+		}
+	}
+	assert.Equal(t, len(data), total)
+	assert.Greater(t, len(hashes), 5)
+
+	// Chunking is deterministic
+	hashes2, _ := chunkHashes(t, data)
+	assert.Equal(t, hashes, hashes2)
+}
+
+func TestChunkerSmallFiles(t *testing.T) {
+	hashes, _ := chunkHashes(t, []byte{})
+	assert.Len(t, hashes, 0)
+
+	_, sizes := chunkHashes(t, []byte("testdata"))
+	assert.Equal(t, []int{8}, sizes)
+
+	_, sizes = chunkHashes(t, make([]byte, minChunkSize))
+	assert.Equal(t, []int{minChunkSize}, sizes)
+}
+
+func TestChunkerInsertion(t *testing.T) {
+	data := randomBytes(t, 20*1024*1024)
+	hashes, _ := chunkHashes(t, data)
+
+	// Insert a few bytes in the middle, only the chunks around the insertion should change
+	modified := append(append(append([]byte{}, data[:10*1024*1024]...), []byte("inserted")...), data[10*1024*1024:]...)
+	modifiedHashes, _ := chunkHashes(t, modified)
+
+	existing := make(map[string]bool)
+	for _, hash := range hashes {
+		existing[hash] = true
+	}
+	changed := 0
+	for _, hash := range modifiedHashes {
+		if !existing[hash] {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 2)
+}
+
+func TestChunkerNoBoundaries(t *testing.T) {
+	// Data without cut points is split at the maximum chunk size
+	_, sizes := chunkHashes(t, make([]byte, 3*maxChunkSize+10))
+	assert.Equal(t, []int{maxChunkSize, maxChunkSize, maxChunkSize, 10}, sizes)
+}
+
+// countingDriver counts the number of uploaded objects
+type countingDriver struct {
+	*MemDriver
+	mutex sync.Mutex
+	puts  int
+}
+
+func (driver *countingDriver) Put(object string, reader io.Reader, length int64) error {
+	driver.mutex.Lock()
+	driver.puts++
+	driver.mutex.Unlock()
+
+	return driver.MemDriver.Put(object, reader, length)
+}
+
+func TestUploadDownloadChunks(t *testing.T) {
+	driver := &countingDriver{MemDriver: CreateMemDriver()}
+	fsClient, err := CreateFSClientWithDriver(nil, "", "", driver)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	dir := t.TempDir()
+	data := randomBytes(t, 10*1024*1024)
+	assert.Nil(t, os.WriteFile(dir+"/file", data, 0644))
+
+	chunks, err := fsClient.uploadChunks(dir, "file", nil, true)
+	assert.Nil(t, err)
+	assert.Greater(t, len(chunks), 1)
+	assert.Equal(t, len(chunks), driver.puts)
+
+	// Only changed chunks are uploaded
+	modified := append([]byte{}, data...)
+	copy(modified[5*1024*1024:], []byte("modified"))
+	assert.Nil(t, os.WriteFile(dir+"/file", modified, 0644))
+	puts := driver.puts
+	modifiedChunks, err := fsClient.uploadChunks(dir, "file", nil, true)
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, driver.puts-puts, 2)
+
+	// Download to an empty directory
+	downloadDir := t.TempDir()
+	fileInfo := &FileInfo{Name: "file", Protocol: ProtocolMem, Chunked: true, Chunks: chunks}
+	assert.Nil(t, fsClient.downloadChunks(fileInfo, downloadDir, nil))
+	downloaded, err := os.ReadFile(downloadDir + "/file")
+	assert.Nil(t, err)
+	assert.Equal(t, data, downloaded)
+
+	// Update the downloaded file, unchanged chunks are reused from the local file
+	for _, chunk := range chunks {
+		assert.Nil(t, driver.Remove(core.ChunkObject(fsClient.colonyName, chunk.Hash)))
+	}
+	fileInfo.Chunks = modifiedChunks
+	assert.Nil(t, fsClient.downloadChunks(fileInfo, downloadDir, nil))
+	downloaded, err = os.ReadFile(downloadDir + "/file")
+	assert.Nil(t, err)
+	assert.Equal(t, modified, downloaded)
+
+	// Corrupt chunks are detected
+	assert.Nil(t, driver.MemDriver.Put(core.ChunkObject(fsClient.colonyName, modifiedChunks[0].Hash), bytes.NewReader([]byte("corrupt")), 7))
+	assert.NotNil(t, fsClient.downloadChunks(fileInfo, t.TempDir(), nil))
+}
@@ -0,0 +1,64 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const (
+	ProtocolS3   = "s3"
+	ProtocolFile = "file"
+	ProtocolHTTP = "http"
+	ProtocolMem  = "mem"
+)
+
+// StorageDriver stores the objects of ColonyFS files. The object name is stored in core.S3Object.Object of
+// the file reference, and core.Reference.Protocol selects the driver used to download or remove the file.
+type StorageDriver interface {
+	Protocol() string
+	// Describe sets the location fields of a file reference, e.g. the S3 endpoint and bucket
+	Describe(s3Object *core.S3Object)
+	Put(object string, reader io.Reader, length int64) error
+	// Get returns a reader of the content of an object, the reader must be closed by the caller
+	Get(object string) (io.ReadCloser, error)
+	Remove(object string) error
+	Exists(object string) bool
+}
+
+// ObjectLister is implemented by storage drivers that can list their objects. Only objects stored with drivers
+// that can be listed are swept by GC.
+type ObjectLister interface {
+	// List returns the objects with a name starting with prefix
+	List(prefix string) ([]*core.DriverObject, error)
+}
+
+// CreateStorageDriver creates a driver for a protocol configured using environmental variables,
+// AWS_S3_* for s3, COLONIES_FS_LOCAL_DIR for file and COLONIES_FS_HTTP_URL for http.
+// In-process mem drivers cannot be created this way since they are not shared between processes.
+func CreateStorageDriver(protocol string) (StorageDriver, error) {
+	switch protocol {
+	case ProtocolS3, "":
+		return CreateS3Client()
+	case ProtocolFile:
+		dir := os.Getenv("COLONIES_FS_LOCAL_DIR")
+		if dir == "" {
+			return nil, errors.New("COLONIES_FS_LOCAL_DIR must be set to use the file storage driver")
+		}
+		return CreateLocalDriver(dir)
+	case ProtocolHTTP:
+		baseURL := os.Getenv("COLONIES_FS_HTTP_URL")
+		if baseURL == "" {
+			return nil, errors.New("COLONIES_FS_HTTP_URL must be set to use the http storage driver")
+		}
+		driver := CreateHTTPDriver(baseURL)
+		if token := os.Getenv("COLONIES_FS_HTTP_TOKEN"); token != "" {
+			driver.Header.Set("Authorization", "Bearer "+token)
+		}
+		return driver, nil
+	default:
+		return nil, errors.New("Unsupported storage protocol <" + protocol + ">")
+	}
+}
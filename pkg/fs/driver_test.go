@@ -0,0 +1,204 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestHTTPServer creates an HTTP server storing objects in memory, objects can only be accessed with the token
+func createTestHTTPServer(token string) *httptest.Server {
+	var mutex sync.Mutex
+	objects := make(map[string][]byte)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet, http.MethodHead:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func testStorageDriver(t *testing.T, driver StorageDriver) {
+	content := randomBytes(t, 100*1024)
+	object := core.GenerateRandomID()
+
+	assert.False(t, driver.Exists(object))
+	_, err := driver.Get(object)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, driver.Put(object, bytes.NewReader(content), int64(len(content))))
+	assert.True(t, driver.Exists(object))
+
+	reader, err := driver.Get(object)
+	assert.Nil(t, err)
+	downloaded, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, content, downloaded)
+
+	emptyObject := core.GenerateRandomID()
+	assert.Nil(t, driver.Put(emptyObject, bytes.NewReader(nil), 0))
+	reader, err = driver.Get(emptyObject)
+	assert.Nil(t, err)
+	downloaded, err = io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Nil(t, reader.Close())
+	assert.Len(t, downloaded, 0)
+
+	assert.Nil(t, driver.Remove(object))
+	assert.False(t, driver.Exists(object))
+	_, err = driver.Get(object)
+	assert.NotNil(t, err)
+
+	// Removing a removed object is not an error
+	assert.Nil(t, driver.Remove(object))
+
+	s3Object := core.S3Object{Object: object}
+	driver.Describe(&s3Object)
+	assert.Equal(t, object, s3Object.Object)
+	assert.Equal(t, -1, s3Object.Port)
+}
+
+func testObjectLister(t *testing.T, driver StorageDriver, lister ObjectLister) {
+	prefix := core.StorageObjectPrefix("test_colony")
+	otherPrefix := core.StorageObjectPrefix("test_colony2")
+	assert.Nil(t, driver.Put(prefix+"object1", bytes.NewReader([]byte("data")), 4))
+	assert.Nil(t, driver.Put(prefix+"object2", bytes.NewReader([]byte("data2")), 5))
+	assert.Nil(t, driver.Put(otherPrefix+"object3", bytes.NewReader([]byte("data")), 4))
+
+	objects, err := lister.List(prefix)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 2)
+	sizes := make(map[string]int64)
+	for _, object := range objects {
+		assert.Equal(t, driver.Protocol(), object.Protocol)
+		assert.WithinDuration(t, time.Now(), object.Modified, time.Minute)
+		sizes[object.Object] = object.Size
+	}
+	assert.Equal(t, map[string]int64{prefix + "object1": 4, prefix + "object2": 5}, sizes)
+
+	objects, err = lister.List(otherPrefix)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, otherPrefix+"object3", objects[0].Object)
+}
+
+func TestLocalDriver(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "objects")
+	driver, err := CreateLocalDriver(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, ProtocolFile, driver.Protocol())
+
+	testStorageDriver(t, driver)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	for _, object := range []string{"", ".", ".."} {
+		assert.NotNil(t, driver.Put(object, bytes.NewReader([]byte("data")), 4), object)
+		_, err := driver.Get(object)
+		assert.NotNil(t, err, object)
+		assert.NotNil(t, driver.Remove(object), object)
+	}
+
+	// Object names with path separators are stored as a single file in the directory
+	for _, object := range []string{"../escape", `..\escape`, "dir/object"} {
+		assert.Nil(t, driver.Put(object, bytes.NewReader([]byte("data")), 4), object)
+		assert.True(t, driver.Exists(object), object)
+		assert.Nil(t, driver.Remove(object), object)
+	}
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escape"))
+	assert.True(t, os.IsNotExist(err))
+	entries, err = os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	testObjectLister(t, driver, driver)
+
+	// Size mismatch, e.g. a truncated upload, is not stored
+	assert.NotNil(t, driver.Put("truncated", bytes.NewReader([]byte("data")), 10))
+	assert.False(t, driver.Exists("truncated"))
+}
+
+func TestHTTPDriver(t *testing.T) {
+	ts := createTestHTTPServer("test_token")
+	defer ts.Close()
+
+	driver := CreateHTTPDriver(ts.URL + "/objects/")
+	driver.Header.Set("Authorization", "Bearer test_token")
+	assert.Equal(t, ProtocolHTTP, driver.Protocol())
+
+	testStorageDriver(t, driver)
+
+	s3Object := core.S3Object{}
+	driver.Describe(&s3Object)
+	assert.Equal(t, ts.URL+"/objects", s3Object.Server)
+
+	unauthorizedDriver := CreateHTTPDriver(ts.URL + "/objects")
+	err := unauthorizedDriver.Put("object", bytes.NewReader([]byte("data")), 4)
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "401"))
+}
+
+func TestMemDriver(t *testing.T) {
+	driver := CreateMemDriver()
+	assert.Equal(t, ProtocolMem, driver.Protocol())
+
+	testStorageDriver(t, driver)
+	testObjectLister(t, driver, driver)
+}
+
+func TestCreateStorageDriver(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("COLONIES_FS_LOCAL_DIR", dir)
+	t.Setenv("COLONIES_FS_HTTP_URL", "http://localhost:8080/objects")
+	t.Setenv("COLONIES_FS_HTTP_TOKEN", "test_token")
+
+	driver, err := CreateStorageDriver(ProtocolFile)
+	assert.Nil(t, err)
+	assert.Equal(t, dir, driver.(*LocalDriver).Dir)
+
+	driver, err = CreateStorageDriver(ProtocolHTTP)
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer test_token", driver.(*HTTPDriver).Header.Get("Authorization"))
+
+	_, err = CreateStorageDriver(ProtocolMem)
+	assert.NotNil(t, err)
+
+	t.Setenv("COLONIES_FS_LOCAL_DIR", "")
+	_, err = CreateStorageDriver(ProtocolFile)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,420 @@
+package fs
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// EncryptionAlg is stored in core.S3Object.EncryptionAlg for files encrypted by the FSClient.
+// The file is encrypted with a random data key using AES-256-GCM in chunks, and the data key is
+// wrapped with AES-256-GCM by a colony or label key and stored in core.S3Object.EncryptionKey.
+const EncryptionAlg = "AES-256-GCM-STREAM"
+
+const (
+	keySize            = 32
+	encryptedChunkSize = 64 * 1024
+	noncePrefixSize    = 7
+	tagSize            = 16
+)
+
+var encryptionMagic = []byte("CFS1")
+
+var encryptionHeaderSize = int64(len(encryptionMagic) + noncePrefixSize)
+
+// KeyRing holds the keys used to encrypt ColonyFS files. Files are encrypted with the key of the longest
+// matching label, or the colony key if no label key matches. Retired keys are only used for decryption,
+// so that files can still be downloaded and re-wrapped after a key has been rotated.
+type KeyRing struct {
+	colonyKey []byte
+	labelKeys map[string][]byte
+	keys      map[string][]byte
+}
+
+// KeyRingFile is the JSON format of a key ring file, keys are hex encoded 256-bit keys
+type KeyRingFile struct {
+	ColonyKey   string            `json:"colonykey,omitempty"`
+	LabelKeys   map[string]string `json:"labelkeys,omitempty"`
+	RetiredKeys []string          `json:"retiredkeys,omitempty"`
+}
+
+func CreateKeyRing() *KeyRing {
+	return &KeyRing{labelKeys: make(map[string][]byte), keys: make(map[string][]byte)}
+}
+
+// LoadKeyRing loads a key ring from a JSON file
+func LoadKeyRing(filename string) (*KeyRing, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyRingFile KeyRingFile
+	if err := json.Unmarshal(data, &keyRingFile); err != nil {
+		return nil, errors.New("Failed to parse key ring file " + filename + ": " + err.Error())
+	}
+
+	keyRing := CreateKeyRing()
+	if keyRingFile.ColonyKey != "" {
+		if err := keyRing.SetColonyKey(keyRingFile.ColonyKey); err != nil {
+			return nil, err
+		}
+	}
+	for label, key := range keyRingFile.LabelKeys {
+		if err := keyRing.SetLabelKey(label, key); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range keyRingFile.RetiredKeys {
+		if err := keyRing.AddRetiredKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return keyRing, nil
+}
+
+// GenerateKey generates a random hex encoded 256-bit key
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+func decodeKey(hexKey string) ([]byte, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != keySize {
+		return nil, errors.New("Invalid encryption key, expected 64 hex characters")
+	}
+
+	return key, nil
+}
+
+func keyID(key []byte) string {
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:8])
+}
+
+func normalizeLabel(label string) string {
+	return "/" + strings.Trim(label, "/")
+}
+
+// SetColonyKey sets the key used for files without a matching label key
+func (keyRing *KeyRing) SetColonyKey(hexKey string) error {
+	key, err := decodeKey(hexKey)
+	if err != nil {
+		return err
+	}
+	keyRing.colonyKey = key
+	keyRing.keys[keyID(key)] = key
+
+	return nil
+}
+
+// SetLabelKey sets the key used for files with the label, or a sub label of it
+func (keyRing *KeyRing) SetLabelKey(label string, hexKey string) error {
+	key, err := decodeKey(hexKey)
+	if err != nil {
+		return err
+	}
+	keyRing.labelKeys[normalizeLabel(label)] = key
+	keyRing.keys[keyID(key)] = key
+
+	return nil
+}
+
+// AddRetiredKey adds a key that is only used to decrypt files
+func (keyRing *KeyRing) AddRetiredKey(hexKey string) error {
+	key, err := decodeKey(hexKey)
+	if err != nil {
+		return err
+	}
+	keyRing.keys[keyID(key)] = key
+
+	return nil
+}
+
+// keyForLabel returns the key used to encrypt files with the label, or nil if files should not be encrypted
+func (keyRing *KeyRing) keyForLabel(label string) []byte {
+	if keyRing == nil {
+		return nil
+	}
+
+	label = normalizeLabel(label)
+	var match string
+	var key []byte
+	for l, k := range keyRing.labelKeys {
+		if (label == l || l == "/" || strings.HasPrefix(label, l+"/")) && len(l) >= len(match) {
+			match = l
+			key = k
+		}
+	}
+
+	if key != nil {
+		return key
+	}
+
+	return keyRing.colonyKey
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// wrapDataKey encrypts a data key with a key encryption key, the object name is authenticated so that
+// a wrapped key cannot be moved to another object
+func wrapDataKey(kek []byte, dataKey []byte, object string) (string, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	wrapped := gcm.Seal(nonce, nonce, dataKey, []byte(object))
+
+	return keyID(kek) + ":" + base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+// unwrapDataKey decrypts a data key wrapped by wrapDataKey using the matching key in the key ring
+func (keyRing *KeyRing) unwrapDataKey(encryptionKey string, object string) ([]byte, error) {
+	id, encoded, found := strings.Cut(encryptionKey, ":")
+	if !found {
+		return nil, errors.New("Invalid encryption key format")
+	}
+
+	var kek []byte
+	if keyRing != nil {
+		kek = keyRing.keys[id]
+	}
+	if kek == nil {
+		return nil, errors.New("File is encrypted with key <" + id + ">, which is not in the key ring")
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, errors.New("Invalid encryption key, too short")
+	}
+
+	dataKey, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], []byte(object))
+	if err != nil {
+		return nil, errors.New("Failed to unwrap data key, wrong key or corrupted encryption key")
+	}
+
+	return dataKey, nil
+}
+
+// rewrapDataKey unwraps a data key and wraps it again with the current key for the label. The returned
+// bool is false if the data key is already wrapped with the current key.
+func (keyRing *KeyRing) rewrapDataKey(encryptionKey string, object string, label string) (string, bool, error) {
+	kek := keyRing.keyForLabel(label)
+	if kek == nil {
+		return "", false, errors.New("No key for label <" + label + "> in the key ring")
+	}
+
+	if strings.HasPrefix(encryptionKey, keyID(kek)+":") {
+		return encryptionKey, false, nil
+	}
+
+	dataKey, err := keyRing.unwrapDataKey(encryptionKey, object)
+	if err != nil {
+		return "", false, err
+	}
+
+	wrapped, err := wrapDataKey(kek, dataKey, object)
+	if err != nil {
+		return "", false, err
+	}
+
+	return wrapped, true, nil
+}
+
+// encryptedSize returns the size of a file of the given size after encryption
+func encryptedSize(size int64) int64 {
+	chunks := (size + encryptedChunkSize - 1) / encryptedChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+
+	return encryptionHeaderSize + size + chunks*tagSize
+}
+
+// chunkNonce creates the nonce for a chunk, the last chunk is marked so that truncation is detected
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+
+	return nonce
+}
+
+type encryptReader struct {
+	src     *bufio.Reader
+	gcm     cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	outBuf  []byte
+	out     []byte
+	done    bool
+}
+
+// newEncryptReader returns a reader producing the encrypted content of src
+func newEncryptReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+
+	header := append(append([]byte{}, encryptionMagic...), prefix...)
+
+	return &encryptReader{
+		src:    bufio.NewReaderSize(src, encryptedChunkSize),
+		gcm:    gcm,
+		prefix: prefix,
+		buf:    make([]byte, encryptedChunkSize),
+		outBuf: make([]byte, 0, encryptedChunkSize+tagSize),
+		out:    header,
+	}, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := io.ReadFull(r.src, r.buf)
+		last := false
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			last = true
+		} else if err != nil {
+			return 0, err
+		} else if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		}
+
+		r.out = r.gcm.Seal(r.outBuf[:0], chunkNonce(r.prefix, r.counter, last), r.buf[:n], nil)
+		r.done = last
+		r.counter++
+		if r.counter == 0 && !last {
+			return 0, errors.New("File too large to encrypt")
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+
+	return n, nil
+}
+
+type decryptReader struct {
+	src     *bufio.Reader
+	gcm     cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	outBuf  []byte
+	out     []byte
+	done    bool
+}
+
+// newDecryptReader returns a reader producing the decrypted content of src, an error is returned by Read
+// if the content has been modified or truncated
+func newDecryptReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, encryptionHeaderSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return nil, errors.New("Failed to read encryption header, file is truncated")
+	}
+
+	if string(header[:len(encryptionMagic)]) != string(encryptionMagic) {
+		return nil, errors.New("Invalid encryption header")
+	}
+
+	return &decryptReader{
+		src:    bufio.NewReaderSize(src, encryptedChunkSize+tagSize),
+		gcm:    gcm,
+		prefix: header[len(encryptionMagic):],
+		buf:    make([]byte, encryptedChunkSize+tagSize),
+		outBuf: make([]byte, 0, encryptedChunkSize),
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		n, err := io.ReadFull(r.src, r.buf)
+		last := false
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			last = true
+		} else if err != nil {
+			return 0, err
+		} else if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		}
+
+		if n < tagSize {
+			return 0, errors.New("Failed to decrypt file, file is truncated")
+		}
+
+		out, err := r.gcm.Open(r.outBuf[:0], chunkNonce(r.prefix, r.counter, last), r.buf[:n], nil)
+		if err != nil {
+			return 0, errors.New("Failed to decrypt file, file has been modified or truncated")
+		}
+
+		r.out = out
+		r.done = last
+		r.counter++
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+
+	return n, nil
+}
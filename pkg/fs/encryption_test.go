@@ -0,0 +1,224 @@
+package fs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encryptBytes(t *testing.T, plaintext []byte, dataKey []byte) []byte {
+	reader, err := newEncryptReader(bytes.NewReader(plaintext), dataKey)
+	assert.Nil(t, err)
+	ciphertext, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+
+	return ciphertext
+}
+
+func decryptBytes(ciphertext []byte, dataKey []byte) ([]byte, error) {
+	reader, err := newDecryptReader(bytes.NewReader(ciphertext), dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	buf := make([]byte, size)
+	_, err := rand.Read(buf)
+	assert.Nil(t, err)
+
+	return buf
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	dataKey := randomBytes(t, keySize)
+
+	for _, size := range []int{0, 1, encryptedChunkSize - 1, encryptedChunkSize, encryptedChunkSize + 1, 200 * 1024} {
+		plaintext := randomBytes(t, size)
+		ciphertext := encryptBytes(t, plaintext, dataKey)
+		assert.Equal(t, encryptedSize(int64(size)), int64(len(ciphertext)))
+
+		decrypted, err := decryptBytes(ciphertext, dataKey)
+		assert.Nil(t, err)
+		assert.True(t, bytes.Equal(plaintext, decrypted))
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	ciphertext := encryptBytes(t, []byte("secret"), randomBytes(t, keySize))
+
+	_, err := decryptBytes(ciphertext, randomBytes(t, keySize))
+	assert.NotNil(t, err)
+}
+
+func TestDecryptModified(t *testing.T) {
+	dataKey := randomBytes(t, keySize)
+	ciphertext := encryptBytes(t, randomBytes(t, 200*1024), dataKey)
+
+	modified := append([]byte{}, ciphertext...)
+	modified[encryptionHeaderSize+encryptedChunkSize+100] ^= 1
+	_, err := decryptBytes(modified, dataKey)
+	assert.NotNil(t, err)
+
+	modified = append([]byte{}, ciphertext...)
+	modified[0] = 'X'
+	_, err = decryptBytes(modified, dataKey)
+	assert.NotNil(t, err)
+
+	// Appended data
+	_, err = decryptBytes(append(append([]byte{}, ciphertext...), 0), dataKey)
+	assert.NotNil(t, err)
+}
+
+func TestDecryptTruncated(t *testing.T) {
+	dataKey := randomBytes(t, keySize)
+	ciphertext := encryptBytes(t, randomBytes(t, 3*encryptedChunkSize), dataKey)
+
+	// Remove the last chunk, the remaining chunks are valid but the last chunk marker is missing
+	_, err := decryptBytes(ciphertext[:len(ciphertext)-encryptedChunkSize-tagSize], dataKey)
+	assert.NotNil(t, err)
+
+	_, err = decryptBytes(ciphertext[:len(ciphertext)-1], dataKey)
+	assert.NotNil(t, err)
+
+	_, err = decryptBytes(ciphertext[:5], dataKey)
+	assert.NotNil(t, err)
+}
+
+func TestWrapUnwrapDataKey(t *testing.T) {
+	colonyKey, err := GenerateKey()
+	assert.Nil(t, err)
+
+	keyRing := CreateKeyRing()
+	assert.Nil(t, keyRing.SetColonyKey(colonyKey))
+
+	dataKey := randomBytes(t, keySize)
+	wrapped, err := wrapDataKey(keyRing.keyForLabel("/data"), dataKey, "object1")
+	assert.Nil(t, err)
+
+	unwrapped, err := keyRing.unwrapDataKey(wrapped, "object1")
+	assert.Nil(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The wrapped key is bound to the object
+	_, err = keyRing.unwrapDataKey(wrapped, "object2")
+	assert.NotNil(t, err)
+
+	// Key not in key ring
+	_, err = CreateKeyRing().unwrapDataKey(wrapped, "object1")
+	assert.NotNil(t, err)
+
+	var nilKeyRing *KeyRing
+	_, err = nilKeyRing.unwrapDataKey(wrapped, "object1")
+	assert.NotNil(t, err)
+
+	_, err = keyRing.unwrapDataKey("invalid", "object1")
+	assert.NotNil(t, err)
+}
+
+func TestKeyForLabel(t *testing.T) {
+	var nilKeyRing *KeyRing
+	assert.Nil(t, nilKeyRing.keyForLabel("/data"))
+
+	keyRing := CreateKeyRing()
+	assert.Nil(t, keyRing.keyForLabel("/data"))
+
+	colonyKey, _ := GenerateKey()
+	dataKey, _ := GenerateKey()
+	secretKey, _ := GenerateKey()
+	assert.Nil(t, keyRing.SetColonyKey(colonyKey))
+	assert.Nil(t, keyRing.SetLabelKey("/data", dataKey))
+	assert.Nil(t, keyRing.SetLabelKey("/data/secret/", secretKey))
+
+	decoded := func(hexKey string) []byte {
+		key, err := decodeKey(hexKey)
+		assert.Nil(t, err)
+		return key
+	}
+
+	assert.Equal(t, decoded(colonyKey), keyRing.keyForLabel("/other"))
+	assert.Equal(t, decoded(colonyKey), keyRing.keyForLabel("/database"))
+	assert.Equal(t, decoded(dataKey), keyRing.keyForLabel("/data"))
+	assert.Equal(t, decoded(dataKey), keyRing.keyForLabel("data/images"))
+	assert.Equal(t, decoded(secretKey), keyRing.keyForLabel("/data/secret"))
+	assert.Equal(t, decoded(secretKey), keyRing.keyForLabel("/data/secret/more"))
+
+	assert.NotNil(t, keyRing.SetColonyKey("invalid"))
+	assert.NotNil(t, keyRing.SetLabelKey("/data", "abcd"))
+}
+
+func TestRewrapDataKey(t *testing.T) {
+	oldKey, _ := GenerateKey()
+	newKey, _ := GenerateKey()
+
+	oldKeyRing := CreateKeyRing()
+	assert.Nil(t, oldKeyRing.SetColonyKey(oldKey))
+
+	dataKey := randomBytes(t, keySize)
+	wrapped, err := wrapDataKey(oldKeyRing.keyForLabel("/data"), dataKey, "object1")
+	assert.Nil(t, err)
+
+	_, rotated, err := oldKeyRing.rewrapDataKey(wrapped, "object1", "/data")
+	assert.Nil(t, err)
+	assert.False(t, rotated)
+
+	// The old key is required to rotate
+	keyRing := CreateKeyRing()
+	assert.Nil(t, keyRing.SetColonyKey(newKey))
+	_, _, err = keyRing.rewrapDataKey(wrapped, "object1", "/data")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, keyRing.AddRetiredKey(oldKey))
+	rewrapped, rotated, err := keyRing.rewrapDataKey(wrapped, "object1", "/data")
+	assert.Nil(t, err)
+	assert.True(t, rotated)
+	assert.True(t, strings.HasPrefix(rewrapped, keyID(keyRing.keyForLabel("/data"))+":"))
+
+	unwrapped, err := keyRing.unwrapDataKey(rewrapped, "object1")
+	assert.Nil(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The retired key is no longer needed
+	newKeyRing := CreateKeyRing()
+	assert.Nil(t, newKeyRing.SetColonyKey(newKey))
+	unwrapped, err = newKeyRing.unwrapDataKey(rewrapped, "object1")
+	assert.Nil(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+}
+
+func TestLoadKeyRing(t *testing.T) {
+	colonyKey, _ := GenerateKey()
+	labelKey, _ := GenerateKey()
+	retiredKey, _ := GenerateKey()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "keyring.json")
+	keyRingJSON := `{"colonykey":"` + colonyKey + `","labelkeys":{"/data":"` + labelKey + `"},"retiredkeys":["` + retiredKey + `"]}`
+	assert.Nil(t, os.WriteFile(filename, []byte(keyRingJSON), 0600))
+
+	keyRing, err := LoadKeyRing(filename)
+	assert.Nil(t, err)
+	assert.Len(t, keyRing.keys, 3)
+
+	key, _ := decodeKey(labelKey)
+	assert.Equal(t, key, keyRing.keyForLabel("/data/images"))
+
+	assert.Nil(t, os.WriteFile(filename, []byte(`{"colonykey":"invalid"}`), 0600))
+	_, err = LoadKeyRing(filename)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.WriteFile(filename, []byte(`invalid json`), 0600))
+	_, err = LoadKeyRing(filename)
+	assert.NotNil(t, err)
+
+	_, err = LoadKeyRing(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,8 @@
 package fs
 
 import (
+	"bufio"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -10,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
@@ -23,15 +26,34 @@ type FSClient struct {
 	colonyName     string
 	executorPrvKey string
 	s3Client       *S3Client
+	driver         StorageDriver
+	drivers        map[string]StorageDriver
+	driversMutex   sync.Mutex
+	backend        string
+	keyRing        *KeyRing
 	Quiet          bool
+	// Chunking stores new files as content-defined chunks, so that only changed chunks are uploaded. Files
+	// are not chunked when they are encrypted or stored in a storage backend.
+	Chunking bool
+	// ProcessID is set by executors syncing the sync dirs of a process assigned to them, files uploaded are
+	// recorded as outputs of the process
+	ProcessID string
 }
 
 type FileInfo struct {
-	Name       string
-	Checksum   string
-	Size       int64
-	S3Filename string
-	Dir        bool
+	FileID        string
+	Protocol      string
+	Backend       string
+	Name          string
+	Checksum      string
+	Size          int64
+	S3Filename    string
+	EncryptionKey string
+	EncryptionAlg string
+	Chunked       bool
+	Chunks        []core.FileChunk
+	Shared        bool
+	Dir           bool
 }
 
 type SyncPlan struct {
@@ -64,10 +86,83 @@ func CreateFSClient(coloniesClient *client.ColoniesClient, colonyName string, ex
 		return nil, err
 	}
 	fsClient.s3Client = s3Client
+	fsClient.driver = s3Client
+	fsClient.drivers = map[string]StorageDriver{ProtocolS3: s3Client}
 
 	return fsClient, nil
 }
 
+// CreateFSClientWithDriver creates an FSClient that stores new files using driver. Files stored using other
+// protocols are downloaded using drivers added with AddDriver, or drivers created by CreateStorageDriver.
+func CreateFSClientWithDriver(coloniesClient *client.ColoniesClient, colonyName string, executorPrvKey string, driver StorageDriver) (*FSClient, error) {
+	if driver == nil {
+		return nil, errors.New("Storage driver must be specified")
+	}
+
+	fsClient := &FSClient{}
+	fsClient.coloniesClient = coloniesClient
+	fsClient.colonyName = colonyName
+	fsClient.executorPrvKey = executorPrvKey
+	fsClient.driver = driver
+	fsClient.drivers = map[string]StorageDriver{driver.Protocol(): driver}
+
+	return fsClient, nil
+}
+
+// CreateFSClientWithBackend creates an FSClient that stores new files in a storage backend registered in the
+// colony. No S3 credentials are needed, objects are transferred using presigned URLs obtained from the server.
+func CreateFSClientWithBackend(coloniesClient *client.ColoniesClient, colonyName string, executorPrvKey string, backend string) (*FSClient, error) {
+	if backend == "" {
+		return nil, errors.New("Storage backend name must be specified")
+	}
+
+	fsClient := &FSClient{}
+	fsClient.coloniesClient = coloniesClient
+	fsClient.colonyName = colonyName
+	fsClient.executorPrvKey = executorPrvKey
+	fsClient.backend = backend
+	fsClient.drivers = make(map[string]StorageDriver)
+
+	return fsClient, nil
+}
+
+// AddDriver adds a driver used to download and remove files stored using its protocol
+func (fsClient *FSClient) AddDriver(driver StorageDriver) {
+	fsClient.driversMutex.Lock()
+	defer fsClient.driversMutex.Unlock()
+
+	fsClient.drivers[driver.Protocol()] = driver
+}
+
+// driverFor returns the driver of a protocol, a driver is created using CreateStorageDriver if none has
+// been added. Files added before protocols were introduced have an empty protocol and are stored in S3.
+func (fsClient *FSClient) driverFor(protocol string) (StorageDriver, error) {
+	if protocol == "" {
+		protocol = ProtocolS3
+	}
+
+	fsClient.driversMutex.Lock()
+	defer fsClient.driversMutex.Unlock()
+
+	if driver, ok := fsClient.drivers[protocol]; ok {
+		return driver, nil
+	}
+
+	driver, err := CreateStorageDriver(protocol)
+	if err != nil {
+		return nil, err
+	}
+	fsClient.drivers[protocol] = driver
+
+	return driver, nil
+}
+
+// SetKeyRing enables client-side encryption, uploaded files are encrypted if the key ring has a key for
+// their label, and encrypted files are decrypted and verified when downloaded
+func (fsClient *FSClient) SetKeyRing(keyRing *KeyRing) {
+	fsClient.keyRing = keyRing
+}
+
 func checksum(filePath string) (string, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -89,19 +184,41 @@ func (fsClient *FSClient) uploadFile(syncPlan *SyncPlan, fileInfo *FileInfo, tra
 	if err != nil {
 		return err
 	}
-	s3Object := core.S3Object{
-		Server:        fsClient.s3Client.Endpoint,
-		Port:          -1,
-		TLS:           fsClient.s3Client.TLS,
-		AccessKey:     fsClient.s3Client.AccessKey,
-		SecretKey:     fsClient.s3Client.SecretKey,
-		Region:        fsClient.s3Client.Region,
-		EncryptionKey: "",
-		EncryptionAlg: "",
-		Object:        core.GenerateRandomID(),
-		Bucket:        fsClient.s3Client.BucketName,
-	}
-	ref := core.Reference{Protocol: "s3", S3Object: s3Object}
+
+	var s3Object core.S3Object
+	var presignedURL *core.PresignedURL
+	protocol := ProtocolS3
+	if fsClient.backend != "" {
+		presignedURL, err = fsClient.coloniesClient.PresignFile(fsClient.colonyName, core.PresignPut, "", fsClient.backend, fsClient.executorPrvKey)
+		if err != nil {
+			return err
+		}
+		s3Object = core.S3Object{Port: -1, Object: presignedURL.Object}
+	} else {
+		s3Object = core.S3Object{Object: core.StorageObjectPrefix(fsClient.colonyName) + core.GenerateRandomID()}
+		fsClient.driver.Describe(&s3Object)
+		protocol = fsClient.driver.Protocol()
+	}
+
+	kek := fsClient.keyRing.keyForLabel(syncPlan.Label)
+	if fsClient.Chunking && fsClient.backend == "" && kek == nil {
+		return fsClient.uploadChunkedFile(syncPlan, fileInfo, fileStat.Size(), tracker, quite)
+	}
+
+	var dataKey []byte
+	if kek != nil {
+		dataKey = make([]byte, keySize)
+		if _, err := rand.Read(dataKey); err != nil {
+			return err
+		}
+		s3Object.EncryptionKey, err = wrapDataKey(kek, dataKey, s3Object.Object)
+		if err != nil {
+			return err
+		}
+		s3Object.EncryptionAlg = EncryptionAlg
+	}
+
+	ref := core.Reference{Protocol: protocol, Backend: fsClient.backend, S3Object: s3Object}
 	coloniesFile := &core.File{
 		ColonyName:  fsClient.colonyName,
 		Label:       syncPlan.Label,
@@ -111,19 +228,176 @@ func (fsClient *FSClient) uploadFile(syncPlan *SyncPlan, fileInfo *FileInfo, tra
 		ChecksumAlg: "SHA256",
 		Reference:   ref}
 
-	if coloniesFile.Size > 0 {
-		err = fsClient.s3Client.Upload(syncPlan.Dir, coloniesFile.Name, coloniesFile.Reference.S3Object.Object, coloniesFile.Size, tracker, quite)
+	put := func(reader io.Reader, length int64) error {
+		if presignedURL != nil {
+			return putPresigned(presignedURL.URL, reader, length)
+		}
+		return fsClient.driver.Put(s3Object.Object, reader, length)
+	}
+
+	err = fsClient.uploadObject(syncPlan.Dir, coloniesFile.Name, coloniesFile.Size, dataKey, put, tracker, quite)
+	if err != nil {
+		return err
+	}
+
+	_, err = fsClient.coloniesClient.AddProcessFile(coloniesFile, fsClient.ProcessID, fsClient.executorPrvKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (fsClient *FSClient) uploadChunkedFile(syncPlan *SyncPlan, fileInfo *FileInfo, size int64, tracker *progress.Tracker, quiet bool) error {
+	chunks, err := fsClient.uploadChunks(syncPlan.Dir, fileInfo.Name, tracker, quiet)
+	if err != nil {
+		return err
+	}
+
+	s3Object := core.S3Object{}
+	fsClient.driver.Describe(&s3Object)
+	coloniesFile := &core.File{
+		ColonyName:  fsClient.colonyName,
+		Label:       syncPlan.Label,
+		Name:        fileInfo.Name,
+		Size:        size,
+		Checksum:    fileInfo.Checksum,
+		ChecksumAlg: "SHA256",
+		Reference:   core.Reference{Protocol: fsClient.driver.Protocol(), S3Object: s3Object},
+		Chunks:      chunks}
+
+	_, err = fsClient.coloniesClient.AddProcessFile(coloniesFile, fsClient.ProcessID, fsClient.executorPrvKey)
+	return err
+}
+
+// uploadObject reads a file and passes its content to put, the file is encrypted if dataKey is not nil
+func (fsClient *FSClient) uploadObject(dir string, filename string, size int64, dataKey []byte, put func(reader io.Reader, length int64) error, tracker *progress.Tracker, quiet bool) error {
+	f, err := os.Open(dir + "/" + filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var reader io.Reader = bufio.NewReader(f)
+	if !quiet {
+		reader = io.TeeReader(reader, &ProgressWriter{tracker: tracker})
+	}
+
+	if dataKey == nil {
+		return put(reader, size)
+	}
+
+	encryptedReader, err := newEncryptReader(reader, dataKey)
+	if err != nil {
+		return err
+	}
+
+	return put(encryptedReader, encryptedSize(size))
+}
+
+// getObject returns a reader of the content of a file, files stored in a storage backend are read using
+// a presigned URL, other files using the driver of their protocol
+func (fsClient *FSClient) getObject(fileInfo *FileInfo) (io.ReadCloser, error) {
+	if fileInfo.Backend != "" {
+		presignedURL, err := fsClient.coloniesClient.PresignFile(fsClient.colonyName, core.PresignGet, fileInfo.FileID, "", fsClient.executorPrvKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return getPresigned(presignedURL.URL)
+	}
+
+	driver, err := fsClient.driverFor(fileInfo.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Get(fileInfo.S3Filename)
+}
+
+// removeObject removes the object of a file, it must be called before the file is removed from the server.
+// The chunks of chunked files and the objects of shared files, e.g. files restored or cloned from a snapshot,
+// are not removed since they may be referenced by other files, they are removed by GC instead.
+func (fsClient *FSClient) removeObject(fileInfo *FileInfo) error {
+	if fileInfo.Chunked || fileInfo.Shared {
+		return nil
+	}
+
+	if fileInfo.Backend != "" {
+		presignedURL, err := fsClient.coloniesClient.PresignFile(fsClient.colonyName, core.PresignDelete, fileInfo.FileID, "", fsClient.executorPrvKey)
 		if err != nil {
 			return err
 		}
+
+		return deletePresigned(presignedURL.URL)
 	}
 
-	_, err = fsClient.coloniesClient.AddFile(coloniesFile, fsClient.executorPrvKey)
+	driver, err := fsClient.driverFor(fileInfo.Protocol)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return driver.Remove(fileInfo.S3Filename)
+}
+
+// downloadFile downloads a file to downloadDir, encrypted files are decrypted and verified. Files are written
+// to a temporary file first, so that a partially downloaded file or a file that fails verification is never
+// left in downloadDir.
+func (fsClient *FSClient) downloadFile(fileInfo *FileInfo, downloadDir string, tracker *progress.Tracker) error {
+	if fileInfo.Chunked {
+		return fsClient.downloadChunks(fileInfo, downloadDir, tracker)
+	}
+
+	var dataKey []byte
+	if fileInfo.EncryptionAlg != "" {
+		if fileInfo.EncryptionAlg != EncryptionAlg {
+			return errors.New("File <" + fileInfo.Name + "> is encrypted with unsupported algorithm <" + fileInfo.EncryptionAlg + ">")
+		}
+
+		var err error
+		dataKey, err = fsClient.keyRing.unwrapDataKey(fileInfo.EncryptionKey, fileInfo.S3Filename)
+		if err != nil {
+			return errors.New("Failed to decrypt <" + fileInfo.Name + ">, " + err.Error())
+		}
+	}
+
+	object, err := fsClient.getObject(fileInfo)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	var reader io.Reader = object
+	if dataKey != nil {
+		reader, err = newDecryptReader(object, dataKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	tmpFile, err := os.CreateTemp(downloadDir, "."+fileInfo.Name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	var writer io.Writer = tmpFile
+	if !fsClient.Quiet {
+		writer = io.MultiWriter(tmpFile, &ProgressWriter{tracker: tracker})
+	}
+
+	_, err = io.Copy(writer, reader)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if dataKey != nil {
+			return errors.New("Failed to decrypt <" + fileInfo.Name + ">, " + err.Error())
+		}
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), downloadDir+"/"+fileInfo.Name)
 }
 
 func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
@@ -229,7 +503,7 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 		errChan := pool.Call(func(arg interface{}) error {
 			f := arg.(*FileInfo)
 			if f.Size > 0 {
-				return fsClient.s3Client.Download(f.Name, f.S3Filename, syncPlan.Dir, &downloadTracker, fsClient.Quiet)
+				return fsClient.downloadFile(f, syncPlan.Dir, &downloadTracker)
 			} else {
 				file, err := os.Create(syncPlan.Dir + "/" + f.Name)
 				if err != nil {
@@ -262,7 +536,7 @@ func (fsClient *FSClient) ApplySyncPlan(syncPlan *SyncPlan) error {
 		for _, fileInfo := range syncPlan.Conflicts {
 			errChan := pool.Call(func(arg interface{}) error {
 				f := arg.(*FileInfo)
-				return fsClient.s3Client.Download(f.Name, f.S3Filename, syncPlan.Dir, &conflictTracker, fsClient.Quiet)
+				return fsClient.downloadFile(f, syncPlan.Dir, &conflictTracker)
 			}, fileInfo)
 			go func() {
 				err := <-errChan
@@ -413,11 +687,13 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 	var remoteFileMap = make(map[string]string)
 	var remoteS3FilenameMap = make(map[string]string)
 	var remoteFileSizeMap = make(map[string]int64)
+	var remoteFileDataMap = make(map[string]*core.FileData)
 
 	for _, remoteFileData := range remoteFileDataArr {
 		remoteFileMap[remoteFileData.Name] = remoteFileData.Checksum
 		remoteFileSizeMap[remoteFileData.Name] = remoteFileData.Size
 		remoteS3FilenameMap[remoteFileData.Name] = remoteFileData.S3Filename
+		remoteFileDataMap[remoteFileData.Name] = remoteFileData
 	}
 
 	var localFileMap = make(map[string]string)
@@ -467,7 +743,8 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 			// File missing locally
 			size := remoteFileSizeMap[filename]
 			s3Filename := remoteS3FilenameMap[filename]
-			localMissing = append(localMissing, &FileInfo{Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename})
+			fileData := remoteFileDataMap[filename]
+			localMissing = append(localMissing, &FileInfo{FileID: fileData.FileID, Protocol: fileData.Protocol, Backend: fileData.Backend, Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename, EncryptionKey: fileData.EncryptionKey, EncryptionAlg: fileData.EncryptionAlg, Chunked: fileData.Chunked, Shared: fileData.Shared})
 		}
 	}
 
@@ -485,7 +762,8 @@ func (fsClient *FSClient) CalcSyncPlan(dir string, label string, keepLocal bool)
 				} else {
 					size := remoteFileSizeMap[filename]
 					s3Filename := remoteS3FilenameMap[filename]
-					conflicts = append(conflicts, &FileInfo{Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename})
+					fileData := remoteFileDataMap[filename]
+					conflicts = append(conflicts, &FileInfo{FileID: fileData.FileID, Protocol: fileData.Protocol, Backend: fileData.Backend, Name: filename, Checksum: checksum, Size: size, S3Filename: s3Filename, EncryptionKey: fileData.EncryptionKey, EncryptionAlg: fileData.EncryptionAlg, Chunked: fileData.Chunked, Shared: fileData.Shared})
 				}
 			}
 		}
@@ -698,7 +976,7 @@ func (fsClient *FSClient) Download(colonyName string, fileID string, downloadDir
 		downloadTracker.Start()
 	}
 
-	err = fsClient.s3Client.Download(file[0].Name, file[0].Reference.S3Object.Object, downloadDir, &downloadTracker, fsClient.Quiet)
+	err = fsClient.downloadFile(fileInfoFromFile(file[0]), downloadDir, &downloadTracker)
 
 	if !fsClient.Quiet {
 		for {
@@ -713,6 +991,23 @@ func (fsClient *FSClient) Download(colonyName string, fileID string, downloadDir
 	return err
 }
 
+func fileInfoFromFile(file *core.File) *FileInfo {
+	return &FileInfo{
+		FileID:        file.ID,
+		Protocol:      file.Reference.Protocol,
+		Backend:       file.Reference.Backend,
+		Name:          file.Name,
+		Checksum:      file.Checksum,
+		Size:          file.Size,
+		S3Filename:    file.Reference.S3Object.Object,
+		EncryptionKey: file.Reference.S3Object.EncryptionKey,
+		EncryptionAlg: file.Reference.S3Object.EncryptionAlg,
+		Chunked:       len(file.Chunks) > 0,
+		Chunks:        file.Chunks,
+		Shared:        file.Shared,
+	}
+}
+
 func (fsClient *FSClient) RemoveFileByID(colonyName string, fileID string) error {
 	file, err := fsClient.coloniesClient.GetFileByID(colonyName, fileID, fsClient.executorPrvKey)
 	if err != nil {
@@ -723,7 +1018,7 @@ func (fsClient *FSClient) RemoveFileByID(colonyName string, fileID string) error
 		return errors.New("Failed to get file info")
 	}
 
-	err = fsClient.s3Client.Remove(file[0].Reference.S3Object.Object)
+	err = fsClient.removeObject(fileInfoFromFile(file[0]))
 	if err != nil {
 		return err
 	}
@@ -738,7 +1033,7 @@ func (fsClient *FSClient) RemoveFileByName(colonyName string, label string, name
 	}
 
 	for _, revision := range file {
-		err = fsClient.s3Client.Remove(revision.Reference.S3Object.Object)
+		err = fsClient.removeObject(fileInfoFromFile(revision))
 		if err != nil {
 			return err
 		}
@@ -804,22 +1099,21 @@ func (fsClient *FSClient) RemoveAllFilesWithLabel(label string) error {
 	pool := utils.NewWorkerPool(50).Start()
 
 	type w struct {
-		l          string
-		filename   string
-		s3Filename string
+		l        string
+		fileInfo *FileInfo
 	}
 
 	for l, fileDataArr := range allFileDataArr {
 		for _, fileData := range fileDataArr {
 			errChan := pool.Call(func(arg interface{}) error {
 				w := arg.(w)
-				log.WithFields(log.Fields{"S3Filename": w.filename, "BucketName": fsClient.s3Client.BucketName}).Debug("Removing file from S3")
-				err := fsClient.s3Client.Remove(w.s3Filename)
+				log.WithFields(log.Fields{"S3Filename": w.fileInfo.S3Filename, "Backend": w.fileInfo.Backend}).Debug("Removing file from storage")
+				err := fsClient.removeObject(w.fileInfo)
 				if err != nil {
 					return err
 				}
-				log.WithFields(log.Fields{"ColonyName": fsClient.colonyName, "Filename": w.filename}).Debug("Remove file from Colonies FS")
-				err = fsClient.coloniesClient.RemoveFileByName(fsClient.colonyName, w.l, w.filename, fsClient.executorPrvKey)
+				log.WithFields(log.Fields{"ColonyName": fsClient.colonyName, "Filename": w.fileInfo.Name}).Debug("Remove file from Colonies FS")
+				err = fsClient.coloniesClient.RemoveFileByName(fsClient.colonyName, w.l, w.fileInfo.Name, fsClient.executorPrvKey)
 				if err != nil {
 					return err
 				}
@@ -827,7 +1121,7 @@ func (fsClient *FSClient) RemoveAllFilesWithLabel(label string) error {
 					removeTracker.Increment(int64(1))
 				}
 				return nil
-			}, w{l: l.Name, filename: fileData.Name, s3Filename: fileData.S3Filename})
+			}, w{l: l.Name, fileInfo: &FileInfo{FileID: fileData.FileID, Protocol: fileData.Protocol, Backend: fileData.Backend, Name: fileData.Name, S3Filename: fileData.S3Filename, Chunked: fileData.Chunked, Shared: fileData.Shared}})
 			go func() {
 				err := <-errChan
 				aggErrChan <- err
@@ -871,6 +1165,59 @@ O:
 	return nil
 }
 
+// RotateKeys re-wraps the data keys of all encrypted files with the label, or a sub label of it, using the
+// current key in the key ring. The old keys must be in the key ring, e.g. as retired keys. File content is
+// not downloaded or uploaded again. The number of updated file revisions is returned.
+func (fsClient *FSClient) RotateKeys(label string) (int, error) {
+	if fsClient.keyRing == nil {
+		return 0, errors.New("No key ring has been set")
+	}
+
+	labels, err := fsClient.coloniesClient.GetFileLabelsByName(fsClient.colonyName, label, false, fsClient.executorPrvKey)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := 0
+	for _, l := range labels {
+		fileDataArr, err := fsClient.coloniesClient.GetFileData(fsClient.colonyName, l.Name, fsClient.executorPrvKey)
+		if err != nil {
+			return counter, err
+		}
+
+		for _, fileData := range fileDataArr {
+			revisions, err := fsClient.coloniesClient.GetFileByName(fsClient.colonyName, l.Name, fileData.Name, fsClient.executorPrvKey)
+			if err != nil {
+				return counter, err
+			}
+
+			for _, revision := range revisions {
+				s3Object := revision.Reference.S3Object
+				if s3Object.EncryptionAlg == "" {
+					continue
+				}
+
+				encryptionKey, rotated, err := fsClient.keyRing.rewrapDataKey(s3Object.EncryptionKey, s3Object.Object, l.Name)
+				if err != nil {
+					return counter, errors.New("Failed to rotate key of <" + l.Name + "/" + revision.Name + ">, " + err.Error())
+				}
+				if !rotated {
+					continue
+				}
+
+				log.WithFields(log.Fields{"Label": l.Name, "Filename": revision.Name, "FileID": revision.ID}).Debug("Rotating file encryption key")
+				err = fsClient.coloniesClient.UpdateFileEncryptionKey(fsClient.colonyName, revision.ID, encryptionKey, fsClient.executorPrvKey)
+				if err != nil {
+					return counter, err
+				}
+				counter++
+			}
+		}
+	}
+
+	return counter, nil
+}
+
 func (fsClient *FSClient) DownloadSnapshot(snapshotID string, downloadDir string) error {
 	snapshot, err := fsClient.coloniesClient.GetSnapshotByID(fsClient.colonyName, snapshotID, fsClient.executorPrvKey)
 	if err != nil {
@@ -917,7 +1264,7 @@ func (fsClient *FSClient) DownloadSnapshot(snapshotID string, downloadDir string
 				downloadTracker.Start()
 			}
 
-			err = fsClient.s3Client.Download(file[0].Name, file[0].Reference.S3Object.Object, dir, &downloadTracker, fsClient.Quiet)
+			err = fsClient.downloadFile(fileInfoFromFile(file[0]), dir, &downloadTracker)
 			if err != nil {
 				return err
 			}
@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -1592,3 +1594,296 @@ func TestCleanFilesRecursively(t *testing.T) {
 	coloniesServer.Shutdown()
 	<-done
 }
+
+func TestSyncWithStorageDrivers(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	ts := createTestHTTPServer("test_token")
+	defer ts.Close()
+
+	localDriver, err := CreateLocalDriver(t.TempDir())
+	assert.Nil(t, err)
+	httpDriver := CreateHTTPDriver(ts.URL)
+	httpDriver.Header.Set("Authorization", "Bearer test_token")
+
+	for _, driver := range []StorageDriver{localDriver, httpDriver, CreateMemDriver()} {
+		label := "/test_" + driver.Protocol()
+
+		syncDir := t.TempDir()
+		assert.Nil(t, os.WriteFile(syncDir+"/file1", []byte("testdata1"), 0644))
+		assert.Nil(t, os.WriteFile(syncDir+"/empty", []byte{}, 0644))
+
+		fsClient, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, driver)
+		assert.Nil(t, err)
+		fsClient.Quiet = true
+
+		syncPlan, err := fsClient.CalcSyncPlan(syncDir, label, true)
+		assert.Nil(t, err)
+		assert.Len(t, syncPlan.RemoteMissing, 2)
+		assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+		files, err := coloniesClient.GetFileByName(env.colonyName, label, "file1", env.executorPrvKey)
+		assert.Nil(t, err)
+		assert.Len(t, files, 1)
+		assert.Equal(t, driver.Protocol(), files[0].Reference.Protocol)
+		assert.True(t, driver.Exists(files[0].Reference.S3Object.Object))
+
+		// Download to an empty directory
+		downloadDir := t.TempDir()
+		syncPlan, err = fsClient.CalcSyncPlan(downloadDir, label, false)
+		assert.Nil(t, err)
+		assert.Len(t, syncPlan.LocalMissing, 2)
+		assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+		data, err := os.ReadFile(downloadDir + "/file1")
+		assert.Nil(t, err)
+		assert.Equal(t, "testdata1", string(data))
+		data, err = os.ReadFile(downloadDir + "/empty")
+		assert.Nil(t, err)
+		assert.Len(t, data, 0)
+
+		// Snapshots work identically across protocols
+		snapshot, err := coloniesClient.CreateSnapshot(env.colonyName, label, "snapshot_"+driver.Protocol(), env.executorPrvKey)
+		assert.Nil(t, err)
+		snapshotDir := t.TempDir()
+		assert.Nil(t, fsClient.DownloadSnapshot(snapshot.ID, snapshotDir))
+		data, err = os.ReadFile(snapshotDir + "/file1")
+		assert.Nil(t, err)
+		assert.Equal(t, "testdata1", string(data))
+
+		assert.Nil(t, fsClient.RemoveFileByName(env.colonyName, label, "file1"))
+		assert.False(t, driver.Exists(files[0].Reference.S3Object.Object))
+	}
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestDownloadWithOtherDriver(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	memDriver := CreateMemDriver()
+	fsClient, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, memDriver)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	syncDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(syncDir+"/file1", []byte("testdata1"), 0644))
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, "/test", true)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+	// Files are downloaded using the driver of their protocol, not the driver used for uploads
+	localDriver, err := CreateLocalDriver(t.TempDir())
+	assert.Nil(t, err)
+	fsClient2, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, localDriver)
+	assert.Nil(t, err)
+	fsClient2.Quiet = true
+
+	downloadDir := t.TempDir()
+	syncPlan, err = fsClient2.CalcSyncPlan(downloadDir, "/test", false)
+	assert.Nil(t, err)
+	assert.NotNil(t, fsClient2.ApplySyncPlan(syncPlan))
+
+	fsClient2.AddDriver(memDriver)
+	assert.Nil(t, fsClient2.ApplySyncPlan(syncPlan))
+	data, err := os.ReadFile(downloadDir + "/file1")
+	assert.Nil(t, err)
+	assert.Equal(t, "testdata1", string(data))
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestSyncChunked(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	driver := &countingDriver{MemDriver: CreateMemDriver()}
+	fsClient, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, driver)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+	fsClient.Chunking = true
+
+	data := randomBytes(t, 5*1024*1024)
+	syncDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(syncDir+"/file1", data, 0644))
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, "/test", true)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+	files, err := coloniesClient.GetFileByName(env.colonyName, "/test", "file1", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	assert.Greater(t, len(files[0].Chunks), 1)
+	assert.Equal(t, len(files[0].Chunks), driver.puts)
+
+	// The same content in another label is deduplicated
+	syncDir2 := t.TempDir()
+	assert.Nil(t, os.WriteFile(syncDir2+"/file1", data, 0644))
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir2, "/test2", true)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+	assert.Equal(t, len(files[0].Chunks), driver.puts)
+
+	// Download to an empty directory
+	downloadDir := t.TempDir()
+	syncPlan, err = fsClient.CalcSyncPlan(downloadDir, "/test", false)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+	downloaded, err := os.ReadFile(downloadDir + "/file1")
+	assert.Nil(t, err)
+	assert.Equal(t, data, downloaded)
+
+	// Chunks are shared and are kept when files are removed
+	assert.Nil(t, fsClient.RemoveFileByName(env.colonyName, "/test", "file1"))
+	assert.True(t, driver.Exists(core.ChunkObject(env.colonyName, files[0].Chunks[0].Hash)))
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestGC(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	driver := CreateMemDriver()
+	fsClient, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, driver)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+
+	// Add three revisions of a file
+	syncDir := t.TempDir()
+	for i := 1; i <= 3; i++ {
+		assert.Nil(t, os.WriteFile(syncDir+"/file1", []byte(fmt.Sprintf("testdata%d", i)), 0644))
+		syncPlan, err := fsClient.CalcSyncPlan(syncDir, "/test", true)
+		assert.Nil(t, err)
+		assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+		if i == 1 {
+			_, err = coloniesClient.CreateSnapshot(env.colonyName, "/test", "test_snapshot", env.executorPrvKey)
+			assert.Nil(t, err)
+		}
+	}
+
+	revisions, err := coloniesClient.GetFileByName(env.colonyName, "/test", "file1", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 3)
+
+	// Objects left behind by files removed without removing their objects. Only objects in the namespace of
+	// the colony are swept, since the storage may be shared with other colonies and applications.
+	orphan := core.StorageObjectPrefix(env.colonyName) + "orphan"
+	assert.Nil(t, driver.Put(orphan, strings.NewReader("orphan"), 6))
+	assert.Nil(t, driver.Put("orphan", strings.NewReader("orphan"), 6))
+
+	// Objects modified within the grace period are kept
+	report, err := fsClient.GC(time.Hour, false)
+	assert.Nil(t, err)
+	assert.Len(t, report.UnreferencedDriverObjects, 0)
+	assert.True(t, driver.Exists(orphan))
+
+	// Without retention policies, no revisions are collected
+	report, err = fsClient.GC(0, true)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 0)
+	assert.Len(t, report.UnreferencedObjects, 0)
+	assert.Len(t, report.UnreferencedDriverObjects, 1)
+	assert.Equal(t, orphan, report.UnreferencedDriverObjects[0].Object)
+	assert.Equal(t, int64(6), report.ReclaimedBytes)
+	assert.True(t, driver.Exists(orphan))
+
+	report, err = fsClient.GC(0, false)
+	assert.Nil(t, err)
+	assert.Len(t, report.UnreferencedDriverObjects, 1)
+	assert.False(t, driver.Exists(orphan))
+	assert.True(t, driver.Exists("orphan"))
+
+	// The first revision is referenced by the snapshot, so only the second revision is stale
+	_, err = coloniesClient.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(env.colonyName, "/test", 1, 0), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	report, err = fsClient.GC(0, true)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 1)
+	assert.Len(t, report.UnreferencedDriverObjects, 0)
+	staleRevision := report.StaleRevisions[0]
+	assert.Equal(t, int64(len("testdata2")), staleRevision.Size)
+	assert.Equal(t, staleRevision.Size, report.ReclaimedBytes)
+	assert.True(t, driver.Exists(staleRevision.Reference.S3Object.Object))
+
+	report, err = fsClient.GC(0, false)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 1)
+	assert.False(t, driver.Exists(staleRevision.Reference.S3Object.Object))
+
+	revisions, err = coloniesClient.GetFileByName(env.colonyName, "/test", "file1", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 2)
+	for _, revision := range revisions {
+		assert.True(t, driver.Exists(revision.Reference.S3Object.Object))
+	}
+
+	// Nothing is left to collect
+	report, err = fsClient.GC(0, false)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 0)
+	assert.Len(t, report.UnreferencedObjects, 0)
+	assert.Len(t, report.UnreferencedDriverObjects, 0)
+
+	coloniesServer.Shutdown()
+	<-done
+}
+
+func TestGCChunks(t *testing.T) {
+	env, coloniesClient, coloniesServer, _, done := setupTestEnv(t)
+
+	driver := CreateMemDriver()
+	fsClient, err := CreateFSClientWithDriver(coloniesClient, env.colonyName, env.executorPrvKey, driver)
+	assert.Nil(t, err)
+	fsClient.Quiet = true
+	fsClient.Chunking = true
+
+	// The first file is also stored in another label, so its chunks are shared
+	data := randomBytes(t, 2*1024*1024)
+	syncDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(syncDir+"/file1", data, 0644))
+	assert.Nil(t, os.WriteFile(syncDir+"/file2", randomBytes(t, 2*1024*1024), 0644))
+	syncPlan, err := fsClient.CalcSyncPlan(syncDir, "/test", true)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+	syncDir2 := t.TempDir()
+	assert.Nil(t, os.WriteFile(syncDir2+"/file1", data, 0644))
+	syncPlan, err = fsClient.CalcSyncPlan(syncDir2, "/test2", true)
+	assert.Nil(t, err)
+	assert.Nil(t, fsClient.ApplySyncPlan(syncPlan))
+
+	files1, err := coloniesClient.GetFileByName(env.colonyName, "/test", "file1", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, files1, 1)
+	files2, err := coloniesClient.GetFileByName(env.colonyName, "/test", "file2", env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, files2, 1)
+
+	// Chunks are not removed with the files, chunks no longer referenced by any file are collected by GC
+	assert.Nil(t, fsClient.RemoveFileByName(env.colonyName, "/test", "file1"))
+	assert.Nil(t, fsClient.RemoveFileByName(env.colonyName, "/test", "file2"))
+	report, err := fsClient.GC(0, true)
+	assert.Nil(t, err)
+	assert.Len(t, report.UnreferencedObjects, 0)
+	assert.Len(t, report.UnreferencedDriverObjects, len(files2[0].Chunks))
+	assert.Equal(t, files2[0].Size, report.ReclaimedBytes)
+	for _, chunk := range files2[0].Chunks {
+		assert.True(t, driver.Exists(core.ChunkObject(env.colonyName, chunk.Hash)))
+	}
+
+	report, err = fsClient.GC(0, false)
+	assert.Nil(t, err)
+	assert.Len(t, report.UnreferencedDriverObjects, len(files2[0].Chunks))
+	for _, chunk := range files1[0].Chunks {
+		assert.True(t, driver.Exists(core.ChunkObject(env.colonyName, chunk.Hash)))
+	}
+	for _, chunk := range files2[0].Chunks {
+		assert.False(t, driver.Exists(core.ChunkObject(env.colonyName, chunk.Hash)))
+	}
+
+	coloniesServer.Shutdown()
+	<-done
+}
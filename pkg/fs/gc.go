@@ -0,0 +1,100 @@
+package fs
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// GC asks the server to remove file revisions not retained by the retention policies of their labels, and
+// objects issued to the colony that are no longer referenced by any file. Objects issued within gracePeriod are
+// kept, since uploads store objects before files are added. If dryRun is true, nothing is removed and the
+// report tells what would be removed.
+//
+// Objects of stale revisions stored with the drivers of the client are removed by the client, by name. Drivers
+// that can list their objects are then swept: objects and chunks in the namespace of the colony that are not
+// retained by the server and were modified before gracePeriod are removed, e.g. objects of removed files and
+// chunks no longer referenced by any file or snapshot. Objects outside the namespace of the colony, e.g.
+// objects stored before object names were namespaced, are never swept. A chunk that is no longer referenced may
+// be reused by a chunked upload running at the same time, so GC should not run while chunked files are synced.
+func (fsClient *FSClient) GC(gracePeriod time.Duration, dryRun bool) (*core.FileGCReport, error) {
+	report, err := fsClient.coloniesClient.GCFiles(fsClient.colonyName, gracePeriod, dryRun, fsClient.executorPrvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server has removed the revisions, objects in storage backends are removed by the server
+	removedByName := make(map[string]bool)
+	for _, revision := range report.StaleRevisions {
+		if revision.Reference.Backend != "" {
+			continue
+		}
+
+		fileInfo := fileInfoFromFile(revision)
+		if fileInfo.Chunked || fileInfo.Shared {
+			continue
+		}
+		removedByName[fileInfo.S3Filename] = true
+
+		if dryRun {
+			continue
+		}
+
+		if err := fsClient.removeObject(fileInfo); err != nil {
+			log.WithFields(log.Fields{"Label": revision.Label, "Filename": revision.Name, "FileID": revision.ID, "Error": err}).Warn("Failed to remove object of stale file revision")
+		}
+	}
+
+	retained := make(map[string]bool)
+	for _, object := range report.RetainedObjects {
+		retained[object] = true
+	}
+
+	for _, driver := range fsClient.listDrivers() {
+		lister, ok := driver.(ObjectLister)
+		if !ok {
+			log.WithFields(log.Fields{"Protocol": driver.Protocol()}).Debug("Storage driver cannot list objects, unreferenced objects not collected")
+			continue
+		}
+
+		objects, err := lister.List(core.StorageObjectPrefix(fsClient.colonyName))
+		if err != nil {
+			return report, err
+		}
+
+		now := time.Now()
+		for _, object := range objects {
+			if retained[object.Object] || removedByName[object.Object] || now.Sub(object.Modified) < gracePeriod || !core.IsColonyStorageObject(fsClient.colonyName, object.Object) {
+				continue
+			}
+
+			report.UnreferencedDriverObjects = append(report.UnreferencedDriverObjects, object)
+			report.ReclaimedBytes += object.Size
+			if dryRun {
+				continue
+			}
+
+			log.WithFields(log.Fields{"Protocol": object.Protocol, "Object": object.Object}).Debug("Removing unreferenced object")
+			if err := driver.Remove(object.Object); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// listDrivers returns the drivers of the client, the driver used to store new files and drivers added with
+// AddDriver or created to download or remove files
+func (fsClient *FSClient) listDrivers() []StorageDriver {
+	fsClient.driversMutex.Lock()
+	defer fsClient.driversMutex.Unlock()
+
+	drivers := make([]StorageDriver, 0, len(fsClient.drivers))
+	for _, driver := range fsClient.drivers {
+		drivers = append(drivers, driver)
+	}
+
+	return drivers
+}
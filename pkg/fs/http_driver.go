@@ -0,0 +1,72 @@
+package fs
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// HTTPDriver stores objects on a plain HTTP server supporting PUT, GET, DELETE and HEAD, e.g. a WebDAV
+// server. Objects are stored at BaseURL/object, and Header is added to every request, e.g. for authorization.
+type HTTPDriver struct {
+	BaseURL string
+	Header  http.Header
+}
+
+func CreateHTTPDriver(baseURL string) *HTTPDriver {
+	return &HTTPDriver{BaseURL: strings.TrimRight(baseURL, "/"), Header: make(http.Header)}
+}
+
+func (driver *HTTPDriver) url(object string) string {
+	return driver.BaseURL + "/" + url.PathEscape(object)
+}
+
+func (driver *HTTPDriver) Protocol() string {
+	return ProtocolHTTP
+}
+
+func (driver *HTTPDriver) Describe(s3Object *core.S3Object) {
+	s3Object.Server = driver.BaseURL
+	s3Object.Port = -1
+	s3Object.TLS = strings.HasPrefix(driver.BaseURL, "https://")
+}
+
+func (driver *HTTPDriver) Put(object string, reader io.Reader, length int64) error {
+	resp, err := sendObjectRequest(http.MethodPut, driver.url(object), driver.Header, reader, length)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (driver *HTTPDriver) Get(object string) (io.ReadCloser, error) {
+	resp, err := sendObjectRequest(http.MethodGet, driver.url(object), driver.Header, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (driver *HTTPDriver) Remove(object string) error {
+	resp, err := sendObjectRequest(http.MethodDelete, driver.url(object), driver.Header, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (driver *HTTPDriver) Exists(object string) bool {
+	resp, err := sendObjectRequest(http.MethodHead, driver.url(object), driver.Header, nil, 0)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return true
+}
@@ -0,0 +1,138 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// LocalDriver stores objects as files in a directory, e.g. an NFS mount shared by all executors. The
+// directory is not stored in file references, so it may be mounted at different paths on different hosts.
+// Object names are escaped, so an object name containing slashes is stored as a single file.
+type LocalDriver struct {
+	Dir string
+}
+
+func CreateLocalDriver(dir string) (*LocalDriver, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalDriver{Dir: dir}, nil
+}
+
+func (driver *LocalDriver) path(object string) (string, error) {
+	if object == "" || object == "." || object == ".." {
+		return "", errors.New("Invalid object name <" + object + ">")
+	}
+
+	return filepath.Join(driver.Dir, url.PathEscape(object)), nil
+}
+
+func (driver *LocalDriver) Protocol() string {
+	return ProtocolFile
+}
+
+func (driver *LocalDriver) Describe(s3Object *core.S3Object) {
+	s3Object.Port = -1
+}
+
+// Put writes the object to a temporary file first, so that a partially written object is never visible
+func (driver *LocalDriver) Put(object string, reader io.Reader, length int64) error {
+	path, err := driver.path(object)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(driver.Dir, "."+url.PathEscape(object)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	n, err := io.Copy(tmpFile, reader)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if n != length {
+		return errors.New("Failed to store object <" + object + ">, size does not match")
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+func (driver *LocalDriver) Get(object string) (io.ReadCloser, error) {
+	path, err := driver.path(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (driver *LocalDriver) Remove(object string) error {
+	path, err := driver.path(object)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (driver *LocalDriver) Exists(object string) bool {
+	path, err := driver.path(object)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// List returns the objects with a name starting with prefix, temporary files of objects being stored are skipped
+func (driver *LocalDriver) List(prefix string) ([]*core.DriverObject, error) {
+	entries, err := os.ReadDir(driver.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []*core.DriverObject{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		object, err := url.PathUnescape(entry.Name())
+		if err != nil || !strings.HasPrefix(object, prefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, &core.DriverObject{Protocol: ProtocolFile, Object: object, Size: info.Size(), Modified: info.ModTime()})
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,95 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// MemDriver stores objects in memory. Objects are only available in the process that stored them, so it
+// is only useful for tests.
+type MemDriver struct {
+	mutex   sync.Mutex
+	objects map[string]*memObject
+}
+
+type memObject struct {
+	data     []byte
+	modified time.Time
+}
+
+func CreateMemDriver() *MemDriver {
+	return &MemDriver{objects: make(map[string]*memObject)}
+}
+
+func (driver *MemDriver) Protocol() string {
+	return ProtocolMem
+}
+
+func (driver *MemDriver) Describe(s3Object *core.S3Object) {
+	s3Object.Port = -1
+}
+
+func (driver *MemDriver) Put(object string, reader io.Reader, length int64) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(data)) != length {
+		return errors.New("Failed to store object <" + object + ">, size does not match")
+	}
+
+	driver.mutex.Lock()
+	defer driver.mutex.Unlock()
+	driver.objects[object] = &memObject{data: data, modified: time.Now()}
+
+	return nil
+}
+
+func (driver *MemDriver) Get(object string) (io.ReadCloser, error) {
+	driver.mutex.Lock()
+	defer driver.mutex.Unlock()
+
+	memObject, ok := driver.objects[object]
+	if !ok {
+		return nil, errors.New("Object <" + object + "> not found")
+	}
+
+	return io.NopCloser(bytes.NewReader(memObject.data)), nil
+}
+
+func (driver *MemDriver) Remove(object string) error {
+	driver.mutex.Lock()
+	defer driver.mutex.Unlock()
+	delete(driver.objects, object)
+
+	return nil
+}
+
+func (driver *MemDriver) Exists(object string) bool {
+	driver.mutex.Lock()
+	defer driver.mutex.Unlock()
+	_, ok := driver.objects[object]
+
+	return ok
+}
+
+func (driver *MemDriver) List(prefix string) ([]*core.DriverObject, error) {
+	driver.mutex.Lock()
+	defer driver.mutex.Unlock()
+
+	objects := []*core.DriverObject{}
+	for name, memObject := range driver.objects {
+		if strings.HasPrefix(name, prefix) {
+			objects = append(objects, &core.DriverObject{Protocol: ProtocolMem, Object: name, Size: int64(len(memObject.data)), Modified: memObject.modified})
+		}
+	}
+
+	return objects, nil
+}
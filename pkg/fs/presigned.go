@@ -0,0 +1,81 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+func checkObjectResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.New("Object request failed with status " + strconv.Itoa(resp.StatusCode) + ": " + string(body))
+}
+
+// sendObjectRequest sends a request to an object URL, the response body is closed if the request failed
+func sendObjectRequest(method string, url string, header http.Header, body io.Reader, length int64) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if method == http.MethodPut {
+		req.ContentLength = length
+		if length == 0 {
+			req.Body = http.NoBody
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkObjectResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// putPresigned uploads length bytes read from reader to a presigned PUT URL
+func putPresigned(url string, reader io.Reader, length int64) error {
+	resp, err := sendObjectRequest(http.MethodPut, url, nil, reader, length)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// getPresigned returns a reader of the content of a presigned GET URL, the reader must be closed by the caller
+func getPresigned(url string) (io.ReadCloser, error) {
+	resp, err := sendObjectRequest(http.MethodGet, url, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// deletePresigned removes the object of a presigned DELETE URL
+func deletePresigned(url string) error {
+	resp, err := sendObjectRequest(http.MethodDelete, url, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresignedPutGetDelete(t *testing.T) {
+	objects := make(map[string][]byte)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("NoSuchKey"))
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	content := randomBytes(t, 100*1024)
+	assert.Nil(t, putPresigned(ts.URL+"/bucket/object1", bytes.NewReader(content), int64(len(content))))
+	assert.Nil(t, putPresigned(ts.URL+"/bucket/empty", bytes.NewReader(nil), 0))
+
+	reader, err := getPresigned(ts.URL + "/bucket/object1")
+	assert.Nil(t, err)
+	downloaded, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	reader.Close()
+	assert.Equal(t, content, downloaded)
+
+	reader, err = getPresigned(ts.URL + "/bucket/empty")
+	assert.Nil(t, err)
+	downloaded, err = io.ReadAll(reader)
+	assert.Nil(t, err)
+	reader.Close()
+	assert.Len(t, downloaded, 0)
+
+	assert.Nil(t, deletePresigned(ts.URL+"/bucket/object1"))
+	_, err = getPresigned(ts.URL + "/bucket/object1")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestPresignedExpired(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Request has expired"))
+	}))
+	defer ts.Close()
+
+	err := putPresigned(ts.URL+"/bucket/object", bytes.NewReader([]byte("data")), 4)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "expired")
+
+	assert.NotNil(t, deletePresigned(ts.URL+"/bucket/object"))
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/jedib0t/go-pretty/v6/progress"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -149,6 +150,34 @@ func (s3Client *S3Client) Download(filename string, s3Filename string, downloadD
 	return err
 }
 
+func (s3Client *S3Client) Protocol() string {
+	return ProtocolS3
+}
+
+func (s3Client *S3Client) Describe(s3Object *core.S3Object) {
+	s3Object.Server = s3Client.Endpoint
+	s3Object.Port = -1
+	s3Object.TLS = s3Client.TLS
+	s3Object.Region = s3Client.Region
+	s3Object.Bucket = s3Client.BucketName
+}
+
+// Put uploads length bytes read from reader
+func (s3Client *S3Client) Put(s3Filename string, reader io.Reader, length int64) error {
+	_, err := s3Client.mc.PutObject(context.Background(), s3Client.BucketName, s3Filename, reader, length, minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
+// Get returns a reader of the content of an object, the reader must be closed by the caller
+func (s3Client *S3Client) Get(s3Filename string) (io.ReadCloser, error) {
+	return s3Client.mc.GetObject(context.Background(), s3Client.BucketName, s3Filename, minio.GetObjectOptions{})
+}
+
 func (s3Client *S3Client) Exists(filename string) bool {
 	_, err := s3Client.mc.StatObject(context.Background(), s3Client.BucketName, filename, minio.StatObjectOptions{})
 	if err != nil {
@@ -160,3 +189,15 @@ func (s3Client *S3Client) Exists(filename string) bool {
 func (s3Client *S3Client) Remove(filename string) error {
 	return s3Client.mc.RemoveObject(context.Background(), s3Client.BucketName, filename, minio.RemoveObjectOptions{})
 }
+
+func (s3Client *S3Client) List(prefix string) ([]*core.DriverObject, error) {
+	objects := []*core.DriverObject{}
+	for info := range s3Client.mc.ListObjects(context.Background(), s3Client.BucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+		objects = append(objects, &core.DriverObject{Protocol: ProtocolS3, Object: info.Key, Size: info.Size, Modified: info.LastModified})
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,147 @@
+package logsink
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+// fileWriter appends log entries as JSON lines to a file. When the file grows beyond maxSize it is
+// renamed to <path>.1, older files are shifted to <path>.2 and so on, keeping at most maxFiles.
+type fileWriter struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// FileSinkPath returns the file written by a file sink. Each colony has its own subdirectory in dir, so
+// sinks in different colonies never write to the same file.
+func FileSinkPath(sink *core.LogSink, dir string) (string, error) {
+	colonyDir := url.PathEscape(sink.ColonyName)
+	if colonyDir == "" || colonyDir == "." || colonyDir == ".." {
+		return "", errors.New("Invalid colony name <" + sink.ColonyName + "> for a file log sink")
+	}
+
+	colonyPath := filepath.Join(dir, colonyDir)
+	path := filepath.Join(colonyPath, filepath.Clean(sink.Target))
+	rel, err := filepath.Rel(colonyPath, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("Log sink target <" + sink.Target + "> is outside the log sink directory")
+	}
+
+	return path, nil
+}
+
+func createFileWriter(sink *core.LogSink, dir string) (*fileWriter, error) {
+	path, err := FileSinkPath(sink, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := sink.MaxSize
+	if maxSize <= 0 {
+		maxSize = constants.LOG_SINK_DEFAULT_MAX_SIZE
+	}
+
+	maxFiles := sink.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = constants.LOG_SINK_DEFAULT_MAX_FILES
+	}
+
+	return &fileWriter{path: path, maxSize: maxSize, maxFiles: maxFiles}, nil
+}
+
+func (w *fileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	return nil
+}
+
+func (w *fileWriter) rotate() error {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+
+	os.Remove(w.path + "." + strconv.Itoa(w.maxFiles))
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		os.Rename(w.path+"."+strconv.Itoa(i), w.path+"."+strconv.Itoa(i+1))
+	}
+
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return w.open()
+}
+
+func (w *fileWriter) Write(logs []*core.Log) error {
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	for _, logEntry := range logs {
+		line, err := json.Marshal(logEntry)
+		if err != nil {
+			return err
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	if w.size > 0 && w.size+int64(len(data)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	if err != nil {
+		// Reopen the file on the next write, a partially written batch is rewritten on retry
+		w.file.Close()
+		w.file = nil
+		return err
+	}
+
+	return nil
+}
+
+func (w *fileWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
@@ -0,0 +1,194 @@
+package logsink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+)
+
+type encoder func(logs []*core.Log) ([]byte, error)
+
+// httpWriter POSTs each batch to an HTTP endpoint, either as a JSON array of log entries or as an
+// OTLP/HTTP JSON ExportLogsServiceRequest
+type httpWriter struct {
+	url         string
+	headers     map[string]string
+	encode      encoder
+	contentType string
+	httpClient  *http.Client
+}
+
+func createHTTPWriter(sink *core.LogSink, encode encoder, contentType string, egressPolicy *egress.Policy) *httpWriter {
+	return &httpWriter{
+		url:         sink.Target,
+		headers:     sink.Headers,
+		encode:      encode,
+		contentType: contentType,
+		httpClient:  egressPolicy.HTTPClient(time.Duration(constants.LOG_SINK_TIMEOUT) * time.Second),
+	}
+}
+
+func (w *httpWriter) Write(logs []*core.Log) error {
+	payload, err := w.encode(logs)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", w.contentType)
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("log sink endpoint replied with status code " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return nil
+}
+
+func (w *httpWriter) Close() error {
+	w.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func encodeJSON(logs []*core.Log) ([]byte, error) {
+	return json.Marshal(logs)
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber,omitempty"`
+	SeverityText   string         `json:"severityText,omitempty"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpExportLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+func otlpAttribute(key string, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
+
+// otlpSeverity maps a log level to an OpenTelemetry severity number and text
+func otlpSeverity(logEntry *core.Log) (int, string) {
+	switch logEntry.Level {
+	case core.LogLevelDebug:
+		return 5, "DEBUG"
+	case core.LogLevelInfo:
+		return 9, "INFO"
+	case core.LogLevelWarn:
+		return 13, "WARN"
+	case core.LogLevelError:
+		return 17, "ERROR"
+	}
+
+	return 0, ""
+}
+
+// encodeOTLP encodes a batch as an OTLP/HTTP JSON request, entries are grouped by colony since
+// the colony is a resource attribute
+func encodeOTLP(logs []*core.Log) ([]byte, error) {
+	recordsByColony := make(map[string][]otlpLogRecord)
+	for _, logEntry := range logs {
+		severityNumber, severityText := otlpSeverity(logEntry)
+		record := otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(logEntry.Timestamp, 10),
+			SeverityNumber: severityNumber,
+			SeverityText:   severityText,
+			Body:           otlpAnyValue{StringValue: logEntry.Message},
+		}
+
+		if logEntry.ProcessID != "" {
+			record.Attributes = append(record.Attributes, otlpAttribute("colonies.process_id", logEntry.ProcessID))
+		}
+		if logEntry.ProcessGraphID != "" {
+			record.Attributes = append(record.Attributes, otlpAttribute("colonies.processgraph_id", logEntry.ProcessGraphID))
+		}
+		if logEntry.ExecutorName != "" {
+			record.Attributes = append(record.Attributes, otlpAttribute("colonies.executor_name", logEntry.ExecutorName))
+		}
+		if logEntry.Stream != "" {
+			record.Attributes = append(record.Attributes, otlpAttribute("log.iostream", logEntry.Stream))
+		}
+
+		keys := make([]string, 0, len(logEntry.Fields))
+		for key := range logEntry.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			record.Attributes = append(record.Attributes, otlpAttribute(key, logEntry.Fields[key]))
+		}
+
+		recordsByColony[logEntry.ColonyName] = append(recordsByColony[logEntry.ColonyName], record)
+	}
+
+	colonyNames := make([]string, 0, len(recordsByColony))
+	for colonyName := range recordsByColony {
+		colonyNames = append(colonyNames, colonyName)
+	}
+	sort.Strings(colonyNames)
+
+	request := otlpExportLogsRequest{}
+	for _, colonyName := range colonyNames {
+		request.ResourceLogs = append(request.ResourceLogs, otlpResourceLogs{
+			Resource: otlpResource{Attributes: []otlpKeyValue{
+				otlpAttribute("service.name", "colonies"),
+				otlpAttribute("colonies.colony_name", colonyName),
+			}},
+			ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "colonies"}, LogRecords: recordsByColony[colonyName]}},
+		})
+	}
+
+	return json.Marshal(request)
+}
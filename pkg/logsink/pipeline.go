@@ -0,0 +1,436 @@
+package logsink
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	log "github.com/sirupsen/logrus"
+)
+
+// LogSinkStore is the subset of the database needed to resolve the sinks of a colony
+type LogSinkStore interface {
+	GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error)
+}
+
+type colonySinks struct {
+	sinks  []*core.LogSink
+	loaded time.Time
+}
+
+// Pipeline forwards log entries to the external sinks registered in each colony. Forward never blocks
+// the caller. Entries are first queued in a server wide queue and then routed to a bounded queue per
+// sink, where they are batched and written by a dedicated worker. If a sink is slow or unavailable its
+// queue fills up and new entries for that sink are dropped, other sinks and add_log requests are
+// not affected. File sinks write to a subdirectory per colony, and a file is only written by one sink.
+type Pipeline struct {
+	store          LogSinkStore
+	dir            string
+	egressPolicy   *egress.Policy
+	entries        chan *core.Log
+	colonies       map[string]*colonySinks
+	workers        map[string]*worker
+	paths          map[string]string
+	mutex          sync.Mutex
+	reloadInterval time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	stop           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+	dropping       bool
+}
+
+// CreatePipeline creates a pipeline, file sinks are written to files in dir and are
+// disabled if dir is empty. Other sinks are checked against the egress policy of the server.
+func CreatePipeline(store LogSinkStore, dir string) *Pipeline {
+	return CreatePipelineWithBackoff(store,
+		dir,
+		egress.LoadPolicy(),
+		time.Duration(constants.LOG_SINK_INITIAL_BACKOFF)*time.Millisecond,
+		time.Duration(constants.LOG_SINK_MAX_BACKOFF)*time.Millisecond)
+}
+
+func CreatePipelineWithBackoff(store LogSinkStore, dir string, egressPolicy *egress.Policy, initialBackoff time.Duration, maxBackoff time.Duration) *Pipeline {
+	pipeline := &Pipeline{
+		store:          store,
+		dir:            dir,
+		egressPolicy:   egressPolicy,
+		entries:        make(chan *core.Log, constants.LOG_SINK_QUEUE_SIZE),
+		colonies:       make(map[string]*colonySinks),
+		workers:        make(map[string]*worker),
+		paths:          make(map[string]string),
+		reloadInterval: time.Duration(constants.LOG_SINK_RELOAD_INTERVAL) * time.Second,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		stop:           make(chan struct{}),
+	}
+
+	pipeline.wg.Add(1)
+	go pipeline.router()
+
+	return pipeline
+}
+
+// Dir returns the directory file sinks are written to, an empty string means that file sinks are disabled
+func (pipeline *Pipeline) Dir() string {
+	return pipeline.dir
+}
+
+// Validate checks that the sink can be used by this server, i.e. that file sinks do not write to the same
+// file as another sink in the colony and that other sinks connect to addresses allowed by the egress policy
+func (pipeline *Pipeline) Validate(sink *core.LogSink) error {
+	if err := sink.Validate(); err != nil {
+		return err
+	}
+
+	switch sink.Type {
+	case core.LogSinkTypeFile:
+		if pipeline.dir == "" {
+			return errors.New("File log sinks are disabled on this server, COLONIES_LOG_SINK_DIR is not set")
+		}
+
+		path, err := FileSinkPath(sink, pipeline.dir)
+		if err != nil {
+			return err
+		}
+
+		sinks, err := pipeline.store.GetLogSinksByColonyName(sink.ColonyName)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range sinks {
+			if other.Type != core.LogSinkTypeFile || other.Name == sink.Name {
+				continue
+			}
+			if otherPath, err := FileSinkPath(other, pipeline.dir); err == nil && otherPath == path {
+				return errors.New("Log sink target <" + sink.Target + "> is already used by log sink <" + other.Name + ">")
+			}
+		}
+	case core.LogSinkTypeSyslog:
+		u, err := url.Parse(sink.Target)
+		if err != nil {
+			return err
+		}
+
+		return pipeline.egressPolicy.CheckHost(u.Hostname())
+	case core.LogSinkTypeHTTP, core.LogSinkTypeOTLP:
+		return pipeline.egressPolicy.CheckURL(sink.Target)
+	}
+
+	return nil
+}
+
+// Forward queues a log entry for delivery to all matching sinks in its colony
+func (pipeline *Pipeline) Forward(logEntry *core.Log) {
+	if logEntry == nil {
+		return
+	}
+
+	select {
+	case <-pipeline.stop:
+		return
+	default:
+	}
+
+	select {
+	case pipeline.entries <- logEntry:
+	default:
+		pipeline.mutex.Lock()
+		if !pipeline.dropping {
+			log.WithFields(log.Fields{"ColonyName": logEntry.ColonyName}).Warn("Log sink queue is full, dropping log entries")
+		}
+		pipeline.dropping = true
+		pipeline.mutex.Unlock()
+	}
+}
+
+// Reload drops the cached sinks of a colony, it should be called when a sink is added or removed
+func (pipeline *Pipeline) Reload(colonyName string) {
+	pipeline.mutex.Lock()
+	defer pipeline.mutex.Unlock()
+
+	delete(pipeline.colonies, colonyName)
+}
+
+// Stop stops all workers, pending batches are flushed on a best effort basis
+func (pipeline *Pipeline) Stop() {
+	pipeline.stopOnce.Do(func() {
+		close(pipeline.stop)
+	})
+	pipeline.wg.Wait()
+
+	pipeline.mutex.Lock()
+	workers := pipeline.workers
+	pipeline.workers = make(map[string]*worker)
+	pipeline.paths = make(map[string]string)
+	pipeline.mutex.Unlock()
+
+	for _, w := range workers {
+		w.close()
+	}
+}
+
+func (pipeline *Pipeline) router() {
+	defer pipeline.wg.Done()
+
+	for {
+		select {
+		case <-pipeline.stop:
+			return
+		case logEntry := <-pipeline.entries:
+			pipeline.route(logEntry)
+		}
+	}
+}
+
+func (pipeline *Pipeline) route(logEntry *core.Log) {
+	pipeline.mutex.Lock()
+	pipeline.dropping = false
+	pipeline.mutex.Unlock()
+
+	sinks, err := pipeline.resolve(logEntry.ColonyName)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "ColonyName": logEntry.ColonyName}).Error("Failed to get log sinks")
+		return
+	}
+
+	for _, sink := range sinks {
+		if !sink.Matches(logEntry) {
+			continue
+		}
+
+		w := pipeline.getWorker(sink)
+		if w != nil {
+			w.enqueue(logEntry)
+		}
+	}
+}
+
+// resolve returns the sinks of a colony, sinks are cached and reloaded from the database periodically
+// so that sinks added or removed on other servers in the cluster are eventually picked up
+func (pipeline *Pipeline) resolve(colonyName string) ([]*core.LogSink, error) {
+	pipeline.mutex.Lock()
+	cached, ok := pipeline.colonies[colonyName]
+	pipeline.mutex.Unlock()
+
+	if ok && time.Since(cached.loaded) < pipeline.reloadInterval {
+		return cached.sinks, nil
+	}
+
+	sinks, err := pipeline.store.GetLogSinksByColonyName(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]bool)
+	for _, sink := range sinks {
+		active[sink.ID] = true
+	}
+
+	pipeline.mutex.Lock()
+	pipeline.colonies[colonyName] = &colonySinks{sinks: sinks, loaded: time.Now()}
+	var removed []*worker
+	for id, w := range pipeline.workers {
+		if w.sink.ColonyName == colonyName && !active[id] {
+			removed = append(removed, w)
+			delete(pipeline.workers, id)
+			delete(pipeline.paths, w.path)
+		}
+	}
+	pipeline.mutex.Unlock()
+
+	for _, w := range removed {
+		w.close()
+	}
+
+	return sinks, nil
+}
+
+func (pipeline *Pipeline) getWorker(sink *core.LogSink) *worker {
+	pipeline.mutex.Lock()
+	defer pipeline.mutex.Unlock()
+
+	if w, ok := pipeline.workers[sink.ID]; ok {
+		return w
+	}
+
+	// Only one sink can write to a file, two writers would interleave writes and rotate each other's files
+	path := ""
+	if sink.Type == core.LogSinkTypeFile {
+		var err error
+		path, err = FileSinkPath(sink, pipeline.dir)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": sink.ColonyName, "LogSinkName": sink.Name}).Error("Failed to create log sink writer")
+			return nil
+		}
+		if _, ok := pipeline.paths[path]; ok {
+			log.WithFields(log.Fields{"ColonyName": sink.ColonyName, "LogSinkName": sink.Name, "Target": sink.Target}).Error("Log sink target is already written by another log sink")
+			return nil
+		}
+	}
+
+	writer, err := CreateWriter(sink, pipeline.dir, pipeline.egressPolicy)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "ColonyName": sink.ColonyName, "LogSinkName": sink.Name}).Error("Failed to create log sink writer")
+		return nil
+	}
+
+	w := createWorker(sink, writer, pipeline.initialBackoff, pipeline.maxBackoff)
+	w.path = path
+	pipeline.workers[sink.ID] = w
+	if path != "" {
+		pipeline.paths[path] = sink.ID
+	}
+
+	return w
+}
+
+type worker struct {
+	sink           *core.LogSink
+	path           string
+	writer         Writer
+	queue          chan *core.Log
+	batchSize      int
+	flushInterval  time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	stop           chan struct{}
+	done           chan struct{}
+	stopOnce       sync.Once
+	mutex          sync.Mutex
+	dropped        int
+}
+
+func createWorker(sink *core.LogSink, writer Writer, initialBackoff time.Duration, maxBackoff time.Duration) *worker {
+	batchSize := sink.BatchSize
+	if batchSize <= 0 {
+		batchSize = constants.LOG_SINK_DEFAULT_BATCH_SIZE
+	}
+	if batchSize > constants.LOG_SINK_MAX_BATCH_SIZE {
+		batchSize = constants.LOG_SINK_MAX_BATCH_SIZE
+	}
+
+	flushInterval := sink.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = constants.LOG_SINK_DEFAULT_FLUSH_INTERVAL
+	}
+
+	w := &worker{
+		sink:           sink,
+		writer:         writer,
+		queue:          make(chan *core.Log, constants.LOG_SINK_QUEUE_SIZE),
+		batchSize:      batchSize,
+		flushInterval:  time.Duration(flushInterval) * time.Millisecond,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+func (w *worker) enqueue(logEntry *core.Log) {
+	select {
+	case w.queue <- logEntry:
+	default:
+		w.mutex.Lock()
+		if w.dropped == 0 {
+			log.WithFields(log.Fields{"ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name}).Warn("Log sink is not keeping up, dropping log entries")
+		}
+		w.dropped++
+		w.mutex.Unlock()
+	}
+}
+
+func (w *worker) close() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func (w *worker) run() {
+	defer close(w.done)
+	defer w.writer.Close()
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*core.Log, 0, w.batchSize)
+	for {
+		select {
+		case <-w.stop:
+			// Flush what is already queued once, without retries
+		drain:
+			for {
+				select {
+				case logEntry := <-w.queue:
+					batch = append(batch, logEntry)
+				default:
+					break drain
+				}
+			}
+			if len(batch) > 0 {
+				if err := w.writer.Write(batch); err != nil {
+					log.WithFields(log.Fields{"Error": err, "ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name, "Count": len(batch)}).Warn("Failed to flush log sink")
+				}
+			}
+			return
+		case logEntry := <-w.queue:
+			batch = append(batch, logEntry)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = make([]*core.Log, 0, w.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = make([]*core.Log, 0, w.batchSize)
+			}
+		}
+	}
+}
+
+// flush writes a batch, failed writes are retried with exponential backoff. While retrying, new entries
+// accumulate in the queue and are dropped once it is full.
+func (w *worker) flush(batch []*core.Log) {
+	backoff := w.initialBackoff
+	for attempt := 0; attempt <= constants.LOG_SINK_MAX_RETRIES; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-w.stop:
+				log.WithFields(log.Fields{"ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name, "Count": len(batch)}).Warn("Log sink is stopping, dropping batch")
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > w.maxBackoff {
+				backoff = w.maxBackoff
+			}
+		}
+
+		err := w.writer.Write(batch)
+		if err == nil {
+			w.mutex.Lock()
+			if w.dropped > 0 {
+				log.WithFields(log.Fields{"ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name, "Dropped": w.dropped}).Warn("Log sink recovered, log entries were dropped")
+				w.dropped = 0
+			}
+			w.mutex.Unlock()
+			return
+		}
+
+		log.WithFields(log.Fields{"Error": err, "ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name, "Attempt": attempt + 1}).Debug("Failed to write to log sink")
+	}
+
+	log.WithFields(log.Fields{"ColonyName": w.sink.ColonyName, "LogSinkName": w.sink.Name, "Count": len(batch)}).Error("Failed to write to log sink, dropping batch")
+}
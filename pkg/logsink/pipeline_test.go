@@ -0,0 +1,390 @@
+package logsink
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	mutex sync.Mutex
+	sinks []*core.LogSink
+	calls int
+}
+
+func (store *storeMock) GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.calls++
+	var sinks []*core.LogSink
+	for _, sink := range store.sinks {
+		if sink.ColonyName == colonyName {
+			sinks = append(sinks, sink)
+		}
+	}
+
+	return sinks, nil
+}
+
+func (store *storeMock) setSinks(sinks ...*core.LogSink) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.sinks = sinks
+}
+
+func createTestLog(colonyName string, level string, message string) *core.Log {
+	return &core.Log{
+		ProcessID:    core.GenerateRandomID(),
+		ColonyName:   colonyName,
+		ExecutorName: "test_executor",
+		Level:        level,
+		Message:      message,
+		Fields:       map[string]string{"step": "1"},
+		Timestamp:    time.Now().UTC().UnixNano(),
+	}
+}
+
+// createTestPolicy allows connections to the test servers, which listen on the loopback interface
+func createTestPolicy(t *testing.T) *egress.Policy {
+	policy, err := egress.CreatePolicy([]string{"127.0.0.1"}, nil)
+	assert.Nil(t, err)
+	return policy
+}
+
+func createTestPipeline(t *testing.T, sinks ...*core.LogSink) (*Pipeline, *storeMock) {
+	store := &storeMock{sinks: sinks}
+	return CreatePipelineWithBackoff(store, "", createTestPolicy(t), 10*time.Millisecond, 50*time.Millisecond), store
+}
+
+func TestPipelineForwardHTTP(t *testing.T) {
+	received := make(chan []*core.Log, 10)
+	var authHeader atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader.Store(r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		var logs []*core.Log
+		json.Unmarshal(body, &logs)
+		received <- logs
+	}))
+	defer server.Close()
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, server.URL)
+	sink.Headers["Authorization"] = "Bearer test_token"
+	sink.BatchSize = 2
+	sink.FlushInterval = 10000
+
+	pipeline, _ := createTestPipeline(t, sink)
+	defer pipeline.Stop()
+
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "msg1"))
+	pipeline.Forward(createTestLog("other_colony", core.LogLevelInfo, "other"))
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "msg2"))
+
+	select {
+	case logs := <-received:
+		assert.Len(t, logs, 2)
+		assert.Equal(t, "msg1", logs[0].Message)
+		assert.Equal(t, "msg2", logs[1].Message)
+		assert.Equal(t, "1", logs[0].Fields["step"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for batch")
+	}
+
+	assert.Equal(t, "Bearer test_token", authHeader.Load())
+}
+
+func TestPipelineFlushInterval(t *testing.T) {
+	received := make(chan []*core.Log, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var logs []*core.Log
+		json.Unmarshal(body, &logs)
+		received <- logs
+	}))
+	defer server.Close()
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, server.URL)
+	sink.BatchSize = 100
+	sink.FlushInterval = 20
+	sink.Level = core.LogLevelWarn
+
+	pipeline, _ := createTestPipeline(t, sink)
+	defer pipeline.Stop()
+
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "filtered"))
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelError, "partial"))
+
+	select {
+	case logs := <-received:
+		assert.Len(t, logs, 1)
+		assert.Equal(t, "partial", logs[0].Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for partial batch")
+	}
+}
+
+func TestPipelineOTLP(t *testing.T) {
+	received := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]interface{}
+		json.Unmarshal(body, &request)
+		received <- request
+	}))
+	defer server.Close()
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeOTLP, server.URL+"/v1/logs")
+	sink.BatchSize = 1
+
+	pipeline, _ := createTestPipeline(t, sink)
+	defer pipeline.Stop()
+
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelWarn, "otlp message"))
+
+	select {
+	case request := <-received:
+		resourceLogs := request["resourceLogs"].([]interface{})
+		assert.Len(t, resourceLogs, 1)
+		scopeLogs := resourceLogs[0].(map[string]interface{})["scopeLogs"].([]interface{})
+		records := scopeLogs[0].(map[string]interface{})["logRecords"].([]interface{})
+		assert.Len(t, records, 1)
+		record := records[0].(map[string]interface{})
+		assert.Equal(t, "WARN", record["severityText"])
+		assert.Equal(t, float64(13), record["severityNumber"])
+		assert.Equal(t, "otlp message", record["body"].(map[string]interface{})["stringValue"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for OTLP request")
+	}
+}
+
+func TestPipelineSinkOutage(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, server.URL)
+	sink.BatchSize = 1
+
+	pipeline, _ := createTestPipeline(t, sink)
+	defer pipeline.Stop()
+
+	// Forward must never block even if the sink is down and all queues are full
+	start := time.Now()
+	for i := 0; i < 3*constants.LOG_SINK_QUEUE_SIZE; i++ {
+		pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "msg"))
+	}
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Greater(t, atomic.LoadInt32(&requests), int32(1))
+}
+
+func TestPipelineReload(t *testing.T) {
+	received := make(chan []*core.Log, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var logs []*core.Log
+		json.Unmarshal(body, &logs)
+		received <- logs
+	}))
+	defer server.Close()
+
+	pipeline, store := createTestPipeline(t)
+	defer pipeline.Stop()
+
+	// No sinks registered, the entry is discarded
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "discarded"))
+	assert.Eventually(t, func() bool {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+		return store.calls > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, server.URL)
+	sink.BatchSize = 1
+	store.setSinks(sink)
+	pipeline.Reload("test_colony")
+
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "forwarded"))
+
+	select {
+	case logs := <-received:
+		assert.Len(t, logs, 1)
+		assert.Equal(t, "forwarded", logs[0].Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for batch")
+	}
+
+	// Removed sinks stop their workers once the colony is reloaded
+	store.setSinks()
+	pipeline.Reload("test_colony")
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "not forwarded"))
+
+	select {
+	case <-received:
+		t.Fatal("Removed sink should not receive logs")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	pipeline.mutex.Lock()
+	assert.Len(t, pipeline.workers, 0)
+	pipeline.mutex.Unlock()
+}
+
+func TestPipelineValidate(t *testing.T) {
+	pipeline, _ := createTestPipeline(t)
+	defer pipeline.Stop()
+
+	assert.NotNil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "logs.jsonl")))
+	assert.Nil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, "http://127.0.0.1/logs")))
+	assert.NotNil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", "unknown", "http://127.0.0.1/logs")))
+
+	// Sinks are not allowed to connect to internal addresses
+	assert.NotNil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, "http://169.254.169.254/logs")))
+	assert.NotNil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeOTLP, "http://10.0.0.1:4318/v1/logs")))
+	assert.NotNil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeSyslog, "udp://192.168.1.1:514")))
+	assert.Nil(t, pipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeSyslog, "udp://8.8.8.8:514")))
+
+	store := &storeMock{sinks: []*core.LogSink{core.CreateLogSink("test_colony", "other_sink", core.LogSinkTypeFile, "logs.jsonl")}}
+	filePipeline := CreatePipeline(store, t.TempDir())
+	defer filePipeline.Stop()
+	assert.Nil(t, filePipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "other.jsonl")))
+	assert.Nil(t, filePipeline.Validate(core.CreateLogSink("another_colony", "test_sink", core.LogSinkTypeFile, "logs.jsonl")))
+
+	// Two sinks in a colony cannot write to the same file
+	assert.NotNil(t, filePipeline.Validate(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "./logs.jsonl")))
+}
+
+func TestPipelineFileSinkPath(t *testing.T) {
+	dir := t.TempDir()
+	sink1 := core.CreateLogSink("test_colony", "test_sink1", core.LogSinkTypeFile, "logs.jsonl")
+	sink1.ID = core.GenerateRandomID()
+	sink1.FlushInterval = 10
+	sink2 := core.CreateLogSink("test_colony", "test_sink2", core.LogSinkTypeFile, "logs.jsonl")
+	sink2.ID = core.GenerateRandomID()
+	sink3 := core.CreateLogSink("another_colony", "test_sink3", core.LogSinkTypeFile, "logs.jsonl")
+	sink3.ID = core.GenerateRandomID()
+	sink3.FlushInterval = 10
+
+	pipeline := CreatePipelineWithBackoff(&storeMock{sinks: []*core.LogSink{sink1, sink2, sink3}}, dir, createTestPolicy(t), 10*time.Millisecond, 50*time.Millisecond)
+
+	pipeline.Forward(createTestLog("test_colony", core.LogLevelInfo, "msg1"))
+	pipeline.Forward(createTestLog("another_colony", core.LogLevelInfo, "msg2"))
+
+	// Only one of the sinks writing to the same file gets a writer
+	assert.Eventually(t, func() bool {
+		pipeline.mutex.Lock()
+		defer pipeline.mutex.Unlock()
+		return len(pipeline.workers) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	pipeline.Stop()
+
+	data, err := os.ReadFile(filepath.Join(dir, "test_colony", "logs.jsonl"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "msg1"))
+
+	data, err = os.ReadFile(filepath.Join(dir, "another_colony", "logs.jsonl"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "msg2"))
+
+	_, err = FileSinkPath(core.CreateLogSink("..", "test_sink", core.LogSinkTypeFile, "logs.jsonl"), dir)
+	assert.NotNil(t, err)
+
+	path, err := FileSinkPath(core.CreateLogSink("a/../b", "test_sink", core.LogSinkTypeFile, "logs.jsonl"), dir)
+	assert.Nil(t, err)
+	assert.Equal(t, dir, filepath.Dir(filepath.Dir(path)))
+}
+
+func TestFileWriterRotation(t *testing.T) {
+	dir := t.TempDir()
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "colony/logs.jsonl")
+	sink.MaxSize = 300
+	sink.MaxFiles = 2
+
+	writer, err := CreateWriter(sink, dir, createTestPolicy(t))
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		err = writer.Write([]*core.Log{createTestLog("test_colony", core.LogLevelInfo, "message")})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+
+	path := filepath.Join(dir, "test_colony", "colony", "logs.jsonl")
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		file, err := os.Open(p)
+		assert.Nil(t, err)
+		scanner := bufio.NewScanner(file)
+		lines := 0
+		for scanner.Scan() {
+			logEntry, err := core.ConvertJSONToLog(scanner.Text())
+			assert.Nil(t, err)
+			assert.Equal(t, "message", logEntry.Message)
+			lines++
+		}
+		file.Close()
+		assert.Greater(t, lines, 0)
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = CreateWriter(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "../logs.jsonl"), dir, createTestPolicy(t))
+	assert.NotNil(t, err)
+
+	_, err = CreateWriter(core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeFile, "logs.jsonl"), "", createTestPolicy(t))
+	assert.NotNil(t, err)
+}
+
+func TestSyslogWriter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeSyslog, "udp://"+conn.LocalAddr().String())
+
+	// The default policy does not allow connections to the loopback interface
+	writer, err := CreateWriter(sink, "", egress.LoadPolicy())
+	assert.Nil(t, err)
+	assert.NotNil(t, writer.Write([]*core.Log{createTestLog("test_colony", core.LogLevelInfo, "denied")}))
+
+	writer, err = CreateWriter(sink, "", createTestPolicy(t))
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	logEntry := createTestLog("test_colony", core.LogLevelError, "syslog \"message\"\n")
+	err = writer.Write([]*core.Log{logEntry})
+	assert.Nil(t, err)
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<11>1 "))
+	assert.Contains(t, msg, " colonies "+logEntry.ProcessID+" - ")
+	assert.Contains(t, msg, `colony="test_colony"`)
+	assert.Contains(t, msg, `step="1"`)
+	assert.True(t, strings.HasSuffix(msg, "] syslog \"message\""))
+}
@@ -0,0 +1,151 @@
+package logsink
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+)
+
+const syslogFacilityUser = 1
+const syslogAppName = "colonies"
+const syslogSDID = "colonies@32473"
+
+// syslogWriter sends log entries as RFC 5424 messages to a remote syslog server. TCP messages use
+// octet counting framing as described in RFC 6587. The connection is re-established after a failure.
+type syslogWriter struct {
+	network  string
+	address  string
+	hostname string
+	dialer   *net.Dialer
+	conn     net.Conn
+}
+
+func createSyslogWriter(sink *core.LogSink, egressPolicy *egress.Policy) (*syslogWriter, error) {
+	u, err := url.Parse(sink.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	dialer := egressPolicy.Dialer(time.Duration(constants.LOG_SINK_TIMEOUT) * time.Second)
+
+	return &syslogWriter{network: u.Scheme, address: u.Host, hostname: hostname, dialer: dialer}, nil
+}
+
+func syslogSeverity(logEntry *core.Log) int {
+	switch logEntry.Level {
+	case core.LogLevelDebug:
+		return 7
+	case core.LogLevelInfo:
+		return 6
+	case core.LogLevelWarn:
+		return 4
+	case core.LogLevelError:
+		return 3
+	}
+
+	if logEntry.Stream == core.LogStreamStderr {
+		return 3
+	}
+
+	return 5
+}
+
+// escapeSDValue escapes characters that are not allowed in RFC 5424 structured data values
+func escapeSDValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return replacer.Replace(value)
+}
+
+func nilValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+func (w *syslogWriter) format(logEntry *core.Log) string {
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	sd.WriteString(` colony="` + escapeSDValue(logEntry.ColonyName) + `"`)
+	if logEntry.ExecutorName != "" {
+		sd.WriteString(` executor="` + escapeSDValue(logEntry.ExecutorName) + `"`)
+	}
+	if logEntry.ProcessGraphID != "" {
+		sd.WriteString(` processgraph="` + escapeSDValue(logEntry.ProcessGraphID) + `"`)
+	}
+	if logEntry.Stream != "" {
+		sd.WriteString(` stream="` + escapeSDValue(logEntry.Stream) + `"`)
+	}
+
+	keys := make([]string, 0, len(logEntry.Fields))
+	for key := range logEntry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// SD-NAMEs cannot contain '=', ' ', ']' or '"', such fields are skipped
+		if strings.ContainsAny(key, "= ]\"") || len(key) > 32 {
+			continue
+		}
+		sd.WriteString(" " + key + `="` + escapeSDValue(logEntry.Fields[key]) + `"`)
+	}
+	sd.WriteString("]")
+
+	pri := syslogFacilityUser*8 + syslogSeverity(logEntry)
+	timestamp := time.Unix(0, logEntry.Timestamp).UTC().Format(time.RFC3339Nano)
+	message := strings.TrimRight(logEntry.Message, "\n")
+
+	return "<" + strconv.Itoa(pri) + ">1 " + timestamp + " " + w.hostname + " " + syslogAppName + " " + nilValue(logEntry.ProcessID) + " - " + sd.String() + " " + message
+}
+
+func (w *syslogWriter) Write(logs []*core.Log) error {
+	if w.conn == nil {
+		conn, err := w.dialer.Dial(w.network, w.address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(time.Duration(constants.LOG_SINK_TIMEOUT) * time.Second))
+
+	for _, logEntry := range logs {
+		msg := w.format(logEntry)
+		if w.network == "tcp" {
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		}
+
+		if _, err := w.conn.Write([]byte(msg)); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *syslogWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
@@ -0,0 +1,35 @@
+package logsink
+
+import (
+	"errors"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+)
+
+// Writer writes batches of log entries to an external system. Writes are never called concurrently,
+// a failed write is retried with the same batch.
+type Writer interface {
+	Write(logs []*core.Log) error
+	Close() error
+}
+
+// CreateWriter creates a writer for the sink, file sinks are created in dir. Syslog, HTTP and OTLP
+// writers refuse to connect to addresses not allowed by the egress policy.
+func CreateWriter(sink *core.LogSink, dir string, egressPolicy *egress.Policy) (Writer, error) {
+	switch sink.Type {
+	case core.LogSinkTypeFile:
+		if dir == "" {
+			return nil, errors.New("File log sinks are disabled, no log sink directory configured")
+		}
+		return createFileWriter(sink, dir)
+	case core.LogSinkTypeSyslog:
+		return createSyslogWriter(sink, egressPolicy)
+	case core.LogSinkTypeHTTP:
+		return createHTTPWriter(sink, encodeJSON, "application/json", egressPolicy), nil
+	case core.LogSinkTypeOTLP:
+		return createHTTPWriter(sink, encodeOTLP, "application/json", egressPolicy), nil
+	}
+
+	return nil, errors.New("Unknown log sink type <" + sink.Type + ">")
+}
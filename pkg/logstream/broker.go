@@ -0,0 +1,217 @@
+package logstream
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/colonyos/colonies/pkg/cluster"
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// RelayType identifies log entries among the messages sent through the cluster relay server
+const RelayType = "log"
+
+// SubscriberBufferSize is the number of log entries buffered per subscriber, a subscriber that
+// falls further behind is disconnected and has to resubscribe
+const SubscriberBufferSize = 1000
+
+// RelayQueueSize is the number of log entries waiting to be relayed to the rest of the cluster,
+// entries published while the queue is full are not relayed
+const RelayQueueSize = 10000
+
+// MaxRelayBatchSize is the max number of log entries relayed in a single message
+const MaxRelayBatchSize = 500
+
+type relayMsg struct {
+	RelayType string      `json:"relaytype"`
+	Logs      []*core.Log `json:"logs"`
+}
+
+// Subscriber receives log entries matching its filter on C. C is closed when the subscriber
+// is unsubscribed or disconnected for being too slow.
+type Subscriber struct {
+	C      chan *core.Log
+	filter *core.LogFilter
+	closed bool
+}
+
+// Broker delivers log entries to subscribers as they are added. Entries are relayed to the
+// other servers in the cluster, so subscribers receive entries added on any server. Relaying
+// never blocks the caller, entries are queued in a bounded queue and relayed in batches by a
+// single worker.
+type Broker struct {
+	subscribers map[*Subscriber]bool
+	mutex       sync.Mutex
+	broadcast   func(msg []byte) error
+	relayQueue  chan *core.Log
+	dropping    bool
+	stop        chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup
+}
+
+func CreateBroker(relayServer *cluster.RelayServer) *Broker {
+	if relayServer == nil {
+		return createBroker(nil)
+	}
+
+	broker := createBroker(relayServer.Broadcast)
+	relayServer.Subscribe(broker.handleRelayMsg)
+
+	return broker
+}
+
+func createBroker(broadcast func(msg []byte) error) *Broker {
+	broker := &Broker{}
+	broker.subscribers = make(map[*Subscriber]bool)
+	broker.broadcast = broadcast
+	broker.relayQueue = make(chan *core.Log, RelayQueueSize)
+	broker.stop = make(chan struct{})
+
+	if broadcast != nil {
+		broker.wg.Add(1)
+		go broker.relayWorker()
+	}
+
+	return broker
+}
+
+// Stop stops relaying log entries, entries still in the queue are not relayed
+func (broker *Broker) Stop() {
+	broker.stopOnce.Do(func() {
+		close(broker.stop)
+	})
+	broker.wg.Wait()
+}
+
+// Subscribe registers a subscriber for log entries matching filter
+func (broker *Broker) Subscribe(filter *core.LogFilter) *Subscriber {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	subscriber := &Subscriber{C: make(chan *core.Log, SubscriberBufferSize), filter: filter}
+	broker.subscribers[subscriber] = true
+
+	return subscriber
+}
+
+// Unsubscribe removes a subscriber and closes its channel
+func (broker *Broker) Unsubscribe(subscriber *Subscriber) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.remove(subscriber)
+}
+
+func (broker *Broker) remove(subscriber *Subscriber) {
+	delete(broker.subscribers, subscriber)
+	if !subscriber.closed {
+		subscriber.closed = true
+		close(subscriber.C)
+	}
+}
+
+// Subscribers returns the number of registered subscribers
+func (broker *Broker) Subscribers() int {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	return len(broker.subscribers)
+}
+
+// Publish delivers a log entry to local subscribers and queues it for relay to the rest of the cluster
+func (broker *Broker) Publish(logEntry *core.Log) {
+	broker.publishNoRelay(logEntry)
+
+	if broker.broadcast == nil {
+		return
+	}
+
+	select {
+	case <-broker.stop:
+		return
+	default:
+	}
+
+	select {
+	case broker.relayQueue <- logEntry:
+	default:
+		broker.mutex.Lock()
+		if !broker.dropping {
+			log.WithFields(log.Fields{"ColonyName": logEntry.ColonyName}).Warning("Log relay queue is full, log entries are not relayed")
+		}
+		broker.dropping = true
+		broker.mutex.Unlock()
+	}
+}
+
+func (broker *Broker) relayWorker() {
+	defer broker.wg.Done()
+
+	for {
+		select {
+		case <-broker.stop:
+			return
+		case logEntry := <-broker.relayQueue:
+			batch := []*core.Log{logEntry}
+		drain:
+			for len(batch) < MaxRelayBatchSize {
+				select {
+				case logEntry := <-broker.relayQueue:
+					batch = append(batch, logEntry)
+				default:
+					break drain
+				}
+			}
+			broker.relay(batch)
+		}
+	}
+}
+
+func (broker *Broker) relay(logs []*core.Log) {
+	broker.mutex.Lock()
+	broker.dropping = false
+	broker.mutex.Unlock()
+
+	jsonBytes, err := json.Marshal(&relayMsg{RelayType: RelayType, Logs: logs})
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to marshal logs for relay")
+		return
+	}
+
+	if err := broker.broadcast(jsonBytes); err != nil {
+		log.WithFields(log.Fields{"Error": err, "Count": len(logs)}).Warning("Failed to relay logs")
+	}
+}
+
+func (broker *Broker) publishNoRelay(logEntry *core.Log) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for subscriber := range broker.subscribers {
+		if !logEntry.Match(subscriber.filter) {
+			continue
+		}
+
+		select {
+		case subscriber.C <- logEntry:
+		default:
+			log.WithFields(log.Fields{"ProcessID": logEntry.ProcessID, "ExecutorName": logEntry.ExecutorName}).Warning("Log subscriber too slow, disconnecting")
+			broker.remove(subscriber)
+		}
+	}
+}
+
+func (broker *Broker) handleRelayMsg(data []byte) {
+	var msg relayMsg
+	if err := json.Unmarshal(data, &msg); err != nil || msg.RelayType != RelayType {
+		return // Not log entries, e.g. a process event
+	}
+
+	for _, logEntry := range msg.Logs {
+		if logEntry != nil {
+			broker.publishNoRelay(logEntry)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package logstream
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type logDBMock struct {
+	database.LogDatabase
+	logs []*core.Log
+	err  error
+}
+
+func (db *logDBMock) GetLogs(filter *core.LogFilter) ([]*core.Log, error) {
+	var logs []*core.Log
+	for _, logEntry := range db.logs {
+		if logEntry.Match(filter) && len(logs) < filter.Count {
+			logs = append(logs, logEntry)
+		}
+	}
+
+	return logs, db.err
+}
+
+type validatorMock struct {
+	err error
+}
+
+func (v *validatorMock) RequireServerOwner(recoveredID string, serverID string) error { return nil }
+func (v *validatorMock) RequireColonyOwner(recoveredID string, colonyID string) error { return nil }
+func (v *validatorMock) RequireMembership(recoveredID string, colonyID string, approved bool) error {
+	return v.err
+}
+
+type processDBMock struct {
+	database.ProcessDatabase
+	process *core.Process
+}
+
+func (db *processDBMock) GetProcessByID(processID string) (*core.Process, error) {
+	return db.process, nil
+}
+
+type executorDBMock struct {
+	database.ExecutorDatabase
+	executor *core.Executor
+}
+
+func (db *executorDBMock) GetExecutorByName(colonyName string, executorName string) (*core.Executor, error) {
+	return db.executor, nil
+}
+
+type processGraphDBMock struct {
+	database.ProcessGraphDatabase
+	processGraph *core.ProcessGraph
+}
+
+func (db *processGraphDBMock) GetProcessGraphByID(processGraphID string) (*core.ProcessGraph, error) {
+	return db.processGraph, nil
+}
+
+func TestBrokerPublish(t *testing.T) {
+	broker := CreateBroker(nil)
+
+	processSubscriber := broker.Subscribe(&core.LogFilter{ProcessID: "test_processid"})
+	executorSubscriber := broker.Subscribe(&core.LogFilter{ColonyName: "test_colony", ExecutorName: "test_executor"})
+	graphSubscriber := broker.Subscribe(&core.LogFilter{ProcessGraphID: "test_processgraphid", Level: core.LogLevelWarn})
+	assert.Equal(t, 3, broker.Subscribers())
+
+	broker.Publish(&core.Log{ProcessID: "test_processid", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor", Level: core.LogLevelInfo, Message: "1"})
+	broker.Publish(&core.Log{ProcessID: "test_processid2", ProcessGraphID: "test_processgraphid", ColonyName: "test_colony", ExecutorName: "test_executor2", Level: core.LogLevelError, Message: "2"})
+
+	assert.Len(t, processSubscriber.C, 1)
+	assert.Len(t, executorSubscriber.C, 1)
+	assert.Len(t, graphSubscriber.C, 1)
+	assert.Equal(t, "2", (<-graphSubscriber.C).Message)
+
+	broker.Unsubscribe(processSubscriber)
+	broker.Unsubscribe(processSubscriber)
+	assert.Equal(t, 2, broker.Subscribers())
+	<-processSubscriber.C
+	_, ok := <-processSubscriber.C
+	assert.False(t, ok)
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := CreateBroker(nil)
+
+	subscriber := broker.Subscribe(&core.LogFilter{ProcessID: "test_processid"})
+	for i := 0; i <= SubscriberBufferSize; i++ {
+		broker.Publish(&core.Log{ProcessID: "test_processid", Message: "test_msg"})
+	}
+
+	assert.Equal(t, 0, broker.Subscribers())
+	for range subscriber.C {
+	}
+	broker.Unsubscribe(subscriber)
+}
+
+func TestBrokerRelayMsg(t *testing.T) {
+	broker := CreateBroker(nil)
+	subscriber := broker.Subscribe(&core.LogFilter{ProcessID: "test_processid"})
+
+	jsonBytes, err := json.Marshal(&relayMsg{RelayType: RelayType, Logs: []*core.Log{{ProcessID: "test_processid", Message: "test_msg"}, {ProcessID: "another_processid"}}})
+	assert.Nil(t, err)
+	broker.handleRelayMsg(jsonBytes)
+
+	process := core.CreateProcess(core.CreateEmptyFunctionSpec())
+	processJSON, err := process.ToJSON()
+	assert.Nil(t, err)
+	broker.handleRelayMsg([]byte(processJSON))
+	broker.handleRelayMsg([]byte("invalid json"))
+
+	assert.Len(t, subscriber.C, 1)
+	assert.Equal(t, "test_msg", (<-subscriber.C).Message)
+}
+
+func TestBrokerRelayBatches(t *testing.T) {
+	relayed := make(chan []*core.Log, 10)
+	block := make(chan struct{})
+	broker := createBroker(func(msg []byte) error {
+		<-block
+		var relay relayMsg
+		err := json.Unmarshal(msg, &relay)
+		assert.Nil(t, err)
+		relayed <- relay.Logs
+		return nil
+	})
+	defer broker.Stop()
+
+	// The worker blocks relaying the first entry, the rest are queued and relayed in batches,
+	// entries published while the queue is full are dropped without blocking the caller
+	broker.Publish(&core.Log{Message: "first"})
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < RelayQueueSize+10; i++ {
+		broker.Publish(&core.Log{Message: "test_msg"})
+	}
+	close(block)
+
+	count := 0
+	batches := 0
+	timeout := time.After(5 * time.Second)
+	for count < RelayQueueSize+1 {
+		select {
+		case logs := <-relayed:
+			assert.LessOrEqual(t, len(logs), MaxRelayBatchSize)
+			count += len(logs)
+			batches++
+		case <-timeout:
+			t.Fatal("Logs were not relayed")
+		}
+	}
+	assert.Equal(t, RelayQueueSize+1, count)
+	assert.Equal(t, 1+RelayQueueSize/MaxRelayBatchSize, batches)
+}
+
+func TestBrokerNoRelay(t *testing.T) {
+	broker := CreateBroker(nil)
+	broker.Publish(&core.Log{Message: "test_msg"})
+	assert.Len(t, broker.relayQueue, 0)
+	broker.Stop()
+}
+
+func TestBrokerFollow(t *testing.T) {
+	broker := CreateBroker(nil)
+	logDB := &logDBMock{logs: []*core.Log{
+		{ProcessID: "test_processid", Message: "1", Timestamp: 1},
+		{ProcessID: "test_processid", Message: "2", Timestamp: 2},
+		{ProcessID: "test_processid2", Message: "3", Timestamp: 3},
+	}}
+
+	batches := make(chan []*core.Log, 10)
+	done := make(chan error)
+	go func() {
+		done <- broker.Follow(logDB, &core.LogFilter{ProcessID: "test_processid", Since: 1}, 500*time.Millisecond, nil, func(logs []*core.Log) error {
+			batches <- logs
+			return nil
+		})
+	}()
+
+	backlog := <-batches
+	assert.Len(t, backlog, 1)
+	assert.Equal(t, "2", backlog[0].Message)
+
+	broker.Publish(&core.Log{ProcessID: "test_processid", Message: "2", Timestamp: 2}) // Already sent
+	broker.Publish(&core.Log{ProcessID: "test_processid2", Message: "4", Timestamp: 4})
+	broker.Publish(&core.Log{ProcessID: "test_processid", Message: "5", Timestamp: 5})
+
+	live := <-batches
+	assert.Len(t, live, 1)
+	assert.Equal(t, "5", live[0].Message)
+
+	timeout := <-batches
+	assert.Len(t, timeout, 0)
+	assert.Nil(t, <-done)
+	assert.Equal(t, 0, broker.Subscribers())
+}
+
+func TestBrokerFollowDone(t *testing.T) {
+	broker := CreateBroker(nil)
+	closeChan := make(chan struct{})
+	close(closeChan)
+
+	err := broker.Follow(&logDBMock{}, &core.LogFilter{ProcessID: "test_processid"}, time.Minute, closeChan, func(logs []*core.Log) error {
+		return errors.New("no logs should be sent")
+	})
+	assert.Nil(t, err)
+
+	err = broker.Follow(&logDBMock{err: errors.New("db error")}, &core.LogFilter{ProcessID: "test_processid"}, time.Minute, closeChan, func(logs []*core.Log) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, broker.Subscribers())
+}
+
+func TestCreateFilter(t *testing.T) {
+	validator := &validatorMock{}
+	processDB := &processDBMock{process: &core.Process{ID: "test_processid", FunctionSpec: core.FunctionSpec{Conditions: core.Conditions{ColonyName: "test_colony"}}}}
+	executorDB := &executorDBMock{executor: &core.Executor{Name: "test_executor", ColonyName: "test_colony"}}
+	processGraphDB := &processGraphDBMock{processGraph: &core.ProcessGraph{ID: "test_processgraphid", ColonyName: "test_colony"}}
+
+	msg := rpc.CreateSubscribeLogsMsg("", "test_processid", "", "", 10, 1)
+	msg.Level = core.LogLevelWarn
+	filter, _, err := CreateFilter(msg, "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_colony", filter.ColonyName)
+	assert.Equal(t, "test_processid", filter.ProcessID)
+	assert.Equal(t, core.LogLevelWarn, filter.Level)
+	assert.Equal(t, int64(10), filter.Since)
+
+	filter, _, err = CreateFilter(rpc.CreateSubscribeLogsMsg("test_colony", "", "test_executor", "", 0, 1), "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_executor", filter.ExecutorName)
+
+	filter, _, err = CreateFilter(rpc.CreateSubscribeLogsMsg("", "", "", "test_processgraphid", 0, 1), "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_processgraphid", filter.ProcessGraphID)
+	assert.Equal(t, "test_colony", filter.ColonyName)
+
+	_, errorCode, err := CreateFilter(rpc.CreateSubscribeLogsMsg("", "", "", "", 0, 1), "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, errorCode)
+
+	_, errorCode, err = CreateFilter(rpc.CreateSubscribeLogsMsg("", "test_processid", "test_executor", "", 0, 1), "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, errorCode)
+
+	msg = rpc.CreateSubscribeLogsMsg("", "test_processid", "", "", 0, 1)
+	msg.Stream = "stdin"
+	_, errorCode, err = CreateFilter(msg, "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, errorCode)
+
+	_, errorCode, err = CreateFilter(rpc.CreateSubscribeLogsMsg("", "test_processid", "", "", 0, 1), "test_id", validator, &processDBMock{}, executorDB, processGraphDB)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, errorCode)
+
+	validator.err = errors.New("not a member")
+	_, errorCode, err = CreateFilter(rpc.CreateSubscribeLogsMsg("", "", "", "test_processgraphid", 0, 1), "test_id", validator, processDB, executorDB, processGraphDB)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusForbidden, errorCode)
+}
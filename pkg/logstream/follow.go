@@ -0,0 +1,145 @@
+package logstream
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+)
+
+// MaxBacklogCount is the max number of stored log entries sent in each batch when a subscription starts
+const MaxBacklogCount = 500
+
+// DefaultTimeout is used if a subscription does not specify a timeout
+const DefaultTimeout = 30 * time.Second
+
+// CreateFilter validates a subscribe logs message and checks that the caller is a member of the
+// colony owning the process, executor or process graph. Returns the filter selecting the
+// subscribed logs, or an error and a HTTP status code.
+func CreateFilter(msg *rpc.SubscribeLogsMsg,
+	recoveredID string,
+	validator security.Validator,
+	processDB database.ProcessDatabase,
+	executorDB database.ExecutorDatabase,
+	processGraphDB database.ProcessGraphDatabase) (*core.LogFilter, int, error) {
+	targets := 0
+	for _, id := range []string{msg.ProcessID, msg.ExecutorName, msg.ProcessGraphID} {
+		if id != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, http.StatusBadRequest, errors.New("Failed to subscribe to logs, exactly one of process Id, executor name and process graph Id must be specified")
+	}
+
+	if err := core.ValidateLogLevel(msg.Level); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err := core.ValidateLogStream(msg.Stream); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	filter := &core.LogFilter{
+		ProcessID:      msg.ProcessID,
+		ExecutorName:   msg.ExecutorName,
+		ProcessGraphID: msg.ProcessGraphID,
+		Level:          msg.Level,
+		Stream:         msg.Stream,
+		Fields:         msg.Fields,
+		Since:          msg.Since,
+	}
+
+	switch {
+	case msg.ProcessID != "":
+		process, err := processDB.GetProcessByID(msg.ProcessID)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if process == nil {
+			return nil, http.StatusNotFound, errors.New("Failed to subscribe to logs, process does not exist")
+		}
+		filter.ColonyName = process.FunctionSpec.Conditions.ColonyName
+	case msg.ExecutorName != "":
+		executor, err := executorDB.GetExecutorByName(msg.ColonyName, msg.ExecutorName)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if executor == nil {
+			return nil, http.StatusNotFound, errors.New("Failed to subscribe to logs, executor does not exist")
+		}
+		filter.ColonyName = executor.ColonyName
+	default:
+		processGraph, err := processGraphDB.GetProcessGraphByID(msg.ProcessGraphID)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if processGraph == nil {
+			return nil, http.StatusNotFound, errors.New("Failed to subscribe to logs, process graph does not exist")
+		}
+		filter.ColonyName = processGraph.ColonyName
+	}
+
+	if err := validator.RequireMembership(recoveredID, filter.ColonyName, true); err != nil {
+		return nil, http.StatusForbidden, err
+	}
+
+	return filter, http.StatusOK, nil
+}
+
+// Follow sends stored log entries matching filter, followed by new entries as they are
+// published, until timeout or until done is closed. Entries are sent in batches, an empty
+// batch is sent when the subscription times out.
+func (broker *Broker) Follow(logDB database.LogDatabase, filter *core.LogFilter, timeout time.Duration, done <-chan struct{}, send func(logs []*core.Log) error) error {
+	// Subscribe before reading stored entries so that no entry added in between is missed
+	subscriber := broker.Subscribe(filter)
+	defer broker.Unsubscribe(subscriber)
+
+	backlogFilter := *filter
+	backlogFilter.Count = MaxBacklogCount
+	for {
+		logs, err := logDB.GetLogs(&backlogFilter)
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			break
+		}
+		if err := send(logs); err != nil {
+			return err
+		}
+		backlogFilter.Since = logs[len(logs)-1].Timestamp
+		if len(logs) < MaxBacklogCount {
+			break
+		}
+	}
+	backlogTimestamp := backlogFilter.Since
+
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case logEntry, ok := <-subscriber.C:
+			if !ok {
+				return errors.New("Log subscriber disconnected, too slow")
+			}
+			if logEntry.Timestamp <= backlogTimestamp {
+				continue // Already sent as part of the stored entries
+			}
+			if err := send([]*core.Log{logEntry}); err != nil {
+				return err
+			}
+		case <-timer.C:
+			return send([]*core.Log{})
+		case <-done:
+			return nil
+		}
+	}
+}
@@ -9,8 +9,11 @@ import (
 const AddFilePayloadType = "addfilemsg"
 
 type AddFileMsg struct {
-	File    *core.File `json:"file"`
-	MsgType string     `json:"msgtype"`
+	File *core.File `json:"file"`
+	// ProcessID is set when the file is added by the executor assigned to a process, the file is recorded
+	// as an output of the process
+	ProcessID string `json:"processid,omitempty"`
+	MsgType   string `json:"msgtype"`
 }
 
 func CreateAddFileMsg(file *core.File) *AddFileMsg {
@@ -35,7 +38,7 @@ func (msg *AddFileMsg) Equals(msg2 *AddFileMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.File.Equals(msg2.File) {
+	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID && msg.File.Equals(msg2.File) {
 		return true
 	}
 
@@ -43,4 +43,14 @@ func TestRPCAddFileMsgEquals(t *testing.T) {
 	msg := CreateAddFileMsg(file)
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateAddFileMsg(file)
+	msg2.ProcessID = "test_process_id"
+	assert.False(t, msg.Equals(msg2))
+
+	jsonString, err := msg2.ToJSON()
+	assert.Nil(t, err)
+	msg3, err := CreateAddFileMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg2.Equals(msg3))
 }
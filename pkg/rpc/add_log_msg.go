@@ -2,14 +2,19 @@ package rpc
 
 import (
 	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
 )
 
 const AddLogPayloadType = "addlogmsg"
 
 type AddLogMsg struct {
-	ProcessID string `json:"processid"`
-	Message   string `json:"message"`
-	MsgType   string `json:"msgtype"`
+	ProcessID string            `json:"processid"`
+	Message   string            `json:"message"`
+	Level     string            `json:"level,omitempty"`
+	Stream    string            `json:"stream,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	MsgType   string            `json:"msgtype"`
 }
 
 func CreateAddLogMsg(processID string, logmsg string) *AddLogMsg {
@@ -21,6 +26,15 @@ func CreateAddLogMsg(processID string, logmsg string) *AddLogMsg {
 	return msg
 }
 
+func CreateAddStructuredLogMsg(processID string, level string, stream string, fields map[string]string, logmsg string) *AddLogMsg {
+	msg := CreateAddLogMsg(processID, logmsg)
+	msg.Level = level
+	msg.Stream = stream
+	msg.Fields = fields
+
+	return msg
+}
+
 func (msg *AddLogMsg) ToJSON() (string, error) {
 	jsonBytes, err := json.Marshal(msg)
 	if err != nil {
@@ -35,7 +49,12 @@ func (msg *AddLogMsg) Equals(msg2 *AddLogMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID && msg.Message == msg2.Message {
+	if msg.MsgType == msg2.MsgType &&
+		msg.ProcessID == msg2.ProcessID &&
+		msg.Message == msg2.Message &&
+		msg.Level == msg2.Level &&
+		msg.Stream == msg2.Stream &&
+		core.IsStringMapsEqual(msg.Fields, msg2.Fields) {
 		return true
 	}
 
@@ -40,3 +40,16 @@ func TestRPCAddLogMsgEquals(t *testing.T) {
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
 }
+
+func TestRPCAddStructuredLogMsg(t *testing.T) {
+	msg := CreateAddStructuredLogMsg(core.GenerateRandomID(), core.LogLevelError, core.LogStreamStderr, map[string]string{"step": "1"}, "test_msg")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddLogMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+
+	msg2.Level = core.LogLevelInfo
+	assert.False(t, msg.Equals(msg2))
+}
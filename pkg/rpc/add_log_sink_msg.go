@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const AddLogSinkPayloadType = "addlogsinkmsg"
+
+type AddLogSinkMsg struct {
+	LogSink *core.LogSink `json:"logsink"`
+	MsgType string        `json:"msgtype"`
+}
+
+func CreateAddLogSinkMsg(sink *core.LogSink) *AddLogSinkMsg {
+	msg := &AddLogSinkMsg{}
+	msg.LogSink = sink
+	msg.MsgType = AddLogSinkPayloadType
+
+	return msg
+}
+
+func (msg *AddLogSinkMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddLogSinkMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddLogSinkMsg) Equals(msg2 *AddLogSinkMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.LogSink.Equals(msg2.LogSink) {
+		return true
+	}
+
+	return false
+}
+
+func CreateAddLogSinkMsgFromJSON(jsonString string) (*AddLogSinkMsg, error) {
+	var msg *AddLogSinkMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCAddLogSinkMsg(t *testing.T) {
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, "http://localhost:8080/logs")
+	msg := CreateAddLogSinkMsg(sink)
+	assert.Equal(t, AddLogSinkPayloadType, msg.MsgType)
+	assert.True(t, sink.Equals(msg.LogSink))
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddLogSinkMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddLogSinkMsgToJSONIndent(t *testing.T) {
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, "http://localhost:8080/logs")
+	msg := CreateAddLogSinkMsg(sink)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCAddLogSinkMsgEqualsNil(t *testing.T) {
+	sink := core.CreateLogSink("test_colony", "test_sink", core.LogSinkTypeHTTP, "http://localhost:8080/logs")
+	msg := CreateAddLogSinkMsg(sink)
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCAddLogSinkMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateAddLogSinkMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const AddStorageBackendPayloadType = "addstoragebackendmsg"
+
+type AddStorageBackendMsg struct {
+	StorageBackend *core.StorageBackend `json:"storagebackend"`
+	MsgType        string               `json:"msgtype"`
+}
+
+func CreateAddStorageBackendMsg(backend *core.StorageBackend) *AddStorageBackendMsg {
+	msg := &AddStorageBackendMsg{}
+	msg.StorageBackend = backend
+	msg.MsgType = AddStorageBackendPayloadType
+
+	return msg
+}
+
+func (msg *AddStorageBackendMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddStorageBackendMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddStorageBackendMsg) Equals(msg2 *AddStorageBackendMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.StorageBackend.Equals(msg2.StorageBackend) {
+		return true
+	}
+
+	return false
+}
+
+func CreateAddStorageBackendMsgFromJSON(jsonString string) (*AddStorageBackendMsg, error) {
+	var msg *AddStorageBackendMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
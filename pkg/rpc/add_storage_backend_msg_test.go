@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCAddStorageBackendMsg(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	msg := CreateAddStorageBackendMsg(backend)
+	assert.Equal(t, AddStorageBackendPayloadType, msg.MsgType)
+	assert.True(t, backend.Equals(msg.StorageBackend))
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddStorageBackendMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddStorageBackendMsgToJSONIndent(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	msg := CreateAddStorageBackendMsg(backend)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCAddStorageBackendMsgEqualsNil(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+	msg := CreateAddStorageBackendMsg(backend)
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCAddStorageBackendMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateAddStorageBackendMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const AddWebhookPayloadType = "addwebhookmsg"
+
+type AddWebhookMsg struct {
+	Webhook *core.Webhook `json:"webhook"`
+	MsgType string        `json:"msgtype"`
+}
+
+func CreateAddWebhookMsg(webhook *core.Webhook) *AddWebhookMsg {
+	msg := &AddWebhookMsg{}
+	msg.Webhook = webhook
+	msg.MsgType = AddWebhookPayloadType
+
+	return msg
+}
+
+func (msg *AddWebhookMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddWebhookMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *AddWebhookMsg) Equals(msg2 *AddWebhookMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.Webhook.Equals(msg2.Webhook) {
+		return true
+	}
+
+	return false
+}
+
+func CreateAddWebhookMsgFromJSON(jsonString string) (*AddWebhookMsg, error) {
+	var msg *AddWebhookMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
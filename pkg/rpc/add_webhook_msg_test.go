@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCAddWebhookMsg(t *testing.T) {
+	webhook := core.CreateWebhook("test_colony", "test_name", "http://localhost:8080/hook", "test_secret")
+	webhook.States = []int{core.FAILED}
+	msg := CreateAddWebhookMsg(webhook)
+	assert.Equal(t, AddWebhookPayloadType, msg.MsgType)
+	assert.True(t, webhook.Equals(msg.Webhook))
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateAddWebhookMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCAddWebhookMsgToJSONIndent(t *testing.T) {
+	webhook := core.CreateWebhook("test_colony", "test_name", "http://localhost:8080/hook", "test_secret")
+	msg := CreateAddWebhookMsg(webhook)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCAddWebhookMsgEqualsNil(t *testing.T) {
+	webhook := core.CreateWebhook("test_colony", "test_name", "http://localhost:8080/hook", "test_secret")
+	msg := CreateAddWebhookMsg(webhook)
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCAddWebhookMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateAddWebhookMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
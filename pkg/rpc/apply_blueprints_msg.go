@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const ApplyBlueprintsPayloadType = "applyblueprintsmsg"
+
+type ApplyBlueprintsMsg struct {
+	Namespace  string            `json:"namespace"`
+	Blueprints []*core.Blueprint `json:"blueprints"`
+	Prune      bool              `json:"prune"`     // Remove blueprints labelled with ManagedBy that are not in Blueprints
+	ManagedBy  string            `json:"managedby"` // Value of the core.ManagedByLabel label set on applied blueprints
+	DryRun     bool              `json:"dryrun"`    // Only compute the plan
+	MsgType    string            `json:"msgtype"`
+}
+
+func CreateApplyBlueprintsMsg(namespace string, blueprints []*core.Blueprint) *ApplyBlueprintsMsg {
+	msg := &ApplyBlueprintsMsg{}
+	msg.Namespace = namespace
+	msg.Blueprints = blueprints
+	msg.MsgType = ApplyBlueprintsPayloadType
+
+	return msg
+}
+
+func (msg *ApplyBlueprintsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ApplyBlueprintsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ApplyBlueprintsMsg) Equals(msg2 *ApplyBlueprintsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType != msg2.MsgType ||
+		msg.Namespace != msg2.Namespace ||
+		msg.Prune != msg2.Prune ||
+		msg.ManagedBy != msg2.ManagedBy ||
+		msg.DryRun != msg2.DryRun ||
+		len(msg.Blueprints) != len(msg2.Blueprints) {
+		return false
+	}
+
+	for i := range msg.Blueprints {
+		if msg.Blueprints[i].ID != msg2.Blueprints[i].ID || msg.Blueprints[i].Metadata.Name != msg2.Blueprints[i].Metadata.Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+func CreateApplyBlueprintsMsgFromJSON(jsonString string) (*ApplyBlueprintsMsg, error) {
+	var msg *ApplyBlueprintsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCApplyBlueprintsMsg(t *testing.T) {
+	web := core.CreateBlueprint("Deployment", "web", "test-colony")
+	db := core.CreateBlueprint("Deployment", "db", "test-colony")
+
+	msg := CreateApplyBlueprintsMsg("test-colony", []*core.Blueprint{web, db})
+	msg.Prune = true
+	msg.ManagedBy = "cli"
+	msg.DryRun = true
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateApplyBlueprintsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateApplyBlueprintsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+	assert.Len(t, msg2.Blueprints, 2)
+
+	msg2.Blueprints = msg2.Blueprints[:1]
+	assert.False(t, msg.Equals(msg2))
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCApplyBlueprintsMsgIndent(t *testing.T) {
+	msg := CreateApplyBlueprintsMsg("test-colony", []*core.Blueprint{core.CreateBlueprint("Deployment", "web", "test-colony")})
+
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateApplyBlueprintsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
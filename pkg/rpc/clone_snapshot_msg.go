@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const CloneSnapshotPayloadType = "clonesnapshotmsg"
+
+type CloneSnapshotMsg struct {
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+	Label      string `json:"label"`
+	MsgType    string `json:"msgtype"`
+}
+
+func CreateCloneSnapshotMsg(colonyName string, name string, label string) *CloneSnapshotMsg {
+	msg := &CloneSnapshotMsg{}
+	msg.MsgType = CloneSnapshotPayloadType
+	msg.ColonyName = colonyName
+	msg.Name = name
+	msg.Label = label
+
+	return msg
+}
+
+func (msg *CloneSnapshotMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *CloneSnapshotMsg) Equals(msg2 *CloneSnapshotMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Name == msg2.Name &&
+		msg.Label == msg2.Label {
+		return true
+	}
+
+	return false
+}
+
+func CreateCloneSnapshotMsgFromJSON(jsonString string) (*CloneSnapshotMsg, error) {
+	var msg *CloneSnapshotMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCCloneSnapshotMsg(t *testing.T) {
+	msg := CreateCloneSnapshotMsg("test_colony", "test_name", "/test_label")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateCloneSnapshotMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateCloneSnapshotMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCCloneSnapshotMsgEquals(t *testing.T) {
+	msg := CreateCloneSnapshotMsg("test_colony", "test_name", "/test_label")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
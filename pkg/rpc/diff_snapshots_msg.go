@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const DiffSnapshotsPayloadType = "diffsnapshotsmsg"
+
+type DiffSnapshotsMsg struct {
+	ColonyName string `json:"colonyname"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	MsgType    string `json:"msgtype"`
+}
+
+func CreateDiffSnapshotsMsg(colonyName string, from string, to string) *DiffSnapshotsMsg {
+	msg := &DiffSnapshotsMsg{}
+	msg.MsgType = DiffSnapshotsPayloadType
+	msg.ColonyName = colonyName
+	msg.From = from
+	msg.To = to
+
+	return msg
+}
+
+func (msg *DiffSnapshotsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *DiffSnapshotsMsg) Equals(msg2 *DiffSnapshotsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.From == msg2.From &&
+		msg.To == msg2.To {
+		return true
+	}
+
+	return false
+}
+
+func CreateDiffSnapshotsMsgFromJSON(jsonString string) (*DiffSnapshotsMsg, error) {
+	var msg *DiffSnapshotsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
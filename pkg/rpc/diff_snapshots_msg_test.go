@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCDiffSnapshotsMsg(t *testing.T) {
+	msg := CreateDiffSnapshotsMsg("test_colony", "test_from", "test_to")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateDiffSnapshotsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateDiffSnapshotsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCDiffSnapshotsMsgEquals(t *testing.T) {
+	msg := CreateDiffSnapshotsMsg("test_colony", "test_from", "test_to")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
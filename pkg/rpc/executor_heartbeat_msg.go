@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const ExecutorHeartbeatPayloadType = "executorheartbeatmsg"
+
+type ExecutorHeartbeatMsg struct {
+	ColonyName   string `json:"colonyname"`
+	ExecutorName string `json:"executorname"`
+	MsgType      string `json:"msgtype"`
+}
+
+func CreateExecutorHeartbeatMsg(colonyName string, executorName string) *ExecutorHeartbeatMsg {
+	msg := &ExecutorHeartbeatMsg{}
+	msg.ColonyName = colonyName
+	msg.ExecutorName = executorName
+	msg.MsgType = ExecutorHeartbeatPayloadType
+
+	return msg
+}
+
+func (msg *ExecutorHeartbeatMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ExecutorHeartbeatMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ExecutorHeartbeatMsg) Equals(msg2 *ExecutorHeartbeatMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ExecutorName == msg2.ExecutorName && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateExecutorHeartbeatMsgFromJSON(jsonString string) (*ExecutorHeartbeatMsg, error) {
+	var msg *ExecutorHeartbeatMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
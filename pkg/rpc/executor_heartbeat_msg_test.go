@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCExecutorHeartbeatMsg(t *testing.T) {
+	msg := CreateExecutorHeartbeatMsg(core.GenerateRandomID(), core.GenerateRandomID())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateExecutorHeartbeatMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateExecutorHeartbeatMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCExecutorHeartbeatMsgIndent(t *testing.T) {
+	msg := CreateExecutorHeartbeatMsg(core.GenerateRandomID(), core.GenerateRandomID())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateExecutorHeartbeatMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateExecutorHeartbeatMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCExecutorHeartbeatMsgEquals(t *testing.T) {
+	msg := CreateExecutorHeartbeatMsg(core.GenerateRandomID(), core.GenerateRandomID())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GCFilesPayloadType = "gcfilesmsg"
+
+type GCFilesMsg struct {
+	MsgType     string `json:"msgtype"`
+	ColonyName  string `json:"colonyname"`
+	GracePeriod int64  `json:"graceperiod"`
+	DryRun      bool   `json:"dryrun"`
+}
+
+func CreateGCFilesMsg(colonyName string, gracePeriod int64, dryRun bool) *GCFilesMsg {
+	msg := &GCFilesMsg{}
+	msg.ColonyName = colonyName
+	msg.GracePeriod = gracePeriod
+	msg.DryRun = dryRun
+	msg.MsgType = GCFilesPayloadType
+
+	return msg
+}
+
+func (msg *GCFilesMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GCFilesMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GCFilesMsg) Equals(msg2 *GCFilesMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.GracePeriod == msg2.GracePeriod &&
+		msg.DryRun == msg2.DryRun {
+		return true
+	}
+
+	return false
+}
+
+func CreateGCFilesMsgFromJSON(jsonString string) (*GCFilesMsg, error) {
+	var msg *GCFilesMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGCFilesMsg(t *testing.T) {
+	msg := CreateGCFilesMsg("test_colony", 3600, true)
+	assert.Equal(t, GCFilesPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, int64(3600), msg.GracePeriod)
+	assert.True(t, msg.DryRun)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGCFilesMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGCFilesMsgToJSONIndent(t *testing.T) {
+	msg := CreateGCFilesMsg("test_colony", 3600, true)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGCFilesMsgEquals(t *testing.T) {
+	msg := CreateGCFilesMsg("test_colony", 3600, true)
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateGCFilesMsg("test_colony", 3600, false)))
+	assert.False(t, msg.Equals(CreateGCFilesMsg("test_colony", 60, true)))
+}
+
+func TestRPCGCFilesMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGCFilesMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -9,6 +9,7 @@ const GetBlueprintPayloadType = "getblueprintmsg"
 type GetBlueprintMsg struct {
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
 	MsgType   string `json:"msgtype"`
 }
 
@@ -46,7 +47,8 @@ func (msg *GetBlueprintMsg) Equals(msg2 *GetBlueprintMsg) bool {
 
 	return msg.MsgType == msg2.MsgType &&
 		msg.Namespace == msg2.Namespace &&
-		msg.Name == msg2.Name
+		msg.Name == msg2.Name &&
+		msg.Version == msg2.Version
 }
 
 func CreateGetBlueprintMsgFromJSON(jsonString string) (*GetBlueprintMsg, error) {
@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetFileLineagePayloadType = "getfilelineagemsg"
+
+// GetFileLineageMsg gets the lineage of a process, a process graph or a file revision. Exactly one of
+// ProcessID, ProcessGraphID and FileID must be set. If Direction is set, only inputs or outputs are returned.
+type GetFileLineageMsg struct {
+	MsgType        string `json:"msgtype"`
+	ColonyName     string `json:"colonyname"`
+	ProcessID      string `json:"processid"`
+	ProcessGraphID string `json:"processgraphid"`
+	FileID         string `json:"fileid"`
+	Direction      string `json:"direction"`
+}
+
+func CreateGetFileLineageMsg(colonyName string, processID string, processGraphID string, fileID string, direction string) *GetFileLineageMsg {
+	msg := &GetFileLineageMsg{}
+	msg.ColonyName = colonyName
+	msg.ProcessID = processID
+	msg.ProcessGraphID = processGraphID
+	msg.FileID = fileID
+	msg.Direction = direction
+	msg.MsgType = GetFileLineagePayloadType
+
+	return msg
+}
+
+func (msg *GetFileLineageMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFileLineageMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFileLineageMsg) Equals(msg2 *GetFileLineageMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.ProcessID == msg2.ProcessID &&
+		msg.ProcessGraphID == msg2.ProcessGraphID &&
+		msg.FileID == msg2.FileID &&
+		msg.Direction == msg2.Direction {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetFileLineageMsgFromJSON(jsonString string) (*GetFileLineageMsg, error) {
+	var msg *GetFileLineageMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
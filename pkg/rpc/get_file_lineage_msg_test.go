@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetFileLineageMsg(t *testing.T) {
+	msg := CreateGetFileLineageMsg("test_colony", "test_processid", "", "", "input")
+	assert.Equal(t, GetFileLineagePayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_processid", msg.ProcessID)
+	assert.Equal(t, "input", msg.Direction)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFileLineageMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetFileLineageMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetFileLineageMsg("test_colony", "", "", "test_fileid", "")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetFileLineageMsgEquals(t *testing.T) {
+	msg1 := CreateGetFileLineageMsg("test_colony", "", "test_processgraphid", "", "")
+	msg2 := CreateGetFileLineageMsg("test_colony", "", "test_processgraphid", "", "output")
+	assert.True(t, msg1.Equals(msg1))
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCGetFileLineageMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetFileLineageMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetFileRetentionPoliciesPayloadType = "getfileretentionpoliciesmsg"
+
+type GetFileRetentionPoliciesMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetFileRetentionPoliciesMsg(colonyName string) *GetFileRetentionPoliciesMsg {
+	msg := &GetFileRetentionPoliciesMsg{}
+	msg.ColonyName = colonyName
+	msg.MsgType = GetFileRetentionPoliciesPayloadType
+
+	return msg
+}
+
+func (msg *GetFileRetentionPoliciesMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFileRetentionPoliciesMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetFileRetentionPoliciesMsg) Equals(msg2 *GetFileRetentionPoliciesMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetFileRetentionPoliciesMsgFromJSON(jsonString string) (*GetFileRetentionPoliciesMsg, error) {
+	var msg *GetFileRetentionPoliciesMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetFileRetentionPoliciesMsg(t *testing.T) {
+	msg := CreateGetFileRetentionPoliciesMsg("test_colony")
+	assert.Equal(t, GetFileRetentionPoliciesPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetFileRetentionPoliciesMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetFileRetentionPoliciesMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetFileRetentionPoliciesMsg("test_colony")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetFileRetentionPoliciesMsgEquals(t *testing.T) {
+	msg1 := CreateGetFileRetentionPoliciesMsg("test_colony")
+	msg2 := CreateGetFileRetentionPoliciesMsg("other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCGetFileRetentionPoliciesMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetFileRetentionPoliciesMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
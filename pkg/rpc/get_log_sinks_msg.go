@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetLogSinksPayloadType = "getlogsinksmsg"
+
+type GetLogSinksMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetLogSinksMsg(colonyName string) *GetLogSinksMsg {
+	msg := &GetLogSinksMsg{}
+	msg.ColonyName = colonyName
+	msg.MsgType = GetLogSinksPayloadType
+
+	return msg
+}
+
+func (msg *GetLogSinksMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetLogSinksMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetLogSinksMsg) Equals(msg2 *GetLogSinksMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetLogSinksMsgFromJSON(jsonString string) (*GetLogSinksMsg, error) {
+	var msg *GetLogSinksMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetLogSinksMsg(t *testing.T) {
+	msg := CreateGetLogSinksMsg("test_colony")
+	assert.Equal(t, GetLogSinksPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetLogSinksMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetLogSinksMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetLogSinksMsg("test_colony")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetLogSinksMsgEquals(t *testing.T) {
+	msg1 := CreateGetLogSinksMsg("test_colony")
+	msg2 := CreateGetLogSinksMsg("other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCGetLogSinksMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetLogSinksMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
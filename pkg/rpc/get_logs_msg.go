@@ -2,18 +2,24 @@ package rpc
 
 import (
 	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
 )
 
 const GetLogsPayloadType = "getlogsmsg"
 
 type GetLogsMsg struct {
-	ColonyName   string `json:"colonyname"`
-	ProcessID    string `json:"processid"`
-	ExecutorName string `json:"executorname"`
-	Count        int    `json:"count"`
-	Since        int64  `json:"since"`
-	Latest       bool   `json:"latest"` // If true, return latest logs (descending order)
-	MsgType      string `json:"msgtype"`
+	ColonyName   string            `json:"colonyname"`
+	ProcessID    string            `json:"processid"`
+	ExecutorName string            `json:"executorname"`
+	Count        int               `json:"count"`
+	Since        int64             `json:"since"`
+	Until        int64             `json:"until,omitempty"`
+	Latest       bool              `json:"latest"` // If true, return latest logs (descending order)
+	Level        string            `json:"level,omitempty"`
+	Stream       string            `json:"stream,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	MsgType      string            `json:"msgtype"`
 }
 
 func CreateGetLogsMsg(colonyName string, processID string, count int, since int64) *GetLogsMsg {
@@ -47,13 +53,22 @@ func (msg *GetLogsMsg) Equals(msg2 *GetLogsMsg) bool {
 		msg.Count == msg2.Count &&
 		msg.Since == msg2.Since &&
 		msg.ExecutorName == msg2.ExecutorName &&
-		msg.Latest == msg2.Latest {
+		msg.Latest == msg2.Latest &&
+		msg.Until == msg2.Until &&
+		msg.Level == msg2.Level &&
+		msg.Stream == msg2.Stream &&
+		core.IsStringMapsEqual(msg.Fields, msg2.Fields) {
 		return true
 	}
 
 	return false
 }
 
+// HasFilter returns true if the message uses any of the structured log filters
+func (msg *GetLogsMsg) HasFilter() bool {
+	return msg.Until > 0 || msg.Level != "" || msg.Stream != "" || len(msg.Fields) > 0
+}
+
 func (msg *GetLogsMsg) ToJSONIndent() (string, error) {
 	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
 	if err != nil {
@@ -40,3 +40,24 @@ func TestRPCGetLogsMsgEquals(t *testing.T) {
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
 }
+
+func TestRPCGetLogsMsgFilter(t *testing.T) {
+	msg := CreateGetLogsMsg("test_colony_name", core.GenerateRandomID(), 100, 0)
+	assert.False(t, msg.HasFilter())
+
+	msg.Level = core.LogLevelWarn
+	msg.Stream = core.LogStreamStderr
+	msg.Fields = map[string]string{"step": "1"}
+	msg.Until = 100
+	assert.True(t, msg.HasFilter())
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetLogsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+
+	msg2.Fields["step"] = "2"
+	assert.False(t, msg.Equals(msg2))
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetStorageBackendsPayloadType = "getstoragebackendsmsg"
+
+type GetStorageBackendsMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+}
+
+func CreateGetStorageBackendsMsg(colonyName string) *GetStorageBackendsMsg {
+	msg := &GetStorageBackendsMsg{}
+	msg.ColonyName = colonyName
+	msg.MsgType = GetStorageBackendsPayloadType
+
+	return msg
+}
+
+func (msg *GetStorageBackendsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetStorageBackendsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetStorageBackendsMsg) Equals(msg2 *GetStorageBackendsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetStorageBackendsMsgFromJSON(jsonString string) (*GetStorageBackendsMsg, error) {
+	var msg *GetStorageBackendsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
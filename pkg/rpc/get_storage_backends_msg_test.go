@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetStorageBackendsMsg(t *testing.T) {
+	msg := CreateGetStorageBackendsMsg("test_colony")
+	assert.Equal(t, GetStorageBackendsPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetStorageBackendsMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetStorageBackendsMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetStorageBackendsMsg("test_colony")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetStorageBackendsMsgEquals(t *testing.T) {
+	msg1 := CreateGetStorageBackendsMsg("test_colony")
+	msg2 := CreateGetStorageBackendsMsg("other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCGetStorageBackendsMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetStorageBackendsMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
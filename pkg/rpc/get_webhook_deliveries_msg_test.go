@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetWebhookDeliveriesMsg(t *testing.T) {
+	msg := CreateGetWebhookDeliveriesMsg("test_colony", "test_name", 10)
+	assert.Equal(t, GetWebhookDeliveriesPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_name", msg.Name)
+	assert.Equal(t, 10, msg.Count)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetWebhookDeliveriesMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetWebhookDeliveriesMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetWebhookDeliveriesMsg("test_colony", "test_name", 10)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetWebhookDeliveriesMsgEquals(t *testing.T) {
+	msg := CreateGetWebhookDeliveriesMsg("test_colony", "test_name", 10)
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateGetWebhookDeliveriesMsg("test_colony", "test_name", 20)))
+	assert.False(t, msg.Equals(CreateGetWebhookDeliveriesMsg("test_colony", "other_name", 10)))
+}
+
+func TestRPCGetWebhookDeliveriesMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetWebhookDeliveriesMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const GetWebhookPayloadType = "getwebhookmsg"
+
+type GetWebhookMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+}
+
+func CreateGetWebhookMsg(colonyName string, name string) *GetWebhookMsg {
+	msg := &GetWebhookMsg{}
+	msg.MsgType = GetWebhookPayloadType
+	msg.ColonyName = colonyName
+	msg.Name = name
+
+	return msg
+}
+
+func (msg *GetWebhookMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhookMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *GetWebhookMsg) Equals(msg2 *GetWebhookMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ColonyName == msg2.ColonyName && msg.Name == msg2.Name {
+		return true
+	}
+
+	return false
+}
+
+func CreateGetWebhookMsgFromJSON(jsonString string) (*GetWebhookMsg, error) {
+	var msg *GetWebhookMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetWebhookMsg(t *testing.T) {
+	msg := CreateGetWebhookMsg("test_colony", "test_name")
+	assert.Equal(t, GetWebhookPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_name", msg.Name)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetWebhookMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetWebhookMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetWebhookMsg("test_colony", "test_name")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetWebhookMsgEqualsNil(t *testing.T) {
+	msg := CreateGetWebhookMsg("test_colony", "test_name")
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCGetWebhookMsgEqualsDifferentColony(t *testing.T) {
+	msg1 := CreateGetWebhookMsg("test_colony1", "test_name")
+	msg2 := CreateGetWebhookMsg("test_colony2", "test_name")
+	assert.False(t, msg1.Equals(msg2))
+}
+
+func TestRPCGetWebhookMsgEqualsDifferentName(t *testing.T) {
+	msg1 := CreateGetWebhookMsg("test_colony", "test_name1")
+	msg2 := CreateGetWebhookMsg("test_colony", "test_name2")
+	assert.False(t, msg1.Equals(msg2))
+}
+
+func TestRPCGetWebhookMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetWebhookMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
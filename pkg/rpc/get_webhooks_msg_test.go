@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCGetWebhooksMsg(t *testing.T) {
+	msg := CreateGetWebhooksMsg("test_colony")
+	assert.Equal(t, GetWebhooksPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateGetWebhooksMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCGetWebhooksMsgToJSONIndent(t *testing.T) {
+	msg := CreateGetWebhooksMsg("test_colony")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCGetWebhooksMsgEqualsNil(t *testing.T) {
+	msg := CreateGetWebhooksMsg("test_colony")
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCGetWebhooksMsgEqualsDifferentColony(t *testing.T) {
+	msg1 := CreateGetWebhooksMsg("test_colony1")
+	msg2 := CreateGetWebhooksMsg("test_colony2")
+	assert.False(t, msg1.Equals(msg2))
+}
+
+func TestRPCGetWebhooksMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateGetWebhooksMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const MigrateBlueprintDefinitionPayloadType = "migrateblueprintdefinitionmsg"
+
+type MigrateBlueprintDefinitionMsg struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	DryRun    bool   `json:"dryrun"`
+	MsgType   string `json:"msgtype"`
+}
+
+func CreateMigrateBlueprintDefinitionMsg(namespace, name string, dryRun bool) *MigrateBlueprintDefinitionMsg {
+	msg := &MigrateBlueprintDefinitionMsg{}
+	msg.Namespace = namespace
+	msg.Name = name
+	msg.DryRun = dryRun
+	msg.MsgType = MigrateBlueprintDefinitionPayloadType
+
+	return msg
+}
+
+func (msg *MigrateBlueprintDefinitionMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *MigrateBlueprintDefinitionMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *MigrateBlueprintDefinitionMsg) Equals(msg2 *MigrateBlueprintDefinitionMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	return msg.MsgType == msg2.MsgType &&
+		msg.Namespace == msg2.Namespace &&
+		msg.Name == msg2.Name &&
+		msg.DryRun == msg2.DryRun
+}
+
+func CreateMigrateBlueprintDefinitionMsgFromJSON(jsonString string) (*MigrateBlueprintDefinitionMsg, error) {
+	var msg *MigrateBlueprintDefinitionMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
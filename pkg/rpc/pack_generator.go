@@ -9,6 +9,7 @@ const PackGeneratorPayloadType = "packgeneratormsg"
 type PackGeneratorMsg struct {
 	GeneratorID string `json:"generatorid"`
 	Arg         string `json:"arg"`
+	Key         string `json:"key"`
 	MsgType     string `json:"msgtype"`
 }
 
@@ -44,7 +45,7 @@ func (msg *PackGeneratorMsg) Equals(msg2 *PackGeneratorMsg) bool {
 		return false
 	}
 
-	if msg.MsgType == msg2.MsgType && msg.GeneratorID == msg2.GeneratorID && msg.Arg == msg2.Arg {
+	if msg.MsgType == msg2.MsgType && msg.GeneratorID == msg2.GeneratorID && msg.Arg == msg2.Arg && msg.Key == msg2.Key {
 		return true
 	}
 
@@ -39,4 +39,8 @@ func TestRPCPackGeneratorMsgEquals(t *testing.T) {
 	msg := CreatePackGeneratorMsg(core.GenerateRandomID(), "arg")
 	assert.True(t, msg.Equals(msg))
 	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreatePackGeneratorMsg(msg.GeneratorID, "arg")
+	msg2.Key = "tenant1"
+	assert.False(t, msg.Equals(msg2))
 }
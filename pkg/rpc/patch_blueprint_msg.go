@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+const PatchBlueprintPayloadType = "patchblueprintmsg"
+
+type PatchBlueprintMsg struct {
+	Namespace            string                 `json:"namespace"`
+	Name                 string                 `json:"name"`
+	PatchType            string                 `json:"patchtype"` // merge or strategic, defaults to merge
+	Patch                map[string]interface{} `json:"patch"`
+	CheckResourceVersion bool                   `json:"checkresourceversion,omitempty"` // If true, the patch fails unless the stored resource version equals ResourceVersion
+	ResourceVersion      int64                  `json:"resourceversion,omitempty"`
+	MsgType              string                 `json:"msgtype"`
+}
+
+func CreatePatchBlueprintMsg(namespace, name string, patchType string, patch map[string]interface{}) *PatchBlueprintMsg {
+	msg := &PatchBlueprintMsg{}
+	msg.Namespace = namespace
+	msg.Name = name
+	msg.PatchType = patchType
+	msg.Patch = patch
+	msg.MsgType = PatchBlueprintPayloadType
+
+	return msg
+}
+
+func (msg *PatchBlueprintMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *PatchBlueprintMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *PatchBlueprintMsg) Equals(msg2 *PatchBlueprintMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	return msg.MsgType == msg2.MsgType &&
+		msg.Namespace == msg2.Namespace &&
+		msg.Name == msg2.Name &&
+		msg.PatchType == msg2.PatchType &&
+		msg.CheckResourceVersion == msg2.CheckResourceVersion &&
+		msg.ResourceVersion == msg2.ResourceVersion &&
+		reflect.DeepEqual(msg.Patch, msg2.Patch)
+}
+
+func CreatePatchBlueprintMsgFromJSON(jsonString string) (*PatchBlueprintMsg, error) {
+	var msg *PatchBlueprintMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
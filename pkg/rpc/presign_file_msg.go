@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const PresignFilePayloadType = "presignfilemsg"
+
+type PresignFileMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Method     string `json:"method"`
+	FileID     string `json:"fileid"`
+	Backend    string `json:"backend"`
+}
+
+func CreatePresignFileMsg(colonyName string, method string, fileID string, backend string) *PresignFileMsg {
+	msg := &PresignFileMsg{}
+	msg.ColonyName = colonyName
+	msg.Method = method
+	msg.FileID = fileID
+	msg.Backend = backend
+	msg.MsgType = PresignFilePayloadType
+
+	return msg
+}
+
+func (msg *PresignFileMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *PresignFileMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *PresignFileMsg) Equals(msg2 *PresignFileMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Method == msg2.Method &&
+		msg.FileID == msg2.FileID &&
+		msg.Backend == msg2.Backend {
+		return true
+	}
+
+	return false
+}
+
+func CreatePresignFileMsgFromJSON(jsonString string) (*PresignFileMsg, error) {
+	var msg *PresignFileMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
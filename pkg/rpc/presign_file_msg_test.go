@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCPresignFileMsg(t *testing.T) {
+	msg := CreatePresignFileMsg("test_colony", "GET", "test_fileid", "test_backend")
+	assert.Equal(t, PresignFilePayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "GET", msg.Method)
+	assert.Equal(t, "test_fileid", msg.FileID)
+	assert.Equal(t, "test_backend", msg.Backend)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreatePresignFileMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCPresignFileMsgToJSONIndent(t *testing.T) {
+	msg := CreatePresignFileMsg("test_colony", "GET", "test_fileid", "test_backend")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCPresignFileMsgEquals(t *testing.T) {
+	msg1 := CreatePresignFileMsg("test_colony", "GET", "test_fileid", "test_backend")
+	msg2 := CreatePresignFileMsg("test_colony", "GET", "test_fileid", "other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCPresignFileMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreatePresignFileMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -7,9 +7,11 @@ import (
 const RemoveBlueprintPayloadType = "removeblueprintmsg"
 
 type RemoveBlueprintMsg struct {
-	Namespace string `json:"namespace"`
-	Name      string `json:"name"`
-	MsgType   string `json:"msgtype"`
+	Namespace            string `json:"namespace"`
+	Name                 string `json:"name"`
+	CheckResourceVersion bool   `json:"checkresourceversion,omitempty"` // If true, the blueprint is only removed if the stored resource version equals ResourceVersion
+	ResourceVersion      int64  `json:"resourceversion,omitempty"`
+	MsgType              string `json:"msgtype"`
 }
 
 func CreateRemoveBlueprintMsg(namespace, name string) *RemoveBlueprintMsg {
@@ -46,7 +48,9 @@ func (msg *RemoveBlueprintMsg) Equals(msg2 *RemoveBlueprintMsg) bool {
 
 	return msg.MsgType == msg2.MsgType &&
 		msg.Namespace == msg2.Namespace &&
-		msg.Name == msg2.Name
+		msg.Name == msg2.Name &&
+		msg.CheckResourceVersion == msg2.CheckResourceVersion &&
+		msg.ResourceVersion == msg2.ResourceVersion
 }
 
 func CreateRemoveBlueprintMsgFromJSON(jsonString string) (*RemoveBlueprintMsg, error) {
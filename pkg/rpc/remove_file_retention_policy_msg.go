@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveFileRetentionPolicyPayloadType = "removefileretentionpolicymsg"
+
+type RemoveFileRetentionPolicyMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Label      string `json:"label"`
+}
+
+func CreateRemoveFileRetentionPolicyMsg(colonyName string, label string) *RemoveFileRetentionPolicyMsg {
+	msg := &RemoveFileRetentionPolicyMsg{}
+	msg.ColonyName = colonyName
+	msg.Label = label
+	msg.MsgType = RemoveFileRetentionPolicyPayloadType
+
+	return msg
+}
+
+func (msg *RemoveFileRetentionPolicyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFileRetentionPolicyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveFileRetentionPolicyMsg) Equals(msg2 *RemoveFileRetentionPolicyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Label == msg2.Label {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveFileRetentionPolicyMsgFromJSON(jsonString string) (*RemoveFileRetentionPolicyMsg, error) {
+	var msg *RemoveFileRetentionPolicyMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
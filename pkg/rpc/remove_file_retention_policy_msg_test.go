@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveFileRetentionPolicyMsg(t *testing.T) {
+	msg := CreateRemoveFileRetentionPolicyMsg("test_colony", "/test")
+	assert.Equal(t, RemoveFileRetentionPolicyPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "/test", msg.Label)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveFileRetentionPolicyMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveFileRetentionPolicyMsgToJSONIndent(t *testing.T) {
+	msg := CreateRemoveFileRetentionPolicyMsg("test_colony", "/test")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCRemoveFileRetentionPolicyMsgEquals(t *testing.T) {
+	msg1 := CreateRemoveFileRetentionPolicyMsg("test_colony", "/test")
+	msg2 := CreateRemoveFileRetentionPolicyMsg("test_colony", "other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCRemoveFileRetentionPolicyMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateRemoveFileRetentionPolicyMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveLogSinkPayloadType = "removelogsinkmsg"
+
+type RemoveLogSinkMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+}
+
+func CreateRemoveLogSinkMsg(colonyName string, name string) *RemoveLogSinkMsg {
+	msg := &RemoveLogSinkMsg{}
+	msg.ColonyName = colonyName
+	msg.Name = name
+	msg.MsgType = RemoveLogSinkPayloadType
+
+	return msg
+}
+
+func (msg *RemoveLogSinkMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveLogSinkMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveLogSinkMsg) Equals(msg2 *RemoveLogSinkMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Name == msg2.Name {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveLogSinkMsgFromJSON(jsonString string) (*RemoveLogSinkMsg, error) {
+	var msg *RemoveLogSinkMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveLogSinkMsg(t *testing.T) {
+	msg := CreateRemoveLogSinkMsg("test_colony", "test_sink")
+	assert.Equal(t, RemoveLogSinkPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_sink", msg.Name)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveLogSinkMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveLogSinkMsgToJSONIndent(t *testing.T) {
+	msg := CreateRemoveLogSinkMsg("test_colony", "test_sink")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCRemoveLogSinkMsgEquals(t *testing.T) {
+	msg1 := CreateRemoveLogSinkMsg("test_colony", "test_sink")
+	msg2 := CreateRemoveLogSinkMsg("test_colony", "other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCRemoveLogSinkMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateRemoveLogSinkMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
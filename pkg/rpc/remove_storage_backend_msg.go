@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveStorageBackendPayloadType = "removestoragebackendmsg"
+
+type RemoveStorageBackendMsg struct {
+	MsgType    string `json:"msgtype"`
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+}
+
+func CreateRemoveStorageBackendMsg(colonyName string, name string) *RemoveStorageBackendMsg {
+	msg := &RemoveStorageBackendMsg{}
+	msg.ColonyName = colonyName
+	msg.Name = name
+	msg.MsgType = RemoveStorageBackendPayloadType
+
+	return msg
+}
+
+func (msg *RemoveStorageBackendMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveStorageBackendMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveStorageBackendMsg) Equals(msg2 *RemoveStorageBackendMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Name == msg2.Name {
+		return true
+	}
+
+	return false
+}
+
+func CreateRemoveStorageBackendMsgFromJSON(jsonString string) (*RemoveStorageBackendMsg, error) {
+	var msg *RemoveStorageBackendMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
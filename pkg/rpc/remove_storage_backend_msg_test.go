@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveStorageBackendMsg(t *testing.T) {
+	msg := CreateRemoveStorageBackendMsg("test_colony", "test_backend")
+	assert.Equal(t, RemoveStorageBackendPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_backend", msg.Name)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveStorageBackendMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveStorageBackendMsgToJSONIndent(t *testing.T) {
+	msg := CreateRemoveStorageBackendMsg("test_colony", "test_backend")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCRemoveStorageBackendMsgEquals(t *testing.T) {
+	msg1 := CreateRemoveStorageBackendMsg("test_colony", "test_backend")
+	msg2 := CreateRemoveStorageBackendMsg("test_colony", "other")
+	assert.False(t, msg1.Equals(msg2))
+	assert.False(t, msg1.Equals(nil))
+}
+
+func TestRPCRemoveStorageBackendMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateRemoveStorageBackendMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
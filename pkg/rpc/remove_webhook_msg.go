@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RemoveWebhookPayloadType = "removewebhookmsg"
+
+type RemoveWebhookMsg struct {
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+	MsgType    string `json:"msgtype"`
+}
+
+func CreateRemoveWebhookMsg(colonyName string, name string) *RemoveWebhookMsg {
+	msg := &RemoveWebhookMsg{}
+	msg.ColonyName = colonyName
+	msg.Name = name
+	msg.MsgType = RemoveWebhookPayloadType
+
+	return msg
+}
+
+func (msg *RemoveWebhookMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RemoveWebhookMsg) Equals(msg2 *RemoveWebhookMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.Name == msg2.Name && msg.ColonyName == msg2.ColonyName {
+		return true
+	}
+
+	return false
+}
+
+func (msg *RemoveWebhookMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func CreateRemoveWebhookMsgFromJSON(jsonString string) (*RemoveWebhookMsg, error) {
+	var msg *RemoveWebhookMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
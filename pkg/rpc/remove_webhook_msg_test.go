@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRemoveWebhookMsg(t *testing.T) {
+	msg := CreateRemoveWebhookMsg("test_colony", "test_name")
+	assert.Equal(t, RemoveWebhookPayloadType, msg.MsgType)
+	assert.Equal(t, "test_colony", msg.ColonyName)
+	assert.Equal(t, "test_name", msg.Name)
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveWebhookMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveWebhookMsgToJSONIndent(t *testing.T) {
+	msg := CreateRemoveWebhookMsg("test_colony", "test_name")
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCRemoveWebhookMsgEqualsNil(t *testing.T) {
+	msg := CreateRemoveWebhookMsg("test_colony", "test_name")
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCRemoveWebhookMsgEqualsDifferentColony(t *testing.T) {
+	msg1 := CreateRemoveWebhookMsg("test_colony1", "test_name")
+	msg2 := CreateRemoveWebhookMsg("test_colony2", "test_name")
+	assert.False(t, msg1.Equals(msg2))
+}
+
+func TestRPCRemoveWebhookMsgEqualsDifferentName(t *testing.T) {
+	msg1 := CreateRemoveWebhookMsg("test_colony", "test_name1")
+	msg2 := CreateRemoveWebhookMsg("test_colony", "test_name2")
+	assert.False(t, msg1.Equals(msg2))
+}
+
+func TestRPCRemoveWebhookMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateRemoveWebhookMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
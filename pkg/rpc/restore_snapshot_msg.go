@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RestoreSnapshotPayloadType = "restoresnapshotmsg"
+
+type RestoreSnapshotMsg struct {
+	ColonyName string `json:"colonyname"`
+	Name       string `json:"name"`
+	Prune      bool   `json:"prune"`
+	MsgType    string `json:"msgtype"`
+}
+
+func CreateRestoreSnapshotMsg(colonyName string, name string, prune bool) *RestoreSnapshotMsg {
+	msg := &RestoreSnapshotMsg{}
+	msg.MsgType = RestoreSnapshotPayloadType
+	msg.ColonyName = colonyName
+	msg.Name = name
+	msg.Prune = prune
+
+	return msg
+}
+
+func (msg *RestoreSnapshotMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RestoreSnapshotMsg) Equals(msg2 *RestoreSnapshotMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.Name == msg2.Name &&
+		msg.Prune == msg2.Prune {
+		return true
+	}
+
+	return false
+}
+
+func CreateRestoreSnapshotMsgFromJSON(jsonString string) (*RestoreSnapshotMsg, error) {
+	var msg *RestoreSnapshotMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCRestoreSnapshotMsg(t *testing.T) {
+	msg := CreateRestoreSnapshotMsg("test_colony", "test_name", true)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRestoreSnapshotMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateRestoreSnapshotMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCRestoreSnapshotMsgEquals(t *testing.T) {
+	msg := CreateRestoreSnapshotMsg("test_colony", "test_name", true)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
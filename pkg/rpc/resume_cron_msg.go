@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const ResumeCronPayloadType = "resumecronmsg"
+
+type ResumeCronMsg struct {
+	CronID  string `json:"cronid"`
+	MsgType string `json:"msgtype"`
+}
+
+func CreateResumeCronMsg(cronID string) *ResumeCronMsg {
+	msg := &ResumeCronMsg{}
+	msg.CronID = cronID
+	msg.MsgType = ResumeCronPayloadType
+
+	return msg
+}
+
+func (msg *ResumeCronMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ResumeCronMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *ResumeCronMsg) Equals(msg2 *ResumeCronMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.CronID == msg2.CronID {
+		return true
+	}
+
+	return false
+}
+
+func CreateResumeCronMsgFromJSON(jsonString string) (*ResumeCronMsg, error) {
+	var msg *ResumeCronMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeCronMsg(t *testing.T) {
+	msg := CreateResumeCronMsg(core.GenerateRandomID())
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateResumeCronMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateResumeCronMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCResumeCronMsgIndent(t *testing.T) {
+	msg := CreateResumeCronMsg(core.GenerateRandomID())
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateResumeCronMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateResumeCronMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCResumeCronMsgEquals(t *testing.T) {
+	msg := CreateResumeCronMsg(core.GenerateRandomID())
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const RollbackBlueprintPayloadType = "rollbackblueprintmsg"
+
+type RollbackBlueprintMsg struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Generation int64  `json:"generation"`
+	MsgType    string `json:"msgtype"`
+}
+
+func CreateRollbackBlueprintMsg(namespace, name string, generation int64) *RollbackBlueprintMsg {
+	msg := &RollbackBlueprintMsg{}
+	msg.Namespace = namespace
+	msg.Name = name
+	msg.Generation = generation
+	msg.MsgType = RollbackBlueprintPayloadType
+
+	return msg
+}
+
+func (msg *RollbackBlueprintMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RollbackBlueprintMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *RollbackBlueprintMsg) Equals(msg2 *RollbackBlueprintMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	return msg.MsgType == msg2.MsgType &&
+		msg.Namespace == msg2.Namespace &&
+		msg.Name == msg2.Name &&
+		msg.Generation == msg2.Generation
+}
+
+func CreateRollbackBlueprintMsgFromJSON(jsonString string) (*RollbackBlueprintMsg, error) {
+	var msg *RollbackBlueprintMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const SetFileRetentionPolicyPayloadType = "setfileretentionpolicymsg"
+
+type SetFileRetentionPolicyMsg struct {
+	FileRetentionPolicy *core.FileRetentionPolicy `json:"fileretentionpolicy"`
+	MsgType             string                    `json:"msgtype"`
+}
+
+func CreateSetFileRetentionPolicyMsg(policy *core.FileRetentionPolicy) *SetFileRetentionPolicyMsg {
+	msg := &SetFileRetentionPolicyMsg{}
+	msg.FileRetentionPolicy = policy
+	msg.MsgType = SetFileRetentionPolicyPayloadType
+
+	return msg
+}
+
+func (msg *SetFileRetentionPolicyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SetFileRetentionPolicyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SetFileRetentionPolicyMsg) Equals(msg2 *SetFileRetentionPolicyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.FileRetentionPolicy.Equals(msg2.FileRetentionPolicy) {
+		return true
+	}
+
+	return false
+}
+
+func CreateSetFileRetentionPolicyMsgFromJSON(jsonString string) (*SetFileRetentionPolicyMsg, error) {
+	var msg *SetFileRetentionPolicyMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCSetFileRetentionPolicyMsg(t *testing.T) {
+	policy := core.CreateFileRetentionPolicy("test_colony", "/test", 5, 30)
+	msg := CreateSetFileRetentionPolicyMsg(policy)
+	assert.Equal(t, SetFileRetentionPolicyPayloadType, msg.MsgType)
+	assert.True(t, policy.Equals(msg.FileRetentionPolicy))
+
+	jsonStr, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSetFileRetentionPolicyMsgFromJSON(jsonStr)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSetFileRetentionPolicyMsgToJSONIndent(t *testing.T) {
+	policy := core.CreateFileRetentionPolicy("test_colony", "/test", 5, 30)
+	msg := CreateSetFileRetentionPolicyMsg(policy)
+
+	jsonStr, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+	assert.Contains(t, jsonStr, "\n")
+}
+
+func TestRPCSetFileRetentionPolicyMsgEqualsNil(t *testing.T) {
+	policy := core.CreateFileRetentionPolicy("test_colony", "/test", 5, 30)
+	msg := CreateSetFileRetentionPolicyMsg(policy)
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCSetFileRetentionPolicyMsgFromJSONInvalid(t *testing.T) {
+	_, err := CreateSetFileRetentionPolicyMsgFromJSON("invalid json")
+	assert.NotNil(t, err)
+}
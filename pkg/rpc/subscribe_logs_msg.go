@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const SubscribeLogsPayloadType = "subscribelogsmsg"
+
+// SubscribeLogsMsg subscribes to logs added to a process, an executor or all processes in a
+// process graph. Exactly one of ProcessID, ExecutorName and ProcessGraphID must be set.
+type SubscribeLogsMsg struct {
+	ColonyName     string            `json:"colonyname"`
+	ProcessID      string            `json:"processid"`
+	ExecutorName   string            `json:"executorname"`
+	ProcessGraphID string            `json:"processgraphid"`
+	Since          int64             `json:"since"`
+	Level          string            `json:"level,omitempty"`
+	Stream         string            `json:"stream,omitempty"`
+	Fields         map[string]string `json:"fields,omitempty"`
+	Timeout        int               `json:"timeout"`
+	MsgType        string            `json:"msgtype"`
+}
+
+func CreateSubscribeLogsMsg(colonyName string, processID string, executorName string, processGraphID string, since int64, timeout int) *SubscribeLogsMsg {
+	msg := &SubscribeLogsMsg{}
+	msg.ColonyName = colonyName
+	msg.ProcessID = processID
+	msg.ExecutorName = executorName
+	msg.ProcessGraphID = processGraphID
+	msg.Since = since
+	msg.Timeout = timeout
+	msg.MsgType = SubscribeLogsPayloadType
+
+	return msg
+}
+
+func (msg *SubscribeLogsMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeLogsMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SubscribeLogsMsg) Equals(msg2 *SubscribeLogsMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.ProcessID == msg2.ProcessID &&
+		msg.ExecutorName == msg2.ExecutorName &&
+		msg.ProcessGraphID == msg2.ProcessGraphID &&
+		msg.Since == msg2.Since &&
+		msg.Level == msg2.Level &&
+		msg.Stream == msg2.Stream &&
+		core.IsStringMapsEqual(msg.Fields, msg2.Fields) &&
+		msg.Timeout == msg2.Timeout {
+		return true
+	}
+
+	return false
+}
+
+func CreateSubscribeLogsMsgFromJSON(jsonString string) (*SubscribeLogsMsg, error) {
+	var msg *SubscribeLogsMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCSubscribeLogsMsg(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", core.GenerateRandomID(), "", "", 1, 2)
+	msg.Level = core.LogLevelWarn
+	msg.Fields = map[string]string{"step": "1"}
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeLogsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeLogsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubscribeLogsMsgIndent(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", "", "test_executor_name", "", 1, 2)
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateSubscribeLogsMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateSubscribeLogsMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCSubscribeLogsMsgEquals(t *testing.T) {
+	msg := CreateSubscribeLogsMsg("test_colony_name", "", "", core.GenerateRandomID(), 1, 2)
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+
+	msg2 := CreateSubscribeLogsMsg("test_colony_name", "", "", msg.ProcessGraphID, 1, 2)
+	msg2.Stream = core.LogStreamStderr
+	assert.False(t, msg.Equals(msg2))
+}
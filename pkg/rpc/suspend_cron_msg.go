@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const SuspendCronPayloadType = "suspendcronmsg"
+
+type SuspendCronMsg struct {
+	CronID  string `json:"cronid"`
+	MsgType string `json:"msgtype"`
+}
+
+func CreateSuspendCronMsg(cronID string) *SuspendCronMsg {
+	msg := &SuspendCronMsg{}
+	msg.CronID = cronID
+	msg.MsgType = SuspendCronPayloadType
+
+	return msg
+}
+
+func (msg *SuspendCronMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SuspendCronMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *SuspendCronMsg) Equals(msg2 *SuspendCronMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.CronID == msg2.CronID {
+		return true
+	}
+
+	return false
+}
+
+func CreateSuspendCronMsgFromJSON(jsonString string) (*SuspendCronMsg, error) {
+	var msg *SuspendCronMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
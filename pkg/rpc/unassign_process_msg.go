@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const UnassignProcessPayloadType = "unassignprocessmsg"
+
+type UnassignProcessMsg struct {
+	ProcessID string `json:"processid"`
+	MsgType   string `json:"msgtype"`
+}
+
+func CreateUnassignProcessMsg(processID string) *UnassignProcessMsg {
+	msg := &UnassignProcessMsg{}
+	msg.ProcessID = processID
+	msg.MsgType = UnassignProcessPayloadType
+
+	return msg
+}
+
+func (msg *UnassignProcessMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *UnassignProcessMsg) Equals(msg2 *UnassignProcessMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType && msg.ProcessID == msg2.ProcessID {
+		return true
+	}
+
+	return false
+}
+
+func (msg *UnassignProcessMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func CreateUnassignProcessMsgFromJSON(jsonString string) (*UnassignProcessMsg, error) {
+	var msg *UnassignProcessMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -9,9 +9,11 @@ import (
 const UpdateBlueprintPayloadType = "updateblueprintmsg"
 
 type UpdateBlueprintMsg struct {
-	Blueprint       *core.Blueprint `json:"blueprint"`
-	ForceGeneration bool            `json:"forcegeneration"` // If true, increment generation even if spec hasn't changed
-	MsgType         string          `json:"msgtype"`
+	Blueprint            *core.Blueprint `json:"blueprint"`
+	ForceGeneration      bool            `json:"forcegeneration"`                // If true, increment generation even if spec hasn't changed
+	CheckResourceVersion bool            `json:"checkresourceversion,omitempty"` // If true, the update fails unless the stored resource version equals ResourceVersion
+	ResourceVersion      int64           `json:"resourceversion,omitempty"`
+	MsgType              string          `json:"msgtype"`
 }
 
 func CreateUpdateBlueprintMsg(blueprint *core.Blueprint) *UpdateBlueprintMsg {
@@ -55,7 +57,7 @@ func (msg *UpdateBlueprintMsg) Equals(msg2 *UpdateBlueprintMsg) bool {
 		return false
 	}
 
-	if msg.MsgType != msg2.MsgType {
+	if msg.MsgType != msg2.MsgType || msg.CheckResourceVersion != msg2.CheckResourceVersion || msg.ResourceVersion != msg2.ResourceVersion {
 		return false
 	}
 
@@ -103,3 +103,80 @@ func TestRPCUpdateBlueprintMsgNilBlueprint(t *testing.T) {
 	assert.False(t, msg1.Equals(msg3), "Message with nil blueprint should not equal message with blueprint")
 	assert.False(t, msg3.Equals(msg1), "Message with blueprint should not equal message with nil blueprint")
 }
+
+func TestRPCUpdateBlueprintMsgWithResourceVersion(t *testing.T) {
+	msg := CreateUpdateBlueprintMsg(createTestBlueprint())
+	msg.CheckResourceVersion = true
+	msg.ResourceVersion = 5
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateUpdateBlueprintMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg2.CheckResourceVersion)
+	assert.Equal(t, int64(5), msg2.ResourceVersion)
+	assert.True(t, msg.Equals(msg2))
+
+	msg2.ResourceVersion = 6
+	assert.False(t, msg.Equals(msg2))
+
+	msg2.ResourceVersion = 5
+	msg2.CheckResourceVersion = false
+	assert.False(t, msg.Equals(msg2))
+}
+
+func TestRPCUpdateBlueprintStatusMsg(t *testing.T) {
+	msg := CreateUpdateBlueprintStatusMsg("test-colony", "test-deployment", map[string]interface{}{"ready": true})
+	msg.ResourceVersion = 3
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = CreateUpdateBlueprintStatusMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err := CreateUpdateBlueprintStatusMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+	assert.False(t, msg.Equals(nil))
+
+	msg2.ResourceVersion = 0
+	assert.False(t, msg.Equals(msg2))
+}
+
+func TestRPCRemoveBlueprintMsg(t *testing.T) {
+	msg := CreateRemoveBlueprintMsg("test-colony", "test-deployment")
+	msg.ResourceVersion = 2
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateRemoveBlueprintMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+
+	msg2.ResourceVersion = 1
+	assert.False(t, msg.Equals(msg2))
+}
+
+func TestRPCPatchBlueprintMsg(t *testing.T) {
+	patch := map[string]interface{}{"spec": map[string]interface{}{"replicas": 3.0}}
+	msg := CreatePatchBlueprintMsg("test-colony", "test-deployment", core.MergePatchType, patch)
+	msg.ResourceVersion = 4
+
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = CreatePatchBlueprintMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err := CreatePatchBlueprintMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+	assert.False(t, msg.Equals(nil))
+	assert.Equal(t, PatchBlueprintPayloadType, msg2.MsgType)
+
+	msg2.Patch = map[string]interface{}{"spec": map[string]interface{}{"replicas": 4.0}}
+	assert.False(t, msg.Equals(msg2))
+}
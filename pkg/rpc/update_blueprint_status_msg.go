@@ -7,10 +7,12 @@ import (
 const UpdateBlueprintStatusPayloadType = "updateblueprintstatusmsg"
 
 type UpdateBlueprintStatusMsg struct {
-	ColonyName    string                 `json:"colonyname"`
-	BlueprintName string                 `json:"blueprintname"`
-	Status        map[string]interface{} `json:"status"`
-	MsgType       string                 `json:"msgtype"`
+	ColonyName           string                 `json:"colonyname"`
+	BlueprintName        string                 `json:"blueprintname"`
+	Status               map[string]interface{} `json:"status"`
+	CheckResourceVersion bool                   `json:"checkresourceversion,omitempty"` // If true, the update fails unless the stored resource version equals ResourceVersion
+	ResourceVersion      int64                  `json:"resourceversion,omitempty"`
+	MsgType              string                 `json:"msgtype"`
 }
 
 func CreateUpdateBlueprintStatusMsg(colonyName, blueprintName string, status map[string]interface{}) *UpdateBlueprintStatusMsg {
@@ -58,6 +60,10 @@ func (msg *UpdateBlueprintStatusMsg) Equals(msg2 *UpdateBlueprintStatusMsg) bool
 		return false
 	}
 
+	if msg.CheckResourceVersion != msg2.CheckResourceVersion || msg.ResourceVersion != msg2.ResourceVersion {
+		return false
+	}
+
 	return true
 }
 
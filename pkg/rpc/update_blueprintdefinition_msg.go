@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/colonyos/colonies/pkg/core"
+)
+
+const UpdateBlueprintDefinitionPayloadType = "updateblueprintdefinitionmsg"
+
+type UpdateBlueprintDefinitionMsg struct {
+	BlueprintDefinition *core.BlueprintDefinition `json:"blueprintdefinition"`
+	MsgType             string                    `json:"msgtype"`
+}
+
+func CreateUpdateBlueprintDefinitionMsg(sd *core.BlueprintDefinition) *UpdateBlueprintDefinitionMsg {
+	msg := &UpdateBlueprintDefinitionMsg{}
+	msg.BlueprintDefinition = sd
+	msg.MsgType = UpdateBlueprintDefinitionPayloadType
+
+	return msg
+}
+
+func (msg *UpdateBlueprintDefinitionMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *UpdateBlueprintDefinitionMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *UpdateBlueprintDefinitionMsg) Equals(msg2 *UpdateBlueprintDefinitionMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType != msg2.MsgType {
+		return false
+	}
+
+	if msg.BlueprintDefinition == nil && msg2.BlueprintDefinition == nil {
+		return true
+	}
+
+	if msg.BlueprintDefinition == nil || msg2.BlueprintDefinition == nil {
+		return false
+	}
+
+	return msg.BlueprintDefinition.ID == msg2.BlueprintDefinition.ID
+}
+
+func CreateUpdateBlueprintDefinitionMsgFromJSON(jsonString string) (*UpdateBlueprintDefinitionMsg, error) {
+	var msg *UpdateBlueprintDefinitionMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCUpdateBlueprintDefinitionMsg(t *testing.T) {
+	sd := core.CreateBlueprintDefinition("deployments.compute.io", "compute.io", "", "Deployment", "deployments", "Namespaced", "controller", "reconcile")
+	sd.Spec.Versions = []core.BlueprintDefinitionVersion{
+		{Name: "v1", Served: true},
+		{Name: "v2", Served: true, Storage: true},
+	}
+
+	msg := CreateUpdateBlueprintDefinitionMsg(sd)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = CreateUpdateBlueprintDefinitionMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err := CreateUpdateBlueprintDefinitionMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+	assert.Len(t, msg2.BlueprintDefinition.Spec.Versions, 2)
+	assert.Equal(t, "v2", msg2.BlueprintDefinition.StorageVersion())
+
+	jsonString, err = msg.ToJSONIndent()
+	assert.Nil(t, err)
+	msg2, err = CreateUpdateBlueprintDefinitionMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+	assert.False(t, msg.Equals(nil))
+}
+
+func TestRPCMigrateBlueprintDefinitionMsg(t *testing.T) {
+	msg := CreateMigrateBlueprintDefinitionMsg("test-colony", "deployments.compute.io", true)
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	_, err = CreateMigrateBlueprintDefinitionMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err := CreateMigrateBlueprintDefinitionMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+	assert.True(t, msg2.DryRun)
+
+	jsonString, err = msg.ToJSONIndent()
+	assert.Nil(t, err)
+	msg2, err = CreateMigrateBlueprintDefinitionMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+	assert.True(t, msg.Equals(msg2))
+
+	assert.False(t, msg.Equals(nil))
+	assert.False(t, msg.Equals(CreateMigrateBlueprintDefinitionMsg("test-colony", "deployments.compute.io", false)))
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"encoding/json"
+)
+
+const UpdateFileEncryptionKeyPayloadType = "updatefileenckeymsg"
+
+type UpdateFileEncryptionKeyMsg struct {
+	MsgType       string `json:"msgtype"`
+	ColonyName    string `json:"colonyname"`
+	FileID        string `json:"fileid"`
+	EncryptionKey string `json:"encryptionkey"`
+}
+
+func CreateUpdateFileEncryptionKeyMsg(colonyName string, fileID string, encryptionKey string) *UpdateFileEncryptionKeyMsg {
+	msg := &UpdateFileEncryptionKeyMsg{}
+	msg.ColonyName = colonyName
+	msg.FileID = fileID
+	msg.EncryptionKey = encryptionKey
+	msg.MsgType = UpdateFileEncryptionKeyPayloadType
+
+	return msg
+}
+
+func (msg *UpdateFileEncryptionKeyMsg) ToJSON() (string, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func (msg *UpdateFileEncryptionKeyMsg) Equals(msg2 *UpdateFileEncryptionKeyMsg) bool {
+	if msg2 == nil {
+		return false
+	}
+
+	if msg.MsgType == msg2.MsgType &&
+		msg.ColonyName == msg2.ColonyName &&
+		msg.FileID == msg2.FileID &&
+		msg.EncryptionKey == msg2.EncryptionKey {
+		return true
+	}
+
+	return false
+}
+
+func (msg *UpdateFileEncryptionKeyMsg) ToJSONIndent() (string, error) {
+	jsonBytes, err := json.MarshalIndent(msg, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonBytes), nil
+}
+
+func CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString string) (*UpdateFileEncryptionKeyMsg, error) {
+	var msg *UpdateFileEncryptionKeyMsg
+
+	err := json.Unmarshal([]byte(jsonString), &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	return msg, nil
+}
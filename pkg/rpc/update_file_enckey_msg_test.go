@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateFileEncryptionKeyMsg(t *testing.T) {
+	msg := CreateUpdateFileEncryptionKeyMsg("test_colony", "test_fileid", "test_encryptionkey")
+	jsonString, err := msg.ToJSON()
+	assert.Nil(t, err)
+
+	msg2, err := CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCUpdateFileEncryptionKeyMsgIndent(t *testing.T) {
+	msg := CreateUpdateFileEncryptionKeyMsg("test_colony", "test_fileid", "test_encryptionkey")
+	jsonString, err := msg.ToJSONIndent()
+	assert.Nil(t, err)
+
+	msg2, err := CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString + "error")
+	assert.NotNil(t, err)
+
+	msg2, err = CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString)
+	assert.Nil(t, err)
+
+	assert.True(t, msg.Equals(msg2))
+}
+
+func TestRPCUpdateFileEncryptionKeyMsgEquals(t *testing.T) {
+	msg := CreateUpdateFileEncryptionKeyMsg("test_colony", "test_fileid", "test_encryptionkey")
+	assert.True(t, msg.Equals(msg))
+	assert.False(t, msg.Equals(nil))
+}
@@ -0,0 +1,215 @@
+package egress
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Outbound connections made on behalf of colonies, e.g. webhooks and log sinks, are checked against an
+// egress policy so that colony members cannot make the server call internal addresses.
+const (
+	AllowEnv = "COLONIES_EGRESS_ALLOW" // Comma separated IPs or CIDRs that are always allowed, overrides the deny list
+	DenyEnv  = "COLONIES_EGRESS_DENY"  // Comma separated IPs or CIDRs that are denied in addition to the default list
+)
+
+// Loopback, private, link-local (including cloud metadata endpoints), and other non-public ranges
+var defaultDeny = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+}
+
+type Policy struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// CreatePolicy creates a policy denying the default ranges and the given deny entries. Allow entries take
+// precedence over deny entries.
+func CreatePolicy(allow []string, deny []string) (*Policy, error) {
+	allowNets, err := parseNets(allow)
+	if err != nil {
+		return nil, err
+	}
+
+	denyNets, err := parseNets(append(append([]string{}, defaultDeny...), deny...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Policy{allow: allowNets, deny: denyNets}, nil
+}
+
+// LoadPolicy creates a policy from COLONIES_EGRESS_ALLOW and COLONIES_EGRESS_DENY, falling back to the default
+// policy if any entry is invalid
+func LoadPolicy() *Policy {
+	allow := splitEnv(AllowEnv)
+	deny := splitEnv(DenyEnv)
+
+	policy, err := CreatePolicy(allow, deny)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Invalid egress policy, using the default policy")
+		policy, _ = CreatePolicy(nil, nil)
+	}
+
+	return policy
+}
+
+func splitEnv(name string) []string {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func parseNets(entries []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, errors.New("invalid IP address <" + entry + ">")
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckIP returns an error if the policy does not allow connections to the IP
+func (policy *Policy) CheckIP(ip net.IP) error {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if contains(policy.allow, ip) {
+		return nil
+	}
+
+	if contains(policy.deny, ip) {
+		return errors.New("connections to <" + ip.String() + "> are not allowed")
+	}
+
+	return nil
+}
+
+// CheckHost resolves the host and checks all its addresses. Hosts that cannot be resolved are not rejected,
+// they are checked again when connecting.
+func (policy *Policy) CheckHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return policy.CheckIP(ip)
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil
+	}
+
+	for _, ip := range ips {
+		if err := policy.CheckIP(ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckURL checks that the URL is an http or https URL with a host allowed by the policy
+func (policy *Policy) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.New("invalid url")
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url scheme must be http or https")
+	}
+
+	if u.Hostname() == "" {
+		return errors.New("url has no host")
+	}
+
+	return policy.CheckHost(u.Hostname())
+}
+
+// control checks the address a dialer is about to connect to, after DNS resolution, which also protects
+// against hosts resolving to a different address than when they were checked
+func (policy *Policy) control(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.New("connections to <" + host + "> are not allowed")
+	}
+
+	return policy.CheckIP(ip)
+}
+
+// Dialer returns a dialer that refuses to connect to addresses not allowed by the policy
+func (policy *Policy) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: policy.control}
+}
+
+// HTTPClient returns an HTTP client that refuses to connect to addresses not allowed by the policy. Proxies
+// are not used since the policy would then only apply to the proxy.
+func (policy *Policy) HTTPClient(timeout time.Duration) *http.Client {
+	transport := &http.Transport{
+		Proxy:               nil,
+		DialContext:         policy.Dialer(timeout).DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,82 @@
+package egress
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIP(t *testing.T) {
+	policy, err := CreatePolicy(nil, nil)
+	assert.Nil(t, err)
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "::1", "fd00::1", "::ffff:127.0.0.1", "0.0.0.0"} {
+		assert.NotNil(t, policy.CheckIP(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{"8.8.8.8", "1.1.1.1", "2001:4860:4860::8888"} {
+		assert.Nil(t, policy.CheckIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCheckIPAllowDeny(t *testing.T) {
+	policy, err := CreatePolicy([]string{"10.0.0.5", "192.168.10.0/24"}, []string{"8.8.8.0/24"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, policy.CheckIP(net.ParseIP("10.0.0.5")))
+	assert.NotNil(t, policy.CheckIP(net.ParseIP("10.0.0.6")))
+	assert.Nil(t, policy.CheckIP(net.ParseIP("192.168.10.20")))
+	assert.NotNil(t, policy.CheckIP(net.ParseIP("8.8.8.8")))
+
+	_, err = CreatePolicy([]string{"invalid"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Setenv(AllowEnv, "127.0.0.1/32, ")
+	t.Setenv(DenyEnv, "1.1.1.1")
+
+	policy := LoadPolicy()
+	assert.Nil(t, policy.CheckIP(net.ParseIP("127.0.0.1")))
+	assert.NotNil(t, policy.CheckIP(net.ParseIP("1.1.1.1")))
+
+	t.Setenv(AllowEnv, "invalid")
+	policy = LoadPolicy()
+	assert.NotNil(t, policy.CheckIP(net.ParseIP("127.0.0.1")))
+}
+
+func TestCheckURL(t *testing.T) {
+	policy, err := CreatePolicy(nil, nil)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, policy.CheckURL("http://127.0.0.1:8080/hook"))
+	assert.NotNil(t, policy.CheckURL("http://localhost/hook"))
+	assert.NotNil(t, policy.CheckURL("http://169.254.169.254/latest/meta-data"))
+	assert.NotNil(t, policy.CheckURL("http://[::1]/hook"))
+	assert.NotNil(t, policy.CheckURL("ftp://8.8.8.8/hook"))
+	assert.NotNil(t, policy.CheckURL("http:///hook"))
+	assert.Nil(t, policy.CheckURL("https://8.8.8.8/hook"))
+}
+
+func TestHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy, err := CreatePolicy(nil, nil)
+	assert.Nil(t, err)
+	_, err = policy.HTTPClient(time.Second).Get(server.URL)
+	assert.NotNil(t, err)
+
+	policy, err = CreatePolicy([]string{"127.0.0.1"}, nil)
+	assert.Nil(t, err)
+	resp, err := policy.HTTPClient(time.Second).Get(server.URL)
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
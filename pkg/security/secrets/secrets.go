@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Secrets stored in the database, e.g. webhook signing secrets, are sealed with AES-256-GCM using a key
+// derived from COLONIES_SECRETS_KEY. Sealed secrets are prefixed so that secrets stored before encryption
+// was introduced can still be read.
+const (
+	KeyEnv       = "COLONIES_SECRETS_KEY"
+	sealedPrefix = "enc:v1:"
+)
+
+func loadKey() ([]byte, error) {
+	passphrase := os.Getenv(KeyEnv)
+	if passphrase == "" {
+		return nil, errors.New(KeyEnv + " is not set, secrets cannot be stored")
+	}
+
+	key := sha256.Sum256([]byte(passphrase))
+	return key[:], nil
+}
+
+func createGCM() (cipher.AEAD, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// IsSealed returns true if the value was sealed by Seal
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// Seal encrypts a secret, empty secrets are returned as is
+func Seal(secret string) (string, error) {
+	if secret == "" || IsSealed(secret) {
+		return secret, nil
+	}
+
+	gcm, err := createGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a secret sealed by Seal, values that are not sealed are returned as is
+func Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", errors.New("Failed to decode sealed secret")
+	}
+
+	gcm, err := createGCM()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("Sealed secret is too short")
+	}
+
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("Failed to decrypt sealed secret, wrong " + KeyEnv + "?")
+	}
+
+	return string(secret), nil
+}
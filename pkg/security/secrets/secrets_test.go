@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealOpen(t *testing.T) {
+	t.Setenv(KeyEnv, "test_key")
+
+	sealed, err := Seal("test_secret")
+	assert.Nil(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, sealed, "test_secret")
+
+	sealed2, err := Seal("test_secret")
+	assert.Nil(t, err)
+	assert.NotEqual(t, sealed, sealed2)
+
+	// Already sealed secrets are not sealed twice
+	resealed, err := Seal(sealed)
+	assert.Nil(t, err)
+	assert.Equal(t, sealed, resealed)
+
+	secret, err := Open(sealed)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_secret", secret)
+
+	t.Setenv(KeyEnv, "wrong_key")
+	_, err = Open(sealed)
+	assert.NotNil(t, err)
+}
+
+func TestSealWithoutKey(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+
+	_, err := Seal("test_secret")
+	assert.NotNil(t, err)
+
+	sealed, err := Seal("")
+	assert.Nil(t, err)
+	assert.Equal(t, "", sealed)
+}
+
+func TestOpenPlaintext(t *testing.T) {
+	secret, err := Open("legacy_secret")
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy_secret", secret)
+
+	_, err = Open("enc:v1:not-base64!")
+	assert.NotNil(t, err)
+}
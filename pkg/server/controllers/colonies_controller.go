@@ -15,7 +15,11 @@ import (
 	"github.com/colonyos/colonies/pkg/constants"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/scheduler"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
+	"github.com/colonyos/colonies/pkg/webhook"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -102,6 +106,7 @@ type ColoniesController struct {
 	snapshotDB       database.SnapshotDatabase
 	blueprintDB      database.BlueprintDatabase
 	securityDB       database.SecurityDatabase
+	webhookDB        database.WebhookDatabase
 	cmdQueue         chan *command
 	blockingCmdQueue chan *command
 	scheduler        *scheduler.Scheduler
@@ -125,8 +130,16 @@ type ColoniesController struct {
 	pauseChannelsMux sync.RWMutex
 	// Channel router for bidirectional communication
 	channelRouter *channel.Router
+	// Delivers log entries to realtime log subscribers across the cluster
+	logBroker *logstream.Broker
 	// Stale executor cleanup configuration
 	staleExecutorDuration time.Duration
+	// Delivers process and processgraph lifecycle events to registered webhooks
+	webhookDispatcher *webhook.Dispatcher
+	// Forwards log entries to external log sinks registered in each colony
+	logSinkPipeline *logsink.Pipeline
+	// Validates submitted function args against registered arg schemas
+	functionArgsVerifier *serverutils.FunctionArgsVerifier
 }
 
 func CreateColoniesController(db database.Database,
@@ -157,6 +170,7 @@ func CreateColoniesController(db database.Database,
 	controller.snapshotDB = db
 	controller.blueprintDB = db
 	controller.securityDB = db
+	controller.webhookDB = db
 	controller.thisNode = thisNode
 	controller.clusterConfig = clusterConfig
 	controller.etcdServer = cluster.CreateEtcdServer(controller.thisNode, controller.clusterConfig, etcdDataPath)
@@ -171,8 +185,12 @@ func CreateColoniesController(db database.Database,
 	controller.staleExecutorDuration = staleExecutorDuration
 	controller.pauseChannels = make(map[string][]chan bool)
 	controller.channelRouter = channel.NewRouter()
+	controller.webhookDispatcher = webhook.CreateDispatcher(controller.webhookDB, constants.WEBHOOK_WORKERS)
+	controller.logSinkPipeline = logsink.CreatePipeline(db, os.Getenv("COLONIES_LOG_SINK_DIR"))
+	controller.functionArgsVerifier = serverutils.CreateFunctionArgsVerifier(db, db)
 
 	controller.relayServer = cluster.CreateRelayServer(controller.thisNode, controller.clusterConfig)
+	controller.logBroker = logstream.CreateBroker(controller.relayServer)
 
 	factory := backendGin.NewFactory()
 	controller.eventHandler = factory.CreateEventHandler(controller.relayServer)
@@ -310,6 +328,21 @@ func (controller *ColoniesController) GetChannelRouter() *channel.Router {
 	return controller.channelRouter
 }
 
+// GetLogBroker returns the broker delivering log entries to realtime subscribers
+func (controller *ColoniesController) GetLogBroker() *logstream.Broker {
+	return controller.logBroker
+}
+
+// GetLogSinkPipeline returns the pipeline forwarding log entries to external log sinks
+func (controller *ColoniesController) GetLogSinkPipeline() *logsink.Pipeline {
+	return controller.logSinkPipeline
+}
+
+// GetFunctionArgsVerifier returns the verifier validating submitted function args against registered arg schemas
+func (controller *ColoniesController) GetFunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return controller.functionArgsVerifier
+}
+
 func (controller *ColoniesController) AddProcess(process *core.Process) (*core.Process, error) {
 	cmd := &command{threaded: true, processReplyChan: make(chan *core.Process, 1),
 		errorChan: make(chan error, 1),
@@ -799,6 +832,7 @@ func (controller *ColoniesController) CancelProcess(processID string) error {
 			controller.channelRouter.CleanupProcess(processID)
 
 			controller.eventHandler.Signal(process)
+			controller.webhookDispatcher.Dispatch(core.CreateProcessWebhookEvent(process))
 			cmd.errorChan <- nil
 		}}
 
@@ -809,50 +843,53 @@ func (controller *ColoniesController) CancelProcess(processID string) error {
 func (controller *ColoniesController) CancelProcessGraph(processGraphID string) error {
 	cmd := &command{threaded: true, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
-			processGraph, err := controller.processGraphDB.GetProcessGraphByID(processGraphID)
-			if err != nil {
-				cmd.errorChan <- err
-				return
-			}
-			if processGraph == nil {
-				cmd.errorChan <- errors.New("ProcessGraph with Id <" + processGraphID + "> not found")
-				return
-			}
+			cmd.errorChan <- controller.cancelProcessGraph(processGraphID)
+		}}
 
-			processGraph.SetStorage(controller.GetProcessGraphStorage())
-			err = processGraph.UpdateProcessIDs()
-			if err != nil {
-				cmd.errorChan <- err
-				return
-			}
+	controller.cmdQueue <- cmd
+	return <-cmd.errorChan
+}
 
-			for _, pid := range processGraph.ProcessIDs {
-				p, err := controller.processDB.GetProcessByID(pid)
-				if err != nil {
-					cmd.errorChan <- err
-					return
-				}
-				if p.State == core.WAITING || p.State == core.RUNNING {
-					err = controller.processDB.MarkCancelled(pid)
-					if err != nil {
-						cmd.errorChan <- err
-						return
-					}
-					controller.channelRouter.CleanupProcess(pid)
-				}
-			}
+// cancelProcessGraph cancels all waiting and running processes of a processgraph, it must be called from
+// a command handler
+func (controller *ColoniesController) cancelProcessGraph(processGraphID string) error {
+	processGraph, err := controller.processGraphDB.GetProcessGraphByID(processGraphID)
+	if err != nil {
+		return err
+	}
+	if processGraph == nil {
+		return errors.New("ProcessGraph with Id <" + processGraphID + "> not found")
+	}
+
+	processGraph.SetStorage(controller.GetProcessGraphStorage())
+	err = processGraph.UpdateProcessIDs()
+	if err != nil {
+		return err
+	}
 
-			err = controller.processGraphDB.SetProcessGraphState(processGraphID, core.CANCELLED)
+	for _, pid := range processGraph.ProcessIDs {
+		p, err := controller.processDB.GetProcessByID(pid)
+		if err != nil {
+			return err
+		}
+		if p.State == core.WAITING || p.State == core.RUNNING {
+			err = controller.processDB.MarkCancelled(pid)
 			if err != nil {
-				cmd.errorChan <- err
-				return
+				return err
 			}
+			controller.channelRouter.CleanupProcess(pid)
+		}
+	}
 
-			cmd.errorChan <- nil
-		}}
+	err = controller.processGraphDB.SetProcessGraphState(processGraphID, core.CANCELLED)
+	if err != nil {
+		return err
+	}
 
-	controller.cmdQueue <- cmd
-	return <-cmd.errorChan
+	processGraph.State = core.CANCELLED
+	controller.webhookDispatcher.Dispatch(core.CreateProcessGraphWebhookEvent(processGraph))
+
+	return nil
 }
 
 func (controller *ColoniesController) CloseSuccessful(processID string, executorID string, output []interface{}) error {
@@ -882,6 +919,7 @@ func (controller *ColoniesController) CloseSuccessful(processID string, executor
 					return
 				}
 				processGraph.SetStorage(controller.GetProcessGraphStorage())
+				prevGraphState := processGraph.State
 				err = processGraph.Resolve()
 				if err != nil {
 					err2 := controller.HandleDefunctProcessgraph(processGraph.ID, process.ID, err)
@@ -895,6 +933,7 @@ func (controller *ColoniesController) CloseSuccessful(processID string, executor
 					cmd.errorChan <- err
 					return
 				}
+				controller.dispatchProcessGraphWebhookEvent(prevGraphState, processGraph)
 
 				// This is process is now closed. This means that children processes can now execute,
 				// assuming all their parents are closed successfully
@@ -979,6 +1018,7 @@ func (controller *ColoniesController) CloseSuccessful(processID string, executor
 			controller.channelRouter.CleanupProcess(processID)
 
 			controller.eventHandler.Signal(process)
+			controller.webhookDispatcher.Dispatch(core.CreateProcessWebhookEvent(process))
 			cmd.errorChan <- nil
 		}}
 
@@ -986,6 +1026,18 @@ func (controller *ColoniesController) CloseSuccessful(processID string, executor
 	return <-cmd.errorChan
 }
 
+// dispatchProcessGraphWebhookEvent notifies webhooks when a processgraph has just reached a final state,
+// graphs that were already final before they were resolved are ignored to avoid duplicate events
+func (controller *ColoniesController) dispatchProcessGraphWebhookEvent(prevState int, processGraph *core.ProcessGraph) {
+	if prevState == core.SUCCESS || prevState == core.FAILED || prevState == core.CANCELLED {
+		return
+	}
+
+	if processGraph.State == core.SUCCESS || processGraph.State == core.FAILED {
+		controller.webhookDispatcher.Dispatch(core.CreateProcessGraphWebhookEvent(processGraph))
+	}
+}
+
 func (controller *ColoniesController) NotifyChildren(process *core.Process) error {
 	// First check if parent processes are completed
 	counter := 0
@@ -1036,6 +1088,7 @@ func (controller *ColoniesController) CloseFailed(processID string, errs []strin
 					return
 				}
 				processGraph.SetStorage(controller.GetProcessGraphStorage())
+				prevGraphState := processGraph.State
 				err = processGraph.Resolve()
 				if err != nil {
 					err2 := controller.HandleDefunctProcessgraph(processGraph.ID, process.ID, err)
@@ -1049,7 +1102,7 @@ func (controller *ColoniesController) CloseFailed(processID string, errs []strin
 					cmd.errorChan <- err
 					return
 				}
-
+				controller.dispatchProcessGraphWebhookEvent(prevGraphState, processGraph)
 			}
 
 			process.State = core.FAILED
@@ -1058,6 +1111,7 @@ func (controller *ColoniesController) CloseFailed(processID string, errs []strin
 			controller.channelRouter.CleanupProcess(processID)
 
 			controller.eventHandler.Signal(process)
+			controller.webhookDispatcher.Dispatch(core.CreateProcessWebhookEvent(process))
 			cmd.errorChan <- nil
 		}}
 
@@ -1365,6 +1419,25 @@ func (controller *ColoniesController) UnassignExecutor(processID string) error {
 	return <-cmd.errorChan
 }
 
+// ReleaseProcess moves a running process back to the queue without counting a retry, it is used when an
+// executor releases a process on purpose, e.g. when shutting down
+func (controller *ColoniesController) ReleaseProcess(processID string) error {
+	cmd := &command{threaded: true, errorChan: make(chan error, 1),
+		handler: func(cmd *command) {
+			process, err := controller.processDB.GetProcessByID(processID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+
+			cmd.errorChan <- controller.processDB.Release(process)
+			controller.eventHandler.Signal(process)
+		}}
+
+	controller.cmdQueue <- cmd
+	return <-cmd.errorChan
+}
+
 func (controller *ColoniesController) ResetProcess(processID string) error {
 	cmd := &command{threaded: true, errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
@@ -1462,6 +1535,9 @@ func (controller *ColoniesController) Stop() {
 	controller.stopMutex.Unlock()
 	controller.cmdQueue <- &command{stop: true}
 	controller.eventHandler.Stop()
+	controller.webhookDispatcher.Stop()
+	controller.logSinkPipeline.Stop()
+	controller.logBroker.Stop()
 	controller.relayServer.Shutdown()
 	controller.etcdServer.Stop()
 	controller.etcdServer.WaitToStop()
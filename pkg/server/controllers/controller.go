@@ -7,6 +7,9 @@ import (
 	"github.com/colonyos/colonies/pkg/channel"
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 )
 
 type Controller interface {
@@ -38,9 +41,10 @@ type Controller interface {
 	Assign(executorID string, colonyName string, cpu int64, memory int64) (*AssignResult, error)
 	DistributedAssign(executor *core.Executor, colonyName string, cpu int64, memory int64, storage int64) (*AssignResult, error)
 	UnassignExecutor(processID string) error
+	ReleaseProcess(processID string) error
 	ResetProcess(processID string) error
 	AddGenerator(generator *core.Generator) (*core.Generator, error)
-	PackGenerator(generatorID string, colonyName, arg string) error
+	PackGenerator(generatorID string, colonyName string, key string, arg string) error
 	GeneratorTriggerLoop()
 	TriggerGenerators()
 	SubmitWorkflow(generator *core.Generator, counter int, recoveredID string) // TODO: change name, there is also a submitWorkflowSpec()
@@ -48,6 +52,9 @@ type Controller interface {
 	RemoveGenerator(generatorID string) error
 	RunCron(cronID string) (*core.Cron, error)
 	RemoveCron(cronID string) error
+	SuspendCron(cronID string) (*core.Cron, error)
+	ResumeCron(cronID string) (*core.Cron, error)
+	GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 	CalcNextRun(cron *core.Cron) time.Time
 	StartCron(cron *core.Cron)
 	TriggerCrons()
@@ -64,4 +71,7 @@ type Controller interface {
 	RetentionWorker()
 	CmdQueueWorker()
 	GetChannelRouter() *channel.Router
+	GetLogBroker() *logstream.Broker
+	GetLogSinkPipeline() *logsink.Pipeline
+	GetFunctionArgsVerifier() *serverutils.FunctionArgsVerifier
 }
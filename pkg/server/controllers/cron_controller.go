@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"fmt"
+	"math/rand"
 	"time"
+	_ "time/tzdata" // Time zones of crons are available also on hosts without a time zone database
 
 	"github.com/colonyos/colonies/pkg/core"
 	cronlib "github.com/colonyos/colonies/pkg/cron"
@@ -126,22 +128,16 @@ func (controller *ColoniesController) RunCron(cronID string) (*core.Cron, error)
 				return
 			}
 
-			// Respect WaitForPrevProcessGraph flag to prevent duplicate processes
-			if cron.WaitForPrevProcessGraph && cron.PrevProcessGraphID != "" {
-				processgraph, err := controller.processGraphDB.GetProcessGraphByID(cron.PrevProcessGraphID)
-				if err != nil {
-					log.WithFields(log.Fields{"Error": err, "ProcessGraphID": cron.PrevProcessGraphID}).Warn("Failed to get previous process graph")
-					// Continue anyway - don't block on lookup errors
-				} else if processgraph != nil && processgraph.State != core.SUCCESS && processgraph.State != core.FAILED {
-					log.WithFields(log.Fields{
-						"CronID":             cron.ID,
-						"CronName":           cron.Name,
-						"PrevProcessGraphID": cron.PrevProcessGraphID,
-						"PrevState":          processgraph.State,
-					}).Info("Skipping RunCron - previous process graph still running")
-					cmd.cronReplyChan <- cron
-					return
-				}
+			// Respect the concurrency policy to prevent duplicate processes
+			if !controller.admitCronRun(cron) {
+				log.WithFields(log.Fields{
+					"CronID":             cron.ID,
+					"CronName":           cron.Name,
+					"ConcurrencyPolicy":  cron.GetConcurrencyPolicy(),
+					"PrevProcessGraphID": cron.PrevProcessGraphID,
+				}).Info("Skipping RunCron - previous process graph still running")
+				cmd.cronReplyChan <- cron
+				return
 			}
 
 			log.WithFields(log.Fields{"CronID": cron.ID, "CronName": cron.Name}).Info("Got cron, calling StartCron")
@@ -159,6 +155,89 @@ func (controller *ColoniesController) RunCron(cronID string) (*core.Cron, error)
 	}
 }
 
+func (controller *ColoniesController) SuspendCron(cronID string) (*core.Cron, error) {
+	return controller.setCronSuspended(cronID, true)
+}
+
+// ResumeCron resumes a suspended cron. The next run is calculated from now, i.e. runs that were due while
+// the cron was suspended are not started.
+func (controller *ColoniesController) ResumeCron(cronID string) (*core.Cron, error) {
+	return controller.setCronSuspended(cronID, false)
+}
+
+func (controller *ColoniesController) setCronSuspended(cronID string, suspended bool) (*core.Cron, error) {
+	cmd := &command{cronReplyChan: make(chan *core.Cron, 1),
+		errorChan: make(chan error, 1),
+		handler: func(cmd *command) {
+			cron, err := controller.cronDB.GetCronByID(cronID)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+			if cron == nil {
+				cmd.errorChan <- fmt.Errorf("cron not found")
+				return
+			}
+
+			if cron.Suspended == suspended {
+				cmd.cronReplyChan <- cron
+				return
+			}
+
+			err = controller.cronDB.SetCronSuspended(cronID, suspended)
+			if err != nil {
+				cmd.errorChan <- err
+				return
+			}
+			cron.Suspended = suspended
+
+			if !suspended {
+				cron.NextRun = controller.CalcNextRun(cron)
+				err = controller.cronDB.UpdateCron(cron.ID, cron.NextRun, cron.LastRun, cron.PrevProcessGraphID)
+				if err != nil {
+					cmd.errorChan <- err
+					return
+				}
+			}
+
+			log.WithFields(log.Fields{"CronID": cron.ID, "CronName": cron.Name, "Suspended": suspended}).Info("Cron suspension changed")
+
+			cmd.cronReplyChan <- cron
+		}}
+
+	controller.cmdQueue <- cmd
+	select {
+	case err := <-cmd.errorChan:
+		return nil, err
+	case cron := <-cmd.cronReplyChan:
+		return cron, nil
+	}
+}
+
+// GetCronRuns returns the run history of a cron, newest first, with the current state of each run.
+// Runs whose processgraph has been removed, e.g. by retention, are left out.
+func (controller *ColoniesController) GetCronRuns(cron *core.Cron) ([]*core.CronRun, error) {
+	runs, err := controller.cronDB.GetCronRuns(cron.ID, cron.GetRunHistoryLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*core.CronRun{}
+	for _, run := range runs {
+		processGraph, err := controller.processGraphDB.GetProcessGraphByID(run.ProcessGraphID)
+		if err != nil {
+			return nil, err
+		}
+		if processGraph == nil {
+			continue
+		}
+		run.State = processGraph.State
+		result = append(result, run)
+	}
+
+	return result, nil
+}
+
 func (controller *ColoniesController) RemoveCron(cronID string) error {
 	cmd := &command{errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
@@ -170,38 +249,78 @@ func (controller *ColoniesController) RemoveCron(cronID string) error {
 	return <-cmd.errorChan
 }
 
+// The catch-up policy starts at most maxCronCatchUpRunsPerTrigger missed runs each time crons are
+// triggered, the rest are started by later triggers. If more than maxCronCatchUpRuns runs have been
+// missed, the missed runs are skipped.
+const (
+	maxCronCatchUpRuns           = 100
+	maxCronCatchUpRunsPerTrigger = 10
+)
+
 func (controller *ColoniesController) CalcNextRun(cron *core.Cron) time.Time {
+	return calcNextRun(cron, time.Now())
+}
+
+// calcNextRun returns the first run of a cron after the given time, taking the validity window and time
+// zone of the cron into account. The zero time is returned if the cron will not run again. The jitter of
+// the cron is not included, it is added when the run is triggered, see core.Cron.DueTime.
+func calcNextRun(cron *core.Cron, after time.Time) time.Time {
+	if !cron.StartTime.IsZero() && after.Before(cron.StartTime) {
+		after = cron.StartTime
+	}
+
 	nextRun := time.Time{}
 	var err error
-	if cron.Interval > 0 {
-		nextRun, err = cronlib.NextInterval(cron.Interval)
-		if err != nil {
-			log.WithFields(log.Fields{"Error": err}).Error("Failed generate random next run")
-		}
-	} else if cron.Interval > 0 && cron.Random {
-		nextRun, err = cronlib.Random(cron.Interval)
-		if err != nil {
-			log.WithFields(log.Fields{"Error": err}).Error("Failed generate random next run")
-		}
+	if cron.Interval > 0 && cron.Random {
+		nextRun = after.Add(time.Duration(rand.Intn(cron.Interval)) * time.Second)
+	} else if cron.Interval > 0 {
+		nextRun = after.Add(time.Duration(cron.Interval) * time.Second)
 	} else {
-		nextRun, err = cronlib.Next(cron.CronExpression)
+		loc, locErr := cron.Location()
+		if locErr != nil {
+			log.WithFields(log.Fields{"Error": locErr, "CronID": cron.ID, "TimeZone": cron.TimeZone}).Error("Invalid cron time zone, using local time")
+			loc = time.Local
+		}
+		nextRun, err = cronlib.NextInLocation(cron.CronExpression, loc, after)
 		if err != nil {
 			log.WithFields(log.Fields{"Error": err}).Error("Failed generate next run based on cron expression")
+			return time.Time{}
 		}
 	}
 
+	if !cron.EndTime.IsZero() && nextRun.After(cron.EndTime) {
+		return time.Time{}
+	}
+
 	return nextRun
 }
 
 func (controller *ColoniesController) StartCron(cron *core.Cron) {
+	controller.startCron(cron, controller.CalcNextRun(cron))
+}
+
+// startCron creates a processgraph from the workflow of the cron and schedules the next run
+func (controller *ColoniesController) startCron(cron *core.Cron, nextRun time.Time) error {
 	log.WithFields(log.Fields{"CronID": cron.ID, "CronName": cron.Name}).Info("StartCron called")
 	workflowSpec, err := core.ConvertJSONToWorkflowSpec(cron.WorkflowSpec)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed to parsing WorkflowSpec")
-		return
+		return err
 	}
 	log.WithFields(log.Fields{"FunctionSpecs": len(workflowSpec.FunctionSpecs)}).Info("WorkflowSpec parsed")
 
+	// Functions with arg schemas may have been registered after the cron was added. The run is skipped,
+	// since it would fail again on every trigger.
+	err = controller.functionArgsVerifier.VerifyWorkflowArgs(workflowSpec, nil)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Skipping cron run, invalid function args")
+		cron.NextRun = nextRun
+		if updateErr := controller.cronDB.UpdateCron(cron.ID, cron.NextRun, cron.LastRun, cron.PrevProcessGraphID); updateErr != nil {
+			log.WithFields(log.Fields{"Error": updateErr, "CronId": cron.ID}).Error("Failed to update cron")
+		}
+		return err
+	}
+
 	var rootInput []interface{}
 	// Pick all outputs from the leaves of the previous processgraph and
 	// then use it as input to the root process in the next processgraph
@@ -225,11 +344,160 @@ func (controller *ColoniesController) StartCron(cron *core.Cron) {
 	processGraph, err := controller.CreateProcessGraph(workflowSpec, make([]interface{}, 0), make(map[string]interface{}), rootInput, cron.InitiatorID)
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Failed to create cron processgraph")
+		return err
+	}
+
+	cron.NextRun = nextRun
+	cron.LastRun = time.Now()
+	cron.PrevProcessGraphID = processGraph.ID
+
+	err = controller.cronDB.UpdateCron(cron.ID, cron.NextRun, cron.LastRun, cron.PrevProcessGraphID)
+	if err != nil {
+		return err
+	}
+
+	err = controller.cronDB.AddCronRun(&core.CronRun{CronID: cron.ID, ProcessGraphID: processGraph.ID, StartTime: cron.LastRun})
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Failed to add cron run to history")
+		return nil
+	}
+
+	err = controller.cronDB.PruneCronRuns(cron.ID, cron.GetRunHistoryLimit())
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Failed to prune cron run history")
+	}
+
+	return nil
+}
+
+// activeCronRuns returns the IDs of the waiting and running processgraphs started by a cron
+func (controller *ColoniesController) activeCronRuns(cron *core.Cron) ([]string, error) {
+	runs, err := controller.cronDB.GetCronRuns(cron.ID, cron.GetRunHistoryLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	processGraphIDs := []string{}
+	for _, run := range runs {
+		processGraphIDs = append(processGraphIDs, run.ProcessGraphID)
+	}
+	// Crons started before run history was recorded only know their previous processgraph
+	if len(runs) == 0 && cron.PrevProcessGraphID != "" {
+		processGraphIDs = append(processGraphIDs, cron.PrevProcessGraphID)
+	}
+
+	var active []string
+	for _, processGraphID := range processGraphIDs {
+		processGraph, err := controller.processGraphDB.GetProcessGraphByID(processGraphID)
+		if err != nil {
+			return nil, err
+		}
+		if processGraph != nil && (processGraph.State == core.WAITING || processGraph.State == core.RUNNING) {
+			active = append(active, processGraphID)
+		}
+	}
+
+	return active, nil
+}
+
+// admitCronRun returns true if a new run of the cron can be started according to its concurrency policy.
+// With the Replace policy, running runs are cancelled before true is returned.
+func (controller *ColoniesController) admitCronRun(cron *core.Cron) bool {
+	// Unlimited concurrent runs, no need to look up the running runs
+	if cron.GetConcurrencyPolicy() == core.CronConcurrencyAllow && cron.MaxConcurrentRuns == 0 {
+		return true
+	}
+
+	active, err := controller.activeCronRuns(cron)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "CronId": cron.ID}).Error("Failed to get running cron runs")
+		return false
+	}
+
+	switch cron.GetConcurrencyPolicy() {
+	case core.CronConcurrencyForbid:
+		return len(active) == 0
+	case core.CronConcurrencyReplace:
+		for _, processGraphID := range active {
+			log.WithFields(log.Fields{"CronId": cron.ID, "ProcessGraphID": processGraphID}).Info("Cancelling running cron run, replaced by new run")
+			if err := controller.cancelProcessGraph(processGraphID); err != nil {
+				log.WithFields(log.Fields{"Error": err, "CronId": cron.ID, "ProcessGraphID": processGraphID}).Error("Failed to cancel replaced cron run")
+				return false
+			}
+		}
+		return true
+	default:
+		return cron.MaxConcurrentRuns == 0 || len(active) < cron.MaxConcurrentRuns
+	}
+}
+
+// missedRunGracePeriod returns how late a run can be started before it counts as missed, e.g. because
+// no server was leader when it was due
+func (controller *ColoniesController) missedRunGracePeriod() time.Duration {
+	gracePeriod := 2 * time.Duration(controller.cronPeriod) * time.Millisecond
+	if gracePeriod < time.Minute {
+		gracePeriod = time.Minute
+	}
+
+	return gracePeriod
+}
+
+// triggerCron starts an expired cron according to its concurrency and missed run policies
+func (controller *ColoniesController) triggerCron(cron *core.Cron) {
+	missed := time.Since(cron.DueTime()) > controller.missedRunGracePeriod()
+	if !missed {
+		if controller.admitCronRun(cron) {
+			log.WithFields(log.Fields{"CronId": cron.ID}).Debug("Triggering cron workflow")
+			controller.StartCron(cron)
+		}
 		return
 	}
 
-	nextRun := controller.CalcNextRun(cron)
-	controller.cronDB.UpdateCron(cron.ID, nextRun, time.Now(), processGraph.ID)
+	switch cron.MissedRunPolicy {
+	case core.CronMissedRunsSkip:
+		nextRun := controller.CalcNextRun(cron)
+		log.WithFields(log.Fields{"CronId": cron.ID, "MissedRun": cron.NextRun, "NextRun": nextRun}).Info("Skipping missed cron run")
+		controller.cronDB.UpdateCron(cron.ID, nextRun, cron.LastRun, cron.PrevProcessGraphID)
+	case core.CronMissedRunsCatchUp:
+		if countMissedRuns(cron, time.Now(), maxCronCatchUpRuns+1) > maxCronCatchUpRuns {
+			nextRun := controller.CalcNextRun(cron)
+			log.WithFields(log.Fields{"CronId": cron.ID, "MissedRun": cron.NextRun, "NextRun": nextRun}).Warn("Too many missed cron runs, skipping missed runs")
+			controller.cronDB.UpdateCron(cron.ID, nextRun, cron.LastRun, cron.PrevProcessGraphID)
+			return
+		}
+
+		for runs := 0; runs < maxCronCatchUpRunsPerTrigger; runs++ {
+			if !controller.admitCronRun(cron) {
+				return
+			}
+
+			log.WithFields(log.Fields{"CronId": cron.ID, "MissedRun": cron.NextRun}).Info("Catching up missed cron run")
+			nextRun := calcNextRun(cron, cron.NextRun)
+			if err := controller.startCron(cron, nextRun); err != nil {
+				return
+			}
+
+			// Crons that forbid or replace concurrent runs are caught up one run at a time
+			if cron.GetConcurrencyPolicy() != core.CronConcurrencyAllow || nextRun.IsZero() || nextRun.After(time.Now()) {
+				return
+			}
+		}
+	default:
+		if controller.admitCronRun(cron) {
+			controller.StartCron(cron)
+		}
+	}
+}
+
+// countMissedRuns returns the number of scheduled runs of a cron from its next run up to now, counting at
+// most limit runs
+func countMissedRuns(cron *core.Cron, now time.Time, limit int) int {
+	missed := 0
+	for run := cron.NextRun; !run.IsZero() && !run.After(now) && missed < limit; run = calcNextRun(cron, run) {
+		missed++
+	}
+
+	return missed
 }
 
 func (controller *ColoniesController) TriggerCrons() {
@@ -240,32 +508,21 @@ func (controller *ColoniesController) TriggerCrons() {
 			return
 		}
 		for _, cron := range crons {
+			if cron.Suspended || cron.HasEnded() {
+				continue
+			}
 			t := time.Time{}
 			if t.Unix() == cron.NextRun.Unix() { // This if-statement will be true the first time the cron is evaluted
+				if !cron.LastRun.IsZero() {
+					continue // The validity window of the cron has passed
+				}
 				nextRun := controller.CalcNextRun(cron)
 				controller.cronDB.UpdateCron(cron.ID, nextRun, time.Time{}, "")
 				cron.NextRun = nextRun
 				continue
 			}
 			if cron.HasExpired() {
-				processgraph, err := controller.processGraphDB.GetProcessGraphByID(cron.PrevProcessGraphID)
-				if err != nil {
-					log.WithFields(log.Fields{"Error": err, "PrevProcessGraphId": cron.PrevProcessGraphID}).Error("Failed getting all crons")
-					continue
-				}
-				if processgraph == nil {
-					controller.StartCron(cron)
-					continue
-				}
-				if cron.WaitForPrevProcessGraph {
-					if processgraph.State == core.SUCCESS || processgraph.State == core.FAILED {
-						log.WithFields(log.Fields{"CronId": cron.ID}).Debug("Triggering cron workflow")
-						controller.StartCron(cron)
-					}
-				} else {
-					log.WithFields(log.Fields{"CronId": cron.ID}).Debug("Triggering cron workflow")
-					controller.StartCron(cron)
-				}
+				controller.triggerCron(cron)
 			}
 		}
 	}}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcNextRun_TimeZone(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	assert.Nil(t, err)
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "0 2 * * *", 0, false, "workflow")
+	cron.TimeZone = "Europe/Stockholm"
+
+	after := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	nextRun := calcNextRun(cron, after)
+	assert.Equal(t, time.Date(2026, 6, 2, 2, 0, 0, 0, stockholm).Unix(), nextRun.Unix())
+
+	// 6 fields, the first field is seconds
+	cron.CronExpression = "30 0 2 * * *"
+	nextRun = calcNextRun(cron, after)
+	assert.Equal(t, time.Date(2026, 6, 2, 2, 0, 30, 0, stockholm).Unix(), nextRun.Unix())
+}
+
+func TestCalcNextRun_Window(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "", 60, false, "workflow")
+	cron.StartTime = start
+	cron.EndTime = start.Add(2 * time.Minute)
+
+	// Runs are not scheduled before the start time
+	nextRun := calcNextRun(cron, start.Add(-time.Hour))
+	assert.Equal(t, start.Add(time.Minute).Unix(), nextRun.Unix())
+
+	nextRun = calcNextRun(cron, nextRun)
+	assert.Equal(t, start.Add(2*time.Minute).Unix(), nextRun.Unix())
+
+	// Runs after the end time are not scheduled
+	nextRun = calcNextRun(cron, nextRun)
+	assert.True(t, nextRun.IsZero())
+}
+
+func TestCalcNextRun_Jitter(t *testing.T) {
+	after := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "", 60, false, "workflow")
+	cron.Jitter = 10
+
+	// The jitter is added when the run is triggered, so that it does not accumulate in the schedule
+	nextRun := calcNextRun(cron, after)
+	assert.Equal(t, after.Add(time.Minute).Unix(), nextRun.Unix())
+
+	cron.NextRun = nextRun
+	delay := cron.DueTime().Sub(nextRun)
+	assert.True(t, delay >= 0 && delay <= 10*time.Second, delay)
+	assert.Equal(t, after.Add(2*time.Minute).Unix(), calcNextRun(cron, cron.NextRun).Unix())
+}
+
+func TestCalcNextRun_Random(t *testing.T) {
+	after := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "", 60, true, "workflow")
+
+	runs := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		nextRun := calcNextRun(cron, after)
+		delay := nextRun.Sub(after)
+		assert.True(t, delay >= 0 && delay < time.Minute, delay)
+		runs[nextRun.Unix()] = true
+	}
+	assert.True(t, len(runs) > 1)
+}
+
+func TestCountMissedRuns(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cron := core.CreateCron(core.GenerateRandomID(), "test_name", "", 60, false, "workflow")
+	cron.NextRun = now.Add(-10 * time.Minute)
+	assert.Equal(t, 11, countMissedRuns(cron, now, 100))
+	assert.Equal(t, 5, countMissedRuns(cron, now, 5))
+
+	cron.NextRun = now.Add(time.Second)
+	assert.Equal(t, 0, countMissedRuns(cron, now, 100))
+
+	// Runs after the end time are not counted
+	cron.NextRun = now.Add(-10 * time.Minute)
+	cron.EndTime = now.Add(-5 * time.Minute)
+	assert.Equal(t, 6, countMissedRuns(cron, now, 100))
+}
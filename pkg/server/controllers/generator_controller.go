@@ -33,6 +33,11 @@ func (controller *ColoniesController) AddGenerator(generator *core.Generator) (*
 	}
 }
 
+const (
+	maxGeneratorWindowsPerTrigger = 100         // Remaining windows are submitted on the next trigger
+	generatorWindowGracePeriod    = time.Second // Wait for args packed just before the window ended
+)
+
 func (controller *ColoniesController) TriggerGenerators() {
 	cmd := &command{threaded: true, handler: func(cmd *command) {
 		generatorsFromDB, err := controller.generatorDB.FindAllGenerators()
@@ -40,45 +45,210 @@ func (controller *ColoniesController) TriggerGenerators() {
 			log.WithFields(log.Fields{"Error": err}).Error("Failed get all generators from db")
 			return
 		}
+		now := time.Now()
 		for _, generator := range generatorsFromDB {
-			counter, err := controller.generatorDB.CountGeneratorArgs(generator.ID)
+			if generator.IsWindowed() {
+				controller.triggerGeneratorWindows(generator, now)
+			} else {
+				controller.triggerGeneratorBatches(generator, now)
+			}
+		}
+	}}
+
+	controller.cmdQueue <- cmd
+}
+
+// triggerGeneratorBatches submits the batches of each key that have reached the count or size trigger, or
+// have timed out
+func (controller *ColoniesController) triggerGeneratorBatches(generator *core.Generator, now time.Time) {
+	groups, err := controller.generatorDB.GetGeneratorArgGroups(generator.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed count generator args from db")
+		return
+	}
+
+	timeout := time.Duration(generator.Timeout) * time.Second
+	for _, group := range groups {
+		if !(generator.Trigger > 0 && group.Count >= generator.Trigger) &&
+			!(generator.TriggerSize > 0 && group.Size >= generator.TriggerSize) &&
+			!(generator.Timeout > 0 && now.After(group.Oldest.Add(timeout))) {
+			continue
+		}
+
+		generatorArgs, err := controller.generatorDB.GetGeneratorArgsByKey(generator.ID, group.Key, group.Count)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed get generator args from db")
+			continue
+		}
+
+		for _, batch := range cutGeneratorBatches(generator, generatorArgs, now) {
+			log.WithFields(log.Fields{
+				"GeneratorId": generator.ID,
+				"Key":         group.Key,
+				"Counter":     len(batch)}).
+				Debug("Generator threshold reached, submitting workflow")
+			if !controller.submitGeneratorArgs(generator, batch, generator.InitiatorID) {
+				break
+			}
+		}
+	}
+}
+
+// cutGeneratorBatches splits the pending args of a key, in insertion order, into batches ready to be submitted.
+// A batch is full when it holds Trigger args or when adding the next arg would exceed TriggerSize bytes. The
+// remaining args are submitted as a partial batch when the oldest of them has waited longer than Timeout.
+func cutGeneratorBatches(generator *core.Generator, generatorArgs []*core.GeneratorArg, now time.Time) [][]*core.GeneratorArg {
+	var batches [][]*core.GeneratorArg
+	var batch []*core.GeneratorArg
+	size := 0
+	for _, generatorArg := range generatorArgs {
+		if len(batch) > 0 && generator.TriggerSize > 0 && size+len(generatorArg.Arg) > generator.TriggerSize {
+			batches = append(batches, batch)
+			batch = nil
+			size = 0
+		}
+
+		batch = append(batch, generatorArg)
+		size += len(generatorArg.Arg)
+
+		if (generator.Trigger > 0 && len(batch) >= generator.Trigger) || (generator.TriggerSize > 0 && size >= generator.TriggerSize) {
+			batches = append(batches, batch)
+			batch = nil
+			size = 0
+		}
+	}
+
+	timeout := time.Duration(generator.Timeout) * time.Second
+	if len(batch) > 0 && generator.Timeout > 0 && now.After(batch[0].Added.Add(timeout)) {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// triggerGeneratorWindows submits one batch per key for every window that has closed since the last trigger.
+// The keys submitted for a window are recorded, so if a submission fails, only the remaining keys are submitted
+// on the next trigger.
+func (controller *ColoniesController) triggerGeneratorWindows(generator *core.Generator, now time.Time) {
+	windowEnds := generatorWindowEnds(generator, now.Add(-generatorWindowGracePeriod))
+	if len(windowEnds) == 0 {
+		return
+	}
+
+	groups, err := controller.generatorDB.GetGeneratorArgGroups(generator.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed count generator args from db")
+		return
+	}
+
+	windowSize := time.Duration(generator.WindowSize) * time.Second
+	lastWindowEnd := generator.LastWindowEnd
+	for _, windowEnd := range windowEnds {
+		windowEnd := windowEnd
+		windowStart := windowEnd.Add(-windowSize)
+		submitted := false
+
+		var windowKeys []string
+		if generator.OpenWindowEnd.Equal(windowEnd) {
+			windowKeys = append(windowKeys, generator.WindowKeys...)
+		}
+		submittedKeys := make(map[string]bool)
+		for _, key := range windowKeys {
+			submittedKeys[key] = true
+		}
+
+		for _, group := range groups {
+			if submittedKeys[group.Key] {
+				continue
+			}
+
+			generatorArgs, err := controller.generatorDB.GetGeneratorArgsByKeyAndTime(generator.ID, group.Key, windowStart, windowEnd)
 			if err != nil {
-				log.WithFields(log.Fields{"Error": err}).Error("Failed count generator args from db")
+				log.WithFields(log.Fields{"Error": err}).Error("Failed get generator args from db")
+				return
+			}
+			if len(generatorArgs) == 0 {
 				continue
 			}
-			now := time.Now()
-			timeout := false
-			if generator.LastRun.Unix() <= 0 { // Generator has never run
-				if generator.FirstPack.Unix() <= 0 { // Generator has never been packed
-					timeout = false
-				} else { // Generator has been packed, calulcate deadline based first pack
-					timeoutDeadline := generator.FirstPack.Add(time.Duration(generator.Timeout) * time.Second)
-					timeout = now.Unix() > timeoutDeadline.Unix()
-				}
-			} else { // Generator has run before
-				timeoutDeadline := generator.LastRun.Add(time.Duration(generator.Timeout) * time.Second)
-				timeout = now.Unix() > timeoutDeadline.Unix()
+
+			batch := createGeneratorBatch(group.Key, generatorArgs)
+			batch.WindowStart = &windowStart
+			batch.WindowEnd = &windowEnd
+
+			log.WithFields(log.Fields{
+				"GeneratorId": generator.ID,
+				"Key":         group.Key,
+				"WindowStart": windowStart,
+				"WindowEnd":   windowEnd,
+				"Counter":     len(generatorArgs)}).
+				Debug("Generator window closed, submitting workflow")
+
+			err = controller.submitGeneratorBatch(generator, batch, generator.InitiatorID)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed to create generator processgraph")
+				return
 			}
-			if counter >= generator.Trigger {
-				timesToSubmit := counter / generator.Trigger
-				for i := 0; i < timesToSubmit; i++ {
-					log.WithFields(log.Fields{
-						"GeneratorId": generator.ID,
-						"Counter":     counter}).
-						Debug("Generator threshold reached, submitting workflow")
-					controller.SubmitWorkflow(generator, generator.Trigger, generator.InitiatorID)
-				}
-			} else if counter >= 1 && generator.Timeout > 0 && timeout {
-				log.WithFields(log.Fields{
-					"GeneratorId": generator.ID,
-					"Counter":     counter}).
-					Debug("Generator threshold reached, submitting workflow")
-				controller.SubmitWorkflow(generator, counter, generator.InitiatorID)
+			submitted = true
+
+			windowKeys = append(windowKeys, group.Key)
+			err = controller.generatorDB.SetGeneratorWindowKeys(generator.ID, windowEnd, windowKeys)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed to set generator window keys")
+				return
 			}
 		}
-	}}
 
-	controller.cmdQueue <- cmd
+		err = controller.generatorDB.SetGeneratorLastWindowEnd(generator.ID, windowEnd)
+		if err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to set generator last window end")
+			return
+		}
+		lastWindowEnd = windowEnd
+
+		if submitted {
+			err = controller.generatorDB.SetGeneratorLastRun(generator.ID)
+			if err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed mark generator as run")
+			}
+		}
+	}
+
+	// Args packed before the start of the next window can not be part of any future window
+	nextWindowStart := lastWindowEnd.Add(generator.GetWindowSlide()).Add(-windowSize)
+	err = controller.generatorDB.RemoveGeneratorArgsAddedBefore(generator.ID, nextWindowStart)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to remove generator args")
+	}
+}
+
+// generatorWindowEnds returns the ends of the windows that have closed after the last submitted window, at the
+// latest at now. Window ends are aligned to multiples of the window slide. A generator without a submitted window
+// starts with the most recently closed window.
+func generatorWindowEnds(generator *core.Generator, now time.Time) []time.Time {
+	slide := generator.GetWindowSlide()
+	latestEnd := now.Truncate(slide)
+
+	end := latestEnd
+	if generator.LastWindowEnd.Unix() > 0 {
+		end = generator.LastWindowEnd.Truncate(slide).Add(slide)
+	}
+
+	var windowEnds []time.Time
+	for ; !end.After(latestEnd) && len(windowEnds) < maxGeneratorWindowsPerTrigger; end = end.Add(slide) {
+		windowEnds = append(windowEnds, end)
+	}
+
+	return windowEnds
+}
+
+func createGeneratorBatch(key string, generatorArgs []*core.GeneratorArg) *core.GeneratorBatch {
+	batch := &core.GeneratorBatch{Key: key, Args: make([]string, 0, len(generatorArgs))}
+	for _, generatorArg := range generatorArgs {
+		batch.Args = append(batch.Args, generatorArg.Arg)
+		batch.Size += len(generatorArg.Arg)
+	}
+
+	return batch
 }
 
 func (controller *ColoniesController) GetGenerator(generatorID string) (*core.Generator, error) {
@@ -144,17 +314,18 @@ func (controller *ColoniesController) GetGenerators(colonyName string, count int
 	}
 }
 
-func (controller *ColoniesController) PackGenerator(generatorID string, colonyName, arg string) error {
+func (controller *ColoniesController) PackGenerator(generatorID string, colonyName string, key string, arg string) error {
 	cmd := &command{errorChan: make(chan error, 1),
 		handler: func(cmd *command) {
 			generatorArg := core.CreateGeneratorArg(generatorID, colonyName, arg)
+			generatorArg.Key = key
 			err := controller.generatorDB.AddGeneratorArg(generatorArg)
 			if err != nil {
 				log.WithFields(log.Fields{"Error": err}).Error("Failed add generator args")
 				cmd.errorChan <- err
 			}
 			count, err := controller.generatorDB.CountGeneratorArgs(generatorID)
-			log.WithFields(log.Fields{"Arg": arg, "Count": count, "GeneratorId": generatorID}).Debug("Added args to generator")
+			log.WithFields(log.Fields{"Arg": arg, "Key": key, "Count": count, "GeneratorId": generatorID}).Debug("Added args to generator")
 
 			generator, err := controller.generatorDB.GetGeneratorByID(generatorID)
 			if err != nil {
@@ -197,46 +368,47 @@ func (controller *ColoniesController) GeneratorTriggerLoop() {
 	}
 }
 
+// SubmitWorkflow submits a workflow with the next counter args packed without a key
 func (controller *ColoniesController) SubmitWorkflow(generator *core.Generator, counter int, recoveredID string) {
-	workflowSpec, err := core.ConvertJSONToWorkflowSpec(generator.WorkflowSpec)
+	generatorArgs, err := controller.generatorDB.GetGeneratorArgsByKey(generator.ID, "", counter)
 	if err != nil {
-		log.WithFields(log.Fields{"Error": err}).Error("Failed to parse workflow spec")
+		log.WithFields(log.Fields{"Error": err}).Error("Failed get generator args from db")
 		return
 	}
 
-	generatorArgs, err := controller.generatorDB.GetGeneratorArgs(generator.ID, counter)
-	var args []string
-	for _, generatorArg := range generatorArgs {
-		args = append(args, generatorArg.Arg)
+	controller.submitGeneratorArgs(generator, generatorArgs, recoveredID)
+}
+
+// submitGeneratorArgs submits a workflow for a batch of args and removes the args, returns false if it failed
+func (controller *ColoniesController) submitGeneratorArgs(generator *core.Generator, generatorArgs []*core.GeneratorArg, recoveredID string) bool {
+	key := ""
+	if len(generatorArgs) > 0 {
+		key = generatorArgs[0].Key
 	}
+	batch := createGeneratorBatch(key, generatorArgs)
 
 	log.WithFields(log.Fields{
 		"GeneratorId": generator.ID,
 		"Trigger":     generator.Trigger,
-		"Counter":     counter,
-		"Args":        args}).
+		"Key":         key,
+		"Counter":     len(generatorArgs),
+		"Args":        batch.Args}).
 		Debug("Generator submitting workflow")
 
-	argsif := make([]interface{}, len(args))
-	for i, v := range args {
-		argsif[i] = v
-	}
-
-	_, err = controller.CreateProcessGraph(workflowSpec, argsif, make(map[string]interface{}), make([]interface{}, 0), recoveredID)
+	err := controller.submitGeneratorBatch(generator, batch, recoveredID)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Error": err}).
 			Error("Failed to create generator processgraph")
-		return
+		return false
 	}
 
 	// Now it safe to remove the args since they are now attached to a process graph
 	for _, generatorArg := range generatorArgs {
-		count, err := controller.generatorDB.CountGeneratorArgs(generator.ID)
 		log.WithFields(log.Fields{
 			"GeneratorId": generator.ID,
 			"Trigger":     generator.Trigger,
-			"Count":       count,
+			"Key":         generatorArg.Key,
 			"Arg":         generatorArg.Arg}).
 			Debug("Removing generator arg")
 
@@ -245,7 +417,7 @@ func (controller *ColoniesController) SubmitWorkflow(generator *core.Generator,
 			log.WithFields(log.Fields{
 				"Error": err}).
 				Error("Failed to remove generator arg")
-			return
+			return false
 		}
 	}
 
@@ -253,4 +425,35 @@ func (controller *ColoniesController) SubmitWorkflow(generator *core.Generator,
 	if err != nil {
 		log.WithFields(log.Fields{"Error": err}).Error("Failed mark generator as run")
 	}
+
+	return true
+}
+
+// submitGeneratorBatch creates a process graph from the generator workflow, the batch is passed to the root
+// processes as positional args or, using the structured batch format, as a single GeneratorBatch arg
+func (controller *ColoniesController) submitGeneratorBatch(generator *core.Generator, batch *core.GeneratorBatch, recoveredID string) error {
+	workflowSpec, err := core.ConvertJSONToWorkflowSpec(generator.WorkflowSpec)
+	if err != nil {
+		return err
+	}
+
+	var args []interface{}
+	if generator.BatchFormat == core.GeneratorBatchStructured {
+		args = []interface{}{batch}
+	} else {
+		args = make([]interface{}, len(batch.Args))
+		for i, arg := range batch.Args {
+			args[i] = arg
+		}
+	}
+
+	// An invalid batch would fail again on every trigger, so it is dropped instead of returning an error
+	err = controller.functionArgsVerifier.VerifyWorkflowArgs(workflowSpec, args)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "GeneratorId": generator.ID, "Args": batch.Args}).Error("Dropping generator batch, invalid function args")
+		return nil
+	}
+
+	_, err = controller.CreateProcessGraph(workflowSpec, args, make(map[string]interface{}), make([]interface{}, 0), recoveredID)
+	return err
 }
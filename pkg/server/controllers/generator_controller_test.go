@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestGeneratorArgs(count int, size int, added time.Time) []*core.GeneratorArg {
+	var generatorArgs []*core.GeneratorArg
+	for i := 0; i < count; i++ {
+		generatorArg := core.CreateGeneratorArg(core.GenerateRandomID(), "test_colony", strings.Repeat("a", size))
+		generatorArg.Added = added
+		generatorArgs = append(generatorArgs, generatorArg)
+	}
+
+	return generatorArgs
+}
+
+func TestCutGeneratorBatches_Count(t *testing.T) {
+	now := time.Now()
+	generator := core.CreateGenerator("test_colony", "test_genname", "", 10, -1)
+
+	batches := cutGeneratorBatches(generator, createTestGeneratorArgs(73, 1, now), now)
+	assert.Len(t, batches, 7)
+	for _, batch := range batches {
+		assert.Len(t, batch, 10)
+	}
+
+	// The remaining args are submitted when the oldest arg has timed out
+	generator.Timeout = 1
+	batches = cutGeneratorBatches(generator, createTestGeneratorArgs(13, 1, now), now)
+	assert.Len(t, batches, 1)
+
+	batches = cutGeneratorBatches(generator, createTestGeneratorArgs(13, 1, now.Add(-2*time.Second)), now)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[1], 3)
+}
+
+func TestCutGeneratorBatches_Size(t *testing.T) {
+	now := time.Now()
+	generator := core.CreateGenerator("test_colony", "test_genname", "", -1, -1)
+	generator.TriggerSize = 100
+
+	// Batches never exceed the trigger size
+	batches := cutGeneratorBatches(generator, createTestGeneratorArgs(10, 30, now), now)
+	assert.Len(t, batches, 3)
+	for _, batch := range batches {
+		assert.Len(t, batch, 3)
+	}
+
+	// An arg larger than the trigger size is submitted as a batch of its own
+	generatorArgs := append(createTestGeneratorArgs(1, 30, now), createTestGeneratorArgs(1, 200, now)...)
+	batches = cutGeneratorBatches(generator, generatorArgs, now)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 1)
+	assert.Len(t, batches[1], 1)
+
+	// Count and size triggers can be combined, whichever is reached first closes the batch
+	generator.Trigger = 2
+	batches = cutGeneratorBatches(generator, createTestGeneratorArgs(4, 30, now), now)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+}
+
+func TestGeneratorWindowEnds(t *testing.T) {
+	generator := core.CreateGenerator("test_colony", "test_genname", "", -1, -1)
+	generator.WindowType = core.GeneratorWindowSliding
+	generator.WindowSize = 60
+	generator.WindowSlide = 10
+
+	now := time.Date(2026, 6, 1, 12, 0, 35, 0, time.UTC)
+
+	// A generator that has never submitted a window starts with the latest closed window
+	windowEnds := generatorWindowEnds(generator, now)
+	assert.Len(t, windowEnds, 1)
+	assert.Equal(t, time.Date(2026, 6, 1, 12, 0, 30, 0, time.UTC), windowEnds[0])
+
+	generator.LastWindowEnd = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	windowEnds = generatorWindowEnds(generator, now)
+	assert.Len(t, windowEnds, 3)
+	assert.Equal(t, time.Date(2026, 6, 1, 12, 0, 10, 0, time.UTC), windowEnds[0])
+	assert.Equal(t, time.Date(2026, 6, 1, 12, 0, 30, 0, time.UTC), windowEnds[2])
+
+	generator.LastWindowEnd = time.Date(2026, 6, 1, 12, 0, 30, 0, time.UTC)
+	assert.Len(t, generatorWindowEnds(generator, now), 0)
+
+	// Tumbling windows slide by their size, and the number of windows per trigger is capped
+	generator.WindowType = core.GeneratorWindowTumbling
+	generator.WindowSlide = 0
+	generator.LastWindowEnd = now.Add(-24 * time.Hour)
+	windowEnds = generatorWindowEnds(generator, now)
+	assert.Len(t, windowEnds, maxGeneratorWindowsPerTrigger)
+	assert.Equal(t, time.Minute, windowEnds[1].Sub(windowEnds[0]))
+}
+
+func TestCreateGeneratorBatch(t *testing.T) {
+	generatorArgs := createTestGeneratorArgs(3, 5, time.Now())
+	batch := createGeneratorBatch("tenant1", generatorArgs)
+	assert.Equal(t, "tenant1", batch.Key)
+	assert.Len(t, batch.Args, 3)
+	assert.Equal(t, 15, batch.Size)
+}
@@ -134,6 +134,10 @@ func (v *ControllerMock) UnassignExecutor(processID string) error {
 	return nil
 }
 
+func (v *ControllerMock) ReleaseProcess(processID string) error {
+	return nil
+}
+
 func (v *ControllerMock) ResetProcess(processID string) error {
 	return nil
 }
@@ -154,7 +158,7 @@ func (v *ControllerMock) GetGenerators(colonyName string, count int) ([]*core.Ge
 	return nil, nil
 }
 
-func (v *ControllerMock) PackGenerator(generatorID string, colonyName, arg string) error {
+func (v *ControllerMock) PackGenerator(generatorID string, colonyName string, key string, arg string) error {
 	return nil
 }
 
@@ -195,6 +199,18 @@ func (v *ControllerMock) RemoveCron(cronID string) error {
 	return nil
 }
 
+func (v *ControllerMock) SuspendCron(cronID string) (*core.Cron, error) {
+	return nil, nil
+}
+
+func (v *ControllerMock) ResumeCron(cronID string) (*core.Cron, error) {
+	return nil, nil
+}
+
+func (v *ControllerMock) GetCronRuns(cron *core.Cron) ([]*core.CronRun, error) {
+	return nil, nil
+}
+
 func (v *ControllerMock) CalcNextRun(cron *core.Cron) time.Time {
 	return time.Time{}
 }
@@ -331,6 +347,53 @@ func (db *DatabaseMock) RemoveLocationByID(locationID string) error { return nil
 func (db *DatabaseMock) RemoveLocationByName(colonyName string, name string) error { return nil }
 func (db *DatabaseMock) RemoveLocationsByColonyName(colonyName string) error { return nil }
 
+// WebhookDatabase interface
+func (db *DatabaseMock) AddWebhook(webhook *core.Webhook) error { return nil }
+func (db *DatabaseMock) GetWebhookByID(webhookID string) (*core.Webhook, error) { return nil, nil }
+func (db *DatabaseMock) GetWebhookByName(colonyName string, name string) (*core.Webhook, error) { return nil, nil }
+func (db *DatabaseMock) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) { return nil, nil }
+func (db *DatabaseMock) RemoveWebhookByName(colonyName string, name string) error { return nil }
+func (db *DatabaseMock) RemoveWebhooksByColonyName(colonyName string) error { return nil }
+func (db *DatabaseMock) AddWebhookDelivery(delivery *core.WebhookDelivery) error { return nil }
+func (db *DatabaseMock) GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error) { return nil, nil }
+
+// StorageBackendDatabase interface
+func (db *DatabaseMock) AddStorageBackend(backend *core.StorageBackend) error { return nil }
+func (db *DatabaseMock) GetStorageBackendByName(colonyName string, name string) (*core.StorageBackend, error) { return nil, nil }
+func (db *DatabaseMock) GetStorageBackendsByColonyName(colonyName string) ([]*core.StorageBackend, error) { return nil, nil }
+func (db *DatabaseMock) RemoveStorageBackendByName(colonyName string, name string) error { return nil }
+func (db *DatabaseMock) RemoveStorageBackendsByColonyName(colonyName string) error { return nil }
+func (db *DatabaseMock) AddStorageObject(object *core.StorageObject) error { return nil }
+func (db *DatabaseMock) RegisterStorageObject(colonyName string, backend string, object string) (bool, error) {
+	return false, nil
+}
+func (db *DatabaseMock) GetStorageObjectsByColonyName(colonyName string) ([]*core.StorageObject, error) {
+	return nil, nil
+}
+func (db *DatabaseMock) RemoveStorageObject(colonyName string, object string) error { return nil }
+func (db *DatabaseMock) RemoveStorageObjectsByColonyName(colonyName string) error  { return nil }
+
+// FileRetentionDatabase interface
+func (db *DatabaseMock) SetFileRetentionPolicy(policy *core.FileRetentionPolicy) error { return nil }
+func (db *DatabaseMock) GetFileRetentionPolicyByLabel(colonyName string, label string) (*core.FileRetentionPolicy, error) { return nil, nil }
+func (db *DatabaseMock) GetFileRetentionPoliciesByColonyName(colonyName string) ([]*core.FileRetentionPolicy, error) { return nil, nil }
+func (db *DatabaseMock) RemoveFileRetentionPolicyByLabel(colonyName string, label string) error { return nil }
+func (db *DatabaseMock) RemoveFileRetentionPoliciesByColonyName(colonyName string) error { return nil }
+
+// FileLineageDatabase interface
+func (db *DatabaseMock) AddFileLineages(lineages []*core.FileLineage) error { return nil }
+func (db *DatabaseMock) GetFileLineageByProcessID(colonyName string, processID string) ([]*core.FileLineage, error) { return nil, nil }
+func (db *DatabaseMock) GetFileLineageByProcessGraphID(colonyName string, processGraphID string) ([]*core.FileLineage, error) { return nil, nil }
+func (db *DatabaseMock) GetFileLineageByFileID(colonyName string, fileID string) ([]*core.FileLineage, error) { return nil, nil }
+func (db *DatabaseMock) RemoveFileLineageByColonyName(colonyName string) error { return nil }
+
+// LogSinkDatabase interface
+func (db *DatabaseMock) AddLogSink(sink *core.LogSink) error { return nil }
+func (db *DatabaseMock) GetLogSinkByName(colonyName string, name string) (*core.LogSink, error) { return nil, nil }
+func (db *DatabaseMock) GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error) { return nil, nil }
+func (db *DatabaseMock) RemoveLogSinkByName(colonyName string, name string) error { return nil }
+func (db *DatabaseMock) RemoveLogSinksByColonyName(colonyName string) error { return nil }
+
 // ProcessDatabase interface
 func (db *DatabaseMock) AddProcess(process *core.Process) error {
 	if db.ReturnError == "AddProcess" { return errors.New("mock error") }
@@ -384,6 +447,7 @@ func (db *DatabaseMock) SetChildren(processID string, children []string) error {
 func (db *DatabaseMock) Assign(executorID string, process *core.Process) error { return nil }
 func (db *DatabaseMock) SelectAndAssign(colonyName string, executorID string, executorName string, executorType string, executorLocation string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) (*core.Process, error) { return nil, nil }
 func (db *DatabaseMock) Unassign(process *core.Process) error { return nil }
+func (db *DatabaseMock) Release(process *core.Process) error { return nil }
 func (db *DatabaseMock) MarkFailed(processID string, errs []string) error { return nil }
 func (db *DatabaseMock) CountProcesses() (int, error) { return 0, nil }
 func (db *DatabaseMock) CountWaitingProcesses() (int, error) { return 0, nil }
@@ -491,10 +555,16 @@ func (db *DatabaseMock) FindGeneratorsByColonyName(colonyName string, count int)
 func (db *DatabaseMock) RemoveGeneratorByID(generatorID string) error { return nil }
 func (db *DatabaseMock) AddGeneratorArg(generatorArg *core.GeneratorArg) error { return nil }
 func (db *DatabaseMock) GetGeneratorArgs(generatorID string, count int) ([]*core.GeneratorArg, error) { return nil, nil }
+func (db *DatabaseMock) GetGeneratorArgsByKey(generatorID string, key string, count int) ([]*core.GeneratorArg, error) { return nil, nil }
+func (db *DatabaseMock) GetGeneratorArgsByKeyAndTime(generatorID string, key string, from time.Time, to time.Time) ([]*core.GeneratorArg, error) { return nil, nil }
+func (db *DatabaseMock) GetGeneratorArgGroups(generatorID string) ([]*core.GeneratorArgGroup, error) { return nil, nil }
 func (db *DatabaseMock) CountGeneratorArgs(generatorID string) (int, error) { return 0, nil }
+func (db *DatabaseMock) RemoveGeneratorArgsAddedBefore(generatorID string, before time.Time) error { return nil }
 func (db *DatabaseMock) RemoveGeneratorArgByID(generatorArgID string) error { return nil }
 func (db *DatabaseMock) SetGeneratorLastRun(generatorID string) error { return nil }
 func (db *DatabaseMock) SetGeneratorFirstPack(generatorID string) error { return nil }
+func (db *DatabaseMock) SetGeneratorLastWindowEnd(generatorID string, windowEnd time.Time) error { return nil }
+func (db *DatabaseMock) SetGeneratorWindowKeys(generatorID string, windowEnd time.Time, keys []string) error { return nil }
 func (db *DatabaseMock) RemoveAllGeneratorsByColonyName(colonyName string) error { return nil }
 func (db *DatabaseMock) RemoveAllGeneratorArgsByGeneratorID(generatorID string) error { return nil }
 func (db *DatabaseMock) RemoveAllGeneratorArgsByColonyName(colonyName string) error { return nil }
@@ -517,9 +587,15 @@ func (db *DatabaseMock) RemoveCronByID(cronID string) error {
 func (db *DatabaseMock) UpdateCron(cronID string, nextRun time.Time, lastRun time.Time, processGraphID string) error { return nil }
 func (db *DatabaseMock) GetCronByName(colonyName string, cronName string) (*core.Cron, error) { return nil, nil }
 func (db *DatabaseMock) RemoveAllCronsByColonyName(colonyName string) error { return nil }
+func (db *DatabaseMock) SetCronSuspended(cronID string, suspended bool) error { return nil }
+func (db *DatabaseMock) AddCronRun(run *core.CronRun) error { return nil }
+func (db *DatabaseMock) GetCronRuns(cronID string, count int) ([]*core.CronRun, error) { return nil, nil }
+func (db *DatabaseMock) PruneCronRuns(cronID string, keep int) error { return nil }
 
 // LogDatabase interface  
 func (db *DatabaseMock) AddLog(processID string, colonyName string, executorName string, timestamp int64, msg string) error { return nil }
+func (db *DatabaseMock) AddStructuredLog(log *core.Log) error { return nil }
+func (db *DatabaseMock) GetLogs(filter *core.LogFilter) ([]*core.Log, error) { return nil, nil }
 func (db *DatabaseMock) GetLogsByProcessID(processID string, limit int) ([]*core.Log, error) { return nil, nil }
 func (db *DatabaseMock) GetLogsByProcessIDSince(processID string, limit int, since int64) ([]*core.Log, error) { return nil, nil }
 func (db *DatabaseMock) GetLogsByExecutor(executorName string, limit int) ([]*core.Log, error) { return nil, nil }
@@ -535,6 +611,8 @@ func (db *DatabaseMock) AddFile(file *core.File) error { return nil }
 func (db *DatabaseMock) GetFileByID(colonyName string, fileID string) (*core.File, error) { return nil, nil }
 func (db *DatabaseMock) GetLatestFileByName(colonyName string, label string, name string) ([]*core.File, error) { return nil, nil }
 func (db *DatabaseMock) GetFileByName(colonyName string, label string, name string) ([]*core.File, error) { return nil, nil }
+func (db *DatabaseMock) GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error) { return nil, nil }
+func (db *DatabaseMock) GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error) { return nil, nil }
 func (db *DatabaseMock) GetFilenamesByLabel(colonyName string, label string) ([]string, error) { return nil, nil }
 func (db *DatabaseMock) GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error) { return nil, nil }
 func (db *DatabaseMock) GetFileLabels(colonyName string) ([]*core.Label, error) { return nil, nil }
@@ -543,6 +621,9 @@ func (db *DatabaseMock) GetFilesByColonyName(colonyName string) ([]*core.File, e
 func (db *DatabaseMock) GetFilesByProcessGraphID(processGraphID string) ([]*core.File, error) { return nil, nil }
 func (db *DatabaseMock) GetFiles() ([]*core.File, error) { return nil, nil }
 func (db *DatabaseMock) UpdateFile(file *core.File) error { return nil }
+func (db *DatabaseMock) UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string) error {
+	return nil
+}
 func (db *DatabaseMock) RemoveFileByID(colonyName string, fileID string) error { return nil }
 func (db *DatabaseMock) RemoveFileByName(colonyName string, label string, name string) error { return nil }
 func (db *DatabaseMock) CountFiles(colonyName string) (int, error) { return 0, nil }
@@ -557,6 +638,9 @@ func (db *DatabaseMock) RemoveSnapshotByID(colonyName string, snapshotID string)
 func (db *DatabaseMock) GetSnapshotByName(colonyName string, name string) (*core.Snapshot, error) { return nil, nil }
 func (db *DatabaseMock) RemoveSnapshotByName(colonyName string, name string) error { return nil }
 func (db *DatabaseMock) RemoveSnapshotsByColonyName(colonyName string) error { return nil }
+func (db *DatabaseMock) DiffSnapshots(colonyName string, fromSnapshotID string, toSnapshotID string) (*core.SnapshotDiff, error) { return nil, nil }
+func (db *DatabaseMock) RestoreSnapshot(colonyName string, snapshotID string, prune bool) (*core.SnapshotDiff, error) { return nil, nil }
+func (db *DatabaseMock) CloneSnapshot(colonyName string, snapshotID string, label string) (*core.SnapshotDiff, error) { return nil, nil }
 func (db *DatabaseMock) CountSnapshots() (int, error) { return 0, nil }
 
 
@@ -590,16 +674,21 @@ func (db *DatabaseMock) GetBlueprintsByKind(kind string) ([]*core.Blueprint, err
 func (db *DatabaseMock) GetBlueprintsByNamespaceAndKind(namespace, kind string) ([]*core.Blueprint, error) { return nil, nil }
 func (db *DatabaseMock) GetBlueprintsByNamespaceKindAndLocation(namespace, kind, locationName string) ([]*core.Blueprint, error) { return nil, nil }
 func (db *DatabaseMock) UpdateBlueprint(blueprint *core.Blueprint) error { return nil }
+func (db *DatabaseMock) UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64) error { return nil }
 func (db *DatabaseMock) UpdateBlueprintStatus(id string, status map[string]interface{}) error { return nil }
+func (db *DatabaseMock) UpdateBlueprintStatusWithResourceVersion(id string, status map[string]interface{}, resourceVersion int64) error { return nil }
 func (db *DatabaseMock) RemoveBlueprintByID(id string) error { return nil }
 func (db *DatabaseMock) RemoveBlueprintByName(namespace, name string) error { return nil }
+func (db *DatabaseMock) RemoveBlueprintByNameWithResourceVersion(namespace, name string, resourceVersion int64) error { return nil }
 func (db *DatabaseMock) RemoveBlueprintsByNamespace(namespace string) error { return nil }
+func (db *DatabaseMock) ApplyBlueprints(creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error { return nil }
 func (db *DatabaseMock) CountBlueprints() (int, error) { return 0, nil }
 func (db *DatabaseMock) CountBlueprintsByNamespace(namespace string) (int, error) { return 0, nil }
 func (db *DatabaseMock) AddBlueprintHistory(history *core.BlueprintHistory) error { return nil }
 func (db *DatabaseMock) GetBlueprintHistory(blueprintID string, limit int) ([]*core.BlueprintHistory, error) { return nil, nil }
 func (db *DatabaseMock) GetBlueprintHistoryByGeneration(blueprintID string, generation int64) (*core.BlueprintHistory, error) { return nil, nil }
 func (db *DatabaseMock) RemoveBlueprintHistory(blueprintID string) error { return nil }
+func (db *DatabaseMock) PruneBlueprintHistory(blueprintID string, keep int) error { return nil }
 
 // Implement the database.Database interface
 func (db *DatabaseMock) CreateTables() error { return nil }
@@ -45,6 +45,11 @@ func NewGinManagedServer(config *ServerConfig, sharedResources *SharedResources)
 		config.StaleExecutorDuration,
 	)
 	
+	// Let other backends, e.g. grpc, share the handler registry of this server
+	if sharedResources.BaseServer == nil {
+		sharedResources.BaseServer = server
+	}
+
 	return &GinManagedServer{
 		server: server,
 		config: config,
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/rpc"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPCManagedServer serves the Colonies RPC API over gRPC. Requests are dispatched to the
+// handler registry of a base server, so the gin and grpc backends share handlers,
+// controller and database.
+type GRPCManagedServer struct {
+	server  *Server
+	backend *grpcbackend.Server
+	config  *ServerConfig
+	mu      sync.RWMutex
+	running bool
+}
+
+// NewGRPCManagedServer creates a new grpc managed server
+func NewGRPCManagedServer(config *ServerConfig, sharedResources *SharedResources) (*GRPCManagedServer, error) {
+	if config.BackendType != GRPCBackendType {
+		return nil, fmt.Errorf("invalid backend type for grpc server: %s", config.BackendType)
+	}
+
+	if sharedResources.BaseServer == nil {
+		return nil, errors.New("grpc server requires a base server, enable the gin backend")
+	}
+
+	gms := &GRPCManagedServer{
+		server: sharedResources.BaseServer,
+		config: config,
+	}
+
+	backend, err := grpcbackend.NewServer(config.Port, gms, config.TLS, config.TLSCertPath, config.TLSPrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	gms.backend = backend
+
+	return gms, nil
+}
+
+// Start starts the grpc server
+func (gms *GRPCManagedServer) Start() error {
+	gms.mu.Lock()
+	defer gms.mu.Unlock()
+
+	if gms.running {
+		return errors.New("grpc server is already running")
+	}
+
+	gms.running = true
+
+	go func() {
+		log.WithFields(log.Fields{
+			"BackendType": GRPCBackendType,
+			"Port":        gms.config.Port,
+			"TLS":         gms.config.TLS,
+		}).Info("Starting gRPC server")
+
+		err := gms.backend.ListenAndServe()
+
+		gms.mu.Lock()
+		gms.running = false
+		gms.mu.Unlock()
+
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.WithFields(log.Fields{
+				"BackendType": GRPCBackendType,
+				"Error":       err,
+			}).Error("gRPC server stopped with error")
+		} else {
+			log.WithField("BackendType", GRPCBackendType).Info("gRPC server stopped")
+		}
+	}()
+
+	// Wait a moment to ensure server started
+	time.Sleep(100 * time.Millisecond)
+
+	return nil
+}
+
+// Stop stops the grpc server gracefully, the base server is stopped by its own backend
+func (gms *GRPCManagedServer) Stop(ctx context.Context) error {
+	gms.mu.RLock()
+	if !gms.running {
+		gms.mu.RUnlock()
+		return nil
+	}
+	gms.mu.RUnlock()
+
+	log.WithField("BackendType", GRPCBackendType).Info("Stopping gRPC server")
+
+	if err := gms.backend.Shutdown(ctx); err != nil {
+		return fmt.Errorf("grpc server shutdown timed out: %w", err)
+	}
+
+	return nil
+}
+
+// GetBackendType returns the backend type
+func (gms *GRPCManagedServer) GetBackendType() BackendType {
+	return GRPCBackendType
+}
+
+// GetPort returns the server port
+func (gms *GRPCManagedServer) GetPort() int {
+	return gms.config.Port
+}
+
+// GetAddr returns the server address
+func (gms *GRPCManagedServer) GetAddr() string {
+	return gms.backend.GetAddr()
+}
+
+// IsRunning returns whether the server is running
+func (gms *GRPCManagedServer) IsRunning() bool {
+	gms.mu.RLock()
+	defer gms.mu.RUnlock()
+	return gms.running
+}
+
+// HealthCheck performs a health check on the server
+func (gms *GRPCManagedServer) HealthCheck() error {
+	if !gms.IsRunning() {
+		return errors.New("grpc server is not running")
+	}
+
+	creds := insecure.NewCredentials()
+	if gms.config.TLS {
+		// The certificate of the local server is not verified, only liveness is checked
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+	}
+
+	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", gms.config.Port),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(grpcbackend.CodecName)))
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = conn.Invoke(ctx, grpcbackend.HealthMethod, &grpcbackend.Envelope{}, &grpcbackend.Envelope{})
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetServer returns the base server
+func (gms *GRPCManagedServer) GetServer() *Server {
+	return gms.server
+}
+
+// SendMessage implements grpcbackend.ColoniesServer
+func (gms *GRPCManagedServer) SendMessage(ctx context.Context, request *grpcbackend.Envelope) (*grpcbackend.Envelope, error) {
+	c := grpcbackend.NewContext(ctx, request.Data)
+	gms.server.handleAPIRequest(c)
+
+	return &grpcbackend.Envelope{Data: c.Response()}, nil
+}
+
+// Health implements grpcbackend.ColoniesServer
+func (gms *GRPCManagedServer) Health(ctx context.Context, request *grpcbackend.Envelope) (*grpcbackend.Envelope, error) {
+	return &grpcbackend.Envelope{}, nil
+}
+
+// Subscribe implements grpcbackend.ColoniesServer, replies are streamed as RPCReplyMsgs
+// until the subscription times out or the client cancels the stream
+func (gms *GRPCManagedServer) Subscribe(request *grpcbackend.Envelope, stream grpcbackend.SubscribeStream) error {
+	conn := grpcbackend.NewStreamConnection(stream)
+	defer conn.Close()
+	go conn.CloseOnCancel()
+
+	rpcMsg, err := rpc.CreateRPCMsgFromJSON(string(request.Data))
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusBadRequest, conn)
+		return nil
+	}
+
+	recoveredID, err := gms.server.parseSignature(rpcMsg.Payload, rpcMsg.Signature)
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusForbidden, conn)
+		return nil
+	}
+
+	switch rpcMsg.PayloadType {
+	case rpc.SubscribeProcessesPayloadType:
+		gms.subscribeProcesses(rpcMsg, recoveredID, conn)
+	case rpc.SubscribeProcessPayloadType:
+		gms.subscribeProcess(rpcMsg, recoveredID, conn)
+	case rpc.SubscribeChannelPayloadType:
+		gms.subscribeChannel(rpcMsg, recoveredID, conn)
+	case rpc.SubscribeLogsPayloadType:
+		gms.subscribeLogs(rpcMsg, recoveredID, conn)
+	default:
+		gms.sendStreamErrorMsg(errors.New("invalid rpcMsg.PayloadType for subscription: "+rpcMsg.PayloadType), http.StatusBadRequest, conn)
+	}
+
+	return nil
+}
+
+// GRPCBackendFactory creates grpc managed servers
+type GRPCBackendFactory struct{}
+
+// NewGRPCBackendFactory creates a new grpc backend factory
+func NewGRPCBackendFactory() *GRPCBackendFactory {
+	return &GRPCBackendFactory{}
+}
+
+// CreateServer creates a new grpc managed server
+func (gbf *GRPCBackendFactory) CreateServer(config *ServerConfig, sharedResources *SharedResources) (ManagedServer, error) {
+	return NewGRPCManagedServer(config, sharedResources)
+}
+
+// GetBackendType returns the backend type this factory creates
+func (gbf *GRPCBackendFactory) GetBackendType() BackendType {
+	return GRPCBackendType
+}
+
+var _ grpcbackend.ColoniesServer = (*GRPCManagedServer)(nil)
+var _ ManagedServer = (*GRPCManagedServer)(nil)
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/client"
+	"github.com/colonyos/colonies/pkg/client/backends"
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security/crypto"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGRPCPort = constants.TESTPORT + 2
+
+func startGRPCServer(t *testing.T, server *Server) (*GRPCManagedServer, *client.ColoniesClient) {
+	config := &ServerConfig{BackendType: GRPCBackendType, Port: testGRPCPort, Enabled: true}
+	grpcServer, err := NewGRPCBackendFactory().CreateServer(config, &SharedResources{BaseServer: server})
+	assert.Nil(t, err)
+	assert.Nil(t, grpcServer.Start())
+
+	grpcClient := client.CreateColoniesClientWithConfig(&backends.ClientConfig{
+		BackendType: backends.GRPCClientBackendType,
+		Host:        constants.TESTHOST,
+		Port:        testGRPCPort,
+		Insecure:    true,
+	})
+
+	return grpcServer.(*GRPCManagedServer), grpcClient
+}
+
+func TestGRPCManagedServerRequiresBaseServer(t *testing.T) {
+	config := &ServerConfig{BackendType: GRPCBackendType, Port: testGRPCPort, Enabled: true}
+	_, err := NewGRPCManagedServer(config, &SharedResources{})
+	assert.NotNil(t, err)
+
+	config.BackendType = GinBackendType
+	_, err = NewGRPCManagedServer(config, &SharedResources{})
+	assert.NotNil(t, err)
+}
+
+type subscribeStreamMock struct {
+	sent []*grpcbackend.Envelope
+}
+
+func (stream *subscribeStreamMock) Send(envelope *grpcbackend.Envelope) error {
+	stream.sent = append(stream.sent, envelope)
+	return nil
+}
+
+func (stream *subscribeStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func TestGRPCSubscribeInvalidMsg(t *testing.T) {
+	gms := &GRPCManagedServer{server: &Server{crypto: crypto.CreateCrypto()}}
+
+	stream := &subscribeStreamMock{}
+	assert.Nil(t, gms.Subscribe(&grpcbackend.Envelope{Data: []byte("invalid")}, stream))
+	assert.Len(t, stream.sent, 1)
+	rpcReplyMsg, err := rpc.CreateRPCReplyMsgFromJSON(string(stream.sent[0].Data))
+	assert.Nil(t, err)
+	assert.True(t, rpcReplyMsg.Error)
+
+	prvKey, err := crypto.CreateCrypto().GeneratePrivateKey()
+	assert.Nil(t, err)
+	rpcMsg, err := rpc.CreateRPCMsg(rpc.VersionPayloadType, "{}", prvKey)
+	assert.Nil(t, err)
+	jsonString, err := rpcMsg.ToJSON()
+	assert.Nil(t, err)
+
+	stream = &subscribeStreamMock{}
+	assert.Nil(t, gms.Subscribe(&grpcbackend.Envelope{Data: []byte(jsonString)}, stream))
+	assert.Len(t, stream.sent, 1)
+	rpcReplyMsg, err = rpc.CreateRPCReplyMsgFromJSON(string(stream.sent[0].Data))
+	assert.Nil(t, err)
+	assert.True(t, rpcReplyMsg.Error)
+	failure, err := core.ConvertJSONToFailure(rpcReplyMsg.DecodePayload())
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, failure.Status)
+}
+
+func TestGRPCSendMessage(t *testing.T) {
+	env, httpClient, server, _, done := setupTestEnv2(t)
+
+	grpcServer, grpcClient := startGRPCServer(t, server)
+	assert.Nil(t, grpcServer.HealthCheck())
+	assert.Nil(t, grpcClient.CheckHealth())
+
+	colony, err := grpcClient.GetColonyByName(env.colonyName, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, env.colonyID, colony.ID)
+
+	// A process submitted over gRPC is visible over HTTP, the handlers are shared
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	addedProcess, err := grpcClient.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	process, err := httpClient.GetProcess(addedProcess.ID, env.executorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, addedProcess.ID, process.ID)
+
+	// Errors are returned as Colonies errors
+	_, err = grpcClient.GetColonyByName(env.colonyName, core.GenerateRandomID())
+	assert.NotNil(t, err)
+
+	grpcClient.CloseClient()
+	assert.Nil(t, grpcServer.Stop(context.Background()))
+	server.Shutdown()
+	<-done
+}
+
+func TestGRPCSubscribeProcesses(t *testing.T) {
+	env, httpClient, server, _, done := setupTestEnv2(t)
+
+	grpcServer, grpcClient := startGRPCServer(t, server)
+
+	subscription, err := grpcClient.SubscribeProcesses(env.colonyName, "test_executor_type", core.WAITING, 100, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	waitForProcess := make(chan error)
+	go func() {
+		select {
+		case <-subscription.ProcessChan:
+			waitForProcess <- nil
+		case err := <-subscription.ErrChan:
+			waitForProcess <- err
+		}
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	_, err = httpClient.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = <-waitForProcess
+	assert.Nil(t, err)
+
+	subscription.Close()
+	grpcClient.CloseClient()
+	assert.Nil(t, grpcServer.Stop(context.Background()))
+	server.Shutdown()
+	<-done
+}
+
+func TestGRPCSubscribeChannel(t *testing.T) {
+	env, httpClient, server, _, done := setupTestEnv2(t)
+
+	grpcServer, grpcClient := startGRPCServer(t, server)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	funcSpec.Channels = []string{"chat"}
+	addedProcess, err := httpClient.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = httpClient.Assign(env.colonyName, 10, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = httpClient.ChannelAppend(addedProcess.ID, "chat", 1, 0, []byte("hello"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := grpcClient.SubscribeChannel(addedProcess.ID, "chat", 0, 2, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	// Existing entries are delivered first
+	entries := <-subscription.EntriesChan
+	assert.Len(t, entries, 1)
+	assert.Equal(t, []byte("hello"), entries[0].Payload)
+
+	err = httpClient.ChannelAppend(addedProcess.ID, "chat", 2, 0, []byte("world"), env.executorPrvKey)
+	assert.Nil(t, err)
+
+	entries = <-subscription.EntriesChan
+	assert.Len(t, entries, 1)
+	assert.Equal(t, []byte("world"), entries[0].Payload)
+
+	// An empty list is sent when the subscription times out
+	entries = <-subscription.EntriesChan
+	assert.Len(t, entries, 0)
+
+	grpcClient.CloseClient()
+	assert.Nil(t, grpcServer.Stop(context.Background()))
+	server.Shutdown()
+	<-done
+}
+
+func TestGRPCSubscribeLogs(t *testing.T) {
+	env, httpClient, server, _, done := setupTestEnv2(t)
+
+	grpcServer, grpcClient := startGRPCServer(t, server)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.colonyName)
+	_, err := httpClient.Submit(funcSpec, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := httpClient.Assign(env.colonyName, 10, "", "", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	err = httpClient.AddLog(assignedProcess.ID, "hello", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := grpcClient.SubscribeProcessLogs(env.colonyName, assignedProcess.ID, 0, 2, env.executorPrvKey)
+	assert.Nil(t, err)
+
+	// Stored logs are delivered first
+	logs := <-subscription.LogsChan
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "hello", logs[0].Message)
+
+	err = httpClient.AddLog(assignedProcess.ID, "world", env.executorPrvKey)
+	assert.Nil(t, err)
+
+	logs = <-subscription.LogsChan
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "world", logs[0].Message)
+
+	// An empty list is sent when the subscription times out
+	logs = <-subscription.LogsChan
+	assert.Len(t, logs, 0)
+
+	grpcClient.CloseClient()
+	assert.Nil(t, grpcServer.Stop(context.Background()))
+	server.Shutdown()
+	<-done
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	grpcbackend "github.com/colonyos/colonies/pkg/backends/grpc"
+	"github.com/colonyos/colonies/pkg/channel"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/logstream"
+	"github.com/colonyos/colonies/pkg/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+func (gms *GRPCManagedServer) sendStreamErrorMsg(err error, errorCode int, conn *grpcbackend.StreamConnection) {
+	rpcReplyMsg, err := gms.server.generateRPCErrorMsg(err, errorCode)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to call server.generateRPCErrorMsg()")
+		return
+	}
+
+	jsonString, err := rpcReplyMsg.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to call rpcReplyMsg.ToJSON()")
+		return
+	}
+
+	err = conn.WriteMessage(grpcbackend.TextMessage, []byte(jsonString))
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Error("Failed to write error message to gRPC stream")
+	}
+}
+
+func (gms *GRPCManagedServer) subscribeProcesses(rpcMsg *rpc.RPCMsg, recoveredID string, conn *grpcbackend.StreamConnection) {
+	msg, err := rpc.CreateSubscribeProcessesMsgFromJSON(rpcMsg.DecodePayload())
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusBadRequest, conn)
+		return
+	}
+
+	if msg.MsgType != rpcMsg.PayloadType {
+		gms.sendStreamErrorMsg(errors.New("Failed to subscribe to processes, msg.msgType does not match rpcMsg.PayloadType"), http.StatusForbidden, conn)
+		return
+	}
+
+	subscription := &backends.RealtimeSubscription{
+		Connection:   conn,
+		MsgType:      grpcbackend.TextMessage,
+		Timeout:      msg.Timeout,
+		ExecutorType: msg.ExecutorType,
+		State:        msg.State,
+	}
+
+	err = gms.server.WSController().SubscribeProcesses(recoveredID, subscription)
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusForbidden, conn)
+		return
+	}
+
+	// The subscription closes the connection when it times out
+	<-conn.Done()
+}
+
+func (gms *GRPCManagedServer) subscribeProcess(rpcMsg *rpc.RPCMsg, recoveredID string, conn *grpcbackend.StreamConnection) {
+	msg, err := rpc.CreateSubscribeProcessMsgFromJSON(rpcMsg.DecodePayload())
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusBadRequest, conn)
+		return
+	}
+
+	if msg.MsgType != rpcMsg.PayloadType {
+		gms.sendStreamErrorMsg(errors.New("Failed to subscribe to process, msg.msgType does not match rpcMsg.PayloadType"), http.StatusForbidden, conn)
+		return
+	}
+
+	subscription := &backends.RealtimeSubscription{
+		Connection:   conn,
+		MsgType:      grpcbackend.TextMessage,
+		Timeout:      msg.Timeout,
+		ExecutorType: msg.ExecutorType,
+		State:        msg.State,
+		ProcessID:    msg.ProcessID,
+	}
+
+	err = gms.server.WSController().SubscribeProcess(recoveredID, subscription)
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusForbidden, conn)
+		return
+	}
+
+	<-conn.Done()
+}
+
+func (gms *GRPCManagedServer) subscribeChannel(rpcMsg *rpc.RPCMsg, recoveredID string, conn *grpcbackend.StreamConnection) {
+	msg, err := rpc.CreateSubscribeChannelMsgFromJSON(rpcMsg.DecodePayload())
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusBadRequest, conn)
+		return
+	}
+
+	if msg.MsgType != rpcMsg.PayloadType {
+		gms.sendStreamErrorMsg(errors.New("Failed to subscribe to channel, msg.MsgType does not match rpcMsg.PayloadType"), http.StatusBadRequest, conn)
+		return
+	}
+
+	process, err := gms.server.processDB.GetProcessByID(msg.ProcessID)
+	if err != nil || process == nil {
+		gms.sendStreamErrorMsg(errors.New("Process not found"), http.StatusNotFound, conn)
+		return
+	}
+
+	err = gms.server.validator.RequireMembership(recoveredID, process.FunctionSpec.Conditions.ColonyName, true)
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusForbidden, conn)
+		return
+	}
+
+	router := gms.server.channelRouter
+	ch, err := router.GetByProcessAndName(msg.ProcessID, msg.Name)
+	if err != nil {
+		if !errors.Is(err, channel.ErrChannelNotFound) {
+			gms.sendStreamErrorMsg(err, http.StatusInternalServerError, conn)
+			return
+		}
+
+		// The channel may not have replicated to this server yet
+		ch, err = ensureChannelExists(router, process, msg.Name)
+		if err != nil {
+			gms.sendStreamErrorMsg(errors.New("Channel not found"), http.StatusNotFound, conn)
+			return
+		}
+	}
+
+	entryChan, err := router.Subscribe(ch.ID, recoveredID)
+	if err != nil {
+		if err == channel.ErrUnauthorized {
+			gms.sendStreamErrorMsg(errors.New("Not authorized to subscribe to channel"), http.StatusForbidden, conn)
+		} else {
+			gms.sendStreamErrorMsg(err, http.StatusInternalServerError, conn)
+		}
+		return
+	}
+	defer router.Unsubscribe(ch.ID, entryChan)
+
+	existingEntries, err := router.ReadAfter(ch.ID, recoveredID, msg.AfterSeq, 0)
+	if err == nil && len(existingEntries) > 0 {
+		if err := sendChannelEntries(existingEntries, conn); err != nil {
+			log.WithFields(log.Fields{"Error": err}).Error("Failed to send existing channel entries to gRPC stream")
+			return
+		}
+	}
+
+	timeout := time.Duration(msg.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case entry, ok := <-entryChan:
+			if !ok {
+				return
+			}
+
+			if err := sendChannelEntries([]*channel.MsgEntry{entry}, conn); err != nil {
+				log.WithFields(log.Fields{"Error": err}).Error("Failed to send channel entry to gRPC stream")
+				return
+			}
+		case <-timer.C:
+			// An empty list tells the client that the subscription timed out
+			sendChannelEntries([]*channel.MsgEntry{}, conn)
+			return
+		case <-conn.Done():
+			return
+		}
+	}
+}
+
+func (gms *GRPCManagedServer) subscribeLogs(rpcMsg *rpc.RPCMsg, recoveredID string, conn *grpcbackend.StreamConnection) {
+	msg, err := rpc.CreateSubscribeLogsMsgFromJSON(rpcMsg.DecodePayload())
+	if err != nil {
+		gms.sendStreamErrorMsg(err, http.StatusBadRequest, conn)
+		return
+	}
+
+	if msg.MsgType != rpcMsg.PayloadType {
+		gms.sendStreamErrorMsg(errors.New("Failed to subscribe to logs, msg.MsgType does not match rpcMsg.PayloadType"), http.StatusBadRequest, conn)
+		return
+	}
+
+	filter, errorCode, err := logstream.CreateFilter(msg, recoveredID, gms.server.validator, gms.server.processDB, gms.server.executorDB, gms.server.processGraphDB)
+	if err != nil {
+		gms.sendStreamErrorMsg(err, errorCode, conn)
+		return
+	}
+
+	err = gms.server.logBroker.Follow(gms.server.logDB, filter, time.Duration(msg.Timeout)*time.Second, conn.Done(), func(logs []*core.Log) error {
+		return sendLogs(logs, conn)
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err}).Debug("gRPC log subscription ended")
+	}
+}
+
+// ensureChannelExists creates a channel on demand if it is defined in the process spec
+// but does not exist locally, see gin.RealtimeHandler for details
+func ensureChannelExists(router *channel.Router, process *core.Process, channelName string) (*channel.Channel, error) {
+	if process.State == core.SUCCESS || process.State == core.FAILED {
+		return nil, channel.ErrChannelNotFound
+	}
+
+	channelDefined := false
+	for _, name := range process.FunctionSpec.Channels {
+		if name == channelName {
+			channelDefined = true
+			break
+		}
+	}
+
+	if !channelDefined {
+		return nil, channel.ErrChannelNotFound
+	}
+
+	ch := &channel.Channel{
+		ID:          process.ID + "_" + channelName,
+		ProcessID:   process.ID,
+		Name:        channelName,
+		SubmitterID: process.InitiatorID,
+		ExecutorID:  process.AssignedExecutorID,
+	}
+
+	if err := router.CreateIfNotExists(ch); err != nil {
+		return nil, err
+	}
+
+	return router.GetByProcessAndName(process.ID, channelName)
+}
+
+func sendChannelEntries(entries []*channel.MsgEntry, conn *grpcbackend.StreamConnection) error {
+	jsonBytes, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	replyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeChannelPayloadType, string(jsonBytes))
+	if err != nil {
+		return err
+	}
+
+	jsonString, err := replyMsg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(grpcbackend.TextMessage, []byte(jsonString))
+}
+
+func sendLogs(logs []*core.Log, conn *grpcbackend.StreamConnection) error {
+	jsonString, err := core.ConvertLogArrayToJSON(logs)
+	if err != nil {
+		return err
+	}
+
+	replyMsg, err := rpc.CreateRPCReplyMsg(rpc.SubscribeLogsPayloadType, jsonString)
+	if err != nil {
+		return err
+	}
+
+	jsonString, err = replyMsg.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(grpcbackend.TextMessage, []byte(jsonString))
+}
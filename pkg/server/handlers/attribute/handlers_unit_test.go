@@ -171,6 +171,7 @@ func (m *MockProcessDB) SelectAndAssign(colonyName string, executorID string, ex
 	return nil, nil
 }
 func (m *MockProcessDB) Unassign(process *core.Process) error { return nil }
+func (m *MockProcessDB) Release(process *core.Process) error { return nil }
 func (m *MockProcessDB) MarkSuccessful(processID string) (float64, float64, error) {
 	return 0, 0, nil
 }
@@ -0,0 +1,286 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+// applyChanges holds the blueprints written by an apply, computed from a core.BlueprintApplyPlan
+type applyChanges struct {
+	creates     []*core.Blueprint
+	updates     []*core.Blueprint
+	removes     []*core.Blueprint // Pruned blueprints without finalizers
+	marked      []*core.Blueprint // Pruned blueprints with finalizers, updated with a deletion timestamp
+	specChanged map[string]bool
+	sds         map[string]*core.BlueprintDefinition
+}
+
+// HandleApplyBlueprints makes the blueprints in a colony match a set of desired blueprints. All
+// blueprints are validated and the plan is computed before anything is written, and all writes are made
+// in a single database transaction, so either every blueprint is applied or none is. Reconciliation and
+// cleanup processes are submitted once the transaction has been committed.
+func (h *Handlers) HandleApplyBlueprints(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateApplyBlueprintsMsgFromJSON(jsonString)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to apply blueprints, invalid JSON"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to apply blueprints, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	// Require membership or colony owner to apply blueprints
+	err = h.server.Validator().RequireMembership(recoveredID, msg.Namespace, true)
+	if err != nil {
+		// If not a member, check if colony owner
+		err = h.server.Validator().RequireColonyOwner(recoveredID, msg.Namespace)
+		if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+			return
+		}
+	}
+
+	if msg.Prune && msg.ManagedBy == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to apply blueprints, prune requires managedby"), http.StatusBadRequest)
+		return
+	}
+
+	plan, changes, status, err := h.planApply(msg)
+	if h.server.HandleHTTPError(c, err, status) {
+		return
+	}
+
+	if !msg.DryRun && plan.HasChanges() {
+		status, err = h.executeApply(changes, recoveredID)
+		if h.server.HandleHTTPError(c, err, status) {
+			return
+		}
+		plan.Applied = true
+	}
+
+	log.WithFields(log.Fields{
+		"Namespace": msg.Namespace,
+		"Create":    plan.Count(core.ApplyCreate),
+		"Update":    plan.Count(core.ApplyUpdate),
+		"Delete":    plan.Count(core.ApplyDelete),
+		"DryRun":    msg.DryRun,
+	}).Debug("Applying blueprints")
+
+	jsonString, err = plan.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// planApply validates the desired blueprints, merges them with the stored ones and computes the plan
+func (h *Handlers) planApply(msg *rpc.ApplyBlueprintsMsg) (*core.BlueprintApplyPlan, *applyChanges, int, error) {
+	sds, err := h.server.BlueprintDB().GetBlueprintDefinitionsByNamespace(msg.Namespace)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	changes := &applyChanges{specChanged: make(map[string]bool), sds: make(map[string]*core.BlueprintDefinition)}
+	for _, sd := range sds {
+		changes.sds[sd.Spec.Names.Kind] = sd
+	}
+
+	current, err := h.server.BlueprintDB().GetBlueprintsByNamespace(msg.Namespace)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	currentByName := make(map[string]*core.Blueprint)
+	for _, blueprint := range current {
+		currentByName[blueprint.Metadata.Name] = blueprint
+	}
+
+	var desired []*core.Blueprint
+	seen := make(map[string]bool)
+	for _, blueprint := range msg.Blueprints {
+		if blueprint == nil {
+			return nil, nil, http.StatusBadRequest, errors.New("blueprint is nil")
+		}
+
+		if blueprint.Metadata.ColonyName == "" {
+			blueprint.Metadata.ColonyName = msg.Namespace
+		}
+		if err := blueprint.Validate(); err != nil {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("invalid blueprint '%s': %v", blueprint.Metadata.Name, err)
+		}
+		if blueprint.Metadata.ColonyName != msg.Namespace {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' belongs to colony '%s', not '%s'", blueprint.Metadata.Name, blueprint.Metadata.ColonyName, msg.Namespace)
+		}
+		if seen[blueprint.Metadata.Name] {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' is defined more than once", blueprint.Metadata.Name)
+		}
+		seen[blueprint.Metadata.Name] = true
+
+		sd := changes.sds[blueprint.Kind]
+		if sd == nil {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("BlueprintDefinition for kind '%s' not found in namespace '%s'", blueprint.Kind, msg.Namespace)
+		}
+
+		prepared, err := prepareBlueprint(blueprint, sd)
+		if err != nil {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' validation failed: %v", blueprint.Metadata.Name, err)
+		}
+
+		if msg.ManagedBy != "" {
+			if prepared.Metadata.Labels == nil {
+				prepared.Metadata.Labels = make(map[string]string)
+			}
+			prepared.Metadata.Labels[core.ManagedByLabel] = msg.ManagedBy
+		}
+		prepared.Metadata.DeletionTimestamp = time.Time{}
+
+		existing := currentByName[prepared.Metadata.Name]
+		if existing == nil {
+			if prepared.ID == "" {
+				prepared.ID = core.GenerateRandomID()
+			}
+			if prepared.Metadata.Generation == 0 {
+				prepared.Metadata.Generation = 1
+			}
+			if prepared.Metadata.CreatedAt.IsZero() {
+				prepared.Metadata.CreatedAt = time.Now()
+			}
+		} else {
+			if existing.Kind != prepared.Kind {
+				return nil, nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' already exists with kind '%s'", existing.Metadata.Name, existing.Kind)
+			}
+			if existing.IsBeingDeleted() {
+				return nil, nil, http.StatusConflict, fmt.Errorf("blueprint '%s' is being deleted", existing.Metadata.Name)
+			}
+
+			// Fields maintained by the server and reconcilers are kept
+			prepared.ID = existing.ID
+			prepared.Status = existing.Status
+			prepared.Metadata.Generation = existing.Metadata.Generation
+			prepared.Metadata.ResourceVersion = existing.Metadata.ResourceVersion
+			prepared.Metadata.CreatedAt = existing.Metadata.CreatedAt
+			prepared.Metadata.LastReconciliationProcess = existing.Metadata.LastReconciliationProcess
+			prepared.Metadata.LastReconciliationTime = existing.Metadata.LastReconciliationTime
+			if len(prepared.Metadata.Finalizers) == 0 {
+				prepared.Metadata.Finalizers = existing.Metadata.Finalizers
+			}
+		}
+
+		desired = append(desired, prepared)
+	}
+
+	for _, blueprint := range desired {
+		if err := h.resolveOwnerReferences(blueprint, desired...); err != nil {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' has an invalid owner reference: %v", blueprint.Metadata.Name, err)
+		}
+	}
+
+	plan := core.PlanBlueprintApply(msg.Namespace, desired, current, msg.Prune, msg.ManagedBy)
+
+	desiredByName := make(map[string]*core.Blueprint)
+	for _, blueprint := range desired {
+		desiredByName[blueprint.Metadata.Name] = blueprint
+	}
+
+	for _, step := range plan.Steps {
+		switch step.Action {
+		case core.ApplyCreate:
+			changes.creates = append(changes.creates, desiredByName[step.Name])
+		case core.ApplyUpdate:
+			blueprint := desiredByName[step.Name]
+			if len(step.Diff.SpecChanges) > 0 {
+				blueprint.Metadata.Generation++
+				changes.specChanged[step.Name] = true
+			}
+			changes.updates = append(changes.updates, blueprint)
+		case core.ApplyDelete:
+			blueprint := currentByName[step.Name]
+			if len(blueprint.Metadata.Finalizers) > 0 {
+				marked := *blueprint
+				marked.Metadata.DeletionTimestamp = time.Now()
+				changes.marked = append(changes.marked, &marked)
+			} else {
+				changes.removes = append(changes.removes, blueprint)
+			}
+		}
+	}
+
+	return plan, changes, http.StatusOK, nil
+}
+
+// executeApply writes the changes of an apply in a single transaction and then starts reconciliation
+// of created and updated blueprints, and cleanup of pruned blueprints (best-effort)
+func (h *Handlers) executeApply(changes *applyChanges, recoveredID string) (int, error) {
+	var createdLocations []*core.Blueprint
+	for _, blueprint := range changes.creates {
+		created, err := h.ensureLocation(blueprint)
+		if err != nil {
+			for _, b := range createdLocations {
+				h.removeAutoCreatedLocation(b)
+			}
+			return http.StatusInternalServerError, err
+		}
+		if created {
+			createdLocations = append(createdLocations, blueprint)
+		}
+	}
+
+	updates := append(append([]*core.Blueprint{}, changes.updates...), changes.marked...)
+	err := h.server.BlueprintDB().ApplyBlueprints(changes.creates, updates, changes.removes)
+	if err != nil {
+		for _, b := range createdLocations {
+			h.removeAutoCreatedLocation(b)
+		}
+		if errors.Is(err, core.ErrResourceVersionConflict) {
+			return http.StatusConflict, err
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	for _, blueprint := range changes.creates {
+		if err := h.addHistory(blueprint, changes.sds[blueprint.Kind], recoveredID, "create"); err != nil {
+			log.WithFields(log.Fields{"Error": err, "BlueprintID": blueprint.ID}).Error("Failed to save blueprint history")
+		}
+
+		if err := h.startReconciliation(blueprint, changes.sds[blueprint.Kind], recoveredID); err != nil {
+			log.WithFields(log.Fields{
+				"Error":         err,
+				"BlueprintName": blueprint.Metadata.Name,
+			}).Warn("Failed to start reconciliation of applied blueprint")
+		}
+	}
+
+	for _, blueprint := range changes.updates {
+		if !changes.specChanged[blueprint.Metadata.Name] {
+			continue
+		}
+
+		if err := h.addHistory(blueprint, changes.sds[blueprint.Kind], recoveredID, "update"); err != nil {
+			log.WithFields(log.Fields{"Error": err, "BlueprintID": blueprint.ID}).Error("Failed to save blueprint history")
+		}
+
+		h.reconcileUpdatedBlueprint(blueprint, changes.sds[blueprint.Kind], recoveredID)
+	}
+
+	// Blueprints owned by a marked blueprint are removed when its last finalizer is cleared
+	for _, blueprint := range changes.marked {
+		h.submitCleanupProcess(blueprint, recoveredID)
+	}
+
+	for _, blueprint := range changes.removes {
+		h.finalizeRemoval(blueprint)
+		h.submitCleanupProcess(blueprint, recoveredID)
+		h.removeDependents(blueprint, recoveredID)
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,329 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// removeBlueprint removes a blueprint and everything it owns. A blueprint with finalizers is only marked
+// with a deletion timestamp, and is removed together with the blueprints it owns when the last finalizer
+// is cleared by an update. A cleanup process is submitted to the reconciler in both cases. Unless unconditional is true the blueprint is only
+// removed or marked if resourceVersion matches the stored resource version.
+func (h *Handlers) removeBlueprint(blueprint *core.Blueprint, resourceVersion int64, unconditional bool, recoveredID string) (int, error) {
+	if blueprint.IsBeingDeleted() {
+		// Removal is already in progress, waiting for finalizers
+		return http.StatusOK, nil
+	}
+
+	if len(blueprint.Metadata.Finalizers) > 0 {
+		blueprint.Metadata.DeletionTimestamp = time.Now()
+		var err error
+		if unconditional {
+			err = h.server.BlueprintDB().UpdateBlueprint(blueprint)
+		} else {
+			err = h.server.BlueprintDB().UpdateBlueprintWithResourceVersion(blueprint, resourceVersion)
+		}
+		if errors.Is(err, core.ErrResourceVersionConflict) {
+			return http.StatusConflict, err
+		}
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		log.WithFields(log.Fields{
+			"BlueprintName": blueprint.Metadata.Name,
+			"Finalizers":    blueprint.Metadata.Finalizers,
+		}).Info("Blueprint marked for deletion, waiting for finalizers")
+	} else {
+		var err error
+		if unconditional {
+			err = h.server.BlueprintDB().RemoveBlueprintByName(blueprint.Metadata.ColonyName, blueprint.Metadata.Name)
+		} else {
+			err = h.server.BlueprintDB().RemoveBlueprintByNameWithResourceVersion(blueprint.Metadata.ColonyName, blueprint.Metadata.Name, resourceVersion)
+		}
+		if errors.Is(err, core.ErrResourceVersionConflict) {
+			return http.StatusConflict, err
+		}
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		h.finalizeRemoval(blueprint)
+		h.removeDependents(blueprint, recoveredID)
+	}
+
+	h.submitCleanupProcess(blueprint, recoveredID)
+
+	return http.StatusOK, nil
+}
+
+// completeRemoval removes a blueprint marked for deletion, and the blueprints it owns, once its last
+// finalizer has been cleared
+func (h *Handlers) completeRemoval(blueprint *core.Blueprint, recoveredID string) (int, error) {
+	err := h.server.BlueprintDB().RemoveBlueprintByNameWithResourceVersion(blueprint.Metadata.ColonyName, blueprint.Metadata.Name, blueprint.Metadata.ResourceVersion)
+	if errors.Is(err, core.ErrResourceVersionConflict) {
+		return http.StatusConflict, err
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	h.finalizeRemoval(blueprint)
+	h.removeDependents(blueprint, recoveredID)
+
+	log.WithFields(log.Fields{
+		"BlueprintName": blueprint.Metadata.Name,
+		"Kind":          blueprint.Kind,
+	}).Info("Finalizers cleared, blueprint removed")
+
+	return http.StatusOK, nil
+}
+
+// finalizeRemoval cleans up after a blueprint has been removed from the database, i.e. removes the
+// reconciliation cron if it was the last blueprint of its kind at its location, and removes executors
+// managed by the blueprint together with their functions
+func (h *Handlers) finalizeRemoval(blueprint *core.Blueprint) {
+	namespace := blueprint.Metadata.ColonyName
+
+	// Cron naming must match AddBlueprint: reconcile-{Kind}-{locationName}
+	locationName := blueprint.Metadata.LocationName
+	cronName := "reconcile-" + blueprint.Kind
+	if locationName != "" {
+		cronName = cronName + "-" + locationName
+	}
+
+	// Check if there are any remaining blueprints of this Kind at this location
+	remainingBlueprints, err := h.server.BlueprintDB().GetBlueprintsByNamespaceAndKind(namespace, blueprint.Kind)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error": err,
+			"Kind":  blueprint.Kind,
+		}).Warn("Failed to check remaining blueprints")
+		remainingBlueprints = []*core.Blueprint{} // Assume none remaining on error
+	}
+
+	// Filter to only blueprints at the same location
+	remainingAtLocation := 0
+	for _, bp := range remainingBlueprints {
+		if bp.Metadata.LocationName == locationName {
+			remainingAtLocation++
+		}
+	}
+
+	// Only remove cron if no blueprints of this Kind remain at this location
+	if remainingAtLocation == 0 {
+		existingCron, err := h.server.CronDB().GetCronByName(namespace, cronName)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"Error":    err,
+				"CronName": cronName,
+			}).Warn("Failed to get cron for deletion")
+		} else if existingCron != nil {
+			err = h.server.CronController().RemoveCron(existingCron.ID)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"Error":        err,
+					"Kind":         blueprint.Kind,
+					"CronName":     cronName,
+					"LocationName": locationName,
+				}).Warn("Failed to remove cron")
+			} else {
+				log.WithFields(log.Fields{
+					"Kind":         blueprint.Kind,
+					"CronName":     cronName,
+					"LocationName": locationName,
+				}).Info("Auto-removed cron (last blueprint of Kind at location deleted)")
+			}
+		}
+	} else {
+		log.WithFields(log.Fields{
+			"BlueprintName":       blueprint.Metadata.Name,
+			"Kind":                blueprint.Kind,
+			"LocationName":        locationName,
+			"RemainingBlueprints": remainingAtLocation,
+		}).Debug("Cron kept (other blueprints of same Kind at location exist)")
+	}
+
+	// Remove executors deployed for the blueprint
+	executors, err := h.server.ExecutorDB().GetExecutorsByBlueprintID(blueprint.ID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to lookup executors owned by blueprint")
+		return
+	}
+
+	for _, executor := range executors {
+		if err := h.removeExecutor(executor); err != nil {
+			log.WithFields(log.Fields{
+				"Error":         err,
+				"ExecutorName":  executor.Name,
+				"BlueprintName": blueprint.Metadata.Name,
+			}).Warn("Failed to remove executor owned by blueprint")
+		} else {
+			log.WithFields(log.Fields{
+				"ExecutorName":  executor.Name,
+				"BlueprintName": blueprint.Metadata.Name,
+			}).Info("Removed executor owned by blueprint")
+		}
+	}
+}
+
+// removeExecutor removes an executor the same way as remove_executor, i.e. removes the functions
+// registered by the executor before removing the executor
+func (h *Handlers) removeExecutor(executor *core.Executor) error {
+	err := h.server.FunctionDB().RemoveFunctionsByExecutorName(executor.ColonyName, executor.Name)
+	if err != nil {
+		return err
+	}
+
+	return h.server.ExecutorDB().RemoveExecutorByName(executor.ColonyName, executor.Name)
+}
+
+// submitCleanupProcess submits a cleanup process to the reconciler of a removed blueprint (best-effort)
+func (h *Handlers) submitCleanupProcess(blueprint *core.Blueprint, recoveredID string) {
+	namespace := blueprint.Metadata.ColonyName
+
+	// Look up the BlueprintDefinition by Kind to get the ExecutorType
+	blueprintDef, err := h.server.BlueprintDB().GetBlueprintDefinitionByKind(blueprint.Kind)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"Kind":          blueprint.Kind,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to lookup BlueprintDefinition for cleanup process")
+	}
+	if blueprintDef == nil {
+		log.WithFields(log.Fields{
+			"Kind":          blueprint.Kind,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("BlueprintDefinition not found - cleanup process not created")
+		return
+	}
+	if blueprintDef.Spec.Handler.ExecutorType == "" {
+		log.WithFields(log.Fields{
+			"Kind":          blueprint.Kind,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("BlueprintDefinition has no ExecutorType - cleanup process not created")
+		return
+	}
+
+	// Create cleanup function spec
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.NodeName = fmt.Sprintf("%s-cleanup", blueprintDef.Spec.Handler.ExecutorType)
+	funcSpec.Conditions.ColonyName = namespace
+	funcSpec.Conditions.ExecutorType = blueprintDef.Spec.Handler.ExecutorType
+	funcSpec.Conditions.LocationName = blueprint.Metadata.LocationName
+	funcSpec.FuncName = "cleanup"
+	funcSpec.KwArgs = map[string]interface{}{
+		"blueprintName": blueprint.Metadata.Name,
+		"kind":          blueprint.Kind,
+	}
+
+	// Resolve initiator name for the process
+	initiatorName, _ := h.resolveInitiator(namespace, recoveredID)
+
+	// Create and submit the process
+	cleanupProcess := core.CreateProcess(funcSpec)
+	cleanupProcess.InitiatorID = recoveredID
+	cleanupProcess.InitiatorName = initiatorName
+
+	_, err = h.server.ProcessController().AddProcess(cleanupProcess)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to submit cleanup process")
+	} else {
+		log.WithFields(log.Fields{
+			"BlueprintName": blueprint.Metadata.Name,
+			"ExecutorType":  blueprintDef.Spec.Handler.ExecutorType,
+		}).Info("Submitted cleanup process for deleted blueprint")
+	}
+}
+
+// removeDependents removes all blueprints that have an owner reference to the given blueprint
+func (h *Handlers) removeDependents(owner *core.Blueprint, recoveredID string) {
+	blueprints, err := h.server.BlueprintDB().GetBlueprintsByNamespace(owner.Metadata.ColonyName)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": owner.Metadata.Name,
+		}).Warn("Failed to lookup blueprints owned by blueprint")
+		return
+	}
+
+	for _, dependent := range blueprints {
+		if dependent.ID == owner.ID || !dependent.IsOwnedBy(owner) {
+			continue
+		}
+
+		if _, err := h.removeBlueprint(dependent, 0, true, recoveredID); err != nil {
+			log.WithFields(log.Fields{
+				"Error":         err,
+				"BlueprintName": dependent.Metadata.Name,
+				"Owner":         owner.Metadata.Name,
+			}).Warn("Failed to remove blueprint owned by removed blueprint")
+		} else {
+			log.WithFields(log.Fields{
+				"BlueprintName": dependent.Metadata.Name,
+				"Owner":         owner.Metadata.Name,
+			}).Info("Removed blueprint owned by removed blueprint")
+		}
+	}
+}
+
+// resolveOwnerReferences checks that the owners of a blueprint exist in its colony, and fills in the
+// kind, name and ID of each reference from the owner. Owners can also be among pending blueprints that
+// are created together with the blueprint.
+func (h *Handlers) resolveOwnerReferences(blueprint *core.Blueprint, pending ...*core.Blueprint) error {
+	for i, ref := range blueprint.Metadata.OwnerReferences {
+		var owner *core.Blueprint
+		var err error
+		for _, p := range pending {
+			if (ref.BlueprintID != "" && p.ID == ref.BlueprintID) || (ref.BlueprintID == "" && p.Metadata.Name == ref.Name) {
+				owner = p
+				break
+			}
+		}
+
+		if ref.BlueprintID == "" && ref.Name == "" {
+			return errors.New("owner reference must have a name or a blueprint ID")
+		}
+
+		if owner == nil {
+			if ref.BlueprintID != "" {
+				owner, err = h.server.BlueprintDB().GetBlueprintByID(ref.BlueprintID)
+			} else {
+				owner, err = h.server.BlueprintDB().GetBlueprintByName(blueprint.Metadata.ColonyName, ref.Name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if owner == nil || owner.Metadata.ColonyName != blueprint.Metadata.ColonyName {
+			return fmt.Errorf("owner '%s' not found in colony '%s'", ref.Name, blueprint.Metadata.ColonyName)
+		}
+		if ref.Kind != "" && ref.Kind != owner.Kind {
+			return fmt.Errorf("owner '%s' is of kind '%s', not '%s'", owner.Metadata.Name, owner.Kind, ref.Kind)
+		}
+		if owner.ID == blueprint.ID || owner.Metadata.Name == blueprint.Metadata.Name {
+			return errors.New("blueprint cannot own itself")
+		}
+
+		blueprint.Metadata.OwnerReferences[i] = core.OwnerReference{
+			Kind:        owner.Kind,
+			Name:        owner.Metadata.Name,
+			BlueprintID: owner.ID,
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
@@ -15,6 +16,10 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// maxPatchAttempts is how many times a patch is applied before giving up on a blueprint that keeps
+// being modified concurrently
+const maxPatchAttempts = 5
+
 type Server interface {
 	HandleHTTPError(c backends.Context, err error, errorCode int) bool
 	SendHTTPReply(c backends.Context, payloadType string, jsonString string)
@@ -28,9 +33,13 @@ type Server interface {
 		AddCron(cron *core.Cron) (*core.Cron, error)
 		RunCron(cronID string) (*core.Cron, error)
 		RemoveCron(cronID string) error
+		SuspendCron(cronID string) (*core.Cron, error)
+		ResumeCron(cronID string) (*core.Cron, error)
+		GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 		GetCronPeriod() int
 	}
 	ExecutorDB() database.ExecutorDatabase
+	FunctionDB() database.FunctionDatabase
 	UserDB() database.UserDatabase
 }
 
@@ -222,6 +231,12 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.RemoveBlueprintDefinitionPayloadType, h.HandleRemoveBlueprintDefinition); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.UpdateBlueprintDefinitionPayloadType, h.HandleUpdateBlueprintDefinition); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.MigrateBlueprintDefinitionPayloadType, h.HandleMigrateBlueprintDefinition); err != nil {
+		return err
+	}
 
 	// Blueprint handlers
 	if err := handlerRegistry.Register(rpc.AddBlueprintPayloadType, h.HandleAddBlueprint); err != nil {
@@ -242,6 +257,15 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.GetBlueprintHistoryPayloadType, h.HandleGetBlueprintHistory); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.PatchBlueprintPayloadType, h.HandlePatchBlueprint); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.ApplyBlueprintsPayloadType, h.HandleApplyBlueprints); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RollbackBlueprintPayloadType, h.HandleRollbackBlueprint); err != nil {
+		return err
+	}
 	if err := handlerRegistry.Register(rpc.UpdateBlueprintStatusPayloadType, h.HandleUpdateBlueprintStatus); err != nil {
 		return err
 	}
@@ -270,6 +294,11 @@ func (h *Handlers) HandleAddBlueprintDefinition(c backends.Context, recoveredID
 		return
 	}
 
+	if err := validateBlueprintDefinition(msg.BlueprintDefinition); err != nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("Failed to add blueprint definition, %v", err), http.StatusBadRequest)
+		return
+	}
+
 	// IMPORTANT: Only colony owner can add BlueprintDefinitions
 	// Namespace field holds the colony name
 	err = h.server.Validator().RequireColonyOwner(recoveredID, msg.BlueprintDefinition.Metadata.ColonyName)
@@ -510,13 +539,13 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 		return
 	}
 
-	// Validate against schema if defined
-	if matchedSD.Spec.Schema != nil {
-		if err := core.ValidateBlueprintAgainstSchema(msg.Blueprint, matchedSD.Spec.Schema); err != nil {
-			h.server.HandleHTTPError(c, fmt.Errorf("blueprint validation failed: %v", err), http.StatusBadRequest)
-			return
-		}
+	// Resolve the version, apply schema defaults, validate and convert to the storage version
+	preparedBlueprint, err := prepareBlueprint(msg.Blueprint, matchedSD)
+	if err != nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("blueprint validation failed: %v", err), http.StatusBadRequest)
+		return
 	}
+	msg.Blueprint = preparedBlueprint
 
 	// Check if blueprint with same name already exists
 	existingBlueprint, err := h.server.BlueprintDB().GetBlueprintByName(msg.Blueprint.Metadata.ColonyName, msg.Blueprint.Metadata.Name)
@@ -529,65 +558,32 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 		return
 	}
 
-	// Auto-create location if specified and doesn't exist
-	var locationWasAutoCreated bool
-	if msg.Blueprint.Metadata.LocationName != "" {
-		existingLocation, err := h.server.LocationDB().GetLocationByName(msg.Blueprint.Metadata.ColonyName, msg.Blueprint.Metadata.LocationName)
-		if err != nil {
-			h.server.HandleHTTPError(c, fmt.Errorf("failed to check location: %w", err), http.StatusInternalServerError)
-			return
-		}
+	// Deletion is requested with remove_blueprint, never on creation
+	msg.Blueprint.Metadata.DeletionTimestamp = time.Time{}
 
-		if existingLocation == nil {
-			// Create new location
-			newLocation := core.CreateLocation(
-				core.GenerateRandomID(),
-				msg.Blueprint.Metadata.LocationName,
-				msg.Blueprint.Metadata.ColonyName,
-				"Auto-created from blueprint "+msg.Blueprint.Metadata.Name,
-				0.0, // Default longitude
-				0.0, // Default latitude
-			)
-			err = h.server.LocationDB().AddLocation(newLocation)
-			if err != nil {
-				h.server.HandleHTTPError(c, fmt.Errorf("failed to create location: %w", err), http.StatusInternalServerError)
-				return
-			}
-			locationWasAutoCreated = true
+	if err := h.resolveOwnerReferences(msg.Blueprint); err != nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("invalid owner reference: %v", err), http.StatusBadRequest)
+		return
+	}
 
-			log.WithFields(log.Fields{
-				"LocationName":  msg.Blueprint.Metadata.LocationName,
-				"ColonyName":    msg.Blueprint.Metadata.ColonyName,
-				"BlueprintName": msg.Blueprint.Metadata.Name,
-			}).Info("Auto-created location for blueprint")
-		}
+	// Auto-create location if specified and doesn't exist
+	locationWasAutoCreated, err := h.ensureLocation(msg.Blueprint)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
 	}
 
 	err = h.server.BlueprintDB().AddBlueprint(msg.Blueprint)
 	if err != nil {
 		// Clean up auto-created location if blueprint creation fails
 		if locationWasAutoCreated {
-			if removeErr := h.server.LocationDB().RemoveLocationByName(msg.Blueprint.Metadata.ColonyName, msg.Blueprint.Metadata.LocationName); removeErr != nil {
-				log.WithFields(log.Fields{
-					"Error":        removeErr,
-					"LocationName": msg.Blueprint.Metadata.LocationName,
-					"ColonyName":   msg.Blueprint.Metadata.ColonyName,
-				}).Warn("Failed to cleanup auto-created location after blueprint creation failure")
-			} else {
-				log.WithFields(log.Fields{
-					"LocationName":  msg.Blueprint.Metadata.LocationName,
-					"ColonyName":    msg.Blueprint.Metadata.ColonyName,
-					"BlueprintName": msg.Blueprint.Metadata.Name,
-				}).Info("Cleaned up auto-created location after blueprint creation failure")
-			}
+			h.removeAutoCreatedLocation(msg.Blueprint)
 		}
 		h.server.HandleHTTPError(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	// Save blueprint history for create action
-	history := core.CreateBlueprintHistory(msg.Blueprint, recoveredID, "create")
-	if err := h.server.BlueprintDB().AddBlueprintHistory(history); err != nil {
+	if err := h.addHistory(msg.Blueprint, matchedSD, recoveredID, "create"); err != nil {
 		log.WithFields(log.Fields{"Error": err, "BlueprintID": msg.Blueprint.ID}).Error("Failed to save blueprint history")
 		h.server.HandleHTTPError(c, fmt.Errorf("blueprint created but failed to save audit history: %w", err), http.StatusInternalServerError)
 		return
@@ -601,34 +597,58 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 	}).Debug("Adding blueprint")
 
 	// Auto-create reconciliation cron if handler is defined in BlueprintDefinition
+	err = h.startReconciliation(msg.Blueprint, matchedSD, recoveredID)
+	if errors.Is(err, errReconciliationCron) {
+		// Rollback: remove blueprint if cron creation fails
+		h.server.BlueprintDB().RemoveBlueprintByID(msg.Blueprint.ID)
+	}
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = msg.Blueprint.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// errReconciliationCron is returned by startReconciliation when the reconciliation cron could not be
+// created
+var errReconciliationCron = errors.New("failed to create reconciliation cron")
+
+// startReconciliation makes sure there is a reconciliation cron for the kind and location of a new
+// blueprint, and submits an immediate reconciliation process for it
+func (h *Handlers) startReconciliation(blueprint *core.Blueprint, sd *core.BlueprintDefinition, recoveredID string) error {
 	executorType := ""
 	functionName := ""
-	if matchedSD != nil && matchedSD.Spec.Handler.ExecutorType != "" {
-		executorType = matchedSD.Spec.Handler.ExecutorType
-		functionName = matchedSD.Spec.Handler.FunctionName
+	if sd != nil && sd.Spec.Handler.ExecutorType != "" {
+		executorType = sd.Spec.Handler.ExecutorType
+		functionName = sd.Spec.Handler.FunctionName
 	}
 
 	if executorType != "" && functionName != "" {
 		// Use Kind + location for cron name (one cron per reconciler per location)
-		locationName := msg.Blueprint.Metadata.LocationName
-		cronName := "reconcile-" + msg.Blueprint.Kind
+		locationName := blueprint.Metadata.LocationName
+		cronName := "reconcile-" + blueprint.Kind
 		if locationName != "" {
 			cronName = cronName + "-" + locationName
 		}
 
 		// Resolve initiator name
-		initiatorName, err := h.resolveInitiator(msg.Blueprint.Metadata.ColonyName, recoveredID)
+		initiatorName, err := h.resolveInitiator(blueprint.Metadata.ColonyName, recoveredID)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"Error":         err,
 				"RecoveredID":   recoveredID,
-				"BlueprintName": msg.Blueprint.Metadata.Name,
+				"BlueprintName": blueprint.Metadata.Name,
 			}).Warn("Failed to resolve initiator name for cron")
 			initiatorName = ""
 		}
 
 		// Check if cron for this handler already exists
-		existingCron, err := h.server.CronDB().GetCronByName(msg.Blueprint.Metadata.ColonyName, cronName)
+		existingCron, err := h.server.CronDB().GetCronByName(blueprint.Metadata.ColonyName, cronName)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"Error":    err,
@@ -638,18 +658,18 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 
 		if existingCron == nil {
 			// Create workflow spec targeting handler executors by type and location
-			workflowSpec, err := h.createReconcilerCronWorkflowSpec(msg.Blueprint.Metadata.ColonyName, msg.Blueprint.Kind, executorType, functionName, locationName)
+			workflowSpec, err := h.createReconcilerCronWorkflowSpec(blueprint.Metadata.ColonyName, blueprint.Kind, executorType, functionName, locationName)
 			if err != nil {
 				log.WithFields(log.Fields{
 					"Error":        err,
-					"Kind":         msg.Blueprint.Kind,
+					"Kind":         blueprint.Kind,
 					"LocationName": locationName,
 				}).Warn("Failed to create reconciliation workflow spec")
 			} else {
 				// Create cron for periodic self-healing by reconcilers at this location
 				cron := &core.Cron{
 					ID:                      core.GenerateRandomID(),
-					ColonyName:              msg.Blueprint.Metadata.ColonyName,
+					ColonyName:              blueprint.Metadata.ColonyName,
 					Name:                    cronName,
 					Interval:                60, // 60 seconds
 					WaitForPrevProcessGraph: true,
@@ -660,14 +680,11 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 
 				addedCron, err := h.server.CronController().AddCron(cron)
 				if err != nil {
-					// Rollback: remove blueprint if cron creation fails
-					h.server.BlueprintDB().RemoveBlueprintByID(msg.Blueprint.ID)
-					h.server.HandleHTTPError(c, fmt.Errorf("failed to create reconciliation cron: %w", err), http.StatusInternalServerError)
-					return
+					return fmt.Errorf("%w: %w", errReconciliationCron, err)
 				}
 
 				log.WithFields(log.Fields{
-					"Kind":         msg.Blueprint.Kind,
+					"Kind":         blueprint.Kind,
 					"CronName":     cronName,
 					"CronID":       addedCron.ID,
 					"LocationName": locationName,
@@ -678,8 +695,8 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 			}
 		} else {
 			log.WithFields(log.Fields{
-				"BlueprintName": msg.Blueprint.Metadata.Name,
-				"Kind":          msg.Blueprint.Kind,
+				"BlueprintName": blueprint.Metadata.Name,
+				"Kind":          blueprint.Kind,
 				"CronName":      cronName,
 				"LocationName":  locationName,
 			}).Debug("Cron already exists for handler")
@@ -687,37 +704,87 @@ func (h *Handlers) HandleAddBlueprint(c backends.Context, recoveredID string, pa
 
 		// Submit immediate reconciliation process for this specific blueprint
 		if existingCron != nil {
-			immediateProcess, err := h.createImmediateReconciliationProcess(msg.Blueprint, matchedSD, recoveredID, initiatorName)
+			immediateProcess, err := h.createImmediateReconciliationProcess(blueprint, sd, recoveredID, initiatorName)
 			if err != nil {
 				log.WithFields(log.Fields{
 					"Error":         err,
-					"BlueprintName": msg.Blueprint.Metadata.Name,
+					"BlueprintName": blueprint.Metadata.Name,
 				}).Error("Failed to create immediate reconciliation process")
-				h.server.HandleHTTPError(c, fmt.Errorf("blueprint created but failed to create reconciliation process: %w", err), http.StatusInternalServerError)
-				return
+				return fmt.Errorf("blueprint created but failed to create reconciliation process: %w", err)
 			}
 			_, err = h.server.ProcessController().AddProcess(immediateProcess)
 			if err != nil {
 				log.WithFields(log.Fields{
 					"Error":         err,
-					"BlueprintName": msg.Blueprint.Metadata.Name,
+					"BlueprintName": blueprint.Metadata.Name,
 				}).Error("Failed to submit immediate reconciliation process")
-				h.server.HandleHTTPError(c, fmt.Errorf("blueprint created but failed to submit reconciliation process: %w", err), http.StatusInternalServerError)
-				return
+				return fmt.Errorf("blueprint created but failed to submit reconciliation process: %w", err)
 			}
 			log.WithFields(log.Fields{
-				"BlueprintName": msg.Blueprint.Metadata.Name,
+				"BlueprintName": blueprint.Metadata.Name,
 				"ProcessID":     immediateProcess.ID,
 			}).Info("Submitted immediate reconciliation process for new blueprint")
 		}
 	}
 
-	jsonString, err = msg.Blueprint.ToJSON()
-	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
-		return
+	return nil
+}
+
+// ensureLocation creates the location of a blueprint if it does not exist, and returns true if it
+// was created
+func (h *Handlers) ensureLocation(blueprint *core.Blueprint) (bool, error) {
+	if blueprint.Metadata.LocationName == "" {
+		return false, nil
 	}
 
-	h.server.SendHTTPReply(c, payloadType, jsonString)
+	existingLocation, err := h.server.LocationDB().GetLocationByName(blueprint.Metadata.ColonyName, blueprint.Metadata.LocationName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check location: %w", err)
+	}
+
+	if existingLocation != nil {
+		return false, nil
+	}
+
+	// Create new location
+	newLocation := core.CreateLocation(
+		core.GenerateRandomID(),
+		blueprint.Metadata.LocationName,
+		blueprint.Metadata.ColonyName,
+		"Auto-created from blueprint "+blueprint.Metadata.Name,
+		0.0, // Default longitude
+		0.0, // Default latitude
+	)
+	err = h.server.LocationDB().AddLocation(newLocation)
+	if err != nil {
+		return false, fmt.Errorf("failed to create location: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"LocationName":  blueprint.Metadata.LocationName,
+		"ColonyName":    blueprint.Metadata.ColonyName,
+		"BlueprintName": blueprint.Metadata.Name,
+	}).Info("Auto-created location for blueprint")
+
+	return true, nil
+}
+
+// removeAutoCreatedLocation removes a location created by ensureLocation when the blueprint could not
+// be created
+func (h *Handlers) removeAutoCreatedLocation(blueprint *core.Blueprint) {
+	if removeErr := h.server.LocationDB().RemoveLocationByName(blueprint.Metadata.ColonyName, blueprint.Metadata.LocationName); removeErr != nil {
+		log.WithFields(log.Fields{
+			"Error":        removeErr,
+			"LocationName": blueprint.Metadata.LocationName,
+			"ColonyName":   blueprint.Metadata.ColonyName,
+		}).Warn("Failed to cleanup auto-created location after blueprint creation failure")
+	} else {
+		log.WithFields(log.Fields{
+			"LocationName":  blueprint.Metadata.LocationName,
+			"ColonyName":    blueprint.Metadata.ColonyName,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Info("Cleaned up auto-created location after blueprint creation failure")
+	}
 }
 
 // HandleGetBlueprint retrieves a Blueprint by namespace and name
@@ -753,9 +820,20 @@ func (h *Handlers) HandleGetBlueprint(c backends.Context, recoveredID string, pa
 		return
 	}
 
+	sd, err := h.findBlueprintDefinition(msg.Namespace, blueprint.Kind)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	blueprint, err = convertForRead(blueprint, sd, msg.Version)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	log.WithFields(log.Fields{
 		"Namespace": msg.Namespace,
 		"Name":      msg.Name,
+		"Version":   blueprint.Version,
 	}).Debug("Getting blueprint")
 
 	jsonString, err = blueprint.ToJSON()
@@ -805,6 +883,21 @@ func (h *Handlers) HandleGetBlueprints(c backends.Context, recoveredID string, p
 		return
 	}
 
+	// Convert blueprints stored in older versions to the storage version
+	sds, err := h.server.BlueprintDB().GetBlueprintDefinitionsByNamespace(msg.Namespace)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+	sdsByKind := make(map[string]*core.BlueprintDefinition)
+	for _, sd := range sds {
+		sdsByKind[sd.Spec.Names.Kind] = sd
+	}
+	for i, blueprint := range blueprints {
+		if converted, err := convertForRead(blueprint, sdsByKind[blueprint.Kind], ""); err == nil {
+			blueprints[i] = converted
+		}
+	}
+
 	log.WithFields(log.Fields{
 		"Namespace":    msg.Namespace,
 		"Kind":         msg.Kind,
@@ -860,24 +953,44 @@ func (h *Handlers) HandleUpdateBlueprint(c backends.Context, recoveredID string,
 		return
 	}
 
+	updatedBlueprint, status, err := h.updateBlueprint(msg.Blueprint, oldBlueprint, msg.ForceGeneration, msg.ResourceVersion, !msg.CheckResourceVersion, recoveredID, "update")
+	if h.server.HandleHTTPError(c, err, status) {
+		return
+	}
+
+	jsonString, err = updatedBlueprint.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// updateBlueprint validates and stores a new revision of an existing blueprint, saves history and
+// triggers reconciliation if the spec changed. Unless unconditional is true the write fails with a
+// conflict if resourceVersion does not match the stored resource version. The returned status code is
+// only meaningful on error.
+func (h *Handlers) updateBlueprint(blueprint *core.Blueprint, oldBlueprint *core.Blueprint, forceGeneration bool, resourceVersion int64, unconditional bool, recoveredID string, changeType string) (*core.Blueprint, int, error) {
+	if !unconditional && resourceVersion != oldBlueprint.Metadata.ResourceVersion {
+		return nil, http.StatusConflict, resourceVersionConflict(oldBlueprint, resourceVersion)
+	}
+
 	// Validate blueprint against its BlueprintDefinition schema
 	// Blueprint Kind is required
-	if msg.Blueprint.Kind == "" {
-		h.server.HandleHTTPError(c, errors.New("blueprint kind is required"), http.StatusBadRequest)
-		return
+	if blueprint.Kind == "" {
+		return nil, http.StatusBadRequest, errors.New("blueprint kind is required")
 	}
 
 	// Fetch all BlueprintDefinitions in the namespace
-	sds, err := h.server.BlueprintDB().GetBlueprintDefinitionsByNamespace(msg.Blueprint.Metadata.ColonyName)
+	sds, err := h.server.BlueprintDB().GetBlueprintDefinitionsByNamespace(blueprint.Metadata.ColonyName)
 	if err != nil {
-		h.server.HandleHTTPError(c, err, http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Find matching BlueprintDefinition - REQUIRED
 	var matchedSD *core.BlueprintDefinition
 	for _, sd := range sds {
-		if sd.Spec.Names.Kind == msg.Blueprint.Kind {
+		if sd.Spec.Names.Kind == blueprint.Kind {
 			matchedSD = sd
 			break
 		}
@@ -885,109 +998,220 @@ func (h *Handlers) HandleUpdateBlueprint(c backends.Context, recoveredID string,
 
 	// BlueprintDefinition must exist
 	if matchedSD == nil {
-		h.server.HandleHTTPError(c, fmt.Errorf("BlueprintDefinition for kind '%s' not found in namespace '%s'", msg.Blueprint.Kind, msg.Blueprint.Metadata.ColonyName), http.StatusBadRequest)
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("BlueprintDefinition for kind '%s' not found in namespace '%s'", blueprint.Kind, blueprint.Metadata.ColonyName)
 	}
 
-	// Validate against schema if defined
-	if matchedSD.Spec.Schema != nil {
-		if err := core.ValidateBlueprintAgainstSchema(msg.Blueprint, matchedSD.Spec.Schema); err != nil {
-			h.server.HandleHTTPError(c, fmt.Errorf("blueprint validation failed: %v", err), http.StatusBadRequest)
-			return
+	// Resolve the version, apply schema defaults, validate and convert to the storage version
+	blueprint, err = prepareBlueprint(blueprint, matchedSD)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("blueprint validation failed: %v", err)
+	}
+
+	// Preserve the ID from the existing blueprint
+	blueprint.ID = oldBlueprint.ID
+
+	// Only remove_blueprint can request deletion, and once requested no finalizers can be added
+	blueprint.Metadata.DeletionTimestamp = oldBlueprint.Metadata.DeletionTimestamp
+	if oldBlueprint.IsBeingDeleted() {
+		for _, finalizer := range blueprint.Metadata.Finalizers {
+			if !oldBlueprint.HasFinalizer(finalizer) {
+				return nil, http.StatusBadRequest, fmt.Errorf("cannot add finalizer '%s' to blueprint '%s', it is being deleted", finalizer, blueprint.Metadata.Name)
+			}
 		}
 	}
 
+	if err := h.resolveOwnerReferences(blueprint); err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid owner reference: %v", err)
+	}
+
 	// Check if spec changed and increment generation if it did
 	specChanged := false
-	if oldBlueprint != nil {
-		// Preserve the ID from the existing blueprint
-		msg.Blueprint.ID = oldBlueprint.ID
-
-		reconciliation := core.CreateReconciliation(oldBlueprint, msg.Blueprint)
-		if reconciliation.Diff != nil && len(reconciliation.Diff.SpecChanges) > 0 {
-			// Spec changed, increment generation
-			msg.Blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation + 1
-			specChanged = true
-		} else if msg.ForceGeneration {
-			// Force generation bump requested (for force reconciliation)
-			msg.Blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation + 1
-			specChanged = true
-			log.WithFields(log.Fields{
-				"BlueprintName": msg.Blueprint.Metadata.Name,
-				"OldGeneration": oldBlueprint.Metadata.Generation,
-				"NewGeneration": msg.Blueprint.Metadata.Generation,
-			}).Info("Force generation bump requested")
-		} else {
-			// Preserve old generation if spec didn't change
-			msg.Blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation
-		}
+	reconciliation := core.CreateReconciliation(oldBlueprint, blueprint)
+	if reconciliation.Diff != nil && len(reconciliation.Diff.SpecChanges) > 0 {
+		// Spec changed, increment generation
+		blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation + 1
+		specChanged = true
+	} else if forceGeneration {
+		// Force generation bump requested (for force reconciliation)
+		blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation + 1
+		specChanged = true
+		log.WithFields(log.Fields{
+			"BlueprintName": blueprint.Metadata.Name,
+			"OldGeneration": oldBlueprint.Metadata.Generation,
+			"NewGeneration": blueprint.Metadata.Generation,
+		}).Info("Force generation bump requested")
+	} else {
+		// Preserve old generation if spec didn't change
+		blueprint.Metadata.Generation = oldBlueprint.Metadata.Generation
 	}
 
-	err = h.server.BlueprintDB().UpdateBlueprint(msg.Blueprint)
-	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
-		return
+	if unconditional {
+		err = h.server.BlueprintDB().UpdateBlueprint(blueprint)
+	} else {
+		err = h.server.BlueprintDB().UpdateBlueprintWithResourceVersion(blueprint, resourceVersion)
+	}
+	if errors.Is(err, core.ErrResourceVersionConflict) {
+		return nil, http.StatusConflict, err
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Save blueprint history only if spec changed (not for status-only updates)
 	if specChanged {
-		history := core.CreateBlueprintHistory(msg.Blueprint, recoveredID, "update")
-		if err := h.server.BlueprintDB().AddBlueprintHistory(history); err != nil {
-			log.WithFields(log.Fields{"Error": err, "BlueprintID": msg.Blueprint.ID}).Error("Failed to save blueprint history")
-			h.server.HandleHTTPError(c, fmt.Errorf("blueprint updated but failed to save audit history: %w", err), http.StatusInternalServerError)
-			return
+		if err := h.addHistory(blueprint, matchedSD, recoveredID, changeType); err != nil {
+			log.WithFields(log.Fields{"Error": err, "BlueprintID": blueprint.ID}).Error("Failed to save blueprint history")
+			return nil, http.StatusInternalServerError, fmt.Errorf("blueprint updated but failed to save audit history: %w", err)
 		}
 	}
 
 	log.WithFields(log.Fields{
-		"ID":        msg.Blueprint.ID,
-		"Namespace": msg.Blueprint.Metadata.ColonyName,
-		"Name":      msg.Blueprint.Metadata.Name,
-		"Generation": msg.Blueprint.Metadata.Generation,
+		"ID":              blueprint.ID,
+		"Namespace":       blueprint.Metadata.ColonyName,
+		"Name":            blueprint.Metadata.Name,
+		"Generation":      blueprint.Metadata.Generation,
+		"ResourceVersion": blueprint.Metadata.ResourceVersion,
 	}).Debug("Updating blueprint")
 
+	// A blueprint being deleted is not reconciled again, its cleanup process has already been
+	// submitted, and it is removed once the last finalizer has been cleared
+	if blueprint.IsBeingDeleted() {
+		if len(blueprint.Metadata.Finalizers) == 0 {
+			if status, err := h.completeRemoval(blueprint, recoveredID); err != nil {
+				return nil, status, err
+			}
+		}
+		return blueprint, http.StatusOK, nil
+	}
+
 	// Trigger immediate reconciliation for this specific blueprint
-	// This creates a process with blueprintName so the reconciler knows exactly which blueprint changed
 	if specChanged {
-		initiatorName, err := h.resolveInitiator(msg.Blueprint.Metadata.ColonyName, recoveredID)
+		h.reconcileUpdatedBlueprint(blueprint, matchedSD, recoveredID)
+	}
+
+	return blueprint, http.StatusOK, nil
+}
+
+// reconcileUpdatedBlueprint submits an immediate reconciliation process for an updated blueprint
+// (best-effort). The process has the blueprintName so the reconciler knows exactly which blueprint changed.
+func (h *Handlers) reconcileUpdatedBlueprint(blueprint *core.Blueprint, sd *core.BlueprintDefinition, recoveredID string) {
+	initiatorName, err := h.resolveInitiator(blueprint.Metadata.ColonyName, recoveredID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to resolve initiator for reconciliation")
+		return
+	}
+
+	immediateProcess, err := h.createImmediateReconciliationProcess(blueprint, sd, recoveredID, initiatorName)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to create immediate reconciliation process after blueprint update")
+		return
+	}
+
+	_, err = h.server.ProcessController().AddProcess(immediateProcess)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+		}).Warn("Failed to submit immediate reconciliation process after blueprint update")
+	} else {
+		log.WithFields(log.Fields{
+			"BlueprintName": blueprint.Metadata.Name,
+			"Generation":    blueprint.Metadata.Generation,
+			"ProcessID":     immediateProcess.ID,
+		}).Info("Submitted immediate reconciliation process for updated blueprint")
+	}
+}
+
+// resourceVersionConflict returns the error reported when a write is conditioned on a resource version
+// that does not match the stored blueprint
+func resourceVersionConflict(blueprint *core.Blueprint, resourceVersion int64) error {
+	return fmt.Errorf("%w: blueprint '%s' has resource version %d, expected %d", core.ErrResourceVersionConflict, blueprint.Metadata.Name, blueprint.Metadata.ResourceVersion, resourceVersion)
+}
+
+// HandlePatchBlueprint applies a merge or strategic merge patch to a blueprint on the server, so that
+// clients can change individual fields without a read-modify-write race. Without a resource version in
+// the request the patch is reapplied if the blueprint is modified concurrently.
+func (h *Handlers) HandlePatchBlueprint(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreatePatchBlueprintMsgFromJSON(jsonString)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to patch blueprint, invalid JSON"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to patch blueprint, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Patch == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to patch blueprint, patch is nil"), http.StatusBadRequest)
+		return
+	}
+
+	// Require membership or colony owner to patch blueprints
+	err = h.server.Validator().RequireMembership(recoveredID, msg.Namespace, true)
+	if err != nil {
+		// If not a member, check if colony owner
+		err = h.server.Validator().RequireColonyOwner(recoveredID, msg.Namespace)
+		if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+			return
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		oldBlueprint, err := h.server.BlueprintDB().GetBlueprintByName(msg.Namespace, msg.Name)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		if oldBlueprint == nil {
+			h.server.HandleHTTPError(c, fmt.Errorf("blueprint '%s' not found in namespace '%s'", msg.Name, msg.Namespace), http.StatusNotFound)
+			return
+		}
+
+		patchedBlueprint, err := oldBlueprint.ApplyPatch(msg.PatchType, msg.Patch)
 		if err != nil {
+			h.server.HandleHTTPError(c, fmt.Errorf("Failed to patch blueprint, %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// The blueprint must not change between reading and writing it, otherwise the patch would
+		// overwrite the concurrent change
+		resourceVersion := oldBlueprint.Metadata.ResourceVersion
+		if msg.CheckResourceVersion {
+			resourceVersion = msg.ResourceVersion
+		}
+
+		updatedBlueprint, status, err := h.updateBlueprint(patchedBlueprint, oldBlueprint, false, resourceVersion, false, recoveredID, "update")
+		if errors.Is(err, core.ErrResourceVersionConflict) && !msg.CheckResourceVersion && attempt < maxPatchAttempts {
 			log.WithFields(log.Fields{
-				"Error":         err,
-				"BlueprintName": msg.Blueprint.Metadata.Name,
-			}).Warn("Failed to resolve initiator for reconciliation")
-		} else {
-			immediateProcess, err := h.createImmediateReconciliationProcess(msg.Blueprint, matchedSD, recoveredID, initiatorName)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Error":         err,
-					"BlueprintName": msg.Blueprint.Metadata.Name,
-				}).Warn("Failed to create immediate reconciliation process after blueprint update")
-			} else {
-				_, err = h.server.ProcessController().AddProcess(immediateProcess)
-				if err != nil {
-					log.WithFields(log.Fields{
-						"Error":         err,
-						"BlueprintName": msg.Blueprint.Metadata.Name,
-					}).Warn("Failed to submit immediate reconciliation process after blueprint update")
-				} else {
-					log.WithFields(log.Fields{
-						"BlueprintName": msg.Blueprint.Metadata.Name,
-						"Generation":    msg.Blueprint.Metadata.Generation,
-						"ProcessID":     immediateProcess.ID,
-					}).Info("Submitted immediate reconciliation process for updated blueprint")
-				}
-			}
+				"BlueprintName": msg.Name,
+				"Attempt":       attempt,
+			}).Debug("Blueprint modified concurrently, reapplying patch")
+			continue
+		}
+		if h.server.HandleHTTPError(c, err, status) {
+			return
 		}
-	}
 
-	jsonString, err = msg.Blueprint.ToJSON()
-	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		jsonString, err = updatedBlueprint.ToJSON()
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		h.server.SendHTTPReply(c, payloadType, jsonString)
 		return
 	}
-
-	h.server.SendHTTPReply(c, payloadType, jsonString)
 }
 
-// HandleRemoveBlueprint removes a Blueprint by namespace and name
+// HandleRemoveBlueprint removes a Blueprint by namespace and name. Blueprints with finalizers are only
+// marked for deletion, see removeBlueprint.
 func (h *Handlers) HandleRemoveBlueprint(c backends.Context, recoveredID string, payloadType string, jsonString string) {
 	msg, err := rpc.CreateRemoveBlueprintMsgFromJSON(jsonString)
 	if err != nil {
@@ -1021,124 +1245,14 @@ func (h *Handlers) HandleRemoveBlueprint(c backends.Context, recoveredID string,
 		return
 	}
 
-	// Cron naming must match AddBlueprint: reconcile-{Kind}-{locationName}
-	locationName := blueprint.Metadata.LocationName
-	cronName := "reconcile-" + blueprint.Kind
-	if locationName != "" {
-		cronName = cronName + "-" + locationName
-	}
-
-	// Remove the blueprint from database first
-	err = h.server.BlueprintDB().RemoveBlueprintByName(msg.Namespace, msg.Name)
-	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+	if msg.CheckResourceVersion && msg.ResourceVersion != blueprint.Metadata.ResourceVersion {
+		h.server.HandleHTTPError(c, resourceVersionConflict(blueprint, msg.ResourceVersion), http.StatusConflict)
 		return
 	}
 
-	// Check if there are any remaining blueprints of this Kind at this location
-	remainingBlueprints, err := h.server.BlueprintDB().GetBlueprintsByNamespaceAndKind(msg.Namespace, blueprint.Kind)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"Error": err,
-			"Kind":  blueprint.Kind,
-		}).Warn("Failed to check remaining blueprints")
-		remainingBlueprints = []*core.Blueprint{} // Assume none remaining on error
-	}
-
-	// Filter to only blueprints at the same location
-	remainingAtLocation := 0
-	for _, bp := range remainingBlueprints {
-		if bp.Metadata.LocationName == locationName {
-			remainingAtLocation++
-		}
-	}
-
-	// Only remove cron if no blueprints of this Kind remain at this location
-	if remainingAtLocation == 0 {
-		existingCron, err := h.server.CronDB().GetCronByName(msg.Namespace, cronName)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"Error":    err,
-				"CronName": cronName,
-			}).Warn("Failed to get cron for deletion")
-		} else if existingCron != nil {
-			err = h.server.CronController().RemoveCron(existingCron.ID)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"Error":        err,
-					"Kind":         blueprint.Kind,
-					"CronName":     cronName,
-					"LocationName": locationName,
-				}).Warn("Failed to remove cron")
-			} else {
-				log.WithFields(log.Fields{
-					"Kind":         blueprint.Kind,
-					"CronName":     cronName,
-					"LocationName": locationName,
-				}).Info("Auto-removed cron (last blueprint of Kind at location deleted)")
-			}
-		}
-	} else {
-		log.WithFields(log.Fields{
-			"BlueprintName":       msg.Name,
-			"Kind":                blueprint.Kind,
-			"LocationName":        locationName,
-			"RemainingBlueprints": remainingAtLocation,
-		}).Debug("Cron kept (other blueprints of same Kind at location exist)")
-	}
-
-	// Submit cleanup process to reconciler (best-effort)
-	// Look up the BlueprintDefinition by Kind to get the ExecutorType
-	blueprintDef, err := h.server.BlueprintDB().GetBlueprintDefinitionByKind(blueprint.Kind)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"Error":         err,
-			"Kind":          blueprint.Kind,
-			"BlueprintName": msg.Name,
-		}).Warn("Failed to lookup BlueprintDefinition for cleanup process")
-	}
-	if blueprintDef == nil {
-		log.WithFields(log.Fields{
-			"Kind":          blueprint.Kind,
-			"BlueprintName": msg.Name,
-		}).Warn("BlueprintDefinition not found - cleanup process not created")
-	} else if blueprintDef.Spec.Handler.ExecutorType == "" {
-		log.WithFields(log.Fields{
-			"Kind":          blueprint.Kind,
-			"BlueprintName": msg.Name,
-		}).Warn("BlueprintDefinition has no ExecutorType - cleanup process not created")
-	} else {
-		// Create cleanup function spec
-		funcSpec := core.CreateEmptyFunctionSpec()
-		funcSpec.NodeName = fmt.Sprintf("%s-cleanup", blueprintDef.Spec.Handler.ExecutorType)
-		funcSpec.Conditions.ColonyName = msg.Namespace
-		funcSpec.Conditions.ExecutorType = blueprintDef.Spec.Handler.ExecutorType
-		funcSpec.Conditions.LocationName = blueprint.Metadata.LocationName
-		funcSpec.FuncName = "cleanup"
-		funcSpec.KwArgs = map[string]interface{}{
-			"blueprintName": msg.Name,
-			"kind":          blueprint.Kind,
-		}
-
-		// Resolve initiator name for the process
-		initiatorName, _ := h.resolveInitiator(msg.Namespace, recoveredID)
-
-		// Create and submit the process
-		cleanupProcess := core.CreateProcess(funcSpec)
-		cleanupProcess.InitiatorID = recoveredID
-		cleanupProcess.InitiatorName = initiatorName
-
-		_, err = h.server.ProcessController().AddProcess(cleanupProcess)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"Error":         err,
-				"BlueprintName": msg.Name,
-			}).Warn("Failed to submit cleanup process")
-		} else {
-			log.WithFields(log.Fields{
-				"BlueprintName": msg.Name,
-				"ExecutorType":  blueprintDef.Spec.Handler.ExecutorType,
-			}).Info("Submitted cleanup process for deleted blueprint")
-		}
+	status, err := h.removeBlueprint(blueprint, msg.ResourceVersion, !msg.CheckResourceVersion, recoveredID)
+	if h.server.HandleHTTPError(c, err, status) {
+		return
 	}
 
 	log.WithFields(log.Fields{
@@ -1232,8 +1346,36 @@ func (h *Handlers) HandleUpdateBlueprintStatus(c backends.Context, recoveredID s
 		return
 	}
 
+	if msg.CheckResourceVersion && msg.ResourceVersion != blueprint.Metadata.ResourceVersion {
+		h.server.HandleHTTPError(c, resourceVersionConflict(blueprint, msg.ResourceVersion), http.StatusConflict)
+		return
+	}
+
+	// Conditions have a standard shape so that clients can tell whether a blueprint is healthy
+	if err := core.NormalizeConditions(blueprint.Status, msg.Status); err != nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("Failed to update blueprint status, %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updated := *blueprint
+	updated.Status = msg.Status
+	for _, condition := range updated.GetConditions() {
+		if condition.ObservedGeneration > blueprint.Metadata.Generation {
+			h.server.HandleHTTPError(c, fmt.Errorf("Failed to update blueprint status, condition '%s' observed generation %d but blueprint is at generation %d", condition.Type, condition.ObservedGeneration, blueprint.Metadata.Generation), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Update only the status
-	err = h.server.BlueprintDB().UpdateBlueprintStatus(blueprint.ID, msg.Status)
+	if msg.CheckResourceVersion {
+		err = h.server.BlueprintDB().UpdateBlueprintStatusWithResourceVersion(blueprint.ID, msg.Status, msg.ResourceVersion)
+	} else {
+		err = h.server.BlueprintDB().UpdateBlueprintStatus(blueprint.ID, msg.Status)
+	}
+	if errors.Is(err, core.ErrResourceVersionConflict) {
+		h.server.HandleHTTPError(c, err, http.StatusConflict)
+		return
+	}
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
@@ -2,7 +2,9 @@ package blueprint_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/colonyos/colonies/pkg/client"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
 	"github.com/stretchr/testify/assert"
@@ -835,6 +837,204 @@ func TestAddBlueprintWithInvalidSchema(t *testing.T) {
 	<-done
 }
 
+// TestAddBlueprintAppliesSchemaDefaults tests that nested defaults are applied and that errors carry JSON pointers
+func TestAddBlueprintAppliesSchemaDefaults(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"defaulted-deployment",
+		"compute.io",
+		"v1",
+		"DefaultedDeployment",
+		"defaulteddeployments",
+		"Namespaced",
+		"test_executor_type",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+
+	// Schemas that do not compile are rejected
+	sd.Spec.Schema = &core.ValidationSchema{
+		Properties: map[string]core.SchemaProperty{
+			"image": {Type: "string", Pattern: "[a-"},
+		},
+	}
+	_, err := client.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "/properties/image/pattern")
+
+	minReplicas := 1.0
+	sd.Spec.Schema = &core.ValidationSchema{
+		Type: "object",
+		Properties: map[string]core.SchemaProperty{
+			"replicas": {Type: "integer", Default: 1, Minimum: &minReplicas},
+			"resources": {
+				Type: "object",
+				Properties: map[string]core.SchemaProperty{
+					"memory": {Type: "string", Default: "1Gi"},
+				},
+			},
+			"ports": {
+				Type: "array",
+				Items: &core.SchemaProperty{
+					Type: "object",
+					Properties: map[string]core.SchemaProperty{
+						"port":     {Type: "integer"},
+						"protocol": {Type: "string", Default: "TCP"},
+					},
+				},
+			},
+		},
+	}
+	_, err = client.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	blueprint := core.CreateBlueprint("DefaultedDeployment", "test-deployment", env.ColonyName)
+	blueprint.SetSpec("resources", map[string]interface{}{})
+	blueprint.SetSpec("ports", []interface{}{map[string]interface{}{"port": 80}})
+	addedBlueprint, err := client.AddBlueprint(blueprint, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), addedBlueprint.Spec["replicas"])
+	assert.Equal(t, "1Gi", addedBlueprint.Spec["resources"].(map[string]interface{})["memory"])
+	assert.Equal(t, "TCP", addedBlueprint.Spec["ports"].([]interface{})[0].(map[string]interface{})["protocol"])
+
+	addedBlueprint.SetSpec("replicas", 0)
+	_, err = client.UpdateBlueprint(addedBlueprint, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "/spec/replicas: must be >= 1")
+
+	server.Shutdown()
+	<-done
+}
+
+func TestBlueprintDefinitionVersionsAndMigration(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"versioned-deployment",
+		"compute.io",
+		"v1",
+		"VersionedDeployment",
+		"versioneddeployments",
+		"Namespaced",
+		"test_executor_type",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	sd.Spec.Schema = &core.ValidationSchema{
+		Type:       "object",
+		Properties: map[string]core.SchemaProperty{"image": {Type: "string"}, "cpu": {Type: "string"}},
+	}
+	addedSD, err := client.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	web := core.CreateBlueprint("VersionedDeployment", "web", env.ColonyName)
+	web.SetSpec("image", "nginx")
+	web.SetSpec("cpu", "500m")
+	_, err = client.AddBlueprint(web, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	db := core.CreateBlueprint("VersionedDeployment", "db", env.ColonyName)
+	db.SetSpec("cpu", "2")
+	_, err = client.AddBlueprint(db, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	// Introduce v2 as the storage version, v1 is still served
+	addedSD.Spec.Schema = nil
+	addedSD.Spec.Versions = []core.BlueprintDefinitionVersion{
+		{Name: "v1", Served: true, Schema: sd.Spec.Schema},
+		{
+			Name:    "v2",
+			Served:  true,
+			Storage: true,
+			Schema: &core.ValidationSchema{
+				Type: "object",
+				Properties: map[string]core.SchemaProperty{
+					"image":     {Type: "string"},
+					"resources": {Type: "object", Properties: map[string]core.SchemaProperty{"cpu": {Type: "string"}}},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+	addedSD.Spec.Conversion = &core.ConversionSpec{
+		Strategy: core.ConversionStrategyMappings,
+		Mappings: []core.VersionMapping{
+			{From: "v1", To: "v2", Fields: []core.FieldMapping{{From: "/cpu", To: "/resources/cpu"}}},
+			{From: "v2", To: "v1", Fields: []core.FieldMapping{{From: "/resources/cpu", To: "/cpu"}, {From: "/resources"}}},
+		},
+	}
+	updatedSD, err := client.UpdateBlueprintDefinition(addedSD, env.ExecutorPrvKey)
+	assert.NotNil(t, err) // Only colony owner can update definitions
+	updatedSD, err = client.UpdateBlueprintDefinition(addedSD, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", updatedSD.Spec.Version)
+	assert.Equal(t, addedSD.ID, updatedSD.ID)
+
+	// Blueprints stored in v1 are converted on read
+	webV2, err := client.GetBlueprint(env.ColonyName, "web", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", webV2.Version)
+	assert.Equal(t, "500m", webV2.Spec["resources"].(map[string]interface{})["cpu"])
+
+	webV1, err := client.GetBlueprintByVersion(env.ColonyName, "web", "v1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", webV1.Version)
+	assert.Equal(t, "500m", webV1.Spec["cpu"])
+
+	_, err = client.GetBlueprintByVersion(env.ColonyName, "web", "v3", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// New blueprints written in v1 are stored in v2
+	api := core.CreateBlueprint("VersionedDeployment", "api", env.ColonyName)
+	api.Version = "v1"
+	api.SetSpec("image", "api")
+	api.SetSpec("cpu", "1")
+	addedAPI, err := client.AddBlueprint(api, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", addedAPI.Version)
+	assert.Equal(t, "1", addedAPI.Spec["resources"].(map[string]interface{})["cpu"])
+
+	report, err := client.MigrateBlueprintDefinition(env.ColonyName, sd.Metadata.Name, true, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, "v2", report.StorageVersion)
+	assert.Len(t, report.Results, 3)
+	statuses := make(map[string]string)
+	for _, result := range report.Results {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, core.MigrationUnchanged, statuses["api"])
+	assert.Equal(t, core.MigrationFailed, statuses["db"])
+	assert.Equal(t, core.MigrationMigrated, statuses["web"])
+	assert.Equal(t, "db", report.Results[1].Name)
+	assert.Contains(t, report.Results[1].Error, "required field 'image' is missing")
+
+	report, err = client.MigrateBlueprintDefinition(env.ColonyName, sd.Metadata.Name, false, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.MigrationMigrated, report.Results[2].Status)
+
+	report, err = client.MigrateBlueprintDefinition(env.ColonyName, sd.Metadata.Name, false, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.MigrationUnchanged, report.Results[2].Status)
+	assert.Equal(t, core.MigrationFailed, report.Results[1].Status)
+
+	// v1 cannot be removed while db is still stored in it
+	updatedSD.Spec.Versions = updatedSD.Spec.Versions[1:]
+	updatedSD.Spec.Conversion = nil
+	_, err = client.UpdateBlueprintDefinition(updatedSD, env.ColonyPrvKey)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "still used by blueprint 'db'")
+
+	err = client.RemoveBlueprint(env.ColonyName, "db", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	_, err = client.UpdateBlueprintDefinition(updatedSD, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
 func TestUpdateBlueprintWithoutHandler(t *testing.T) {
 	env, client, server, _, done := server.SetupTestEnv2(t)
 
@@ -1930,6 +2130,360 @@ func TestUpdateBlueprintStatus(t *testing.T) {
 	<-done
 }
 
+func TestUpdateBlueprintStatusConditions(t *testing.T) {
+	env, c, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"condition-test",
+		"example.com",
+		"v1",
+		"ConditionTest",
+		"conditiontests",
+		"Namespaced",
+		"test_controller",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	_, err := c.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	blueprint := core.CreateBlueprint("ConditionTest", "web", env.ColonyName)
+	added, err := c.AddBlueprint(blueprint, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	added.SetCondition(core.BlueprintCondition{
+		Type:               core.ConditionReady,
+		Status:             core.ConditionTrue,
+		Reason:             "AllReplicasRunning",
+		ObservedGeneration: added.Metadata.Generation,
+	})
+	err = c.UpdateBlueprintStatus(env.ColonyName, "web", added.Status, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	ready, err := c.WaitForBlueprintCondition(env.ColonyName, "web", core.ConditionReady, core.ConditionTrue, 5*time.Second, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "AllReplicasRunning", ready.GetCondition(core.ConditionReady).Reason)
+	assert.Equal(t, core.ConditionReady, ready.ConditionSummary())
+
+	_, err = c.WaitForBlueprintCondition(env.ColonyName, "web", core.ConditionDegraded, core.ConditionTrue, 1*time.Second, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Conditions must have a type and a valid status
+	invalid := map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "yes"}},
+	}
+	err = c.UpdateBlueprintStatus(env.ColonyName, "web", invalid, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Conditions cannot be observed from a future generation
+	future := core.CreateBlueprint("ConditionTest", "web", env.ColonyName)
+	future.SetCondition(core.BlueprintCondition{Type: core.ConditionReady, Status: core.ConditionTrue, ObservedGeneration: added.Metadata.Generation + 1})
+	err = c.UpdateBlueprintStatus(env.ColonyName, "web", future.Status, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestBlueprintResourceVersionAndPatch(t *testing.T) {
+	env, c, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"patch-test",
+		"example.com",
+		"v1",
+		"PatchTest",
+		"patchtests",
+		"Namespaced",
+		"test_controller",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	_, err := c.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	blueprint := core.CreateBlueprint("PatchTest", "web", env.ColonyName)
+	blueprint.SetSpec("image", "nginx:1.0")
+	blueprint.SetSpec("replicas", 1)
+	blueprint.SetSpec("containers", []interface{}{
+		map[string]interface{}{"name": "web", "image": "nginx:1.0"},
+		map[string]interface{}{"name": "sidecar", "image": "envoy"},
+	})
+	added, err := c.AddBlueprint(blueprint, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), added.Metadata.ResourceVersion)
+
+	// Two clients read the same version, the second write must fail
+	added.SetSpec("replicas", 2)
+	updated, err := c.UpdateBlueprintWithResourceVersion(added, 1, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), updated.Metadata.ResourceVersion)
+
+	added.SetSpec("replicas", 5)
+	_, err = c.UpdateBlueprintWithResourceVersion(added, 1, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+	assert.True(t, client.IsConflict(err))
+
+	// Status updates also bump the resource version
+	err = c.UpdateBlueprintStatusWithResourceVersion(env.ColonyName, "web", map[string]interface{}{"ready": true}, 1, env.ExecutorPrvKey)
+	assert.True(t, client.IsConflict(err))
+	err = c.UpdateBlueprintStatusWithResourceVersion(env.ColonyName, "web", map[string]interface{}{"ready": true}, 2, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	current, err := c.GetBlueprint(env.ColonyName, "web", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), current.Metadata.ResourceVersion)
+	assert.Equal(t, float64(2), current.Spec["replicas"])
+
+	// Merge patch
+	patched, err := c.PatchBlueprint(env.ColonyName, "web", core.MergePatchType, map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 3},
+	}, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), patched.Spec["replicas"])
+	assert.Equal(t, "nginx:1.0", patched.Spec["image"])
+	assert.Equal(t, int64(4), patched.Metadata.ResourceVersion)
+	assert.Equal(t, current.Metadata.Generation+1, patched.Metadata.Generation)
+
+	// Strategic merge patch merges lists by name
+	patched, err = c.PatchBlueprint(env.ColonyName, "web", core.StrategicPatchType, map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{
+			map[string]interface{}{"name": "web", "image": "nginx:2.0"},
+		}},
+	}, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	containers := patched.Spec["containers"].([]interface{})
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "nginx:2.0", containers[0].(map[string]interface{})["image"])
+
+	_, err = c.PatchBlueprintWithResourceVersion(env.ColonyName, "web", core.MergePatchType, map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 10},
+	}, 1, env.ExecutorPrvKey)
+	assert.True(t, client.IsConflict(err))
+
+	_, err = c.PatchBlueprint(env.ColonyName, "web", core.MergePatchType, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "other"},
+	}, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = c.PatchBlueprint(env.ColonyName, "missing", core.MergePatchType, map[string]interface{}{}, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Remove with a stale resource version fails
+	err = c.RemoveBlueprintWithResourceVersion(env.ColonyName, "web", 1, env.ExecutorPrvKey)
+	assert.True(t, client.IsConflict(err))
+	err = c.RemoveBlueprintWithResourceVersion(env.ColonyName, "web", patched.Metadata.ResourceVersion, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestBlueprintFinalizersAndOwnerReferences(t *testing.T) {
+	env, c, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"finalizer-test",
+		"example.com",
+		"v1",
+		"FinalizerTest",
+		"finalizertests",
+		"Namespaced",
+		"test_controller",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	_, err := c.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	parent := core.CreateBlueprint("FinalizerTest", "parent", env.ColonyName)
+	parent.AddFinalizer("test_controller/cleanup")
+	_, err = c.AddBlueprint(parent, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	child := core.CreateBlueprint("FinalizerTest", "child", env.ColonyName)
+	child.Metadata.OwnerReferences = []core.OwnerReference{{Name: "parent"}}
+	addedChild, err := c.AddBlueprint(child, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, parent.ID, addedChild.Metadata.OwnerReferences[0].BlueprintID)
+
+	orphan := core.CreateBlueprint("FinalizerTest", "orphan", env.ColonyName)
+	orphan.Metadata.OwnerReferences = []core.OwnerReference{{Name: "missing"}}
+	_, err = c.AddBlueprint(orphan, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// The parent is only marked for deletion, the child is removed immediately
+	err = c.RemoveBlueprint(env.ColonyName, "parent", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	marked, err := c.GetBlueprint(env.ColonyName, "parent", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, marked.IsBeingDeleted())
+
+	_, err = c.GetBlueprint(env.ColonyName, "child", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// New finalizers cannot be added while the blueprint is being deleted
+	marked.AddFinalizer("other")
+	_, err = c.UpdateBlueprint(marked, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Removing the last finalizer completes the removal
+	err = c.RemoveBlueprintFinalizer(env.ColonyName, "parent", "test_controller/cleanup", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = c.GetBlueprint(env.ColonyName, "parent", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestApplyBlueprints(t *testing.T) {
+	env, c, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"apply-test",
+		"example.com",
+		"v1",
+		"ApplyTest",
+		"applytests",
+		"Namespaced",
+		"test_controller",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	_, err := c.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	bp1 := core.CreateBlueprint("ApplyTest", "bp1", env.ColonyName)
+	bp1.SetSpec("replicas", 1)
+	bp2 := core.CreateBlueprint("ApplyTest", "bp2", env.ColonyName)
+	bp2.SetSpec("replicas", 2)
+
+	// Dry run does not write anything
+	plan, err := c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1, bp2}, false, "test", true, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, plan.Count(core.ApplyCreate))
+	assert.False(t, plan.Applied)
+
+	blueprints, err := c.GetBlueprints(env.ColonyName, "ApplyTest", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, blueprints, 0)
+
+	plan, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1, bp2}, false, "test", false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, plan.Count(core.ApplyCreate))
+	assert.True(t, plan.Applied)
+
+	applied, err := c.GetBlueprint(env.ColonyName, "bp1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", applied.Metadata.Labels[core.ManagedByLabel])
+
+	// Applying the same blueprints again changes nothing
+	plan, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1, bp2}, false, "test", false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.False(t, plan.HasChanges())
+	assert.False(t, plan.Applied)
+
+	// Changing a spec updates the blueprint and pruning removes bp2
+	bp1.SetSpec("replicas", 3)
+	plan, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1}, true, "test", false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, plan.Count(core.ApplyUpdate))
+	assert.Equal(t, 1, plan.Count(core.ApplyDelete))
+
+	updated, err := c.GetBlueprint(env.ColonyName, "bp1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), updated.Spec["replicas"])
+	assert.Equal(t, applied.Metadata.Generation+1, updated.Metadata.Generation)
+
+	_, err = c.GetBlueprint(env.ColonyName, "bp2", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Blueprints without the managed-by label are never pruned
+	unmanaged := core.CreateBlueprint("ApplyTest", "unmanaged", env.ColonyName)
+	_, err = c.AddBlueprint(unmanaged, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	plan, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1}, true, "test", false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, plan.Count(core.ApplyDelete))
+
+	// An invalid blueprint fails the whole apply
+	bp3 := core.CreateBlueprint("ApplyTest", "bp3", env.ColonyName)
+	invalid := core.CreateBlueprint("MissingKind", "invalid", env.ColonyName)
+	_, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1, bp3, invalid}, false, "test", false, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = c.GetBlueprint(env.ColonyName, "bp3", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Prune requires a managed-by value
+	_, err = c.ApplyBlueprints(env.ColonyName, []*core.Blueprint{bp1}, true, "", false, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestRollbackBlueprint(t *testing.T) {
+	env, c, server, _, done := server.SetupTestEnv2(t)
+
+	sd := core.CreateBlueprintDefinition(
+		"rollback-test",
+		"example.com",
+		"v1",
+		"RollbackTest",
+		"rollbacktests",
+		"Namespaced",
+		"test_controller",
+		"reconcile",
+	)
+	sd.Metadata.ColonyName = env.ColonyName
+	sd.Spec.HistoryLimit = 3
+	_, err := c.AddBlueprintDefinition(sd, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	blueprint := core.CreateBlueprint("RollbackTest", "web", env.ColonyName)
+	blueprint.SetSpec("image", "nginx:1.0")
+	added, err := c.AddBlueprint(blueprint, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	firstGeneration := added.Metadata.Generation
+	added.SetSpec("image", "nginx:2.0")
+	updated, err := c.UpdateBlueprint(added, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	rolledBack, err := c.RollbackBlueprint(env.ColonyName, "web", firstGeneration, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx:1.0", rolledBack.Spec["image"])
+	assert.Equal(t, updated.Metadata.Generation+1, rolledBack.Metadata.Generation)
+
+	histories, err := c.GetBlueprintHistory(rolledBack.ID, 0, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 3)
+	assert.Equal(t, "rollback", histories[0].ChangeType)
+
+	// Only the configured number of history entries are kept
+	rolledBack.SetSpec("image", "nginx:3.0")
+	_, err = c.UpdateBlueprint(rolledBack, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	histories, err = c.GetBlueprintHistory(rolledBack.ID, 0, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 3)
+
+	_, err = c.RollbackBlueprint(env.ColonyName, "web", 99, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = c.RollbackBlueprint(env.ColonyName, "missing", 1, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
+
 func TestUpdateBlueprintStatusNotFound(t *testing.T) {
 	env, client, server, _, done := server.SetupTestEnv2(t)
 
@@ -2,11 +2,15 @@ package blueprint
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/handlers/process"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +24,9 @@ type MockBlueprintDB struct {
 	getBlueprintDefByNameErr  error
 	getBlueprintDefByKindErr  error
 	getDefsByNamespaceErr     error
+	updateBlueprintErr        error
+	applyBlueprintsErr        error
+	histories                 []*core.BlueprintHistory
 }
 
 func (m *MockBlueprintDB) AddBlueprintDefinition(sd *core.BlueprintDefinition) error {
@@ -174,25 +181,74 @@ func (m *MockBlueprintDB) GetBlueprintsByNamespaceKindAndLocation(namespace, kin
 }
 
 func (m *MockBlueprintDB) UpdateBlueprint(blueprint *core.Blueprint) error {
+	blueprint.Metadata.ResourceVersion++
 	return nil
 }
 
+func (m *MockBlueprintDB) UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64) error {
+	if m.updateBlueprintErr != nil {
+		return m.updateBlueprintErr
+	}
+	return m.UpdateBlueprint(blueprint)
+}
+
 func (m *MockBlueprintDB) UpdateBlueprintStatus(id string, status map[string]interface{}) error {
 	return nil
 }
 
+func (m *MockBlueprintDB) UpdateBlueprintStatusWithResourceVersion(id string, status map[string]interface{}, resourceVersion int64) error {
+	return nil
+}
+
 func (m *MockBlueprintDB) RemoveBlueprintByID(id string) error {
 	return nil
 }
 
 func (m *MockBlueprintDB) RemoveBlueprintByName(namespace, name string) error {
+	for i, bp := range m.blueprints {
+		if bp.Metadata.ColonyName == namespace && bp.Metadata.Name == name {
+			m.blueprints = append(m.blueprints[:i], m.blueprints[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
+func (m *MockBlueprintDB) RemoveBlueprintByNameWithResourceVersion(namespace, name string, resourceVersion int64) error {
+	return m.RemoveBlueprintByName(namespace, name)
+}
+
 func (m *MockBlueprintDB) RemoveBlueprintsByNamespace(namespace string) error {
 	return nil
 }
 
+func (m *MockBlueprintDB) ApplyBlueprints(creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error {
+	if m.applyBlueprintsErr != nil {
+		return m.applyBlueprintsErr
+	}
+	for _, bp := range creates {
+		bp.Metadata.ResourceVersion = 1
+		m.blueprints = append(m.blueprints, bp)
+	}
+	for _, bp := range updates {
+		bp.Metadata.ResourceVersion++
+		for i, existing := range m.blueprints {
+			if existing.ID == bp.ID {
+				m.blueprints[i] = bp
+			}
+		}
+	}
+	for _, bp := range removes {
+		for i, existing := range m.blueprints {
+			if existing.ID == bp.ID {
+				m.blueprints = append(m.blueprints[:i], m.blueprints[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
 func (m *MockBlueprintDB) CountBlueprints() (int, error) {
 	return len(m.blueprints), nil
 }
@@ -208,14 +264,29 @@ func (m *MockBlueprintDB) CountBlueprintsByNamespace(namespace string) (int, err
 }
 
 func (m *MockBlueprintDB) AddBlueprintHistory(history *core.BlueprintHistory) error {
+	m.histories = append(m.histories, history)
 	return nil
 }
 
 func (m *MockBlueprintDB) GetBlueprintHistory(blueprintID string, limit int) ([]*core.BlueprintHistory, error) {
-	return nil, nil
+	var result []*core.BlueprintHistory
+	for i := len(m.histories) - 1; i >= 0; i-- {
+		if m.histories[i].BlueprintID == blueprintID {
+			result = append(result, m.histories[i])
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
 }
 
 func (m *MockBlueprintDB) GetBlueprintHistoryByGeneration(blueprintID string, generation int64) (*core.BlueprintHistory, error) {
+	for _, history := range m.histories {
+		if history.BlueprintID == blueprintID && history.Generation == generation {
+			return history, nil
+		}
+	}
 	return nil, nil
 }
 
@@ -223,6 +294,22 @@ func (m *MockBlueprintDB) RemoveBlueprintHistory(blueprintID string) error {
 	return nil
 }
 
+func (m *MockBlueprintDB) PruneBlueprintHistory(blueprintID string, keep int) error {
+	kept := 0
+	var result []*core.BlueprintHistory
+	for i := len(m.histories) - 1; i >= 0; i-- {
+		if m.histories[i].BlueprintID == blueprintID {
+			if kept >= keep {
+				continue
+			}
+			kept++
+		}
+		result = append([]*core.BlueprintHistory{m.histories[i]}, result...)
+	}
+	m.histories = result
+	return nil
+}
+
 // MockExecutorDB is a mock implementation of ExecutorDatabase
 type MockExecutorDB struct {
 	executors        []*core.Executor
@@ -278,7 +365,13 @@ func (m *MockExecutorDB) GetExecutorByName(colonyName string, executorName strin
 }
 
 func (m *MockExecutorDB) GetExecutorsByBlueprintID(blueprintID string) ([]*core.Executor, error) {
-	return nil, nil
+	var result []*core.Executor
+	for _, e := range m.executors {
+		if e.BlueprintID == blueprintID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
 }
 
 func (m *MockExecutorDB) ApproveExecutor(executor *core.Executor) error {
@@ -294,6 +387,12 @@ func (m *MockExecutorDB) MarkAlive(executor *core.Executor) error {
 }
 
 func (m *MockExecutorDB) RemoveExecutorByName(colonyName string, executorName string) error {
+	for i, e := range m.executors {
+		if e.ColonyName == colonyName && e.Name == executorName {
+			m.executors = append(m.executors[:i], m.executors[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
@@ -323,6 +422,36 @@ func (m *MockExecutorDB) UpdateExecutorCapabilities(colonyName string, executorN
 	return nil
 }
 
+// MockFunctionDB is a mock implementation of FunctionDatabase
+type MockFunctionDB struct {
+	removedExecutorNames []string
+}
+
+func (m *MockFunctionDB) AddFunction(function *core.Function) error { return nil }
+func (m *MockFunctionDB) GetFunctionByID(functionID string) (*core.Function, error) { return nil, nil }
+func (m *MockFunctionDB) GetFunctionsByExecutorName(colonyName string, executorName string) ([]*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByColonyName(colonyName string) ([]*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByExecutorAndName(colonyName string, executorName string, name string) (*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) UpdateFunctionStats(colonyName string, executorName string, name string, counter int, minWaitTime float64, maxWaitTime float64, minExecTime float64, maxExecTime float64, avgWaitTime float64, avgExecTime float64) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionByID(functionID string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctionByName(colonyName string, executorName string, name string) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByExecutorName(colonyName string, executorName string) error {
+	m.removedExecutorNames = append(m.removedExecutorNames, executorName)
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByColonyName(colonyName string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctions() error                             { return nil }
+
 // MockUserDB is a mock implementation of UserDatabase
 type MockUserDB struct {
 	users            []*core.User
@@ -435,6 +564,10 @@ func (m *MockProcessController) UnassignExecutor(processID string) error {
 	return nil
 }
 
+func (m *MockProcessController) ReleaseProcess(processID string) error {
+	return nil
+}
+
 func (m *MockProcessController) PauseColonyAssignments(colonyName string) error {
 	return nil
 }
@@ -494,14 +627,91 @@ func (m *MockCronController) RemoveCron(cronID string) error {
 	return nil
 }
 
+func (m *MockCronController) SuspendCron(cronID string) (*core.Cron, error) {
+	return nil, nil
+}
+
+func (m *MockCronController) ResumeCron(cronID string) (*core.Cron, error) {
+	return nil, nil
+}
+
+func (m *MockCronController) GetCronRuns(cron *core.Cron) ([]*core.CronRun, error) {
+	return []*core.CronRun{}, nil
+}
+
 func (m *MockCronController) GetCronPeriod() int {
 	return 60
 }
 
+// MockCronDB is a mock implementation of CronDatabase
+type MockCronDB struct {
+	crons []*core.Cron
+}
+
+func (m *MockCronDB) AddCron(cron *core.Cron) error {
+	m.crons = append(m.crons, cron)
+	return nil
+}
+
+func (m *MockCronDB) UpdateCron(cronID string, nextRun time.Time, lastRun time.Time, lastProcessGraphID string) error {
+	return nil
+}
+
+func (m *MockCronDB) GetCronByID(cronID string) (*core.Cron, error) {
+	for _, c := range m.crons {
+		if c.ID == cronID {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCronDB) GetCronByName(colonyName string, cronName string) (*core.Cron, error) {
+	for _, c := range m.crons {
+		if c.ColonyName == colonyName && c.Name == cronName {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCronDB) FindCronsByColonyName(colonyName string, count int) ([]*core.Cron, error) {
+	return m.crons, nil
+}
+
+func (m *MockCronDB) FindAllCrons() ([]*core.Cron, error) {
+	return m.crons, nil
+}
+
+func (m *MockCronDB) RemoveCronByID(cronID string) error {
+	return nil
+}
+
+func (m *MockCronDB) RemoveAllCronsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (m *MockCronDB) SetCronSuspended(cronID string, suspended bool) error {
+	return nil
+}
+
+func (m *MockCronDB) AddCronRun(run *core.CronRun) error {
+	return nil
+}
+
+func (m *MockCronDB) GetCronRuns(cronID string, count int) ([]*core.CronRun, error) {
+	return []*core.CronRun{}, nil
+}
+
+func (m *MockCronDB) PruneCronRuns(cronID string, keep int) error {
+	return nil
+}
+
 // MockServer implements the Server interface for testing
 type MockServer struct {
 	blueprintDB       *MockBlueprintDB
 	executorDB        *MockExecutorDB
+	functionDB        *MockFunctionDB
 	userDB            *MockUserDB
 	processController *MockProcessController
 	cronController    *MockCronController
@@ -544,6 +754,9 @@ func (m *MockServer) CronController() interface {
 	AddCron(cron *core.Cron) (*core.Cron, error)
 	RunCron(cronID string) (*core.Cron, error)
 	RemoveCron(cronID string) error
+	SuspendCron(cronID string) (*core.Cron, error)
+	ResumeCron(cronID string) (*core.Cron, error)
+	GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 	GetCronPeriod() int
 } {
 	return m.cronController
@@ -553,6 +766,10 @@ func (m *MockServer) ExecutorDB() database.ExecutorDatabase {
 	return m.executorDB
 }
 
+func (m *MockServer) FunctionDB() database.FunctionDatabase {
+	return m.functionDB
+}
+
 func (m *MockServer) UserDB() database.UserDatabase {
 	return m.userDB
 }
@@ -1021,3 +1238,612 @@ func TestNewHandlers(t *testing.T) {
 
 	assert.NotNil(t, handlers)
 }
+
+// =============================================
+// Tests for versioned BlueprintDefinitions
+// =============================================
+
+func createTestVersionedBlueprintDefinition(kind, colonyName string) *core.BlueprintDefinition {
+	sd := createTestBlueprintDefinition(kind, "docker-reconciler", colonyName)
+	sd.Spec.Version = ""
+	sd.Spec.Versions = []core.BlueprintDefinitionVersion{
+		{
+			Name:   "v1",
+			Served: true,
+			Schema: &core.ValidationSchema{
+				Type:       "object",
+				Properties: map[string]core.SchemaProperty{"image": {Type: "string"}, "cpu": {Type: "string"}},
+			},
+		},
+		{
+			Name:    "v2",
+			Served:  true,
+			Storage: true,
+			Schema: &core.ValidationSchema{
+				Type: "object",
+				Properties: map[string]core.SchemaProperty{
+					"image":     {Type: "string"},
+					"resources": {Type: "object", Properties: map[string]core.SchemaProperty{"cpu": {Type: "string"}}},
+					"replicas":  {Type: "integer", Default: 1},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+	sd.Spec.Conversion = &core.ConversionSpec{
+		Strategy: core.ConversionStrategyMappings,
+		Mappings: []core.VersionMapping{
+			{From: "v1", To: "v2", Fields: []core.FieldMapping{{From: "/cpu", To: "/resources/cpu"}}},
+			{From: "v2", To: "v1", Fields: []core.FieldMapping{{From: "/resources/cpu", To: "/cpu"}, {From: "/resources"}, {From: "/replicas"}}},
+		},
+	}
+	return sd
+}
+
+func TestValidateBlueprintDefinition_SetsStorageVersion(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+
+	assert.Nil(t, validateBlueprintDefinition(sd))
+	assert.Equal(t, "v2", sd.Spec.Version)
+
+	sd.Spec.Versions[1].Storage = false
+	assert.NotNil(t, validateBlueprintDefinition(sd))
+}
+
+func TestPrepareBlueprint_ConvertsToStorageVersion(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Version = "v1"
+	blueprint.SetSpec("image", "nginx")
+	blueprint.SetSpec("cpu", "500m")
+
+	prepared, err := prepareBlueprint(blueprint, sd)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", prepared.Version)
+	assert.Equal(t, "500m", prepared.Spec["resources"].(map[string]interface{})["cpu"])
+	assert.Equal(t, 1, prepared.Spec["replicas"])
+	assert.NotContains(t, prepared.Spec, "cpu")
+}
+
+func TestPrepareBlueprint_DefaultsToStorageVersion(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.SetSpec("image", "nginx")
+
+	prepared, err := prepareBlueprint(blueprint, sd)
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", prepared.Version)
+}
+
+func TestPrepareBlueprint_Errors(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+
+	// Unknown version
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Version = "v3"
+	_, err := prepareBlueprint(blueprint, sd)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not served")
+
+	// Valid in v1 but missing a field required by the storage version
+	blueprint = createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Version = "v1"
+	blueprint.SetSpec("cpu", "500m")
+	_, err = prepareBlueprint(blueprint, sd)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "after conversion to version 'v2'")
+}
+
+func TestPrepareBlueprint_ExecutorConversionKeepsVersion(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+	sd.Spec.Conversion = &core.ConversionSpec{
+		Strategy: core.ConversionStrategyExecutor,
+		Executor: &core.ConversionHandler{ExecutorType: "converter", FunctionName: "convert"},
+	}
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Version = "v1"
+	blueprint.SetSpec("cpu", "500m")
+
+	prepared, err := prepareBlueprint(blueprint, sd)
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", prepared.Version)
+	assert.Equal(t, "500m", prepared.Spec["cpu"])
+}
+
+func TestConvertForRead(t *testing.T) {
+	sd := createTestVersionedBlueprintDefinition("TestKind", "test-colony")
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Version = "v2"
+	blueprint.SetSpec("image", "nginx")
+	blueprint.SetSpec("resources", map[string]interface{}{"cpu": "1"})
+	blueprint.SetSpec("replicas", 1)
+
+	converted, err := convertForRead(blueprint, sd, "v1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", converted.Version)
+	assert.Equal(t, map[string]interface{}{"image": "nginx", "cpu": "1"}, converted.Spec)
+
+	converted, err = convertForRead(blueprint, sd, "")
+	assert.Nil(t, err)
+	assert.Equal(t, blueprint, converted)
+
+	_, err = convertForRead(blueprint, sd, "v3")
+	assert.NotNil(t, err)
+
+	// Blueprints stored in an old version are converted to the storage version
+	old := createTestBlueprint("TestKind", "old", "test-colony", "")
+	old.Version = "v1"
+	old.SetSpec("image", "nginx")
+	old.SetSpec("cpu", "1")
+	converted, err = convertForRead(old, sd, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "v2", converted.Version)
+
+	// Without a definition only the stored version can be read
+	converted, err = convertForRead(old, nil, "")
+	assert.Nil(t, err)
+	assert.Equal(t, old, converted)
+	_, err = convertForRead(old, nil, "v2")
+	assert.NotNil(t, err)
+}
+
+// =============================================
+// Tests for updateBlueprint
+// =============================================
+
+func TestUpdateBlueprint_ResourceVersionMismatch(t *testing.T) {
+	mockServer := &MockServer{
+		blueprintDB: &MockBlueprintDB{},
+	}
+	handlers := NewHandlers(mockServer)
+
+	oldBlueprint := createTestBlueprint("TestKind", "my-blueprint", "test-colony", "")
+	oldBlueprint.Metadata.ResourceVersion = 3
+
+	_, status, err := handlers.updateBlueprint(oldBlueprint, oldBlueprint, false, 2, false, "initiator-123", "update")
+
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Contains(t, err.Error(), "has resource version 3, expected 2")
+
+	// 0 is a resource version like any other, only unconditional writes skip the check
+	_, status, err = handlers.updateBlueprint(oldBlueprint, oldBlueprint, false, 0, false, "initiator-123", "update")
+
+	assert.True(t, errors.Is(err, core.ErrResourceVersionConflict))
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestUpdateBlueprint_DatabaseConflict(t *testing.T) {
+	mockBlueprintDB := &MockBlueprintDB{
+		updateBlueprintErr: fmt.Errorf("%w: modified concurrently", core.ErrResourceVersionConflict),
+	}
+	mockServer := &MockServer{
+		blueprintDB: mockBlueprintDB,
+	}
+	handlers := NewHandlers(mockServer)
+
+	sd := createTestBlueprintDefinition("TestKind", "docker-reconciler", "test-colony")
+	mockBlueprintDB.blueprintDefinitions = append(mockBlueprintDB.blueprintDefinitions, sd)
+
+	oldBlueprint := createTestBlueprint("TestKind", "my-blueprint", "test-colony", "")
+	oldBlueprint.Metadata.ResourceVersion = 3
+
+	_, status, err := handlers.updateBlueprint(oldBlueprint, oldBlueprint, false, 3, false, "initiator-123", "update")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, status)
+
+	mockBlueprintDB.updateBlueprintErr = errors.New("database error")
+	_, status, err = handlers.updateBlueprint(oldBlueprint, oldBlueprint, false, 3, false, "initiator-123", "update")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestUpdateBlueprint_StatusOnlyChangeKeepsGeneration(t *testing.T) {
+	mockBlueprintDB := &MockBlueprintDB{}
+	mockServer := &MockServer{
+		blueprintDB: mockBlueprintDB,
+	}
+	handlers := NewHandlers(mockServer)
+
+	sd := createTestBlueprintDefinition("TestKind", "docker-reconciler", "test-colony")
+	mockBlueprintDB.blueprintDefinitions = append(mockBlueprintDB.blueprintDefinitions, sd)
+
+	oldBlueprint := createTestBlueprint("TestKind", "my-blueprint", "test-colony", "")
+	oldBlueprint.SetSpec("replicas", 1)
+	oldBlueprint.Metadata.ResourceVersion = 3
+
+	patched, err := oldBlueprint.ApplyPatch(core.MergePatchType, map[string]interface{}{
+		"status": map[string]interface{}{"ready": true},
+	})
+	assert.Nil(t, err)
+
+	updated, _, err := handlers.updateBlueprint(patched, oldBlueprint, false, 3, false, "initiator-123", "update")
+
+	assert.Nil(t, err)
+	assert.Equal(t, oldBlueprint.Metadata.Generation, updated.Metadata.Generation)
+	assert.Equal(t, int64(4), updated.Metadata.ResourceVersion)
+	assert.Equal(t, true, updated.Status["ready"])
+	assert.Equal(t, oldBlueprint.ID, updated.ID)
+}
+
+// =============================================
+// Tests for finalizers and owner references
+// =============================================
+
+func createDeletionTestServer() (*MockServer, *MockBlueprintDB) {
+	mockBlueprintDB := &MockBlueprintDB{}
+	mockBlueprintDB.blueprintDefinitions = append(mockBlueprintDB.blueprintDefinitions,
+		createTestBlueprintDefinition("TestKind", "docker-reconciler", "test-colony"))
+
+	mockServer := &MockServer{
+		blueprintDB:       mockBlueprintDB,
+		executorDB:        &MockExecutorDB{},
+		functionDB:        &MockFunctionDB{},
+		userDB:            &MockUserDB{},
+		processController: &MockProcessController{},
+		cronController:    &MockCronController{},
+		cronDB:            &MockCronDB{},
+	}
+
+	return mockServer, mockBlueprintDB
+}
+
+func TestRemoveBlueprint_WithoutFinalizers(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, blueprint)
+
+	executor := core.CreateExecutor("exec-id", "docker", "web-0", "test-colony", time.Now(), time.Now())
+	executor.BlueprintID = blueprint.ID
+	mockServer.executorDB.executors = append(mockServer.executorDB.executors, executor)
+
+	status, err := handlers.removeBlueprint(blueprint, 0, true, "initiator-123")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, mockBlueprintDB.blueprints, 0)
+	assert.Len(t, mockServer.executorDB.executors, 0)
+	assert.Equal(t, []string{"web-0"}, mockServer.functionDB.removedExecutorNames)
+	assert.Len(t, mockServer.processController.processes, 1)
+	assert.Equal(t, "cleanup", mockServer.processController.processes[0].FunctionSpec.FuncName)
+}
+
+func TestRemoveBlueprint_WithFinalizersMarksForDeletion(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.AddFinalizer("docker-reconciler/cleanup")
+	child := createTestBlueprint("TestKind", "web-config", "test-colony", "")
+	child.SetOwner(blueprint)
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, blueprint, child)
+
+	executor := core.CreateExecutor("exec-id", "docker", "web-0", "test-colony", time.Now(), time.Now())
+	executor.BlueprintID = blueprint.ID
+	mockServer.executorDB.executors = append(mockServer.executorDB.executors, executor)
+
+	status, err := handlers.removeBlueprint(blueprint, 0, true, "initiator-123")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, mockBlueprintDB.blueprints, 2)
+	assert.True(t, blueprint.IsBeingDeleted())
+	assert.Len(t, mockServer.executorDB.executors, 1)
+	assert.Len(t, mockServer.functionDB.removedExecutorNames, 0)
+	assert.Len(t, mockServer.processController.processes, 1)
+
+	// Removing again while waiting for finalizers does nothing
+	status, err = handlers.removeBlueprint(blueprint, 0, true, "initiator-123")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, mockServer.processController.processes, 1)
+
+	// Clearing the last finalizer completes the removal
+	updated := createTestBlueprint("TestKind", "web", "test-colony", "")
+	updated.Metadata.ResourceVersion = blueprint.Metadata.ResourceVersion
+	_, status, err = handlers.updateBlueprint(updated, blueprint, false, 0, true, "initiator-123", "update")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, mockBlueprintDB.blueprints, 0)
+	assert.Len(t, mockServer.executorDB.executors, 0)
+	assert.Equal(t, []string{"web-0"}, mockServer.functionDB.removedExecutorNames)
+	assert.Len(t, mockServer.processController.processes, 2)
+}
+
+func TestUpdateBlueprint_CannotAddFinalizerWhileDeleting(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.AddFinalizer("a")
+	blueprint.Metadata.DeletionTimestamp = time.Now()
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, blueprint)
+
+	updated := createTestBlueprint("TestKind", "web", "test-colony", "")
+	updated.AddFinalizer("a")
+	updated.AddFinalizer("b")
+	_, status, err := handlers.updateBlueprint(updated, blueprint, false, 0, true, "initiator-123", "update")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// The deletion timestamp cannot be cleared by an update
+	updated.RemoveFinalizer("b")
+	result, _, err := handlers.updateBlueprint(updated, blueprint, false, 0, true, "initiator-123", "update")
+	assert.Nil(t, err)
+	assert.True(t, result.IsBeingDeleted())
+	assert.Len(t, mockBlueprintDB.blueprints, 1)
+}
+
+func TestRemoveBlueprint_CascadesToDependents(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	parent := createTestBlueprint("TestKind", "parent", "test-colony", "")
+	child := createTestBlueprint("TestKind", "child", "test-colony", "")
+	child.SetOwner(parent)
+	grandchild := createTestBlueprint("TestKind", "grandchild", "test-colony", "")
+	grandchild.SetOwner(child)
+	grandchild.AddFinalizer("a")
+	unrelated := createTestBlueprint("TestKind", "unrelated", "test-colony", "")
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, parent, child, grandchild, unrelated)
+
+	status, err := handlers.removeBlueprint(parent, 0, true, "initiator-123")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, mockBlueprintDB.blueprints, 2)
+	assert.True(t, grandchild.IsBeingDeleted())
+	assert.False(t, unrelated.IsBeingDeleted())
+	assert.Len(t, mockServer.processController.processes, 3)
+}
+
+func TestResolveOwnerReferences(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	owner := createTestBlueprint("TestKind", "owner", "test-colony", "")
+	other := createTestBlueprint("TestKind", "other", "other-colony", "")
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, owner, other)
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{Name: "owner"}}
+	assert.Nil(t, handlers.resolveOwnerReferences(blueprint))
+	assert.Equal(t, core.OwnerReference{Kind: "TestKind", Name: "owner", BlueprintID: owner.ID}, blueprint.Metadata.OwnerReferences[0])
+
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{Name: "missing"}}
+	assert.NotNil(t, handlers.resolveOwnerReferences(blueprint))
+
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{BlueprintID: other.ID}}
+	assert.NotNil(t, handlers.resolveOwnerReferences(blueprint))
+
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{Name: "owner", Kind: "OtherKind"}}
+	assert.NotNil(t, handlers.resolveOwnerReferences(blueprint))
+
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{}}
+	assert.NotNil(t, handlers.resolveOwnerReferences(blueprint))
+
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, blueprint)
+	blueprint.Metadata.OwnerReferences = []core.OwnerReference{{Name: "web"}}
+	assert.NotNil(t, handlers.resolveOwnerReferences(blueprint))
+}
+
+// =============================================
+// Tests for apply
+// =============================================
+
+func TestPlanApply(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	web := createTestBlueprint("TestKind", "web", "test-colony", "")
+	web.SetSpec("image", "nginx:1.0")
+	web.SetStatus("ready", true)
+	web.Metadata.ResourceVersion = 4
+	old := createTestBlueprint("TestKind", "old", "test-colony", "")
+	old.Metadata.Labels = map[string]string{core.ManagedByLabel: "cli"}
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, web, old)
+
+	desiredWeb := createTestBlueprint("TestKind", "web", "", "")
+	desiredWeb.Metadata.Generation = 0
+	desiredWeb.SetSpec("image", "nginx:2.0")
+	child := createTestBlueprint("TestKind", "child", "", "")
+	child.Metadata.OwnerReferences = []core.OwnerReference{{Name: "web"}}
+
+	msg := rpc.CreateApplyBlueprintsMsg("test-colony", []*core.Blueprint{desiredWeb, child})
+	msg.Prune = true
+	msg.ManagedBy = "cli"
+
+	plan, changes, status, err := handlers.planApply(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, plan.Count(core.ApplyUpdate))
+	assert.Equal(t, 1, plan.Count(core.ApplyCreate))
+	assert.Equal(t, 1, plan.Count(core.ApplyDelete))
+
+	// Updates keep the ID, status and resource version of the stored blueprint
+	assert.Len(t, changes.updates, 1)
+	updated := changes.updates[0]
+	assert.Equal(t, web.ID, updated.ID)
+	assert.Equal(t, true, updated.Status["ready"])
+	assert.Equal(t, int64(4), updated.Metadata.ResourceVersion)
+	assert.Equal(t, web.Metadata.Generation+1, updated.Metadata.Generation)
+	assert.Equal(t, "cli", updated.Metadata.Labels[core.ManagedByLabel])
+	assert.True(t, changes.specChanged["web"])
+
+	// Owners can be resolved among the applied blueprints
+	assert.Len(t, changes.creates, 1)
+	assert.Equal(t, web.ID, changes.creates[0].Metadata.OwnerReferences[0].BlueprintID)
+
+	assert.Len(t, changes.removes, 1)
+	assert.Equal(t, old.ID, changes.removes[0].ID)
+}
+
+func TestPlanApply_Invalid(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+
+	existing := createTestBlueprint("OtherKind", "web", "test-colony", "")
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, existing)
+
+	tests := []struct {
+		name       string
+		blueprints []*core.Blueprint
+		status     int
+	}{
+		{"unknown kind", []*core.Blueprint{createTestBlueprint("Unknown", "a", "test-colony", "")}, http.StatusBadRequest},
+		{"other colony", []*core.Blueprint{createTestBlueprint("TestKind", "a", "other-colony", "")}, http.StatusBadRequest},
+		{"duplicate", []*core.Blueprint{createTestBlueprint("TestKind", "a", "", ""), createTestBlueprint("TestKind", "a", "", "")}, http.StatusBadRequest},
+		{"missing name", []*core.Blueprint{createTestBlueprint("TestKind", "", "", "")}, http.StatusBadRequest},
+		{"kind changed", []*core.Blueprint{createTestBlueprint("TestKind", "web", "", "")}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := rpc.CreateApplyBlueprintsMsg("test-colony", tt.blueprints)
+			_, _, status, err := handlers.planApply(msg)
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.status, status)
+		})
+	}
+
+	existing.Kind = "TestKind"
+	existing.Metadata.DeletionTimestamp = time.Now()
+	msg := rpc.CreateApplyBlueprintsMsg("test-colony", []*core.Blueprint{createTestBlueprint("TestKind", "web", "", "")})
+	_, _, status, err := handlers.planApply(msg)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestExecuteApply(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+	initiator := core.CreateExecutor("initiator-123", "cli", "cli", "test-colony", time.Now(), time.Now())
+	mockServer.executorDB.executors = append(mockServer.executorDB.executors, initiator)
+
+	web := createTestBlueprint("TestKind", "web", "test-colony", "")
+	web.SetSpec("image", "nginx:1.0")
+	old := createTestBlueprint("TestKind", "old", "test-colony", "")
+	old.Metadata.Labels = map[string]string{core.ManagedByLabel: "cli"}
+	kept := createTestBlueprint("TestKind", "kept", "test-colony", "")
+	kept.Metadata.Labels = map[string]string{core.ManagedByLabel: "cli"}
+	kept.AddFinalizer("a")
+	mockBlueprintDB.blueprints = append(mockBlueprintDB.blueprints, web, old, kept)
+
+	desiredWeb := createTestBlueprint("TestKind", "web", "", "")
+	desiredWeb.SetSpec("image", "nginx:2.0")
+	msg := rpc.CreateApplyBlueprintsMsg("test-colony", []*core.Blueprint{desiredWeb, createTestBlueprint("TestKind", "cache", "", "")})
+	msg.Prune = true
+	msg.ManagedBy = "cli"
+
+	_, changes, _, err := handlers.planApply(msg)
+	assert.Nil(t, err)
+
+	status, err := handlers.executeApply(changes, "initiator-123")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	names := []string{}
+	for _, bp := range mockBlueprintDB.blueprints {
+		names = append(names, bp.Metadata.Name)
+	}
+	assert.ElementsMatch(t, []string{"web", "cache", "kept"}, names)
+
+	markedKept, _ := mockBlueprintDB.GetBlueprintByName("test-colony", "kept")
+	assert.True(t, markedKept.IsBeingDeleted())
+	updatedWeb, _ := mockBlueprintDB.GetBlueprintByName("test-colony", "web")
+	assert.Equal(t, "nginx:2.0", updatedWeb.Spec["image"])
+
+	// Reconciliation for the created and updated blueprint, cleanup for the two pruned blueprints
+	assert.Len(t, mockServer.processController.processes, 4)
+	assert.Len(t, mockServer.cronController.crons, 1)
+
+	// Nothing is reconciled if the transaction fails
+	mockServer.processController.processes = nil
+	mockBlueprintDB.applyBlueprintsErr = fmt.Errorf("%w: modified", core.ErrResourceVersionConflict)
+	status, err = handlers.executeApply(changes, "initiator-123")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Len(t, mockServer.processController.processes, 0)
+}
+
+func TestRollbackBlueprint_RestoresHistoricalSpec(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+	sd := mockBlueprintDB.blueprintDefinitions[0]
+
+	original := createTestBlueprint("TestKind", "web", "test-colony", "")
+	original.Spec = map[string]interface{}{"replicas": 1}
+	original.Metadata.Generation = 1
+	err := handlers.addHistory(original, sd, "initiator-123", "create")
+	assert.Nil(t, err)
+
+	current := createTestBlueprint("TestKind", "web", "test-colony", "")
+	current.ID = original.ID
+	current.Spec = map[string]interface{}{"replicas": 2}
+	current.Metadata.Generation = 2
+	current.Metadata.ResourceVersion = 2
+	err = handlers.addHistory(current, sd, "initiator-123", "update")
+	assert.Nil(t, err)
+
+	result, status, err := handlers.rollbackBlueprint(current, 1, "initiator-123")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.EqualValues(t, 1, result.Spec["replicas"])
+	assert.Equal(t, int64(3), result.Metadata.Generation)
+
+	histories, err := mockBlueprintDB.GetBlueprintHistory(original.ID, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 3)
+	assert.Equal(t, "rollback", histories[0].ChangeType)
+	assert.Equal(t, int64(3), histories[0].Generation)
+
+	_, status, err = handlers.rollbackBlueprint(current, 2, "initiator-123")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, status, err = handlers.rollbackBlueprint(current, 99, "initiator-123")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	current.Metadata.DeletionTimestamp = time.Now()
+	_, status, err = handlers.rollbackBlueprint(current, 1, "initiator-123")
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusConflict, status)
+}
+
+func TestAddHistory_PrunesToLimit(t *testing.T) {
+	mockServer, mockBlueprintDB := createDeletionTestServer()
+	handlers := NewHandlers(mockServer)
+	sd := mockBlueprintDB.blueprintDefinitions[0]
+	sd.Spec.HistoryLimit = 2
+
+	blueprint := createTestBlueprint("TestKind", "web", "test-colony", "")
+	other := createTestBlueprint("TestKind", "other", "test-colony", "")
+	assert.Nil(t, handlers.addHistory(other, sd, "initiator-123", "create"))
+
+	for generation := int64(1); generation <= 3; generation++ {
+		blueprint.Metadata.Generation = generation
+		assert.Nil(t, handlers.addHistory(blueprint, sd, "initiator-123", "update"))
+	}
+
+	histories, err := mockBlueprintDB.GetBlueprintHistory(blueprint.ID, 0)
+	assert.Nil(t, err)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, int64(3), histories[0].Generation)
+	assert.Equal(t, int64(2), histories[1].Generation)
+
+	otherHistories, err := mockBlueprintDB.GetBlueprintHistory(other.ID, 0)
+	assert.Nil(t, err)
+	assert.Len(t, otherHistories, 1)
+}
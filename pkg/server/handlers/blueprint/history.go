@@ -0,0 +1,129 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+// addHistory saves a snapshot of a blueprint, and removes the oldest snapshots beyond the history limit
+// of its BlueprintDefinition (best-effort)
+func (h *Handlers) addHistory(blueprint *core.Blueprint, sd *core.BlueprintDefinition, changedBy string, changeType string) error {
+	history := core.CreateBlueprintHistory(blueprint, changedBy, changeType)
+	if err := h.server.BlueprintDB().AddBlueprintHistory(history); err != nil {
+		return err
+	}
+
+	if sd == nil || sd.Spec.HistoryLimit <= 0 {
+		return nil
+	}
+
+	if err := h.server.BlueprintDB().PruneBlueprintHistory(blueprint.ID, sd.Spec.HistoryLimit); err != nil {
+		log.WithFields(log.Fields{
+			"Error":         err,
+			"BlueprintName": blueprint.Metadata.Name,
+			"HistoryLimit":  sd.Spec.HistoryLimit,
+		}).Warn("Failed to prune blueprint history")
+	}
+
+	return nil
+}
+
+// HandleRollbackBlueprint restores the spec of a previous generation of a blueprint
+func (h *Handlers) HandleRollbackBlueprint(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRollbackBlueprintMsgFromJSON(jsonString)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to rollback blueprint, invalid JSON"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to rollback blueprint, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	// Require membership or colony owner to rollback blueprints
+	err = h.server.Validator().RequireMembership(recoveredID, msg.Namespace, true)
+	if err != nil {
+		// If not a member, check if colony owner
+		err = h.server.Validator().RequireColonyOwner(recoveredID, msg.Namespace)
+		if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+			return
+		}
+	}
+
+	oldBlueprint, err := h.server.BlueprintDB().GetBlueprintByName(msg.Namespace, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if oldBlueprint == nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("blueprint '%s' not found in namespace '%s'", msg.Name, msg.Namespace), http.StatusNotFound)
+		return
+	}
+
+	blueprint, status, err := h.rollbackBlueprint(oldBlueprint, msg.Generation, recoveredID)
+	if h.server.HandleHTTPError(c, err, status) {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"Namespace":      msg.Namespace,
+		"Name":           msg.Name,
+		"FromGeneration": msg.Generation,
+		"Generation":     blueprint.Metadata.Generation,
+	}).Debug("Rolling back blueprint")
+
+	jsonString, err = blueprint.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// rollbackBlueprint re-applies the spec saved in the history of a previous generation. The spec is
+// written as a new generation, so it is validated against the current schema and reconciled like any
+// other update. Metadata is not part of the history and is kept as it is.
+func (h *Handlers) rollbackBlueprint(oldBlueprint *core.Blueprint, generation int64, recoveredID string) (*core.Blueprint, int, error) {
+	if generation <= 0 {
+		return nil, http.StatusBadRequest, errors.New("generation must be greater than 0")
+	}
+
+	if generation == oldBlueprint.Metadata.Generation {
+		return nil, http.StatusBadRequest, fmt.Errorf("blueprint '%s' is already at generation %d", oldBlueprint.Metadata.Name, generation)
+	}
+
+	if oldBlueprint.IsBeingDeleted() {
+		return nil, http.StatusConflict, fmt.Errorf("blueprint '%s' is being deleted", oldBlueprint.Metadata.Name)
+	}
+
+	history, err := h.server.BlueprintDB().GetBlueprintHistoryByGeneration(oldBlueprint.ID, generation)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if history == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("generation %d of blueprint '%s' not found in history", generation, oldBlueprint.Metadata.Name)
+	}
+
+	jsonString, err := oldBlueprint.ToJSON()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	blueprint, err := core.ConvertJSONToBlueprint(jsonString)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	blueprint.Spec = history.Spec
+
+	// The generation is always bumped, also when the spec is the same as the current one, so the
+	// rollback is recorded in the history and reconciled
+	return h.updateBlueprint(blueprint, oldBlueprint, true, oldBlueprint.Metadata.ResourceVersion, false, recoveredID, "rollback")
+}
@@ -0,0 +1,393 @@
+package blueprint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+// validateBlueprintDefinition validates the schema and versions of a BlueprintDefinition, and keeps
+// spec.version in sync with the storage version
+func validateBlueprintDefinition(sd *core.BlueprintDefinition) error {
+	if sd.Spec.Schema != nil {
+		if err := sd.Spec.Schema.Check(); err != nil {
+			return fmt.Errorf("invalid schema: %v", err)
+		}
+	}
+
+	if err := sd.ValidateVersions(); err != nil {
+		return err
+	}
+
+	if len(sd.Spec.Versions) > 0 {
+		sd.Spec.Version = sd.StorageVersion()
+	}
+
+	return nil
+}
+
+// findBlueprintDefinition returns the BlueprintDefinition for a kind in a colony, or nil if none exists
+func (h *Handlers) findBlueprintDefinition(colonyName string, kind string) (*core.BlueprintDefinition, error) {
+	sds, err := h.server.BlueprintDB().GetBlueprintDefinitionsByNamespace(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sd := range sds {
+		if sd.Spec.Names.Kind == kind {
+			return sd, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// prepareBlueprint resolves the version of a blueprint that is about to be written, applies schema
+// defaults, validates it, and converts it to the storage version if the server can
+func prepareBlueprint(blueprint *core.Blueprint, sd *core.BlueprintDefinition) (*core.Blueprint, error) {
+	version := sd.GetVersion(blueprint.Version)
+	if version == nil || !version.Served {
+		return nil, fmt.Errorf("version '%s' is not served by BlueprintDefinition '%s'", blueprint.Version, sd.Metadata.Name)
+	}
+	blueprint.Version = version.Name
+
+	if version.Schema != nil {
+		blueprint.Spec = core.ApplySchemaDefaults(blueprint.Spec, version.Schema)
+		if err := core.ValidateBlueprintAgainstSchema(blueprint, version.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	storageVersion := sd.StorageVersion()
+	if blueprint.Version == storageVersion || !sd.CanConvert(blueprint.Version, storageVersion) {
+		return blueprint, nil
+	}
+
+	converted, err := sd.ConvertBlueprint(blueprint, storageVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema := sd.SchemaForVersion(storageVersion); schema != nil {
+		converted.Spec = core.ApplySchemaDefaults(converted.Spec, schema)
+		if err := core.ValidateBlueprintAgainstSchema(converted, schema); err != nil {
+			return nil, fmt.Errorf("after conversion to version '%s': %v", storageVersion, err)
+		}
+	}
+
+	return converted, nil
+}
+
+// convertForRead converts a blueprint to the requested version. If no version is requested, blueprints
+// are converted to the storage version when the server can, otherwise they are returned as stored.
+func convertForRead(blueprint *core.Blueprint, sd *core.BlueprintDefinition, version string) (*core.Blueprint, error) {
+	if sd == nil {
+		if version != "" && version != blueprint.Version {
+			return nil, fmt.Errorf("BlueprintDefinition for kind '%s' not found", blueprint.Kind)
+		}
+		return blueprint, nil
+	}
+
+	if version != "" {
+		if v := sd.GetVersion(version); v == nil || !v.Served {
+			return nil, fmt.Errorf("version '%s' is not served by BlueprintDefinition '%s'", version, sd.Metadata.Name)
+		}
+	}
+
+	target := version
+	if target == "" {
+		target = sd.StorageVersion()
+	}
+
+	from := blueprint.Version
+	if from == "" {
+		from = sd.StorageVersion()
+	}
+
+	if from == target || (version == "" && !sd.CanConvert(from, target)) {
+		return blueprint, nil
+	}
+
+	return sd.ConvertBlueprint(blueprint, target)
+}
+
+// HandleUpdateBlueprintDefinition replaces a BlueprintDefinition, e.g. to add a version or change the
+// storage version. Existing blueprints are not touched, use migrate to convert them.
+func (h *Handlers) HandleUpdateBlueprintDefinition(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateUpdateBlueprintDefinitionMsgFromJSON(jsonString)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to update blueprint definition, invalid JSON"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to update blueprint definition, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.BlueprintDefinition == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to update blueprint definition, blueprint definition is nil"), http.StatusBadRequest)
+		return
+	}
+
+	sd := msg.BlueprintDefinition
+	colonyName := sd.Metadata.ColonyName
+
+	// Only colony owner can update BlueprintDefinitions
+	err = h.server.Validator().RequireColonyOwner(recoveredID, colonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	oldSD, err := h.server.BlueprintDB().GetBlueprintDefinitionByName(colonyName, sd.Metadata.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if oldSD == nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("BlueprintDefinition '%s' not found in namespace '%s'", sd.Metadata.Name, colonyName), http.StatusNotFound)
+		return
+	}
+
+	if sd.Spec.Names.Kind != oldSD.Spec.Names.Kind {
+		h.server.HandleHTTPError(c, fmt.Errorf("Failed to update blueprint definition, kind cannot be changed from '%s' to '%s'", oldSD.Spec.Names.Kind, sd.Spec.Names.Kind), http.StatusBadRequest)
+		return
+	}
+
+	if err := validateBlueprintDefinition(sd); err != nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("Failed to update blueprint definition, %v", err), http.StatusBadRequest)
+		return
+	}
+
+	blueprints, err := h.server.BlueprintDB().GetBlueprintsByNamespaceAndKind(colonyName, oldSD.Spec.Names.Kind)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	// Versions that blueprints are still stored in cannot be removed
+	oldStorageVersion := oldSD.StorageVersion()
+	for _, blueprint := range blueprints {
+		version := blueprint.Version
+		if version == "" {
+			version = oldStorageVersion
+		}
+		if sd.GetVersion(version) == nil {
+			h.server.HandleHTTPError(c, fmt.Errorf("Failed to update blueprint definition, version '%s' is still used by blueprint '%s', migrate it first", version, blueprint.Metadata.Name), http.StatusConflict)
+			return
+		}
+	}
+
+	// Blueprints written before versioning have no version, pin them to the old storage version so
+	// they are not mistaken for the new one
+	if sd.StorageVersion() != oldStorageVersion {
+		for _, blueprint := range blueprints {
+			if blueprint.Version != "" {
+				continue
+			}
+			blueprint.Version = oldStorageVersion
+			if err := h.server.BlueprintDB().UpdateBlueprint(blueprint); err != nil {
+				h.server.HandleHTTPError(c, err, http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	sd.ID = oldSD.ID
+	sd.Metadata.CreatedAt = oldSD.Metadata.CreatedAt
+	sd.Metadata.Generation = oldSD.Metadata.Generation + 1
+
+	err = h.server.BlueprintDB().UpdateBlueprintDefinition(sd)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"ID":             sd.ID,
+		"Name":           sd.Metadata.Name,
+		"ColonyName":     colonyName,
+		"StorageVersion": sd.StorageVersion(),
+	}).Debug("Updating blueprint definition")
+
+	jsonString, err = sd.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// HandleMigrateBlueprintDefinition migrates all blueprints of a kind to the storage version and
+// reports the blueprints that fail its schema. With dry run nothing is written.
+func (h *Handlers) HandleMigrateBlueprintDefinition(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateMigrateBlueprintDefinitionMsgFromJSON(jsonString)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to migrate blueprint definition, invalid JSON"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to migrate blueprint definition, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	// Only colony owner can migrate BlueprintDefinitions
+	err = h.server.Validator().RequireColonyOwner(recoveredID, msg.Namespace)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	sd, err := h.server.BlueprintDB().GetBlueprintDefinitionByName(msg.Namespace, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if sd == nil {
+		h.server.HandleHTTPError(c, fmt.Errorf("BlueprintDefinition '%s' not found in namespace '%s'", msg.Name, msg.Namespace), http.StatusNotFound)
+		return
+	}
+
+	blueprints, err := h.server.BlueprintDB().GetBlueprintsByNamespaceAndKind(msg.Namespace, sd.Spec.Names.Kind)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	sort.Slice(blueprints, func(i, j int) bool {
+		return blueprints[i].Metadata.Name < blueprints[j].Metadata.Name
+	})
+
+	report := &core.BlueprintMigrationReport{
+		Kind:           sd.Spec.Names.Kind,
+		StorageVersion: sd.StorageVersion(),
+		DryRun:         msg.DryRun,
+		Results:        make([]*core.BlueprintMigrationResult, 0, len(blueprints)),
+	}
+
+	for _, blueprint := range blueprints {
+		result, err := h.migrateBlueprint(blueprint, sd, msg.DryRun, recoveredID)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	log.WithFields(log.Fields{
+		"Namespace":      msg.Namespace,
+		"Name":           msg.Name,
+		"StorageVersion": report.StorageVersion,
+		"DryRun":         msg.DryRun,
+		"Count":          len(report.Results),
+	}).Debug("Migrating blueprints")
+
+	jsonString, err = report.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// migrateBlueprint migrates a single blueprint, validation failures are reported in the result while
+// the returned error is reserved for database failures
+func (h *Handlers) migrateBlueprint(blueprint *core.Blueprint, sd *core.BlueprintDefinition, dryRun bool, recoveredID string) (*core.BlueprintMigrationResult, error) {
+	storageVersion := sd.StorageVersion()
+	fromVersion := blueprint.Version
+	if fromVersion == "" {
+		fromVersion = storageVersion
+	}
+
+	result := &core.BlueprintMigrationResult{
+		Name:        blueprint.Metadata.Name,
+		FromVersion: fromVersion,
+		ToVersion:   storageVersion,
+	}
+
+	// Conversions done by an executor are submitted as processes, the executor writes back the result
+	if fromVersion != storageVersion && sd.ConversionStrategy() == core.ConversionStrategyExecutor {
+		result.Status = core.MigrationPending
+		if dryRun {
+			return result, nil
+		}
+
+		process, err := h.createConversionProcess(blueprint, sd, fromVersion, recoveredID)
+		if err != nil {
+			return nil, err
+		}
+		result.ProcessID = process.ID
+
+		return result, nil
+	}
+
+	migrated := blueprint
+	if fromVersion != storageVersion && sd.CanConvert(fromVersion, storageVersion) {
+		converted, err := sd.ConvertBlueprint(blueprint, storageVersion)
+		if err != nil {
+			result.Status = core.MigrationFailed
+			result.Error = err.Error()
+			return result, nil
+		}
+		migrated = converted
+	}
+
+	// Blueprints that cannot be converted are checked against the storage schema as they are
+	spec := migrated.Spec
+	if schema := sd.SchemaForVersion(storageVersion); schema != nil {
+		spec = core.ApplySchemaDefaults(spec, schema)
+		if err := core.ValidateSpec(spec, schema); err != nil {
+			result.Status = core.MigrationFailed
+			result.Error = err.Error()
+			return result, nil
+		}
+	}
+
+	if fromVersion == storageVersion && blueprint.Version != "" {
+		result.Status = core.MigrationUnchanged
+		return result, nil
+	}
+
+	result.Status = core.MigrationMigrated
+	if dryRun {
+		return result, nil
+	}
+
+	updated := *migrated
+	updated.Spec = spec
+	updated.Version = storageVersion
+	if err := h.server.BlueprintDB().UpdateBlueprint(&updated); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// createConversionProcess submits a process asking the conversion executor to convert a blueprint
+func (h *Handlers) createConversionProcess(blueprint *core.Blueprint, sd *core.BlueprintDefinition, fromVersion string, recoveredID string) (*core.Process, error) {
+	initiatorName, err := h.resolveInitiator(blueprint.Metadata.ColonyName, recoveredID)
+	if err != nil {
+		return nil, err
+	}
+
+	executor := sd.Spec.Conversion.Executor
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.NodeName = fmt.Sprintf("%s-%s", executor.ExecutorType, executor.FunctionName)
+	funcSpec.Conditions.ColonyName = blueprint.Metadata.ColonyName
+	funcSpec.Conditions.ExecutorType = executor.ExecutorType
+	funcSpec.FuncName = executor.FunctionName
+	funcSpec.KwArgs = map[string]interface{}{
+		"kind":          blueprint.Kind,
+		"blueprintName": blueprint.Metadata.Name,
+		"fromVersion":   fromVersion,
+		"toVersion":     sd.StorageVersion(),
+	}
+
+	process := core.CreateProcess(funcSpec)
+	process.InitiatorID = recoveredID
+	process.InitiatorName = initiatorName
+
+	return h.server.ProcessController().AddProcess(process)
+}
@@ -180,6 +180,7 @@ func (m *MockProcessDB) SetWaitForParents(processID string, waiting bool) error
 func (m *MockProcessDB) Assign(executorID string, process *core.Process) error       { return nil }
 func (m *MockProcessDB) SelectAndAssign(colonyName, executorID, executorName, executorType, executorLocation string, cpu, memory, storage int64, nodes, processes, processesPerNode, count int) (*core.Process, error) { return nil, nil }
 func (m *MockProcessDB) Unassign(process *core.Process) error                        { return nil }
+func (m *MockProcessDB) Release(process *core.Process) error                         { return nil }
 func (m *MockProcessDB) MarkSuccessful(processID string) (float64, float64, error)   { return 0, 0, nil }
 func (m *MockProcessDB) MarkFailed(processID string, errs []string) error            { return nil }
 func (m *MockProcessDB) CountProcesses() (int, error)                                { return 0, nil }
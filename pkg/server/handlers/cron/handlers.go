@@ -25,6 +25,9 @@ type Server interface {
 		AddCron(cron *core.Cron) (*core.Cron, error)
 		RunCron(cronID string) (*core.Cron, error)
 		RemoveCron(cronID string) error
+		SuspendCron(cronID string) (*core.Cron, error)
+		ResumeCron(cronID string) (*core.Cron, error)
+		GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 		GetCronPeriod() int
 	}
 	ExecutorDB() database.ExecutorDatabase
@@ -56,6 +59,12 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.RemoveCronPayloadType, h.HandleRemoveCron); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.SuspendCronPayloadType, h.HandleSuspendCron); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.ResumeCronPayloadType, h.HandleResumeCron); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -132,6 +141,16 @@ func (h *Handlers) HandleAddCron(c backends.Context, recoveredID string, payload
 		}
 	}
 
+	err = msg.Cron.ValidateSchedule()
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	err = msg.Cron.ValidateConcurrency()
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	msg.Cron.ID = core.GenerateRandomID()
 	msg.Cron.InitiatorID = recoveredID
 
@@ -191,6 +210,11 @@ func (h *Handlers) HandleGetCron(c backends.Context, recoveredID string, payload
 
 	cron.CheckerPeriod = h.server.CronController().GetCronPeriod()
 
+	cron.Runs, err = h.server.CronController().GetCronRuns(cron)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
 	jsonString, err = cron.ToJSON()
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
@@ -311,4 +335,74 @@ func (h *Handlers) HandleRemoveCron(c backends.Context, recoveredID string, payl
 	log.WithFields(log.Fields{"CronId": cron.ID}).Debug("Removing cron")
 
 	h.server.SendEmptyHTTPReply(c, payloadType)
-}
\ No newline at end of file
+}
+
+func (h *Handlers) HandleSuspendCron(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateSuspendCronMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to suspend cron, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to suspend cron, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	h.setCronSuspended(c, recoveredID, payloadType, msg.CronID, true)
+}
+
+func (h *Handlers) HandleResumeCron(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateResumeCronMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resume cron, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to resume cron, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	h.setCronSuspended(c, recoveredID, payloadType, msg.CronID, false)
+}
+
+func (h *Handlers) setCronSuspended(c backends.Context, recoveredID string, payloadType string, cronID string, suspended bool) {
+	cron, err := h.server.CronDB().GetCronByID(cronID)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if cron == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to suspend or resume cron, cron not found"), http.StatusNotFound)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, cron.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if suspended {
+		cron, err = h.server.CronController().SuspendCron(cron.ID)
+	} else {
+		cron, err = h.server.CronController().ResumeCron(cron.ID)
+	}
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+	if cron == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to suspend or resume cron, cron is nil"), http.StatusInternalServerError)
+		return
+	}
+
+	jsonString, err := cron.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"CronId": cron.ID, "Suspended": suspended}).Debug("Suspending or resuming cron")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -484,6 +485,95 @@ func TestRunCronWaitForPrevProcessGraph(t *testing.T) {
 }
 
 // TestAddCronWithUserAsInitiator tests that a user can create a cron (covers resolveInitiator user path)
+func TestRunCronReplace(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	cron := utils.FakeSingleCron(t, env.ColonyName, env.ExecutorID, env.ExecutorName)
+	cron.Interval = 1000 // Long interval so it won't trigger automatically
+	cron.ConcurrencyPolicy = core.CronConcurrencyReplace
+
+	addedCron, err := client.AddCron(cron, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedCron)
+
+	_, err = client.RunCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	// The second run cancels the first run
+	_, err = client.RunCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	processes, err := client.GetWaitingProcesses(env.ColonyName, "", "", "", 100, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 1)
+
+	cronFromServer, err := client.GetCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, cronFromServer.Runs, 2)
+	assert.Equal(t, core.WAITING, cronFromServer.Runs[0].State)
+	assert.Equal(t, core.CANCELLED, cronFromServer.Runs[1].State)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestCronRunHistoryLimit(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	cron := utils.FakeSingleCron(t, env.ColonyName, env.ExecutorID, env.ExecutorName)
+	cron.Interval = 1000 // Long interval so it won't trigger automatically
+	cron.RunHistoryLimit = 2
+
+	addedCron, err := client.AddCron(cron, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedCron)
+
+	for i := 0; i < 3; i++ {
+		_, err = client.RunCron(addedCron.ID, env.ExecutorPrvKey)
+		assert.Nil(t, err)
+	}
+
+	cronFromServer, err := client.GetCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, cronFromServer.Runs, 2)
+	assert.Equal(t, cronFromServer.PrevProcessGraphID, cronFromServer.Runs[0].ProcessGraphID)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestSuspendResumeCron(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	cron := utils.FakeCron(t, env.ColonyName, env.ExecutorID, env.ExecutorName)
+	cron.Interval = 1
+
+	addedCron, err := client.AddCron(cron, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedCron)
+
+	suspendedCron, err := client.SuspendCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, suspendedCron.Suspended)
+
+	// A suspended cron is not triggered
+	time.Sleep(3 * time.Second)
+	processes, err := client.GetWaitingProcesses(env.ColonyName, "", "", "", 100, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, processes, 0)
+
+	resumedCron, err := client.ResumeCron(addedCron.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.False(t, resumedCron.Suspended)
+
+	process, err := client.Assign(env.ColonyName, 10, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, process)
+
+	server.Shutdown()
+	<-done
+}
+
 func TestAddCronWithUserAsInitiator(t *testing.T) {
 	env, client, server, _, done := server.SetupTestEnv2(t)
 
@@ -92,12 +92,30 @@ func (m *MockCronDB) RemoveAllCronsByColonyName(colonyName string) error {
 	return nil
 }
 
+func (m *MockCronDB) SetCronSuspended(cronID string, suspended bool) error {
+	return nil
+}
+
+func (m *MockCronDB) AddCronRun(run *core.CronRun) error {
+	return nil
+}
+
+func (m *MockCronDB) GetCronRuns(cronID string, count int) ([]*core.CronRun, error) {
+	return []*core.CronRun{}, nil
+}
+
+func (m *MockCronDB) PruneCronRuns(cronID string, keep int) error {
+	return nil
+}
+
 // MockCronController implements the CronController interface
 type MockCronController struct {
 	crons          []*core.Cron
 	addCronErr     error
 	runCronErr     error
 	removeCronErr  error
+	suspendErr     error
+	runs           []*core.CronRun
 	cronPeriod     int
 	returnNilOnAdd bool
 	returnNilOnRun bool
@@ -136,6 +154,31 @@ func (m *MockCronController) RemoveCron(cronID string) error {
 	return nil
 }
 
+func (m *MockCronController) SuspendCron(cronID string) (*core.Cron, error) {
+	return m.setSuspended(cronID, true)
+}
+
+func (m *MockCronController) ResumeCron(cronID string) (*core.Cron, error) {
+	return m.setSuspended(cronID, false)
+}
+
+func (m *MockCronController) setSuspended(cronID string, suspended bool) (*core.Cron, error) {
+	if m.suspendErr != nil {
+		return nil, m.suspendErr
+	}
+	for _, c := range m.crons {
+		if c.ID == cronID {
+			c.Suspended = suspended
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockCronController) GetCronRuns(cron *core.Cron) ([]*core.CronRun, error) {
+	return m.runs, nil
+}
+
 func (m *MockCronController) GetCronPeriod() int {
 	return m.cronPeriod
 }
@@ -324,6 +367,9 @@ func (m *MockServer) CronController() interface {
 	AddCron(cron *core.Cron) (*core.Cron, error)
 	RunCron(cronID string) (*core.Cron, error)
 	RemoveCron(cronID string) error
+	SuspendCron(cronID string) (*core.Cron, error)
+	ResumeCron(cronID string) (*core.Cron, error)
+	GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 	GetCronPeriod() int
 } {
 	return m.cronController
@@ -481,6 +527,22 @@ func TestHandleAddCron_ZeroInterval(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
 }
 
+func TestHandleAddCron_InvalidConcurrency(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	cron := createTestCron()
+	cron.ConcurrencyPolicy = core.CronConcurrencyForbid
+	cron.MaxConcurrentRuns = 2
+	msg := rpc.CreateAddCronMsg(cron)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleAddCron(ctx, "executor-123", rpc.AddCronPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
 func TestHandleAddCron_CronControllerError(t *testing.T) {
 	mockServer := createMockServer()
 	mockServer.cronController.addCronErr = errors.New("controller error")
@@ -564,6 +626,30 @@ func TestHandleGetCron_Success(t *testing.T) {
 	assert.NotEmpty(t, mockServer.replyPayload)
 }
 
+func TestHandleGetCron_IncludesRuns(t *testing.T) {
+	mockServer := createMockServer()
+	cron := createTestCron()
+	mockServer.cronDB.crons = []*core.Cron{cron}
+	mockServer.cronController.runs = []*core.CronRun{
+		{CronID: cron.ID, ProcessGraphID: "graph-2", State: core.RUNNING},
+		{CronID: cron.ID, ProcessGraphID: "graph-1", State: core.SUCCESS},
+	}
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateGetCronMsg(cron.ID)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleGetCron(ctx, "executor-123", rpc.GetCronPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+	cronFromReply, err := core.ConvertJSONToCron(mockServer.replyPayload)
+	assert.Nil(t, err)
+	assert.Len(t, cronFromReply.Runs, 2)
+	assert.Equal(t, "graph-2", cronFromReply.Runs[0].ProcessGraphID)
+	assert.Equal(t, core.SUCCESS, cronFromReply.Runs[1].State)
+}
+
 func TestHandleGetCron_InvalidJSON(t *testing.T) {
 	mockServer := createMockServer()
 	handlers := NewHandlers(mockServer)
@@ -901,6 +987,95 @@ func TestHandleRemoveCron_ControllerError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, mockServer.httpErrorCode)
 }
 
+func TestHandleSuspendResumeCron_Success(t *testing.T) {
+	mockServer := createMockServer()
+	cron := createTestCron()
+	mockServer.cronDB.crons = []*core.Cron{cron}
+	mockServer.cronController.crons = []*core.Cron{cron}
+	handlers := NewHandlers(mockServer)
+
+	suspendMsg := rpc.CreateSuspendCronMsg(cron.ID)
+	jsonStr, _ := suspendMsg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSuspendCron(ctx, "executor-123", rpc.SuspendCronPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+	assert.Equal(t, rpc.SuspendCronPayloadType, mockServer.replyType)
+	cronFromReply, err := core.ConvertJSONToCron(mockServer.replyPayload)
+	assert.Nil(t, err)
+	assert.True(t, cronFromReply.Suspended)
+
+	resumeMsg := rpc.CreateResumeCronMsg(cron.ID)
+	jsonStr, _ = resumeMsg.ToJSON()
+
+	handlers.HandleResumeCron(ctx, "executor-123", rpc.ResumeCronPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+	assert.Equal(t, rpc.ResumeCronPayloadType, mockServer.replyType)
+	cronFromReply, err = core.ConvertJSONToCron(mockServer.replyPayload)
+	assert.Nil(t, err)
+	assert.False(t, cronFromReply.Suspended)
+}
+
+func TestHandleSuspendCron_MsgTypeMismatch(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateSuspendCronMsg("cron-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSuspendCron(ctx, "executor-123", rpc.ResumeCronPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
+func TestHandleSuspendCron_NotFound(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateSuspendCronMsg("non-existent")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSuspendCron(ctx, "executor-123", rpc.SuspendCronPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusNotFound, mockServer.httpErrorCode)
+}
+
+func TestHandleResumeCron_AuthError(t *testing.T) {
+	mockServer := createMockServer()
+	cron := createTestCron()
+	mockServer.cronDB.crons = []*core.Cron{cron}
+	mockServer.validator.requireMembershipErr = errors.New("not a member")
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateResumeCronMsg(cron.ID)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleResumeCron(ctx, "executor-123", rpc.ResumeCronPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusForbidden, mockServer.httpErrorCode)
+}
+
+func TestHandleSuspendCron_ControllerError(t *testing.T) {
+	mockServer := createMockServer()
+	cron := createTestCron()
+	mockServer.cronDB.crons = []*core.Cron{cron}
+	mockServer.cronController.suspendErr = errors.New("controller error")
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateSuspendCronMsg(cron.ID)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSuspendCron(ctx, "executor-123", rpc.SuspendCronPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusInternalServerError, mockServer.httpErrorCode)
+}
+
 // Handler registration tests
 func TestRegisterHandlers_Success(t *testing.T) {
 	mockServer := createMockServer()
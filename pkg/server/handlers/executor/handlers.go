@@ -62,6 +62,9 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.UpdateExecutorPayloadType, h.HandleUpdateExecutor); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.ExecutorHeartbeatPayloadType, h.HandleExecutorHeartbeat); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -411,6 +414,50 @@ func (h *Handlers) HandleReportAllocations(c backends.Context, recoveredID strin
 	h.server.SendEmptyHTTPReply(c, payloadType)
 }
 
+// HandleExecutorHeartbeat marks an executor as alive, executors busy executing long running
+// processes send heartbeats to avoid being removed as stale
+func (h *Handlers) HandleExecutorHeartbeat(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateExecutorHeartbeatMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to send executor heartbeat, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to send executor heartbeat, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	executor, err := h.server.ExecutorDB().GetExecutorByName(msg.ColonyName, msg.ExecutorName)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+	if executor == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to send executor heartbeat, executor with name <"+msg.ExecutorName+"> does not exist"), http.StatusBadRequest)
+		return
+	}
+
+	if executor.ID != recoveredID {
+		h.server.HandleHTTPError(c, errors.New("Failed to send executor heartbeat, only an executor can send heartbeats for itself"), http.StatusForbidden)
+		return
+	}
+
+	err = h.server.ExecutorDB().MarkAlive(executor)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ExecutorName": executor.Name, "ColonyName": msg.ColonyName}).Debug("Executor heartbeat")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
 func (h *Handlers) HandleUpdateExecutor(c backends.Context, recoveredID string, payloadType string, jsonString string) {
 	msg, err := rpc.CreateUpdateExecutorMsgFromJSON(jsonString)
 	if err != nil {
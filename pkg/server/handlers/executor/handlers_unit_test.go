@@ -75,6 +75,7 @@ type MockExecutorDB struct {
 	removeErr     error
 	allocErr      error
 	updateCapErr  error
+	markAliveErr  error
 }
 
 func (m *MockExecutorDB) AddExecutor(executor *core.Executor) error                    { return m.addErr }
@@ -94,7 +95,7 @@ func (m *MockExecutorDB) GetExecutorsByBlueprintID(blueprintID string) ([]*core.
 }
 func (m *MockExecutorDB) ApproveExecutor(executor *core.Executor) error { return m.approveErr }
 func (m *MockExecutorDB) RejectExecutor(executor *core.Executor) error  { return m.rejectErr }
-func (m *MockExecutorDB) MarkAlive(executor *core.Executor) error       { return nil }
+func (m *MockExecutorDB) MarkAlive(executor *core.Executor) error       { return m.markAliveErr }
 func (m *MockExecutorDB) RemoveExecutorByName(colonyName string, executorName string) error {
 	return m.removeErr
 }
@@ -843,6 +844,111 @@ func TestHandleReportAllocationsSuccessUnit(t *testing.T) {
 	assert.False(t, server.httpError)
 }
 
+// HandleExecutorHeartbeat tests
+
+func TestHandleExecutorHeartbeatInvalidJSONUnit(t *testing.T) {
+	server := createMockServer()
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, "invalid json")
+	assert.True(t, server.httpError)
+}
+
+func TestHandleExecutorHeartbeatMsgTypeMismatchUnit(t *testing.T) {
+	server := createMockServer()
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", "wrong-type", jsonStr)
+	assert.True(t, server.httpError)
+}
+
+func TestHandleExecutorHeartbeatMembershipErrorUnit(t *testing.T) {
+	server := createMockServer()
+	server.validator.requireMembershipErr = errors.New("not a member")
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Equal(t, http.StatusForbidden, server.lastErrCode)
+}
+
+func TestHandleExecutorHeartbeatExecutorNilUnit(t *testing.T) {
+	server := createMockServer()
+	server.executorDB.executor = nil
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Equal(t, http.StatusBadRequest, server.lastErrCode)
+}
+
+func TestHandleExecutorHeartbeatNotOwnExecutorUnit(t *testing.T) {
+	server := createMockServer()
+	server.executorDB.executor = &core.Executor{
+		ID:         "different-executor-id",
+		Name:       "test-executor",
+		ColonyName: "test-colony",
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Equal(t, http.StatusForbidden, server.lastErrCode)
+}
+
+func TestHandleExecutorHeartbeatMarkAliveErrorUnit(t *testing.T) {
+	server := createMockServer()
+	server.executorDB.executor = &core.Executor{
+		ID:         "test-id",
+		Name:       "test-executor",
+		ColonyName: "test-colony",
+	}
+	server.executorDB.markAliveErr = errors.New("db error")
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Equal(t, http.StatusInternalServerError, server.lastErrCode)
+}
+
+func TestHandleExecutorHeartbeatSuccessUnit(t *testing.T) {
+	server := createMockServer()
+	server.executorDB.executor = &core.Executor{
+		ID:         "test-id",
+		Name:       "test-executor",
+		ColonyName: "test-colony",
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateExecutorHeartbeatMsg("test-colony", "test-executor")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleExecutorHeartbeat(ctx, "test-id", rpc.ExecutorHeartbeatPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+}
+
 // HandleUpdateExecutor tests
 
 func TestHandleUpdateExecutorInvalidJSONUnit(t *testing.T) {
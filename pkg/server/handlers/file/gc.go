@@ -0,0 +1,185 @@
+package file
+
+import (
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// collectGarbage removes file revisions not retained by the retention policies of their labels, and then
+// removes objects issued to the colony that are no longer referenced by any file or snapshot. Only objects
+// issued by the server, which are all in the namespace of the colony, are removed, so buckets can be shared by
+// several colonies and other applications. Objects issued within gracePeriod are kept, since they may still be
+// uploaded or added as files. If dryRun is true, nothing is removed and the report tells what would be removed.
+//
+// Objects not stored in a storage backend are stored by clients using storage drivers. The report lists the
+// objects retained in the namespace of the colony, including chunks, so that clients can sweep the rest.
+func (h *Handlers) collectGarbage(colonyName string, gracePeriod time.Duration, dryRun bool) (*core.FileGCReport, error) {
+	report := &core.FileGCReport{DryRun: dryRun}
+	now := time.Now()
+
+	// Presigned PUT URLs are valid for STORAGE_PRESIGN_EXPIRY, an object must not be removed before
+	// its URL has expired, since it may still be uploaded
+	minGracePeriod := time.Duration(constants.STORAGE_PRESIGN_EXPIRY) * time.Second
+	if gracePeriod < minGracePeriod {
+		gracePeriod = minGracePeriod
+	}
+
+	policies, err := h.server.FileRetentionDB().GetFileRetentionPoliciesByColonyName(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := h.server.SnapshotDB().GetSnapshotsByColonyName(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotFileIDs := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		for _, fileID := range snapshot.FileIDs {
+			snapshotFileIDs[fileID] = true
+		}
+	}
+
+	referenced := make(map[string]bool)
+	retain := func(objects []string) {
+		for _, object := range objects {
+			if object != "" && !referenced[object] {
+				referenced[object] = true
+				report.RetainedObjects = append(report.RetainedObjects, object)
+			}
+		}
+	}
+
+	labels, err := h.server.FileDB().GetFileLabels(colonyName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range labels {
+		fileDataArr, err := h.server.FileDB().GetFileDataByLabel(colonyName, label.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		policy := core.FindFileRetentionPolicy(policies, label.Name)
+		for _, fileData := range fileDataArr {
+			revisions, err := h.server.FileDB().GetFileByName(colonyName, label.Name, fileData.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			stale := make(map[string]bool)
+			if policy != nil {
+				for _, revision := range policy.StaleRevisions(revisions, snapshotFileIDs, now) {
+					stale[revision.ID] = true
+					report.StaleRevisions = append(report.StaleRevisions, revision)
+					if len(revision.Chunks) == 0 && !revision.Shared {
+						report.ReclaimedBytes += revision.Size
+					}
+				}
+			}
+
+			for _, revision := range revisions {
+				if !stale[revision.ID] {
+					retain(referencedObjects(revision))
+				}
+			}
+		}
+	}
+
+	// Files of snapshots are never stale, but their labels may have been removed
+	fileIDs := make([]string, 0, len(snapshotFileIDs))
+	for fileID := range snapshotFileIDs {
+		fileIDs = append(fileIDs, fileID)
+	}
+
+	snapshotFiles, err := h.server.FileDB().GetFilesByIDs(colonyName, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range snapshotFiles {
+		retain(referencedObjects(file))
+	}
+
+	redactCredentials(report.StaleRevisions)
+
+	if !dryRun {
+		for _, revision := range report.StaleRevisions {
+			log.WithFields(log.Fields{"ColonyName": colonyName, "Label": revision.Label, "Filename": revision.Name, "FileID": revision.ID}).Debug("Removing stale file revision")
+			err := h.server.FileDB().RemoveFileByID(colonyName, revision.ID)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	// The objects of removed revisions stored in a storage backend are no longer referenced, and are
+	// removed below together with objects of removed files and objects that were never added as files
+	objects, err := h.server.StorageBackendDB().GetStorageObjectsByColonyName(colonyName)
+	if err != nil {
+		return report, err
+	}
+
+	backends := make(map[string]*core.StorageBackend)
+	for _, object := range objects {
+		if referenced[object.Object] || !core.IsColonyStorageObject(colonyName, object.Object) {
+			continue
+		}
+
+		if now.Sub(object.Issued) < gracePeriod {
+			retain([]string{object.Object})
+			continue
+		}
+
+		report.UnreferencedObjects = append(report.UnreferencedObjects, object)
+		if dryRun {
+			continue
+		}
+
+		backend, ok := backends[object.Backend]
+		if !ok {
+			backend, err = h.server.StorageBackendDB().GetStorageBackendByName(colonyName, object.Backend)
+			if err != nil {
+				return report, err
+			}
+			backends[object.Backend] = backend
+		}
+
+		if backend == nil {
+			log.WithFields(log.Fields{"ColonyName": colonyName, "Backend": object.Backend, "Object": object.Object}).Warn("Storage backend not found, unreferenced object not removed")
+			continue
+		}
+
+		log.WithFields(log.Fields{"ColonyName": colonyName, "Backend": object.Backend, "Object": object.Object}).Debug("Removing unreferenced object")
+		err := h.remover.Remove(backend, object.Object)
+		if err != nil {
+			return report, err
+		}
+
+		err = h.server.StorageBackendDB().RemoveStorageObject(colonyName, object.Object)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// referencedObjects returns the objects referenced by a file, the object of the file or the objects of its chunks
+func referencedObjects(file *core.File) []string {
+	if len(file.Chunks) == 0 {
+		return []string{file.Reference.S3Object.Object}
+	}
+
+	objects := make([]string, 0, len(file.Chunks))
+	for _, chunk := range file.Chunks {
+		objects = append(objects, core.ChunkObject(file.ColonyName, chunk.Hash))
+	}
+
+	return objects
+}
@@ -3,6 +3,8 @@ package file
 import (
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
@@ -10,6 +12,8 @@ import (
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
+	"github.com/colonyos/colonies/pkg/storage"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -18,15 +22,32 @@ type Server interface {
 	SendHTTPReply(c backends.Context, payloadType string, jsonString string)
 	SendEmptyHTTPReply(c backends.Context, payloadType string)
 	Validator() security.Validator
+	ProcessDB() database.ProcessDatabase
 	FileDB() database.FileDatabase
+	StorageBackendDB() database.StorageBackendDatabase
+	FileRetentionDB() database.FileRetentionDatabase
+	FileLineageDB() database.FileLineageDatabase
+	SnapshotDB() database.SnapshotDatabase
 }
 
 type Handlers struct {
-	server Server
+	server  Server
+	remover storage.ObjectRemover
 }
 
 func NewHandlers(server Server) *Handlers {
-	return &Handlers{server: server}
+	return &Handlers{server: server, remover: storage.CreateS3ObjectRemover()}
+}
+
+// redactCredentials removes S3 credentials from files before they are sent to clients. Credentials are no
+// longer stored, but files added by older clients may still have them.
+func redactCredentials(files []*core.File) {
+	for _, file := range files {
+		if file != nil {
+			file.Reference.S3Object.AccessKey = ""
+			file.Reference.S3Object.SecretKey = ""
+		}
+	}
 }
 
 // RegisterHandlers implements the HandlerRegistrar interface
@@ -46,6 +67,44 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.RemoveFilePayloadType, h.HandleRemoveFile); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.UpdateFileEncryptionKeyPayloadType, h.HandleUpdateFileEncryptionKey); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.SetFileRetentionPolicyPayloadType, h.HandleSetFileRetentionPolicy); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetFileRetentionPoliciesPayloadType, h.HandleGetFileRetentionPolicies); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RemoveFileRetentionPolicyPayloadType, h.HandleRemoveFileRetentionPolicy); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetFileLineagePayloadType, h.HandleGetFileLineage); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GCFilesPayloadType, h.HandleGCFiles); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateChunks checks that the chunks of a file are SHA256 hashes and that their sizes add up to the file size
+func validateChunks(file *core.File) error {
+	var size int64
+	for _, chunk := range file.Chunks {
+		if len(chunk.Hash) != 64 || strings.Trim(chunk.Hash, "0123456789abcdef") != "" {
+			return errors.New("Failed to add file, invalid chunk hash <" + chunk.Hash + ">")
+		}
+		if chunk.Size <= 0 {
+			return errors.New("Failed to add file, chunk size must be positive")
+		}
+		size += chunk.Size
+	}
+
+	if size != file.Size {
+		return errors.New("Failed to add file, size of chunks does not match file size")
+	}
+
 	return nil
 }
 
@@ -73,8 +132,74 @@ func (h *Handlers) HandleAddFile(c backends.Context, recoveredID string, payload
 		return
 	}
 
-	// Bypass colonies controller and use the database directly, no need to synchronize this operation since files are immutable
+	// Only the executor assigned to a process can add files produced by it, so that files added to the same
+	// label by other processes or users are never recorded as outputs of the process
+	var process *core.Process
+	if msg.ProcessID != "" {
+		process, err = h.server.ProcessDB().GetProcessByID(msg.ProcessID)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		if process == nil || process.FunctionSpec.Conditions.ColonyName != msg.File.ColonyName {
+			h.server.HandleHTTPError(c, errors.New("Failed to add file, process with id <"+msg.ProcessID+"> not found"), http.StatusBadRequest)
+			return
+		}
+
+		if process.State != core.RUNNING || process.AssignedExecutorID != recoveredID {
+			h.server.HandleHTTPError(c, errors.New("Failed to add file, process with id <"+msg.ProcessID+"> is not running on the executor adding the file"), http.StatusForbidden)
+			return
+		}
+	}
+
+	// Credentials are never stored in file records, files stored in a storage backend only reference the backend
+	// by name, the endpoint and credentials are looked up by the server when presigning URLs
 	file := msg.File
+	redactCredentials([]*core.File{file})
+
+	if len(file.Chunks) > 0 {
+		if err := validateChunks(file); h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if file.Reference.Backend != "" {
+		backend, err := h.server.StorageBackendDB().GetStorageBackendByName(file.ColonyName, file.Reference.Backend)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		if backend == nil {
+			h.server.HandleHTTPError(c, errors.New("Failed to add file, storage backend with name <"+file.Reference.Backend+"> does not exists"), http.StatusBadRequest)
+			return
+		}
+
+		// Only objects issued to the colony by a PUT presign can be referenced, and only by one file, otherwise
+		// members could presign GET and DELETE URLs for objects of other colonies sharing the bucket
+		if !core.IsColonyStorageObject(file.ColonyName, file.Reference.S3Object.Object) {
+			h.server.HandleHTTPError(c, errors.New("Failed to add file, object <"+file.Reference.S3Object.Object+"> is not in the namespace of the colony"), http.StatusForbidden)
+			return
+		}
+
+		registered, err := h.server.StorageBackendDB().RegisterStorageObject(file.ColonyName, backend.Name, file.Reference.S3Object.Object)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		if !registered {
+			h.server.HandleHTTPError(c, errors.New("Failed to add file, object <"+file.Reference.S3Object.Object+"> was not issued by a presigned PUT or is already referenced by another file"), http.StatusBadRequest)
+			return
+		}
+
+		file.Reference.Protocol = backend.Protocol
+		file.Reference.S3Object.Server = ""
+		file.Reference.S3Object.Port = 0
+		file.Reference.S3Object.TLS = false
+		file.Reference.S3Object.Region = ""
+		file.Reference.S3Object.Bucket = ""
+	}
+
+	// Bypass colonies controller and use the database directly, no need to synchronize this operation since files are immutable
 	file.ID = core.GenerateRandomID()
 	h.server.FileDB().AddFile(msg.File)
 
@@ -84,6 +209,18 @@ func (h *Handlers) HandleAddFile(c backends.Context, recoveredID string, payload
 		return
 	}
 
+	if addedFile == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add file"), http.StatusInternalServerError)
+		return
+	}
+
+	if process != nil {
+		err = serverutils.RecordProcessOutput(process, addedFile, h.server.FileLineageDB())
+		if err != nil {
+			log.WithFields(log.Fields{"ProcessId": process.ID, "FileID": addedFile.ID, "Error": err}).Error("Failed to record process file lineage")
+		}
+	}
+
 	jsonStr, err := addedFile.ToJSON()
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		log.Error(err)
@@ -172,6 +309,8 @@ func (h *Handlers) HandleGetFile(c backends.Context, recoveredID string, payload
 		}
 	}
 
+	redactCredentials(files)
+
 	jsonStr, err := core.ConvertFileArrayToJSON(files)
 	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to converts files to json")
@@ -297,4 +436,263 @@ func (h *Handlers) HandleRemoveFile(c backends.Context, recoveredID string, payl
 	}
 
 	h.server.SendEmptyHTTPReply(c, payloadType)
-}
\ No newline at end of file
+}
+
+func (h *Handlers) HandleUpdateFileEncryptionKey(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateUpdateFileEncryptionKeyMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to update file encryption key, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to update file encryption key, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if msg.EncryptionKey == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to update file encryption key, encryption key is empty"), http.StatusBadRequest)
+		return
+	}
+
+	file, err := h.server.FileDB().GetFileByID(msg.ColonyName, msg.FileID)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if file == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to update file encryption key, file with Id <"+msg.FileID+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	if file.Reference.S3Object.EncryptionAlg == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to update file encryption key, file with Id <"+msg.FileID+"> is not encrypted"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.FileDB().UpdateFileEncryptionKey(msg.ColonyName, msg.FileID, msg.EncryptionKey)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"FileID": msg.FileID}).Debug("Updating file encryption key")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
+func (h *Handlers) HandleSetFileRetentionPolicy(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateSetFileRetentionPolicyMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to set file retention policy, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to set file retention policy, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	policy := msg.FileRetentionPolicy
+	if policy == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to set file retention policy, policy is nil"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, policy.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if !strings.HasPrefix(policy.Label, "/") {
+		h.server.HandleHTTPError(c, errors.New("Failed to set file retention policy, label must start with /"), http.StatusBadRequest)
+		return
+	}
+
+	if policy.KeepLast < 0 || policy.KeepDays < 0 {
+		h.server.HandleHTTPError(c, errors.New("Failed to set file retention policy, keeplast and keepdays must not be negative"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.FileRetentionDB().SetFileRetentionPolicy(policy)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": policy.ColonyName, "Label": policy.Label, "KeepLast": policy.KeepLast, "KeepDays": policy.KeepDays}).Debug("Setting file retention policy")
+
+	jsonString, err = policy.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleGetFileRetentionPolicies(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetFileRetentionPoliciesMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get file retention policies, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get file retention policies, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	policies, err := h.server.FileRetentionDB().GetFileRetentionPoliciesByColonyName(msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	jsonString, err = core.ConvertFileRetentionPolicyArrayToJSON(policies)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleRemoveFileRetentionPolicy(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveFileRetentionPolicyMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to remove file retention policy, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to remove file retention policy, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	policy, err := h.server.FileRetentionDB().GetFileRetentionPolicyByLabel(msg.ColonyName, msg.Label)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if policy == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to remove file retention policy, no policy with label <"+msg.Label+"> found"), http.StatusNotFound)
+		return
+	}
+
+	err = h.server.FileRetentionDB().RemoveFileRetentionPolicyByLabel(msg.ColonyName, msg.Label)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Label": msg.Label}).Debug("Removing file retention policy")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
+func (h *Handlers) HandleGetFileLineage(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetFileLineageMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get file lineage, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get file lineage, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if msg.Direction != "" && msg.Direction != core.LineageInput && msg.Direction != core.LineageOutput {
+		h.server.HandleHTTPError(c, errors.New("Failed to get file lineage, direction must be "+core.LineageInput+" or "+core.LineageOutput), http.StatusBadRequest)
+		return
+	}
+
+	var lineage []*core.FileLineage
+	if msg.ProcessID != "" {
+		lineage, err = h.server.FileLineageDB().GetFileLineageByProcessID(msg.ColonyName, msg.ProcessID)
+	} else if msg.ProcessGraphID != "" {
+		lineage, err = h.server.FileLineageDB().GetFileLineageByProcessGraphID(msg.ColonyName, msg.ProcessGraphID)
+	} else if msg.FileID != "" {
+		lineage, err = h.server.FileLineageDB().GetFileLineageByFileID(msg.ColonyName, msg.FileID)
+	} else {
+		err = errors.New("Failed to get file lineage, process Id, process graph Id or file Id must be specified")
+		h.server.HandleHTTPError(c, err, http.StatusBadRequest)
+		return
+	}
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	filtered := []*core.FileLineage{}
+	for _, l := range lineage {
+		if msg.Direction == "" || l.Direction == msg.Direction {
+			filtered = append(filtered, l)
+		}
+	}
+
+	jsonString, err = core.ConvertFileLineageArrayToJSON(filtered)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+// HandleGCFiles removes stale file revisions and unreferenced objects issued to the colony, see collectGarbage
+func (h *Handlers) HandleGCFiles(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGCFilesMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to collect garbage, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to collect garbage, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if msg.GracePeriod < 0 {
+		h.server.HandleHTTPError(c, errors.New("Failed to collect garbage, grace period must not be negative"), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.collectGarbage(msg.ColonyName, time.Duration(msg.GracePeriod)*time.Second, msg.DryRun)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "DryRun": msg.DryRun, "StaleRevisions": len(report.StaleRevisions), "UnreferencedObjects": len(report.UnreferencedObjects)}).Debug("Collecting garbage")
+
+	jsonString, err = report.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
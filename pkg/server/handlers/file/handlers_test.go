@@ -3,6 +3,7 @@ package file_test
 import (
 	"testing"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,8 @@ func TestAddFile(t *testing.T) {
 	file.ID = addedFile.ID
 	file.Added = addedFile.Added
 	file.SequenceNumber = addedFile.SequenceNumber
+	file.Reference.S3Object.AccessKey = ""
+	file.Reference.S3Object.SecretKey = ""
 	assert.True(t, file.Equals(addedFile))
 
 	server.Shutdown()
@@ -37,6 +40,8 @@ func TestGetFileByID(t *testing.T) {
 	file.ID = file2.ID
 	file.Added = file2.Added
 	file.SequenceNumber = file2.SequenceNumber
+	file.Reference.S3Object.AccessKey = ""
+	file.Reference.S3Object.SecretKey = ""
 	assert.True(t, file.Equals(file2))
 
 	server.Shutdown()
@@ -62,6 +67,8 @@ func TestGetFileByName(t *testing.T) {
 	file.ID = file2.ID
 	file.Added = file2.Added
 	file.SequenceNumber = file2.SequenceNumber
+	file.Reference.S3Object.AccessKey = ""
+	file.Reference.S3Object.SecretKey = ""
 	assert.True(t, file.Equals(file2))
 	assert.Equal(t, file.Size, int64(1))
 
@@ -442,3 +449,120 @@ func TestGetFileByIDNotFound(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestFileRetentionPolicies(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	policy, err := client.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(env.ColonyName, "/test", 5, 30), env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, policy.KeepLast)
+
+	_, err = client.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(env.ColonyName, "/test", 2, 0), env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	policies, err := client.GetFileRetentionPolicies(env.ColonyName, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, 2, policies[0].KeepLast)
+
+	err = client.RemoveFileRetentionPolicy(env.ColonyName, "/test", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.RemoveFileRetentionPolicy(env.ColonyName, "/test", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	policies, err = client.GetFileRetentionPolicies(env.ColonyName, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 0)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestGCFiles(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	for i := 0; i < 3; i++ {
+		file := utils.CreateTestFile(env.ColonyName)
+		file.Label = "/test"
+		_, err := client.AddFile(file, env.ExecutorPrvKey)
+		assert.Nil(t, err)
+	}
+
+	_, err := client.SetFileRetentionPolicy(core.CreateFileRetentionPolicy(env.ColonyName, "/test", 1, 0), env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	report, err := client.GCFiles(env.ColonyName, 0, true, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.StaleRevisions, 2)
+
+	revisions, err := client.GetFileByName(env.ColonyName, "/test", "test_name", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 3)
+
+	report, err = client.GCFiles(env.ColonyName, 0, false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 2)
+
+	revisions, err = client.GetFileByName(env.ColonyName, "/test", "test_name", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 1)
+
+	server.Shutdown()
+	<-done
+}
+
+func TestFileLineage(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	input := utils.CreateTestFile(env.ColonyName)
+	input.Label = "/lineage"
+	input.Name = "input"
+	addedInput, err := client.AddFile(input, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.ColonyName)
+	funcSpec.Filesystem.SyncDirMounts = []core.SyncDirMount{{Label: "/lineage", Dir: "/lineage"}}
+	_, err = client.Submit(funcSpec, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	process, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	output := utils.CreateTestFile(env.ColonyName)
+	output.Label = "/lineage"
+	output.Name = "output"
+	addedOutput, err := client.AddProcessFile(output, process.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	// Files added to the label without the process id are not outputs of the process
+	other := utils.CreateTestFile(env.ColonyName)
+	other.Label = "/lineage"
+	other.Name = "other"
+	_, err = client.AddFile(other, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.Close(process.ID, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	lineage, err := client.GetProcessFileLineage(env.ColonyName, process.ID, "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 2)
+
+	lineage, err = client.GetProcessFileLineage(env.ColonyName, process.ID, core.LineageInput, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 1)
+	assert.Equal(t, addedInput.ID, lineage[0].FileID)
+
+	lineage, err = client.GetFileLineage(env.ColonyName, addedOutput.ID, core.LineageOutput, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 1)
+	assert.Equal(t, process.ID, lineage[0].ProcessID)
+
+	_, err = client.GetProcessFileLineage(env.ColonyName, process.ID, "invalid", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	server.Shutdown()
+	<-done
+}
@@ -3,7 +3,9 @@ package file
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
@@ -25,8 +27,11 @@ type MockFileDB struct {
 	getLatestErr     error
 	getDataErr       error
 	getLabelsErr     error
+	updateEncKeyErr  error
+	updatedEncKey    string
 	removeByIDErr    error
 	removeByNameErr  error
+	removedIDs       []string
 	returnNilByID    bool
 	returnEmptyByName bool
 }
@@ -68,6 +73,22 @@ func (m *MockFileDB) GetFileByName(colonyName string, label string, name string)
 	return m.files, nil
 }
 
+func (m *MockFileDB) GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error) {
+	var files []*core.File
+	for _, file := range m.files {
+		for _, fileID := range fileIDs {
+			if file.ID == fileID {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+func (m *MockFileDB) GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error) {
+	return nil, nil
+}
+
 func (m *MockFileDB) GetLatestFileByName(colonyName string, label string, name string) ([]*core.File, error) {
 	if m.getLatestErr != nil {
 		return nil, m.getLatestErr
@@ -89,10 +110,19 @@ func (m *MockFileDB) GetFileDataByLabel(colonyName string, label string) ([]*cor
 	return m.fileData, nil
 }
 
+func (m *MockFileDB) UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string) error {
+	if m.updateEncKeyErr != nil {
+		return m.updateEncKeyErr
+	}
+	m.updatedEncKey = encryptionKey
+	return nil
+}
+
 func (m *MockFileDB) RemoveFileByID(colonyName string, fileID string) error {
 	if m.removeByIDErr != nil {
 		return m.removeByIDErr
 	}
+	m.removedIDs = append(m.removedIDs, fileID)
 	return nil
 }
 
@@ -193,6 +223,11 @@ func (m *MockContext) Next()           {}
 // MockServer implements Server interface
 type MockServer struct {
 	fileDB          *MockFileDB
+	processDB       *MockProcessDB
+	backendDB       *MockStorageBackendDB
+	retentionDB     *MockFileRetentionDB
+	lineageDB       *MockFileLineageDB
+	snapshotDB      *MockSnapshotDB
 	validator       *MockValidator
 	lastError       error
 	lastStatusCode  int
@@ -227,10 +262,239 @@ func (m *MockServer) Validator() security.Validator {
 	return m.validator
 }
 
+func (m *MockServer) ProcessDB() database.ProcessDatabase {
+	return m.processDB
+}
+
 func (m *MockServer) FileDB() database.FileDatabase {
 	return m.fileDB
 }
 
+func (m *MockServer) StorageBackendDB() database.StorageBackendDatabase {
+	return m.backendDB
+}
+
+func (m *MockServer) FileRetentionDB() database.FileRetentionDatabase {
+	return m.retentionDB
+}
+
+func (m *MockServer) FileLineageDB() database.FileLineageDatabase {
+	return m.lineageDB
+}
+
+func (m *MockServer) SnapshotDB() database.SnapshotDatabase {
+	return m.snapshotDB
+}
+
+// MockProcessDB implements database.ProcessDatabase, only GetProcessByID is used by the file handlers
+type MockProcessDB struct {
+	database.ProcessDatabase
+	processes []*core.Process
+}
+
+func (m *MockProcessDB) GetProcessByID(processID string) (*core.Process, error) {
+	for _, process := range m.processes {
+		if process.ID == processID {
+			return process, nil
+		}
+	}
+	return nil, nil
+}
+
+// MockStorageBackendDB implements database.StorageBackendDatabase
+type MockStorageBackendDB struct {
+	backends     []*core.StorageBackend
+	objects        []*core.StorageObject
+	removedObjects []string
+	getByNameErr   error
+}
+
+func (m *MockStorageBackendDB) AddStorageBackend(backend *core.StorageBackend) error {
+	m.backends = append(m.backends, backend)
+	return nil
+}
+
+func (m *MockStorageBackendDB) GetStorageBackendByName(colonyName string, name string) (*core.StorageBackend, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	for _, b := range m.backends {
+		if b.ColonyName == colonyName && b.Name == name {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockStorageBackendDB) GetStorageBackendsByColonyName(colonyName string) ([]*core.StorageBackend, error) {
+	return m.backends, nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageBackendByName(colonyName string, name string) error {
+	return nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageBackendsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (m *MockStorageBackendDB) AddStorageObject(object *core.StorageObject) error {
+	m.objects = append(m.objects, object)
+	return nil
+}
+
+func (m *MockStorageBackendDB) RegisterStorageObject(colonyName string, backend string, object string) (bool, error) {
+	for _, o := range m.objects {
+		if o.ColonyName == colonyName && o.Backend == backend && o.Object == object && !o.Registered {
+			o.Registered = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockStorageBackendDB) GetStorageObjectsByColonyName(colonyName string) ([]*core.StorageObject, error) {
+	return m.objects, nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageObject(colonyName string, object string) error {
+	m.removedObjects = append(m.removedObjects, object)
+	return nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageObjectsByColonyName(colonyName string) error {
+	return nil
+}
+
+// MockFileRetentionDB implements database.FileRetentionDatabase
+type MockFileRetentionDB struct {
+	policies  []*core.FileRetentionPolicy
+	setErr    error
+	removeErr error
+}
+
+func (m *MockFileRetentionDB) SetFileRetentionPolicy(policy *core.FileRetentionPolicy) error {
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.policies = append(m.policies, policy)
+	return nil
+}
+
+func (m *MockFileRetentionDB) GetFileRetentionPolicyByLabel(colonyName string, label string) (*core.FileRetentionPolicy, error) {
+	for _, p := range m.policies {
+		if p.ColonyName == colonyName && p.Label == label {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockFileRetentionDB) GetFileRetentionPoliciesByColonyName(colonyName string) ([]*core.FileRetentionPolicy, error) {
+	return m.policies, nil
+}
+
+func (m *MockFileRetentionDB) RemoveFileRetentionPolicyByLabel(colonyName string, label string) error {
+	return m.removeErr
+}
+
+func (m *MockFileRetentionDB) RemoveFileRetentionPoliciesByColonyName(colonyName string) error {
+	return nil
+}
+
+// MockSnapshotDB implements database.SnapshotDatabase
+type MockSnapshotDB struct {
+	snapshots []*core.Snapshot
+}
+
+func (m *MockSnapshotDB) CreateSnapshot(colonyName string, label string, name string) (*core.Snapshot, error) {
+	return nil, nil
+}
+
+func (m *MockSnapshotDB) GetSnapshotByID(colonyName string, snapshotID string) (*core.Snapshot, error) {
+	return nil, nil
+}
+
+func (m *MockSnapshotDB) GetSnapshotsByColonyName(colonyName string) ([]*core.Snapshot, error) {
+	return m.snapshots, nil
+}
+
+func (m *MockSnapshotDB) RemoveSnapshotByID(colonyName string, snapshotID string) error { return nil }
+
+func (m *MockSnapshotDB) GetSnapshotByName(colonyName string, name string) (*core.Snapshot, error) {
+	return nil, nil
+}
+
+func (m *MockSnapshotDB) RemoveSnapshotByName(colonyName string, name string) error { return nil }
+
+func (m *MockSnapshotDB) RemoveSnapshotsByColonyName(colonyName string) error { return nil }
+
+func (m *MockSnapshotDB) DiffSnapshots(colonyName string, fromSnapshotID string, toSnapshotID string) (*core.SnapshotDiff, error) {
+	return nil, nil
+}
+
+func (m *MockSnapshotDB) RestoreSnapshot(colonyName string, snapshotID string, prune bool) (*core.SnapshotDiff, error) {
+	return nil, nil
+}
+
+func (m *MockSnapshotDB) CloneSnapshot(colonyName string, snapshotID string, label string) (*core.SnapshotDiff, error) {
+	return nil, nil
+}
+
+// MockObjectRemover implements storage.ObjectRemover
+type MockObjectRemover struct {
+	removed []string
+	err     error
+}
+
+func (m *MockObjectRemover) Remove(backend *core.StorageBackend, object string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.removed = append(m.removed, object)
+	return nil
+}
+
+// MockFileLineageDB implements database.FileLineageDatabase
+type MockFileLineageDB struct {
+	lineage []*core.FileLineage
+	getErr  error
+}
+
+func (m *MockFileLineageDB) AddFileLineages(lineages []*core.FileLineage) error {
+	m.lineage = append(m.lineage, lineages...)
+	return nil
+}
+
+func (m *MockFileLineageDB) find(match func(l *core.FileLineage) bool) ([]*core.FileLineage, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	var lineage []*core.FileLineage
+	for _, l := range m.lineage {
+		if match(l) {
+			lineage = append(lineage, l)
+		}
+	}
+	return lineage, nil
+}
+
+func (m *MockFileLineageDB) GetFileLineageByProcessID(colonyName string, processID string) ([]*core.FileLineage, error) {
+	return m.find(func(l *core.FileLineage) bool { return l.ProcessID == processID })
+}
+
+func (m *MockFileLineageDB) GetFileLineageByProcessGraphID(colonyName string, processGraphID string) ([]*core.FileLineage, error) {
+	return m.find(func(l *core.FileLineage) bool { return l.ProcessGraphID == processGraphID })
+}
+
+func (m *MockFileLineageDB) GetFileLineageByFileID(colonyName string, fileID string) ([]*core.FileLineage, error) {
+	return m.find(func(l *core.FileLineage) bool { return l.FileID == fileID })
+}
+
+func (m *MockFileLineageDB) RemoveFileLineageByColonyName(colonyName string) error {
+	return nil
+}
+
 // Helper to create test file
 func createTestFile() *core.File {
 	return &core.File{
@@ -250,7 +514,12 @@ func createMockServer() (*MockServer, *MockContext) {
 
 	server := &MockServer{
 		fileDB:    fileDB,
-		validator: validator,
+		processDB:   &MockProcessDB{},
+		backendDB:   &MockStorageBackendDB{},
+		retentionDB: &MockFileRetentionDB{},
+		lineageDB:   &MockFileLineageDB{},
+		snapshotDB:  &MockSnapshotDB{},
+		validator:   validator,
 	}
 
 	ctx := &MockContext{}
@@ -282,6 +551,46 @@ func TestHandleAddFile_Success(t *testing.T) {
 	assert.Nil(t, server.lastError)
 }
 
+func TestHandleAddFile_Process(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = "test-colony"
+	process := core.CreateProcess(funcSpec)
+	process.State = core.RUNNING
+	process.AssignedExecutorID = "test-executor"
+	server.processDB.processes = []*core.Process{process}
+
+	file := createTestFile()
+	msg := rpc.CreateAddFileMsg(file)
+	msg.ProcessID = process.ID
+	jsonString, _ := msg.ToJSON()
+
+	// Only the executor assigned to the process can add files produced by it
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+	assert.Len(t, server.lineageDB.lineage, 0)
+
+	server.lastError = nil
+	handlers.HandleAddFile(ctx, "test-executor", rpc.AddFilePayloadType, jsonString)
+	assert.Nil(t, server.lastError)
+	assert.Len(t, server.lineageDB.lineage, 1)
+	assert.Equal(t, process.ID, server.lineageDB.lineage[0].ProcessID)
+	assert.Equal(t, core.LineageOutput, server.lineageDB.lineage[0].Direction)
+
+	// Files cannot be added for processes that are not running
+	process.State = core.SUCCESS
+	handlers.HandleAddFile(ctx, "test-executor", rpc.AddFilePayloadType, jsonString)
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+	assert.Len(t, server.lineageDB.lineage, 1)
+
+	msg.ProcessID = "unknown"
+	jsonString, _ = msg.ToJSON()
+	handlers.HandleAddFile(ctx, "test-executor", rpc.AddFilePayloadType, jsonString)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
 func TestHandleAddFile_InvalidJSON(t *testing.T) {
 	server, ctx := createMockServer()
 	handlers := NewHandlers(server)
@@ -344,7 +653,176 @@ func TestHandleAddFile_GetFileError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
 }
 
+func TestHandleAddFile_CredentialsNotStored(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	file := createTestFile()
+	file.Reference.S3Object.Server = "localhost:9000"
+	file.Reference.S3Object.AccessKey = "test-accesskey"
+	file.Reference.S3Object.SecretKey = "test-secretkey"
+	msg := rpc.CreateAddFileMsg(file)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	addedFile := server.fileDB.files[len(server.fileDB.files)-1]
+	assert.Equal(t, "", addedFile.Reference.S3Object.AccessKey)
+	assert.Equal(t, "", addedFile.Reference.S3Object.SecretKey)
+	assert.Equal(t, "localhost:9000", addedFile.Reference.S3Object.Server)
+	assert.NotContains(t, server.lastResponse, "test-secretkey")
+}
+
+func TestHandleAddFile_StorageBackend(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{core.CreateStorageBackend("test-colony", "test-backend", "localhost:9000", "test-bucket", "test-accesskey", "test-secretkey")}
+	storageObject := core.CreateStorageObject("test-colony", "test-backend")
+	server.backendDB.objects = []*core.StorageObject{storageObject}
+	handlers := NewHandlers(server)
+
+	file := createTestFile()
+	file.Reference.Backend = "test-backend"
+	file.Reference.S3Object.Server = "localhost:9000"
+	file.Reference.S3Object.Bucket = "test-bucket"
+	file.Reference.S3Object.Object = storageObject.Object
+	msg := rpc.CreateAddFileMsg(file)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	addedFile := server.fileDB.files[len(server.fileDB.files)-1]
+	assert.Equal(t, "test-backend", addedFile.Reference.Backend)
+	assert.Equal(t, "s3", addedFile.Reference.Protocol)
+	assert.Equal(t, storageObject.Object, addedFile.Reference.S3Object.Object)
+	assert.Equal(t, "", addedFile.Reference.S3Object.Server)
+	assert.Equal(t, "", addedFile.Reference.S3Object.Bucket)
+	assert.True(t, storageObject.Registered)
+
+	// An issued object can only be referenced by one file
+	server.lastError = nil
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddFile_StorageObjectNotIssued(t *testing.T) {
+	tests := []struct {
+		name       string
+		object     string
+		statusCode int
+	}{
+		{"OtherColony", core.CreateStorageObject("other-colony", "test-backend").Object, http.StatusForbidden},
+		{"NoNamespace", "test-object", http.StatusForbidden},
+		{"NotIssued", core.StorageObjectPrefix("test-colony") + "not-issued", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, ctx := createMockServer()
+			server.backendDB.backends = []*core.StorageBackend{core.CreateStorageBackend("test-colony", "test-backend", "localhost:9000", "test-bucket", "test-accesskey", "test-secretkey")}
+			handlers := NewHandlers(server)
+
+			file := createTestFile()
+			file.Reference.Backend = "test-backend"
+			file.Reference.S3Object.Object = tt.object
+			msg := rpc.CreateAddFileMsg(file)
+			jsonString, _ := msg.ToJSON()
+
+			handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+			assert.Equal(t, tt.statusCode, server.lastStatusCode)
+			assert.Len(t, server.fileDB.files, 1)
+		})
+	}
+}
+
+func TestHandleAddFile_StorageBackendNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	file := createTestFile()
+	file.Reference.Backend = "does-not-exist"
+	msg := rpc.CreateAddFileMsg(file)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.fileDB.files, 1)
+}
+
+func TestHandleAddFile_StorageBackendDBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.getByNameErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	file := createTestFile()
+	file.Reference.Backend = "test-backend"
+	msg := rpc.CreateAddFileMsg(file)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandleAddFile_Chunks(t *testing.T) {
+	hash1 := strings.Repeat("a", 64)
+	hash2 := strings.Repeat("b", 64)
+	tests := []struct {
+		name   string
+		chunks []core.FileChunk
+		valid  bool
+	}{
+		{"Valid", []core.FileChunk{{Hash: hash1, Size: 60}, {Hash: hash2, Size: 40}}, true},
+		{"SizeMismatch", []core.FileChunk{{Hash: hash1, Size: 60}}, false},
+		{"InvalidHash", []core.FileChunk{{Hash: "../object", Size: 100}}, false},
+		{"UppercaseHash", []core.FileChunk{{Hash: strings.Repeat("A", 64), Size: 100}}, false},
+		{"ZeroSize", []core.FileChunk{{Hash: hash1, Size: 100}, {Hash: hash2, Size: 0}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, ctx := createMockServer()
+			handlers := NewHandlers(server)
+
+			file := createTestFile()
+			file.Size = 100
+			file.Chunks = tt.chunks
+			msg := rpc.CreateAddFileMsg(file)
+			jsonString, _ := msg.ToJSON()
+
+			handlers.HandleAddFile(ctx, "test-user", rpc.AddFilePayloadType, jsonString)
+
+			if tt.valid {
+				assert.Nil(t, server.lastError)
+				assert.Len(t, server.fileDB.files, 2)
+			} else {
+				assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+				assert.Len(t, server.fileDB.files, 1)
+			}
+		})
+	}
+}
+
 // Tests for HandleGetFile
+func TestHandleGetFile_RedactsCredentials(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.files[0].Reference.S3Object.AccessKey = "test-accesskey"
+	server.fileDB.files[0].Reference.S3Object.SecretKey = "test-secretkey"
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileMsg("test-colony", "file-123", "", "", false)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFile(ctx, "test-user", rpc.GetFilePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.NotContains(t, server.lastResponse, "test-accesskey")
+	assert.NotContains(t, server.lastResponse, "test-secretkey")
+}
+
 func TestHandleGetFile_ByID_Success(t *testing.T) {
 	server, ctx := createMockServer()
 	handlers := NewHandlers(server)
@@ -634,3 +1112,544 @@ func TestHandleRemoveFile_RemoveByIDError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
 }
+
+// Tests for HandleUpdateFileEncryptionKey
+func TestHandleUpdateFileEncryptionKey_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.files[0].Reference.S3Object.EncryptionKey = "old-key"
+	server.fileDB.files[0].Reference.S3Object.EncryptionAlg = "AES-256-GCM-STREAM"
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.True(t, server.emptyReplySent)
+	assert.Equal(t, "new-key", server.fileDB.updatedEncKey)
+}
+
+func TestHandleUpdateFileEncryptionKey_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleUpdateFileEncryptionKey_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleUpdateFileEncryptionKey_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleUpdateFileEncryptionKey_EmptyKey(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleUpdateFileEncryptionKey_FileNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.returnNilByID = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleUpdateFileEncryptionKey_NotEncrypted(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Equal(t, "", server.fileDB.updatedEncKey)
+}
+
+func TestHandleUpdateFileEncryptionKey_UpdateError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.files[0].Reference.S3Object.EncryptionAlg = "AES-256-GCM-STREAM"
+	server.fileDB.updateEncKeyErr = errors.New("update error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateUpdateFileEncryptionKeyMsg("test-colony", "file-123", "new-key")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleUpdateFileEncryptionKey(ctx, "test-user", rpc.UpdateFileEncryptionKeyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandleSetFileRetentionPolicy_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateSetFileRetentionPolicyMsg(core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30))
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleSetFileRetentionPolicy(ctx, "test-user", rpc.SetFileRetentionPolicyPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Len(t, server.retentionDB.policies, 1)
+	policy, err := core.ConvertJSONToFileRetentionPolicy(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, policy.KeepLast)
+}
+
+func TestHandleSetFileRetentionPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *core.FileRetentionPolicy
+	}{
+		{"NilPolicy", nil},
+		{"RelativeLabel", core.CreateFileRetentionPolicy("test-colony", "test", 5, 0)},
+		{"NegativeKeepLast", core.CreateFileRetentionPolicy("test-colony", "/test", -1, 0)},
+		{"NegativeKeepDays", core.CreateFileRetentionPolicy("test-colony", "/test", 0, -1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, ctx := createMockServer()
+			handlers := NewHandlers(server)
+
+			msg := rpc.CreateSetFileRetentionPolicyMsg(tt.policy)
+			jsonString, _ := msg.ToJSON()
+
+			handlers.HandleSetFileRetentionPolicy(ctx, "test-user", rpc.SetFileRetentionPolicyPayloadType, jsonString)
+
+			assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+			assert.Len(t, server.retentionDB.policies, 0)
+		})
+	}
+}
+
+func TestHandleSetFileRetentionPolicy_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleSetFileRetentionPolicy(ctx, "test-user", rpc.SetFileRetentionPolicyPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleSetFileRetentionPolicy_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateSetFileRetentionPolicyMsg(core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30))
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleSetFileRetentionPolicy(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleSetFileRetentionPolicy_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateSetFileRetentionPolicyMsg(core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30))
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleSetFileRetentionPolicy(ctx, "test-user", rpc.SetFileRetentionPolicyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleSetFileRetentionPolicy_DBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.retentionDB.setErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateSetFileRetentionPolicyMsg(core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30))
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleSetFileRetentionPolicy(ctx, "test-user", rpc.SetFileRetentionPolicyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandleGetFileRetentionPolicies_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.retentionDB.policies = []*core.FileRetentionPolicy{core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30)}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileRetentionPoliciesMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileRetentionPolicies(ctx, "test-user", rpc.GetFileRetentionPoliciesPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	policies, err := core.ConvertJSONToFileRetentionPolicyArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+}
+
+func TestHandleGetFileRetentionPolicies_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileRetentionPoliciesMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileRetentionPolicies(ctx, "test-user", rpc.GetFileRetentionPoliciesPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleRemoveFileRetentionPolicy_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.retentionDB.policies = []*core.FileRetentionPolicy{core.CreateFileRetentionPolicy("test-colony", "/test", 5, 30)}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveFileRetentionPolicyMsg("test-colony", "/test")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveFileRetentionPolicy(ctx, "test-user", rpc.RemoveFileRetentionPolicyPayloadType, jsonString)
+
+	assert.True(t, server.emptyReplySent)
+}
+
+func TestHandleRemoveFileRetentionPolicy_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveFileRetentionPolicyMsg("test-colony", "/test")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveFileRetentionPolicy(ctx, "test-user", rpc.RemoveFileRetentionPolicyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleRemoveFileRetentionPolicy_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveFileRetentionPolicyMsg("test-colony", "/test")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveFileRetentionPolicy(ctx, "test-user", rpc.RemoveFileRetentionPolicyPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func createTestLineage() []*core.FileLineage {
+	return []*core.FileLineage{
+		{ColonyName: "test-colony", ProcessID: "process-1", ProcessGraphID: "graph-1", Direction: core.LineageInput, FileID: "file-1"},
+		{ColonyName: "test-colony", ProcessID: "process-1", ProcessGraphID: "graph-1", Direction: core.LineageOutput, FileID: "file-2"},
+		{ColonyName: "test-colony", ProcessID: "process-2", ProcessGraphID: "graph-1", Direction: core.LineageInput, FileID: "file-2"},
+	}
+}
+
+func TestHandleGetFileLineage_ByProcessID(t *testing.T) {
+	server, ctx := createMockServer()
+	server.lineageDB.lineage = createTestLineage()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "process-1", "", "", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	lineage, err := core.ConvertJSONToFileLineageArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 2)
+}
+
+func TestHandleGetFileLineage_ByProcessGraphIDInputs(t *testing.T) {
+	server, ctx := createMockServer()
+	server.lineageDB.lineage = createTestLineage()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "", "graph-1", "", core.LineageInput)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	lineage, err := core.ConvertJSONToFileLineageArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 2)
+	for _, l := range lineage {
+		assert.Equal(t, core.LineageInput, l.Direction)
+	}
+}
+
+func TestHandleGetFileLineage_ByFileIDOutputs(t *testing.T) {
+	server, ctx := createMockServer()
+	server.lineageDB.lineage = createTestLineage()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "", "", "file-2", core.LineageOutput)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	lineage, err := core.ConvertJSONToFileLineageArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, lineage, 1)
+	assert.Equal(t, "process-1", lineage[0].ProcessID)
+}
+
+func TestHandleGetFileLineage_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetFileLineage_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "process-1", "", "", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleGetFileLineage_InvalidDirection(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "process-1", "", "", "sideways")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetFileLineage_MissingID(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "", "", "", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetFileLineage_DBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.lineageDB.getErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetFileLineageMsg("test-colony", "process-1", "", "", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetFileLineage(ctx, "test-user", rpc.GetFileLineagePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+// Tests for HandleGCFiles
+func createGCTestServer() (*MockServer, *MockContext, []*core.StorageObject) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{core.CreateStorageBackend("test-colony", "test-backend", "localhost:9000", "test-bucket", "test-accesskey", "test-secretkey")}
+
+	issued := time.Now().Add(-time.Hour)
+	var objects []*core.StorageObject
+	for i := 0; i < 6; i++ {
+		object := core.CreateStorageObject("test-colony", "test-backend")
+		object.Issued = issued
+		objects = append(objects, object)
+	}
+	objects[4].Issued = time.Now().Add(-time.Minute)
+	objects[5].Object = core.StorageObjectPrefix("other-colony") + "test-object"
+	server.backendDB.objects = objects
+
+	// Three revisions of a file, the first one is referenced by a snapshot
+	server.fileDB.files = nil
+	for i := 0; i < 3; i++ {
+		file := createTestFile()
+		file.ID = "file-" + string(rune('1'+i))
+		file.Label = "/test"
+		file.SequenceNumber = int64(i + 1)
+		file.Added = issued
+		file.Reference = core.Reference{Protocol: "s3", Backend: "test-backend", S3Object: core.S3Object{Object: objects[i].Object}}
+		server.fileDB.files = append(server.fileDB.files, file)
+	}
+	server.fileDB.labels = []*core.Label{{Name: "/test", Files: 1}}
+	server.fileDB.fileData = []*core.FileData{{Name: "test-file.txt"}}
+	server.snapshotDB.snapshots = []*core.Snapshot{{ColonyName: "test-colony", Label: "/test", Name: "test-snapshot", FileIDs: []string{"file-1"}}}
+	server.retentionDB.policies = []*core.FileRetentionPolicy{core.CreateFileRetentionPolicy("test-colony", "/test", 1, 0)}
+
+	return server, ctx, objects
+}
+
+func TestHandleGCFiles(t *testing.T) {
+	server, ctx, objects := createGCTestServer()
+	handlers := NewHandlers(server)
+	remover := &MockObjectRemover{}
+	handlers.remover = remover
+
+	msg := rpc.CreateGCFilesMsg("test-colony", 0, true)
+	jsonString, _ := msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	// The second revision is stale, its object and the object never added as a file are unreferenced. The
+	// object issued a minute ago is within the minimum grace period, and objects outside the namespace of the
+	// colony are never removed.
+	assert.Nil(t, server.lastError)
+	report, err := core.ConvertJSONToFileGCReport(server.lastResponse)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.StaleRevisions, 1)
+	assert.Equal(t, "file-2", report.StaleRevisions[0].ID)
+	assert.Len(t, report.UnreferencedObjects, 2)
+	assert.Equal(t, objects[1].Object, report.UnreferencedObjects[0].Object)
+	assert.Equal(t, objects[3].Object, report.UnreferencedObjects[1].Object)
+	assert.ElementsMatch(t, []string{objects[0].Object, objects[2].Object, objects[4].Object}, report.RetainedObjects)
+	assert.Len(t, server.fileDB.removedIDs, 0)
+	assert.Len(t, remover.removed, 0)
+
+	msg = rpc.CreateGCFilesMsg("test-colony", 0, false)
+	jsonString, _ = msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Equal(t, []string{"file-2"}, server.fileDB.removedIDs)
+	assert.Equal(t, []string{objects[1].Object, objects[3].Object}, remover.removed)
+	assert.Equal(t, []string{objects[1].Object, objects[3].Object}, server.backendDB.removedObjects)
+
+	// A grace period longer than the age of the objects keeps them
+	server.fileDB.removedIDs = nil
+	remover.removed = nil
+	msg = rpc.CreateGCFilesMsg("test-colony", 2*3600, false)
+	jsonString, _ = msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Equal(t, []string{"file-2"}, server.fileDB.removedIDs)
+	assert.Len(t, remover.removed, 0)
+}
+
+func TestHandleGCFiles_Chunks(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	// Three revisions of a chunked file stored with a driver, the first one is referenced by a snapshot
+	server.fileDB.files = nil
+	for i := 0; i < 3; i++ {
+		file := createTestFile()
+		file.ID = "file-" + string(rune('1'+i))
+		file.Label = "/test"
+		file.SequenceNumber = int64(i + 1)
+		file.Added = time.Now().Add(-time.Hour)
+		file.Reference = core.Reference{Protocol: "s3"}
+		file.Chunks = []core.FileChunk{{Hash: "shared", Size: 1}, {Hash: "chunk" + string(rune('1'+i)), Size: 1}}
+		server.fileDB.files = append(server.fileDB.files, file)
+	}
+	server.fileDB.labels = []*core.Label{{Name: "/test", Files: 1}}
+	server.fileDB.fileData = []*core.FileData{{Name: "test-file.txt"}}
+	server.snapshotDB.snapshots = []*core.Snapshot{{ColonyName: "test-colony", Label: "/test", Name: "test-snapshot", FileIDs: []string{"file-1"}}}
+	server.retentionDB.policies = []*core.FileRetentionPolicy{core.CreateFileRetentionPolicy("test-colony", "/test", 1, 0)}
+
+	msg := rpc.CreateGCFilesMsg("test-colony", 0, true)
+	jsonString, _ := msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	// Chunks of the retained revisions are retained, the chunk only used by the stale revision is not
+	assert.Nil(t, server.lastError)
+	report, err := core.ConvertJSONToFileGCReport(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, report.StaleRevisions, 1)
+	assert.Equal(t, "file-2", report.StaleRevisions[0].ID)
+	assert.ElementsMatch(t, []string{
+		core.ChunkObject("test-colony", "shared"),
+		core.ChunkObject("test-colony", "chunk1"),
+		core.ChunkObject("test-colony", "chunk3"),
+	}, report.RetainedObjects)
+}
+
+func TestHandleGCFiles_RemoveError(t *testing.T) {
+	server, ctx, _ := createGCTestServer()
+	handlers := NewHandlers(server)
+	handlers.remover = &MockObjectRemover{err: errors.New("remove error")}
+
+	msg := rpc.CreateGCFilesMsg("test-colony", 0, false)
+	jsonString, _ := msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+	assert.Len(t, server.backendDB.removedObjects, 0)
+}
+
+func TestHandleGCFiles_InvalidMsg(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, "invalid json")
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+
+	msg := rpc.CreateGCFilesMsg("test-colony", 0, true)
+	jsonString, _ := msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", "wrong-type", jsonString)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+
+	msg = rpc.CreateGCFilesMsg("test-colony", -1, true)
+	jsonString, _ = msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGCFiles_NotMember(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("not a member")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGCFilesMsg("test-colony", 0, true)
+	jsonString, _ := msg.ToJSON()
+	handlers.HandleGCFiles(ctx, "test-user", rpc.GCFilesPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
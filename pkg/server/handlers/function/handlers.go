@@ -9,6 +9,7 @@ import (
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	"github.com/colonyos/colonies/pkg/backends"
 	log "github.com/sirupsen/logrus"
 )
@@ -19,6 +20,7 @@ type Server interface {
 	SendEmptyHTTPReply(c backends.Context, payloadType string)
 	Validator() security.Validator
 	FunctionDB() database.FunctionDatabase
+	FunctionArgsVerifier() *serverutils.FunctionArgsVerifier
 	ExecutorDB() database.ExecutorDatabase
 	UserDB() database.UserDatabase
 }
@@ -95,11 +97,18 @@ func (h *Handlers) HandleAddFunction(c backends.Context, recoveredID string, pay
 	// Auto-populate LocationName from the executor
 	msg.Function.LocationName = executor.LocationName
 
+	// Executors registered with RequireFuncReg require validation of all their functions
+	if executor.RequireFuncReg {
+		msg.Function.RequireFuncReg = true
+	}
+
 	err = h.server.FunctionDB().AddFunction(msg.Function)
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
 
+	h.server.FunctionArgsVerifier().Invalidate(msg.Function.ColonyName)
+
 	addedFunction, err := h.server.FunctionDB().GetFunctionByID(msg.Function.FunctionID)
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
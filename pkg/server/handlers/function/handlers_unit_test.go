@@ -11,6 +11,7 @@ import (
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -248,6 +249,10 @@ func (m *MockServer) FunctionDB() database.FunctionDatabase {
 	return m.functionDB
 }
 
+func (m *MockServer) FunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return serverutils.CreateFunctionArgsVerifier(nil, m.functionDB)
+}
+
 func (m *MockServer) ExecutorDB() database.ExecutorDatabase {
 	return m.executorDB
 }
@@ -309,6 +314,25 @@ func TestHandleAddFunction_Success(t *testing.T) {
 	assert.Len(t, functionDB.functions, 1)
 }
 
+func TestHandleAddFunction_InheritRequireFuncReg(t *testing.T) {
+	server, functionDB, executorDB, _, ctx := createTestMocks()
+	h := NewHandlers(server)
+
+	executor := createTestExecutor("exec-123", "test-executor", "test-colony")
+	executor.RequireFuncReg = true
+	executorDB.executors = append(executorDB.executors, executor)
+
+	function := createTestFunction("func-123", "test-func", "test-executor", "test-colony")
+	msg := rpc.CreateAddFunctionMsg(function)
+	jsonString, _ := msg.ToJSON()
+
+	h.HandleAddFunction(ctx, "exec-123", rpc.AddFunctionPayloadType, jsonString)
+
+	assert.True(t, server.httpReplyCalled)
+	assert.Len(t, functionDB.functions, 1)
+	assert.True(t, functionDB.functions[0].RequireFuncReg)
+}
+
 func TestHandleAddFunction_InvalidJSON(t *testing.T) {
 	server, _, _, _, ctx := createTestMocks()
 	h := NewHandlers(server)
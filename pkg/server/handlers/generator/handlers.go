@@ -27,7 +27,7 @@ type Server interface {
 
 type Controller interface {
 	AddGenerator(generator *core.Generator) (*core.Generator, error)
-	PackGenerator(generatorID string, colonyName string, arg string) error
+	PackGenerator(generatorID string, colonyName string, key string, arg string) error
 	RemoveGenerator(generatorID string) error
 	GetGeneratorPeriod() int
 }
@@ -98,6 +98,11 @@ func (h *Handlers) HandleAddGenerator(c backends.Context, recoveredID string, pa
 		return
 	}
 
+	err = msg.Generator.ValidateTriggers()
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	msg.Generator.ID = core.GenerateRandomID()
 
 	initiatorName, err := h.resolveInitiator(msg.Generator.ColonyName, recoveredID)
@@ -268,12 +273,12 @@ func (h *Handlers) HandlePackGenerator(c backends.Context, recoveredID string, p
 		return
 	}
 
-	err = h.server.GeneratorController().PackGenerator(generator.ID, generator.ColonyName, msg.Arg)
+	err = h.server.GeneratorController().PackGenerator(generator.ID, generator.ColonyName, msg.Key, msg.Arg)
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
 	}
 
-	log.WithFields(log.Fields{"GeneratorId": generator.ID, "Arg": msg.Arg}).Debug("Adding arg to generator")
+	log.WithFields(log.Fields{"GeneratorId": generator.ID, "Key": msg.Key, "Arg": msg.Arg}).Debug("Adding arg to generator")
 
 	h.server.SendEmptyHTTPReply(c, payloadType)
 }
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -341,6 +342,90 @@ func TestAddGeneratorTimeout3(t *testing.T) {
 	<-done
 }
 
+// Args packed with different keys are batched separately
+func TestAddGeneratorKeyed(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	colonyName := env.ColonyName
+
+	generator := utils.FakeGenerator(t, colonyName, env.ExecutorID, env.ExecutorName)
+	generator.Trigger = 2
+	generator.BatchFormat = core.GeneratorBatchStructured
+	addedGenerator, err := client.AddGenerator(generator, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedGenerator)
+
+	err = client.PackGeneratorWithKey(addedGenerator.ID, "tenant1", "arg1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.PackGeneratorWithKey(addedGenerator.ID, "tenant2", "arg2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.PackGeneratorWithKey(addedGenerator.ID, "tenant1", "arg3", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	server.WaitForProcessGraphs(t, client, colonyName, addedGenerator.ID, env.ExecutorPrvKey, 1)
+
+	// The tenant2 batch is not full yet
+	graphs, err := client.GetWaitingProcessGraphs(colonyName, 100, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, graphs, 1)
+
+	process, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, process.FunctionSpec.Args, 1)
+	batch, ok := process.FunctionSpec.Args[0].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "tenant1", batch["key"])
+	assert.Equal(t, []interface{}{"arg1", "arg3"}, batch["args"])
+
+	s.Shutdown()
+	<-done
+}
+
+func TestAddGeneratorTriggerSize(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	colonyName := env.ColonyName
+
+	generator := utils.FakeGenerator(t, colonyName, env.ExecutorID, env.ExecutorName)
+	generator.Trigger = -1
+	generator.TriggerSize = 10
+	addedGenerator, err := client.AddGenerator(generator, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedGenerator)
+
+	for i := 0; i < 3; i++ {
+		err = client.PackGenerator(addedGenerator.ID, "1234", env.ExecutorPrvKey)
+		assert.Nil(t, err)
+	}
+
+	server.WaitForProcessGraphs(t, client, colonyName, addedGenerator.ID, env.ExecutorPrvKey, 1)
+
+	process, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, process.FunctionSpec.Args, 2)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestAddGeneratorInvalidWindow(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	generator := utils.FakeGenerator(t, env.ColonyName, env.ExecutorID, env.ExecutorName)
+	generator.WindowType = core.GeneratorWindowTumbling
+	generator.WindowSize = 60
+	_, err := client.AddGenerator(generator, env.ExecutorPrvKey)
+	assert.NotNil(t, err) // Count triggers cannot be combined with windows
+
+	generator.Trigger = -1
+	addedGenerator, err := client.AddGenerator(generator, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.True(t, addedGenerator.IsWindowed())
+
+	s.Shutdown()
+	<-done
+}
+
 // TestAddGeneratorWithUserAsInitiator tests that a user can create a generator (covers resolveInitiator user path)
 func TestAddGeneratorWithUserAsInitiator(t *testing.T) {
 	env, client, s, _, done := server.SetupTestEnv2(t)
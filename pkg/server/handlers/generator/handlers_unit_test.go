@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
@@ -43,6 +44,14 @@ func (m *MockGeneratorDB) SetGeneratorFirstPack(generatorID string) error {
 	return nil
 }
 
+func (m *MockGeneratorDB) SetGeneratorLastWindowEnd(generatorID string, windowEnd time.Time) error {
+	return nil
+}
+
+func (m *MockGeneratorDB) SetGeneratorWindowKeys(generatorID string, windowEnd time.Time, keys []string) error {
+	return nil
+}
+
 func (m *MockGeneratorDB) GetGeneratorByID(generatorID string) (*core.Generator, error) {
 	if m.getByIDErr != nil {
 		return nil, m.getByIDErr
@@ -107,6 +116,18 @@ func (m *MockGeneratorDB) GetGeneratorArgs(generatorID string, count int) ([]*co
 	return m.generatorArgs, nil
 }
 
+func (m *MockGeneratorDB) GetGeneratorArgsByKey(generatorID string, key string, count int) ([]*core.GeneratorArg, error) {
+	return m.generatorArgs, nil
+}
+
+func (m *MockGeneratorDB) GetGeneratorArgsByKeyAndTime(generatorID string, key string, from time.Time, to time.Time) ([]*core.GeneratorArg, error) {
+	return m.generatorArgs, nil
+}
+
+func (m *MockGeneratorDB) GetGeneratorArgGroups(generatorID string) ([]*core.GeneratorArgGroup, error) {
+	return nil, nil
+}
+
 func (m *MockGeneratorDB) CountGeneratorArgs(generatorID string) (int, error) {
 	if m.countArgsErr != nil {
 		return 0, m.countArgsErr
@@ -118,6 +139,10 @@ func (m *MockGeneratorDB) RemoveGeneratorArgByID(generatorArgsID string) error {
 	return nil
 }
 
+func (m *MockGeneratorDB) RemoveGeneratorArgsAddedBefore(generatorID string, before time.Time) error {
+	return nil
+}
+
 func (m *MockGeneratorDB) RemoveAllGeneratorArgsByGeneratorID(generatorID string) error {
 	return nil
 }
@@ -201,6 +226,7 @@ type MockController struct {
 	packErr     error
 	removeErr   error
 	addedGen    *core.Generator
+	packedKey   string
 	period      int
 	returnNil   bool
 }
@@ -216,10 +242,11 @@ func (m *MockController) AddGenerator(generator *core.Generator) (*core.Generato
 	return generator, nil
 }
 
-func (m *MockController) PackGenerator(generatorID string, colonyName string, arg string) error {
+func (m *MockController) PackGenerator(generatorID string, colonyName string, key string, arg string) error {
 	if m.packErr != nil {
 		return m.packErr
 	}
+	m.packedKey = key
 	return nil
 }
 
@@ -491,6 +518,21 @@ func TestHandleAddGenerator_ReturnsNil(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
 }
 
+func TestHandleAddGenerator_InvalidTriggers(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	generator := createTestGenerator()
+	generator.WindowType = "invalid"
+	msg := rpc.CreateAddGeneratorMsg(generator)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddGenerator(ctx, "executor-123", rpc.AddGeneratorPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Nil(t, server.controller.addedGen)
+}
+
 // Tests for HandleGetGenerator
 func TestHandleGetGenerator_Success(t *testing.T) {
 	server, ctx := createMockServer()
@@ -658,6 +700,20 @@ func TestHandlePackGenerator_Success(t *testing.T) {
 	assert.True(t, server.emptyReplySent)
 }
 
+func TestHandlePackGenerator_WithKey(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreatePackGeneratorMsg("generator-123", "test-arg")
+	msg.Key = "tenant1"
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePackGenerator(ctx, "test-user", rpc.PackGeneratorPayloadType, jsonString)
+
+	assert.True(t, server.emptyReplySent)
+	assert.Equal(t, "tenant1", server.controller.packedKey)
+}
+
 func TestHandlePackGenerator_InvalidJSON(t *testing.T) {
 	server, ctx := createMockServer()
 	handlers := NewHandlers(server)
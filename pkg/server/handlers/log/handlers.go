@@ -8,6 +8,8 @@ import (
 
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
@@ -27,6 +29,8 @@ type Server interface {
 	ExecutorDB() database.ExecutorDatabase
 	ProcessDB() database.ProcessDatabase
 	LogDB() database.LogDatabase
+	LogBroker() *logstream.Broker
+	LogSinkPipeline() *logsink.Pipeline
 }
 
 type Handlers struct {
@@ -108,14 +112,37 @@ func (h *Handlers) HandleAddLog(c backends.Context, recoveredID string, payloadT
 		return
 	}
 
-	colonyName := process.FunctionSpec.Conditions.ColonyName
-	err = h.server.LogDB().AddLog(process.ID, colonyName, executor.Name, time.Now().UTC().UnixNano(), msg.Message)
+	err = core.ValidateLogLevel(msg.Level)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	err = core.ValidateLogStream(msg.Stream)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	logEntry := &core.Log{
+		ProcessID:      process.ID,
+		ProcessGraphID: process.ProcessGraphID,
+		ColonyName:     process.FunctionSpec.Conditions.ColonyName,
+		ExecutorName:   executor.Name,
+		Level:          msg.Level,
+		Stream:         msg.Stream,
+		Fields:         msg.Fields,
+		Message:        msg.Message,
+		Timestamp:      time.Now().UTC().UnixNano(),
+	}
+	err = h.server.LogDB().AddStructuredLog(logEntry)
 	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to add log")
 		h.server.HandleHTTPError(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	h.server.LogBroker().Publish(logEntry)
+	h.server.LogSinkPipeline().Forward(logEntry)
+
 	log.WithFields(log.Fields{"ProcessId": process.ID}).Debug("Adding log")
 
 	h.server.SendEmptyHTTPReply(c, rpc.AddLogPayloadType)
@@ -166,13 +193,17 @@ func (h *Handlers) HandleAddExecutorLog(c backends.Context, recoveredID string,
 
 	// Add the log with a special process ID to indicate executor-level log
 	// Using empty processID which will be stored and retrievable via executor name
-	err = h.server.LogDB().AddLog("", msg.ColonyName, executor.Name, time.Now().UTC().UnixNano(), msg.Message)
+	logEntry := &core.Log{ColonyName: msg.ColonyName, ExecutorName: executor.Name, Message: msg.Message, Timestamp: time.Now().UTC().UnixNano()}
+	err = h.server.LogDB().AddStructuredLog(logEntry)
 	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
 		log.WithFields(log.Fields{"Error": err}).Debug("Failed to add executor log")
 		h.server.HandleHTTPError(c, err, http.StatusInternalServerError)
 		return
 	}
 
+	h.server.LogBroker().Publish(logEntry)
+	h.server.LogSinkPipeline().Forward(logEntry)
+
 	log.WithFields(log.Fields{"ExecutorName": executor.Name}).Debug("Adding executor log")
 
 	h.server.SendEmptyHTTPReply(c, rpc.AddExecutorLogPayloadType)
@@ -234,7 +265,39 @@ func (h *Handlers) HandleGetLogs(c backends.Context, recoveredID string, payload
 	}
 
 	var logs []*core.Log
-	if msg.ExecutorName != "" {
+	if msg.HasFilter() {
+		err = core.ValidateLogLevel(msg.Level)
+		if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+			return
+		}
+
+		err = core.ValidateLogStream(msg.Stream)
+		if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+			return
+		}
+
+		filter := &core.LogFilter{
+			Level:  msg.Level,
+			Stream: msg.Stream,
+			Fields: msg.Fields,
+			Since:  msg.Since,
+			Until:  msg.Until,
+			Latest: msg.Latest,
+			Count:  msg.Count,
+		}
+		if msg.ExecutorName != "" {
+			filter.ColonyName = msg.ColonyName
+			filter.ExecutorName = msg.ExecutorName
+		} else {
+			filter.ProcessID = msg.ProcessID
+		}
+
+		logs, err = h.server.LogDB().GetLogs(filter)
+		if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+			log.WithFields(log.Fields{"Error": err, "ColonyName": msg.ColonyName}).Debug("Failed to get filtered logs")
+			return
+		}
+	} else if msg.ExecutorName != "" {
 		if msg.Latest {
 			// Get latest logs (most recent count logs)
 			logs, err = h.server.LogDB().GetLogsByExecutorLatest(msg.ExecutorName, msg.Count)
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/server"
 	"github.com/colonyos/colonies/pkg/utils"
 	"github.com/stretchr/testify/assert"
@@ -77,6 +78,46 @@ func TestAddGetLogSinceByProcess(t *testing.T) {
 	<-done
 }
 
+func TestAddGetStructuredLogByProcess(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	funcSpec1 := utils.CreateTestFunctionSpec(env.ColonyName)
+	_, err := client.Submit(funcSpec1, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelDebug, core.LogStreamStdout, map[string]string{"step": "1"}, "test_msg1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelError, core.LogStreamStderr, map[string]string{"step": "2"}, "test_msg2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.AddLog(assignedProcess.ID, "test_msg3", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, "verbose", "", nil, "test_msg4", env.ExecutorPrvKey)
+	assert.NotNil(t, err) // Invalid log level
+
+	logs, err := client.GetLogs(&core.LogFilter{ColonyName: env.ColonyName, ProcessID: assignedProcess.ID, Level: core.LogLevelInfo, Count: 100}, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "test_msg2", logs[0].Message)
+	assert.Equal(t, core.LogStreamStderr, logs[0].Stream)
+	assert.Equal(t, env.ExecutorName, logs[0].ExecutorName)
+
+	logs, err = client.GetLogs(&core.LogFilter{ColonyName: env.ColonyName, ProcessID: assignedProcess.ID, Fields: map[string]string{"step": "1"}, Count: 100}, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "test_msg1", logs[0].Message)
+
+	logs, err = client.GetLogsByProcess(env.ColonyName, assignedProcess.ID, 100, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, logs, 3)
+
+	server.Shutdown()
+	<-done
+}
+
 func TestAddGetLogByExecutor(t *testing.T) {
 	env, client, server, _, done := server.SetupTestEnv2(t)
 
@@ -3,11 +3,17 @@ package log
 import (
 	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/backends"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
@@ -357,6 +363,10 @@ func (m *MockProcessDB) Unassign(process *core.Process) error {
 	return nil
 }
 
+func (m *MockProcessDB) Release(process *core.Process) error {
+	return nil
+}
+
 func (m *MockProcessDB) MarkSuccessful(processID string) (float64, float64, error) {
 	return 0, 0, nil
 }
@@ -410,6 +420,8 @@ func (m *MockProcessDB) CountCancelledProcessesByColonyName(string) (int, error)
 
 type MockLogDB struct {
 	logs      []*core.Log
+	addedLog  *core.Log
+	filter    *core.LogFilter
 	addLogErr error
 	getLogErr error
 }
@@ -418,6 +430,16 @@ func (m *MockLogDB) AddLog(processID string, colonyName string, executorName str
 	return m.addLogErr
 }
 
+func (m *MockLogDB) AddStructuredLog(log *core.Log) error {
+	m.addedLog = log
+	return m.addLogErr
+}
+
+func (m *MockLogDB) GetLogs(filter *core.LogFilter) ([]*core.Log, error) {
+	m.filter = filter
+	return m.logs, m.getLogErr
+}
+
 func (m *MockLogDB) GetLogsByProcessID(processID string, limit int) ([]*core.Log, error) {
 	return m.logs, m.getLogErr
 }
@@ -459,6 +481,8 @@ type MockServer struct {
 	executorDB   *MockExecutorDB
 	processDB    *MockProcessDB
 	logDB        *MockLogDB
+	logBroker    *logstream.Broker
+	logSinks     *logsink.Pipeline
 	httpError    bool
 	lastErrCode  int
 	lastResponse string
@@ -497,12 +521,30 @@ func (m *MockServer) LogDB() database.LogDatabase {
 	return m.logDB
 }
 
+func (m *MockServer) LogBroker() *logstream.Broker {
+	return m.logBroker
+}
+
+func (m *MockServer) LogSinkPipeline() *logsink.Pipeline {
+	return m.logSinks
+}
+
+type MockLogSinkStore struct {
+	sinks []*core.LogSink
+}
+
+func (m *MockLogSinkStore) GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error) {
+	return m.sinks, nil
+}
+
 func createMockServer() *MockServer {
 	return &MockServer{
 		validator:  &MockValidator{},
 		executorDB: &MockExecutorDB{},
 		processDB:  &MockProcessDB{},
 		logDB:      &MockLogDB{},
+		logBroker:  logstream.CreateBroker(nil),
+		logSinks:   logsink.CreatePipeline(&MockLogSinkStore{}, ""),
 	}
 }
 
@@ -1059,6 +1101,249 @@ func TestHandleGetLogsByExecutorSinceUnit(t *testing.T) {
 	assert.False(t, server.httpError)
 }
 
+func TestHandleAddStructuredLogUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID:                 "process-id",
+		ProcessGraphID:     "processgraph-id",
+		State:              core.RUNNING,
+		AssignedExecutorID: "test-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.executorDB.executor = &core.Executor{
+		ID:   "test-id",
+		Name: "test-executor",
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateAddStructuredLogMsg("process-id", core.LogLevelWarn, core.LogStreamStderr, map[string]string{"step": "1"}, "test message")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+	assert.NotNil(t, server.logDB.addedLog)
+	assert.Equal(t, "processgraph-id", server.logDB.addedLog.ProcessGraphID)
+	assert.Equal(t, "test-colony", server.logDB.addedLog.ColonyName)
+	assert.Equal(t, "test-executor", server.logDB.addedLog.ExecutorName)
+	assert.Equal(t, core.LogLevelWarn, server.logDB.addedLog.Level)
+	assert.Equal(t, core.LogStreamStderr, server.logDB.addedLog.Stream)
+	assert.Equal(t, "1", server.logDB.addedLog.Fields["step"])
+}
+
+func TestHandleAddLogPublishUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID:                 "process-id",
+		State:              core.RUNNING,
+		AssignedExecutorID: "test-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.executorDB.executor = &core.Executor{
+		ID:   "test-id",
+		Name: "test-executor",
+	}
+	subscriber := server.logBroker.Subscribe(&core.LogFilter{ProcessID: "process-id"})
+	otherSubscriber := server.logBroker.Subscribe(&core.LogFilter{ProcessID: "other-process-id"})
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateAddLogMsg("process-id", "test message")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+	assert.Len(t, subscriber.C, 1)
+	assert.Len(t, otherSubscriber.C, 0)
+	logEntry := <-subscriber.C
+	assert.Equal(t, "test message", logEntry.Message)
+
+	server.logDB.addLogErr = errors.New("db error")
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Len(t, subscriber.C, 0)
+}
+
+func TestHandleAddLogForwardUnit(t *testing.T) {
+	dir := t.TempDir()
+	sink := core.CreateLogSink("test-colony", "test-sink", core.LogSinkTypeFile, "logs.jsonl")
+	sink.FlushInterval = 10
+
+	server := createMockServer()
+	server.logSinks = logsink.CreatePipeline(&MockLogSinkStore{sinks: []*core.LogSink{sink}}, dir)
+	defer server.logSinks.Stop()
+	server.processDB.process = &core.Process{
+		ID:                 "process-id",
+		State:              core.RUNNING,
+		AssignedExecutorID: "test-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.executorDB.executor = &core.Executor{
+		ID:   "test-id",
+		Name: "test-executor",
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateAddLogMsg("process-id", "forwarded message")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(filepath.Join(dir, "test-colony", "logs.jsonl"))
+		return err == nil && strings.Contains(string(data), "forwarded message")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHandleAddStructuredLogInvalidLevelUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID:                 "process-id",
+		State:              core.RUNNING,
+		AssignedExecutorID: "test-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.executorDB.executor = &core.Executor{
+		ID:   "test-id",
+		Name: "test-executor",
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateAddStructuredLogMsg("process-id", "verbose", "", nil, "test message")
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Nil(t, server.logDB.addedLog)
+
+	server.httpError = false
+	msg = rpc.CreateAddStructuredLogMsg("process-id", "", "stdin", nil, "test message")
+	jsonStr, _ = msg.ToJSON()
+
+	handlers.HandleAddLog(ctx, "test-id", rpc.AddLogPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Nil(t, server.logDB.addedLog)
+}
+
+func TestHandleGetLogsByProcessFilterUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID: "process-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.logDB.logs = []*core.Log{}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateGetLogsMsg("test-colony", "process-id", 100, 10)
+	msg.Level = core.LogLevelWarn
+	msg.Fields = map[string]string{"step": "1"}
+	msg.Until = 20
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleGetLogs(ctx, "test-id", rpc.GetLogsPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+	assert.NotNil(t, server.logDB.filter)
+	assert.Equal(t, "process-id", server.logDB.filter.ProcessID)
+	assert.Equal(t, "", server.logDB.filter.ExecutorName)
+	assert.Equal(t, core.LogLevelWarn, server.logDB.filter.Level)
+	assert.Equal(t, "1", server.logDB.filter.Fields["step"])
+	assert.Equal(t, int64(10), server.logDB.filter.Since)
+	assert.Equal(t, int64(20), server.logDB.filter.Until)
+	assert.Equal(t, 100, server.logDB.filter.Count)
+}
+
+func TestHandleGetLogsByExecutorFilterUnit(t *testing.T) {
+	server := createMockServer()
+	server.executorDB.executor = &core.Executor{
+		ID:         "test-id",
+		Name:       "test-executor",
+		ColonyName: "test-colony",
+	}
+	server.logDB.logs = []*core.Log{}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateGetLogsMsg("test-colony", "", 100, 0)
+	msg.ExecutorName = "test-executor"
+	msg.Stream = core.LogStreamStderr
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleGetLogs(ctx, "test-id", rpc.GetLogsPayloadType, jsonStr)
+	assert.False(t, server.httpError)
+	assert.NotNil(t, server.logDB.filter)
+	assert.Equal(t, "test-colony", server.logDB.filter.ColonyName)
+	assert.Equal(t, "test-executor", server.logDB.filter.ExecutorName)
+	assert.Equal(t, core.LogStreamStderr, server.logDB.filter.Stream)
+}
+
+func TestHandleGetLogsFilterInvalidLevelUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID: "process-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateGetLogsMsg("test-colony", "process-id", 100, 0)
+	msg.Level = "verbose"
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleGetLogs(ctx, "test-id", rpc.GetLogsPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+	assert.Nil(t, server.logDB.filter)
+}
+
+func TestHandleGetLogsFilterDBErrorUnit(t *testing.T) {
+	server := createMockServer()
+	server.processDB.process = &core.Process{
+		ID: "process-id",
+		FunctionSpec: core.FunctionSpec{
+			Conditions: core.Conditions{
+				ColonyName: "test-colony",
+			},
+		},
+	}
+	server.logDB.getLogErr = errors.New("db error")
+	handlers := NewHandlers(server)
+	ctx := &MockContext{}
+
+	msg := rpc.CreateGetLogsMsg("test-colony", "process-id", 100, 0)
+	msg.Level = core.LogLevelError
+	jsonStr, _ := msg.ToJSON()
+
+	handlers.HandleGetLogs(ctx, "test-id", rpc.GetLogsPayloadType, jsonStr)
+	assert.True(t, server.httpError)
+}
+
 func TestHandleSearchLogsInvalidJSONUnit(t *testing.T) {
 	server := createMockServer()
 	handlers := NewHandlers(server)
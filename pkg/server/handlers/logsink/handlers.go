@@ -0,0 +1,208 @@
+package logsink
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	log "github.com/sirupsen/logrus"
+)
+
+type Server interface {
+	HandleHTTPError(c backends.Context, err error, errorCode int) bool
+	SendHTTPReply(c backends.Context, payloadType string, jsonString string)
+	SendEmptyHTTPReply(c backends.Context, payloadType string)
+	GetLogSinkDB() database.LogSinkDatabase
+	GetColonyDB() database.ColonyDatabase
+	GetValidator() security.Validator
+	LogSinkPipeline() *logsink.Pipeline
+}
+
+type Handlers struct {
+	server Server
+}
+
+func NewHandlers(server Server) *Handlers {
+	return &Handlers{
+		server: server,
+	}
+}
+
+func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) error {
+	if err := handlerRegistry.Register(rpc.AddLogSinkPayloadType, h.HandleAddLogSink); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetLogSinksPayloadType, h.HandleGetLogSinks); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RemoveLogSinkPayloadType, h.HandleRemoveLogSink); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *Handlers) HandleAddLogSink(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddLogSinkMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to add log sink, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to add log sink, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.LogSink == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add log sink, log sink is nil"), http.StatusBadRequest)
+		return
+	}
+
+	sink := msg.LogSink
+	err = h.server.LogSinkPipeline().Validate(sink)
+	if err != nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add log sink, "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(sink.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+sink.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	sinkExist, err := h.server.GetLogSinkDB().GetLogSinkByName(sink.ColonyName, sink.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if sinkExist != nil {
+		if h.server.HandleHTTPError(c, errors.New("A log sink with name <"+sink.Name+"> already exists in Colony with name <"+sink.ColonyName+">"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	sink.ID = core.GenerateRandomID()
+	err = h.server.GetLogSinkDB().AddLogSink(sink)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	addedSink, err := h.server.GetLogSinkDB().GetLogSinkByName(colony.Name, sink.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if addedSink == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add log sink, addedSink is nil"), http.StatusInternalServerError)
+		return
+	}
+
+	h.server.LogSinkPipeline().Reload(colony.Name)
+
+	jsonString, err = addedSink.Redact().ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": addedSink.ColonyName, "Name": addedSink.Name, "Type": addedSink.Type, "LogSinkID": addedSink.ID}).Debug("Adding log sink")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleGetLogSinks(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetLogSinksMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get log sinks, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get log sinks, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	sinks, err := h.server.GetLogSinkDB().GetLogSinksByColonyName(msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	redactedSinks := make([]*core.LogSink, 0, len(sinks))
+	for _, sink := range sinks {
+		redactedSinks = append(redactedSinks, sink.Redact())
+	}
+
+	jsonString, err = core.ConvertLogSinkArrayToJSON(redactedSinks)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting log sinks")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleRemoveLogSink(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveLogSinkMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to remove log sink, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to remove log sink, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	sink, err := h.server.GetLogSinkDB().GetLogSinkByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if sink == nil {
+		h.server.HandleHTTPError(c, errors.New("Log sink with name <"+msg.Name+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	err = h.server.GetLogSinkDB().RemoveLogSinkByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	h.server.LogSinkPipeline().Reload(msg.ColonyName)
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Name": msg.Name}).Debug("Removing log sink")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
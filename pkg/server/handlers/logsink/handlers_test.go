@@ -0,0 +1,102 @@
+package logsink_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/colonyos/colonies/pkg/server"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddLogSink(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	sink := utils.CreateTestLogSink(env.ColonyName, "test_sink")
+	addedSink, err := client.AddLogSink(sink, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedSink)
+	assert.Equal(t, "test_sink", addedSink.Name)
+	assert.Equal(t, sink.Target, addedSink.Target)
+	assert.Empty(t, addedSink.Headers["Authorization"])
+
+	// Same name again should fail
+	_, err = client.AddLogSink(utils.CreateTestLogSink(env.ColonyName, "test_sink"), env.ColonyPrvKey)
+	assert.NotNil(t, err)
+
+	// Only the colony owner may manage log sinks
+	_, err = client.AddLogSink(utils.CreateTestLogSink(env.ColonyName, "test_sink2"), env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = client.GetLogSinks(env.ColonyName, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	sinks, err := client.GetLogSinks(env.ColonyName, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 1)
+
+	err = client.RemoveLogSink(env.ColonyName, "test_sink", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.RemoveLogSink(env.ColonyName, "test_sink", env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	sinks, err = client.GetLogSinks(env.ColonyName, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 0)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestLogSinkForwardsLogs(t *testing.T) {
+	// The endpoint listens on loopback, which is denied by default
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	received := make(chan []*core.Log, 10)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var logs []*core.Log
+		json.Unmarshal(body, &logs)
+		received <- logs
+	}))
+	defer endpoint.Close()
+
+	sink := core.CreateLogSink(env.ColonyName, "test_sink", core.LogSinkTypeHTTP, endpoint.URL)
+	sink.BatchSize = 2
+	_, err := client.AddLogSink(sink, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.ColonyName)
+	_, err = client.Submit(funcSpec, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddStructuredLog(assignedProcess.ID, core.LogLevelInfo, core.LogStreamStdout, nil, "test_msg1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.AddLog(assignedProcess.ID, "test_msg2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case logs := <-received:
+		assert.Len(t, logs, 2)
+		assert.Equal(t, "test_msg1", logs[0].Message)
+		assert.Equal(t, assignedProcess.ID, logs[0].ProcessID)
+		assert.Equal(t, "test_msg2", logs[1].Message)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for log sink batch")
+	}
+
+	s.Shutdown()
+	<-done
+}
@@ -0,0 +1,505 @@
+package logsink
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockLogSinkDB implements database.LogSinkDatabase
+type MockLogSinkDB struct {
+	sinks           []*core.LogSink
+	addErr          error
+	getByNameErr    error
+	removeByNameErr error
+	removed         string
+}
+
+func (m *MockLogSinkDB) AddLogSink(sink *core.LogSink) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.sinks = append(m.sinks, sink)
+	return nil
+}
+
+func (m *MockLogSinkDB) GetLogSinkByName(colonyName string, name string) (*core.LogSink, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	for _, s := range m.sinks {
+		if s.Name == name && s.ColonyName == colonyName {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockLogSinkDB) GetLogSinksByColonyName(colonyName string) ([]*core.LogSink, error) {
+	return m.sinks, nil
+}
+
+func (m *MockLogSinkDB) RemoveLogSinkByName(colonyName string, name string) error {
+	if m.removeByNameErr != nil {
+		return m.removeByNameErr
+	}
+	m.removed = name
+	return nil
+}
+
+func (m *MockLogSinkDB) RemoveLogSinksByColonyName(colonyName string) error {
+	return nil
+}
+
+// MockColonyDB implements database.ColonyDatabase
+type MockColonyDB struct {
+	colonies     []*core.Colony
+	getByNameErr error
+	returnNil    bool
+}
+
+func (m *MockColonyDB) AddColony(colony *core.Colony) error                 { return nil }
+func (m *MockColonyDB) GetColonies() ([]*core.Colony, error)                { return nil, nil }
+func (m *MockColonyDB) GetColonyByID(colonyID string) (*core.Colony, error) { return nil, nil }
+func (m *MockColonyDB) RemoveColonyByName(colonyName string) error          { return nil }
+func (m *MockColonyDB) RemoveColonies() error                               { return nil }
+func (m *MockColonyDB) CountColonies() (int, error)                         { return 0, nil }
+func (m *MockColonyDB) RenameColony(colonyName, newName string) error       { return nil }
+
+func (m *MockColonyDB) GetColonyByName(colonyName string) (*core.Colony, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	if m.returnNil {
+		return nil, nil
+	}
+	for _, c := range m.colonies {
+		if c.Name == colonyName {
+			return c, nil
+		}
+	}
+	return &core.Colony{ID: "colony-123", Name: colonyName}, nil
+}
+
+// MockValidator implements security.Validator
+type MockValidator struct {
+	membershipErr  error
+	colonyOwnerErr error
+	serverOwnerErr error
+}
+
+func (m *MockValidator) RequireMembership(recoveredID string, colonyName string, executorMayJoin bool) error {
+	return m.membershipErr
+}
+
+func (m *MockValidator) RequireColonyOwner(recoveredID string, colonyName string) error {
+	return m.colonyOwnerErr
+}
+
+func (m *MockValidator) RequireServerOwner(recoveredID string, serverID string) error {
+	return m.serverOwnerErr
+}
+
+// MockContext implements backends.Context
+type MockContext struct {
+	aborted               bool
+	abortedWithStatus     int
+	abortedWithStatusJSON int
+	jsonResponse          interface{}
+}
+
+func (m *MockContext) String(code int, format string, values ...interface{}) {}
+func (m *MockContext) JSON(code int, obj interface{})                        { m.jsonResponse = obj }
+func (m *MockContext) XML(code int, obj interface{})                         {}
+func (m *MockContext) Data(code int, contentType string, data []byte)        {}
+func (m *MockContext) Status(code int)                                       {}
+func (m *MockContext) Request() *http.Request                                { return nil }
+func (m *MockContext) ReadBody() ([]byte, error)                             { return nil, nil }
+func (m *MockContext) GetHeader(key string) string                           { return "" }
+func (m *MockContext) Header(key, value string)                              {}
+func (m *MockContext) Param(key string) string                               { return "" }
+func (m *MockContext) Query(key string) string                               { return "" }
+func (m *MockContext) DefaultQuery(key, defaultValue string) string          { return defaultValue }
+func (m *MockContext) PostForm(key string) string                            { return "" }
+func (m *MockContext) DefaultPostForm(key, defaultValue string) string       { return defaultValue }
+func (m *MockContext) Bind(obj interface{}) error                            { return nil }
+func (m *MockContext) ShouldBind(obj interface{}) error                      { return nil }
+func (m *MockContext) BindJSON(obj interface{}) error                        { return nil }
+func (m *MockContext) ShouldBindJSON(obj interface{}) error                  { return nil }
+func (m *MockContext) Set(key string, value interface{})                     {}
+func (m *MockContext) Get(key string) (value interface{}, exists bool)       { return nil, false }
+func (m *MockContext) GetString(key string) string                           { return "" }
+func (m *MockContext) GetBool(key string) bool                               { return false }
+func (m *MockContext) GetInt(key string) int                                 { return 0 }
+func (m *MockContext) GetInt64(key string) int64                             { return 0 }
+func (m *MockContext) GetFloat64(key string) float64                         { return 0 }
+func (m *MockContext) Abort()                                                { m.aborted = true }
+func (m *MockContext) AbortWithStatus(code int) {
+	m.abortedWithStatus = code
+	m.aborted = true
+}
+func (m *MockContext) AbortWithStatusJSON(code int, jsonObj interface{}) {
+	m.abortedWithStatusJSON = code
+	m.jsonResponse = jsonObj
+	m.aborted = true
+}
+func (m *MockContext) IsAborted() bool { return m.aborted }
+func (m *MockContext) Next()           {}
+
+// MockServer implements Server interface
+type MockServer struct {
+	sinkDB          *MockLogSinkDB
+	colonyDB        *MockColonyDB
+	validator       *MockValidator
+	pipeline        *logsink.Pipeline
+	lastError       error
+	lastStatusCode  int
+	lastPayloadType string
+	lastResponse    string
+	emptyReplySent  bool
+}
+
+func (m *MockServer) HandleHTTPError(c backends.Context, err error, errorCode int) bool {
+	if err != nil {
+		m.lastError = err
+		m.lastStatusCode = errorCode
+		c.AbortWithStatusJSON(errorCode, map[string]string{"error": err.Error()})
+		return true
+	}
+	return false
+}
+
+func (m *MockServer) SendHTTPReply(c backends.Context, payloadType string, jsonString string) {
+	m.lastPayloadType = payloadType
+	m.lastResponse = jsonString
+	c.JSON(http.StatusOK, map[string]string{"response": jsonString})
+}
+
+func (m *MockServer) SendEmptyHTTPReply(c backends.Context, payloadType string) {
+	m.lastPayloadType = payloadType
+	m.emptyReplySent = true
+	c.JSON(http.StatusOK, nil)
+}
+
+func (m *MockServer) GetValidator() security.Validator {
+	return m.validator
+}
+
+func (m *MockServer) GetLogSinkDB() database.LogSinkDatabase {
+	return m.sinkDB
+}
+
+func (m *MockServer) GetColonyDB() database.ColonyDatabase {
+	return m.colonyDB
+}
+
+func (m *MockServer) LogSinkPipeline() *logsink.Pipeline {
+	return m.pipeline
+}
+
+// Helper to create test log sink
+func createTestLogSink() *core.LogSink {
+	sink := core.CreateLogSink("test-colony", "test-sink", core.LogSinkTypeOTLP, "http://203.0.113.10:4318/v1/logs")
+	sink.Headers["Authorization"] = "Bearer test-token"
+	return sink
+}
+
+// Helper to create mock server, file sinks are disabled since no log sink directory is set
+func createMockServer(t *testing.T) (*MockServer, *MockContext) {
+	sinkDB := &MockLogSinkDB{sinks: []*core.LogSink{createTestLogSink()}}
+	pipeline := logsink.CreatePipeline(sinkDB, "")
+	t.Cleanup(pipeline.Stop)
+
+	server := &MockServer{
+		sinkDB:    sinkDB,
+		colonyDB:  &MockColonyDB{},
+		validator: &MockValidator{},
+		pipeline:  pipeline,
+	}
+
+	ctx := &MockContext{}
+	return server, ctx
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	server, _ := createMockServer(t)
+	handlers := NewHandlers(server)
+	reg := registry.NewHandlerRegistry()
+
+	err := handlers.RegisterHandlers(reg)
+	assert.Nil(t, err)
+}
+
+// Tests for HandleAddLogSink
+func TestHandleAddLogSink_Success(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Equal(t, rpc.AddLogSinkPayloadType, server.lastPayloadType)
+
+	addedSink, err := core.ConvertJSONToLogSink(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-sink", addedSink.Name)
+	assert.Equal(t, core.LogSinkTypeOTLP, addedSink.Type)
+
+	// Header values are never sent back
+	value, ok := addedSink.Headers["Authorization"]
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+	assert.Equal(t, "Bearer test-token", server.sinkDB.sinks[0].Headers["Authorization"])
+}
+
+func TestHandleAddLogSink_DeniedTarget(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	handlers := NewHandlers(server)
+
+	sink := createTestLogSink()
+	sink.Target = "http://169.254.169.254/latest/meta-data"
+	msg := rpc.CreateAddLogSinkMsg(sink)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.sinkDB.sinks, 0)
+}
+
+func TestHandleAddLogSink_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, "invalid json")
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_NilSink(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(nil)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_InvalidSink(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	handlers := NewHandlers(server)
+
+	sink := createTestLogSink()
+	sink.Type = "kafka"
+	msg := rpc.CreateAddLogSinkMsg(sink)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.sinkDB.sinks, 0)
+}
+
+func TestHandleAddLogSink_FileSinksDisabled(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(core.CreateLogSink("test-colony", "test-sink", core.LogSinkTypeFile, "logs.jsonl"))
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Contains(t, server.lastError.Error(), "COLONIES_LOG_SINK_DIR")
+}
+
+func TestHandleAddLogSink_ColonyNotFound(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.colonyDB.returnNil = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_NotColonyOwner(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	server.validator.colonyOwnerErr = errors.New("not colony owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_AlreadyExists(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddLogSink_AddError(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.sinks = []*core.LogSink{}
+	server.sinkDB.addErr = errors.New("database error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddLogSinkMsg(createTestLogSink())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddLogSink(ctx, "test-user", rpc.AddLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+// Tests for HandleGetLogSinks
+func TestHandleGetLogSinks_Success(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetLogSinksMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetLogSinks(ctx, "test-user", rpc.GetLogSinksPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	sinks, err := core.ConvertJSONToLogSinkArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, "", sinks[0].Headers["Authorization"])
+}
+
+func TestHandleGetLogSinks_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	handlers.HandleGetLogSinks(ctx, "test-user", rpc.GetLogSinksPayloadType, "invalid json")
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetLogSinks_NotColonyOwner(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.validator.colonyOwnerErr = errors.New("not colony owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetLogSinksMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetLogSinks(ctx, "test-user", rpc.GetLogSinksPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+// Tests for HandleRemoveLogSink
+func TestHandleRemoveLogSink_Success(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveLogSinkMsg("test-colony", "test-sink")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveLogSink(ctx, "test-user", rpc.RemoveLogSinkPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.True(t, server.emptyReplySent)
+	assert.Equal(t, "test-sink", server.sinkDB.removed)
+}
+
+func TestHandleRemoveLogSink_NotFound(t *testing.T) {
+	server, ctx := createMockServer(t)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveLogSinkMsg("test-colony", "does-not-exist")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveLogSink(ctx, "test-user", rpc.RemoveLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleRemoveLogSink_NotColonyOwner(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.validator.colonyOwnerErr = errors.New("not colony owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveLogSinkMsg("test-colony", "test-sink")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveLogSink(ctx, "test-user", rpc.RemoveLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleRemoveLogSink_RemoveError(t *testing.T) {
+	server, ctx := createMockServer(t)
+	server.sinkDB.removeByNameErr = errors.New("database error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveLogSinkMsg("test-colony", "test-sink")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveLogSink(ctx, "test-user", rpc.RemoveLogSinkPayloadType, jsonString)
+
+	assert.NotNil(t, server.lastError)
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
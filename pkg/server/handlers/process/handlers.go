@@ -17,6 +17,7 @@ import (
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -77,6 +78,7 @@ type Controller interface {
 	Assign(executorID string, colonyName string, cpu int64, memory int64) (*AssignResult, error)
 	DistributedAssign(executor *core.Executor, colonyName string, cpu int64, memory int64, storage int64) (*AssignResult, error)
 	UnassignExecutor(processID string) error
+	ReleaseProcess(processID string) error
 	PauseColonyAssignments(colonyName string) error
 	ResumeColonyAssignments(colonyName string) error
 	AreColonyAssignmentsPaused(colonyName string) (bool, error)
@@ -113,8 +115,12 @@ type Server interface {
 	Validator() security.Validator
 	ExecutorDB() database.ExecutorDatabase
 	UserDB() database.UserDatabase
+	FunctionArgsVerifier() *serverutils.FunctionArgsVerifier
 	ProcessDB() database.ProcessDatabase
 	BlueprintDB() database.BlueprintDatabase
+	FileDB() database.FileDatabase
+	SnapshotDB() database.SnapshotDatabase
+	FileLineageDB() database.FileLineageDatabase
 	ProcessController() Controller
 	ExclusiveAssign() bool
 	TLS() bool
@@ -171,6 +177,9 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.CancelProcessPayloadType, h.HandleCancelProcess); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.UnassignProcessPayloadType, h.HandleUnassignProcess); err != nil {
+		return err
+	}
 	if err := handlerRegistry.Register(rpc.SetOutputPayloadType, h.HandleSetOutput); err != nil {
 		return err
 	}
@@ -206,6 +215,11 @@ func (h *Handlers) HandleSubmit(c backends.Context, recoveredID string, payloadT
 		return
 	}
 
+	err = h.server.FunctionArgsVerifier().VerifyArgs(msg.FunctionSpec)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	process := core.CreateProcess(msg.FunctionSpec)
 
 	initiatorName, err := resolveInitiator(msg.FunctionSpec.Conditions.ColonyName, recoveredID, h.server.ExecutorDB(), h.server.UserDB())
@@ -447,6 +461,12 @@ func (h *Handlers) HandleAssignProcess(c backends.Context, recoveredID string, p
 		return
 	}
 
+	// Lineage is only recorded for auditing, failing to record it must not fail the assignment
+	err = serverutils.RecordProcessInputs(process, h.server.FileDB(), h.server.SnapshotDB(), h.server.FileLineageDB())
+	if err != nil {
+		log.WithFields(log.Fields{"ProcessId": process.ID, "Error": err}).Error("Failed to record process file lineage")
+	}
+
 	jsonString, err = process.ToJSON()
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
@@ -910,6 +930,58 @@ func (h *Handlers) HandleCloseFailed(c backends.Context, recoveredID string, pay
 	h.server.SendEmptyHTTPReply(c, payloadType)
 }
 
+// HandleUnassignProcess moves a running process back to the queue without counting a retry, it can only be
+// called by the executor assigned to the process, e.g. when the executor is shutting down
+func (h *Handlers) HandleUnassignProcess(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateUnassignProcessMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to unassign process, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to unassign process, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	process, err := h.server.ProcessDB().GetProcessByID(msg.ProcessID)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if process == nil {
+		errmsg := "Failed to unassign process, process is nil"
+		log.Error(errmsg)
+		h.server.HandleHTTPError(c, errors.New(errmsg), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, process.FunctionSpec.Conditions.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	if process.State != core.RUNNING || process.AssignedExecutorID == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to unassign process, process is not running"), http.StatusBadRequest)
+		return
+	}
+
+	if process.AssignedExecutorID != recoveredID {
+		h.server.HandleHTTPError(c, errors.New("Failed to unassign process, not allowed to unassign process assigned to another executor"), http.StatusForbidden)
+		return
+	}
+
+	err = h.server.ProcessController().ReleaseProcess(process.ID)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		log.WithFields(log.Fields{"Error": err}).Debug("Failed to unassign process")
+		return
+	}
+
+	log.WithFields(log.Fields{"ProcessId": process.ID}).Debug("Unassign process")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
 func (h *Handlers) HandleCancelProcess(c backends.Context, recoveredID string, payloadType string, jsonString string) {
 	msg, err := rpc.CreateCancelProcessMsgFromJSON(jsonString)
 	if err != nil {
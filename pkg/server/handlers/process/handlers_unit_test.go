@@ -11,6 +11,7 @@ import (
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -204,6 +205,7 @@ func (m *MockProcessDB) SelectAndAssign(colonyName string, executorID string, ex
 	return nil, nil
 }
 func (m *MockProcessDB) Unassign(process *core.Process) error { return nil }
+func (m *MockProcessDB) Release(process *core.Process) error { return nil }
 func (m *MockProcessDB) MarkSuccessful(processID string) (float64, float64, error) {
 	return 0, 0, nil
 }
@@ -277,12 +279,22 @@ func (m *MockBlueprintDB) GetBlueprintsByNamespaceKindAndLocation(namespace, kin
 	return nil, nil
 }
 func (m *MockBlueprintDB) UpdateBlueprint(blueprint *core.Blueprint) error { return nil }
+func (m *MockBlueprintDB) UpdateBlueprintWithResourceVersion(blueprint *core.Blueprint, resourceVersion int64) error {
+	return nil
+}
 func (m *MockBlueprintDB) UpdateBlueprintStatus(id string, status map[string]interface{}) error {
 	return nil
 }
+func (m *MockBlueprintDB) UpdateBlueprintStatusWithResourceVersion(id string, status map[string]interface{}, resourceVersion int64) error {
+	return nil
+}
+func (m *MockBlueprintDB) RemoveBlueprintByNameWithResourceVersion(namespace, name string, resourceVersion int64) error {
+	return nil
+}
 func (m *MockBlueprintDB) RemoveBlueprintByID(id string) error                       { return nil }
 func (m *MockBlueprintDB) RemoveBlueprintByName(namespace, name string) error        { return nil }
 func (m *MockBlueprintDB) RemoveBlueprintsByNamespace(namespace string) error        { return nil }
+func (m *MockBlueprintDB) ApplyBlueprints(creates []*core.Blueprint, updates []*core.Blueprint, removes []*core.Blueprint) error { return nil }
 func (m *MockBlueprintDB) CountBlueprints() (int, error)                             { return 0, nil }
 func (m *MockBlueprintDB) CountBlueprintsByNamespace(namespace string) (int, error)  { return 0, nil }
 func (m *MockBlueprintDB) AddBlueprintHistory(history *core.BlueprintHistory) error  { return nil }
@@ -293,6 +305,53 @@ func (m *MockBlueprintDB) GetBlueprintHistoryByGeneration(blueprintID string, ge
 	return nil, nil
 }
 func (m *MockBlueprintDB) RemoveBlueprintHistory(blueprintID string) error { return nil }
+func (m *MockBlueprintDB) PruneBlueprintHistory(blueprintID string, keep int) error { return nil }
+
+// MockColonyDB implements database.ColonyDatabase
+type MockColonyDB struct {
+	colony *core.Colony
+}
+
+func (m *MockColonyDB) AddColony(colony *core.Colony) error           { return nil }
+func (m *MockColonyDB) GetColonies() ([]*core.Colony, error)          { return nil, nil }
+func (m *MockColonyDB) GetColonyByID(id string) (*core.Colony, error) { return m.colony, nil }
+func (m *MockColonyDB) GetColonyByName(name string) (*core.Colony, error) {
+	return m.colony, nil
+}
+func (m *MockColonyDB) RenameColony(colonyName string, newColonyName string) error { return nil }
+func (m *MockColonyDB) RemoveColonyByName(colonyName string) error                 { return nil }
+func (m *MockColonyDB) CountColonies() (int, error)                                { return 0, nil }
+
+// MockFunctionDB implements database.FunctionDatabase
+type MockFunctionDB struct {
+	functions []*core.Function
+}
+
+func (m *MockFunctionDB) AddFunction(function *core.Function) error { return nil }
+func (m *MockFunctionDB) GetFunctionByID(functionID string) (*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByExecutorName(colonyName string, executorName string) ([]*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByColonyName(colonyName string) ([]*core.Function, error) {
+	return m.functions, nil
+}
+func (m *MockFunctionDB) GetFunctionsByExecutorAndName(colonyName string, executorName string, name string) (*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) UpdateFunctionStats(colonyName string, executorName string, name string, counter int, minWaitTime float64, maxWaitTime float64, minExecTime float64, maxExecTime float64, avgWaitTime float64, avgExecTime float64) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionByID(functionID string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctionByName(colonyName string, executorName string, name string) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByExecutorName(colonyName string, executorName string) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByColonyName(colonyName string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctions() error                              { return nil }
 
 // MockController implements Controller interface
 type MockController struct {
@@ -302,6 +361,8 @@ type MockController struct {
 	closeSuccessfulErr          error
 	closeFailedErr              error
 	cancelProcessErr            error
+	unassignErr                 error
+	releaseErr                  error
 	pauseAssignmentsErr         error
 	resumeAssignmentsErr        error
 	pauseStatusResult           bool
@@ -349,7 +410,9 @@ func (m *MockController) DistributedAssign(executor *core.Executor, colonyName s
 	return nil, nil
 }
 
-func (m *MockController) UnassignExecutor(processID string) error { return nil }
+func (m *MockController) UnassignExecutor(processID string) error { return m.unassignErr }
+
+func (m *MockController) ReleaseProcess(processID string) error { return m.releaseErr }
 
 func (m *MockController) PauseColonyAssignments(colonyName string) error {
 	return m.pauseAssignmentsErr
@@ -441,6 +504,8 @@ type MockServer struct {
 	userDB            *MockUserDB
 	processDB         *MockProcessDB
 	blueprintDB       *MockBlueprintDB
+	colonyDB          *MockColonyDB
+	functionDB        *MockFunctionDB
 	validator         *MockValidator
 	controller        *MockController
 	httpErrorCode     int
@@ -486,10 +551,27 @@ func (m *MockServer) ProcessDB() database.ProcessDatabase {
 	return m.processDB
 }
 
+func (m *MockServer) FunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return serverutils.CreateFunctionArgsVerifier(m.colonyDB, m.functionDB)
+}
+
 func (m *MockServer) BlueprintDB() database.BlueprintDatabase {
 	return m.blueprintDB
 }
 
+// The mocked processes have no filesystem mounts, so the file databases used to record lineage are never used
+func (m *MockServer) FileDB() database.FileDatabase {
+	return nil
+}
+
+func (m *MockServer) SnapshotDB() database.SnapshotDatabase {
+	return nil
+}
+
+func (m *MockServer) FileLineageDB() database.FileLineageDatabase {
+	return nil
+}
+
 func (m *MockServer) ProcessController() Controller {
 	return m.controller
 }
@@ -550,6 +632,8 @@ func createMockServer() *MockServer {
 		userDB:      userDB,
 		processDB:   processDB,
 		blueprintDB: blueprintDB,
+		colonyDB:    &MockColonyDB{colony: core.CreateColony("colony-123", "test-colony")},
+		functionDB:  &MockFunctionDB{},
 		validator:   validator,
 		controller:  controller,
 	}
@@ -673,6 +757,90 @@ func TestHandleSubmit_ControllerReturnsNil(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, mockServer.httpErrorCode)
 }
 
+func createTestRegisteredFunction(requireFuncReg bool) *core.Function {
+	function := core.CreateFunctionWithDesc("test-executor", "test-type", "test-colony", "test-func", "", []*core.FunctionArg{
+		core.CreateFunctionArg("count", core.FunctionArgInteger, "", true, nil),
+	})
+	function.RequireFuncReg = requireFuncReg
+	return function
+}
+
+func TestHandleSubmit_ValidArgs(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.functionDB.functions = []*core.Function{createTestRegisteredFunction(true)}
+	handlers := NewHandlers(mockServer)
+
+	funcSpec := createTestFunctionSpec()
+	funcSpec.FuncName = "test-func"
+	funcSpec.KwArgs = map[string]interface{}{"count": 10}
+	msg := rpc.CreateSubmitFunctionSpecMsg(funcSpec)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSubmit(ctx, "executor-123", rpc.SubmitFunctionSpecPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+	assert.Equal(t, rpc.SubmitFunctionSpecPayloadType, mockServer.replyType)
+}
+
+func TestHandleSubmit_InvalidArgs(t *testing.T) {
+	kwargs := []map[string]interface{}{
+		{"count": 10, "cuont": 10}, // Unknown arg
+		{},                          // Missing required arg
+		{"count": "ten"},            // Ill-typed arg
+	}
+
+	for _, kwarg := range kwargs {
+		mockServer := createMockServer()
+		mockServer.functionDB.functions = []*core.Function{createTestRegisteredFunction(true)}
+		handlers := NewHandlers(mockServer)
+
+		funcSpec := createTestFunctionSpec()
+		funcSpec.FuncName = "test-func"
+		funcSpec.KwArgs = kwarg
+		msg := rpc.CreateSubmitFunctionSpecMsg(funcSpec)
+		jsonStr, _ := msg.ToJSON()
+
+		ctx := &MockContext{}
+		handlers.HandleSubmit(ctx, "executor-123", rpc.SubmitFunctionSpecPayloadType, jsonStr)
+
+		assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+	}
+}
+
+func TestHandleSubmit_InvalidArgsColonyRequireFuncReg(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.colonyDB.colony.RequireFuncReg = true
+	mockServer.functionDB.functions = []*core.Function{createTestRegisteredFunction(false)}
+	handlers := NewHandlers(mockServer)
+
+	funcSpec := createTestFunctionSpec()
+	funcSpec.FuncName = "test-func"
+	msg := rpc.CreateSubmitFunctionSpecMsg(funcSpec)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSubmit(ctx, "executor-123", rpc.SubmitFunctionSpecPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
+func TestHandleSubmit_ArgsNotValidatedWithoutRequireFuncReg(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.functionDB.functions = []*core.Function{createTestRegisteredFunction(false)}
+	handlers := NewHandlers(mockServer)
+
+	funcSpec := createTestFunctionSpec()
+	funcSpec.FuncName = "test-func"
+	msg := rpc.CreateSubmitFunctionSpecMsg(funcSpec)
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleSubmit(ctx, "executor-123", rpc.SubmitFunctionSpecPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+}
+
 // HandleGetProcess tests
 func TestHandleGetProcess_Success(t *testing.T) {
 	mockServer := createMockServer()
@@ -1481,3 +1649,110 @@ func TestHandleCancelProcess_ControllerError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
 }
+
+// HandleUnassignProcess tests
+func TestHandleUnassignProcess_Success(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, 0, mockServer.httpErrorCode)
+	assert.Equal(t, rpc.UnassignProcessPayloadType, mockServer.replyType)
+}
+
+func TestHandleUnassignProcess_InvalidJSON(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_MsgTypeMismatch(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", "wrong-type", jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_ProcessNotFound(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.processDB.returnNilByID = true
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("non-existent")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusInternalServerError, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_AuthError(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.validator.requireMembershipErr = errors.New("not a member")
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusForbidden, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_NotRunning(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.processDB.processes[0].State = core.WAITING
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_WrongExecutor(t *testing.T) {
+	mockServer := createMockServer()
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "wrong-executor", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusForbidden, mockServer.httpErrorCode)
+}
+
+func TestHandleUnassignProcess_ControllerError(t *testing.T) {
+	mockServer := createMockServer()
+	mockServer.controller.releaseErr = errors.New("controller error")
+	handlers := NewHandlers(mockServer)
+
+	msg := rpc.CreateUnassignProcessMsg("process-123")
+	jsonStr, _ := msg.ToJSON()
+
+	ctx := &MockContext{}
+	handlers.HandleUnassignProcess(ctx, "executor-123", rpc.UnassignProcessPayloadType, jsonStr)
+
+	assert.Equal(t, http.StatusBadRequest, mockServer.httpErrorCode)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/colonyos/colonies/pkg/database"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -36,6 +37,7 @@ type Server interface {
 	Validator() Validator
 	Controller() Controller
 	ProcessGraphDB() database.ProcessGraphDatabase
+	FunctionArgsVerifier() *serverutils.FunctionArgsVerifier
 }
 
 type Handlers struct {
@@ -97,6 +99,11 @@ func (h *Handlers) HandleSubmitWorkflow(c backends.Context, recoveredID string,
 		return
 	}
 
+	err = h.server.FunctionArgsVerifier().VerifyWorkflowArgs(msg.WorkflowSpec, nil)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	processGraph, err := h.server.Controller().SubmitWorkflowSpec(msg.WorkflowSpec, recoveredID)
 	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
 		return
@@ -373,6 +380,11 @@ func (h *Handlers) HandleAddChild(c backends.Context, recoveredID string, payloa
 		return
 	}
 
+	err = h.server.FunctionArgsVerifier().VerifyArgs(msg.FunctionSpec)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
 	process := core.CreateProcess(msg.FunctionSpec)
 	addedProcess, err := h.server.Controller().AddChild(msg.ProcessGraphID, msg.ParentProcessID, msg.ChildProcessID, process, recoveredID, msg.Insert)
 	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
@@ -10,6 +10,7 @@ import (
 	"github.com/colonyos/colonies/pkg/database"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -233,11 +234,59 @@ func (m *MockProcessGraphDB) CountCancelledProcessGraphsByColonyName(colonyName
 	return 0, nil
 }
 
+// MockColonyDB implements database.ColonyDatabase
+type MockColonyDB struct {
+	colony *core.Colony
+}
+
+func (m *MockColonyDB) AddColony(colony *core.Colony) error           { return nil }
+func (m *MockColonyDB) GetColonies() ([]*core.Colony, error)          { return nil, nil }
+func (m *MockColonyDB) GetColonyByID(id string) (*core.Colony, error) { return m.colony, nil }
+func (m *MockColonyDB) GetColonyByName(name string) (*core.Colony, error) {
+	return m.colony, nil
+}
+func (m *MockColonyDB) RenameColony(colonyName string, newColonyName string) error { return nil }
+func (m *MockColonyDB) RemoveColonyByName(colonyName string) error                 { return nil }
+func (m *MockColonyDB) CountColonies() (int, error)                                { return 0, nil }
+
+// MockFunctionDB implements database.FunctionDatabase
+type MockFunctionDB struct {
+	functions []*core.Function
+}
+
+func (m *MockFunctionDB) AddFunction(function *core.Function) error { return nil }
+func (m *MockFunctionDB) GetFunctionByID(functionID string) (*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByExecutorName(colonyName string, executorName string) ([]*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) GetFunctionsByColonyName(colonyName string) ([]*core.Function, error) {
+	return m.functions, nil
+}
+func (m *MockFunctionDB) GetFunctionsByExecutorAndName(colonyName string, executorName string, name string) (*core.Function, error) {
+	return nil, nil
+}
+func (m *MockFunctionDB) UpdateFunctionStats(colonyName string, executorName string, name string, counter int, minWaitTime float64, maxWaitTime float64, minExecTime float64, maxExecTime float64, avgWaitTime float64, avgExecTime float64) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionByID(functionID string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctionByName(colonyName string, executorName string, name string) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByExecutorName(colonyName string, executorName string) error {
+	return nil
+}
+func (m *MockFunctionDB) RemoveFunctionsByColonyName(colonyName string) error { return nil }
+func (m *MockFunctionDB) RemoveFunctions() error                              { return nil }
+
 // MockServer implements Server interface
 type MockServer struct {
 	controller      *MockController
 	validator       *MockValidator
 	processGraphDB  *MockProcessGraphDB
+	colonyDB        *MockColonyDB
+	functionDB      *MockFunctionDB
 	lastError       error
 	lastStatusCode  int
 	lastPayloadType string
@@ -279,6 +328,10 @@ func (m *MockServer) ProcessGraphDB() database.ProcessGraphDatabase {
 	return m.processGraphDB
 }
 
+func (m *MockServer) FunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return serverutils.CreateFunctionArgsVerifier(m.colonyDB, m.functionDB)
+}
+
 // Helper to create test process graph
 func createTestProcessGraph() *core.ProcessGraph {
 	return &core.ProcessGraph{
@@ -342,6 +395,8 @@ func createMockServer() (*MockServer, *MockContext) {
 		controller:     controller,
 		validator:      validator,
 		processGraphDB: &MockProcessGraphDB{},
+		colonyDB:       &MockColonyDB{colony: core.CreateColony("colony-123", "test-colony")},
+		functionDB:     &MockFunctionDB{},
 	}
 
 	ctx := &MockContext{}
@@ -406,6 +461,35 @@ func TestHandleSubmitWorkflow_NilWorkflowSpec(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
 }
 
+func TestHandleSubmitWorkflow_InvalidArgs(t *testing.T) {
+	server, ctx := createMockServer()
+	server.colonyDB.colony.RequireFuncReg = true
+	server.functionDB.functions = []*core.Function{
+		core.CreateFunctionWithDesc("test-executor", "test-type", "test-colony", "test-func", "", []*core.FunctionArg{
+			core.CreateFunctionArg("count", core.FunctionArgInteger, "", true, nil),
+		}),
+	}
+	handlers := NewHandlers(server)
+
+	workflowSpec := createTestWorkflowSpec()
+	msg := rpc.CreateSubmitWorkflowSpecMsg(workflowSpec)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleSubmitWorkflow(ctx, "user-123", rpc.SubmitWorkflowSpecPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+
+	workflowSpec.FunctionSpecs[0].KwArgs = map[string]interface{}{"count": 1}
+	msg = rpc.CreateSubmitWorkflowSpecMsg(workflowSpec)
+	jsonString, _ = msg.ToJSON()
+
+	server.lastStatusCode = 0
+	handlers.HandleSubmitWorkflow(ctx, "user-123", rpc.SubmitWorkflowSpecPayloadType, jsonString)
+
+	assert.Equal(t, 0, server.lastStatusCode)
+	assert.Equal(t, rpc.SubmitWorkflowSpecPayloadType, server.lastPayloadType)
+}
+
 func TestHandleSubmitWorkflow_MembershipError(t *testing.T) {
 	server, ctx := createMockServer()
 	server.validator.membershipErr = errors.New("membership error")
@@ -794,6 +878,26 @@ func TestHandleAddChild_Success(t *testing.T) {
 	assert.Equal(t, rpc.AddChildPayloadType, server.lastPayloadType)
 }
 
+func TestHandleAddChild_InvalidArgs(t *testing.T) {
+	server, ctx := createMockServer()
+	server.colonyDB.colony.RequireFuncReg = true
+	server.functionDB.functions = []*core.Function{
+		core.CreateFunctionWithDesc("test-executor", "test-type", "test-colony", "child-func", "", []*core.FunctionArg{
+			core.CreateFunctionArg("mode", core.FunctionArgString, "", false, []string{"fast", "slow"}),
+		}),
+	}
+	handlers := NewHandlers(server)
+
+	funcSpec := createTestFunctionSpec()
+	funcSpec.KwArgs = map[string]interface{}{"mode": "medium"}
+	msg := rpc.CreateAddChildMsg("processgraph-123", "parent-123", "child-123", funcSpec, false)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddChild(ctx, "user-123", rpc.AddChildPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
 func TestHandleAddChild_InvalidJSON(t *testing.T) {
 	server, ctx := createMockServer()
 	handlers := NewHandlers(server)
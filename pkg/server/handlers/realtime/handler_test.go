@@ -215,3 +215,50 @@ func TestSubscribeChangeStateProcess2(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+// Executor 1 adds a log to an assigned process, the log is delivered first when executor 2
+// subscribes on logs for the process, followed by logs added after the subscription started
+func TestSubscribeLogs(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv1(t)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.Colony1Name)
+	_, err := client.Submit(funcSpec, env.Executor1PrvKey)
+	assert.Nil(t, err)
+
+	assignedProcess, err := client.Assign(env.Colony1Name, -1, "", "", env.Executor1PrvKey)
+	assert.Nil(t, err)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg1", env.Executor1PrvKey)
+	assert.Nil(t, err)
+
+	subscription, err := client.SubscribeProcessLogs(env.Colony1Name, assignedProcess.ID, 0, 2, env.Executor2PrvKey)
+	assert.Nil(t, err)
+
+	logs := <-subscription.LogsChan
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "test_msg1", logs[0].Message)
+
+	err = client.AddLog(assignedProcess.ID, "test_msg2", env.Executor1PrvKey)
+	assert.Nil(t, err)
+
+	logs = <-subscription.LogsChan
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "test_msg2", logs[0].Message)
+
+	// An empty list is sent when the subscription times out
+	logs = <-subscription.LogsChan
+	assert.Len(t, logs, 0)
+
+	subscription, err = client.SubscribeProcessLogs(env.Colony1Name, assignedProcess.ID, 0, 2, env.Colony2PrvKey)
+	assert.Nil(t, err)
+	err = <-subscription.ErrChan
+	assert.NotNil(t, err) // Not a member of the colony
+
+	subscription, err = client.SubscribeExecutorLogs(env.Colony1Name, env.Executor1Name, 0, 2, env.Executor2PrvKey)
+	assert.Nil(t, err)
+	logs = <-subscription.LogsChan
+	assert.Len(t, logs, 2)
+
+	server.Shutdown()
+	<-done
+}
@@ -108,6 +108,7 @@ func (m *MockProcessDB) SetWaitForParents(processID string, waitingForParent boo
 func (m *MockProcessDB) Assign(executorID string, process *core.Process) error                    { return nil }
 func (m *MockProcessDB) SelectAndAssign(colonyName string, executorID string, executorName string, executorType string, executorLocation string, cpu int64, memory int64, storage int64, nodes int, processes int, processesPerNode int, count int) (*core.Process, error) { return nil, nil }
 func (m *MockProcessDB) Unassign(process *core.Process) error                                     { return nil }
+func (m *MockProcessDB) Release(process *core.Process) error                                      { return nil }
 func (m *MockProcessDB) MarkSuccessful(processID string) (float64, float64, error)                { return 0, 0, nil }
 func (m *MockProcessDB) MarkFailed(processID string, errs []string) error                         { return nil }
 func (m *MockProcessDB) CountProcesses() (int, error)                                             { return 0, nil }
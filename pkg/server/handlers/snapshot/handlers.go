@@ -48,6 +48,15 @@ func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) e
 	if err := handlerRegistry.Register(rpc.RemoveAllSnapshotsPayloadType, h.HandleRemoveAllSnapshots); err != nil {
 		return err
 	}
+	if err := handlerRegistry.Register(rpc.DiffSnapshotsPayloadType, h.HandleDiffSnapshots); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RestoreSnapshotPayloadType, h.HandleRestoreSnapshot); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.CloneSnapshotPayloadType, h.HandleCloneSnapshot); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -244,4 +253,157 @@ func (h *Handlers) HandleRemoveAllSnapshots(c backends.Context, recoveredID stri
 	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Removing all snapshots")
 
 	h.server.SendEmptyHTTPReply(c, payloadType)
-}
\ No newline at end of file
+}
+
+func (h *Handlers) getSnapshotByName(c backends.Context, colonyName string, name string) (*core.Snapshot, bool) {
+	snapshot, err := h.server.SnapshotDB().GetSnapshotByName(colonyName, name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return nil, false
+	}
+
+	if snapshot == nil {
+		h.server.HandleHTTPError(c, errors.New("Snapshot with name <"+name+"> not found"), http.StatusNotFound)
+		return nil, false
+	}
+
+	return snapshot, true
+}
+
+func (h *Handlers) HandleDiffSnapshots(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateDiffSnapshotsMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to diff snapshots, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to diff snapshots, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		log.Error(err)
+		return
+	}
+
+	fromSnapshot, ok := h.getSnapshotByName(c, msg.ColonyName, msg.From)
+	if !ok {
+		return
+	}
+
+	toSnapshot, ok := h.getSnapshotByName(c, msg.ColonyName, msg.To)
+	if !ok {
+		return
+	}
+
+	diff, err := h.server.SnapshotDB().DiffSnapshots(msg.ColonyName, fromSnapshot.ID, toSnapshot.ID)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	jsonStr, err := diff.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "From": msg.From, "To": msg.To}).Debug("Diffing snapshots")
+
+	h.server.SendHTTPReply(c, payloadType, jsonStr)
+}
+
+func (h *Handlers) HandleRestoreSnapshot(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRestoreSnapshotMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to restore snapshot, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to restore snapshot, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		log.Error(err)
+		return
+	}
+
+	snapshot, ok := h.getSnapshotByName(c, msg.ColonyName, msg.Name)
+	if !ok {
+		return
+	}
+
+	diff, err := h.server.SnapshotDB().RestoreSnapshot(msg.ColonyName, snapshot.ID, msg.Prune)
+	if errors.Is(err, core.ErrSnapshotConflict) {
+		h.server.HandleHTTPError(c, err, http.StatusBadRequest)
+		log.Error(err)
+		return
+	}
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	jsonStr, err := diff.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "SnapshotID": snapshot.ID, "Label": snapshot.Label, "Prune": msg.Prune}).Debug("Restoring snapshot")
+
+	h.server.SendHTTPReply(c, payloadType, jsonStr)
+}
+
+func (h *Handlers) HandleCloneSnapshot(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateCloneSnapshotMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to clone snapshot, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to clone snapshot, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.Validator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		log.Error(err)
+		return
+	}
+
+	snapshot, ok := h.getSnapshotByName(c, msg.ColonyName, msg.Name)
+	if !ok {
+		return
+	}
+
+	diff, err := h.server.SnapshotDB().CloneSnapshot(msg.ColonyName, snapshot.ID, msg.Label)
+	if errors.Is(err, core.ErrSnapshotConflict) {
+		h.server.HandleHTTPError(c, err, http.StatusBadRequest)
+		log.Error(err)
+		return
+	}
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	jsonStr, err := diff.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		log.Error(err)
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "SnapshotID": snapshot.ID, "Label": msg.Label}).Debug("Cloning snapshot")
+
+	h.server.SendHTTPReply(c, payloadType, jsonStr)
+}
@@ -442,3 +442,70 @@ func TestGetSnapshotByNameNotFound(t *testing.T) {
 	server.Shutdown()
 	<-done
 }
+
+func TestDiffRestoreCloneSnapshot(t *testing.T) {
+	env, client, server, _, done := server.SetupTestEnv2(t)
+
+	file := utils.CreateTestFile(env.ColonyName)
+	file.Label = "/test_label"
+	file.Name = "file1"
+	file.Checksum = "checksum1"
+	_, err := client.AddFile(file, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.CreateSnapshot(env.ColonyName, "/test_label", "snapshot1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	file.Checksum = "checksum1_rev2"
+	_, err = client.AddFile(file, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	file.Name = "file2"
+	file.Checksum = "checksum2"
+	_, err = client.AddFile(file, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.CreateSnapshot(env.ColonyName, "/test_label", "snapshot2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	diff, err := client.DiffSnapshots(env.ColonyName, "snapshot1", "snapshot2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "/file2", diff.Added[0].Path)
+	assert.Len(t, diff.Changed, 1)
+	assert.Equal(t, "/file1", diff.Changed[0].Path)
+	assert.Len(t, diff.Removed, 0)
+
+	_, err = client.DiffSnapshots(env.ColonyName, "snapshot1", "invalid_snapshot", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	diff, err = client.CloneSnapshot(env.ColonyName, "snapshot2", "/test_clone", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Added, 2)
+
+	_, err = client.CloneSnapshot(env.ColonyName, "snapshot2", "/test_clone", env.ExecutorPrvKey)
+	assert.NotNil(t, err) // Label is not empty
+
+	diff, err = client.RestoreSnapshot(env.ColonyName, "snapshot1", false, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Changed, 1)
+	assert.Len(t, diff.Removed, 0)
+
+	files, err := client.GetFileByName(env.ColonyName, "/test_label", "file1", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, files, 3)
+
+	_, err = client.RestoreSnapshot(env.ColonyName, "snapshot1", true, env.ExecutorPrvKey)
+	assert.NotNil(t, err) // file2 is referenced by snapshot2
+
+	err = client.RemoveSnapshotByName(env.ColonyName, "snapshot2", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	diff, err = client.RestoreSnapshot(env.ColonyName, "snapshot1", true, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "/file2", diff.Removed[0].Path)
+
+	server.Shutdown()
+	<-done
+}
@@ -2,6 +2,7 @@ package snapshot
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -24,6 +25,9 @@ type MockSnapshotDB struct {
 	removeByIDErr   error
 	removeByNameErr error
 	removeAllErr    error
+	diffErr         error
+	restoreErr      error
+	cloneErr        error
 	returnNilByID   bool
 	returnNilByName bool
 }
@@ -106,6 +110,27 @@ func (m *MockSnapshotDB) RemoveSnapshotsByColonyName(colonyName string) error {
 	return nil
 }
 
+func (m *MockSnapshotDB) DiffSnapshots(colonyName string, fromSnapshotID string, toSnapshotID string) (*core.SnapshotDiff, error) {
+	if m.diffErr != nil {
+		return nil, m.diffErr
+	}
+	return &core.SnapshotDiff{Added: []*core.SnapshotDiffEntry{{Path: "/file", ToFileID: "file-123"}}}, nil
+}
+
+func (m *MockSnapshotDB) RestoreSnapshot(colonyName string, snapshotID string, prune bool) (*core.SnapshotDiff, error) {
+	if m.restoreErr != nil {
+		return nil, m.restoreErr
+	}
+	return &core.SnapshotDiff{Changed: []*core.SnapshotDiffEntry{{Path: "/file", FromFileID: "file-123", ToFileID: "file-456"}}}, nil
+}
+
+func (m *MockSnapshotDB) CloneSnapshot(colonyName string, snapshotID string, label string) (*core.SnapshotDiff, error) {
+	if m.cloneErr != nil {
+		return nil, m.cloneErr
+	}
+	return &core.SnapshotDiff{Added: []*core.SnapshotDiffEntry{{Path: "/file", ToFileID: "file-456"}}}, nil
+}
+
 // MockValidator implements security.Validator
 type MockValidator struct {
 	membershipErr   error
@@ -566,3 +591,263 @@ func TestHandleRemoveAllSnapshots_DBError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
 }
+
+// Tests for HandleDiffSnapshots
+func TestHandleDiffSnapshots_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateDiffSnapshotsMsg("test-colony", "test-snapshot", "test-snapshot")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", rpc.DiffSnapshotsPayloadType, jsonString)
+
+	assert.Equal(t, rpc.DiffSnapshotsPayloadType, server.lastPayloadType)
+	assert.Nil(t, server.lastError)
+
+	diff, err := core.ConvertJSONToSnapshotDiff(server.lastResponse)
+	assert.Nil(t, err)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestHandleDiffSnapshots_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", rpc.DiffSnapshotsPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleDiffSnapshots_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateDiffSnapshotsMsg("test-colony", "test-snapshot", "test-snapshot")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleDiffSnapshots_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateDiffSnapshotsMsg("test-colony", "test-snapshot", "test-snapshot")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", rpc.DiffSnapshotsPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleDiffSnapshots_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.returnNilByName = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateDiffSnapshotsMsg("test-colony", "test-snapshot", "test-snapshot")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", rpc.DiffSnapshotsPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleDiffSnapshots_DBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.diffErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateDiffSnapshotsMsg("test-colony", "test-snapshot", "test-snapshot")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleDiffSnapshots(ctx, "test-user", rpc.DiffSnapshotsPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+// Tests for HandleRestoreSnapshot
+func TestHandleRestoreSnapshot_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, rpc.RestoreSnapshotPayloadType, server.lastPayloadType)
+	assert.Nil(t, server.lastError)
+
+	diff, err := core.ConvertJSONToSnapshotDiff(server.lastResponse)
+	assert.Nil(t, err)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestHandleRestoreSnapshot_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleRestoreSnapshot_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleRestoreSnapshot_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleRestoreSnapshot_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.returnNilByName = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleRestoreSnapshot_DBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.restoreErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandleRestoreSnapshot_Conflict(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.restoreErr = fmt.Errorf("%w: file is referenced by a snapshot", core.ErrSnapshotConflict)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRestoreSnapshotMsg("test-colony", "test-snapshot", true)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRestoreSnapshot(ctx, "test-user", rpc.RestoreSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+// Tests for HandleCloneSnapshot
+func TestHandleCloneSnapshot_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, rpc.CloneSnapshotPayloadType, server.lastPayloadType)
+	assert.Nil(t, server.lastError)
+
+	diff, err := core.ConvertJSONToSnapshotDiff(server.lastResponse)
+	assert.Nil(t, err)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestHandleCloneSnapshot_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleCloneSnapshot_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleCloneSnapshot_MembershipError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("membership error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleCloneSnapshot_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.returnNilByName = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleCloneSnapshot_DBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.cloneErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandleCloneSnapshot_Conflict(t *testing.T) {
+	server, ctx := createMockServer()
+	server.snapshotDB.cloneErr = fmt.Errorf("%w: label is not empty", core.ErrSnapshotConflict)
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateCloneSnapshotMsg("test-colony", "test-snapshot", "/clone")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleCloneSnapshot(ctx, "test-user", rpc.CloneSnapshotPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
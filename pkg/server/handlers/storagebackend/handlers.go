@@ -0,0 +1,322 @@
+package storagebackend
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/security/secrets"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	"github.com/colonyos/colonies/pkg/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+type Server interface {
+	HandleHTTPError(c backends.Context, err error, errorCode int) bool
+	SendHTTPReply(c backends.Context, payloadType string, jsonString string)
+	SendEmptyHTTPReply(c backends.Context, payloadType string)
+	GetStorageBackendDB() database.StorageBackendDatabase
+	GetColonyDB() database.ColonyDatabase
+	FileDB() database.FileDatabase
+	GetValidator() security.Validator
+}
+
+type Handlers struct {
+	server    Server
+	presigner storage.Presigner
+}
+
+func NewHandlers(server Server) *Handlers {
+	return &Handlers{
+		server:    server,
+		presigner: storage.CreateS3Presigner(),
+	}
+}
+
+func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) error {
+	if err := handlerRegistry.Register(rpc.AddStorageBackendPayloadType, h.HandleAddStorageBackend); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetStorageBackendsPayloadType, h.HandleGetStorageBackends); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RemoveStorageBackendPayloadType, h.HandleRemoveStorageBackend); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.PresignFilePayloadType, h.HandlePresignFile); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *Handlers) HandleAddStorageBackend(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddStorageBackendMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.StorageBackend == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, storage backend is nil"), http.StatusBadRequest)
+		return
+	}
+
+	backend := msg.StorageBackend
+	if backend.Name == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, name is empty"), http.StatusBadRequest)
+		return
+	}
+
+	if backend.Server == "" || backend.Bucket == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, server and bucket must be specified"), http.StatusBadRequest)
+		return
+	}
+
+	if backend.Protocol == "" {
+		backend.Protocol = "s3"
+	}
+
+	if backend.Protocol != "s3" {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, unsupported protocol <"+backend.Protocol+">"), http.StatusBadRequest)
+		return
+	}
+
+	// Credentials looking sealed would be stored as is and could then not be opened
+	if secrets.IsSealed(backend.AccessKey) || secrets.IsSealed(backend.SecretKey) {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, credentials cannot start with a reserved prefix"), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(backend.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+backend.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	backendExist, err := h.server.GetStorageBackendDB().GetStorageBackendByName(backend.ColonyName, backend.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if backendExist != nil {
+		if h.server.HandleHTTPError(c, errors.New("A storage backend with name <"+backend.Name+"> already exists in Colony with name <"+backend.ColonyName+">"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	backend.ID = core.GenerateRandomID()
+	err = h.server.GetStorageBackendDB().AddStorageBackend(backend)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	addedBackend, err := h.server.GetStorageBackendDB().GetStorageBackendByName(colony.Name, backend.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if addedBackend == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add storage backend, addedBackend is nil"), http.StatusInternalServerError)
+		return
+	}
+
+	jsonString, err = addedBackend.Redact().ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": addedBackend.ColonyName, "Name": addedBackend.Name, "BackendID": addedBackend.ID}).Debug("Adding storage backend")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleGetStorageBackends(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetStorageBackendsMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get storage backends, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get storage backends, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.GetValidator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	storageBackends, err := h.server.GetStorageBackendDB().GetStorageBackendsByColonyName(msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	redactedBackends := make([]*core.StorageBackend, 0, len(storageBackends))
+	for _, backend := range storageBackends {
+		redactedBackends = append(redactedBackends, backend.Redact())
+	}
+
+	jsonString, err = core.ConvertStorageBackendArrayToJSON(redactedBackends)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName}).Debug("Getting storage backends")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleRemoveStorageBackend(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveStorageBackendMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to remove storage backend, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to remove storage backend, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, msg.ColonyName)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	backend, err := h.server.GetStorageBackendDB().GetStorageBackendByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if backend == nil {
+		h.server.HandleHTTPError(c, errors.New("Storage backend with name <"+msg.Name+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	err = h.server.GetStorageBackendDB().RemoveStorageBackendByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Name": msg.Name}).Debug("Removing storage backend")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
+// HandlePresignFile creates a presigned URL for a file. GET and DELETE URLs are created for an existing file,
+// PUT URLs for a new object in a backend. The object key of a new object is issued by the server in the
+// namespace of the colony and recorded, so that executors cannot overwrite objects referenced by other files,
+// and files can only reference objects issued to their colony.
+func (h *Handlers) HandlePresignFile(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreatePresignFileMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to presign file, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to presign file, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.server.GetValidator().RequireMembership(recoveredID, msg.ColonyName, true)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	var backendName string
+	var object string
+	switch msg.Method {
+	case core.PresignGet, core.PresignDelete:
+		file, err := h.server.FileDB().GetFileByID(msg.ColonyName, msg.FileID)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+
+		if file == nil {
+			h.server.HandleHTTPError(c, errors.New("Failed to presign file, file with Id <"+msg.FileID+"> not found"), http.StatusNotFound)
+			return
+		}
+
+		if file.Reference.Backend == "" {
+			h.server.HandleHTTPError(c, errors.New("Failed to presign file, file with Id <"+msg.FileID+"> is not stored in a storage backend"), http.StatusBadRequest)
+			return
+		}
+
+		if !core.IsColonyStorageObject(msg.ColonyName, file.Reference.S3Object.Object) {
+			h.server.HandleHTTPError(c, errors.New("Failed to presign file, object of file with Id <"+msg.FileID+"> is not in the namespace of the colony"), http.StatusForbidden)
+			return
+		}
+
+		backendName = file.Reference.Backend
+		object = file.Reference.S3Object.Object
+	case core.PresignPut:
+		backendName = msg.Backend
+	default:
+		h.server.HandleHTTPError(c, errors.New("Failed to presign file, invalid method <"+msg.Method+">"), http.StatusBadRequest)
+		return
+	}
+
+	backend, err := h.server.GetStorageBackendDB().GetStorageBackendByName(msg.ColonyName, backendName)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if backend == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to presign file, storage backend with name <"+backendName+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	if msg.Method == core.PresignPut {
+		storageObject := core.CreateStorageObject(msg.ColonyName, backend.Name)
+		err = h.server.GetStorageBackendDB().AddStorageObject(storageObject)
+		if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+			return
+		}
+		object = storageObject.Object
+	}
+
+	expires := time.Duration(constants.STORAGE_PRESIGN_EXPIRY) * time.Second
+	url, err := h.presigner.Presign(backend, msg.Method, object, expires)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	presignedURL := &core.PresignedURL{URL: url, Method: msg.Method, Backend: backend.Name, Object: object, Expires: time.Now().Add(expires)}
+	jsonString, err = presignedURL.ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Backend": backend.Name, "Method": msg.Method, "FileID": msg.FileID}).Debug("Presigning file")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
@@ -0,0 +1,124 @@
+package storagebackend_test
+
+import (
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/server"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddStorageBackend(t *testing.T) {
+	client, s, serverPrvKey, done := server.PrepareTests(t)
+
+	colony, colonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client.AddColony(colony, serverPrvKey)
+	assert.Nil(t, err)
+
+	backend := utils.CreateTestStorageBackend(colony.Name, "test_backend")
+	addedBackend, err := client.AddStorageBackend(backend, colonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedBackend)
+	assert.Equal(t, "test_backend", addedBackend.Name)
+	assert.Equal(t, backend.Bucket, addedBackend.Bucket)
+	assert.Empty(t, addedBackend.AccessKey)
+	assert.Empty(t, addedBackend.SecretKey)
+
+	// Same name again should fail
+	_, err = client.AddStorageBackend(utils.CreateTestStorageBackend(colony.Name, "test_backend"), colonyPrvKey)
+	assert.NotNil(t, err)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestAddStorageBackendNotColonyOwner(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	_, err := client.AddStorageBackend(utils.CreateTestStorageBackend(env.ColonyName, "test_backend"), env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = client.AddStorageBackend(utils.CreateTestStorageBackend(env.ColonyName, "test_backend"), env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	// Members may list backends, but not remove them
+	backends, err := client.GetStorageBackends(env.ColonyName, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 1)
+	assert.Empty(t, backends[0].SecretKey)
+
+	err = client.RemoveStorageBackend(env.ColonyName, "test_backend", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestGetAndRemoveStorageBackend(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	_, err := client.AddStorageBackend(utils.CreateTestStorageBackend(env.ColonyName, "test_backend1"), env.ColonyPrvKey)
+	assert.Nil(t, err)
+	_, err = client.AddStorageBackend(utils.CreateTestStorageBackend(env.ColonyName, "test_backend2"), env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	backends, err := client.GetStorageBackends(env.ColonyName, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 2)
+
+	err = client.RemoveStorageBackend(env.ColonyName, "test_backend1", env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	err = client.RemoveStorageBackend(env.ColonyName, "test_backend1", env.ColonyPrvKey)
+	assert.NotNil(t, err)
+
+	backends, err = client.GetStorageBackends(env.ColonyName, env.ColonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 1)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestPresignFile(t *testing.T) {
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	_, err := client.AddStorageBackend(utils.CreateTestStorageBackend(env.ColonyName, "test_backend"), env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	putURL, err := client.PresignFile(env.ColonyName, core.PresignPut, "", "test_backend", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, core.PresignPut, putURL.Method)
+	assert.NotEmpty(t, putURL.Object)
+	assert.Contains(t, putURL.URL, putURL.Object)
+	assert.Contains(t, putURL.URL, "X-Amz-Signature")
+	assert.NotContains(t, putURL.URL, "test_secretkey")
+
+	file := utils.CreateTestFile(env.ColonyName)
+	file.Reference = core.Reference{Protocol: "s3", Backend: "test_backend", S3Object: core.S3Object{Object: putURL.Object}}
+	addedFile, err := client.AddFile(file, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_backend", addedFile.Reference.Backend)
+
+	getURL, err := client.PresignFile(env.ColonyName, core.PresignGet, addedFile.ID, "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, putURL.Object, getURL.Object)
+
+	_, err = client.PresignFile(env.ColonyName, core.PresignDelete, addedFile.ID, "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	// Unknown backend
+	_, err = client.PresignFile(env.ColonyName, core.PresignPut, "", "does_not_exist", env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	// Files must reference a registered backend
+	file = utils.CreateTestFile(env.ColonyName)
+	file.Reference.Backend = "does_not_exist"
+	_, err = client.AddFile(file, env.ExecutorPrvKey)
+	assert.NotNil(t, err)
+
+	s.Shutdown()
+	<-done
+}
@@ -0,0 +1,778 @@
+package storagebackend
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockStorageBackendDB implements database.StorageBackendDatabase
+type MockStorageBackendDB struct {
+	backends        []*core.StorageBackend
+	objects         []*core.StorageObject
+	addErr          error
+	addObjectErr    error
+	getByNameErr    error
+	removeByNameErr error
+}
+
+func (m *MockStorageBackendDB) AddStorageBackend(backend *core.StorageBackend) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.backends = append(m.backends, backend)
+	return nil
+}
+
+func (m *MockStorageBackendDB) GetStorageBackendByName(colonyName string, name string) (*core.StorageBackend, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	for _, b := range m.backends {
+		if b.Name == name && b.ColonyName == colonyName {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockStorageBackendDB) GetStorageBackendsByColonyName(colonyName string) ([]*core.StorageBackend, error) {
+	return m.backends, nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageBackendByName(colonyName string, name string) error {
+	return m.removeByNameErr
+}
+
+func (m *MockStorageBackendDB) RemoveStorageBackendsByColonyName(colonyName string) error {
+	return nil
+}
+
+func (m *MockStorageBackendDB) AddStorageObject(object *core.StorageObject) error {
+	if m.addObjectErr != nil {
+		return m.addObjectErr
+	}
+	m.objects = append(m.objects, object)
+	return nil
+}
+
+func (m *MockStorageBackendDB) RegisterStorageObject(colonyName string, backend string, object string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStorageBackendDB) GetStorageObjectsByColonyName(colonyName string) ([]*core.StorageObject, error) {
+	return m.objects, nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageObject(colonyName string, object string) error {
+	return nil
+}
+
+func (m *MockStorageBackendDB) RemoveStorageObjectsByColonyName(colonyName string) error {
+	return nil
+}
+
+// MockFileDB implements database.FileDatabase
+type MockFileDB struct {
+	files      []*core.File
+	getByIDErr error
+}
+
+func (m *MockFileDB) AddFile(file *core.File) error { return nil }
+func (m *MockFileDB) GetLatestFileByName(colonyName string, label string, name string) ([]*core.File, error) {
+	return nil, nil
+}
+func (m *MockFileDB) GetFileByName(colonyName string, label string, name string) ([]*core.File, error) {
+	return nil, nil
+}
+func (m *MockFileDB) GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error) {
+	return nil, nil
+}
+func (m *MockFileDB) GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error) {
+	return nil, nil
+}
+func (m *MockFileDB) GetFilenamesByLabel(colonyName string, label string) ([]string, error) {
+	return nil, nil
+}
+func (m *MockFileDB) GetFileDataByLabel(colonyName string, label string) ([]*core.FileData, error) {
+	return nil, nil
+}
+func (m *MockFileDB) UpdateFileEncryptionKey(colonyName string, fileID string, encryptionKey string) error {
+	return nil
+}
+func (m *MockFileDB) RemoveFileByID(colonyName string, fileID string) error { return nil }
+func (m *MockFileDB) RemoveFileByName(colonyName string, label string, name string) error {
+	return nil
+}
+func (m *MockFileDB) GetFileLabels(colonyName string) ([]*core.Label, error) { return nil, nil }
+func (m *MockFileDB) GetFileLabelsByName(colonyName string, name string, exact bool) ([]*core.Label, error) {
+	return nil, nil
+}
+func (m *MockFileDB) CountFilesWithLabel(colonyName string, label string) (int, error) { return 0, nil }
+func (m *MockFileDB) CountFiles(colonyName string) (int, error)                        { return 0, nil }
+
+func (m *MockFileDB) GetFileByID(colonyName string, fileID string) (*core.File, error) {
+	if m.getByIDErr != nil {
+		return nil, m.getByIDErr
+	}
+	for _, f := range m.files {
+		if f.ID == fileID && f.ColonyName == colonyName {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// MockPresigner implements storage.Presigner
+type MockPresigner struct {
+	err         error
+	lastBackend *core.StorageBackend
+	lastMethod  string
+	lastObject  string
+	lastExpires time.Duration
+}
+
+func (m *MockPresigner) Presign(backend *core.StorageBackend, method string, object string, expires time.Duration) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.lastBackend = backend
+	m.lastMethod = method
+	m.lastObject = object
+	m.lastExpires = expires
+	return "http://" + backend.Server + "/" + backend.Bucket + "/" + object + "?X-Amz-Signature=test", nil
+}
+
+// MockColonyDB implements database.ColonyDatabase
+type MockColonyDB struct {
+	colonies     []*core.Colony
+	getByNameErr error
+	returnNil    bool
+}
+
+func (m *MockColonyDB) AddColony(colony *core.Colony) error                 { return nil }
+func (m *MockColonyDB) GetColonies() ([]*core.Colony, error)                { return nil, nil }
+func (m *MockColonyDB) GetColonyByID(colonyID string) (*core.Colony, error) { return nil, nil }
+func (m *MockColonyDB) RemoveColonyByName(colonyName string) error          { return nil }
+func (m *MockColonyDB) RemoveColonies() error                               { return nil }
+func (m *MockColonyDB) CountColonies() (int, error)                         { return 0, nil }
+func (m *MockColonyDB) RenameColony(colonyName, newName string) error       { return nil }
+
+func (m *MockColonyDB) GetColonyByName(colonyName string) (*core.Colony, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	if m.returnNil {
+		return nil, nil
+	}
+	for _, c := range m.colonies {
+		if c.Name == colonyName {
+			return c, nil
+		}
+	}
+	return &core.Colony{ID: "colony-123", Name: colonyName}, nil
+}
+
+// MockValidator implements security.Validator
+type MockValidator struct {
+	membershipErr  error
+	colonyOwnerErr error
+	serverOwnerErr error
+}
+
+func (m *MockValidator) RequireMembership(recoveredID string, colonyName string, executorMayJoin bool) error {
+	return m.membershipErr
+}
+
+func (m *MockValidator) RequireColonyOwner(recoveredID string, colonyName string) error {
+	return m.colonyOwnerErr
+}
+
+func (m *MockValidator) RequireServerOwner(recoveredID string, serverID string) error {
+	return m.serverOwnerErr
+}
+
+// MockContext implements backends.Context
+type MockContext struct {
+	aborted               bool
+	abortedWithStatus     int
+	abortedWithStatusJSON int
+	jsonResponse          interface{}
+}
+
+func (m *MockContext) String(code int, format string, values ...interface{}) {}
+func (m *MockContext) JSON(code int, obj interface{})                        { m.jsonResponse = obj }
+func (m *MockContext) XML(code int, obj interface{})                         {}
+func (m *MockContext) Data(code int, contentType string, data []byte)        {}
+func (m *MockContext) Status(code int)                                       {}
+func (m *MockContext) Request() *http.Request                                { return nil }
+func (m *MockContext) ReadBody() ([]byte, error)                             { return nil, nil }
+func (m *MockContext) GetHeader(key string) string                           { return "" }
+func (m *MockContext) Header(key, value string)                              {}
+func (m *MockContext) Param(key string) string                               { return "" }
+func (m *MockContext) Query(key string) string                               { return "" }
+func (m *MockContext) DefaultQuery(key, defaultValue string) string          { return defaultValue }
+func (m *MockContext) PostForm(key string) string                            { return "" }
+func (m *MockContext) DefaultPostForm(key, defaultValue string) string       { return defaultValue }
+func (m *MockContext) Bind(obj interface{}) error                            { return nil }
+func (m *MockContext) ShouldBind(obj interface{}) error                      { return nil }
+func (m *MockContext) BindJSON(obj interface{}) error                        { return nil }
+func (m *MockContext) ShouldBindJSON(obj interface{}) error                  { return nil }
+func (m *MockContext) Set(key string, value interface{})                     {}
+func (m *MockContext) Get(key string) (value interface{}, exists bool)       { return nil, false }
+func (m *MockContext) GetString(key string) string                           { return "" }
+func (m *MockContext) GetBool(key string) bool                               { return false }
+func (m *MockContext) GetInt(key string) int                                 { return 0 }
+func (m *MockContext) GetInt64(key string) int64                             { return 0 }
+func (m *MockContext) GetFloat64(key string) float64                         { return 0 }
+func (m *MockContext) Abort()                                                { m.aborted = true }
+func (m *MockContext) AbortWithStatus(code int) {
+	m.abortedWithStatus = code
+	m.aborted = true
+}
+func (m *MockContext) AbortWithStatusJSON(code int, jsonObj interface{}) {
+	m.abortedWithStatusJSON = code
+	m.jsonResponse = jsonObj
+	m.aborted = true
+}
+func (m *MockContext) IsAborted() bool { return m.aborted }
+func (m *MockContext) Next()           {}
+
+// MockServer implements Server interface
+type MockServer struct {
+	backendDB       *MockStorageBackendDB
+	colonyDB        *MockColonyDB
+	fileDB          *MockFileDB
+	validator       *MockValidator
+	lastError       error
+	lastStatusCode  int
+	lastPayloadType string
+	lastResponse    string
+	emptyReplySent  bool
+}
+
+func (m *MockServer) HandleHTTPError(c backends.Context, err error, errorCode int) bool {
+	if err != nil {
+		m.lastError = err
+		m.lastStatusCode = errorCode
+		c.AbortWithStatusJSON(errorCode, map[string]string{"error": err.Error()})
+		return true
+	}
+	return false
+}
+
+func (m *MockServer) SendHTTPReply(c backends.Context, payloadType string, jsonString string) {
+	m.lastPayloadType = payloadType
+	m.lastResponse = jsonString
+	c.JSON(http.StatusOK, map[string]string{"response": jsonString})
+}
+
+func (m *MockServer) SendEmptyHTTPReply(c backends.Context, payloadType string) {
+	m.lastPayloadType = payloadType
+	m.emptyReplySent = true
+	c.JSON(http.StatusOK, nil)
+}
+
+func (m *MockServer) GetValidator() security.Validator {
+	return m.validator
+}
+
+func (m *MockServer) GetStorageBackendDB() database.StorageBackendDatabase {
+	return m.backendDB
+}
+
+func (m *MockServer) GetColonyDB() database.ColonyDatabase {
+	return m.colonyDB
+}
+
+func (m *MockServer) FileDB() database.FileDatabase {
+	return m.fileDB
+}
+
+// Helper to create test storage backend
+func createTestStorageBackend() *core.StorageBackend {
+	return core.CreateStorageBackend("test-colony", "test-backend", "localhost:9000", "test-bucket", "test-accesskey", "test-secretkey")
+}
+
+// Object issued to the test colony
+var testObject = core.StorageObjectPrefix("test-colony") + "test-object"
+
+// Helper to create test file stored in the test storage backend
+func createTestFile() *core.File {
+	return &core.File{
+		ID:         "file-123",
+		ColonyName: "test-colony",
+		Label:      "/test",
+		Name:       "test-file",
+		Reference:  core.Reference{Protocol: "s3", Backend: "test-backend", S3Object: core.S3Object{Object: testObject}},
+	}
+}
+
+// Helper to create mock server
+func createMockServer() (*MockServer, *MockContext) {
+	server := &MockServer{
+		backendDB: &MockStorageBackendDB{backends: []*core.StorageBackend{createTestStorageBackend()}},
+		colonyDB:  &MockColonyDB{},
+		fileDB:    &MockFileDB{files: []*core.File{createTestFile()}},
+		validator: &MockValidator{},
+	}
+
+	ctx := &MockContext{}
+	return server, ctx
+}
+
+// Helper to create handlers with a mock presigner
+func createHandlers(server *MockServer) (*Handlers, *MockPresigner) {
+	handlers := NewHandlers(server)
+	presigner := &MockPresigner{}
+	handlers.presigner = presigner
+	return handlers, presigner
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	server, _ := createMockServer()
+	handlers := NewHandlers(server)
+	reg := registry.NewHandlerRegistry()
+
+	err := handlers.RegisterHandlers(reg)
+	assert.Nil(t, err)
+}
+
+// Tests for HandleAddStorageBackend
+func TestHandleAddStorageBackend_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Equal(t, rpc.AddStorageBackendPayloadType, server.lastPayloadType)
+
+	addedBackend, err := core.ConvertJSONToStorageBackend(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-backend", addedBackend.Name)
+	assert.Empty(t, addedBackend.AccessKey)
+	assert.Empty(t, addedBackend.SecretKey)
+	assert.Equal(t, "test-secretkey", server.backendDB.backends[0].SecretKey)
+}
+
+func TestHandleAddStorageBackend_ReservedCredentialPrefix(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{}
+	handlers := NewHandlers(server)
+
+	backend := createTestStorageBackend()
+	backend.SecretKey = "enc:v1:test-secretkey"
+	msg := rpc.CreateAddStorageBackendMsg(backend)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.backendDB.backends, 0)
+}
+
+func TestHandleAddStorageBackend_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddStorageBackend_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddStorageBackend_NilBackend(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(nil)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddStorageBackend_InvalidBackend(t *testing.T) {
+	invalid := []func(backend *core.StorageBackend){
+		func(backend *core.StorageBackend) { backend.Name = "" },
+		func(backend *core.StorageBackend) { backend.Server = "" },
+		func(backend *core.StorageBackend) { backend.Bucket = "" },
+		func(backend *core.StorageBackend) { backend.Protocol = "ftp" },
+	}
+	for _, modify := range invalid {
+		server, ctx := createMockServer()
+		server.backendDB.backends = []*core.StorageBackend{}
+		handlers := NewHandlers(server)
+
+		backend := createTestStorageBackend()
+		modify(backend)
+		msg := rpc.CreateAddStorageBackendMsg(backend)
+		jsonString, _ := msg.ToJSON()
+
+		handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+		assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+		assert.Len(t, server.backendDB.backends, 0)
+	}
+}
+
+func TestHandleAddStorageBackend_ColonyNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.colonyDB.returnNil = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddStorageBackend_NotColonyOwner(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{}
+	server.validator.colonyOwnerErr = errors.New("not colony owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+	assert.Len(t, server.backendDB.backends, 0)
+}
+
+func TestHandleAddStorageBackend_AlreadyExists(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.backendDB.backends, 1)
+}
+
+func TestHandleAddStorageBackend_AddError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.backends = []*core.StorageBackend{}
+	server.backendDB.addErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddStorageBackendMsg(createTestStorageBackend())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddStorageBackend(ctx, "test-user", rpc.AddStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+// Tests for HandleGetStorageBackends
+func TestHandleGetStorageBackends_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetStorageBackendsMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetStorageBackends(ctx, "test-user", rpc.GetStorageBackendsPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	backends, err := core.ConvertJSONToStorageBackendArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, backends, 1)
+	assert.Empty(t, backends[0].AccessKey)
+	assert.Empty(t, backends[0].SecretKey)
+	assert.Equal(t, "test-secretkey", server.backendDB.backends[0].SecretKey)
+}
+
+func TestHandleGetStorageBackends_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleGetStorageBackends(ctx, "test-user", rpc.GetStorageBackendsPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetStorageBackends_NotMember(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("not member")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetStorageBackendsMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetStorageBackends(ctx, "test-user", rpc.GetStorageBackendsPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+// Tests for HandleRemoveStorageBackend
+func TestHandleRemoveStorageBackend_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveStorageBackendMsg("test-colony", "test-backend")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveStorageBackend(ctx, "test-user", rpc.RemoveStorageBackendPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.True(t, server.emptyReplySent)
+}
+
+func TestHandleRemoveStorageBackend_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveStorageBackendMsg("test-colony", "does-not-exist")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveStorageBackend(ctx, "test-user", rpc.RemoveStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandleRemoveStorageBackend_NotColonyOwner(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.colonyOwnerErr = errors.New("not colony owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveStorageBackendMsg("test-colony", "test-backend")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveStorageBackend(ctx, "test-user", rpc.RemoveStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleRemoveStorageBackend_RemoveError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.removeByNameErr = errors.New("db error")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveStorageBackendMsg("test-colony", "test-backend")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveStorageBackend(ctx, "test-user", rpc.RemoveStorageBackendPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+// Tests for HandlePresignFile
+func TestHandlePresignFile_Get(t *testing.T) {
+	for _, method := range []string{core.PresignGet, core.PresignDelete} {
+		server, ctx := createMockServer()
+		handlers, presigner := createHandlers(server)
+
+		msg := rpc.CreatePresignFileMsg("test-colony", method, "file-123", "")
+		jsonString, _ := msg.ToJSON()
+
+		handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+		assert.Nil(t, server.lastError)
+		presignedURL, err := core.ConvertJSONToPresignedURL(server.lastResponse)
+		assert.Nil(t, err)
+		assert.Equal(t, method, presignedURL.Method)
+		assert.Equal(t, "test-backend", presignedURL.Backend)
+		assert.Equal(t, testObject, presignedURL.Object)
+		assert.Contains(t, presignedURL.URL, "X-Amz-Signature")
+		assert.True(t, presignedURL.Expires.After(time.Now()))
+		assert.Equal(t, method, presigner.lastMethod)
+		assert.Equal(t, testObject, presigner.lastObject)
+		assert.NotContains(t, server.lastResponse, "test-secretkey")
+	}
+}
+
+func TestHandlePresignFile_Put(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, presigner := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignPut, "", "test-backend")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	presignedURL, err := core.ConvertJSONToPresignedURL(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, core.PresignPut, presignedURL.Method)
+	assert.NotEqual(t, testObject, presignedURL.Object)
+	assert.True(t, core.IsColonyStorageObject("test-colony", presignedURL.Object))
+	assert.Equal(t, presignedURL.Object, presigner.lastObject)
+
+	// The issued object is recorded so that it can be registered by a file
+	assert.Len(t, server.backendDB.objects, 1)
+	assert.Equal(t, presignedURL.Object, server.backendDB.objects[0].Object)
+	assert.Equal(t, "test-backend", server.backendDB.objects[0].Backend)
+	assert.False(t, server.backendDB.objects[0].Registered)
+}
+
+func TestHandlePresignFile_PutAddObjectError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.backendDB.addObjectErr = errors.New("db error")
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignPut, "", "test-backend")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_ObjectOutsideNamespace(t *testing.T) {
+	for _, object := range []string{"test-object", core.StorageObjectPrefix("other-colony") + "test-object"} {
+		server, ctx := createMockServer()
+		server.fileDB.files[0].Reference.S3Object.Object = object
+		handlers, presigner := createHandlers(server)
+
+		msg := rpc.CreatePresignFileMsg("test-colony", core.PresignDelete, "file-123", "")
+		jsonString, _ := msg.ToJSON()
+
+		handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+		assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+		assert.Equal(t, "", presigner.lastObject)
+	}
+}
+
+func TestHandlePresignFile_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, _ := createHandlers(server)
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_InvalidMethod(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", "POST", "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_NotMember(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.membershipErr = errors.New("not member")
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignGet, "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_FileNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignGet, "does-not-exist", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_FileInOtherColony(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("other-colony", core.PresignGet, "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_FileWithoutBackend(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.files[0].Reference.Backend = ""
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignGet, "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_BackendNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignPut, "", "does-not-exist")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_FileDBError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.fileDB.getByIDErr = errors.New("db error")
+	handlers, _ := createHandlers(server)
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignGet, "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
+
+func TestHandlePresignFile_PresignError(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers, presigner := createHandlers(server)
+	presigner.err = errors.New("presign error")
+
+	msg := rpc.CreatePresignFileMsg("test-colony", core.PresignGet, "file-123", "")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandlePresignFile(ctx, "test-user", rpc.PresignFilePayloadType, jsonString)
+
+	assert.Equal(t, http.StatusInternalServerError, server.lastStatusCode)
+}
@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/colonyos/colonies/pkg/security/secrets"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	log "github.com/sirupsen/logrus"
+)
+
+type Server interface {
+	HandleHTTPError(c backends.Context, err error, errorCode int) bool
+	SendHTTPReply(c backends.Context, payloadType string, jsonString string)
+	SendEmptyHTTPReply(c backends.Context, payloadType string)
+	GetWebhookDB() database.WebhookDatabase
+	GetColonyDB() database.ColonyDatabase
+	GetValidator() security.Validator
+}
+
+type Handlers struct {
+	server       Server
+	egressPolicy *egress.Policy
+}
+
+func NewHandlers(server Server) *Handlers {
+	return &Handlers{
+		server:       server,
+		egressPolicy: egress.LoadPolicy(),
+	}
+}
+
+func (h *Handlers) RegisterHandlers(handlerRegistry *registry.HandlerRegistry) error {
+	if err := handlerRegistry.Register(rpc.AddWebhookPayloadType, h.HandleAddWebhook); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetWebhooksPayloadType, h.HandleGetWebhooks); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetWebhookPayloadType, h.HandleGetWebhook); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.RemoveWebhookPayloadType, h.HandleRemoveWebhook); err != nil {
+		return err
+	}
+	if err := handlerRegistry.Register(rpc.GetWebhookDeliveriesPayloadType, h.HandleGetWebhookDeliveries); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *Handlers) validateWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return errors.New("Failed to add webhook, url is empty")
+	}
+
+	if err := h.egressPolicy.CheckURL(webhookURL); err != nil {
+		return errors.New("Failed to add webhook, " + err.Error())
+	}
+
+	return nil
+}
+
+// redactSecret returns a copy of the webhook without the signing secret, secrets are never sent back to clients
+func redactSecret(webhook *core.Webhook) *core.Webhook {
+	redacted := *webhook
+	redacted.Secret = ""
+
+	return &redacted
+}
+
+func (h *Handlers) HandleAddWebhook(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateAddWebhookMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to add webhook, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Webhook == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, webhook is nil"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Webhook.Name == "" {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, name is empty"), http.StatusBadRequest)
+		return
+	}
+
+	err = h.validateWebhookURL(msg.Webhook.URL)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if msg.Webhook.MaxRetries < 0 {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, maxretries cannot be negative"), http.StatusBadRequest)
+		return
+	}
+
+	// A secret looking sealed would be stored as is and could then not be opened
+	if secrets.IsSealed(msg.Webhook.Secret) {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, secret cannot start with a reserved prefix"), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(msg.Webhook.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+msg.Webhook.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhookExist, err := h.server.GetWebhookDB().GetWebhookByName(msg.Webhook.ColonyName, msg.Webhook.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if webhookExist != nil {
+		if h.server.HandleHTTPError(c, errors.New("A webhook with name <"+msg.Webhook.Name+"> already exists in Colony with name <"+msg.Webhook.ColonyName+">"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	msg.Webhook.ID = core.GenerateRandomID()
+	err = h.server.GetWebhookDB().AddWebhook(msg.Webhook)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	addedWebhook, err := h.server.GetWebhookDB().GetWebhookByName(colony.Name, msg.Webhook.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	if addedWebhook == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to add webhook, addedWebhook is nil"), http.StatusInternalServerError)
+		return
+	}
+
+	jsonString, err = redactSecret(addedWebhook).ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": addedWebhook.ColonyName, "Name": addedWebhook.Name, "WebhookID": addedWebhook.ID}).Debug("Adding webhook")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleGetWebhooks(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetWebhooksMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get webhooks, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhooks, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(msg.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+msg.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhooks, err := h.server.GetWebhookDB().GetWebhooksByColonyName(colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	redactedWebhooks := make([]*core.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		redactedWebhooks = append(redactedWebhooks, redactSecret(webhook))
+	}
+
+	jsonString, err = core.ConvertWebhookArrayToJSON(redactedWebhooks)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": colony.Name}).Debug("Getting webhooks")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleGetWebhook(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetWebhookMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get webhook, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhook, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(msg.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+msg.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhook, err := h.server.GetWebhookDB().GetWebhookByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if webhook == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhook, webhook with name <"+msg.Name+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	jsonString, err = redactSecret(webhook).ToJSON()
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Name": msg.Name}).Debug("Getting webhook")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
+
+func (h *Handlers) HandleRemoveWebhook(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateRemoveWebhookMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to remove webhook, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to remove webhook, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(msg.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+msg.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhook, err := h.server.GetWebhookDB().GetWebhookByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if webhook == nil {
+		if h.server.HandleHTTPError(c, errors.New("Webhook with name <"+msg.Name+"> not found"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetWebhookDB().RemoveWebhookByName(colony.Name, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Name": msg.Name}).Debug("Removing webhook")
+
+	h.server.SendEmptyHTTPReply(c, payloadType)
+}
+
+func (h *Handlers) HandleGetWebhookDeliveries(c backends.Context, recoveredID string, payloadType string, jsonString string) {
+	msg, err := rpc.CreateGetWebhookDeliveriesMsgFromJSON(jsonString)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to get webhook deliveries, invalid JSON"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if msg.MsgType != payloadType {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhook deliveries, msg.MsgType does not match payloadType"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Count > constants.WEBHOOK_MAX_DELIVERY_COUNT {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhook deliveries, count exceeds max limit <"+strconv.Itoa(constants.WEBHOOK_MAX_DELIVERY_COUNT)+">"), http.StatusBadRequest)
+		return
+	}
+
+	if msg.Count <= 0 {
+		msg.Count = constants.WEBHOOK_MAX_DELIVERY_COUNT
+	}
+
+	colony, err := h.server.GetColonyDB().GetColonyByName(msg.ColonyName)
+	if err != nil {
+		if h.server.HandleHTTPError(c, errors.New("Failed to resolve colony name"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	if colony == nil {
+		if h.server.HandleHTTPError(c, errors.New("Colony with name <"+msg.ColonyName+"> does not exists"), http.StatusBadRequest) {
+			return
+		}
+	}
+
+	err = h.server.GetValidator().RequireColonyOwner(recoveredID, colony.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusForbidden) {
+		return
+	}
+
+	webhook, err := h.server.GetWebhookDB().GetWebhookByName(msg.ColonyName, msg.Name)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	if webhook == nil {
+		h.server.HandleHTTPError(c, errors.New("Failed to get webhook deliveries, webhook with name <"+msg.Name+"> not found"), http.StatusNotFound)
+		return
+	}
+
+	deliveries, err := h.server.GetWebhookDB().GetWebhookDeliveries(webhook.ID, msg.Count)
+	if h.server.HandleHTTPError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	jsonString, err = core.ConvertWebhookDeliveryArrayToJSON(deliveries)
+	if h.server.HandleHTTPError(c, err, http.StatusInternalServerError) {
+		return
+	}
+
+	log.WithFields(log.Fields{"ColonyName": msg.ColonyName, "Name": msg.Name, "Count": msg.Count}).Debug("Getting webhook deliveries")
+
+	h.server.SendHTTPReply(c, payloadType, jsonString)
+}
@@ -0,0 +1,161 @@
+package webhook_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/colonyos/colonies/pkg/server"
+	"github.com/colonyos/colonies/pkg/utils"
+	"github.com/colonyos/colonies/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWebhook(t *testing.T) {
+	client, s, serverPrvKey, done := server.PrepareTests(t)
+
+	colony, colonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client.AddColony(colony, serverPrvKey)
+	assert.Nil(t, err)
+
+	wh := utils.CreateTestWebhook(colony.Name, "test_webhook")
+	addedWebhook, err := client.AddWebhook(wh, colonyPrvKey)
+	assert.Nil(t, err)
+	assert.NotNil(t, addedWebhook)
+	assert.Equal(t, "test_webhook", addedWebhook.Name)
+	assert.Empty(t, addedWebhook.Secret)
+
+	// Same name again should fail
+	_, err = client.AddWebhook(utils.CreateTestWebhook(colony.Name, "test_webhook"), colonyPrvKey)
+	assert.NotNil(t, err)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestAddWebhookNotColonyOwner(t *testing.T) {
+	client, s, serverPrvKey, done := server.PrepareTests(t)
+
+	colony, colonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client.AddColony(colony, serverPrvKey)
+	assert.Nil(t, err)
+
+	executor, executorPrvKey, err := utils.CreateTestExecutorWithKey(colony.Name)
+	assert.Nil(t, err)
+	_, err = client.AddExecutor(executor, colonyPrvKey)
+	assert.Nil(t, err)
+	err = client.ApproveExecutor(colony.Name, executor.Name, colonyPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.AddWebhook(utils.CreateTestWebhook(colony.Name, "test_webhook"), executorPrvKey)
+	assert.NotNil(t, err)
+
+	_, err = client.GetWebhooks(colony.Name, executorPrvKey)
+	assert.NotNil(t, err)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestGetAndRemoveWebhook(t *testing.T) {
+	client, s, serverPrvKey, done := server.PrepareTests(t)
+
+	colony, colonyPrvKey, err := utils.CreateTestColonyWithKey()
+	assert.Nil(t, err)
+	_, err = client.AddColony(colony, serverPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.AddWebhook(utils.CreateTestWebhook(colony.Name, "test_webhook1"), colonyPrvKey)
+	assert.Nil(t, err)
+	_, err = client.AddWebhook(utils.CreateTestWebhook(colony.Name, "test_webhook2"), colonyPrvKey)
+	assert.Nil(t, err)
+
+	webhooks, err := client.GetWebhooks(colony.Name, colonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 2)
+
+	webhookFromServer, err := client.GetWebhook(colony.Name, "test_webhook1", colonyPrvKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "test_webhook1", webhookFromServer.Name)
+
+	err = client.RemoveWebhook(colony.Name, "test_webhook1", colonyPrvKey)
+	assert.Nil(t, err)
+
+	_, err = client.GetWebhook(colony.Name, "test_webhook1", colonyPrvKey)
+	assert.NotNil(t, err)
+
+	err = client.RemoveWebhook(colony.Name, "test_webhook1", colonyPrvKey)
+	assert.NotNil(t, err)
+
+	webhooks, err = client.GetWebhooks(colony.Name, colonyPrvKey)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+
+	s.Shutdown()
+	<-done
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	// The receiver listens on loopback, which is denied by default
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	env, client, s, _, done := server.SetupTestEnv2(t)
+
+	received := make(chan *core.WebhookEvent, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("test_secret", r.Header.Get(webhook.TimestampHeader), body, r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		event, err := core.ConvertJSONToWebhookEvent(string(body))
+		if err == nil {
+			received <- event
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	wh := core.CreateWebhook(env.ColonyName, "test_webhook", receiver.URL, "test_secret")
+	wh.States = []int{core.FAILED}
+	_, err := client.AddWebhook(wh, env.ColonyPrvKey)
+	assert.Nil(t, err)
+
+	funcSpec := utils.CreateTestFunctionSpec(env.ColonyName)
+	_, err = client.Submit(funcSpec, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	assignedProcess, err := client.Assign(env.ColonyName, -1, "", "", env.ExecutorPrvKey)
+	assert.Nil(t, err)
+	err = client.Fail(assignedProcess.ID, []string{"error"}, env.ExecutorPrvKey)
+	assert.Nil(t, err)
+
+	select {
+	case event := <-received:
+		assert.Equal(t, core.WebhookEventProcessFailed, event.Type)
+		assert.Equal(t, assignedProcess.ID, event.Process.ID)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for webhook event")
+	}
+
+	// The delivery is recorded after the receiver has replied
+	var deliveries []*core.WebhookDelivery
+	for i := 0; i < 50; i++ {
+		deliveries, err = client.GetWebhookDeliveries(env.ColonyName, "test_webhook", 10, env.ColonyPrvKey)
+		assert.Nil(t, err)
+		if len(deliveries) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Success)
+
+	s.Shutdown()
+	<-done
+}
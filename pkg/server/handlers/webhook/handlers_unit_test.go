@@ -0,0 +1,562 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/backends"
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/rpc"
+	"github.com/colonyos/colonies/pkg/security"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/colonyos/colonies/pkg/server/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockWebhookDB implements database.WebhookDatabase
+type MockWebhookDB struct {
+	webhooks        []*core.Webhook
+	deliveries      []*core.WebhookDelivery
+	addErr          error
+	getByNameErr    error
+	removeByNameErr error
+	lastCount       int
+}
+
+func (m *MockWebhookDB) AddWebhook(webhook *core.Webhook) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.webhooks = append(m.webhooks, webhook)
+	return nil
+}
+
+func (m *MockWebhookDB) GetWebhookByID(webhookID string) (*core.Webhook, error) {
+	for _, w := range m.webhooks {
+		if w.ID == webhookID {
+			return w, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookDB) GetWebhookByName(colonyName string, name string) (*core.Webhook, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	for _, w := range m.webhooks {
+		if w.Name == name && w.ColonyName == colonyName {
+			return w, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookDB) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *MockWebhookDB) RemoveWebhookByName(colonyName string, name string) error {
+	return m.removeByNameErr
+}
+
+func (m *MockWebhookDB) RemoveWebhooksByColonyName(colonyName string) error {
+	return nil
+}
+
+func (m *MockWebhookDB) AddWebhookDelivery(delivery *core.WebhookDelivery) error {
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *MockWebhookDB) GetWebhookDeliveries(webhookID string, count int) ([]*core.WebhookDelivery, error) {
+	m.lastCount = count
+	return m.deliveries, nil
+}
+
+// MockColonyDB implements database.ColonyDatabase
+type MockColonyDB struct {
+	colonies     []*core.Colony
+	getByNameErr error
+	returnNil    bool
+}
+
+func (m *MockColonyDB) AddColony(colony *core.Colony) error                 { return nil }
+func (m *MockColonyDB) GetColonies() ([]*core.Colony, error)                { return nil, nil }
+func (m *MockColonyDB) GetColonyByID(colonyID string) (*core.Colony, error) { return nil, nil }
+func (m *MockColonyDB) RemoveColonyByName(colonyName string) error          { return nil }
+func (m *MockColonyDB) RemoveColonies() error                               { return nil }
+func (m *MockColonyDB) CountColonies() (int, error)                         { return 0, nil }
+func (m *MockColonyDB) RenameColony(colonyName, newName string) error       { return nil }
+
+func (m *MockColonyDB) GetColonyByName(colonyName string) (*core.Colony, error) {
+	if m.getByNameErr != nil {
+		return nil, m.getByNameErr
+	}
+	if m.returnNil {
+		return nil, nil
+	}
+	for _, c := range m.colonies {
+		if c.Name == colonyName {
+			return c, nil
+		}
+	}
+	return &core.Colony{ID: "colony-123", Name: colonyName}, nil
+}
+
+// MockValidator implements security.Validator
+type MockValidator struct {
+	membershipErr  error
+	colonyOwnerErr error
+	serverOwnerErr error
+}
+
+func (m *MockValidator) RequireMembership(recoveredID string, colonyName string, executorMayJoin bool) error {
+	return m.membershipErr
+}
+
+func (m *MockValidator) RequireColonyOwner(recoveredID string, colonyName string) error {
+	return m.colonyOwnerErr
+}
+
+func (m *MockValidator) RequireServerOwner(recoveredID string, serverID string) error {
+	return m.serverOwnerErr
+}
+
+// MockContext implements backends.Context
+type MockContext struct {
+	aborted               bool
+	abortedWithStatus     int
+	abortedWithStatusJSON int
+	jsonResponse          interface{}
+}
+
+func (m *MockContext) String(code int, format string, values ...interface{}) {}
+func (m *MockContext) JSON(code int, obj interface{})                        { m.jsonResponse = obj }
+func (m *MockContext) XML(code int, obj interface{})                         {}
+func (m *MockContext) Data(code int, contentType string, data []byte)        {}
+func (m *MockContext) Status(code int)                                       {}
+func (m *MockContext) Request() *http.Request                                { return nil }
+func (m *MockContext) ReadBody() ([]byte, error)                             { return nil, nil }
+func (m *MockContext) GetHeader(key string) string                           { return "" }
+func (m *MockContext) Header(key, value string)                              {}
+func (m *MockContext) Param(key string) string                               { return "" }
+func (m *MockContext) Query(key string) string                               { return "" }
+func (m *MockContext) DefaultQuery(key, defaultValue string) string          { return defaultValue }
+func (m *MockContext) PostForm(key string) string                            { return "" }
+func (m *MockContext) DefaultPostForm(key, defaultValue string) string       { return defaultValue }
+func (m *MockContext) Bind(obj interface{}) error                            { return nil }
+func (m *MockContext) ShouldBind(obj interface{}) error                      { return nil }
+func (m *MockContext) BindJSON(obj interface{}) error                        { return nil }
+func (m *MockContext) ShouldBindJSON(obj interface{}) error                  { return nil }
+func (m *MockContext) Set(key string, value interface{})                     {}
+func (m *MockContext) Get(key string) (value interface{}, exists bool)       { return nil, false }
+func (m *MockContext) GetString(key string) string                           { return "" }
+func (m *MockContext) GetBool(key string) bool                               { return false }
+func (m *MockContext) GetInt(key string) int                                 { return 0 }
+func (m *MockContext) GetInt64(key string) int64                             { return 0 }
+func (m *MockContext) GetFloat64(key string) float64                         { return 0 }
+func (m *MockContext) Abort()                                                { m.aborted = true }
+func (m *MockContext) AbortWithStatus(code int) {
+	m.abortedWithStatus = code
+	m.aborted = true
+}
+func (m *MockContext) AbortWithStatusJSON(code int, jsonObj interface{}) {
+	m.abortedWithStatusJSON = code
+	m.jsonResponse = jsonObj
+	m.aborted = true
+}
+func (m *MockContext) IsAborted() bool { return m.aborted }
+func (m *MockContext) Next()           {}
+
+// MockServer implements Server interface
+type MockServer struct {
+	webhookDB       *MockWebhookDB
+	colonyDB        *MockColonyDB
+	validator       *MockValidator
+	lastError       error
+	lastStatusCode  int
+	lastPayloadType string
+	lastResponse    string
+	emptyReplySent  bool
+}
+
+func (m *MockServer) HandleHTTPError(c backends.Context, err error, errorCode int) bool {
+	if err != nil {
+		m.lastError = err
+		m.lastStatusCode = errorCode
+		c.AbortWithStatusJSON(errorCode, map[string]string{"error": err.Error()})
+		return true
+	}
+	return false
+}
+
+func (m *MockServer) SendHTTPReply(c backends.Context, payloadType string, jsonString string) {
+	m.lastPayloadType = payloadType
+	m.lastResponse = jsonString
+	c.JSON(http.StatusOK, map[string]string{"response": jsonString})
+}
+
+func (m *MockServer) SendEmptyHTTPReply(c backends.Context, payloadType string) {
+	m.lastPayloadType = payloadType
+	m.emptyReplySent = true
+	c.JSON(http.StatusOK, nil)
+}
+
+func (m *MockServer) GetValidator() security.Validator {
+	return m.validator
+}
+
+func (m *MockServer) GetWebhookDB() database.WebhookDatabase {
+	return m.webhookDB
+}
+
+func (m *MockServer) GetColonyDB() database.ColonyDatabase {
+	return m.colonyDB
+}
+
+// Helper to create test webhook
+func createTestWebhook() *core.Webhook {
+	return core.CreateWebhook("test-colony", "test-webhook", "https://203.0.113.10/hook", "test-secret")
+}
+
+// Helper to create mock server
+func createMockServer() (*MockServer, *MockContext) {
+	webhookDB := &MockWebhookDB{webhooks: []*core.Webhook{createTestWebhook()}}
+
+	server := &MockServer{
+		webhookDB: webhookDB,
+		colonyDB:  &MockColonyDB{},
+		validator: &MockValidator{},
+	}
+
+	ctx := &MockContext{}
+	return server, ctx
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	server, _ := createMockServer()
+	handlers := NewHandlers(server)
+	reg := registry.NewHandlerRegistry()
+
+	err := handlers.RegisterHandlers(reg)
+	assert.Nil(t, err)
+}
+
+// Tests for HandleAddWebhook
+func TestHandleAddWebhook_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.webhookDB.webhooks = []*core.Webhook{}
+	handlers := NewHandlers(server)
+
+	webhook := createTestWebhook()
+	msg := rpc.CreateAddWebhookMsg(webhook)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Nil(t, server.lastError)
+	assert.Equal(t, rpc.AddWebhookPayloadType, server.lastPayloadType)
+
+	addedWebhook, err := core.ConvertJSONToWebhook(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-webhook", addedWebhook.Name)
+	assert.Empty(t, addedWebhook.Secret)
+	assert.Equal(t, "test-secret", server.webhookDB.webhooks[0].Secret)
+}
+
+func TestHandleAddWebhook_ReservedSecretPrefix(t *testing.T) {
+	server, ctx := createMockServer()
+	server.webhookDB.webhooks = []*core.Webhook{}
+	handlers := NewHandlers(server)
+
+	webhook := createTestWebhook()
+	webhook.Secret = "enc:v1:test-secret"
+	msg := rpc.CreateAddWebhookMsg(webhook)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.Len(t, server.webhookDB.webhooks, 0)
+}
+
+func TestHandleAddWebhook_InvalidJSON(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, "invalid json")
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddWebhook_MsgTypeMismatch(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddWebhookMsg(createTestWebhook())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", "wrong-type", jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddWebhook_NilWebhook(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddWebhookMsg(nil)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddWebhook_InvalidURL(t *testing.T) {
+	urls := []string{"", "ftp://localhost/hook", "http://", "not a url"}
+	for _, u := range urls {
+		server, ctx := createMockServer()
+		server.webhookDB.webhooks = []*core.Webhook{}
+		handlers := NewHandlers(server)
+
+		webhook := createTestWebhook()
+		webhook.URL = u
+		msg := rpc.CreateAddWebhookMsg(webhook)
+		jsonString, _ := msg.ToJSON()
+
+		handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+		assert.Equal(t, http.StatusBadRequest, server.lastStatusCode, u)
+		assert.Len(t, server.webhookDB.webhooks, 0)
+	}
+}
+
+func TestHandleAddWebhook_BlockedURL(t *testing.T) {
+	urls := []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/hook", "http://[::1]/hook"}
+	for _, u := range urls {
+		server, ctx := createMockServer()
+		server.webhookDB.webhooks = []*core.Webhook{}
+		handlers := NewHandlers(server)
+
+		webhook := createTestWebhook()
+		webhook.URL = u
+		msg := rpc.CreateAddWebhookMsg(webhook)
+		jsonString, _ := msg.ToJSON()
+
+		handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+		assert.Equal(t, http.StatusBadRequest, server.lastStatusCode, u)
+		assert.Len(t, server.webhookDB.webhooks, 0)
+	}
+
+	// Internal addresses can be allowed explicitly
+	t.Setenv(egress.AllowEnv, "10.0.0.0/8")
+	server, ctx := createMockServer()
+	server.webhookDB.webhooks = []*core.Webhook{}
+	handlers := NewHandlers(server)
+
+	webhook := createTestWebhook()
+	webhook.URL = "http://10.0.0.1/hook"
+	msg := rpc.CreateAddWebhookMsg(webhook)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Len(t, server.webhookDB.webhooks, 1)
+}
+
+func TestHandleAddWebhook_ColonyNotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	server.colonyDB.returnNil = true
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddWebhookMsg(createTestWebhook())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleAddWebhook_ColonyOwnerError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.webhookDB.webhooks = []*core.Webhook{}
+	server.validator.colonyOwnerErr = errors.New("not owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddWebhookMsg(createTestWebhook())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+func TestHandleAddWebhook_AlreadyExists(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateAddWebhookMsg(createTestWebhook())
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleAddWebhook(ctx, "test-user", rpc.AddWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+// Tests for HandleGetWebhooks
+func TestHandleGetWebhooks_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhooksMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhooks(ctx, "test-user", rpc.GetWebhooksPayloadType, jsonString)
+
+	assert.Equal(t, rpc.GetWebhooksPayloadType, server.lastPayloadType)
+	webhooks, err := core.ConvertJSONToWebhookArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret)
+}
+
+func TestHandleGetWebhooks_ColonyOwnerError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.colonyOwnerErr = errors.New("not owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhooksMsg("test-colony")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhooks(ctx, "test-user", rpc.GetWebhooksPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+// Tests for HandleGetWebhook
+func TestHandleGetWebhook_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookMsg("test-colony", "test-webhook")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhook(ctx, "test-user", rpc.GetWebhookPayloadType, jsonString)
+
+	assert.Equal(t, rpc.GetWebhookPayloadType, server.lastPayloadType)
+	webhook, err := core.ConvertJSONToWebhook(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-webhook", webhook.Name)
+	assert.Empty(t, webhook.Secret)
+}
+
+func TestHandleGetWebhook_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookMsg("test-colony", "unknown-webhook")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhook(ctx, "test-user", rpc.GetWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
+
+// Tests for HandleRemoveWebhook
+func TestHandleRemoveWebhook_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveWebhookMsg("test-colony", "test-webhook")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveWebhook(ctx, "test-user", rpc.RemoveWebhookPayloadType, jsonString)
+
+	assert.True(t, server.emptyReplySent)
+}
+
+func TestHandleRemoveWebhook_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveWebhookMsg("test-colony", "unknown-webhook")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveWebhook(ctx, "test-user", rpc.RemoveWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+	assert.False(t, server.emptyReplySent)
+}
+
+func TestHandleRemoveWebhook_ColonyOwnerError(t *testing.T) {
+	server, ctx := createMockServer()
+	server.validator.colonyOwnerErr = errors.New("not owner")
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateRemoveWebhookMsg("test-colony", "test-webhook")
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleRemoveWebhook(ctx, "test-user", rpc.RemoveWebhookPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusForbidden, server.lastStatusCode)
+}
+
+// Tests for HandleGetWebhookDeliveries
+func TestHandleGetWebhookDeliveries_Success(t *testing.T) {
+	server, ctx := createMockServer()
+	server.webhookDB.deliveries = []*core.WebhookDelivery{{ID: "delivery-123", Success: true, Attempts: 1}}
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookDeliveriesMsg("test-colony", "test-webhook", 10)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhookDeliveries(ctx, "test-user", rpc.GetWebhookDeliveriesPayloadType, jsonString)
+
+	assert.Equal(t, rpc.GetWebhookDeliveriesPayloadType, server.lastPayloadType)
+	assert.Equal(t, 10, server.webhookDB.lastCount)
+	deliveries, err := core.ConvertJSONToWebhookDeliveryArray(server.lastResponse)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestHandleGetWebhookDeliveries_DefaultCount(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookDeliveriesMsg("test-colony", "test-webhook", 0)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhookDeliveries(ctx, "test-user", rpc.GetWebhookDeliveriesPayloadType, jsonString)
+
+	assert.Equal(t, constants.WEBHOOK_MAX_DELIVERY_COUNT, server.webhookDB.lastCount)
+}
+
+func TestHandleGetWebhookDeliveries_CountExceedsLimit(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookDeliveriesMsg("test-colony", "test-webhook", constants.WEBHOOK_MAX_DELIVERY_COUNT+1)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhookDeliveries(ctx, "test-user", rpc.GetWebhookDeliveriesPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusBadRequest, server.lastStatusCode)
+}
+
+func TestHandleGetWebhookDeliveries_NotFound(t *testing.T) {
+	server, ctx := createMockServer()
+	handlers := NewHandlers(server)
+
+	msg := rpc.CreateGetWebhookDeliveriesMsg("test-colony", "unknown-webhook", 10)
+	jsonString, _ := msg.ToJSON()
+
+	handlers.HandleGetWebhookDeliveries(ctx, "test-user", rpc.GetWebhookDeliveriesPayloadType, jsonString)
+
+	assert.Equal(t, http.StatusNotFound, server.lastStatusCode)
+}
@@ -76,7 +76,7 @@ type GenericServer interface {
 		GetGenerator(generatorID string) (*core.Generator, error)
 		ResolveGenerator(colonyName string, generatorName string) (*core.Generator, error)
 		GetGenerators(colonyName string, count int) ([]*core.Generator, error)
-		PackGenerator(generatorID string, colonyName string, arg string) error
+		PackGenerator(generatorID string, colonyName string, key string, arg string) error
 		RemoveGenerator(generatorID string) error
 		GetGeneratorPeriod() int
 	}
@@ -87,6 +87,9 @@ type GenericServer interface {
 		GetCronByName(colonyName string, cronName string) (*core.Cron, error)
 		RunCron(cronID string) (*core.Cron, error)
 		RemoveCron(cronID string) error
+		SuspendCron(cronID string) (*core.Cron, error)
+		ResumeCron(cronID string) (*core.Cron, error)
+		GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 		GetCronPeriod() int
 	}
 }
\ No newline at end of file
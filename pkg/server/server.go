@@ -14,6 +14,8 @@ import (
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/security/crypto"
@@ -30,14 +32,18 @@ import (
 	generatorhandlers "github.com/colonyos/colonies/pkg/server/handlers/generator"
 	locationhandlers "github.com/colonyos/colonies/pkg/server/handlers/location"
 	loghandlers "github.com/colonyos/colonies/pkg/server/handlers/log"
+	logsinkhandlers "github.com/colonyos/colonies/pkg/server/handlers/logsink"
 	"github.com/colonyos/colonies/pkg/server/handlers/process"
 	"github.com/colonyos/colonies/pkg/server/handlers/processgraph"
 	realtimehandlers "github.com/colonyos/colonies/pkg/server/handlers/realtime"
 	securityhandlers "github.com/colonyos/colonies/pkg/server/handlers/security"
 	serverhandlers "github.com/colonyos/colonies/pkg/server/handlers/server"
 	snapshothandlers "github.com/colonyos/colonies/pkg/server/handlers/snapshot"
+	storagebackendhandlers "github.com/colonyos/colonies/pkg/server/handlers/storagebackend"
 	"github.com/colonyos/colonies/pkg/server/handlers/user"
+	webhookhandlers "github.com/colonyos/colonies/pkg/server/handlers/webhook"
 	"github.com/colonyos/colonies/pkg/server/registry"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -73,6 +79,11 @@ type Server struct {
 	resourceDB              database.BlueprintDatabase
 	securityDB              database.SecurityDatabase
 	locationDB              database.LocationDatabase
+	webhookDB               database.WebhookDatabase
+	storageBackendDB        database.StorageBackendDatabase
+	fileRetentionDB         database.FileRetentionDatabase
+	fileLineageDB           database.FileLineageDatabase
+	logSinkDB               database.LogSinkDatabase
 	exclusiveAssign         bool
 	allowExecutorReregister bool
 	retention               bool
@@ -100,8 +111,14 @@ type Server struct {
 	realtimeHandlers       *realtimehandlers.Handlers
 	channelHandlers        *channelhandlers.Handlers
 	locationHandlers       *locationhandlers.Handlers
+	webhookHandlers        *webhookhandlers.Handlers
+	storageBackendHandlers *storagebackendhandlers.Handlers
+	logSinkHandlers        *logsinkhandlers.Handlers
 	backendRealtimeHandler realtimehandlers.RealtimeHandler
 	channelRouter          *channel.Router
+	logBroker              *logstream.Broker
+	logSinkPipeline        *logsink.Pipeline
+	functionArgsVerifier   *serverutils.FunctionArgsVerifier
 }
 
 func CreateServer(db database.Database,
@@ -162,6 +179,11 @@ func createServerInternal(db database.Database,
 	server.resourceDB = db
 	server.securityDB = db
 	server.locationDB = db
+	server.webhookDB = db
+	server.storageBackendDB = db
+	server.fileRetentionDB = db
+	server.fileLineageDB = db
+	server.logSinkDB = db
 
 	server.controller = controllers.CreateColoniesController(db, thisNode, clusterConfig, etcdDataPath, generatorPeriod, cronPeriod, retention, retentionPolicy, retentionPeriod, staleExecutorDuration)
 
@@ -196,8 +218,14 @@ func createServerInternal(db database.Database,
 	server.securityHandlers = securityhandlers.NewHandlers(server.serverAdapter)
 	server.realtimeHandlers = realtimehandlers.NewHandlers(server.serverAdapter)
 	server.channelRouter = server.controller.GetChannelRouter()
+	server.logBroker = server.controller.GetLogBroker()
+	server.logSinkPipeline = server.controller.GetLogSinkPipeline()
+	server.functionArgsVerifier = server.controller.GetFunctionArgsVerifier()
 	server.channelHandlers = channelhandlers.NewHandlers(server.serverAdapter)
 	server.locationHandlers = locationhandlers.NewHandlers(server.serverAdapter)
+	server.webhookHandlers = webhookhandlers.NewHandlers(server.serverAdapter)
+	server.storageBackendHandlers = storagebackendhandlers.NewHandlers(server.serverAdapter)
+	server.logSinkHandlers = logsinkhandlers.NewHandlers(server.serverAdapter)
 
 	// Create backend-specific realtime handler
 	server.backendRealtimeHandler = gin.NewRealtimeHandler(server.serverAdapter)
@@ -319,6 +347,21 @@ func (server *Server) registerHandlers() {
 	if err := server.locationHandlers.RegisterHandlers(server.handlerRegistry); err != nil {
 		log.WithFields(log.Fields{"Error": err}).Fatal("Failed to register location handlers")
 	}
+
+	// Register webhook handlers
+	if err := server.webhookHandlers.RegisterHandlers(server.handlerRegistry); err != nil {
+		log.WithFields(log.Fields{"Error": err}).Fatal("Failed to register webhook handlers")
+	}
+
+	// Register storage backend handlers
+	if err := server.storageBackendHandlers.RegisterHandlers(server.handlerRegistry); err != nil {
+		log.WithFields(log.Fields{"Error": err}).Fatal("Failed to register storage backend handlers")
+	}
+
+	// Register log sink handlers
+	if err := server.logSinkHandlers.RegisterHandlers(server.handlerRegistry); err != nil {
+		log.WithFields(log.Fields{"Error": err}).Fatal("Failed to register log sink handlers")
+	}
 }
 
 func (server *Server) getServerID() (string, error) {
@@ -443,6 +486,16 @@ func (server *Server) buildServerInfoFromEnv() *core.ServerInfo {
 	tls := tlsStr == "true"
 	serverInfo.AddBackend("http", port, host, tls, false)
 
+	// gRPC backend is only announced if enabled
+	grpcPortStr := os.Getenv("COLONIES_SERVER_GRPC_PORT")
+	if grpcPortStr != "" {
+		grpcPort := 0
+		fmt.Sscanf(grpcPortStr, "%d", &grpcPort)
+		if grpcPort > 0 {
+			serverInfo.AddBackend("grpc", grpcPort, host, tls, false)
+		}
+	}
+
 	return serverInfo
 }
 
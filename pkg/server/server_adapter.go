@@ -7,6 +7,8 @@ import (
 	"github.com/colonyos/colonies/pkg/cluster"
 	"github.com/colonyos/colonies/pkg/core"
 	"github.com/colonyos/colonies/pkg/database"
+	"github.com/colonyos/colonies/pkg/logsink"
+	"github.com/colonyos/colonies/pkg/logstream"
 	"github.com/colonyos/colonies/pkg/rpc"
 	"github.com/colonyos/colonies/pkg/security"
 	"github.com/colonyos/colonies/pkg/server/controllers"
@@ -15,6 +17,7 @@ import (
 	"github.com/colonyos/colonies/pkg/server/handlers/processgraph"
 	realtimehandlers "github.com/colonyos/colonies/pkg/server/handlers/realtime"
 	serverhandlers "github.com/colonyos/colonies/pkg/server/handlers/server"
+	serverutils "github.com/colonyos/colonies/pkg/server/utils"
 )
 
 // ServerAdapter implements interfaces needed by handler packages
@@ -49,6 +52,34 @@ func (s *ServerAdapter) LocationDB() database.LocationDatabase {
 	return s.server.locationDB
 }
 
+func (s *ServerAdapter) GetWebhookDB() database.WebhookDatabase {
+	return s.server.webhookDB
+}
+
+func (s *ServerAdapter) WebhookDB() database.WebhookDatabase {
+	return s.server.webhookDB
+}
+
+func (s *ServerAdapter) GetStorageBackendDB() database.StorageBackendDatabase {
+	return s.server.storageBackendDB
+}
+
+func (s *ServerAdapter) StorageBackendDB() database.StorageBackendDatabase {
+	return s.server.storageBackendDB
+}
+
+func (s *ServerAdapter) GetLogSinkDB() database.LogSinkDatabase {
+	return s.server.logSinkDB
+}
+
+func (s *ServerAdapter) FileRetentionDB() database.FileRetentionDatabase {
+	return s.server.fileRetentionDB
+}
+
+func (s *ServerAdapter) FileLineageDB() database.FileLineageDatabase {
+	return s.server.fileLineageDB
+}
+
 func (s *ServerAdapter) GetValidator() security.Validator {
 	return s.server.validator
 }
@@ -115,6 +146,10 @@ func (s *ServerAdapter) FunctionDB() database.FunctionDatabase {
 	return s.server.functionDB
 }
 
+func (s *ServerAdapter) FunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return s.server.functionArgsVerifier
+}
+
 func (s *ServerAdapter) GeneratorDB() database.GeneratorDatabase {
 	return s.server.generatorDB
 }
@@ -130,7 +165,7 @@ func (s *ServerAdapter) ProcessGraphDB() database.ProcessGraphDatabase {
 type generatorControllerAdapter struct {
 	controller interface {
 		AddGenerator(generator *core.Generator) (*core.Generator, error)
-		PackGenerator(generatorID string, colonyName string, arg string) error
+		PackGenerator(generatorID string, colonyName string, key string, arg string) error
 		RemoveGenerator(generatorID string) error
 		GetGeneratorPeriod() int
 	}
@@ -140,8 +175,8 @@ func (c *generatorControllerAdapter) AddGenerator(generator *core.Generator) (*c
 	return c.controller.AddGenerator(generator)
 }
 
-func (c *generatorControllerAdapter) PackGenerator(generatorID string, colonyName string, arg string) error {
-	return c.controller.PackGenerator(generatorID, colonyName, arg)
+func (c *generatorControllerAdapter) PackGenerator(generatorID string, colonyName string, key string, arg string) error {
+	return c.controller.PackGenerator(generatorID, colonyName, key, arg)
 }
 
 func (c *generatorControllerAdapter) RemoveGenerator(generatorID string) error {
@@ -174,6 +209,7 @@ type processControllerAdapter struct {
 		Assign(executorID string, colonyName string, cpu int64, memory int64) (*controllers.AssignResult, error)
 		DistributedAssign(executor *core.Executor, colonyName string, cpu int64, memory int64, storage int64) (*controllers.AssignResult, error)
 		UnassignExecutor(processID string) error
+		ReleaseProcess(processID string) error
 		PauseColonyAssignments(colonyName string) error
 		ResumeColonyAssignments(colonyName string) error
 		AreColonyAssignmentsPaused(colonyName string) (bool, error)
@@ -233,6 +269,10 @@ func (c *processControllerAdapter) UnassignExecutor(processID string) error {
 	return c.controller.UnassignExecutor(processID)
 }
 
+func (c *processControllerAdapter) ReleaseProcess(processID string) error {
+	return c.controller.ReleaseProcess(processID)
+}
+
 func (c *processControllerAdapter) PauseColonyAssignments(colonyName string) error {
 	return c.controller.PauseColonyAssignments(colonyName)
 }
@@ -339,6 +379,9 @@ type cronControllerAdapter struct {
 		AddCron(cron *core.Cron) (*core.Cron, error)
 		RunCron(cronID string) (*core.Cron, error)
 		RemoveCron(cronID string) error
+		SuspendCron(cronID string) (*core.Cron, error)
+		ResumeCron(cronID string) (*core.Cron, error)
+		GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 		GetCronPeriod() int
 	}
 }
@@ -355,6 +398,18 @@ func (c *cronControllerAdapter) RemoveCron(cronID string) error {
 	return c.controller.RemoveCron(cronID)
 }
 
+func (c *cronControllerAdapter) SuspendCron(cronID string) (*core.Cron, error) {
+	return c.controller.SuspendCron(cronID)
+}
+
+func (c *cronControllerAdapter) ResumeCron(cronID string) (*core.Cron, error) {
+	return c.controller.ResumeCron(cronID)
+}
+
+func (c *cronControllerAdapter) GetCronRuns(cron *core.Cron) ([]*core.CronRun, error) {
+	return c.controller.GetCronRuns(cron)
+}
+
 func (c *cronControllerAdapter) GetCronPeriod() int {
 	return c.controller.GetCronPeriod()
 }
@@ -364,6 +419,9 @@ func (s *ServerAdapter) CronController() interface {
 	AddCron(cron *core.Cron) (*core.Cron, error)
 	RunCron(cronID string) (*core.Cron, error)
 	RemoveCron(cronID string) error
+	SuspendCron(cronID string) (*core.Cron, error)
+	ResumeCron(cronID string) (*core.Cron, error)
+	GetCronRuns(cron *core.Cron) ([]*core.CronRun, error)
 	GetCronPeriod() int
 } {
 	return &cronControllerAdapter{controller: s.server.controller}
@@ -467,6 +525,10 @@ func (s *processgraphServerAdapter) Controller() processgraph.Controller {
 	return s.adapter.ProcessgraphController()
 }
 
+func (s *processgraphServerAdapter) FunctionArgsVerifier() *serverutils.FunctionArgsVerifier {
+	return s.server.functionArgsVerifier
+}
+
 func (s *processgraphServerAdapter) ProcessGraphDB() database.ProcessGraphDatabase {
 	return s.server.processGraphDB
 }
@@ -563,4 +625,14 @@ func (s *ServerAdapter) ServerServer() serverhandlers.Server {
 // ChannelRouter returns the channel router for channel operations
 func (s *ServerAdapter) ChannelRouter() *channel.Router {
 	return s.server.channelRouter
-}
\ No newline at end of file
+}
+
+// LogBroker returns the broker delivering log entries to realtime subscribers
+func (s *ServerAdapter) LogBroker() *logstream.Broker {
+	return s.server.logBroker
+}
+
+// LogSinkPipeline returns the pipeline forwarding log entries to external log sinks
+func (s *ServerAdapter) LogSinkPipeline() *logsink.Pipeline {
+	return s.server.logSinkPipeline
+}
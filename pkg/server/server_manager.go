@@ -17,7 +17,8 @@ import (
 type BackendType string
 
 const (
-	GinBackendType BackendType = "gin"
+	GinBackendType  BackendType = "gin"
+	GRPCBackendType BackendType = "grpc"
 )
 
 // ManagedServer represents a server instance managed by ServerManager
@@ -185,9 +186,21 @@ func (sm *ServerManager) StartAll() error {
 		StaleExecutorDuration: 600, // Default 10 minutes
 	}
 	
-	// Create and start servers for each enabled backend
+	// Create and start servers for each enabled backend, the gin backend is created first
+	// since it owns the base server whose handler registry is shared with other backends
+	backendTypes := make([]BackendType, 0, len(sm.configs))
+	if _, exists := sm.configs[GinBackendType]; exists {
+		backendTypes = append(backendTypes, GinBackendType)
+	}
+	for backendType := range sm.configs {
+		if backendType != GinBackendType {
+			backendTypes = append(backendTypes, backendType)
+		}
+	}
+
 	var errors []error
-	for backendType, config := range sm.configs {
+	for _, backendType := range backendTypes {
+		config := sm.configs[backendType]
 		if !config.Enabled {
 			continue
 		}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+)
+
+// RecordProcessInputs records the file revisions mounted by a process as lineage inputs, i.e. the files
+// of its snapshot mounts and the latest revisions of its sync dir labels. It is called when the process
+// is assigned, since executors mount the filesystem before the process starts. The files are fetched
+// with one query per mount and the lineage is added in a single batch, inputs already recorded when a
+// process is reassigned are ignored by the database.
+func RecordProcessInputs(process *core.Process, fileDB database.FileDatabase, snapshotDB database.SnapshotDatabase, lineageDB database.FileLineageDatabase) error {
+	colonyName := process.FunctionSpec.Conditions.ColonyName
+
+	var lineage []*core.FileLineage
+	recorded := make(map[string]bool)
+	for _, mount := range process.FunctionSpec.Filesystem.SnapshotMounts {
+		if mount.SnapshotID == "" {
+			continue
+		}
+
+		snapshot, err := snapshotDB.GetSnapshotByID(colonyName, mount.SnapshotID)
+		if err != nil {
+			return err
+		}
+
+		files, err := fileDB.GetFilesByIDs(colonyName, snapshot.FileIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if recorded[file.ID] {
+				continue
+			}
+			recorded[file.ID] = true
+			lineage = append(lineage, core.CreateFileLineage(process, core.LineageInput, file, snapshot.ID))
+		}
+	}
+
+	for _, mount := range process.FunctionSpec.Filesystem.SyncDirMounts {
+		files, err := fileDB.GetLatestFilesByLabel(colonyName, mount.Label)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if recorded[file.ID] {
+				continue
+			}
+			recorded[file.ID] = true
+			lineage = append(lineage, core.CreateFileLineage(process, core.LineageInput, file, ""))
+		}
+	}
+
+	return lineageDB.AddFileLineages(lineage)
+}
+
+// RecordProcessOutput records a file revision added by the executor assigned to a process as a lineage output
+// of the process. It is called when the file is added, so revisions added to the same labels by other processes
+// or users are never recorded.
+func RecordProcessOutput(process *core.Process, file *core.File, lineageDB database.FileLineageDatabase) error {
+	return lineageDB.AddFileLineages([]*core.FileLineage{core.CreateFileLineage(process, core.LineageOutput, file, "")})
+}
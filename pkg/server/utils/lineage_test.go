@@ -0,0 +1,112 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+type fileDBMock struct {
+	database.FileDatabase
+	files []*core.File
+}
+
+func (db *fileDBMock) GetFilesByIDs(colonyName string, fileIDs []string) ([]*core.File, error) {
+	var files []*core.File
+	for _, fileID := range fileIDs {
+		for _, file := range db.files {
+			if file.ID == fileID {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+func (db *fileDBMock) GetLatestFilesByLabel(colonyName string, label string) ([]*core.File, error) {
+	var files []*core.File
+	latest := make(map[string]int)
+	for _, file := range db.files {
+		if file.Label != label {
+			continue
+		}
+		if i, ok := latest[file.Name]; !ok {
+			latest[file.Name] = len(files)
+			files = append(files, file)
+		} else if files[i].SequenceNumber < file.SequenceNumber {
+			files[i] = file
+		}
+	}
+	return files, nil
+}
+
+type snapshotDBMock struct {
+	database.SnapshotDatabase
+	snapshot *core.Snapshot
+}
+
+func (db *snapshotDBMock) GetSnapshotByID(colonyName string, snapshotID string) (*core.Snapshot, error) {
+	return db.snapshot, nil
+}
+
+type lineageDBMock struct {
+	database.FileLineageDatabase
+	lineage []*core.FileLineage
+}
+
+func (db *lineageDBMock) AddFileLineages(lineages []*core.FileLineage) error {
+	db.lineage = append(db.lineage, lineages...)
+	return nil
+}
+
+func TestRecordProcessLineage(t *testing.T) {
+	colonyName := core.GenerateRandomID()
+	startTime := time.Now()
+
+	snapshotFile := &core.File{ID: "snapshot_file", Label: "/dataset", Name: "data.csv", SequenceNumber: 1, Added: startTime.Add(-time.Hour)}
+	oldResult := &core.File{ID: "old_result", Label: "/results", Name: "old.txt", SequenceNumber: 2, Added: startTime.Add(-time.Minute)}
+	fileDB := &fileDBMock{files: []*core.File{snapshotFile, oldResult}}
+	snapshotDB := &snapshotDBMock{snapshot: &core.Snapshot{ID: "snapshot_id", ColonyName: colonyName, Label: "/dataset", FileIDs: []string{snapshotFile.ID}}}
+	lineageDB := &lineageDBMock{}
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = colonyName
+	funcSpec.Filesystem.SnapshotMounts = []core.SnapshotMount{{SnapshotID: "snapshot_id", Label: "/dataset", Dir: "/dataset"}}
+	funcSpec.Filesystem.SyncDirMounts = []core.SyncDirMount{{Label: "/results", Dir: "/results"}}
+	process := core.CreateProcess(funcSpec)
+	process.ProcessGraphID = core.GenerateRandomID()
+	process.StartTime = startTime
+
+	err := RecordProcessInputs(process, fileDB, snapshotDB, lineageDB)
+	assert.Nil(t, err)
+	assert.Len(t, lineageDB.lineage, 2)
+	assert.Equal(t, core.LineageInput, lineageDB.lineage[0].Direction)
+	assert.Equal(t, "snapshot_file", lineageDB.lineage[0].FileID)
+	assert.Equal(t, "snapshot_id", lineageDB.lineage[0].SnapshotID)
+	assert.Equal(t, process.ProcessGraphID, lineageDB.lineage[0].ProcessGraphID)
+	assert.Equal(t, "old_result", lineageDB.lineage[1].FileID)
+
+	// The process syncs back a new revision of old.txt
+	oldResultRev2 := &core.File{ID: "old_result_rev2", Label: "/results", Name: "old.txt", SequenceNumber: 3, Added: startTime.Add(time.Second)}
+	err = RecordProcessOutput(process, oldResultRev2, lineageDB)
+	assert.Nil(t, err)
+	assert.Len(t, lineageDB.lineage, 3)
+	assert.Equal(t, core.LineageOutput, lineageDB.lineage[2].Direction)
+	assert.Equal(t, "old_result_rev2", lineageDB.lineage[2].FileID)
+	assert.Equal(t, process.ID, lineageDB.lineage[2].ProcessID)
+	assert.Equal(t, process.ProcessGraphID, lineageDB.lineage[2].ProcessGraphID)
+}
+
+func TestRecordProcessLineageNoMounts(t *testing.T) {
+	lineageDB := &lineageDBMock{}
+	funcSpec := core.CreateEmptyFunctionSpec()
+	process := core.CreateProcess(funcSpec)
+
+	err := RecordProcessInputs(process, &fileDBMock{}, &snapshotDBMock{}, lineageDB)
+	assert.Nil(t, err)
+
+	assert.Len(t, lineageDB.lineage, 0)
+}
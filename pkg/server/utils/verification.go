@@ -3,9 +3,12 @@ package server
 import (
 	"errors"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/constants"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
 )
 
 func VerifyFunctionSpec(funcSpec *core.FunctionSpec) error {
@@ -36,3 +39,191 @@ func VerifyWorkflowSpec(workflowSpec *core.WorkflowSpec) error {
 
 	return nil
 }
+
+func verifyFunctionArgs(funcSpec *core.FunctionSpec, colony *core.Colony, functions []*core.Function) error {
+	if funcSpec.FuncName == "" {
+		return nil
+	}
+
+	required := colony != nil && colony.RequireFuncReg
+	var candidates []*core.Function
+	for _, function := range functions {
+		if !matchesConditions(function, funcSpec) {
+			continue
+		}
+		if function.RequireFuncReg {
+			required = true
+		}
+		if len(function.Args) > 0 {
+			candidates = append(candidates, function)
+		}
+	}
+
+	if !required || len(candidates) == 0 {
+		return nil
+	}
+
+	var firstErr error
+	for _, function := range candidates {
+		err := core.ValidateFunctionArgs(funcSpec, function.Args)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return errors.New("Failed to submit function spec, " + firstErr.Error())
+}
+
+func hasArgSchemas(functions []*core.Function) bool {
+	for _, function := range functions {
+		if len(function.Args) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+type schemaCacheEntry struct {
+	hasSchemas bool
+	expires    time.Time
+}
+
+// FunctionArgsVerifier validates function args against the functions registered by executors. It remembers
+// which colonies have no functions registered with an arg schema, so that submissions to them do not query
+// the database. Cached
+// entries are invalidated when a function is added on this server, and expire after
+// FUNCTION_SCHEMA_CACHE_TTL to pick up functions added on other servers.
+type FunctionArgsVerifier struct {
+	colonyDB   database.ColonyDatabase
+	functionDB database.FunctionDatabase
+	ttl        time.Duration
+	cache      map[string]schemaCacheEntry
+	mutex      sync.Mutex
+}
+
+func CreateFunctionArgsVerifier(colonyDB database.ColonyDatabase, functionDB database.FunctionDatabase) *FunctionArgsVerifier {
+	return &FunctionArgsVerifier{
+		colonyDB:   colonyDB,
+		functionDB: functionDB,
+		ttl:        time.Duration(constants.FUNCTION_SCHEMA_CACHE_TTL) * time.Second,
+		cache:      make(map[string]schemaCacheEntry),
+	}
+}
+
+// Invalidate removes the cached entry of a colony, it must be called when functions are added
+func (verifier *FunctionArgsVerifier) Invalidate(colonyName string) {
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+
+	delete(verifier.cache, colonyName)
+}
+
+func (verifier *FunctionArgsVerifier) mayHaveSchemas(colonyName string) bool {
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+
+	entry, ok := verifier.cache[colonyName]
+	if !ok || time.Now().After(entry.expires) {
+		return true
+	}
+
+	return entry.hasSchemas
+}
+
+func (verifier *FunctionArgsVerifier) setHasSchemas(colonyName string, hasSchemas bool) {
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+
+	verifier.cache[colonyName] = schemaCacheEntry{hasSchemas: hasSchemas, expires: time.Now().Add(verifier.ttl)}
+}
+
+// load returns the colony and its functions, or nil if the colony has no functions with arg schemas
+func (verifier *FunctionArgsVerifier) load(colonyName string) (*core.Colony, []*core.Function, error) {
+	if !verifier.mayHaveSchemas(colonyName) {
+		return nil, nil, nil
+	}
+
+	functions, err := verifier.functionDB.GetFunctionsByColonyName(colonyName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasSchemas := hasArgSchemas(functions)
+	verifier.setHasSchemas(colonyName, hasSchemas)
+	if !hasSchemas {
+		return nil, nil, nil
+	}
+
+	colony, err := verifier.colonyDB.GetColonyByName(colonyName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return colony, functions, nil
+}
+
+// VerifyArgs validates the args of a function spec against the functions registered by executors matching
+// its conditions. Validation is only done if the colony or a matching function has RequireFuncReg set, and
+// only functions registered with an arg schema are considered. If executors have registered different
+// schemas, the args must be valid according to at least one of them.
+func (verifier *FunctionArgsVerifier) VerifyArgs(funcSpec *core.FunctionSpec) error {
+	if funcSpec.FuncName == "" {
+		return nil
+	}
+
+	colony, functions, err := verifier.load(funcSpec.Conditions.ColonyName)
+	if err != nil || functions == nil {
+		return err
+	}
+
+	return verifyFunctionArgs(funcSpec, colony, functions)
+}
+
+// VerifyWorkflowArgs validates the args of all function specs in a workflow. If rootArgs is not empty, it
+// replaces the args and kwargs of the root function specs, as done for generator workflows.
+func (verifier *FunctionArgsVerifier) VerifyWorkflowArgs(workflowSpec *core.WorkflowSpec, rootArgs []interface{}) error {
+	colony, functions, err := verifier.load(workflowSpec.ColonyName)
+	if err != nil || functions == nil {
+		return err
+	}
+
+	for i := range workflowSpec.FunctionSpecs {
+		funcSpec := workflowSpec.FunctionSpecs[i]
+		funcSpec.Conditions.ColonyName = workflowSpec.ColonyName
+		if len(rootArgs) > 0 && len(funcSpec.Conditions.Dependencies) == 0 {
+			funcSpec.Args = rootArgs
+			funcSpec.KwArgs = make(map[string]interface{})
+		}
+
+		if err := verifyFunctionArgs(&funcSpec, colony, functions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func matchesConditions(function *core.Function, funcSpec *core.FunctionSpec) bool {
+	if function.FuncName != funcSpec.FuncName {
+		return false
+	}
+
+	if funcSpec.Conditions.ExecutorType != "" && function.ExecutorType != funcSpec.Conditions.ExecutorType {
+		return false
+	}
+
+	if len(funcSpec.Conditions.ExecutorNames) > 0 {
+		for _, executorName := range funcSpec.Conditions.ExecutorNames {
+			if executorName == function.ExecutorName {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
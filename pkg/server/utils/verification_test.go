@@ -2,9 +2,11 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/colonyos/colonies/pkg/constants"
 	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/database"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -104,3 +106,148 @@ func TestVerifyWorkflowSpec(t *testing.T) {
 	err = VerifyWorkflowSpec(workflowSpec) // Should not work
 	assert.Nil(t, err)
 }
+
+type colonyDBMock struct {
+	database.ColonyDatabase
+	colony  *core.Colony
+	queries int
+}
+
+func (db *colonyDBMock) GetColonyByName(name string) (*core.Colony, error) {
+	db.queries++
+	return db.colony, nil
+}
+
+type functionDBMock struct {
+	database.FunctionDatabase
+	functions []*core.Function
+	queries   int
+}
+
+func (db *functionDBMock) GetFunctionsByColonyName(colonyName string) ([]*core.Function, error) {
+	db.queries++
+	return db.functions, nil
+}
+
+func TestFunctionArgsVerifierVerifyArgs(t *testing.T) {
+	colonyDB := &colonyDBMock{colony: core.CreateColony("test_colony_id", "test_colony")}
+	function1 := core.CreateFunctionWithDesc("executor1", "type1", "test_colony", "test_func", "", []*core.FunctionArg{
+		core.CreateFunctionArg("count", core.FunctionArgInteger, "", true, nil),
+	})
+	function2 := core.CreateFunctionWithDesc("executor2", "type2", "test_colony", "test_func", "", []*core.FunctionArg{
+		core.CreateFunctionArg("name", core.FunctionArgString, "", true, nil),
+	})
+	functionDB := &functionDBMock{functions: []*core.Function{function1, function2}}
+	verifier := CreateFunctionArgsVerifier(colonyDB, functionDB)
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.FuncName = "test_func"
+	funcSpec.Conditions.ColonyName = "test_colony"
+	funcSpec.KwArgs["count"] = float64(1)
+
+	// Not validated unless RequireFuncReg is set
+	funcSpec.KwArgs["unknown"] = "value"
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+
+	colonyDB.colony.RequireFuncReg = true
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+	delete(funcSpec.KwArgs, "unknown")
+
+	// Valid according to the schema registered by executor1
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+
+	// Only executor2 matches the conditions
+	funcSpec.Conditions.ExecutorType = "type2"
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+
+	funcSpec.Conditions.ExecutorType = ""
+	funcSpec.Conditions.ExecutorNames = []string{"executor2"}
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+
+	funcSpec.Conditions.ExecutorNames = []string{"executor1"}
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+
+	// Function level RequireFuncReg
+	colonyDB.colony.RequireFuncReg = false
+	function1.RequireFuncReg = true
+	funcSpec.KwArgs["count"] = "one"
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+
+	// Functions not registered are not validated
+	funcSpec.FuncName = "other_func"
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+}
+
+func TestFunctionArgsVerifierCache(t *testing.T) {
+	colony := core.CreateColony("test_colony_id", "test_colony")
+	colony.RequireFuncReg = true
+	colonyDB := &colonyDBMock{colony: colony}
+	functionDB := &functionDBMock{functions: []*core.Function{core.CreateFunctionWithDesc("executor1", "type1", "test_colony", "test_func", "", nil)}}
+	verifier := CreateFunctionArgsVerifier(colonyDB, functionDB)
+
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.FuncName = "test_func"
+	funcSpec.Conditions.ColonyName = "test_colony"
+	funcSpec.KwArgs["count"] = "one"
+
+	// Without arg schemas the colony is remembered and not queried again
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+	assert.Equal(t, 1, functionDB.queries)
+	assert.Equal(t, 0, colonyDB.queries)
+
+	functionDB.functions = append(functionDB.functions, core.CreateFunctionWithDesc("executor2", "type1", "test_colony", "test_func", "", []*core.FunctionArg{
+		core.CreateFunctionArg("count", core.FunctionArgInteger, "", true, nil),
+	}))
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+
+	verifier.Invalidate("test_colony")
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+	assert.Equal(t, 3, functionDB.queries)
+	assert.Equal(t, 2, colonyDB.queries)
+
+	// Cached entries expire
+	verifier = CreateFunctionArgsVerifier(colonyDB, functionDB)
+	verifier.ttl = time.Millisecond
+	functions := functionDB.functions
+	functionDB.functions = nil
+	assert.Nil(t, verifier.VerifyArgs(funcSpec))
+	functionDB.functions = functions
+	time.Sleep(5 * time.Millisecond)
+	assert.NotNil(t, verifier.VerifyArgs(funcSpec))
+}
+
+func TestFunctionArgsVerifierWorkflow(t *testing.T) {
+	colony := core.CreateColony("test_colony_id", "test_colony")
+	colony.RequireFuncReg = true
+	colonyDB := &colonyDBMock{colony: colony}
+	functionDB := &functionDBMock{functions: []*core.Function{core.CreateFunctionWithDesc("executor1", "type1", "test_colony", "test_func", "", []*core.FunctionArg{
+		core.CreateFunctionArg("count", core.FunctionArgInteger, "", true, nil),
+	})}}
+	verifier := CreateFunctionArgsVerifier(colonyDB, functionDB)
+
+	funcSpec1 := core.CreateEmptyFunctionSpec()
+	funcSpec1.NodeName = "task1"
+	funcSpec1.FuncName = "test_func"
+	funcSpec1.Args = []interface{}{float64(1)}
+	funcSpec2 := core.CreateEmptyFunctionSpec()
+	funcSpec2.NodeName = "task2"
+	funcSpec2.FuncName = "test_func"
+	funcSpec2.Args = []interface{}{float64(2)}
+	funcSpec2.AddDependency("task1")
+	workflowSpec := core.CreateWorkflowSpec("test_colony")
+	workflowSpec.AddFunctionSpec(funcSpec1)
+	workflowSpec.AddFunctionSpec(funcSpec2)
+
+	assert.Nil(t, verifier.VerifyWorkflowArgs(workflowSpec, nil))
+	assert.Equal(t, 1, functionDB.queries)
+	assert.Equal(t, 1, colonyDB.queries)
+
+	// Generator args replace the args of the root function specs
+	assert.NotNil(t, verifier.VerifyWorkflowArgs(workflowSpec, []interface{}{"one"}))
+	assert.Nil(t, verifier.VerifyWorkflowArgs(workflowSpec, []interface{}{float64(3)}))
+
+	workflowSpec.FunctionSpecs[1].Args = []interface{}{"two"}
+	assert.NotNil(t, verifier.VerifyWorkflowArgs(workflowSpec, nil))
+}
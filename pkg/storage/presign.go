@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Presigner creates short-lived URLs that can be used to transfer objects in a storage backend without credentials
+type Presigner interface {
+	Presign(backend *core.StorageBackend, method string, object string, expires time.Duration) (string, error)
+}
+
+type S3Presigner struct{}
+
+func CreateS3Presigner() *S3Presigner {
+	return &S3Presigner{}
+}
+
+// Presign creates a presigned URL for an object. The URL is signed locally, no request is sent to the backend.
+func (presigner *S3Presigner) Presign(backend *core.StorageBackend, method string, object string, expires time.Duration) (string, error) {
+	if backend == nil {
+		return "", errors.New("Storage backend is nil")
+	}
+
+	if backend.Protocol != "" && backend.Protocol != "s3" {
+		return "", errors.New("Storage backend protocol <" + backend.Protocol + "> does not support presigned URLs")
+	}
+
+	if method != core.PresignGet && method != core.PresignPut && method != core.PresignDelete {
+		return "", errors.New("Invalid presign method <" + method + ">")
+	}
+
+	if object == "" {
+		return "", errors.New("Object is empty")
+	}
+
+	mc, err := createMinioClient(backend)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := mc.Presign(context.Background(), method, backend.Bucket, object, expires, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+func createMinioClient(backend *core.StorageBackend) (*minio.Client, error) {
+	// Without a region the client would ask the backend for the bucket location
+	region := backend.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: backend.SkipVerify}
+
+	return minio.New(backend.Server, &minio.Options{
+		Creds:     credentials.NewStaticV4(backend.AccessKey, backend.SecretKey, ""),
+		Secure:    backend.TLS,
+		Region:    region,
+		Transport: transport,
+	})
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Presign(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test-bucket", "test_accesskey", "test_secretkey")
+	presigner := CreateS3Presigner()
+
+	for _, method := range []string{core.PresignGet, core.PresignPut, core.PresignDelete} {
+		presignedURL, err := presigner.Presign(backend, method, "test_object", 15*time.Minute)
+		assert.Nil(t, err)
+
+		u, err := url.Parse(presignedURL)
+		assert.Nil(t, err)
+		assert.Equal(t, "http", u.Scheme)
+		assert.Equal(t, "localhost:9000", u.Host)
+		assert.Equal(t, "/test-bucket/test_object", u.Path)
+		assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+		assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+		assert.NotContains(t, presignedURL, "test_secretkey")
+	}
+
+	backend.TLS = true
+	presignedURL, err := presigner.Presign(backend, core.PresignGet, "test_object", time.Minute)
+	assert.Nil(t, err)
+	u, err := url.Parse(presignedURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "https", u.Scheme)
+}
+
+func TestS3PresignInvalid(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test-bucket", "test_accesskey", "test_secretkey")
+	presigner := CreateS3Presigner()
+
+	_, err := presigner.Presign(nil, core.PresignGet, "test_object", time.Minute)
+	assert.NotNil(t, err)
+
+	_, err = presigner.Presign(backend, "POST", "test_object", time.Minute)
+	assert.NotNil(t, err)
+
+	_, err = presigner.Presign(backend, core.PresignGet, "", time.Minute)
+	assert.NotNil(t, err)
+
+	backend.Protocol = "ftp"
+	_, err = presigner.Presign(backend, core.PresignGet, "test_object", time.Minute)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/minio/minio-go/v7"
+)
+
+// ObjectRemover removes objects from a storage backend, it is used by the server to garbage collect objects
+// it has issued
+type ObjectRemover interface {
+	Remove(backend *core.StorageBackend, object string) error
+}
+
+type S3ObjectRemover struct{}
+
+func CreateS3ObjectRemover() *S3ObjectRemover {
+	return &S3ObjectRemover{}
+}
+
+// Remove removes an object, removing an object that does not exist is not an error
+func (remover *S3ObjectRemover) Remove(backend *core.StorageBackend, object string) error {
+	if backend == nil {
+		return errors.New("Storage backend is nil")
+	}
+
+	if backend.Protocol != "" && backend.Protocol != "s3" {
+		return errors.New("Storage backend protocol <" + backend.Protocol + "> does not support removing objects")
+	}
+
+	if object == "" {
+		return errors.New("Object is empty")
+	}
+
+	mc, err := createMinioClient(backend)
+	if err != nil {
+		return err
+	}
+
+	return mc.RemoveObject(context.Background(), backend.Bucket, object, minio.RemoveObjectOptions{})
+}
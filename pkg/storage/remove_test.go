@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Remove(t *testing.T) {
+	var method string
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	backend := core.CreateStorageBackend("test_colony", "test_backend", strings.TrimPrefix(server.URL, "http://"), "test-bucket", "test_accesskey", "test_secretkey")
+	remover := CreateS3ObjectRemover()
+
+	err := remover.Remove(backend, "colonies/test_colony/test_object")
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodDelete, method)
+	assert.Equal(t, "/test-bucket/colonies/test_colony/test_object", path)
+}
+
+func TestS3RemoveInvalid(t *testing.T) {
+	backend := core.CreateStorageBackend("test_colony", "test_backend", "localhost:9000", "test-bucket", "test_accesskey", "test_secretkey")
+	remover := CreateS3ObjectRemover()
+
+	err := remover.Remove(nil, "test_object")
+	assert.NotNil(t, err)
+
+	err = remover.Remove(backend, "")
+	assert.NotNil(t, err)
+
+	backend.Protocol = "ftp"
+	err = remover.Remove(backend, "test_object")
+	assert.NotNil(t, err)
+}
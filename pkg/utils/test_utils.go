@@ -267,3 +267,17 @@ func CreateTestLocation(colonyName string, name string) *core.Location {
 	locationID := core.GenerateRandomID()
 	return core.CreateLocation(locationID, name, colonyName, "test_description", 12.34, 56.78)
 }
+
+func CreateTestWebhook(colonyName string, name string) *core.Webhook {
+	return core.CreateWebhook(colonyName, name, "https://203.0.113.10/"+name, "test_secret")
+}
+
+func CreateTestStorageBackend(colonyName string, name string) *core.StorageBackend {
+	return core.CreateStorageBackend(colonyName, name, "localhost:9000", "test_bucket", "test_accesskey", "test_secretkey")
+}
+
+func CreateTestLogSink(colonyName string, name string) *core.LogSink {
+	sink := core.CreateLogSink(colonyName, name, core.LogSinkTypeHTTP, "http://203.0.113.10:8080/"+name)
+	sink.Headers["Authorization"] = "Bearer test_token"
+	return sink
+}
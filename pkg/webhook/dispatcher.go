@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/constants"
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookStore is the subset of the database needed to resolve webhooks and record deliveries
+type WebhookStore interface {
+	GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error)
+	AddWebhookDelivery(delivery *core.WebhookDelivery) error
+}
+
+type job struct {
+	webhook *core.Webhook
+	event   *core.WebhookEvent
+	payload []byte
+}
+
+// Dispatcher delivers webhook events asynchronously. Dispatch never blocks the caller,
+// events are dropped if the queue is full. Failed deliveries are retried with exponential
+// backoff and the final outcome of every delivery is recorded in the WebhookStore.
+type Dispatcher struct {
+	store          WebhookStore
+	httpClient     *http.Client
+	events         chan *core.WebhookEvent
+	jobs           chan *job
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	stop           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+}
+
+func CreateDispatcher(store WebhookStore, workers int) *Dispatcher {
+	return CreateDispatcherWithBackoff(store,
+		workers,
+		time.Duration(constants.WEBHOOK_INITIAL_BACKOFF)*time.Millisecond,
+		time.Duration(constants.WEBHOOK_MAX_BACKOFF)*time.Millisecond)
+}
+
+func CreateDispatcherWithBackoff(store WebhookStore, workers int, initialBackoff time.Duration, maxBackoff time.Duration) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+
+	dispatcher := &Dispatcher{
+		store:          store,
+		httpClient:     egress.LoadPolicy().HTTPClient(time.Duration(constants.WEBHOOK_TIMEOUT) * time.Second),
+		events:         make(chan *core.WebhookEvent, constants.WEBHOOK_QUEUE_SIZE),
+		jobs:           make(chan *job, constants.WEBHOOK_QUEUE_SIZE),
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		stop:           make(chan struct{}),
+	}
+
+	dispatcher.wg.Add(1)
+	go dispatcher.eventWorker()
+
+	for i := 0; i < workers; i++ {
+		dispatcher.wg.Add(1)
+		go dispatcher.deliveryWorker()
+	}
+
+	return dispatcher
+}
+
+// Dispatch queues an event for delivery to all matching webhooks
+func (dispatcher *Dispatcher) Dispatch(event *core.WebhookEvent) {
+	if event == nil {
+		return
+	}
+
+	select {
+	case <-dispatcher.stop:
+		return
+	default:
+	}
+
+	select {
+	case dispatcher.events <- event:
+	default:
+		log.WithFields(log.Fields{"EventID": event.ID, "EventType": event.Type, "ColonyName": event.ColonyName}).Warn("Webhook queue is full, dropping event")
+	}
+}
+
+// Stop stops all workers, pending retries are aborted
+func (dispatcher *Dispatcher) Stop() {
+	dispatcher.stopOnce.Do(func() {
+		close(dispatcher.stop)
+	})
+	dispatcher.wg.Wait()
+}
+
+func (dispatcher *Dispatcher) eventWorker() {
+	defer dispatcher.wg.Done()
+
+	for {
+		select {
+		case <-dispatcher.stop:
+			return
+		case event := <-dispatcher.events:
+			dispatcher.resolve(event)
+		}
+	}
+}
+
+func (dispatcher *Dispatcher) resolve(event *core.WebhookEvent) {
+	webhooks, err := dispatcher.store.GetWebhooksByColonyName(event.ColonyName)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "ColonyName": event.ColonyName}).Error("Failed to get webhooks")
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := event.ToJSON()
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "EventID": event.ID}).Error("Failed to serialize webhook event")
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Matches(event) {
+			continue
+		}
+
+		select {
+		case <-dispatcher.stop:
+			return
+		case dispatcher.jobs <- &job{webhook: webhook, event: event, payload: []byte(payload)}:
+		}
+	}
+}
+
+func (dispatcher *Dispatcher) deliveryWorker() {
+	defer dispatcher.wg.Done()
+
+	for {
+		select {
+		case <-dispatcher.stop:
+			return
+		case j := <-dispatcher.jobs:
+			dispatcher.deliver(j)
+		}
+	}
+}
+
+func (dispatcher *Dispatcher) deliver(j *job) {
+	maxRetries := j.webhook.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = constants.WEBHOOK_DEFAULT_MAX_RETRIES
+	}
+
+	delivery := &core.WebhookDelivery{
+		ID:         core.GenerateRandomID(),
+		WebhookID:  j.webhook.ID,
+		ColonyName: j.webhook.ColonyName,
+		EventID:    j.event.ID,
+		EventType:  j.event.Type,
+		Added:      time.Now(),
+	}
+
+	backoff := dispatcher.initialBackoff
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-dispatcher.stop:
+				delivery.Error = "delivery aborted, server is shutting down: " + delivery.Error
+				dispatcher.record(delivery)
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > dispatcher.maxBackoff {
+				backoff = dispatcher.maxBackoff
+			}
+		}
+
+		delivery.Attempts = attempt + 1
+		delivery.LastAttempt = time.Now()
+		statusCode, err := dispatcher.post(j.webhook, j.event, delivery.ID, j.payload)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			break
+		}
+
+		delivery.Error = err.Error()
+		log.WithFields(log.Fields{"WebhookName": j.webhook.Name, "ColonyName": j.webhook.ColonyName, "Attempt": delivery.Attempts, "Error": err}).Debug("Webhook delivery failed")
+	}
+
+	dispatcher.record(delivery)
+}
+
+func (dispatcher *Dispatcher) record(delivery *core.WebhookDelivery) {
+	err := dispatcher.store.AddWebhookDelivery(delivery)
+	if err != nil {
+		log.WithFields(log.Fields{"Error": err, "WebhookID": delivery.WebhookID}).Error("Failed to record webhook delivery")
+	}
+}
+
+func (dispatcher *Dispatcher) post(webhook *core.Webhook, event *core.WebhookEvent, deliveryID string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(TimestampHeader, timestamp)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, payload))
+	}
+
+	resp, err := dispatcher.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New("webhook endpoint replied with status code " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp.StatusCode, nil
+}
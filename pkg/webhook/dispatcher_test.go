@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/colonyos/colonies/pkg/core"
+	"github.com/colonyos/colonies/pkg/security/egress"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	mutex      sync.Mutex
+	webhooks   []*core.Webhook
+	deliveries []*core.WebhookDelivery
+	recorded   chan *core.WebhookDelivery
+}
+
+func createStoreMock(webhooks ...*core.Webhook) *storeMock {
+	return &storeMock{webhooks: webhooks, recorded: make(chan *core.WebhookDelivery, 10)}
+}
+
+func (store *storeMock) GetWebhooksByColonyName(colonyName string) ([]*core.Webhook, error) {
+	var webhooks []*core.Webhook
+	for _, webhook := range store.webhooks {
+		if webhook.ColonyName == colonyName {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (store *storeMock) AddWebhookDelivery(delivery *core.WebhookDelivery) error {
+	store.mutex.Lock()
+	store.deliveries = append(store.deliveries, delivery)
+	store.mutex.Unlock()
+	store.recorded <- delivery
+
+	return nil
+}
+
+func waitForDelivery(t *testing.T, store *storeMock) *core.WebhookDelivery {
+	select {
+	case delivery := <-store.recorded:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for webhook delivery")
+	}
+
+	return nil
+}
+
+func createTestProcessEvent(colonyName string, state int) *core.WebhookEvent {
+	funcSpec := core.CreateEmptyFunctionSpec()
+	funcSpec.Conditions.ColonyName = colonyName
+	funcSpec.Conditions.ExecutorType = "test_executor_type"
+	process := core.CreateProcess(funcSpec)
+	process.State = state
+
+	return core.CreateProcessWebhookEvent(process)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	payload := []byte(`{"type":"process.failed"}`)
+	signature := Sign("secret", "1700000000", payload)
+
+	assert.True(t, Verify("secret", "1700000000", payload, signature))
+	assert.False(t, Verify("other_secret", "1700000000", payload, signature))
+	assert.False(t, Verify("secret", "1700000001", payload, signature))
+	assert.False(t, Verify("secret", "1700000000", []byte("{}"), signature))
+	assert.False(t, Verify("secret", "1700000000", payload, "invalid"))
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	var receivedHeader http.Header
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeader = r.Header.Clone()
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", server.URL, "secret")
+	store := createStoreMock(webhook)
+	dispatcher := CreateDispatcherWithBackoff(store, 2, time.Millisecond, 10*time.Millisecond)
+	defer dispatcher.Stop()
+
+	event := createTestProcessEvent("test_colony", core.FAILED)
+	dispatcher.Dispatch(event)
+
+	delivery := waitForDelivery(t, store)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, webhook.ID, delivery.WebhookID)
+	assert.Equal(t, event.ID, delivery.EventID)
+	assert.Equal(t, core.WebhookEventProcessFailed, delivery.EventType)
+
+	assert.Equal(t, core.WebhookEventProcessFailed, receivedHeader.Get(EventHeader))
+	assert.Equal(t, delivery.ID, receivedHeader.Get(DeliveryHeader))
+	assert.True(t, Verify("secret", receivedHeader.Get(TimestampHeader), receivedBody, receivedHeader.Get(SignatureHeader)))
+
+	receivedEvent, err := core.ConvertJSONToWebhookEvent(string(receivedBody))
+	assert.Nil(t, err)
+	assert.Equal(t, event.ID, receivedEvent.ID)
+	assert.Equal(t, event.Process.ID, receivedEvent.Process.ID)
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", server.URL, "")
+	store := createStoreMock(webhook)
+	dispatcher := CreateDispatcherWithBackoff(store, 1, time.Millisecond, 10*time.Millisecond)
+	defer dispatcher.Stop()
+
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.SUCCESS))
+
+	delivery := waitForDelivery(t, store)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+	assert.Empty(t, delivery.Error)
+}
+
+func TestDispatcherRetryExhausted(t *testing.T) {
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	webhook := core.CreateWebhook("test_colony", "test_webhook", server.URL, "")
+	webhook.MaxRetries = 2
+	store := createStoreMock(webhook)
+	dispatcher := CreateDispatcherWithBackoff(store, 1, time.Millisecond, 10*time.Millisecond)
+	defer dispatcher.Stop()
+
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.FAILED))
+
+	delivery := waitForDelivery(t, store)
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusBadGateway, delivery.StatusCode)
+	assert.NotEmpty(t, delivery.Error)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDispatcherFilter(t *testing.T) {
+	t.Setenv(egress.AllowEnv, "127.0.0.1/32")
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	failedWebhook := core.CreateWebhook("test_colony", "failed_webhook", server.URL, "")
+	failedWebhook.States = []int{core.FAILED}
+	otherColonyWebhook := core.CreateWebhook("other_colony", "other_webhook", server.URL, "")
+	store := createStoreMock(failedWebhook, otherColonyWebhook)
+	dispatcher := CreateDispatcherWithBackoff(store, 1, time.Millisecond, 10*time.Millisecond)
+	defer dispatcher.Stop()
+
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.SUCCESS))
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.FAILED))
+
+	delivery := waitForDelivery(t, store)
+	assert.Equal(t, failedWebhook.ID, delivery.WebhookID)
+	assert.Equal(t, core.WebhookEventProcessFailed, delivery.EventType)
+
+	select {
+	case <-store.recorded:
+		t.Fatal("Unexpected webhook delivery")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDispatcherBlockedAddress(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Loopback addresses are denied by the default egress policy
+	webhook := core.CreateWebhook("test_colony", "test_webhook", server.URL, "")
+	store := createStoreMock(webhook)
+	dispatcher := CreateDispatcherWithBackoff(store, 1, time.Millisecond, 10*time.Millisecond)
+	defer dispatcher.Stop()
+
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.FAILED))
+
+	delivery := waitForDelivery(t, store)
+	assert.False(t, delivery.Success)
+	assert.Contains(t, delivery.Error, "not allowed")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestDispatcherStop(t *testing.T) {
+	store := createStoreMock()
+	dispatcher := CreateDispatcher(store, 2)
+	dispatcher.Stop()
+	dispatcher.Stop()
+
+	// Dispatching after Stop must not block or panic
+	dispatcher.Dispatch(createTestProcessEvent("test_colony", core.FAILED))
+	dispatcher.Dispatch(nil)
+}
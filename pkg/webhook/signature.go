@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	SignatureHeader = "X-Colonies-Signature"
+	TimestampHeader = "X-Colonies-Timestamp"
+	EventHeader     = "X-Colonies-Event"
+	DeliveryHeader  = "X-Colonies-Delivery"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign calculates the value of the X-Colonies-Signature header. The signature is an
+// HMAC-SHA256 over the timestamp and the raw body, i.e. "<timestamp>.<body>", so that
+// a captured request cannot be replayed with a different timestamp.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a X-Colonies-Signature header, it is intended to be used by webhook receivers
+func Verify(secret string, timestamp string, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	expected := Sign(secret, timestamp, payload)
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}